@@ -0,0 +1,156 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/dustin/go-humanize"
+	"github.com/google/uuid"
+
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/encoding"
+	"github.com/grafana/tempo/tempodb/encoding/common"
+)
+
+type blockCopyCmd struct {
+	backendOptions
+
+	SourceConfigFile string `type:"path" help:"Path to tempo config file for the source. Defaults to the destination config file and backend options"`
+	DestTenantID     string `help:"tenant-id to copy the block into. Defaults to the source tenant-id"`
+	Force            bool   `help:"overwrite the block if it already exists in the destination"`
+
+	TenantID string `arg:"" help:"tenant-id within the source bucket"`
+	BlockID  string `arg:"" help:"block ID to copy"`
+}
+
+func (cmd *blockCopyCmd) Run(opts *globalOptions) error {
+	ctx := context.Background()
+
+	blockID, err := uuid.Parse(cmd.BlockID)
+	if err != nil {
+		return fmt.Errorf("invalid block ID %s: %w", cmd.BlockID, err)
+	}
+
+	destTenantID := cmd.DestTenantID
+	if destTenantID == "" {
+		destTenantID = cmd.TenantID
+	}
+
+	if cmd.sameBackend(opts) && destTenantID == cmd.TenantID {
+		return errors.New("source and destination are the same. set --dest-tenant-id or --source-config-file")
+	}
+
+	readerSource, compactorSource, readerDest, writerDest, err := cmd.setupBackends(opts)
+	if err != nil {
+		return fmt.Errorf("setting up backends: %w", err)
+	}
+	defer func() {
+		readerSource.Shutdown()
+		readerDest.Shutdown()
+	}()
+
+	sourceMeta, err := readerSource.BlockMeta(ctx, blockID, cmd.TenantID)
+	if errors.Is(err, backend.ErrDoesNotExist) {
+		// compacted blocks are copied as regular blocks. this restores blocks that were compacted or past retention
+		compactedMeta, compactedErr := compactorSource.CompactedBlockMeta(blockID, cmd.TenantID)
+		if compactedErr != nil {
+			return fmt.Errorf("reading source block meta: %w", compactedErr)
+		}
+		fmt.Printf("Block %s is compacted in the source. It will be copied as a regular block\n", blockID)
+		sourceMeta = &compactedMeta.BlockMeta
+	} else if err != nil {
+		return fmt.Errorf("reading source block meta: %w", err)
+	}
+
+	existing, err := readerDest.BlockMeta(ctx, blockID, destTenantID)
+	if err != nil && !errors.Is(err, backend.ErrDoesNotExist) {
+		return fmt.Errorf("reading destination block meta: %w", err)
+	}
+	if existing != nil && !cmd.Force {
+		return fmt.Errorf("block %s already exists in tenant %s of the destination. use --force to overwrite it", blockID, destTenantID)
+	}
+
+	enc, err := encoding.FromVersion(sourceMeta.Version)
+	if err != nil {
+		return fmt.Errorf("creating encoder from version: %w", err)
+	}
+
+	destMeta := *sourceMeta
+	destMeta.TenantID = destTenantID
+
+	fmt.Printf("Copying block %s (%s, %d objects, %s) from tenant %s to tenant %s\n", blockID, sourceMeta.Version, sourceMeta.TotalObjects, humanize.Bytes(sourceMeta.Size), cmd.TenantID, destTenantID)
+
+	err = enc.MigrateBlock(ctx, sourceMeta, &destMeta, readerSource, writerDest)
+	if err != nil {
+		return fmt.Errorf("copying block: %w", err)
+	}
+
+	err = validateCopiedBlock(ctx, sourceMeta, readerDest, destTenantID)
+	if err != nil {
+		return fmt.Errorf("validating copied block: %w", err)
+	}
+
+	fmt.Println("Block copied and validated. It is picked up by the next blocklist poll of the destination")
+	return nil
+}
+
+// validateCopiedBlock checks that the copied meta matches the source and that the first and last trace of the block
+// can be found in the copy. this reads the bloom filters, the index and the data of the copied block.
+func validateCopiedBlock(ctx context.Context, sourceMeta *backend.BlockMeta, r backend.Reader, tenantID string) error {
+	meta, err := r.BlockMeta(ctx, sourceMeta.BlockID, tenantID)
+	if err != nil {
+		return fmt.Errorf("reading block meta: %w", err)
+	}
+
+	if meta.Version != sourceMeta.Version || meta.Size != sourceMeta.Size || meta.TotalObjects != sourceMeta.TotalObjects {
+		return fmt.Errorf("block meta doesn't match the source. version: %s/%s, size: %d/%d, objects: %d/%d",
+			meta.Version, sourceMeta.Version, meta.Size, sourceMeta.Size, meta.TotalObjects, sourceMeta.TotalObjects)
+	}
+
+	block, err := encoding.OpenBlock(meta, r)
+	if err != nil {
+		return fmt.Errorf("opening block: %w", err)
+	}
+
+	for _, id := range []common.ID{meta.MinID, meta.MaxID} {
+		if len(id) == 0 {
+			continue
+		}
+
+		tr, err := block.FindTraceByID(ctx, id, common.DefaultSearchOptions())
+		if err != nil {
+			return fmt.Errorf("finding trace %x: %w", id, err)
+		}
+		if tr == nil {
+			return fmt.Errorf("trace %x not found", id)
+		}
+	}
+
+	return nil
+}
+
+// sameBackend returns true if the source and destination are read from the same config. the backend options
+// then apply to both
+func (cmd *blockCopyCmd) sameBackend(opts *globalOptions) bool {
+	return cmd.SourceConfigFile == "" || (cmd.SourceConfigFile == opts.ConfigFile && cmd.backendOptions == backendOptions{})
+}
+
+func (cmd *blockCopyCmd) setupBackends(optsDest *globalOptions) (readerSource backend.Reader, compactorSource backend.Compactor, readerDest backend.Reader, writerDest backend.Writer, err error) {
+	optsSource := optsDest
+	backendOptsSource := &cmd.backendOptions
+	if cmd.SourceConfigFile != "" {
+		optsSource = &globalOptions{
+			ConfigFile: cmd.SourceConfigFile,
+		}
+		backendOptsSource = &backendOptions{}
+	}
+
+	readerSource, _, compactorSource, err = loadBackend(backendOptsSource, optsSource)
+	if err != nil {
+		return
+	}
+
+	readerDest, writerDest, _, err = loadBackend(&cmd.backendOptions, optsDest)
+	return
+}
@@ -0,0 +1,243 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+
+	"github.com/dustin/go-humanize"
+	"github.com/google/uuid"
+	willf_bloom "github.com/willf/bloom"
+
+	"github.com/grafana/tempo/pkg/util"
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/encoding"
+	"github.com/grafana/tempo/tempodb/encoding/common"
+	"github.com/grafana/tempo/tempodb/encoding/vparquet2"
+	"github.com/grafana/tempo/tempodb/encoding/vparquet3"
+	"github.com/grafana/tempo/tempodb/encoding/vparquet4"
+)
+
+type blockVerifyCmd struct {
+	backendOptions
+
+	Sample int `help:"number of traces sampled from the block to be found by ID" default:"100"`
+
+	TenantID string `arg:"" help:"tenant-id within the bucket"`
+	BlockID  string `arg:"" help:"block ID to verify"`
+}
+
+func (cmd *blockVerifyCmd) Run(opts *globalOptions) error {
+	ctx := context.Background()
+
+	blockID, err := uuid.Parse(cmd.BlockID)
+	if err != nil {
+		return fmt.Errorf("invalid block ID %s: %w", cmd.BlockID, err)
+	}
+
+	rawR, _, c, err := loadRawBackend(&cmd.backendOptions, opts)
+	if err != nil {
+		return err
+	}
+	r := backend.NewReader(rawR)
+	defer r.Shutdown()
+
+	meta, err := r.BlockMeta(ctx, blockID, cmd.TenantID)
+	if errors.Is(err, backend.ErrDoesNotExist) {
+		compactedMeta, compactedErr := c.CompactedBlockMeta(blockID, cmd.TenantID)
+		if compactedErr != nil {
+			return fmt.Errorf("reading block meta: %w", compactedErr)
+		}
+		fmt.Printf("Block %s is compacted\n", blockID)
+		meta = &compactedMeta.BlockMeta
+	} else if err != nil {
+		return fmt.Errorf("reading block meta: %w", err)
+	}
+
+	fmt.Printf("Verifying block %s (%s, %d objects, %s)\n", blockID, meta.Version, meta.TotalObjects, humanize.Bytes(meta.Size))
+
+	var problems []string
+	report := func(format string, args ...interface{}) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	verifyMeta(meta, report)
+	verifyDataSize(ctx, rawR, meta, report)
+	bloom := readBloom(ctx, r, meta, report)
+	sample := verifyTraces(ctx, r, meta, bloom, cmd.Sample, report)
+	verifyFindTraceByID(ctx, r, meta, sample, report)
+
+	if len(problems) == 0 {
+		fmt.Println("block is valid")
+		return nil
+	}
+
+	fmt.Println("corruption found:")
+	for _, p := range problems {
+		fmt.Println("  " + p)
+	}
+	return fmt.Errorf("block %s failed %d checks", blockID, len(problems))
+}
+
+// verifyMeta checks that the fields of the meta are consistent
+func verifyMeta(meta *backend.BlockMeta, report func(string, ...interface{})) {
+	if meta.TotalObjects <= 0 {
+		report("meta: the block has %d objects", meta.TotalObjects)
+	}
+	if meta.StartTime.After(meta.EndTime) {
+		report("meta: the start time %s is after the end time %s", meta.StartTime, meta.EndTime)
+	}
+	if bytes.Compare(meta.MinID, meta.MaxID) > 0 {
+		report("meta: the min ID %s is greater than the max ID %s", util.TraceIDToHexString(meta.MinID), util.TraceIDToHexString(meta.MaxID))
+	}
+	if meta.BloomShardCount == 0 {
+		report("meta: the block has no bloom shards")
+	}
+}
+
+// verifyDataSize checks that the size in the meta is the size of the data object. parquet blocks are read from the
+// end of the data object using the size of the meta
+func verifyDataSize(ctx context.Context, rawR backend.RawReader, meta *backend.BlockMeta, report func(string, ...interface{})) {
+	var name string
+	switch meta.Version {
+	case vparquet2.VersionString:
+		name = vparquet2.DataFileName
+	case vparquet3.VersionString:
+		name = vparquet3.DataFileName
+	case vparquet4.VersionString:
+		name = vparquet4.DataFileName
+	default:
+		return
+	}
+
+	rc, size, err := rawR.Read(ctx, name, backend.KeyPathForBlock(meta.BlockID, meta.TenantID), nil)
+	if err != nil {
+		report("data: reading %s: %v", name, err)
+		return
+	}
+	rc.Close()
+
+	if uint64(size) != meta.Size {
+		report("data: %s is %d bytes but the meta has a size of %d bytes", name, size, meta.Size)
+	}
+}
+
+// readBloom reads all the shards of the bloom filter. it returns nil if a shard can't be read
+func readBloom(ctx context.Context, r backend.Reader, meta *backend.BlockMeta, report func(string, ...interface{})) []*willf_bloom.BloomFilter {
+	shards := make([]*willf_bloom.BloomFilter, common.ValidateShardCount(int(meta.BloomShardCount)))
+	for i := range shards {
+		b, err := r.Read(ctx, common.BloomName(i), meta.BlockID, meta.TenantID, nil)
+		if err != nil {
+			report("bloom: reading shard %d: %v", i, err)
+			return nil
+		}
+
+		shards[i] = &willf_bloom.BloomFilter{}
+		_, err = shards[i].ReadFrom(bytes.NewReader(b))
+		if err != nil {
+			report("bloom: unmarshalling shard %d: %v", i, err)
+			return nil
+		}
+	}
+	return shards
+}
+
+// verifyTraces reads every trace of the block, which checks the page checksums of parquet blocks, and checks that
+// the traces are ordered by ID within the IDs of the meta and are in the bloom filter. it returns a sample of the
+// trace IDs
+func verifyTraces(ctx context.Context, r backend.Reader, meta *backend.BlockMeta, bloom []*willf_bloom.BloomFilter, sampleSize int, report func(string, ...interface{})) []common.ID {
+	block, err := encoding.OpenBlock(meta, r)
+	if err != nil {
+		report("data: opening block: %v", err)
+		return nil
+	}
+
+	iterable, ok := block.(common.IterableBlock)
+	if !ok {
+		fmt.Printf("Reading the traces of %s blocks is not supported. Only the meta, size and bloom are verified\n", meta.Version)
+		return nil
+	}
+
+	iter, err := iterable.Iterator(ctx)
+	if err != nil {
+		report("data: creating iterator: %v", err)
+		return nil
+	}
+	defer iter.Close()
+
+	var (
+		count      int
+		prev       common.ID
+		notInBloom int
+		sample     = make([]common.ID, 0, sampleSize)
+	)
+	for {
+		id, tr, err := iter.Next(ctx)
+		if errors.Is(err, io.EOF) || (err == nil && tr == nil) {
+			break
+		}
+		if err != nil {
+			report("data: reading trace %d: %v", count, err)
+			return sample
+		}
+
+		if prev != nil && bytes.Compare(prev, id) >= 0 {
+			report("index: trace %s follows trace %s", util.TraceIDToHexString(id), util.TraceIDToHexString(prev))
+		}
+		if bytes.Compare(id, meta.MinID) < 0 || bytes.Compare(id, meta.MaxID) > 0 {
+			report("index: trace %s is outside of the IDs of the meta", util.TraceIDToHexString(id))
+		}
+		if bloom != nil && !bloom[common.ShardKeyForTraceID(id, len(bloom))].Test(id) {
+			notInBloom++
+		}
+
+		// reservoir sampling of the trace IDs
+		if len(sample) < sampleSize {
+			sample = append(sample, append(common.ID(nil), id...))
+		} else if i := rand.Intn(count + 1); i < sampleSize {
+			sample[i] = append(common.ID(nil), id...)
+		}
+
+		prev = append(prev[:0], id...)
+		count++
+	}
+
+	if count != meta.TotalObjects {
+		report("data: the block has %d traces but the meta has %d objects", count, meta.TotalObjects)
+	}
+	if notInBloom > 0 {
+		report("bloom: %d traces are not in the bloom filter", notInBloom)
+	}
+	return sample
+}
+
+// verifyFindTraceByID checks that the sampled traces are found by ID, which reads the bloom, the index and the data
+func verifyFindTraceByID(ctx context.Context, r backend.Reader, meta *backend.BlockMeta, sample []common.ID, report func(string, ...interface{})) {
+	if len(sample) == 0 {
+		return
+	}
+
+	block, err := encoding.OpenBlock(meta, r)
+	if err != nil {
+		report("data: opening block: %v", err)
+		return
+	}
+
+	missing := 0
+	for _, id := range sample {
+		tr, err := block.FindTraceByID(ctx, id, common.DefaultSearchOptions())
+		if err != nil {
+			report("data: finding trace %s: %v", util.TraceIDToHexString(id), err)
+			return
+		}
+		if tr == nil {
+			missing++
+		}
+	}
+	if missing > 0 {
+		report("index: %d of %d sampled traces were not found by ID", missing, len(sample))
+	}
+}
@@ -0,0 +1,318 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/grafana/dskit/ring"
+	"github.com/olekukonko/tablewriter"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/grafana/tempo/cmd/tempo/app"
+	"github.com/grafana/tempo/pkg/api"
+	"github.com/grafana/tempo/tempodb/backend"
+)
+
+type severity int
+
+const (
+	severityInfo severity = iota
+	severityWarning
+	severityCritical
+)
+
+func (s severity) String() string {
+	switch s {
+	case severityCritical:
+		return "critical"
+	case severityWarning:
+		return "warning"
+	}
+	return "info"
+}
+
+type finding struct {
+	severity severity
+	check    string
+	message  string
+}
+
+type doctorCmd struct {
+	backendOptions
+
+	URL                  []string      `help:"http endpoints of running tempo components, e.g. http://distributor:3200. checked for clock skew and mismatched versions"`
+	JoinMembers          []string      `help:"memberlist members to join, optional, overrides join_members in config file"`
+	BindPort             int           `help:"memberlist bind port. the default of 0 picks a random port so the cli doesn't collide with a local tempo" default:"0"`
+	SkipRings            bool          `help:"skip the checks of the rings"`
+	SkipBackend          bool          `help:"skip the checks of the backend"`
+	Timeout              time.Duration `help:"time to wait for the ingester ring to be populated and for each http endpoint" default:"30s"`
+	MaxClockSkew         time.Duration `help:"clock skew reported between the cli and the components" default:"5s"`
+	MaxUncompactedBlocks int           `help:"number of level 0 blocks of a tenant reported as a compaction backlog" default:"100"`
+}
+
+func (cmd *doctorCmd) Run(g *globalOptions) error {
+	cfg, err := loadConfig(g)
+	if err != nil {
+		return err
+	}
+
+	var findings []finding
+	report := func(s severity, check, format string, args ...interface{}) {
+		findings = append(findings, finding{severity: s, check: check, message: fmt.Sprintf(format, args...)})
+	}
+
+	if len(cmd.URL) > 0 {
+		cmd.checkEndpoints(report)
+	}
+	if !cmd.SkipRings {
+		cmd.checkRings(cfg, report)
+	}
+	if !cmd.SkipBackend {
+		cmd.checkBackend(cfg, g, report)
+	}
+
+	// most severe findings first
+	sort.SliceStable(findings, func(i, j int) bool { return findings[i].severity > findings[j].severity })
+
+	if len(findings) == 0 {
+		fmt.Println("no issues found")
+		return nil
+	}
+
+	critical := 0
+	out := make([][]string, 0, len(findings))
+	for _, f := range findings {
+		if f.severity == severityCritical {
+			critical++
+		}
+		out = append(out, []string{f.severity.String(), f.check, f.message})
+	}
+
+	w := tablewriter.NewWriter(os.Stdout)
+	w.SetHeader([]string{"severity", "check", "finding"})
+	w.SetAutoWrapText(false)
+	w.AppendBulk(out)
+	w.Render()
+
+	if critical > 0 {
+		return fmt.Errorf("found %d critical issues", critical)
+	}
+	return nil
+}
+
+// checkEndpoints compares the clocks and versions of the components with the build info endpoint
+func (cmd *doctorCmd) checkEndpoints(report func(severity, string, string, ...interface{})) {
+	client := &http.Client{Timeout: cmd.Timeout}
+	versions := map[string][]string{}
+
+	for _, url := range cmd.URL {
+		url = strings.TrimSuffix(url, "/")
+
+		start := time.Now()
+		resp, err := client.Get(url + api.PathBuildInfo)
+		if err != nil {
+			report(severityCritical, "endpoint", "%s is unreachable: %v", url, err)
+			continue
+		}
+		end := time.Now()
+
+		info := struct {
+			Version  string `json:"version"`
+			Revision string `json:"revision"`
+		}{}
+		err = json.NewDecoder(resp.Body).Decode(&info)
+		_ = resp.Body.Close()
+		if resp.StatusCode != http.StatusOK || err != nil {
+			report(severityWarning, "endpoint", "%s returned an invalid build info response: status %d, err %v", url, resp.StatusCode, err)
+			continue
+		}
+		version := fmt.Sprintf("%s (%s)", info.Version, info.Revision)
+		versions[version] = append(versions[version], url)
+
+		// the date header has a resolution of a second and is set at some point during the request so both are
+		// tolerated on top of the max skew
+		date, err := http.ParseTime(resp.Header.Get("Date"))
+		if err != nil {
+			continue
+		}
+		skew := date.Sub(start.Add(end.Sub(start) / 2))
+		if skew < 0 {
+			skew = -skew
+		}
+		if skew > cmd.MaxClockSkew+time.Second+end.Sub(start)/2 {
+			skew = skew.Truncate(time.Second)
+			report(severityWarning, "clock skew", "the clock of %s is %s off the clock of the cli", url, skew)
+		}
+	}
+
+	if len(versions) > 1 {
+		descs := make([]string, 0, len(versions))
+		for version, urls := range versions {
+			descs = append(descs, fmt.Sprintf("%s: %s", version, strings.Join(urls, ", ")))
+		}
+		sort.Strings(descs)
+		report(severityWarning, "versions", "components run different versions: %s", strings.Join(descs, "; "))
+	}
+}
+
+// checkRings checks the replication factor against the ingesters and the health and heartbeats of the ring members.
+// the other rings are read once the ingester ring is populated as they are optional.
+func (cmd *doctorCmd) checkRings(cfg *app.Config, report func(severity, string, string, ...interface{})) {
+	logger := level.NewFilter(log.NewLogfmtLogger(os.Stderr), level.AllowWarn())
+	reg := prometheus.NewRegistry()
+
+	ctx, cancel := context.WithTimeout(context.Background(), cmd.Timeout)
+	defer cancel()
+
+	ingesterCfg, ingesterKey, _ := ringConfigFor(cfg, ringIngester)
+	client, stop, err := newRingKVClient(ctx, cfg, &ingesterCfg, cmd.JoinMembers, cmd.BindPort, logger, reg)
+	if err != nil {
+		report(severityInfo, "rings", "rings not checked: %v", err)
+		return
+	}
+	defer stop()
+
+	desc, err := waitForRingDesc(ctx, client, ingesterKey)
+	if err != nil {
+		report(severityCritical, "rings", "%s ring: %v", ringIngester, err)
+		return
+	}
+
+	healthy := cmd.checkRingMembers(ringIngester, desc, ingesterCfg.HeartbeatTimeout, report)
+	rf := ingesterCfg.ReplicationFactor
+	if healthy < rf {
+		report(severityCritical, "replication factor", "replication factor is %d but only %d ingesters are active and healthy. writes fail", rf, healthy)
+	}
+	if rf > 0 && rf%2 == 0 {
+		report(severityWarning, "replication factor", "replication factor is %d. it tolerates as many unavailable ingesters as a replication factor of %d", rf, rf-1)
+	}
+
+	for _, name := range []string{ringMetricsGenerator, ringDistributor, ringCompactor} {
+		ringCfg, key, _ := ringConfigFor(cfg, name)
+
+		c := client
+		if ringCfg.KVStore.Store != ingesterCfg.KVStore.Store || ringCfg.KVStore.Store != "memberlist" {
+			var stopRing func()
+			c, stopRing, err = newRingKVClient(ctx, cfg, &ringCfg, cmd.JoinMembers, cmd.BindPort, logger, prometheus.NewRegistry())
+			if err != nil {
+				report(severityInfo, "rings", "%s ring not checked: %v", name, err)
+				continue
+			}
+			defer stopRing()
+		}
+
+		val, err := c.Get(ctx, key)
+		if err != nil {
+			report(severityWarning, "rings", "failed to read the %s ring: %v", name, err)
+			continue
+		}
+		d := ring.GetOrCreateRingDesc(val)
+		if len(d.Ingesters) == 0 {
+			report(severityInfo, "rings", "%s ring is empty", name)
+			continue
+		}
+		cmd.checkRingMembers(name, d, ringCfg.HeartbeatTimeout, report)
+	}
+}
+
+// checkRingMembers reports unhealthy members and heartbeats from the future and returns the number of members that
+// are active and healthy
+func (cmd *doctorCmd) checkRingMembers(name string, desc *ring.Desc, heartbeatTimeout time.Duration, report func(severity, string, string, ...interface{})) int {
+	now := time.Now()
+	healthy := 0
+
+	ids := make([]string, 0, len(desc.Ingesters))
+	for id := range desc.Ingesters {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		inst := desc.Ingesters[id]
+
+		heartbeat := time.Unix(inst.Timestamp, 0)
+		if skew := heartbeat.Sub(now); skew > cmd.MaxClockSkew {
+			report(severityWarning, "clock skew", "%s %s heartbeats %s in the future. its clock is off the clock of the cli", name, id, skew.Truncate(time.Second))
+		}
+
+		switch {
+		case !inst.IsHeartbeatHealthy(heartbeatTimeout, now):
+			report(severityWarning, "rings", "%s %s is unhealthy. last heartbeat %s ago", name, id, now.Sub(heartbeat).Truncate(time.Second))
+		case inst.State != ring.ACTIVE:
+			report(severityInfo, "rings", "%s %s is %s", name, id, inst.State)
+		default:
+			healthy++
+		}
+	}
+
+	return healthy
+}
+
+// checkBackend checks the tenant index and the compaction backlog of every tenant in the backend
+func (cmd *doctorCmd) checkBackend(cfg *app.Config, g *globalOptions, report func(severity, string, string, ...interface{})) {
+	if cmd.Backend == "" && cfg.StorageConfig.Trace.Backend == "" {
+		report(severityInfo, "backend", "backend not checked: no backend configured")
+		return
+	}
+
+	r, _, _, err := loadBackend(&cmd.backendOptions, g)
+	if err != nil {
+		report(severityCritical, "backend", "failed to connect to the backend: %v", err)
+		return
+	}
+
+	ctx := context.Background()
+	tenants, err := r.Tenants(ctx)
+	if err != nil {
+		report(severityCritical, "backend", "failed to list tenants: %v", err)
+		return
+	}
+	if len(tenants) == 0 {
+		report(severityInfo, "backend", "no tenants found in the backend")
+		return
+	}
+	sort.Strings(tenants)
+
+	var (
+		staleIndex = cfg.StorageConfig.Trace.BlocklistPollStaleTenantIndex
+		poll       = cfg.StorageConfig.Trace.BlocklistPoll
+	)
+	for _, tenantID := range tenants {
+		idx, err := r.TenantIndex(ctx, tenantID)
+		if errors.Is(err, backend.ErrDoesNotExist) {
+			report(severityCritical, "tenant index", "tenant %s has no tenant index. the queriers and compactors can't find its blocks unless blocklist_poll_fallback is enabled", tenantID)
+			continue
+		}
+		if err != nil {
+			report(severityWarning, "tenant index", "failed to read the tenant index of tenant %s: %v", tenantID, err)
+			continue
+		}
+
+		age := time.Since(idx.CreatedAt).Truncate(time.Second)
+		switch {
+		case staleIndex > 0 && age > staleIndex:
+			report(severityCritical, "tenant index", "tenant index of tenant %s is %s old and ignored as stale. check the tenant index builders", tenantID, age)
+		case poll > 0 && age > 3*poll:
+			report(severityWarning, "tenant index", "tenant index of tenant %s is %s old. check the tenant index builders", tenantID, age)
+		}
+
+		uncompacted := 0
+		for _, m := range idx.Meta {
+			if m.CompactionLevel == 0 {
+				uncompacted++
+			}
+		}
+		if uncompacted > cmd.MaxUncompactedBlocks {
+			report(severityWarning, "compaction", "tenant %s has %d uncompacted blocks of %d blocks. scale the compactors or check their logs", tenantID, uncompacted, len(idx.Meta))
+		}
+	}
+}
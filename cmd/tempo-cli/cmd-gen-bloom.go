@@ -15,6 +15,7 @@ import (
 	"github.com/grafana/tempo/tempodb/backend"
 	"github.com/grafana/tempo/tempodb/encoding/common"
 	v2 "github.com/grafana/tempo/tempodb/encoding/v2"
+	"github.com/grafana/tempo/tempodb/encoding/vparquet4"
 )
 
 type bloomCmd struct {
@@ -89,7 +90,7 @@ func (cmd *bloomCmd) Run(ctx *globalOptions) error {
 		return err
 	}
 
-	if meta.Version != v2.VersionString {
+	if meta.Version != v2.VersionString && meta.Version != vparquet4.VersionString {
 		return fmt.Errorf("unsupported block version: %s", meta.Version)
 	}
 
@@ -101,6 +102,17 @@ func (cmd *bloomCmd) Run(ctx *globalOptions) error {
 		return err
 	}
 
+	if meta.Version == vparquet4.VersionString {
+		// the bloom of parquet blocks is rebuilt from the data object in the backend
+		err = vparquet4.RebuildBloom(context.TODO(), meta, bloom, r, w)
+		if err != nil {
+			fmt.Println("error rebuilding bloom filter", err)
+			return err
+		}
+		fmt.Println("bloom written to backend successfully. use `tempo-cli block verify` to verify the block")
+		return nil
+	}
+
 	addToBloom := func(id common.ID) error {
 		bloom.Add(id)
 		return nil
@@ -13,6 +13,7 @@ import (
 	"github.com/grafana/tempo/tempodb/backend"
 	"github.com/grafana/tempo/tempodb/encoding/common"
 	v2 "github.com/grafana/tempo/tempodb/encoding/v2"
+	"github.com/grafana/tempo/tempodb/encoding/vparquet4"
 )
 
 type indexCmd struct {
@@ -116,7 +117,18 @@ func (cmd *indexCmd) Run(ctx *globalOptions) error {
 		return err
 	}
 
-	if meta.Version != v2.VersionString {
+	switch meta.Version {
+	case v2.VersionString:
+	case vparquet4.VersionString:
+		// the index of parquet blocks is rebuilt from the data object in the backend
+		err = vparquet4.RebuildIndex(context.TODO(), meta, r, w)
+		if err != nil {
+			fmt.Println("error rebuilding index", err)
+			return err
+		}
+		fmt.Println("index written to backend successfully. use `tempo-cli block verify` to verify the block")
+		return nil
+	default:
 		return fmt.Errorf("unsupported block version: %s", meta.Version)
 	}
 
@@ -1,13 +1,17 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"strconv"
 	"time"
 
 	"github.com/dustin/go-humanize"
+	"github.com/google/uuid"
 	"github.com/olekukonko/tablewriter"
+
+	"github.com/grafana/tempo/tempodb/backend"
 )
 
 type listBlocksCmd struct {
@@ -17,10 +21,11 @@ type listBlocksCmd struct {
 }
 
 func (l *listBlocksCmd) Run(ctx *globalOptions) error {
-	r, _, c, err := loadBackend(&l.backendOptions, ctx)
+	rawR, _, c, err := loadRawBackend(&l.backendOptions, ctx)
 	if err != nil {
 		return err
 	}
+	r := backend.NewReader(rawR)
 
 	windowDuration := time.Hour
 
@@ -29,11 +34,88 @@ func (l *listBlocksCmd) Run(ctx *globalOptions) error {
 		return err
 	}
 
+	missingMeta, err := blocksWithoutMeta(context.Background(), rawR, r, l.TenantID)
+	if err != nil {
+		return err
+	}
+
 	displayResults(results, windowDuration, l.IncludeCompacted)
+	displayAnomalies(results, missingMeta)
 
 	return nil
 }
 
+// blocksWithoutMeta returns the ids of the block folders of the tenant that have neither a meta nor a compacted meta.
+// they are ignored by tempo and are usually left by a failed write or a partial delete.
+func blocksWithoutMeta(ctx context.Context, rawR backend.RawReader, r backend.Reader, tenantID string) ([]uuid.UUID, error) {
+	blockIDs, compactedBlockIDs, err := r.Blocks(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	withMeta := make(map[uuid.UUID]struct{}, len(blockIDs)+len(compactedBlockIDs))
+	for _, id := range append(blockIDs, compactedBlockIDs...) {
+		withMeta[id] = struct{}{}
+	}
+
+	folders, err := rawR.List(ctx, backend.KeyPath{tenantID})
+	if err != nil {
+		return nil, err
+	}
+
+	var missing []uuid.UUID
+	for _, folder := range folders {
+		id, err := uuid.Parse(folder)
+		if err != nil {
+			// not a block, i.e. the tenant index
+			continue
+		}
+		if _, ok := withMeta[id]; !ok {
+			missing = append(missing, id)
+		}
+	}
+	return missing, nil
+}
+
+// overlappingBlocks returns the ids of the blocks that overlap another block of the same compaction level. level 0
+// blocks are ignored because every ingester writes blocks of the same time range.
+func overlappingBlocks(results []blockStats) map[uuid.UUID]struct{} {
+	overlapping := map[uuid.UUID]struct{}{}
+	for i, a := range results {
+		if a.compacted || a.CompactionLevel == 0 {
+			continue
+		}
+		for _, b := range results[i+1:] {
+			if b.compacted || b.CompactionLevel != a.CompactionLevel {
+				continue
+			}
+			if a.StartTime.Before(b.EndTime) && b.StartTime.Before(a.EndTime) {
+				overlapping[a.BlockID] = struct{}{}
+				overlapping[b.BlockID] = struct{}{}
+			}
+		}
+	}
+	return overlapping
+}
+
+func displayAnomalies(results []blockStats, missingMeta []uuid.UUID) {
+	overlapping := overlappingBlocks(results)
+	if len(overlapping) == 0 && len(missingMeta) == 0 {
+		fmt.Println("no anomalies found")
+		return
+	}
+
+	fmt.Println("possible anomalies:")
+	for _, r := range results {
+		if _, ok := overlapping[r.BlockID]; ok {
+			fmt.Printf("  %s: time range %s - %s overlaps another block of level %d\n", r.BlockID, r.StartTime.Format(time.RFC3339), r.EndTime.Format(time.RFC3339), r.CompactionLevel)
+		}
+	}
+	for _, id := range missingMeta {
+		fmt.Printf("  %s: missing meta, the block is ignored\n", id)
+	}
+}
+
 func displayResults(results []blockStats, windowDuration time.Duration, includeCompacted bool) {
 	columns := []string{"id", "lvl", "objects", "size", "encoding", "vers", "window", "start", "end", "duration", "age"}
 	if includeCompacted {
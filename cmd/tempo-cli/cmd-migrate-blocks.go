@@ -0,0 +1,177 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/dustin/go-humanize"
+	"github.com/google/uuid"
+
+	"github.com/grafana/tempo/tempodb"
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/encoding"
+	"github.com/grafana/tempo/tempodb/encoding/common"
+)
+
+type migrateBlocksCmd struct {
+	backendOptions
+
+	SourceConfigFile   string   `type:"path" help:"Path to tempo config file for the source. Defaults to the destination config file and backend options"`
+	DestTenantID       string   `help:"tenant-id to write the blocks into. Defaults to the source tenant-id"`
+	Version            string   `help:"block version of the rewritten blocks. Defaults to the block version of the destination config"`
+	ParquetCompression string   `help:"compression of the rewritten blocks: none, snappy, gzip, lz4 or zstd. Defaults to the compression of the destination config"`
+	BlockID            []string `name:"block-id" help:"block ID to migrate, can be repeated. Defaults to all blocks that are not in the target version and compression"`
+	DryRun             bool     `help:"only list the blocks that would be migrated"`
+
+	TenantID string `arg:"" help:"tenant-id within the source bucket"`
+}
+
+func (cmd *migrateBlocksCmd) Run(opts *globalOptions) error {
+	ctx := context.Background()
+
+	destTenantID := cmd.DestTenantID
+	if destTenantID == "" {
+		destTenantID = cmd.TenantID
+	}
+
+	blockCfg, err := cmd.blockConfig(opts)
+	if err != nil {
+		return err
+	}
+
+	readerSource, compactorSource, err := cmd.loadSource(opts)
+	if err != nil {
+		return fmt.Errorf("setting up source backend: %w", err)
+	}
+	defer readerSource.Shutdown()
+
+	readerDest, writerDest, compactorDest, err := loadBackend(&cmd.backendOptions, opts)
+	if err != nil {
+		return fmt.Errorf("setting up destination backend: %w", err)
+	}
+	defer readerDest.Shutdown()
+
+	metas, err := cmd.blocksToMigrate(ctx, readerSource, blockCfg)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Blocks to migrate to %s (%s): %d\n", blockCfg.Version, blockCfg.ParquetCompression, len(metas))
+
+	// in place migrations replace the source blocks, otherwise both blocks would be queried
+	inPlace := cmd.sameBackend(opts) && destTenantID == cmd.TenantID
+
+	var migratedBlocks, migratedSize uint64
+	for _, meta := range metas {
+		fmt.Printf("Migrating block %s (%s, %d objects, %s)\n", meta.BlockID, meta.Version, meta.TotalObjects, humanize.Bytes(meta.Size))
+		if cmd.DryRun {
+			continue
+		}
+
+		newMeta, err := tempodb.MigrateBlock(ctx, meta, blockCfg, destTenantID, readerSource, readerDest, writerDest, compactorDest)
+		if err != nil {
+			return fmt.Errorf("migrating block %s: %w", meta.BlockID, err)
+		}
+
+		if inPlace {
+			err = compactorSource.MarkBlockCompacted(meta.BlockID, cmd.TenantID)
+			if err != nil {
+				return fmt.Errorf("marking block %s compacted: %w", meta.BlockID, err)
+			}
+		}
+
+		fmt.Printf("Block %s migrated and validated as block %s (%s)\n", meta.BlockID, newMeta.BlockID, humanize.Bytes(newMeta.Size))
+		migratedBlocks++
+		migratedSize += newMeta.Size
+	}
+
+	if !cmd.DryRun {
+		fmt.Printf("Finished migrating blocks. Wrote %d blocks, %s\n", migratedBlocks, humanize.Bytes(migratedSize))
+	}
+	return nil
+}
+
+// blockConfig returns the block config of the destination with the version and compression of the options
+func (cmd *migrateBlocksCmd) blockConfig(opts *globalOptions) (*common.BlockConfig, error) {
+	cfg, err := loadConfig(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	blockCfg := *cfg.StorageConfig.Trace.Block
+	if cmd.Version != "" {
+		blockCfg.Version = cmd.Version
+	}
+	if cmd.ParquetCompression != "" {
+		blockCfg.ParquetCompression = cmd.ParquetCompression
+	}
+
+	if _, err := encoding.FromVersion(blockCfg.Version); err != nil {
+		return nil, err
+	}
+	if err := common.ValidateConfig(&blockCfg); err != nil {
+		return nil, fmt.Errorf("invalid block config: %w", err)
+	}
+	return &blockCfg, nil
+}
+
+func (cmd *migrateBlocksCmd) blocksToMigrate(ctx context.Context, r backend.Reader, blockCfg *common.BlockConfig) ([]*backend.BlockMeta, error) {
+	var blockIDs []uuid.UUID
+	for _, id := range cmd.BlockID {
+		blockID, err := uuid.Parse(id)
+		if err != nil {
+			return nil, fmt.Errorf("invalid block ID %s: %w", id, err)
+		}
+		blockIDs = append(blockIDs, blockID)
+	}
+
+	explicit := len(blockIDs) > 0
+	if !explicit {
+		var err error
+		blockIDs, _, err = r.Blocks(ctx, cmd.TenantID)
+		if err != nil {
+			return nil, fmt.Errorf("listing blocks: %w", err)
+		}
+	}
+
+	compression, err := common.ParquetCompression(blockCfg.ParquetCompression)
+	if err != nil {
+		return nil, err
+	}
+
+	metas := make([]*backend.BlockMeta, 0, len(blockIDs))
+	for _, id := range blockIDs {
+		meta, err := r.BlockMeta(ctx, id, cmd.TenantID)
+		if err != nil {
+			return nil, fmt.Errorf("reading block meta %s: %w", id, err)
+		}
+
+		// older parquet blocks don't record their compression so it's only compared when it is set explicitly
+		upToDate := meta.Version == blockCfg.Version && (cmd.ParquetCompression == "" || meta.Encoding == compression)
+		if !explicit && upToDate {
+			continue
+		}
+		metas = append(metas, meta)
+	}
+
+	slices.SortFunc(metas, func(a, b *backend.BlockMeta) int {
+		return a.StartTime.Compare(b.StartTime)
+	})
+	return metas, nil
+}
+
+// sameBackend returns true if the source and destination are read from the same config. the backend options
+// then apply to both
+func (cmd *migrateBlocksCmd) sameBackend(opts *globalOptions) bool {
+	return cmd.SourceConfigFile == "" || (cmd.SourceConfigFile == opts.ConfigFile && cmd.backendOptions == backendOptions{})
+}
+
+func (cmd *migrateBlocksCmd) loadSource(opts *globalOptions) (backend.Reader, backend.Compactor, error) {
+	if cmd.SourceConfigFile == "" {
+		r, _, c, err := loadBackend(&cmd.backendOptions, opts)
+		return r, c, err
+	}
+
+	r, _, c, err := loadBackend(&backendOptions{}, &globalOptions{ConfigFile: cmd.SourceConfigFile})
+	return r, c, err
+}
@@ -1,17 +1,32 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"io"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"github.com/grafana/dskit/user"
 	"github.com/grafana/tempo/pkg/httpclient"
+	"github.com/grafana/tempo/pkg/tempopb"
+	"github.com/grafana/tempo/pkg/util"
 )
 
 type queryTraceIDCmd struct {
-	APIEndpoint string `arg:"" help:"tempo api endpoint"`
+	APIEndpoint string `arg:"" help:"tempo api endpoint. when using grpc this is the host and port. e.g. localhost:3200"`
 	TraceID     string `arg:"" help:"trace ID to retrieve"`
 
-	OrgID string `help:"optional orgID"`
+	OrgID   string `help:"optional orgID"`
+	UseGRPC bool   `help:"stream the trace over GRPC"`
 }
 
 func (cmd *queryTraceIDCmd) Run(_ *globalOptions) error {
+	if cmd.UseGRPC {
+		return cmd.traceIDGRPC()
+	}
+
 	client := httpclient.New(cmd.APIEndpoint, cmd.OrgID)
 
 	// util.QueryTrace will only add orgID header if len(orgID) > 0
@@ -22,3 +37,44 @@ func (cmd *queryTraceIDCmd) Run(_ *globalOptions) error {
 
 	return printAsJSON(trace)
 }
+
+func (cmd *queryTraceIDCmd) traceIDGRPC() error {
+	traceID, err := util.HexStringToTraceID(cmd.TraceID)
+	if err != nil {
+		return err
+	}
+
+	ctx := user.InjectOrgID(context.Background(), cmd.OrgID)
+	ctx, err = user.InjectIntoGRPCRequest(ctx)
+	if err != nil {
+		return err
+	}
+
+	clientConn, err := grpc.DialContext(ctx, cmd.APIEndpoint, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return err
+	}
+
+	client := tempopb.NewStreamingQuerierClient(clientConn)
+
+	resp, err := client.FindTraceByID(ctx, &tempopb.TraceByIDRequest{TraceID: traceID})
+	if err != nil {
+		return err
+	}
+
+	for {
+		traceResp, err := resp.Recv()
+		if traceResp != nil {
+			err = printAsJSON(traceResp)
+			if err != nil {
+				return err
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,267 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/grafana/dskit/dns"
+	"github.com/grafana/dskit/kv"
+	"github.com/grafana/dskit/kv/codec"
+	"github.com/grafana/dskit/kv/memberlist"
+	"github.com/grafana/dskit/ring"
+	"github.com/grafana/dskit/services"
+	"github.com/olekukonko/tablewriter"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/grafana/tempo/cmd/tempo/app"
+	tempo_ring "github.com/grafana/tempo/pkg/ring"
+	"github.com/grafana/tempo/pkg/util"
+)
+
+const (
+	ringIngester         = "ingester"
+	ringMetricsGenerator = "metrics-generator"
+	ringDistributor      = "distributor"
+	ringCompactor        = "compactor"
+)
+
+type ringDescribeCmd struct {
+	Ring string `arg:"" help:"ring to describe" enum:"ingester,metrics-generator,distributor,compactor"`
+
+	TraceID     string        `help:"trace ID as a hexadecimal string. prints the instances that own the trace. only valid for the ingester and metrics-generator rings"`
+	OrgID       string        `help:"tenant used to hash the trace ID" default:"single-tenant"`
+	Token       string        `help:"prints the instances that own the token"`
+	JoinMembers []string      `help:"memberlist members to join, optional, overrides join_members in config file"`
+	BindPort    int           `help:"memberlist bind port. the default of 0 picks a random port so the cli doesn't collide with a local tempo" default:"0"`
+	Timeout     time.Duration `help:"time to wait for the ring to be populated" default:"30s"`
+}
+
+func (cmd *ringDescribeCmd) Run(g *globalOptions) error {
+	cfg, err := loadConfig(g)
+	if err != nil {
+		return err
+	}
+
+	ringCfg, key, err := ringConfigFor(cfg, cmd.Ring)
+	if err != nil {
+		return err
+	}
+
+	token, hasToken, err := cmd.lookupToken()
+	if err != nil {
+		return err
+	}
+
+	logger := level.NewFilter(log.NewLogfmtLogger(os.Stderr), level.AllowWarn())
+	reg := prometheus.NewRegistry()
+
+	ctx, cancel := context.WithTimeout(context.Background(), cmd.Timeout)
+	defer cancel()
+
+	kvClient, stop, err := newRingKVClient(ctx, cfg, &ringCfg, cmd.JoinMembers, cmd.BindPort, logger, reg)
+	if err != nil {
+		return err
+	}
+	defer stop()
+
+	desc, err := waitForRingDesc(ctx, kvClient, key)
+	if err != nil {
+		return err
+	}
+
+	r, err := tempo_ring.New(ringCfg, cmd.Ring, key, reg)
+	if err != nil {
+		return fmt.Errorf("failed to create ring: %w", err)
+	}
+	if err := services.StartAndAwaitRunning(ctx, r); err != nil {
+		return fmt.Errorf("failed to start ring: %w", err)
+	}
+	defer func() { _ = services.StopAndAwaitTerminated(context.Background(), r) }()
+
+	printRingInstances(desc, ringCfg.HeartbeatTimeout)
+
+	if !hasToken {
+		return nil
+	}
+
+	// the ring picks up the kv contents asynchronously. wait until it has caught up with the desc we already printed
+	for r.InstancesCount() < len(desc.Ingesters) {
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("timed out waiting for ring: %w", ctx.Err())
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+
+	for _, op := range []struct {
+		name string
+		op   ring.Operation
+	}{
+		{"write", ring.Write},
+		{"read", ring.Read},
+	} {
+		rs, err := r.Get(token, op.op, nil, nil, nil)
+		if err != nil {
+			fmt.Printf("\n%s replication set for token %d: %v\n", op.name, token, err)
+			continue
+		}
+
+		fmt.Printf("\n%s replication set for token %d (max errors: %d, max unavailable zones: %d)\n", op.name, token, rs.MaxErrors, rs.MaxUnavailableZones)
+		printReplicationSet(rs)
+	}
+
+	return nil
+}
+
+// lookupToken returns the token to find the replication set for. trace ids are hashed the same way the distributor does
+func (cmd *ringDescribeCmd) lookupToken() (uint32, bool, error) {
+	if cmd.TraceID != "" && cmd.Token != "" {
+		return 0, false, errors.New("only one of --trace-id and --token can be provided")
+	}
+
+	if cmd.Token != "" {
+		token, err := strconv.ParseUint(cmd.Token, 10, 32)
+		if err != nil {
+			return 0, false, fmt.Errorf("failed to parse token: %w", err)
+		}
+		return uint32(token), true, nil
+	}
+
+	if cmd.TraceID != "" {
+		if cmd.Ring != ringIngester && cmd.Ring != ringMetricsGenerator {
+			return 0, false, fmt.Errorf("--trace-id is not supported for the %s ring", cmd.Ring)
+		}
+
+		traceID, err := util.HexStringToTraceID(cmd.TraceID)
+		if err != nil {
+			return 0, false, fmt.Errorf("failed to parse trace id: %w", err)
+		}
+		return util.TokenFor(cmd.OrgID, traceID), true, nil
+	}
+
+	return 0, false, nil
+}
+
+func ringConfigFor(cfg *app.Config, name string) (ring.Config, string, error) {
+	switch name {
+	case ringIngester:
+		return cfg.Ingester.LifecyclerConfig.RingConfig, cfg.Ingester.OverrideRingKey, nil
+	case ringMetricsGenerator:
+		return cfg.Generator.Ring.ToRingConfig(), cfg.Generator.OverrideRingKey, nil
+	case ringDistributor:
+		return cfg.Distributor.DistributorRing.ToLifecyclerConfig().RingConfig, cfg.Distributor.OverrideRingKey, nil
+	case ringCompactor:
+		return cfg.Compactor.ShardingRing.ToLifecyclerConfig().RingConfig, cfg.Compactor.OverrideRingKey, nil
+	}
+
+	return ring.Config{}, "", fmt.Errorf("unknown ring %s", name)
+}
+
+// newRingKVClient returns a client of the kv store of the ring. memberlist has to join the cluster before the ring can
+// be read. the cli joins as a regular memberlist member but never registers itself in a ring. stop leaves memberlist.
+func newRingKVClient(ctx context.Context, cfg *app.Config, ringCfg *ring.Config, joinMembers []string, bindPort int, logger log.Logger, reg prometheus.Registerer) (kv.Client, func(), error) {
+	stop := func() {}
+
+	if ringCfg.KVStore.Store == "memberlist" {
+		mlCfg := cfg.MemberlistKV
+		mlCfg.Codecs = []codec.Codec{ring.GetCodec()}
+		mlCfg.TCPTransport.BindPort = bindPort
+		if len(joinMembers) > 0 {
+			mlCfg.JoinMembers = joinMembers
+		}
+		if len(mlCfg.JoinMembers) == 0 {
+			return nil, nil, errors.New("memberlist kv store requires join members. pass --join-members or set memberlist.join_members in the config file")
+		}
+
+		mlSvc := memberlist.NewKVInitService(&mlCfg, logger, dns.NewProvider(logger, reg, dns.GolangResolverType), reg)
+		if err := services.StartAndAwaitRunning(ctx, mlSvc); err != nil {
+			return nil, nil, fmt.Errorf("failed to start memberlist: %w", err)
+		}
+		stop = func() { _ = services.StopAndAwaitTerminated(context.Background(), mlSvc) }
+
+		ringCfg.KVStore.MemberlistKV = mlSvc.GetMemberlistKV
+	}
+
+	kvClient, err := kv.NewClient(ringCfg.KVStore, ring.GetCodec(), reg, logger)
+	if err != nil {
+		stop()
+		return nil, nil, fmt.Errorf("failed to create kv client: %w", err)
+	}
+
+	return kvClient, stop, nil
+}
+
+func waitForRingDesc(ctx context.Context, client kv.Client, key string) (*ring.Desc, error) {
+	for {
+		val, err := client.Get(ctx, key)
+		if err != nil && ctx.Err() == nil {
+			return nil, fmt.Errorf("failed to read ring key %q: %w", key, err)
+		}
+
+		if desc := ring.GetOrCreateRingDesc(val); len(desc.Ingesters) > 0 {
+			return desc, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("timed out waiting for ring key %q to be populated: %w", key, ctx.Err())
+		case <-time.After(500 * time.Millisecond):
+		}
+	}
+}
+
+func printRingInstances(desc *ring.Desc, heartbeatTimeout time.Duration) {
+	ownedTokens := desc.CountTokens()
+	now := time.Now()
+
+	ids := make([]string, 0, len(desc.Ingesters))
+	for id := range desc.Ingesters {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	out := make([][]string, 0, len(ids))
+	for _, id := range ids {
+		inst := desc.Ingesters[id]
+
+		health := "healthy"
+		if !inst.IsHeartbeatHealthy(heartbeatTimeout, now) {
+			health = "unhealthy"
+		}
+
+		out = append(out, []string{
+			id,
+			inst.Addr,
+			inst.Zone,
+			inst.State.String(),
+			health,
+			time.Unix(inst.Timestamp, 0).UTC().Format(time.RFC3339),
+			strconv.Itoa(len(inst.Tokens)),
+			fmt.Sprintf("%.2f%%", float64(ownedTokens[id])*100/float64(uint64(1)<<32)),
+		})
+	}
+
+	w := tablewriter.NewWriter(os.Stdout)
+	w.SetHeader([]string{"id", "addr", "zone", "state", "health", "last heartbeat", "tokens", "ownership"})
+	w.AppendBulk(out)
+	w.Render()
+}
+
+func printReplicationSet(rs ring.ReplicationSet) {
+	out := make([][]string, 0, len(rs.Instances))
+	for _, inst := range rs.Instances {
+		out = append(out, []string{inst.Id, inst.Addr, inst.Zone, inst.State.String()})
+	}
+
+	w := tablewriter.NewWriter(os.Stdout)
+	w.SetHeader([]string{"id", "addr", "zone", "state"})
+	w.AppendBulk(out)
+	w.Render()
+}
@@ -11,9 +11,11 @@ import (
 	"github.com/grafana/tempo/cmd/tempo/app"
 	"github.com/grafana/tempo/tempodb/backend"
 	"github.com/grafana/tempo/tempodb/backend/azure"
+	"github.com/grafana/tempo/tempodb/backend/encryption"
 	"github.com/grafana/tempo/tempodb/backend/gcs"
 	"github.com/grafana/tempo/tempodb/backend/local"
 	"github.com/grafana/tempo/tempodb/backend/s3"
+	"github.com/grafana/tempo/tempodb/backend/swift"
 )
 
 const (
@@ -27,7 +29,7 @@ type globalOptions struct {
 }
 
 type backendOptions struct {
-	Backend string `help:"backend to connect to (s3/gcs/local/azure), optional, overrides backend in config file" enum:",s3,gcs,local,azure" default:""`
+	Backend string `help:"backend to connect to (s3/gcs/local/azure/swift), optional, overrides backend in config file" enum:",s3,gcs,local,azure,swift" default:""`
 	Bucket  string `help:"bucket (or path on local backend) to scan, optional, overrides bucket in config file"`
 
 	S3Endpoint string `name:"s3-endpoint" help:"s3 endpoint (s3.dualstack.us-east-2.amazonaws.com), optional, overrides endpoint in config file"`
@@ -83,8 +85,20 @@ var cli struct {
 		Convert3to4 convertParquet3to4 `cmd:"" help:"convert an existing vParquet3 file to vParquet4 block"`
 	} `cmd:""`
 
+	Ring struct {
+		Describe ringDescribeCmd `cmd:"" help:"describe ring members and the replication set for a trace ID or token"`
+	} `cmd:""`
+
+	Block struct {
+		Copy   blockCopyCmd   `cmd:"" help:"copy a block between backends or tenants"`
+		Verify blockVerifyCmd `cmd:"" help:"verify the meta, index, bloom and data of a block for corruption"`
+	} `cmd:""`
+
+	Doctor doctorCmd `cmd:"" help:"check a cluster and its backend for common misconfigurations and health issues"`
+
 	Migrate struct {
 		Tenant          migrateTenantCmd          `cmd:"" help:"migrate tenant between two backends"`
+		Blocks          migrateBlocksCmd          `cmd:"" help:"rewrite blocks into another block version, compression, tenant or backend"`
 		OverridesConfig migrateOverridesConfigCmd `cmd:"" help:"migrate overrides config"`
 	} `cmd:""`
 }
@@ -100,24 +114,44 @@ func main() {
 	ctx.FatalIfErrorf(err)
 }
 
-func loadBackend(b *backendOptions, g *globalOptions) (backend.Reader, backend.Writer, backend.Compactor, error) {
+// loadConfig returns the default tempo config overlaid with the config file, if one was provided
+func loadConfig(g *globalOptions) (*app.Config, error) {
 	// Defaults
-	cfg := app.Config{}
+	cfg := &app.Config{}
 	cfg.RegisterFlagsAndApplyDefaults("", &flag.FlagSet{})
 
 	// Existing config
 	if g.ConfigFile != "" {
 		buff, err := os.ReadFile(g.ConfigFile)
 		if err != nil {
-			return nil, nil, nil, fmt.Errorf("failed to read configFile %s: %w", g.ConfigFile, err)
+			return nil, fmt.Errorf("failed to read configFile %s: %w", g.ConfigFile, err)
 		}
 
-		err = yaml.UnmarshalStrict(buff, &cfg)
+		err = yaml.UnmarshalStrict(buff, cfg)
 		if err != nil {
-			return nil, nil, nil, fmt.Errorf("failed to parse configFile %s: %w", g.ConfigFile, err)
+			return nil, fmt.Errorf("failed to parse configFile %s: %w", g.ConfigFile, err)
 		}
 	}
 
+	return cfg, nil
+}
+
+func loadBackend(b *backendOptions, g *globalOptions) (backend.Reader, backend.Writer, backend.Compactor, error) {
+	r, w, c, err := loadRawBackend(b, g)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return backend.NewReader(r), backend.NewWriter(w), c, nil
+}
+
+// loadRawBackend returns the raw backend to list and read objects that are not blocks
+func loadRawBackend(b *backendOptions, g *globalOptions) (backend.RawReader, backend.RawWriter, backend.Compactor, error) {
+	cfg, err := loadConfig(g)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
 	// cli overrides
 	if b.Backend != "" {
 		cfg.StorageConfig.Trace.Backend = b.Backend
@@ -128,13 +162,13 @@ func loadBackend(b *backendOptions, g *globalOptions) (backend.Reader, backend.W
 		cfg.StorageConfig.Trace.GCS.BucketName = b.Bucket
 		cfg.StorageConfig.Trace.S3.Bucket = b.Bucket
 		cfg.StorageConfig.Trace.Azure.ContainerName = b.Bucket
+		cfg.StorageConfig.Trace.Swift.ContainerName = b.Bucket
 	}
 
 	if b.S3Endpoint != "" {
 		cfg.StorageConfig.Trace.S3.Endpoint = b.S3Endpoint
 	}
 
-	var err error
 	var r backend.RawReader
 	var w backend.RawWriter
 	var c backend.Compactor
@@ -148,6 +182,8 @@ func loadBackend(b *backendOptions, g *globalOptions) (backend.Reader, backend.W
 		r, w, c, err = s3.New(cfg.StorageConfig.Trace.S3)
 	case backend.Azure:
 		r, w, c, err = azure.New(cfg.StorageConfig.Trace.Azure)
+	case backend.Swift:
+		r, w, c, err = swift.New(cfg.StorageConfig.Trace.Swift)
 	default:
 		err = fmt.Errorf("unknown backend %s", cfg.StorageConfig.Trace.Backend)
 	}
@@ -156,5 +192,12 @@ func loadBackend(b *backendOptions, g *globalOptions) (backend.Reader, backend.W
 		return nil, nil, nil, err
 	}
 
-	return backend.NewReader(r), backend.NewWriter(w), c, nil
+	if cfg.StorageConfig.Trace.Encryption.Enabled {
+		r, w, c, err = encryption.New(&cfg.StorageConfig.Trace.Encryption, r, w, c)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+	}
+
+	return r, w, c, nil
 }
@@ -42,6 +42,8 @@ var (
 	tempoReadBackoffDuration      time.Duration
 	tempoSearchBackoffDuration    time.Duration
 	tempoRetentionDuration        time.Duration
+	tempoFreshnessPollDuration    time.Duration
+	tempoFreshnessTimeout         time.Duration
 	tempoPushTLS                  bool
 
 	logger *zap.Logger
@@ -71,6 +73,8 @@ func init() {
 	flag.DurationVar(&tempoReadBackoffDuration, "tempo-read-backoff-duration", 30*time.Second, "The amount of time to pause between read Tempo calls")
 	flag.DurationVar(&tempoSearchBackoffDuration, "tempo-search-backoff-duration", 60*time.Second, "The amount of time to pause between search Tempo calls.  Set to 0s to disable search.")
 	flag.DurationVar(&tempoRetentionDuration, "tempo-retention-duration", 336*time.Hour, "The block retention that Tempo is using")
+	flag.DurationVar(&tempoFreshnessPollDuration, "tempo-freshness-poll-duration", 0, "The amount of time to pause between queries for a just written trace to measure the time until it can be read. Set to 0s to disable.")
+	flag.DurationVar(&tempoFreshnessTimeout, "tempo-freshness-timeout", 5*time.Minute, "The maximum time to wait for a just written trace to be readable")
 }
 
 func main() {
@@ -139,6 +143,8 @@ func main() {
 			err := info.EmitBatches(client)
 			if err != nil {
 				metricErrorTotal.Inc()
+			} else if tempoFreshnessPollDuration > 0 {
+				go measureFreshness(info)
 			}
 			queueFutureBatches(client, info)
 		}
@@ -260,6 +266,56 @@ func queueFutureBatches(client *jaeger_grpc.Reporter, info *util.TraceInfo) {
 	}()
 }
 
+// measureFreshness polls Tempo for a just written trace and records the time until it can be found by id
+func measureFreshness(info *util.TraceInfo) {
+	log := logger.With(
+		zap.String("org_id", tempoOrgID),
+		zap.String("write_trace_id", info.HexID()),
+		zap.Int64("seed", info.Timestamp().Unix()),
+	)
+
+	client := httpclient.New(tempoQueryURL, tempoOrgID)
+	elapsed, found := waitUntilReadable(func() (bool, error) {
+		tr, err := client.QueryTrace(info.HexID())
+		if errors.Is(err, util.ErrTraceNotFound) {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		return len(tr.Batches) > 0, nil
+	}, tempoFreshnessPollDuration, tempoFreshnessTimeout)
+
+	if !found {
+		metricWriteToReadableTimeouts.Inc()
+		log.Error("trace not readable within freshness timeout", zap.Duration("timeout", tempoFreshnessTimeout))
+		return
+	}
+
+	metricWriteToReadable.Observe(elapsed.Seconds())
+	log.Info("trace readable", zap.Duration("write_to_readable", elapsed))
+}
+
+// waitUntilReadable calls readable every poll until it returns true or timeout has passed. It returns the time until
+// readable returned true. Errors are logged and polling continues.
+func waitUntilReadable(readable func() (bool, error), poll, timeout time.Duration) (time.Duration, bool) {
+	start := time.Now()
+	for {
+		ok, err := readable()
+		if err != nil {
+			logger.Error("error querying Tempo for freshness", zap.Error(err))
+		}
+		if ok {
+			return time.Since(start), true
+		}
+
+		if time.Since(start)+poll > timeout {
+			return 0, false
+		}
+		time.Sleep(poll)
+	}
+}
+
 func pushMetrics(metrics traceMetrics) {
 	metricTracesInspected.Add(float64(metrics.requested))
 	metricTracesErrors.WithLabelValues("incorrectresult").Add(float64(metrics.incorrectResult))
@@ -2,6 +2,7 @@ package main
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"os"
 	"testing"
@@ -11,6 +12,7 @@ import (
 	"github.com/gogo/protobuf/jsonpb"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
 
 	"github.com/grafana/tempo/pkg/tempopb"
 	v1 "github.com/grafana/tempo/pkg/tempopb/trace/v1"
@@ -99,6 +101,35 @@ func TestResponseFixture(t *testing.T) {
 	}
 }
 
+func TestWaitUntilReadable(t *testing.T) {
+	logger = zap.NewNop()
+
+	calls := 0
+	elapsed, found := waitUntilReadable(func() (bool, error) {
+		calls++
+		switch calls {
+		case 1:
+			return false, nil
+		case 2:
+			return false, errors.New("request failed")
+		default:
+			return true, nil
+		}
+	}, 10*time.Millisecond, time.Second)
+	require.True(t, found)
+	require.Equal(t, 3, calls)
+	require.GreaterOrEqual(t, elapsed, 20*time.Millisecond)
+
+	// never readable
+	calls = 0
+	_, found = waitUntilReadable(func() (bool, error) {
+		calls++
+		return false, nil
+	}, 10*time.Millisecond, 55*time.Millisecond)
+	require.False(t, found)
+	require.Greater(t, calls, 1)
+}
+
 func TestEqualTraces(t *testing.T) {
 	seed := time.Now()
 	info1 := util.NewTraceInfo(seed, "")
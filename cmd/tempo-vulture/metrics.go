@@ -34,10 +34,31 @@ var (
 		},
 		[]string{"error"},
 	)
+
+	// metricWriteToReadable is a prometheus histogram of the time from writing a trace until it can be read.
+	metricWriteToReadable = prometheus.NewHistogram(
+		prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "write_to_readable_seconds",
+			Help:      "time from writing a trace until it can be found by id",
+			Buckets:   prometheus.ExponentialBuckets(0.25, 2, 12),
+		},
+	)
+
+	// metricWriteToReadableTimeouts is a prometheus counter of traces that couldn't be read within the freshness timeout.
+	metricWriteToReadableTimeouts = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "write_to_readable_timeout_total",
+			Help:      "total number of written traces that couldn't be found by id within the freshness timeout",
+		},
+	)
 )
 
 func init() {
 	prometheus.MustRegister(metricErrorTotal)
 	prometheus.MustRegister(metricTracesInspected)
 	prometheus.MustRegister(metricTracesErrors)
+	prometheus.MustRegister(metricWriteToReadable)
+	prometheus.MustRegister(metricWriteToReadableTimeouts)
 }
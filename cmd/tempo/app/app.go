@@ -9,6 +9,7 @@ import (
 	"io"
 	"net/http"
 	"sort"
+	"sync"
 	"time"
 
 	"github.com/go-kit/log/level"
@@ -41,6 +42,7 @@ import (
 	"github.com/grafana/tempo/modules/storage"
 	"github.com/grafana/tempo/pkg/api"
 	"github.com/grafana/tempo/pkg/cache"
+	"github.com/grafana/tempo/pkg/tenantusage"
 	"github.com/grafana/tempo/pkg/usagestats"
 	"github.com/grafana/tempo/pkg/util"
 	"github.com/grafana/tempo/pkg/util/log"
@@ -82,6 +84,7 @@ type App struct {
 	generator     *generator.Generator
 	store         storage.Store
 	usageReport   *usagestats.Reporter
+	tenantUsage   *tenantusage.Tracker
 	cacheProvider cache.Provider
 	MemberlistKV  *memberlist.KVInitService
 
@@ -91,14 +94,24 @@ type App struct {
 	ModuleManager *modules.Manager
 	serviceMap    map[string]services.Service
 	deps          map[string][]string
+
+	// configLoader, loadedCfg and reloadMtx support reloading parts of the config at runtime. see ReloadConfig
+	configLoader ConfigLoader
+	loadedCfg    *Config
+	reloadMtx    sync.Mutex
+
+	// blockProfileRate is the rate set by the profiling endpoint. the runtime doesn't expose it
+	blockProfileRate int
 }
 
 // New makes a new app.
 func New(cfg Config) (*App, error) {
+	loadedCfg := cfg
 	app := &App{
 		cfg:       cfg,
 		readRings: map[string]*ring.Ring{},
 		Server:    newTempoServer(),
+		loadedCfg: &loadedCfg,
 	}
 
 	usagestats.Edition("oss")
@@ -204,6 +217,11 @@ func (t *App) Run() error {
 	t.Server.HTTPRouter().Path("/ready").Handler(t.readyHandler(sm, shutdownRequested))
 	t.Server.HTTPRouter().Path("/status").Handler(t.statusHandler()).Methods("GET")
 	t.Server.HTTPRouter().Path("/status/{endpoint}").Handler(t.statusHandler()).Methods("GET")
+	if t.configLoader != nil {
+		t.Server.HTTPRouter().Path("/config/reload").Handler(t.reloadHandler()).Methods("POST")
+	}
+	t.Server.HTTPRouter().Path("/log_level").Handler(t.logLevelHandler()).Methods("GET", "POST")
+	t.Server.HTTPRouter().Path("/debug/profiling").Handler(t.profilingHandler()).Methods("GET", "POST")
 	grpc_health_v1.RegisterHealthServer(t.Server.GRPC(),
 		grpcutil.NewHealthCheckFrom(
 			grpcutil.WithShutdownRequested(shutdownRequested),
@@ -251,6 +269,12 @@ func (t *App) Run() error {
 		sm.StopAsync()
 	}()
 
+	if t.configLoader != nil {
+		stopReload := make(chan struct{})
+		defer close(stopReload)
+		go t.reloadOnSignal(stopReload)
+	}
+
 	// Start all services. This can really only fail if some service is already
 	// in other state than New, which should not be the case.
 	err = sm.StartAsync(context.Background())
@@ -22,6 +22,7 @@ import (
 	"github.com/grafana/tempo/modules/querier"
 	"github.com/grafana/tempo/modules/storage"
 	internalserver "github.com/grafana/tempo/pkg/server"
+	"github.com/grafana/tempo/pkg/tenantusage"
 	"github.com/grafana/tempo/pkg/usagestats"
 	"github.com/grafana/tempo/pkg/util"
 	"github.com/grafana/tempo/tempodb"
@@ -55,6 +56,7 @@ type Config struct {
 	Overrides       overrides.Config        `yaml:"overrides,omitempty"`
 	MemberlistKV    memberlist.KVConfig     `yaml:"memberlist,omitempty"`
 	UsageReport     usagestats.Config       `yaml:"usage_report,omitempty"`
+	TenantUsage     tenantusage.Config      `yaml:"tenant_usage,omitempty"`
 	CacheProvider   cache.Config            `yaml:"cache,omitempty"`
 }
 
@@ -132,6 +134,7 @@ func (c *Config) RegisterFlagsAndApplyDefaults(prefix string, f *flag.FlagSet) {
 	c.Compactor.RegisterFlagsAndApplyDefaults(util.PrefixConfig(prefix, "compactor"), f)
 	c.StorageConfig.RegisterFlagsAndApplyDefaults(util.PrefixConfig(prefix, "storage"), f)
 	c.UsageReport.RegisterFlagsAndApplyDefaults(util.PrefixConfig(prefix, "reporting"), f)
+	c.TenantUsage.RegisterFlagsAndApplyDefaults(util.PrefixConfig(prefix, "tenant-usage"), f)
 	c.CacheProvider.RegisterFlagsAndApplyDefaults(util.PrefixConfig(prefix, "cache"), f)
 }
 
@@ -151,6 +154,10 @@ func (c *Config) CheckConfig() []ConfigWarning {
 		warnings = append(warnings, warnBlockRetention)
 	}
 
+	if c.Compactor.Compactor.CompactedBlockRetention < c.StorageConfig.Trace.BlocklistPoll {
+		warnings = append(warnings, warnCompactedBlockRetention)
+	}
+
 	if c.Compactor.Compactor.RetentionConcurrency == 0 {
 		warnings = append(warnings, warnRetentionConcurrency)
 	}
@@ -251,9 +258,13 @@ var (
 		Explain: "You may receive 404s between the time the ingesters have flushed a trace and the querier is aware of the new block",
 	}
 	warnBlockRetention = ConfigWarning{
-		Message: "compactor.compaction.compacted_block_timeout < storage.trace.blocklist_poll",
+		Message: "compactor.compaction.block_retention < storage.trace.blocklist_poll",
 		Explain: "Queriers and Compactors may attempt to read a block that no longer exists",
 	}
+	warnCompactedBlockRetention = ConfigWarning{
+		Message: "compactor.compaction.compacted_block_retention < storage.trace.blocklist_poll",
+		Explain: "Compacted blocks may be deleted before the queriers poll the blocklist and queries may fail on blocks that no longer exist",
+	}
 	warnRetentionConcurrency = ConfigWarning{
 		Message: "c.Compactor.Compactor.RetentionConcurrency must be greater than zero. Using default.",
 		Explain: fmt.Sprintf("default=%d", tempodb.DefaultRetentionConcurrency),
@@ -315,6 +326,8 @@ func (c *Config) tracesAndOverridesStorageConflict() bool {
 		return traceStorage.S3.PathMatches(overridesStorage.S3)
 	case backend.Azure:
 		return traceStorage.Azure.PathMatches(overridesStorage.Azure)
+	case backend.Swift:
+		return traceStorage.Swift.PathMatches(overridesStorage.Swift)
 	}
 
 	return false
@@ -53,6 +53,7 @@ func TestConfig_CheckConfig(t *testing.T) {
 			expect: []ConfigWarning{
 				warnCompleteBlockTimeout,
 				warnBlockRetention,
+				warnCompactedBlockRetention,
 				warnRetentionConcurrency,
 				warnStorageTraceBackendS3,
 				warnBlocklistPollConcurrency,
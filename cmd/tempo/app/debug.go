@@ -0,0 +1,99 @@
+package app
+
+import (
+	"fmt"
+	"net/http"
+	"runtime"
+	"strconv"
+
+	"github.com/go-kit/log/level"
+	dslog "github.com/grafana/dskit/log"
+
+	"github.com/grafana/tempo/pkg/util"
+	util_log "github.com/grafana/tempo/pkg/util/log"
+)
+
+const (
+	paramLogLevel             = "log_level"
+	paramMutexProfileFraction = "mutex_profile_fraction"
+	paramBlockProfileRate     = "block_profile_rate"
+)
+
+type logLevelResponse struct {
+	LogLevel string `json:"logLevel"`
+}
+
+type profilingResponse struct {
+	MutexProfileFraction int `json:"mutexProfileFraction"`
+	BlockProfileRate     int `json:"blockProfileRate"`
+}
+
+// logLevelHandler returns the log level on GET and changes it to the log_level parameter on POST. the level is kept
+// until it's changed again or a config reload changes server.log_level
+func (t *App) logLevelHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		t.reloadMtx.Lock()
+		defer t.reloadMtx.Unlock()
+
+		if r.Method == http.MethodPost {
+			var lvl dslog.Level
+			if err := lvl.Set(r.FormValue(paramLogLevel)); err != nil {
+				http.Error(w, fmt.Sprintf("invalid %s: %v", paramLogLevel, err), http.StatusBadRequest)
+				return
+			}
+
+			util_log.SetLevel(lvl.String())
+			t.cfg.Server.LogLevel = lvl
+			level.Info(util_log.Logger).Log("msg", "log level changed", "level", lvl.String())
+		}
+
+		util.WriteJSONResponse(w, logLevelResponse{LogLevel: t.cfg.Server.LogLevel.String()})
+	}
+}
+
+// profilingHandler returns the mutex and block profiling rates on GET and changes them to the mutex_profile_fraction
+// and block_profile_rate parameters on POST. a rate of 0 disables the profile. the profiles are served by
+// /debug/pprof/mutex and /debug/pprof/block
+func (t *App) profilingHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		t.reloadMtx.Lock()
+		defer t.reloadMtx.Unlock()
+
+		if r.Method == http.MethodPost {
+			mutexFraction, err := rateParam(r, paramMutexProfileFraction, runtime.SetMutexProfileFraction(-1))
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			blockRate, err := rateParam(r, paramBlockProfileRate, t.blockProfileRate)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+
+			runtime.SetMutexProfileFraction(mutexFraction)
+			runtime.SetBlockProfileRate(blockRate)
+			t.blockProfileRate = blockRate
+			level.Info(util_log.Logger).Log("msg", "profiling rates changed", "mutex_profile_fraction", mutexFraction, "block_profile_rate", blockRate)
+		}
+
+		util.WriteJSONResponse(w, profilingResponse{
+			MutexProfileFraction: runtime.SetMutexProfileFraction(-1),
+			BlockProfileRate:     t.blockProfileRate,
+		})
+	}
+}
+
+// rateParam returns the non-negative integer parameter of the request, or current if it is not set
+func rateParam(r *http.Request, name string, current int) (int, error) {
+	s := r.FormValue(name)
+	if s == "" {
+		return current, nil
+	}
+
+	v, err := strconv.Atoi(s)
+	if err != nil || v < 0 {
+		return 0, fmt.Errorf("invalid %s %q: must be a non-negative integer", name, s)
+	}
+	return v, nil
+}
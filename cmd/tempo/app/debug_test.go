@@ -0,0 +1,71 @@
+package app
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	util_log "github.com/grafana/tempo/pkg/util/log"
+)
+
+func TestLogLevelHandler(t *testing.T) {
+	cfg := newDefaultConfig()
+	app := &App{cfg: *cfg, loadedCfg: cfg}
+	handler := app.logLevelHandler()
+
+	// the handler changes the level of the global logger
+	originalLevel := cfg.Server.LogLevel.String()
+	t.Cleanup(func() { util_log.SetLevel(originalLevel) })
+
+	do := func(method, target string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(method, target, nil))
+		return rec
+	}
+
+	rec := do(http.MethodGet, "/log_level")
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.JSONEq(t, `{"logLevel":"info"}`, rec.Body.String())
+
+	rec = do(http.MethodPost, "/log_level?log_level=debug")
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.JSONEq(t, `{"logLevel":"debug"}`, rec.Body.String())
+	require.Equal(t, "debug", app.cfg.Server.LogLevel.String())
+
+	rec = do(http.MethodPost, "/log_level?log_level=verbose")
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+	require.Equal(t, "debug", app.cfg.Server.LogLevel.String())
+}
+
+func TestProfilingHandler(t *testing.T) {
+	defer runtime.SetMutexProfileFraction(runtime.SetMutexProfileFraction(-1))
+	defer runtime.SetBlockProfileRate(0)
+
+	app := &App{}
+	handler := app.profilingHandler()
+
+	do := func(method, target string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(method, target, nil))
+		return rec
+	}
+
+	rec := do(http.MethodPost, "/debug/profiling?mutex_profile_fraction=5&block_profile_rate=1000")
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.JSONEq(t, `{"mutexProfileFraction":5,"blockProfileRate":1000}`, rec.Body.String())
+	require.Equal(t, 5, runtime.SetMutexProfileFraction(-1))
+
+	// unset parameters are left unchanged
+	rec = do(http.MethodPost, "/debug/profiling?block_profile_rate=0")
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.JSONEq(t, `{"mutexProfileFraction":5,"blockProfileRate":0}`, rec.Body.String())
+
+	rec = do(http.MethodPost, "/debug/profiling?mutex_profile_fraction=-1")
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = do(http.MethodGet, "/debug/profiling")
+	require.JSONEq(t, `{"mutexProfileFraction":5,"blockProfileRate":0}`, rec.Body.String())
+}
@@ -2,13 +2,187 @@ package app
 
 import (
 	"net/http"
+	"strconv"
+	"strings"
+	"sync"
 
 	"github.com/grafana/dskit/middleware"
 	"github.com/klauspost/compress/gzhttp"
+	"github.com/klauspost/compress/zstd"
+
+	"github.com/grafana/tempo/modules/frontend"
 )
 
-func httpGzipMiddleware() middleware.Interface {
+const (
+	headerAcceptEncoding  = "Accept-Encoding"
+	headerContentEncoding = "Content-Encoding"
+	headerContentLength   = "Content-Length"
+	headerContentType     = "Content-Type"
+	headerVary            = "Vary"
+
+	encodingZstd = "zstd"
+)
+
+var zstdEncoderPool = sync.Pool{
+	New: func() any {
+		enc, _ := zstd.NewWriter(nil, zstd.WithEncoderConcurrency(1))
+		return enc
+	},
+}
+
+// httpCompressionMiddleware compresses responses that are at least cfg.MinSizeBytes large. zstd is used if it is
+// enabled and accepted by the client, otherwise gzip is used if accepted by the client.
+func httpCompressionMiddleware(cfg frontend.ResponseCompressionConfig) (middleware.Interface, error) {
+	gzipWrapper, err := gzhttp.NewWrapper(gzhttp.MinSize(cfg.MinSizeBytes))
+	if err != nil {
+		return nil, err
+	}
+
 	return middleware.Func(func(handler http.Handler) http.Handler {
-		return gzhttp.GzipHandler(handler)
-	})
+		gzipHandler := gzipWrapper(handler)
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !cfg.Zstd || !acceptsEncoding(r.Header.Get(headerAcceptEncoding), encodingZstd) {
+				gzipHandler.ServeHTTP(w, r)
+				return
+			}
+
+			w.Header().Add(headerVary, headerAcceptEncoding)
+
+			zw := &zstdResponseWriter{
+				ResponseWriter: w,
+				minSize:        cfg.MinSizeBytes,
+			}
+			defer zw.close()
+
+			handler.ServeHTTP(zw, r)
+		})
+	}), nil
+}
+
+// acceptsEncoding returns true if the Accept-Encoding header lists the encoding with a non zero quality
+func acceptsEncoding(header, encoding string) bool {
+	for _, part := range strings.Split(header, ",") {
+		name, params, _ := strings.Cut(part, ";")
+		if !strings.EqualFold(strings.TrimSpace(name), encoding) {
+			continue
+		}
+
+		q, found := strings.CutPrefix(strings.TrimSpace(params), "q=")
+		if !found {
+			return true
+		}
+		f, err := strconv.ParseFloat(q, 64)
+		return err == nil && f > 0
+	}
+
+	return false
+}
+
+// zstdResponseWriter buffers the response until it reaches minSize bytes and then compresses it with zstd.
+// smaller responses and responses that are already encoded are written as they are.
+type zstdResponseWriter struct {
+	http.ResponseWriter
+	minSize int
+
+	code        int
+	buf         []byte
+	enc         *zstd.Encoder
+	passthrough bool
+}
+
+func (w *zstdResponseWriter) WriteHeader(code int) {
+	if w.code == 0 {
+		w.code = code
+	}
+}
+
+func (w *zstdResponseWriter) Write(b []byte) (int, error) {
+	if w.enc != nil {
+		return w.enc.Write(b)
+	}
+	if w.passthrough {
+		return w.ResponseWriter.Write(b)
+	}
+
+	w.buf = append(w.buf, b...)
+	if len(w.buf) < w.minSize {
+		return len(b), nil
+	}
+
+	if err := w.start(); err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
+
+// Flush starts the response regardless of the size written so far. streamed responses are always compressed
+func (w *zstdResponseWriter) Flush() {
+	if w.enc == nil && !w.passthrough {
+		_ = w.start()
+	}
+	if w.enc != nil {
+		_ = w.enc.Flush()
+	}
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// start writes the header and the buffered data. the data is compressed unless the handler already set a
+// Content-Encoding.
+func (w *zstdResponseWriter) start() error {
+	h := w.Header()
+	if h.Get(headerContentType) == "" && len(w.buf) > 0 {
+		h.Set(headerContentType, http.DetectContentType(w.buf))
+	}
+
+	if h.Get(headerContentEncoding) != "" {
+		return w.writeUncompressed()
+	}
+
+	h.Set(headerContentEncoding, encodingZstd)
+	h.Del(headerContentLength)
+	w.ResponseWriter.WriteHeader(w.statusCode())
+
+	w.enc = zstdEncoderPool.Get().(*zstd.Encoder)
+	w.enc.Reset(w.ResponseWriter)
+
+	_, err := w.enc.Write(w.buf)
+	w.buf = nil
+	return err
+}
+
+func (w *zstdResponseWriter) writeUncompressed() error {
+	w.passthrough = true
+	w.ResponseWriter.WriteHeader(w.statusCode())
+
+	if len(w.buf) == 0 {
+		return nil
+	}
+	_, err := w.ResponseWriter.Write(w.buf)
+	w.buf = nil
+	return err
+}
+
+func (w *zstdResponseWriter) statusCode() int {
+	if w.code == 0 {
+		return http.StatusOK
+	}
+	return w.code
+}
+
+// close completes the compressed stream or writes the buffered response if it never reached minSize
+func (w *zstdResponseWriter) close() {
+	if w.enc != nil {
+		_ = w.enc.Close()
+		w.enc.Reset(nil)
+		zstdEncoderPool.Put(w.enc)
+		w.enc = nil
+		return
+	}
+
+	if !w.passthrough {
+		_ = w.writeUncompressed()
+	}
 }
@@ -0,0 +1,150 @@
+package app
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/modules/frontend"
+)
+
+func TestHTTPCompressionMiddleware(t *testing.T) {
+	large := strings.Repeat(`{"traceID":"1234"}`, 100)
+	small := `{"traceID":"1234"}`
+
+	tcs := []struct {
+		name             string
+		cfg              frontend.ResponseCompressionConfig
+		acceptEncoding   string
+		body             string
+		expectedEncoding string
+	}{
+		{
+			name:             "zstd",
+			cfg:              frontend.ResponseCompressionConfig{MinSizeBytes: 1024, Zstd: true},
+			acceptEncoding:   "gzip, deflate, br, zstd",
+			body:             large,
+			expectedEncoding: "zstd",
+		},
+		{
+			name:             "gzip",
+			cfg:              frontend.ResponseCompressionConfig{MinSizeBytes: 1024, Zstd: true},
+			acceptEncoding:   "gzip",
+			body:             large,
+			expectedEncoding: "gzip",
+		},
+		{
+			name:             "zstd disabled",
+			cfg:              frontend.ResponseCompressionConfig{MinSizeBytes: 1024},
+			acceptEncoding:   "zstd, gzip",
+			body:             large,
+			expectedEncoding: "gzip",
+		},
+		{
+			name:             "zstd rejected",
+			cfg:              frontend.ResponseCompressionConfig{MinSizeBytes: 1024, Zstd: true},
+			acceptEncoding:   "zstd;q=0, gzip",
+			body:             large,
+			expectedEncoding: "gzip",
+		},
+		{
+			name:           "zstd below threshold",
+			cfg:            frontend.ResponseCompressionConfig{MinSizeBytes: 1024, Zstd: true},
+			acceptEncoding: "zstd",
+			body:           small,
+		},
+		{
+			name:           "gzip below threshold",
+			cfg:            frontend.ResponseCompressionConfig{MinSizeBytes: 1024, Zstd: true},
+			acceptEncoding: "gzip",
+			body:           small,
+		},
+		{
+			name: "not accepted",
+			cfg:  frontend.ResponseCompressionConfig{MinSizeBytes: 1024, Zstd: true},
+			body: large,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			m, err := httpCompressionMiddleware(tc.cfg)
+			require.NoError(t, err)
+
+			handler := m.Wrap(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusOK)
+				// write in chunks to exercise buffering up to the threshold
+				for i := 0; i < len(tc.body); i += 100 {
+					_, _ = w.Write([]byte(tc.body[i:min(i+100, len(tc.body))]))
+				}
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/api/search", nil)
+			if tc.acceptEncoding != "" {
+				req.Header.Set("Accept-Encoding", tc.acceptEncoding)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			require.Equal(t, http.StatusOK, rec.Code)
+			require.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+			require.Equal(t, tc.expectedEncoding, rec.Header().Get("Content-Encoding"))
+
+			var body []byte
+			switch tc.expectedEncoding {
+			case "zstd":
+				dec, err := zstd.NewReader(rec.Body)
+				require.NoError(t, err)
+				defer dec.Close()
+				body, err = io.ReadAll(dec)
+				require.NoError(t, err)
+			case "gzip":
+				dec, err := gzip.NewReader(rec.Body)
+				require.NoError(t, err)
+				body, err = io.ReadAll(dec)
+				require.NoError(t, err)
+			default:
+				body = rec.Body.Bytes()
+			}
+			require.Equal(t, tc.body, string(body))
+		})
+	}
+}
+
+func TestHTTPCompressionMiddlewareAlreadyEncoded(t *testing.T) {
+	m, err := httpCompressionMiddleware(frontend.ResponseCompressionConfig{Zstd: true})
+	require.NoError(t, err)
+
+	encoded := []byte("already encoded")
+	handler := m.Wrap(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Encoding", "snappy")
+		w.WriteHeader(http.StatusTeapot)
+		_, _ = w.Write(encoded)
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/search", nil)
+	req.Header.Set("Accept-Encoding", "zstd")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusTeapot, rec.Code)
+	require.Equal(t, "snappy", rec.Header().Get("Content-Encoding"))
+	require.True(t, bytes.Equal(encoded, rec.Body.Bytes()))
+}
+
+func TestAcceptsEncoding(t *testing.T) {
+	require.True(t, acceptsEncoding("zstd", "zstd"))
+	require.True(t, acceptsEncoding("gzip, ZSTD", "zstd"))
+	require.True(t, acceptsEncoding("gzip;q=1.0, zstd;q=0.5", "zstd"))
+	require.False(t, acceptsEncoding("zstd;q=0", "zstd"))
+	require.False(t, acceptsEncoding("gzip, br", "zstd"))
+	require.False(t, acceptsEncoding("", "zstd"))
+}
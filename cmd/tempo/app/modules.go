@@ -37,6 +37,7 @@ import (
 	"github.com/grafana/tempo/pkg/api"
 	tempo_ring "github.com/grafana/tempo/pkg/ring"
 	"github.com/grafana/tempo/pkg/tempopb"
+	"github.com/grafana/tempo/pkg/tenantusage"
 	"github.com/grafana/tempo/pkg/usagestats"
 	"github.com/grafana/tempo/pkg/util/log"
 	util_log "github.com/grafana/tempo/pkg/util/log"
@@ -45,6 +46,7 @@ import (
 	"github.com/grafana/tempo/tempodb/backend/gcs"
 	"github.com/grafana/tempo/tempodb/backend/local"
 	"github.com/grafana/tempo/tempodb/backend/s3"
+	"github.com/grafana/tempo/tempodb/backend/swift"
 )
 
 // The various modules that make up tempo.
@@ -56,6 +58,7 @@ const (
 	OptionalStore  string = "optional-store"
 	MemberlistKV   string = "memberlist-kv"
 	UsageReport    string = "usage-report"
+	TenantUsage    string = "tenant-usage"
 	Overrides      string = "overrides"
 	OverridesAPI   string = "overrides-api"
 	CacheProvider  string = "cache-provider"
@@ -194,6 +197,7 @@ func (t *App) initOverrides() (services.Service, error) {
 
 	t.Server.HTTPRouter().Path("/status/overrides").HandlerFunc(overrides.TenantsHandler(t.Overrides)).Methods("GET")
 	t.Server.HTTPRouter().Path("/status/overrides/{tenant}").HandlerFunc(overrides.TenantStatusHandler(t.Overrides)).Methods("GET")
+	t.Server.HTTPRouter().Path(addHTTPAPIPrefix(&t.cfg, api.PathOverridesTenant)).Handler(t.HTTPAuthMiddleware.Wrap(overrides.TenantLimitsHandler(t.Overrides))).Methods("GET")
 
 	return t.Overrides, nil
 }
@@ -224,6 +228,8 @@ func (t *App) initOverridesAPI() (services.Service, error) {
 }
 
 func (t *App) initDistributor() (services.Service, error) {
+	t.cfg.Distributor.TenantUsage = t.tenantUsage
+
 	// todo: make ingester client a module instead of passing the config everywhere
 	distributor, err := distributor.New(t.cfg.Distributor,
 		t.cfg.IngesterClient,
@@ -242,6 +248,14 @@ func (t *App) initDistributor() (services.Service, error) {
 		t.Server.HTTPRouter().Handle("/distributor/ring", distributor.DistributorRing)
 	}
 
+	if t.cfg.Distributor.FailureInjection.Enabled {
+		t.Server.HTTPRouter().Path("/distributor/failure_injection").Handler(http.HandlerFunc(t.distributor.FailureInjectionHandler))
+	}
+
+	if t.cfg.Distributor.StreamingPush.Enabled {
+		tempopb.RegisterStreamingPusherServer(t.Server.GRPC(), t.distributor.StreamingPusher())
+	}
+
 	return t.distributor, nil
 }
 
@@ -259,9 +273,18 @@ func (t *App) initIngester() (services.Service, error) {
 	tempopb.RegisterQuerierServer(t.Server.GRPC(), t.ingester)
 	t.Server.HTTPRouter().Path("/flush").Handler(http.HandlerFunc(t.ingester.FlushHandler))
 	t.Server.HTTPRouter().Path("/shutdown").Handler(http.HandlerFunc(t.ingester.ShutdownHandler))
+	t.Server.HTTPRouter().Path("/ingester/tenants").Methods(http.MethodGet).Handler(http.HandlerFunc(t.ingester.TenantsHandler))
 	return t.ingester, nil
 }
 
+// localIngester returns the ingester of this process or nil if it hasn't been initialized
+func (t *App) localIngester() querier.LocalIngester {
+	if t.ingester == nil {
+		return nil
+	}
+	return t.ingester
+}
+
 func (t *App) initGenerator() (services.Service, error) {
 	if t.cfg.Generator.Processor.LocalBlocks.FlushToStorage &&
 		t.store == nil {
@@ -314,6 +337,7 @@ func (t *App) initQuerier() (services.Service, error) {
 	}
 
 	t.cfg.Querier.AutocompleteFilteringEnabled = t.cfg.AutocompleteFilteringEnabled
+	t.cfg.Querier.TraceTombstonesEnabled = t.cfg.Frontend.TraceTombstonesEnabled
 
 	querier, err := querier.New(
 		t.cfg.Querier,
@@ -329,6 +353,12 @@ func (t *App) initQuerier() (services.Service, error) {
 	}
 	t.querier = querier
 
+	// in single binary mode the local ingester is called directly. it's looked up lazily as the ingester module may
+	// be initialized after the querier
+	if t.cfg.Target == SingleBinary {
+		t.querier.SetLocalIngester(t.localIngester)
+	}
+
 	middleware := middleware.Merge(
 		t.HTTPAuthMiddleware,
 	)
@@ -369,6 +399,17 @@ func (t *App) initQueryFrontend() (services.Service, error) {
 	}
 	t.frontend = v1
 
+	// the stored bytes of the tenants are read from the blocklist of the query-frontend
+	t.cfg.Frontend.TenantUsage = t.tenantUsage
+	t.tenantUsage.SetStoredBytes(func() map[string]uint64 {
+		stats := t.store.TenantStats()
+		stored := make(map[string]uint64, len(stats))
+		for _, s := range stats {
+			stored[s.TenantID] = s.Bytes
+		}
+		return stored
+	})
+
 	// create query frontend
 	queryFrontend, err := frontend.New(t.cfg.Frontend, cortexTripper, t.Overrides, t.store, t.cacheProvider, t.cfg.HTTPAPIPrefix, log.Logger, prometheus.DefaultRegisterer)
 	if err != nil {
@@ -381,9 +422,14 @@ func (t *App) initQueryFrontend() (services.Service, error) {
 	// this GRPC service to be available on the HTTP server.
 	tempopb.RegisterStreamingQuerierServer(t.Server.GRPC(), queryFrontend)
 
+	compressionMiddleware, err := httpCompressionMiddleware(t.cfg.Frontend.ResponseCompression)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create response compression middleware: %w", err)
+	}
+
 	httpAPIMiddleware := []middleware.Interface{
 		t.HTTPAuthMiddleware,
-		httpGzipMiddleware(),
+		compressionMiddleware,
 	}
 
 	// use the api timeout for http requests if set. note that this is set in initServer() for
@@ -398,6 +444,27 @@ func (t *App) initQueryFrontend() (services.Service, error) {
 	// http trace by id endpoint
 	t.Server.HTTPRouter().Handle(addHTTPAPIPrefix(&t.cfg, api.PathTraces), base.Wrap(queryFrontend.TraceByIDHandler))
 
+	// http trace annotations endpoint
+	if t.cfg.Frontend.TraceAnnotationsEnabled {
+		t.Server.HTTPRouter().Handle(addHTTPAPIPrefix(&t.cfg, api.PathTraceAnnotations), base.Wrap(frontend.NewAnnotationsHandler(t.store, log.Logger)))
+	}
+
+	// http trace tombstones endpoint
+	if t.cfg.Frontend.TraceTombstonesEnabled {
+		t.Server.HTTPRouter().Handle(addHTTPAPIPrefix(&t.cfg, api.PathTraceTombstones), base.Wrap(frontend.NewTombstonesHandler(t.store, log.Logger)))
+	}
+
+	// http block inspection endpoints
+	if t.cfg.Frontend.BlockInspectionEnabled {
+		blocksHandler := base.Wrap(frontend.NewBlocksHandler(t.store, log.Logger))
+		t.Server.HTTPRouter().Handle(addHTTPAPIPrefix(&t.cfg, api.PathBlocks), blocksHandler)
+		t.Server.HTTPRouter().Handle(addHTTPAPIPrefix(&t.cfg, api.PathBlock), blocksHandler)
+		t.Server.HTTPRouter().Handle(addHTTPAPIPrefix(&t.cfg, api.PathBlockQuarantine), base.Wrap(frontend.NewBlockQuarantineHandler(t.store, log.Logger)))
+	}
+
+	// admin endpoint listing the tenants in the blocklist
+	t.Server.HTTPRouter().Handle("/status/tenants", frontend.NewTenantsHandler(t.store))
+
 	// http search endpoints
 	t.Server.HTTPRouter().Handle(addHTTPAPIPrefix(&t.cfg, api.PathSearch), base.Wrap(queryFrontend.SearchHandler))
 	t.Server.HTTPRouter().Handle(addHTTPAPIPrefix(&t.cfg, api.PathSearchTags), base.Wrap(queryFrontend.SearchTagsHandler))
@@ -439,6 +506,12 @@ func (t *App) initCompactor() (services.Service, error) {
 		t.Server.HTTPRouter().Handle("/compactor/ring", t.compactor.Ring)
 	}
 
+	t.Server.HTTPRouter().Handle("/compactor/status", http.HandlerFunc(t.compactor.StatusHandler))
+
+	if t.cfg.Compactor.TenantDeletionEnabled {
+		t.Server.HTTPRouter().Handle("/compactor/tenant_deletion", http.HandlerFunc(t.compactor.TenantDeletionHandler))
+	}
+
 	return t.compactor, nil
 }
 
@@ -468,6 +541,9 @@ func (t *App) initMemberlistKV() (services.Service, error) {
 	t.cfg.MemberlistKV.Codecs = []codec.Codec{
 		ring.GetCodec(),
 		usagestats.JSONCodec,
+		distributor.IngestionRatesCodec,
+		compactor.CompactorLeaderCodec,
+		tempo_storage.BlocklistUpdatesCodec,
 	}
 
 	dnsProviderReg := prometheus.WrapRegistererWithPrefix(
@@ -485,6 +561,7 @@ func (t *App) initMemberlistKV() (services.Service, error) {
 	t.cfg.Generator.Ring.KVStore.MemberlistKV = t.MemberlistKV.GetMemberlistKV
 	t.cfg.Distributor.DistributorRing.KVStore.MemberlistKV = t.MemberlistKV.GetMemberlistKV
 	t.cfg.Compactor.ShardingRing.KVStore.MemberlistKV = t.MemberlistKV.GetMemberlistKV
+	t.cfg.StorageConfig.BlocklistUpdates.KVStore.MemberlistKV = t.MemberlistKV.GetMemberlistKV
 
 	t.Server.HTTPRouter().Handle("/memberlist", t.MemberlistKV)
 
@@ -516,6 +593,8 @@ func (t *App) initUsageReport() (services.Service, error) {
 		reader, writer, _, err = s3.New(t.cfg.StorageConfig.Trace.S3)
 	case backend.Azure:
 		reader, writer, _, err = azure.New(t.cfg.StorageConfig.Trace.Azure)
+	case backend.Swift:
+		reader, writer, _, err = swift.New(t.cfg.StorageConfig.Trace.Swift)
 	default:
 		err = fmt.Errorf("unknown backend %s", t.cfg.StorageConfig.Trace.Backend)
 	}
@@ -533,6 +612,22 @@ func (t *App) initUsageReport() (services.Service, error) {
 	return ur, nil
 }
 
+func (t *App) initTenantUsage() (services.Service, error) {
+	if !t.cfg.TenantUsage.Enabled {
+		return nil, nil
+	}
+
+	tracker, err := tenantusage.NewTracker(t.cfg.TenantUsage)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create tenant usage tracker: %w", err)
+	}
+	prometheus.MustRegister(tracker)
+	t.tenantUsage = tracker
+
+	t.Server.HTTPRouter().Path("/status/usage").Methods(http.MethodGet).HandlerFunc(tracker.Handler)
+	return nil, nil
+}
+
 func (t *App) initCacheProvider() (services.Service, error) {
 	c, err := cache.NewProvider(&t.cfg.CacheProvider, util_log.Logger)
 	if err != nil {
@@ -557,6 +652,7 @@ func (t *App) setupModuleManager() error {
 	mm.RegisterModule(Overrides, t.initOverrides, modules.UserInvisibleModule)
 	mm.RegisterModule(OverridesAPI, t.initOverridesAPI)
 	mm.RegisterModule(UsageReport, t.initUsageReport)
+	mm.RegisterModule(TenantUsage, t.initTenantUsage, modules.UserInvisibleModule)
 	mm.RegisterModule(CacheProvider, t.initCacheProvider, modules.UserInvisibleModule)
 	mm.RegisterModule(IngesterRing, t.initIngesterRing, modules.UserInvisibleModule)
 	mm.RegisterModule(MetricsGeneratorRing, t.initGeneratorRing, modules.UserInvisibleModule)
@@ -577,12 +673,13 @@ func (t *App) setupModuleManager() error {
 	deps := map[string][]string{
 		// InternalServer: nil,
 		// CacheProvider:  nil,
-		Store:                 {CacheProvider},
+		Store:                 {CacheProvider, MemberlistKV},
 		Server:                {InternalServer},
 		Overrides:             {Server},
 		OverridesAPI:          {Server, Overrides},
 		MemberlistKV:          {Server},
 		UsageReport:           {MemberlistKV},
+		TenantUsage:           {Server},
 		IngesterRing:          {Server, MemberlistKV},
 		SecondaryIngesterRing: {Server, MemberlistKV},
 		MetricsGeneratorRing:  {Server, MemberlistKV},
@@ -590,8 +687,8 @@ func (t *App) setupModuleManager() error {
 		Common: {UsageReport, Server, Overrides},
 
 		// individual targets
-		QueryFrontend:    {Common, Store, OverridesAPI},
-		Distributor:      {Common, IngesterRing, MetricsGeneratorRing},
+		QueryFrontend:    {Common, Store, OverridesAPI, TenantUsage},
+		Distributor:      {Common, IngesterRing, MetricsGeneratorRing, TenantUsage},
 		Ingester:         {Common, Store, MemberlistKV},
 		MetricsGenerator: {Common, OptionalStore, MemberlistKV},
 		Querier:          {Common, Store, IngesterRing, MetricsGeneratorRing, SecondaryIngesterRing},
@@ -1,6 +1,7 @@
 package app
 
 import (
+	"errors"
 	"fmt"
 	"time"
 
@@ -33,6 +34,12 @@ func (r *runtimeConfigValidator) Validate(config *overrides.Overrides) error {
 		}
 	}
 
+	switch config.Compaction.StorageQuotaAction {
+	case "", overrides.StorageQuotaActionDelete, overrides.StorageQuotaActionReject:
+	default:
+		return fmt.Errorf("compaction.storage_quota_action \"%s\" is not valid, valid values: %s, %s", config.Compaction.StorageQuotaAction, overrides.StorageQuotaActionDelete, overrides.StorageQuotaActionReject)
+	}
+
 	return nil
 }
 
@@ -88,5 +95,25 @@ func (v *overridesValidator) Validate(limits *client.Limits) error {
 		}
 	}
 
+	bounds := v.cfg.Overrides.UserConfigurableOverridesConfig.API.Bounds
+
+	if blockRetention, ok := limits.GetCompaction().GetBlockRetention(); ok {
+		if bounds.BlockRetention.Max == 0 {
+			return errors.New("compaction.block_retention can't be set, contact your system administrator")
+		}
+		if blockRetention < bounds.BlockRetention.Min || blockRetention > bounds.BlockRetention.Max {
+			return fmt.Errorf("compaction.block_retention \"%s\" is outside acceptable range of %s to %s", blockRetention, bounds.BlockRetention.Min, bounds.BlockRetention.Max)
+		}
+	}
+
+	if maxBytesPerTrace, ok := limits.GetGlobal().GetMaxBytesPerTrace(); ok {
+		if bounds.MaxBytesPerTrace.Max == 0 {
+			return errors.New("global.max_bytes_per_trace can't be set, contact your system administrator")
+		}
+		if maxBytesPerTrace < bounds.MaxBytesPerTrace.Min || maxBytesPerTrace > bounds.MaxBytesPerTrace.Max {
+			return fmt.Errorf("global.max_bytes_per_trace \"%d\" is outside acceptable range of %d to %d", maxBytesPerTrace, bounds.MaxBytesPerTrace.Min, bounds.MaxBytesPerTrace.Max)
+		}
+	}
+
 	return nil
 }
@@ -51,6 +51,15 @@ func Test_runtimeOverridesValidator(t *testing.T) {
 			},
 			overrides: overrides.Overrides{Ingestion: overrides.IngestionOverrides{TenantShardSize: 3}},
 		},
+		{
+			name:      "compaction.storage_quota_action reject",
+			overrides: overrides.Overrides{Compaction: overrides.CompactionOverrides{StorageQuotaAction: overrides.StorageQuotaActionReject}},
+		},
+		{
+			name:      "compaction.storage_quota_action invalid",
+			overrides: overrides.Overrides{Compaction: overrides.CompactionOverrides{StorageQuotaAction: "archive"}},
+			expErr:    "compaction.storage_quota_action \"archive\" is not valid, valid values: delete, reject",
+		},
 	}
 
 	for _, tc := range testCases {
@@ -179,6 +188,52 @@ func Test_overridesValidator(t *testing.T) {
 			},
 			expErr: "metrics_generator.collection_interval \"10m0s\" is outside acceptable range of 15s to 5m",
 		},
+		{
+			name: "compaction.block_retention without bounds",
+			cfg:  Config{},
+			limits: client.Limits{
+				Compaction: client.LimitsCompaction{BlockRetention: &client.Duration{Duration: 48 * time.Hour}},
+			},
+			expErr: "compaction.block_retention can't be set, contact your system administrator",
+		},
+		{
+			name: "compaction.block_retention valid",
+			cfg:  boundsConfig(),
+			limits: client.Limits{
+				Compaction: client.LimitsCompaction{BlockRetention: &client.Duration{Duration: 48 * time.Hour}},
+			},
+		},
+		{
+			name: "compaction.block_retention outside bounds",
+			cfg:  boundsConfig(),
+			limits: client.Limits{
+				Compaction: client.LimitsCompaction{BlockRetention: &client.Duration{Duration: time.Hour}},
+			},
+			expErr: "compaction.block_retention \"1h0m0s\" is outside acceptable range of 24h0m0s to 720h0m0s",
+		},
+		{
+			name: "global.max_bytes_per_trace without bounds",
+			cfg:  Config{},
+			limits: client.Limits{
+				Global: client.LimitsGlobal{MaxBytesPerTrace: intPtr(10_000)},
+			},
+			expErr: "global.max_bytes_per_trace can't be set, contact your system administrator",
+		},
+		{
+			name: "global.max_bytes_per_trace valid",
+			cfg:  boundsConfig(),
+			limits: client.Limits{
+				Global: client.LimitsGlobal{MaxBytesPerTrace: intPtr(10_000)},
+			},
+		},
+		{
+			name: "global.max_bytes_per_trace outside bounds",
+			cfg:  boundsConfig(),
+			limits: client.Limits{
+				Global: client.LimitsGlobal{MaxBytesPerTrace: intPtr(100_000)},
+			},
+			expErr: "global.max_bytes_per_trace \"100000\" is outside acceptable range of 1000 to 50000",
+		},
 	}
 
 	for _, tc := range testCases {
@@ -194,3 +249,16 @@ func Test_overridesValidator(t *testing.T) {
 		})
 	}
 }
+
+func boundsConfig() Config {
+	var cfg Config
+	cfg.Overrides.UserConfigurableOverridesConfig.API.Bounds = overrides.UserConfigurableOverridesBounds{
+		BlockRetention:   overrides.DurationBounds{Min: 24 * time.Hour, Max: 30 * 24 * time.Hour},
+		MaxBytesPerTrace: overrides.IntBounds{Min: 1000, Max: 50_000},
+	}
+	return cfg
+}
+
+func intPtr(i int) *int {
+	return &i
+}
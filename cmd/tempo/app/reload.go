@@ -0,0 +1,165 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"reflect"
+	"sort"
+	"syscall"
+	"time"
+
+	"github.com/go-kit/log/level"
+
+	"github.com/grafana/tempo/modules/overrides"
+	"github.com/grafana/tempo/pkg/util"
+	util_log "github.com/grafana/tempo/pkg/util/log"
+)
+
+// ConfigLoader loads the config the same way it was loaded on startup
+type ConfigLoader func() (*Config, error)
+
+// SetConfigLoader enables reloading the config with SIGHUP or the reload endpoint
+func (t *App) SetConfigLoader(loader ConfigLoader) {
+	t.configLoader = loader
+}
+
+// ReloadConfig loads the config and applies the settings that can be changed at runtime:
+//   - server.log_level
+//   - overrides.per_tenant_override_config and overrides.per_tenant_override_period
+//   - storage.trace.blocklist_poll
+//
+// Changes to any other settings are logged and require a restart.
+func (t *App) ReloadConfig() error {
+	t.reloadMtx.Lock()
+	defer t.reloadMtx.Unlock()
+
+	if t.configLoader == nil {
+		return errors.New("config reload is not supported")
+	}
+
+	newCfg, err := t.configLoader()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	sections, err := restartRequiredSections(t.loadedCfg, newCfg)
+	if err != nil {
+		return fmt.Errorf("failed to compare config: %w", err)
+	}
+
+	// validate all reloadable settings before applying any of them
+	if newCfg.StorageConfig.Trace.BlocklistPoll < 0 {
+		return fmt.Errorf("invalid blocklist_poll %s", newCfg.StorageConfig.Trace.BlocklistPoll)
+	}
+
+	if newCfg.Overrides.PerTenantOverrideConfig != t.loadedCfg.Overrides.PerTenantOverrideConfig ||
+		newCfg.Overrides.PerTenantOverridePeriod != t.loadedCfg.Overrides.PerTenantOverridePeriod {
+		if t.Overrides != nil {
+			reloader, ok := t.Overrides.(overrides.Reloader)
+			if !ok {
+				return errors.New("overrides do not support reloading")
+			}
+			err := reloader.ReloadPerTenantOverrides(newCfg.Overrides.PerTenantOverrideConfig, time.Duration(newCfg.Overrides.PerTenantOverridePeriod))
+			if err != nil {
+				return fmt.Errorf("failed to reload overrides: %w", err)
+			}
+		}
+		t.cfg.Overrides.PerTenantOverrideConfig = newCfg.Overrides.PerTenantOverrideConfig
+		t.cfg.Overrides.PerTenantOverridePeriod = newCfg.Overrides.PerTenantOverridePeriod
+	}
+
+	if newCfg.Server.LogLevel.String() != t.loadedCfg.Server.LogLevel.String() {
+		util_log.SetLevel(newCfg.Server.LogLevel.String())
+		t.cfg.Server.LogLevel = newCfg.Server.LogLevel
+	}
+
+	if newCfg.StorageConfig.Trace.BlocklistPoll != t.loadedCfg.StorageConfig.Trace.BlocklistPoll {
+		if t.store != nil {
+			t.store.SetBlocklistPoll(newCfg.StorageConfig.Trace.BlocklistPoll)
+		}
+		t.cfg.StorageConfig.Trace.BlocklistPoll = newCfg.StorageConfig.Trace.BlocklistPoll
+	}
+
+	if len(sections) > 0 {
+		level.Warn(util_log.Logger).Log("msg", "config changes in these sections require a restart and were not applied", "sections", fmt.Sprintf("%v", sections))
+	}
+
+	t.loadedCfg = newCfg
+	level.Info(util_log.Logger).Log("msg", "config reloaded")
+
+	return nil
+}
+
+// restartRequiredSections returns the top level sections that differ between the two configs. The settings applied
+// by ReloadConfig are ignored.
+func restartRequiredSections(current, reloaded *Config) ([]string, error) {
+	cmp := *reloaded
+	cmp.Server.LogLevel = current.Server.LogLevel
+	cmp.Overrides.PerTenantOverrideConfig = current.Overrides.PerTenantOverrideConfig
+	cmp.Overrides.PerTenantOverridePeriod = current.Overrides.PerTenantOverridePeriod
+	cmp.StorageConfig.Trace.BlocklistPoll = current.StorageConfig.Trace.BlocklistPoll
+
+	currentYaml, err := util.YAMLMarshalUnmarshal(current)
+	if err != nil {
+		return nil, err
+	}
+	reloadedYaml, err := util.YAMLMarshalUnmarshal(&cmp)
+	if err != nil {
+		return nil, err
+	}
+
+	changed := map[string]struct{}{}
+	for k, v := range currentYaml {
+		if !reflect.DeepEqual(v, reloadedYaml[k]) {
+			changed[fmt.Sprint(k)] = struct{}{}
+		}
+	}
+	for k, v := range reloadedYaml {
+		if !reflect.DeepEqual(v, currentYaml[k]) {
+			changed[fmt.Sprint(k)] = struct{}{}
+		}
+	}
+
+	sections := make([]string, 0, len(changed))
+	for k := range changed {
+		sections = append(sections, k)
+	}
+	sort.Strings(sections)
+
+	return sections, nil
+}
+
+// reloadHandler reloads the config on POST
+func (t *App) reloadHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		if err := t.ReloadConfig(); err != nil {
+			level.Error(util_log.Logger).Log("msg", "failed to reload config", "err", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// reloadOnSignal reloads the config every time the process receives SIGHUP until stop is closed
+func (t *App) reloadOnSignal(stop <-chan struct{}) {
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGHUP)
+	defer signal.Stop(sigs)
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-sigs:
+			level.Info(util_log.Logger).Log("msg", "received SIGHUP, reloading config")
+			if err := t.ReloadConfig(); err != nil {
+				level.Error(util_log.Logger).Log("msg", "failed to reload config", "err", err)
+			}
+		}
+	}
+}
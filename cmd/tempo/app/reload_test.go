@@ -0,0 +1,103 @@
+package app
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/modules/overrides"
+)
+
+type mockReloadableOverrides struct {
+	overrides.Service
+
+	path   string
+	period time.Duration
+	err    error
+}
+
+func (m *mockReloadableOverrides) ReloadPerTenantOverrides(path string, period time.Duration) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.path = path
+	m.period = period
+	return nil
+}
+
+func TestReloadConfig(t *testing.T) {
+	cfg := newDefaultConfig()
+	o := &mockReloadableOverrides{}
+
+	app := &App{cfg: *cfg, loadedCfg: cfg, Overrides: o}
+	require.EqualError(t, app.ReloadConfig(), "config reload is not supported")
+
+	var next *Config
+	app.SetConfigLoader(func() (*Config, error) {
+		return next, nil
+	})
+
+	// reloadable settings are applied
+	next = newDefaultConfig()
+	require.NoError(t, next.Server.LogLevel.Set("debug"))
+	next.Overrides.PerTenantOverrideConfig = "/conf/overrides.yaml"
+	next.Overrides.PerTenantOverridePeriod = model.Duration(time.Minute)
+	next.StorageConfig.Trace.BlocklistPoll = 10 * time.Second
+
+	require.NoError(t, app.ReloadConfig())
+	require.Equal(t, "debug", app.cfg.Server.LogLevel.String())
+	require.Equal(t, "/conf/overrides.yaml", app.cfg.Overrides.PerTenantOverrideConfig)
+	require.Equal(t, 10*time.Second, app.cfg.StorageConfig.Trace.BlocklistPoll)
+	require.Equal(t, "/conf/overrides.yaml", o.path)
+	require.Equal(t, time.Minute, o.period)
+
+	// other settings are not applied
+	next = newDefaultConfig()
+	next.Server.LogLevel = app.cfg.Server.LogLevel
+	next.Overrides.PerTenantOverrideConfig = "/conf/overrides.yaml"
+	next.Overrides.PerTenantOverridePeriod = model.Duration(time.Minute)
+	next.StorageConfig.Trace.BlocklistPoll = 10 * time.Second
+	next.Querier.MaxConcurrentQueries = 1234
+
+	require.NoError(t, app.ReloadConfig())
+	require.NotEqual(t, 1234, app.cfg.Querier.MaxConcurrentQueries)
+
+	// failing to reload the overrides leaves the config untouched
+	o.err = errors.New("invalid overrides")
+	next = newDefaultConfig()
+	next.Overrides.PerTenantOverrideConfig = "/conf/invalid.yaml"
+
+	require.EqualError(t, app.ReloadConfig(), "failed to reload overrides: invalid overrides")
+	require.Equal(t, "/conf/overrides.yaml", app.cfg.Overrides.PerTenantOverrideConfig)
+	require.Equal(t, "debug", app.cfg.Server.LogLevel.String())
+
+	// failing to load the config leaves the config untouched
+	app.SetConfigLoader(func() (*Config, error) {
+		return nil, errors.New("no such file")
+	})
+	require.EqualError(t, app.ReloadConfig(), "failed to load config: no such file")
+}
+
+func TestRestartRequiredSections(t *testing.T) {
+	current := newDefaultConfig()
+
+	reloaded := newDefaultConfig()
+	require.NoError(t, reloaded.Server.LogLevel.Set("debug"))
+	reloaded.Overrides.PerTenantOverrideConfig = "/conf/overrides.yaml"
+	reloaded.StorageConfig.Trace.BlocklistPoll = 10 * time.Second
+
+	sections, err := restartRequiredSections(current, reloaded)
+	require.NoError(t, err)
+	require.Empty(t, sections)
+
+	reloaded.Querier.MaxConcurrentQueries = 1234
+	reloaded.Server.HTTPListenPort = 1234
+	reloaded.Overrides.Defaults.Ingestion.BurstSizeBytes = 1234
+
+	sections, err = restartRequiredSections(current, reloaded)
+	require.NoError(t, err)
+	require.Equal(t, []string{"overrides", "querier", "server"}, sections)
+}
@@ -55,17 +55,28 @@ func init() {
 	encoding.RegisterCodec(gogocodec.NewCodec())
 }
 
+type mainFlags struct {
+	printVersion         bool
+	ballastMBs           int
+	mutexProfileFraction int
+}
+
+func (f *mainFlags) register(fs *flag.FlagSet) {
+	fs.BoolVar(&f.printVersion, "version", false, "Print this builds version information")
+	fs.IntVar(&f.ballastMBs, "mem-ballast-size-mbs", 0, "Size of memory ballast to allocate in MBs.")
+	fs.IntVar(&f.mutexProfileFraction, "mutex-profile-fraction", 0, "Enable mutex profiling.")
+}
+
 func main() {
-	printVersion := flag.Bool("version", false, "Print this builds version information")
-	ballastMBs := flag.Int("mem-ballast-size-mbs", 0, "Size of memory ballast to allocate in MBs.")
-	mutexProfileFraction := flag.Int("mutex-profile-fraction", 0, "Enable mutex profiling.")
+	flags := &mainFlags{}
+	flags.register(flag.CommandLine)
 
-	config, configVerify, err := loadConfig()
+	config, configVerify, err := loadConfig(flag.CommandLine, os.Args[1:])
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "failed parsing config: %v\n", err)
 		os.Exit(1)
 	}
-	if *printVersion {
+	if flags.printVersion {
 		fmt.Println(version.Print(appName))
 		os.Exit(0)
 	}
@@ -101,12 +112,12 @@ func main() {
 	}
 	defer shutdownTracer()
 
-	if *mutexProfileFraction > 0 {
-		runtime.SetMutexProfileFraction(*mutexProfileFraction)
+	if flags.mutexProfileFraction > 0 {
+		runtime.SetMutexProfileFraction(flags.mutexProfileFraction)
 	}
 
 	// Allocate a block of memory to alter GC behaviour. See https://github.com/golang/go/issues/23044
-	ballast := make([]byte, *ballastMBs*1024*1024)
+	ballast := make([]byte, flags.ballastMBs*1024*1024)
 
 	// Start Tempo
 	t, err := app.New(*config)
@@ -115,6 +126,16 @@ func main() {
 		os.Exit(1)
 	}
 
+	// reload the config from the same file and flags tempo was started with
+	t.SetConfigLoader(func() (*app.Config, error) {
+		fs := flag.NewFlagSet("", flag.ContinueOnError)
+		fs.SetOutput(io.Discard)
+		(&mainFlags{}).register(fs)
+
+		config, _, err := loadConfig(fs, os.Args[1:])
+		return config, err
+	})
+
 	level.Info(log.Logger).Log("msg", "Starting Tempo", "version", version.Info())
 
 	if err := t.Run(); err != nil {
@@ -140,7 +161,8 @@ func configIsValid(config *app.Config) bool {
 	return true
 }
 
-func loadConfig() (*app.Config, bool, error) {
+// loadConfig registers the config flags on fs and loads the config from the config file and args
+func loadConfig(fs *flag.FlagSet, args []string) (*app.Config, bool, error) {
 	const (
 		configFileOption      = "config.file"
 		configExpandEnvOption = "config.expand-env"
@@ -153,27 +175,25 @@ func loadConfig() (*app.Config, bool, error) {
 		configVerify    bool
 	)
 
-	args := os.Args[1:]
 	config := &app.Config{}
 
 	// first get the config file
-	fs := flag.NewFlagSet("", flag.ContinueOnError)
-	fs.SetOutput(io.Discard)
+	configFS := flag.NewFlagSet("", flag.ContinueOnError)
+	configFS.SetOutput(io.Discard)
 
-	fs.StringVar(&configFile, configFileOption, "", "")
-	fs.BoolVar(&configExpandEnv, configExpandEnvOption, false, "")
-	fs.BoolVar(&configVerify, configVerifyOption, false, "")
+	configFS.StringVar(&configFile, configFileOption, "", "")
+	configFS.BoolVar(&configExpandEnv, configExpandEnvOption, false, "")
+	configFS.BoolVar(&configVerify, configVerifyOption, false, "")
 
 	// Try to find -config.file & -config.expand-env flags. As Parsing stops on the first error, eg. unknown flag,
 	// we simply try remaining parameters until we find config flag, or there are no params left.
 	// (ContinueOnError just means that flag.Parse doesn't call panic or os.Exit, but it returns error, which we ignore)
-	for len(args) > 0 {
-		_ = fs.Parse(args)
-		args = args[1:]
+	for remaining := args; len(remaining) > 0; remaining = remaining[1:] {
+		_ = configFS.Parse(remaining)
 	}
 
 	// load config defaults and register flags
-	config.RegisterFlagsAndApplyDefaults("", flag.CommandLine)
+	config.RegisterFlagsAndApplyDefaults("", fs)
 
 	// overlay with config file if provided
 	if configFile != "" {
@@ -201,10 +221,12 @@ func loadConfig() (*app.Config, bool, error) {
 	config.Overrides.ExpandEnv = configExpandEnv
 
 	// overlay with cli
-	flagext.IgnoredFlag(flag.CommandLine, configFileOption, "Configuration file to load")
-	flagext.IgnoredFlag(flag.CommandLine, configExpandEnvOption, "Whether to expand environment variables in config file")
-	flagext.IgnoredFlag(flag.CommandLine, configVerifyOption, "Verify configuration and exit")
-	flag.Parse()
+	flagext.IgnoredFlag(fs, configFileOption, "Configuration file to load")
+	flagext.IgnoredFlag(fs, configExpandEnvOption, "Whether to expand environment variables in config file")
+	flagext.IgnoredFlag(fs, configVerifyOption, "Verify configuration and exit")
+	if err := fs.Parse(args); err != nil {
+		return nil, false, err
+	}
 
 	// after loading config, let's force some values if in single binary mode
 	// if we're in single binary mode we're going to force some settings b/c nothing else makes sense
@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/grafana/dskit/services"
+	"github.com/grafana/tempo/modules/cache/inmemory"
 	"github.com/grafana/tempo/modules/cache/memcached"
 	"github.com/grafana/tempo/modules/cache/redis"
 	"github.com/grafana/tempo/pkg/cache"
@@ -17,6 +18,7 @@ import (
 var (
 	statMemcached = usagestats.NewInt("cache_memcached")
 	statRedis     = usagestats.NewInt("cache_redis")
+	statInMemory  = usagestats.NewInt("cache_inmemory")
 )
 
 type provider struct {
@@ -38,6 +40,7 @@ func NewProvider(cfg *Config, logger log.Logger) (cache.Provider, error) {
 
 	statMemcached.Set(0)
 	statRedis.Set(0)
+	statInMemory.Set(0)
 
 	for _, cacheCfg := range cfg.Caches {
 		var c cache.Cache
@@ -56,6 +59,13 @@ func NewProvider(cfg *Config, logger log.Logger) (cache.Provider, error) {
 			c = redis.NewClient(cacheCfg.RedisConfig, cfg.Background, cacheCfg.Name(), logger)
 		}
 
+		if cacheCfg.InMemoryConfig != nil {
+			level.Info(logger).Log("msg", "configuring in-memory cache", "roles", cacheCfg.Name())
+
+			statInMemory.Add(1)
+			c = inmemory.NewClient(cacheCfg.InMemoryConfig, cacheCfg.Name(), logger)
+		}
+
 		// add this cache for all claimed roles
 		for _, role := range cacheCfg.Role {
 			p.caches[role] = c
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/grafana/tempo/modules/cache/inmemory"
 	"github.com/grafana/tempo/modules/cache/memcached"
 	"github.com/grafana/tempo/modules/cache/redis"
 	"github.com/grafana/tempo/pkg/cache"
@@ -20,6 +21,7 @@ type CacheConfig struct { // nolint: revive
 	Role            []cache.Role      `yaml:"roles"`
 	MemcachedConfig *memcached.Config `yaml:"memcached"`
 	RedisConfig     *redis.Config     `yaml:"redis"`
+	InMemoryConfig  *inmemory.Config  `yaml:"inmemory"`
 }
 
 // Validate validates the config.
@@ -28,12 +30,19 @@ func (cfg *Config) Validate() error {
 	allRoles := allRoles()
 
 	for _, cacheCfg := range cfg.Caches {
-		if cacheCfg.MemcachedConfig != nil && cacheCfg.RedisConfig != nil {
-			return fmt.Errorf("cache config for role %s has both memcached and redis configs", cacheCfg.Role)
+		configured := 0
+		for _, set := range []bool{cacheCfg.MemcachedConfig != nil, cacheCfg.RedisConfig != nil, cacheCfg.InMemoryConfig != nil} {
+			if set {
+				configured++
+			}
+		}
+
+		if configured > 1 {
+			return fmt.Errorf("cache config for role %s has more than one of memcached, redis or inmemory configs", cacheCfg.Role)
 		}
 
-		if cacheCfg.MemcachedConfig == nil && cacheCfg.RedisConfig == nil {
-			return fmt.Errorf("cache config for role %s has neither memcached nor redis configs", cacheCfg.Role)
+		if configured == 0 {
+			return fmt.Errorf("cache config for role %s has none of memcached, redis or inmemory configs", cacheCfg.Role)
 		}
 
 		if len(cacheCfg.Role) == 0 {
@@ -84,6 +93,7 @@ func allRoles() map[cache.Role]struct{} {
 		cache.RoleParquetOffsetIdx,
 		cache.RoleTraceIDIdx,
 		cache.RoleFrontendSearch,
+		cache.RoleFrontendTraceByID,
 		cache.RoleParquetPage,
 	}
 
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"testing"
 
+	"github.com/grafana/tempo/modules/cache/inmemory"
 	"github.com/grafana/tempo/modules/cache/memcached"
 	"github.com/grafana/tempo/modules/cache/redis"
 	"github.com/grafana/tempo/pkg/cache"
@@ -32,6 +33,10 @@ func TestConfigValidation(t *testing.T) {
 						Role:        []cache.Role{cache.RoleParquetColumnIdx},
 						RedisConfig: &redis.Config{},
 					},
+					{
+						Role:           []cache.Role{cache.RoleFrontendTraceByID},
+						InMemoryConfig: &inmemory.Config{},
+					},
 				},
 			},
 		},
@@ -85,7 +90,20 @@ func TestConfigValidation(t *testing.T) {
 					},
 				},
 			},
-			expected: errors.New("cache config for role [bloom] has both memcached and redis configs"),
+			expected: errors.New("cache config for role [bloom] has more than one of memcached, redis or inmemory configs"),
+		},
+		{
+			name: "invalid - memcached and inmemory configged",
+			cfg: &Config{
+				Caches: []CacheConfig{
+					{
+						Role:            []cache.Role{cache.RoleFrontendTraceByID},
+						MemcachedConfig: &memcached.Config{},
+						InMemoryConfig:  &inmemory.Config{},
+					},
+				},
+			},
+			expected: errors.New("cache config for role [frontend-trace-by-id] has more than one of memcached, redis or inmemory configs"),
 		},
 		{
 			name: "invalid - no caches configged",
@@ -96,7 +114,7 @@ func TestConfigValidation(t *testing.T) {
 					},
 				},
 			},
-			expected: errors.New("cache config for role [bloom] has neither memcached nor redis configs"),
+			expected: errors.New("cache config for role [bloom] has none of memcached, redis or inmemory configs"),
 		},
 		{
 			name: "invalid - non-existent role",
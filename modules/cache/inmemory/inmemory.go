@@ -0,0 +1,28 @@
+package inmemory
+
+import (
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/grafana/tempo/pkg/cache"
+)
+
+type Config struct {
+	cache.InMemoryConfig `yaml:",inline"`
+}
+
+func NewClient(cfg *Config, name string, logger log.Logger) cache.Cache {
+	if cfg.MaxItems <= 0 {
+		cfg.MaxItems = 1000
+	}
+
+	c, err := cache.NewInMemory(cfg.InMemoryConfig, name, prometheus.DefaultRegisterer)
+	if err != nil {
+		// only returned for a non positive size which is defaulted above
+		level.Error(logger).Log("msg", "failed to create in-memory cache", "name", name, "err", err)
+		return nil
+	}
+
+	return c
+}
@@ -2,6 +2,7 @@ package compactor
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"hash/fnv"
 	"time"
@@ -46,6 +47,9 @@ type Compactor struct {
 	ringLifecycler *ring.BasicLifecycler
 	Ring           *ring.Ring
 
+	// leader election replaces sharding with a single active compactor and standbys
+	leader *leaderElection
+
 	subservices        *services.Manager
 	subservicesWatcher *services.FailureWatcher
 }
@@ -58,7 +62,26 @@ func New(cfg Config, store storage.Store, overrides overrides.Interface, reg pro
 		overrides: overrides,
 	}
 
-	if c.isSharded() {
+	if cfg.LeaderElection.Enabled {
+		if err := cfg.LeaderElection.Validate(); err != nil {
+			return nil, err
+		}
+		if cfg.ShardingRing.KVStore.Store == "" {
+			return nil, errors.New("compactor leader election requires the compactor ring kvstore")
+		}
+
+		leaderStore, err := kv.NewClient(
+			cfg.ShardingRing.KVStore,
+			CompactorLeaderCodec,
+			kv.RegistererWithKVName(prometheus.WrapRegistererWithPrefix("tempo_", reg), compactorLeaderKey),
+			log.Logger,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		c.leader = newLeaderElection(cfg.LeaderElection, cfg.ShardingRing.InstanceID, leaderStore)
+	} else if c.isSharded() {
 		reg = prometheus.WrapRegistererWithPrefix("tempo_", reg)
 
 		lifecyclerStore, err := kv.NewClient(
@@ -110,7 +133,19 @@ func (c *Compactor) starting(ctx context.Context) (err error) {
 		}
 	}()
 
-	if c.isSharded() {
+	if c.leader != nil {
+		c.subservices, err = services.NewManager(c.leader)
+		if err != nil {
+			return fmt.Errorf("failed to create subservices: %w", err)
+		}
+		c.subservicesWatcher = services.NewFailureWatcher()
+		c.subservicesWatcher.WatchManager(c.subservices)
+
+		err = services.StartManagerAndAwaitHealthy(ctx, c.subservices)
+		if err != nil {
+			return fmt.Errorf("failed to start subservices: %w", err)
+		}
+	} else if c.isSharded() {
 		c.subservices, err = services.NewManager(c.ringLifecycler, c.Ring)
 		if err != nil {
 			return fmt.Errorf("failed to create subservices: %w", err)
@@ -188,6 +223,10 @@ func (c *Compactor) stopping(_ error) error {
 
 // Owns implements tempodb.CompactorSharder
 func (c *Compactor) Owns(hash string) bool {
+	if c.leader != nil {
+		return c.leader.isLeader()
+	}
+
 	if !c.isSharded() {
 		return true
 	}
@@ -262,6 +301,26 @@ func (c *Compactor) MaxCompactionRangeForTenant(tenantID string) time.Duration {
 	return c.overrides.MaxCompactionRange(tenantID)
 }
 
+func (c *Compactor) CompactionDisabledForTenant(tenantID string) bool {
+	return c.overrides.CompactionDisabled(tenantID)
+}
+
+func (c *Compactor) CompactionPriorityForTenant(tenantID string) int {
+	return c.overrides.CompactionPriority(tenantID)
+}
+
+func (c *Compactor) MaxStoredBytesForTenant(tenantID string) int {
+	return c.overrides.MaxStoredBytes(tenantID)
+}
+
+func (c *Compactor) MaxStoredBlocksForTenant(tenantID string) int {
+	return c.overrides.MaxStoredBlocks(tenantID)
+}
+
+func (c *Compactor) StorageQuotaRejectsIngestForTenant(tenantID string) bool {
+	return c.overrides.StorageQuotaAction(tenantID) == overrides.StorageQuotaActionReject
+}
+
 func (c *Compactor) isSharded() bool {
 	return c.cfg.ShardingRing.KVStore.Store != ""
 }
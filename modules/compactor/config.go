@@ -18,18 +18,24 @@ type Config struct {
 	ShardingRing    RingConfig              `yaml:"ring,omitempty"`
 	Compactor       tempodb.CompactorConfig `yaml:"compaction"`
 	OverrideRingKey string                  `yaml:"override_ring_key"`
+	LeaderElection  LeaderElectionConfig    `yaml:"leader_election,omitempty"`
+
+	// registers the /compactor/tenant_deletion endpoint that marks tenants for deletion
+	TenantDeletionEnabled bool `yaml:"tenant_deletion_enabled"`
 }
 
 // RegisterFlagsAndApplyDefaults registers the flags.
 func (cfg *Config) RegisterFlagsAndApplyDefaults(prefix string, f *flag.FlagSet) {
 	cfg.Compactor = tempodb.CompactorConfig{
-		ChunkSizeBytes:          tempodb.DefaultChunkSizeBytes, // 5 MiB
-		FlushSizeBytes:          tempodb.DefaultFlushSizeBytes,
-		CompactedBlockRetention: time.Hour,
-		RetentionConcurrency:    tempodb.DefaultRetentionConcurrency,
-		IteratorBufferSize:      tempodb.DefaultIteratorBufferSize,
-		MaxTimePerTenant:        tempodb.DefaultMaxTimePerTenant,
-		CompactionCycle:         tempodb.DefaultCompactionCycle,
+		ChunkSizeBytes:           tempodb.DefaultChunkSizeBytes, // 5 MiB
+		FlushSizeBytes:           tempodb.DefaultFlushSizeBytes,
+		CompactedBlockRetention:  time.Hour,
+		RetentionConcurrency:     tempodb.DefaultRetentionConcurrency,
+		IteratorBufferSize:       tempodb.DefaultIteratorBufferSize,
+		MaxTimePerTenant:         tempodb.DefaultMaxTimePerTenant,
+		CompactionCycle:          tempodb.DefaultCompactionCycle,
+		CompactionConcurrency:    tempodb.DefaultCompactionConcurrency,
+		TenantSchedulingStrategy: tempodb.TenantSchedulingRoundRobin,
 	}
 
 	flagext.DefaultValues(&cfg.ShardingRing)
@@ -39,8 +45,16 @@ func (cfg *Config) RegisterFlagsAndApplyDefaults(prefix string, f *flag.FlagSet)
 	f.IntVar(&cfg.Compactor.MaxCompactionObjects, util.PrefixConfig(prefix, "compaction.max-objects-per-block"), 6000000, "Maximum number of traces in a compacted block.")
 	f.Uint64Var(&cfg.Compactor.MaxBlockBytes, util.PrefixConfig(prefix, "compaction.max-block-bytes"), 100*1024*1024*1024 /* 100GB */, "Maximum size of a compacted block.")
 	f.DurationVar(&cfg.Compactor.MaxCompactionRange, util.PrefixConfig(prefix, "compaction.compaction-window"), time.Hour, "Maximum time window across which to compact blocks.")
+	f.DurationVar(&cfg.Compactor.MaxTimeRange, util.PrefixConfig(prefix, "compaction.max-time-range"), 0, "Maximum time range of a compacted block. Blocks are not compacted together if the result exceeds it. 0 is unlimited.")
+	f.IntVar(&cfg.Compactor.OldWindowMaxInputBlocks, util.PrefixConfig(prefix, "compaction.old-window-max-input-blocks"), 0, "Maximum number of blocks compacted together outside the active window. 0 uses the default of the active window.")
+	f.DurationVar(&cfg.Compactor.OldWindowMaxTimeRange, util.PrefixConfig(prefix, "compaction.old-window-max-time-range"), 0, "Maximum time range of a compacted block outside the active window. 0 uses max_time_range.")
 	f.BoolVar(&cfg.Disabled, util.PrefixConfig(prefix, "disabled"), false, "Disable compaction.")
 	cfg.OverrideRingKey = compactorRingKey
+
+	cfg.LeaderElection = LeaderElectionConfig{
+		LeaseDuration: 15 * time.Second,
+		RenewPeriod:   5 * time.Second,
+	}
 }
 
 func toBasicLifecyclerConfig(cfg RingConfig, logger log.Logger) (ring.BasicLifecyclerConfig, error) {
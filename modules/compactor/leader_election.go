@@ -0,0 +1,241 @@
+package compactor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log/level"
+	"github.com/grafana/dskit/kv"
+	"github.com/grafana/dskit/kv/memberlist"
+	"github.com/grafana/dskit/services"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/grafana/tempo/pkg/util/log"
+)
+
+const compactorLeaderKey = "compactor-leader"
+
+var metricCompactorLeader = promauto.NewGauge(prometheus.GaugeOpts{
+	Namespace: "tempo",
+	Name:      "compactor_leader",
+	Help:      "1 if this compactor is the leader and 0 if it is on standby.",
+})
+
+type LeaderElectionConfig struct {
+	// only the elected leader compacts, applies retention and builds the tenant indexes. the other compactors are on
+	// standby and take over once the lease of the leader expires. the lease is stored in the ring kvstore
+	Enabled       bool          `yaml:"enabled"`
+	LeaseDuration time.Duration `yaml:"lease_duration"`
+	RenewPeriod   time.Duration `yaml:"renew_period"`
+}
+
+func (cfg *LeaderElectionConfig) Validate() error {
+	if !cfg.Enabled {
+		return nil
+	}
+	if cfg.RenewPeriod <= 0 {
+		return errors.New("compactor leader election renew period must be greater than 0")
+	}
+	if cfg.LeaseDuration <= cfg.RenewPeriod {
+		return errors.New("compactor leader election lease duration must be greater than the renew period")
+	}
+	return nil
+}
+
+// CompactorLeader is the lease stored in the kvstore. The leader renews it every renew period. Any compactor
+// can acquire it once it is released or hasn't been renewed for the lease duration.
+type CompactorLeader struct {
+	ID        string    `json:"id"`
+	RenewedAt time.Time `json:"renewed_at"`
+	Released  bool      `json:"released,omitempty"`
+}
+
+var _ memberlist.Mergeable = (*CompactorLeader)(nil)
+
+// Merge implements the memberlist.Mergeable interface. The most recently renewed lease wins.
+func (l *CompactorLeader) Merge(mergeable memberlist.Mergeable, _ bool) (memberlist.Mergeable, error) {
+	if mergeable == nil {
+		return nil, nil
+	}
+	other, ok := mergeable.(*CompactorLeader)
+	if !ok {
+		return nil, fmt.Errorf("expected *compactor.CompactorLeader, got %T", mergeable)
+	}
+	if other == nil || !other.RenewedAt.After(l.RenewedAt) {
+		return nil, nil
+	}
+
+	*l = *other
+	return other.Clone(), nil
+}
+
+// MergeContent implements the memberlist.Mergeable interface.
+func (l *CompactorLeader) MergeContent() []string {
+	return []string{l.ID}
+}
+
+// RemoveTombstones implements the memberlist.Mergeable interface. The lease has no tombstones.
+func (l *CompactorLeader) RemoveTombstones(time.Time) (total, removed int) {
+	return 0, 0
+}
+
+// Clone implements the memberlist.Mergeable interface.
+func (l *CompactorLeader) Clone() memberlist.Mergeable {
+	clone := *l
+	return &clone
+}
+
+var CompactorLeaderCodec = compactorLeaderCodec{}
+
+type compactorLeaderCodec struct{}
+
+func (compactorLeaderCodec) Decode(data []byte) (interface{}, error) {
+	var leader CompactorLeader
+	if err := jsoniter.ConfigFastest.Unmarshal(data, &leader); err != nil {
+		return nil, err
+	}
+	return &leader, nil
+}
+
+func (compactorLeaderCodec) Encode(obj interface{}) ([]byte, error) {
+	return jsoniter.ConfigFastest.Marshal(obj)
+}
+
+func (compactorLeaderCodec) CodecID() string { return "compactor.compactorLeaderCodec" }
+
+// leaderElection acquires and renews the compactor lease in the kvstore.
+type leaderElection struct {
+	services.Service
+
+	cfg        LeaderElectionConfig
+	instanceID string
+	kv         kv.Client
+	now        func() time.Time
+
+	mtx         sync.Mutex
+	leader      bool
+	lastRenewal time.Time
+}
+
+func newLeaderElection(cfg LeaderElectionConfig, instanceID string, kvClient kv.Client) *leaderElection {
+	e := &leaderElection{
+		cfg:        cfg,
+		instanceID: instanceID,
+		kv:         kvClient,
+		now:        time.Now,
+	}
+
+	e.Service = services.NewTimerService(cfg.RenewPeriod, e.starting, e.iteration, e.stopping)
+	return e
+}
+
+// isLeader returns true if this compactor holds the lease
+func (e *leaderElection) isLeader() bool {
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+
+	return e.leader
+}
+
+// starting makes a first attempt so a single compactor is the leader before the first compaction cycle
+func (e *leaderElection) starting(ctx context.Context) error {
+	return e.iteration(ctx)
+}
+
+func (e *leaderElection) iteration(ctx context.Context) error {
+	err := e.update(ctx)
+	if err != nil {
+		level.Warn(log.Logger).Log("msg", "failed to update compactor leader lease", "err", err)
+	}
+	// never fail the service. a leader that can't renew its lease steps down once it expires
+	return nil
+}
+
+// update acquires the lease if it is free or renews it if this compactor is the leader
+func (e *leaderElection) update(ctx context.Context) error {
+	now := e.now()
+
+	var (
+		acquired bool
+		holder   string
+	)
+	err := e.kv.CAS(ctx, compactorLeaderKey, func(in interface{}) (out interface{}, retry bool, err error) {
+		current, _ := in.(*CompactorLeader)
+		if current != nil && current.ID != e.instanceID && !current.Released && now.Sub(current.RenewedAt) <= e.cfg.LeaseDuration {
+			acquired = false
+			holder = current.ID
+			return nil, false, nil
+		}
+
+		acquired = true
+		holder = e.instanceID
+		return &CompactorLeader{ID: e.instanceID, RenewedAt: now}, true, nil
+	})
+
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+
+	if err != nil {
+		// keep the leadership until our own lease expires. a standby can't take over before that
+		if e.leader && now.Sub(e.lastRenewal) > e.cfg.LeaseDuration {
+			e.setLeader(false, "")
+		}
+		return err
+	}
+
+	if acquired {
+		e.lastRenewal = now
+	}
+	e.setLeader(acquired, holder)
+	return nil
+}
+
+// setLeader must be called with the mutex held
+func (e *leaderElection) setLeader(leader bool, holder string) {
+	if leader != e.leader {
+		if leader {
+			level.Info(log.Logger).Log("msg", "compactor acquired the leader lease", "instance", e.instanceID)
+		} else {
+			level.Info(log.Logger).Log("msg", "compactor is on standby", "instance", e.instanceID, "leader", holder)
+		}
+	}
+
+	e.leader = leader
+	if leader {
+		metricCompactorLeader.Set(1)
+	} else {
+		metricCompactorLeader.Set(0)
+	}
+}
+
+// stopping releases the lease so a standby takes over without waiting for it to expire
+func (e *leaderElection) stopping(_ error) error {
+	if !e.isLeader() {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), e.cfg.RenewPeriod)
+	defer cancel()
+
+	err := e.kv.CAS(ctx, compactorLeaderKey, func(in interface{}) (out interface{}, retry bool, err error) {
+		current, _ := in.(*CompactorLeader)
+		if current == nil || current.ID != e.instanceID {
+			return nil, false, nil
+		}
+		return &CompactorLeader{ID: e.instanceID, RenewedAt: e.now(), Released: true}, true, nil
+	})
+	if err != nil {
+		level.Warn(log.Logger).Log("msg", "failed to release compactor leader lease", "err", err)
+	}
+
+	e.mtx.Lock()
+	e.setLeader(false, "")
+	e.mtx.Unlock()
+
+	return nil
+}
@@ -0,0 +1,77 @@
+package compactor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/grafana/dskit/kv/consul"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLeaderElection(t *testing.T) {
+	kvClient, closer := consul.NewInMemoryClient(CompactorLeaderCodec, log.NewNopLogger(), nil)
+	t.Cleanup(func() { _ = closer.Close() })
+
+	cfg := LeaderElectionConfig{Enabled: true, LeaseDuration: 15 * time.Second, RenewPeriod: 5 * time.Second}
+	now := time.Now()
+
+	a := newLeaderElection(cfg, "a", kvClient)
+	b := newLeaderElection(cfg, "b", kvClient)
+	a.now = func() time.Time { return now }
+	b.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	require.NoError(t, a.update(ctx))
+	require.NoError(t, b.update(ctx))
+	require.True(t, a.isLeader())
+	require.False(t, b.isLeader())
+
+	// the leader renews its lease and the standby waits
+	now = now.Add(10 * time.Second)
+	require.NoError(t, a.update(ctx))
+	now = now.Add(10 * time.Second)
+	require.NoError(t, b.update(ctx))
+	require.True(t, a.isLeader())
+	require.False(t, b.isLeader())
+
+	// the leader stops renewing. the standby takes over once the lease expires
+	now = now.Add(10 * time.Second)
+	require.NoError(t, b.update(ctx))
+	require.True(t, b.isLeader())
+
+	require.NoError(t, a.update(ctx))
+	require.False(t, a.isLeader())
+
+	// a released lease is taken over immediately
+	require.NoError(t, b.stopping(nil))
+	require.False(t, b.isLeader())
+	require.NoError(t, a.update(ctx))
+	require.True(t, a.isLeader())
+}
+
+func TestCompactorLeaderMerge(t *testing.T) {
+	now := time.Now()
+	l := &CompactorLeader{ID: "a", RenewedAt: now}
+
+	// older leases are ignored
+	change, err := l.Merge(&CompactorLeader{ID: "b", RenewedAt: now.Add(-time.Second)}, false)
+	require.NoError(t, err)
+	require.Nil(t, change)
+	require.Equal(t, "a", l.ID)
+
+	change, err = l.Merge(&CompactorLeader{ID: "b", RenewedAt: now.Add(time.Second)}, false)
+	require.NoError(t, err)
+	require.Equal(t, []string{"b"}, change.MergeContent())
+	require.Equal(t, "b", l.ID)
+}
+
+func TestLeaderElectionConfigValidate(t *testing.T) {
+	require.NoError(t, (&LeaderElectionConfig{}).Validate())
+	require.NoError(t, (&LeaderElectionConfig{Enabled: true, LeaseDuration: 15 * time.Second, RenewPeriod: 5 * time.Second}).Validate())
+	require.EqualError(t, (&LeaderElectionConfig{Enabled: true, LeaseDuration: 5 * time.Second, RenewPeriod: 5 * time.Second}).Validate(),
+		"compactor leader election lease duration must be greater than the renew period")
+	require.EqualError(t, (&LeaderElectionConfig{Enabled: true, LeaseDuration: 5 * time.Second}).Validate(),
+		"compactor leader election renew period must be greater than 0")
+}
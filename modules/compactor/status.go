@@ -0,0 +1,34 @@
+package compactor
+
+import (
+	_ "embed" // Used to embed html templates
+	"html/template"
+	"net/http"
+	"time"
+
+	"github.com/grafana/tempo/pkg/util"
+	"github.com/grafana/tempo/tempodb"
+)
+
+//go:embed status.gohtml
+var statusPageHTML string
+var statusTemplate = template.Must(template.New("webpage").Parse(statusPageHTML))
+
+type statusPageContents struct {
+	Now     time.Time                         `json:"now"`
+	Jobs    []*tempodb.CompactionJob          `json:"jobs"`
+	Tenants []*tempodb.TenantCompactionStatus `json:"tenants"`
+}
+
+// StatusHandler shows the compactions queued or running on this compactor and the blocks left to compact per tenant
+func (c *Compactor) StatusHandler(w http.ResponseWriter, r *http.Request) {
+	status := c.store.CompactionStatus()
+
+	page := statusPageContents{
+		Now:     time.Now(),
+		Jobs:    status.Jobs,
+		Tenants: status.Tenants,
+	}
+
+	util.RenderHTTPResponse(w, page, statusTemplate, r)
+}
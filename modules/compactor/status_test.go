@@ -0,0 +1,58 @@
+package compactor
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/modules/storage"
+	"github.com/grafana/tempo/tempodb"
+)
+
+type mockStatusStore struct {
+	storage.Store
+	status *tempodb.CompactionStatus
+}
+
+func (m *mockStatusStore) CompactionStatus() *tempodb.CompactionStatus {
+	return m.status
+}
+
+func TestStatusHandler(t *testing.T) {
+	startedAt := time.Unix(2, 0).UTC()
+	c := &Compactor{store: &mockStatusStore{status: &tempodb.CompactionStatus{
+		Jobs: []*tempodb.CompactionJob{
+			{TenantID: "test", Level: 1, Blocks: 4, Bytes: 100, QueuedAt: time.Unix(1, 0).UTC(), StartedAt: &startedAt},
+			{TenantID: "test", Level: 0, Blocks: 2, Bytes: 10, QueuedAt: time.Unix(3, 0).UTC()},
+		},
+		Tenants: []*tempodb.TenantCompactionStatus{
+			{TenantID: "test", OutstandingBlocks: 6, OutstandingBlocksByLevel: map[uint8]int{0: 2, 1: 4}, MeasuredAt: time.Unix(4, 0).UTC()},
+		},
+	}}}
+
+	// html
+	w := httptest.NewRecorder()
+	c.StatusHandler(w, httptest.NewRequest(http.MethodGet, "/compactor/status", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Contains(t, w.Body.String(), "Compactor status")
+	require.Contains(t, w.Body.String(), "queued")
+
+	// json
+	req := httptest.NewRequest(http.MethodGet, "/compactor/status", nil)
+	req.Header.Set("Accept", "application/json")
+	w = httptest.NewRecorder()
+	c.StatusHandler(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	page := statusPageContents{}
+	require.NoError(t, jsoniter.Unmarshal(w.Body.Bytes(), &page))
+	require.Len(t, page.Jobs, 2)
+	require.Equal(t, &startedAt, page.Jobs[0].StartedAt)
+	require.Nil(t, page.Jobs[1].StartedAt)
+	require.Len(t, page.Tenants, 1)
+	require.Equal(t, map[uint8]int{0: 2, 1: 4}, page.Tenants[0].OutstandingBlocksByLevel)
+}
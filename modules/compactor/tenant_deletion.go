@@ -0,0 +1,58 @@
+package compactor
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/go-kit/log/level"
+	jsoniter "github.com/json-iterator/go"
+
+	"github.com/grafana/tempo/pkg/api"
+	"github.com/grafana/tempo/pkg/util/log"
+	"github.com/grafana/tempo/tempodb"
+	"github.com/grafana/tempo/tempodb/backend"
+)
+
+// TenantDeletionHandler marks the tenant in the tenant query parameter for deletion on POST and returns the progress of
+// its deletion on GET. The compactors delete the blocks and objects of the tenant during retention.
+func (c *Compactor) TenantDeletionHandler(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.URL.Query().Get("tenant")
+	if tenantID == "" {
+		http.Error(w, "tenant is required", http.StatusBadRequest)
+		return
+	}
+
+	var (
+		d   *tempodb.TenantDeletion
+		err error
+	)
+	switch r.Method {
+	case http.MethodGet:
+		d, err = c.store.TenantDeletion(r.Context(), tenantID)
+		if errors.Is(err, backend.ErrDoesNotExist) {
+			http.Error(w, fmt.Sprintf("tenant %s is not marked for deletion", tenantID), http.StatusNotFound)
+			return
+		}
+	case http.MethodPost:
+		d, err = c.store.MarkTenantForDeletion(r.Context(), tenantID)
+	default:
+		w.Header().Set("Allow", "GET, POST")
+		http.Error(w, fmt.Sprintf("method %s not allowed", r.Method), http.StatusMethodNotAllowed)
+		return
+	}
+	if err != nil {
+		level.Error(log.Logger).Log("msg", "failed to handle tenant deletion", "tenant", tenantID, "err", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	buff, err := jsoniter.Marshal(d)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set(api.HeaderContentType, api.HeaderAcceptJSON)
+	_, _ = w.Write(buff)
+}
@@ -0,0 +1,64 @@
+package compactor
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/modules/storage"
+	"github.com/grafana/tempo/tempodb"
+	"github.com/grafana/tempo/tempodb/backend"
+)
+
+type mockTenantDeletionStore struct {
+	storage.Store
+	deletions map[string]*tempodb.TenantDeletion
+}
+
+func (m *mockTenantDeletionStore) TenantDeletion(_ context.Context, tenantID string) (*tempodb.TenantDeletion, error) {
+	d, ok := m.deletions[tenantID]
+	if !ok {
+		return nil, backend.ErrDoesNotExist
+	}
+	return d, nil
+}
+
+func (m *mockTenantDeletionStore) MarkTenantForDeletion(_ context.Context, tenantID string) (*tempodb.TenantDeletion, error) {
+	if d, ok := m.deletions[tenantID]; ok {
+		return d, nil
+	}
+	d := &tempodb.TenantDeletion{TenantID: tenantID, RequestedAt: time.Unix(1, 0).UTC()}
+	m.deletions[tenantID] = d
+	return d, nil
+}
+
+func TestTenantDeletionHandler(t *testing.T) {
+	c := &Compactor{store: &mockTenantDeletionStore{deletions: map[string]*tempodb.TenantDeletion{}}}
+
+	do := func(method, url string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		c.TenantDeletionHandler(w, httptest.NewRequest(method, url, nil))
+		return w
+	}
+
+	require.Equal(t, http.StatusBadRequest, do(http.MethodPost, "/compactor/tenant_deletion").Code)
+	require.Equal(t, http.StatusNotFound, do(http.MethodGet, "/compactor/tenant_deletion?tenant=test").Code)
+	require.Equal(t, http.StatusMethodNotAllowed, do(http.MethodDelete, "/compactor/tenant_deletion?tenant=test").Code)
+
+	w := do(http.MethodPost, "/compactor/tenant_deletion?tenant=test")
+	require.Equal(t, http.StatusOK, w.Code)
+
+	w = do(http.MethodGet, "/compactor/tenant_deletion?tenant=test")
+	require.Equal(t, http.StatusOK, w.Code)
+
+	d := &tempodb.TenantDeletion{}
+	require.NoError(t, jsoniter.Unmarshal(w.Body.Bytes(), d))
+	require.Equal(t, "test", d.TenantID)
+	require.Equal(t, time.Unix(1, 0).UTC(), d.RequestedAt)
+	require.Nil(t, d.CompletedAt)
+}
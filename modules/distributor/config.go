@@ -8,6 +8,7 @@ import (
 	ring_client "github.com/grafana/dskit/ring/client"
 
 	"github.com/grafana/tempo/modules/distributor/forwarder"
+	"github.com/grafana/tempo/pkg/tenantusage"
 	"github.com/grafana/tempo/pkg/util"
 )
 
@@ -47,6 +48,27 @@ type Config struct {
 	// provided duration
 	RetryAfterOnResourceExhausted time.Duration `yaml:"retry_after_on_resource_exhausted"`
 
+	// injects push failures and latency toward ingesters for game days
+	FailureInjection FailureInjectionConfig `yaml:"failure_injection,omitempty"`
+
+	// shares ingestion rates between distributors for the global rate strategy
+	RateSharing RateSharingConfig `yaml:"rate_sharing,omitempty"`
+
+	// adds the receiver, protocol, client address and user agent of the request as resource attributes
+	ReceiverMetadata bool `yaml:"receiver_metadata,omitempty"`
+
+	// accepts span batches over long-lived grpc streams
+	StreamingPush StreamingPushConfig `yaml:"streaming_push,omitempty"`
+
+	// spools the batches that couldn't be written to the ingesters to disk and retries them
+	Spool SpoolConfig `yaml:"spool,omitempty"`
+
+	// adds the name, configured values and source of the limit to the errors of requests rejected by a limit
+	IncludeLimitDetails bool `yaml:"include_limit_details,omitempty"`
+
+	// accounts the ingested bytes per tenant. nil if the tenant usage is disabled
+	TenantUsage *tenantusage.Tracker `yaml:"-"`
+
 	// For testing.
 	factory ring_client.PoolAddrFunc `yaml:"-"`
 }
@@ -71,6 +93,12 @@ func (cfg *Config) RegisterFlagsAndApplyDefaults(prefix string, f *flag.FlagSet)
 	cfg.RetryAfterOnResourceExhausted = 0
 	cfg.OverrideRingKey = distributorRingKey
 	cfg.ExtendWrites = true
+	cfg.RateSharing.UpdatePeriod = 5 * time.Second
+	cfg.StreamingPush.MaxInFlight = defaultStreamingPushMaxInFlight
+	cfg.Spool.Path = "/var/tempo/distributor-spool"
+	cfg.Spool.MaxSizeBytes = 100 * 1024 * 1024
+	cfg.Spool.MaxAge = 10 * time.Minute
+	cfg.Spool.RetryPeriod = 5 * time.Second
 
 	f.BoolVar(&cfg.LogReceivedSpans.Enabled, util.PrefixConfig(prefix, "log-received-spans.enabled"), false, "Enable to log every received span to help debug ingestion or calculate span error distributions using the logs.")
 	f.BoolVar(&cfg.LogReceivedSpans.IncludeAllAttributes, util.PrefixConfig(prefix, "log-received-spans.include-attributes"), false, "Enable to include span attributes in the logs.")
@@ -5,12 +5,15 @@ import (
 	"encoding/hex"
 	"fmt"
 	"math"
+	"net/http"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/go-kit/log"
 	"github.com/go-kit/log/level"
 	"github.com/gogo/status"
+	"github.com/grafana/dskit/kv"
 	"github.com/grafana/dskit/limiter"
 	dslog "github.com/grafana/dskit/log"
 	"github.com/grafana/dskit/ring"
@@ -34,6 +37,7 @@ import (
 	"github.com/grafana/tempo/pkg/model"
 	"github.com/grafana/tempo/pkg/tempopb"
 	v1 "github.com/grafana/tempo/pkg/tempopb/trace/v1"
+	"github.com/grafana/tempo/pkg/tenantusage"
 	tempo_util "github.com/grafana/tempo/pkg/util"
 
 	"github.com/grafana/tempo/pkg/validation"
@@ -140,6 +144,16 @@ type Distributor struct {
 
 	// Per-user rate limiter.
 	ingestionRateLimiter *limiter.RateLimiter
+	// ingestionRates is nil unless rate sharing is enabled for the global strategy
+	ingestionRates *ingestionRateSharing
+	// ingestionRateMeter is nil unless limit details are enabled
+	ingestionRateMeter *ingestionRateMeter
+
+	// failureInjector is nil unless failure injection is enabled
+	failureInjector *failureInjector
+
+	// spool is nil unless spooling is enabled
+	spool *spool
 
 	// Manager for subservices
 	subservices        *services.Manager
@@ -162,6 +176,7 @@ func New(cfg Config, clientCfg ingester_client.Config, ingestersRing ring.ReadRi
 	// Create the configured ingestion rate limit strategy (local or global).
 	var ingestionRateStrategy limiter.RateLimiterStrategy
 	var distributorRing *ring.Ring
+	var ingestionRates *ingestionRateSharing
 
 	if o.IngestionRateStrategy() == overrides.GlobalIngestionRateStrategy {
 		lifecyclerCfg := cfg.DistributorRing.ToLifecyclerConfig()
@@ -172,6 +187,20 @@ func New(cfg Config, clientCfg ingester_client.Config, ingestersRing ring.ReadRi
 		subservices = append(subservices, lifecycler)
 		ingestionRateStrategy = newGlobalIngestionRateStrategy(o, lifecycler)
 
+		if cfg.RateSharing.Enabled {
+			if cfg.RateSharing.UpdatePeriod <= 0 {
+				return nil, fmt.Errorf("rate sharing update period must be greater than 0")
+			}
+
+			kvClient, err := kv.NewClient(cfg.DistributorRing.KVStore, IngestionRatesCodec, kv.RegistererWithKVName(reg, "distributor-ingestion-rates"), logger)
+			if err != nil {
+				return nil, fmt.Errorf("unable to initialize ingestion rates kv client: %w", err)
+			}
+			ingestionRates = newIngestionRateSharing(cfg.RateSharing, lifecyclerCfg.ID, kvClient, logger)
+			subservices = append(subservices, ingestionRates)
+			ingestionRateStrategy = newSharedIngestionRateStrategy(o, lifecycler, ingestionRates)
+		}
+
 		ring, err := ring.New(lifecyclerCfg.RingConfig, "distributor", cfg.OverrideRingKey, logger, prometheus.WrapRegistererWithPrefix("tempo_", reg))
 		if err != nil {
 			return nil, fmt.Errorf("unable to initialize distributor ring: %w", err)
@@ -198,6 +227,7 @@ func New(cfg Config, clientCfg ingester_client.Config, ingestersRing ring.ReadRi
 		pool:                 pool,
 		DistributorRing:      distributorRing,
 		ingestionRateLimiter: limiter.NewRateLimiter(ingestionRateStrategy, 10*time.Second),
+		ingestionRates:       ingestionRates,
 		generatorClientCfg:   generatorClientCfg,
 		generatorsRing:       generatorsRing,
 		overrides:            o,
@@ -205,6 +235,23 @@ func New(cfg Config, clientCfg ingester_client.Config, ingestersRing ring.ReadRi
 		logger:               logger,
 	}
 
+	if cfg.IncludeLimitDetails {
+		d.ingestionRateMeter = newIngestionRateMeter()
+	}
+
+	if cfg.FailureInjection.Enabled {
+		d.failureInjector = newFailureInjector(logger)
+	}
+
+	if cfg.Spool.Enabled {
+		spool, err := newSpool(cfg.Spool, d.sendSpooled, logger)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create spool: %w", err)
+		}
+		d.spool = spool
+		subservices = append(subservices, spool)
+	}
+
 	var generatorsPoolFactory ring_client.PoolAddrFunc = func(addr string) (ring_client.PoolClient, error) {
 		return generator_client.New(addr, generatorClientCfg)
 	}
@@ -235,7 +282,7 @@ func New(cfg Config, clientCfg ingester_client.Config, ingestersRing ring.ReadRi
 		cfgReceivers = defaultReceivers
 	}
 
-	receivers, err := receiver.New(cfgReceivers, d, middleware, cfg.RetryAfterOnResourceExhausted, loggingLevel)
+	receivers, err := receiver.New(cfgReceivers, d, middleware, cfg.RetryAfterOnResourceExhausted, cfg.ReceiverMetadata, loggingLevel)
 	if err != nil {
 		return nil, err
 	}
@@ -277,6 +324,9 @@ func (d *Distributor) stopping(_ error) error {
 }
 
 func (d *Distributor) checkForRateLimits(tracesSize, spanCount int, userID string) error {
+	d.ingestionRates.record(userID, tracesSize)
+	d.ingestionRateMeter.record(userID, tracesSize)
+
 	now := time.Now()
 	if !d.ingestionRateLimiter.AllowN(now, userID, tracesSize) {
 		overrides.RecordDiscardedSpans(spanCount, reasonRateLimited, userID)
@@ -284,18 +334,58 @@ func (d *Distributor) checkForRateLimits(tracesSize, spanCount int, userID strin
 		var globalLimit int
 		if d.overrides.IngestionRateStrategy() == overrides.GlobalIngestionRateStrategy {
 			globalLimit = limit * d.DistributorRing.InstancesCount()
+			if d.ingestionRates != nil {
+				// the local limit follows the share of the traffic and can't be multiplied out
+				globalLimit = int(d.overrides.IngestionRateLimitBytes(userID))
+			}
 		}
-		return status.Errorf(codes.ResourceExhausted,
-			"%s: ingestion rate limit (local: %d bytes, global: %d bytes) exceeded while adding %d bytes for user %s",
+		msg := fmt.Sprintf("%s: ingestion rate limit (local: %d bytes, global: %d bytes) exceeded while adding %d bytes for user %s",
 			overrides.ErrorPrefixRateLimited,
 			limit,
 			globalLimit,
 			tracesSize, userID)
+		if d.cfg.IncludeLimitDetails {
+			msg += d.rateLimitDetails(now, userID, tracesSize)
+		}
+		return status.Error(codes.ResourceExhausted, msg)
 	}
 
 	return nil
 }
 
+// rateLimitDetails describes the ingestion rate limit of the tenant and where it's set so rejected clients don't have to
+// look it up. effective and burst are the limit and burst of the limiter of this distributor, measured is the rate
+// received by this distributor including the rejected requests.
+func (d *Distributor) rateLimitDetails(now time.Time, userID string, tracesSize int) string {
+	return fmt.Sprintf(" [limit=ingestion_rate_limit_bytes configured=%d effective=%d burst=%d measured=%d request=%d strategy=%s source=%q]",
+		int(d.overrides.IngestionRateLimitBytes(userID)),
+		int(d.ingestionRateLimiter.Limit(now, userID)),
+		d.ingestionRateLimiter.Burst(now, userID),
+		int(d.ingestionRateMeter.rate(userID)),
+		tracesSize,
+		d.overrides.IngestionRateStrategy(),
+		overrides.RuntimeOverridesSource(d.overrides, userID))
+}
+
+// ingesterLimitsError describes the limits of the ingesters that discarded traces of the request
+func (d *Distributor) ingesterLimitsError(userID string, limitDetails []*tempopb.PushLimitDetails, discardedSpans map[tempopb.PushErrorReason]int) error {
+	source := overrides.RuntimeOverridesSource(d.overrides, userID)
+
+	var msg strings.Builder
+	for _, l := range limitDetails {
+		if msg.Len() > 0 {
+			msg.WriteString(", ")
+		}
+		prefix := overrides.ErrorPrefixTraceTooLarge
+		if l.Reason == tempopb.PushErrorReason_MAX_LIVE_TRACES {
+			prefix = overrides.ErrorPrefixLiveTracesExceeded
+		}
+		fmt.Fprintf(&msg, "%s: %d spans discarded for user %s [limit=%s configured=%d effective=%d measured=%d source=%q]",
+			prefix, discardedSpans[l.Reason], userID, l.Limit, l.Configured, l.Effective, l.Measured, source)
+	}
+	return status.Error(codes.ResourceExhausted, msg.String())
+}
+
 func (d *Distributor) extractBasicInfo(ctx context.Context, traces ptrace.Traces) (userID string, spanCount, tracesSize int, err error) {
 	user, e := user.ExtractOrgID(ctx)
 	if e != nil {
@@ -349,6 +439,7 @@ func (d *Distributor) PushTraces(ctx context.Context, traces ptrace.Traces) (*te
 	}
 
 	metricBytesIngested.WithLabelValues(userID).Add(float64(size))
+	d.cfg.TenantUsage.Add(userID, tenantusage.Ingested, uint64(size))
 	metricSpansIngested.WithLabelValues(userID).Add(float64(spanCount))
 
 	keys, rebatchedTraces, err := requestsByTraceID(batches, userID, spanCount)
@@ -357,8 +448,18 @@ func (d *Distributor) PushTraces(ctx context.Context, traces ptrace.Traces) (*te
 		return nil, err
 	}
 
-	err = d.sendToIngestersViaBytes(ctx, userID, spanCount, rebatchedTraces, keys)
+	limitDetails, discardedSpans, err := d.sendToIngestersViaBytes(ctx, userID, spanCount, rebatchedTraces, keys)
+	if err != nil && d.spool != nil {
+		// the batches are written to the ingesters once they are available again
+		spoolErr := d.spool.add(userID, batches, spanCount)
+		if spoolErr == nil {
+			err = nil
+		} else {
+			level.Warn(d.logger).Log("msg", "failed to spool batches", "tenant", userID, "err", spoolErr)
+		}
+	}
 	if err != nil {
+		overrides.RecordDiscardedSpans(spanCount, reasonInternalError, userID)
 		return nil, err
 	}
 
@@ -370,15 +471,22 @@ func (d *Distributor) PushTraces(ctx context.Context, traces ptrace.Traces) (*te
 		_ = level.Warn(d.logger).Log("msg", "failed to forward batches for tenant=%s: %w", userID, err)
 	}
 
+	// the traces accepted by the ingesters are written, the error only reports the discarded traces
+	if d.cfg.IncludeLimitDetails && len(limitDetails) > 0 {
+		return nil, d.ingesterLimitsError(userID, limitDetails, discardedSpans)
+	}
+
 	return nil, nil // PushRequest is ignored, so no reason to create one
 }
 
-func (d *Distributor) sendToIngestersViaBytes(ctx context.Context, userID string, totalSpanCount int, traces []*rebatchedTrace, keys []uint32) error {
+// sendToIngestersViaBytes writes the traces to the ingesters. It returns the state of the ingester limits that
+// discarded traces and the spans discarded by each limit.
+func (d *Distributor) sendToIngestersViaBytes(ctx context.Context, userID string, totalSpanCount int, traces []*rebatchedTrace, keys []uint32) ([]*tempopb.PushLimitDetails, map[tempopb.PushErrorReason]int, error) {
 	marshalledTraces := make([][]byte, len(traces))
 	for i, t := range traces {
 		b, err := d.traceEncoder.PrepareForWrite(t.trace, t.start, t.end)
 		if err != nil {
-			return fmt.Errorf("failed to marshal PushRequest: %w", err)
+			return nil, nil, fmt.Errorf("failed to marshal PushRequest: %w", err)
 		}
 		marshalledTraces[i] = b
 	}
@@ -391,6 +499,7 @@ func (d *Distributor) sendToIngestersViaBytes(ctx context.Context, userID string
 	numOfTraces := len(keys)
 	numSuccessByTraceIndex := make([]int, numOfTraces)
 	lastErrorReasonByTraceIndex := make([]tempopb.PushErrorReason, numOfTraces)
+	limitDetailsByReason := map[tempopb.PushErrorReason]*tempopb.PushLimitDetails{}
 
 	var mu sync.Mutex
 
@@ -417,7 +526,13 @@ func (d *Distributor) sendToIngestersViaBytes(ctx context.Context, userID string
 			return err
 		}
 
-		pushResponse, err := c.(tempopb.PusherClient).PushBytesV2(localCtx, &req)
+		var pushResponse *tempopb.PushResponse
+		if d.failureInjector != nil {
+			err = d.failureInjector.inject(localCtx, ingester)
+		}
+		if err == nil {
+			pushResponse, err = c.(tempopb.PusherClient).PushBytesV2(localCtx, &req)
+		}
 		metricIngesterAppends.WithLabelValues(ingester.Addr).Inc()
 
 		if err != nil { // internal error, drop entire batch
@@ -429,13 +544,15 @@ func (d *Distributor) sendToIngestersViaBytes(ctx context.Context, userID string
 		defer mu.Unlock()
 
 		d.processPushResponse(pushResponse, numSuccessByTraceIndex, lastErrorReasonByTraceIndex, numOfTraces, indexes)
+		for _, l := range pushResponse.LimitDetails {
+			limitDetailsByReason[l.Reason] = l
+		}
 
 		return nil
 	}, func() {})
-	// if err != nil, we discarded everything because of an internal error
+	// if err != nil, everything was discarded because of an internal error. the caller records the discarded spans
 	if err != nil {
-		overrides.RecordDiscardedSpans(totalSpanCount, reasonInternalError, userID)
-		return err
+		return nil, nil, err
 	}
 
 	// count discarded span count
@@ -447,7 +564,42 @@ func (d *Distributor) sendToIngestersViaBytes(ctx context.Context, userID string
 	overrides.RecordDiscardedSpans(traceTooLargeDiscardedCount, reasonTraceTooLarge, userID)
 	overrides.RecordDiscardedSpans(unknownErrorCount, reasonUnknown, userID)
 
-	return nil
+	// only the limits of the ingesters that made the traces miss the quorum are reported
+	discardedSpans := map[tempopb.PushErrorReason]int{
+		tempopb.PushErrorReason_MAX_LIVE_TRACES: maxLiveDiscardedCount,
+		tempopb.PushErrorReason_TRACE_TOO_LARGE: traceTooLargeDiscardedCount,
+	}
+	var limitDetails []*tempopb.PushLimitDetails
+	for _, reason := range []tempopb.PushErrorReason{tempopb.PushErrorReason_TRACE_TOO_LARGE, tempopb.PushErrorReason_MAX_LIVE_TRACES} {
+		if l, ok := limitDetailsByReason[reason]; ok && discardedSpans[reason] > 0 {
+			limitDetails = append(limitDetails, l)
+		}
+	}
+
+	return limitDetails, discardedSpans, nil
+}
+
+// sendSpooled writes batches retried from the spool to the ingesters
+func (d *Distributor) sendSpooled(ctx context.Context, userID string, batches []*v1.ResourceSpans) error {
+	spanCount := countSpans(batches)
+	keys, rebatchedTraces, err := requestsByTraceID(batches, userID, spanCount)
+	if err != nil {
+		return err
+	}
+
+	_, _, err = d.sendToIngestersViaBytes(ctx, userID, spanCount, rebatchedTraces, keys)
+	return err
+}
+
+// FailureInjectionHandler manages the failures injected into pushes to ingesters. It returns 404 if failure
+// injection is not enabled.
+func (d *Distributor) FailureInjectionHandler(w http.ResponseWriter, r *http.Request) {
+	if d.failureInjector == nil {
+		http.Error(w, "failure injection is not enabled", http.StatusNotFound)
+		return
+	}
+
+	d.failureInjector.ServeHTTP(w, r)
 }
 
 func (d *Distributor) sendToGenerators(ctx context.Context, userID string, keys []uint32, traces []*rebatchedTrace) error {
@@ -1024,6 +1024,50 @@ func TestRateLimitRespected(t *testing.T) {
 	status, ok := status.FromError(err)
 	assert.True(t, ok)
 	assert.True(t, status.Code() == codes.ResourceExhausted, "Wrong status code")
+	assert.NotContains(t, status.Message(), "limit=")
+
+	// the limit, the state of the limiter and where it's set are added to the error if enabled
+	d.cfg.IncludeLimitDetails = true
+	d.ingestionRateMeter = newIngestionRateMeter()
+	_, err = d.PushTraces(ctx, traces)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `[limit=ingestion_rate_limit_bytes configured=400 effective=400 burst=200 measured=`)
+	assert.Contains(t, err.Error(), `strategy=local source="default overrides"]`)
+	assert.NotContains(t, err.Error(), "measured=0 ")
+}
+
+func TestIngesterLimitDetails(t *testing.T) {
+	ctx := user.InjectOrgID(context.Background(), "test")
+	limits := overrides.Config{}
+	limits.RegisterFlagsAndApplyDefaults(&flag.FlagSet{})
+	d := prepare(t, limits, nil)
+
+	details := &tempopb.PushLimitDetails{
+		Reason:     tempopb.PushErrorReason_TRACE_TOO_LARGE,
+		Limit:      "max_bytes_per_trace",
+		Configured: 100,
+		Effective:  100,
+		Measured:   150,
+	}
+	for _, ingester := range d.ingestersRing.(*mockRing).ingesters {
+		c, err := d.pool.GetClientFor(ingester.Addr)
+		require.NoError(t, err)
+		c.(*mockIngester).limitDetails = details
+	}
+
+	traces := batchesToTraces(t, []*v1.ResourceSpans{test.MakeBatch(3, nil)})
+
+	// traces discarded by the ingesters don't fail the push by default
+	_, err := d.PushTraces(ctx, traces)
+	require.NoError(t, err)
+
+	d.cfg.IncludeLimitDetails = true
+	_, err = d.PushTraces(ctx, traces)
+	require.Error(t, err)
+	status, ok := status.FromError(err)
+	require.True(t, ok)
+	require.Equal(t, codes.ResourceExhausted, status.Code())
+	require.Equal(t, `TRACE_TOO_LARGE: 3 spans discarded for user test [limit=max_bytes_per_trace configured=100 effective=100 measured=150 source="default overrides"]`, status.Message())
 }
 
 func TestDiscardCountReplicationFactor(t *testing.T) {
@@ -1430,6 +1474,8 @@ func prepare(t *testing.T, limits overrides.Config, logger kitlog.Logger) *Distr
 
 type mockIngester struct {
 	grpc_health_v1.HealthClient
+	// limitDetails discards all traces if set
+	limitDetails *tempopb.PushLimitDetails
 }
 
 var _ tempopb.PusherClient = (*mockIngester)(nil)
@@ -1438,8 +1484,16 @@ func (i *mockIngester) PushBytes(context.Context, *tempopb.PushBytesRequest, ...
 	return &tempopb.PushResponse{}, nil
 }
 
-func (i *mockIngester) PushBytesV2(context.Context, *tempopb.PushBytesRequest, ...grpc.CallOption) (*tempopb.PushResponse, error) {
-	return &tempopb.PushResponse{}, nil
+func (i *mockIngester) PushBytesV2(_ context.Context, req *tempopb.PushBytesRequest, _ ...grpc.CallOption) (*tempopb.PushResponse, error) {
+	if i.limitDetails == nil {
+		return &tempopb.PushResponse{}, nil
+	}
+
+	errorsByTrace := make([]tempopb.PushErrorReason, len(req.Traces))
+	for j := range errorsByTrace {
+		errorsByTrace[j] = i.limitDetails.Reason
+	}
+	return &tempopb.PushResponse{ErrorsByTrace: errorsByTrace, LimitDetails: []*tempopb.PushLimitDetails{i.limitDetails}}, nil
 }
 
 func (i *mockIngester) Close() error {
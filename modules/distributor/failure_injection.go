@@ -0,0 +1,233 @@
+package distributor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/gogo/status"
+	"github.com/grafana/dskit/ring"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"google.golang.org/grpc/codes"
+)
+
+const (
+	failureInjectionParamIngester       = "ingester"
+	failureInjectionParamFailurePercent = "failure_percent"
+	failureInjectionParamLatency        = "latency"
+	failureInjectionParamDuration       = "duration"
+
+	defaultFailureInjectionDuration = 10 * time.Minute
+)
+
+var metricInjectedFailures = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "tempo",
+	Name:      "distributor_ingester_injected_failures_total",
+	Help:      "The total number of batch appends to ingesters that were failed by failure injection.",
+}, []string{"ingester"})
+
+type FailureInjectionConfig struct {
+	// enables the /distributor/failure_injection endpoint. only enable this for game days
+	Enabled bool `yaml:"enabled"`
+}
+
+// failureRule describes the failures and latency injected into pushes to a single ingester
+type failureRule struct {
+	Ingester       string        `json:"ingester"`
+	FailurePercent float64       `json:"failure_percent"`
+	Latency        time.Duration `json:"-"`
+	Expires        time.Time     `json:"expires"`
+}
+
+func (r failureRule) MarshalJSON() ([]byte, error) {
+	type alias failureRule
+	return json.Marshal(struct {
+		alias
+		Latency string `json:"latency"`
+	}{
+		alias:   alias(r),
+		Latency: r.Latency.String(),
+	})
+}
+
+// failureInjector injects failures and latency into pushes from the distributor to ingesters. rules are keyed by
+// ingester id or address and expire after a fixed duration so a forgotten rule can't degrade writes indefinitely.
+type failureInjector struct {
+	mtx   sync.RWMutex
+	rules map[string]failureRule
+
+	now    func() time.Time
+	random func() float64
+	logger log.Logger
+}
+
+func newFailureInjector(logger log.Logger) *failureInjector {
+	return &failureInjector{
+		rules:  map[string]failureRule{},
+		now:    time.Now,
+		random: rand.Float64,
+		logger: logger,
+	}
+}
+
+// inject applies the rule for the ingester, if any. it waits for the configured latency and then returns an error
+// for the configured percentage of calls.
+func (f *failureInjector) inject(ctx context.Context, ingester ring.InstanceDesc) error {
+	rule, ok := f.ruleFor(ingester)
+	if !ok {
+		return nil
+	}
+
+	if rule.Latency > 0 {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(rule.Latency):
+		}
+	}
+
+	if rule.FailurePercent > 0 && f.random()*100 < rule.FailurePercent {
+		metricInjectedFailures.WithLabelValues(ingester.Addr).Inc()
+		return status.Errorf(codes.Unavailable, "injected failure for ingester %s", rule.Ingester)
+	}
+
+	return nil
+}
+
+func (f *failureInjector) ruleFor(ingester ring.InstanceDesc) (failureRule, bool) {
+	f.mtx.RLock()
+	defer f.mtx.RUnlock()
+
+	if len(f.rules) == 0 {
+		return failureRule{}, false
+	}
+
+	rule, ok := f.rules[ingester.Id]
+	if !ok {
+		rule, ok = f.rules[ingester.Addr]
+	}
+	if !ok || !f.now().Before(rule.Expires) {
+		return failureRule{}, false
+	}
+
+	return rule, true
+}
+
+func (f *failureInjector) set(rule failureRule) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	f.rules[rule.Ingester] = rule
+}
+
+// remove removes the rule for the ingester or all rules if ingester is empty
+func (f *failureInjector) remove(ingester string) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	if ingester == "" {
+		f.rules = map[string]failureRule{}
+		return
+	}
+	delete(f.rules, ingester)
+}
+
+// active returns all rules that have not expired and drops the expired ones
+func (f *failureInjector) active() []failureRule {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	now := f.now()
+	rules := make([]failureRule, 0, len(f.rules))
+	for k, r := range f.rules {
+		if !now.Before(r.Expires) {
+			delete(f.rules, k)
+			continue
+		}
+		rules = append(rules, r)
+	}
+
+	sort.Slice(rules, func(i, j int) bool {
+		return rules[i].Ingester < rules[j].Ingester
+	})
+
+	return rules
+}
+
+// ServeHTTP lists the active rules on GET, adds or replaces the rule for an ingester on POST and removes the rule
+// for an ingester, or all rules, on DELETE.
+func (f *failureInjector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPost:
+		rule, err := f.parseRule(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		f.set(rule)
+		level.Warn(f.logger).Log("msg", "injecting failures into pushes to ingester", "ingester", rule.Ingester, "failure_percent", rule.FailurePercent, "latency", rule.Latency, "expires", rule.Expires)
+	case http.MethodDelete:
+		ingester := r.URL.Query().Get(failureInjectionParamIngester)
+		f.remove(ingester)
+		level.Warn(f.logger).Log("msg", "removed failure injection", "ingester", ingester)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(f.active())
+}
+
+func (f *failureInjector) parseRule(r *http.Request) (failureRule, error) {
+	q := r.URL.Query()
+
+	rule := failureRule{
+		Ingester: q.Get(failureInjectionParamIngester),
+	}
+	if rule.Ingester == "" {
+		return failureRule{}, fmt.Errorf("%s is required", failureInjectionParamIngester)
+	}
+
+	if s := q.Get(failureInjectionParamFailurePercent); s != "" {
+		p, err := strconv.ParseFloat(s, 64)
+		if err != nil || p < 0 || p > 100 {
+			return failureRule{}, fmt.Errorf("invalid %s %s. must be between 0 and 100", failureInjectionParamFailurePercent, s)
+		}
+		rule.FailurePercent = p
+	}
+
+	if s := q.Get(failureInjectionParamLatency); s != "" {
+		l, err := time.ParseDuration(s)
+		if err != nil || l < 0 {
+			return failureRule{}, fmt.Errorf("invalid %s %s", failureInjectionParamLatency, s)
+		}
+		rule.Latency = l
+	}
+
+	if rule.FailurePercent == 0 && rule.Latency == 0 {
+		return failureRule{}, fmt.Errorf("one of %s or %s is required", failureInjectionParamFailurePercent, failureInjectionParamLatency)
+	}
+
+	duration := defaultFailureInjectionDuration
+	if s := q.Get(failureInjectionParamDuration); s != "" {
+		d, err := time.ParseDuration(s)
+		if err != nil || d <= 0 {
+			return failureRule{}, fmt.Errorf("invalid %s %s", failureInjectionParamDuration, s)
+		}
+		duration = d
+	}
+	rule.Expires = f.now().Add(duration)
+
+	return rule, nil
+}
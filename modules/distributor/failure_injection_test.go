@@ -0,0 +1,135 @@
+package distributor
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	kitlog "github.com/go-kit/log"
+	"github.com/grafana/dskit/ring"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/modules/overrides"
+	v1 "github.com/grafana/tempo/pkg/tempopb/trace/v1"
+	"github.com/grafana/tempo/pkg/util/test"
+)
+
+func TestFailureInjectionHandler(t *testing.T) {
+	now := time.Unix(1000, 0)
+	f := newFailureInjector(kitlog.NewNopLogger())
+	f.now = func() time.Time { return now }
+
+	do := func(method, query string) (int, []map[string]interface{}) {
+		w := httptest.NewRecorder()
+		f.ServeHTTP(w, httptest.NewRequest(method, "/distributor/failure_injection?"+query, nil))
+
+		var rules []map[string]interface{}
+		if w.Code == http.StatusOK {
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &rules))
+		}
+		return w.Code, rules
+	}
+
+	// invalid requests
+	for _, query := range []string{
+		"failure_percent=10",
+		"ingester=ingester-0",
+		"ingester=ingester-0&failure_percent=101",
+		"ingester=ingester-0&failure_percent=foo",
+		"ingester=ingester-0&latency=-1s",
+		"ingester=ingester-0&failure_percent=10&duration=0s",
+	} {
+		code, _ := do(http.MethodPost, query)
+		require.Equal(t, http.StatusBadRequest, code, query)
+	}
+
+	code, rules := do(http.MethodPost, "ingester=ingester-1&failure_percent=50&latency=100ms")
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, []map[string]interface{}{
+		{"ingester": "ingester-1", "failure_percent": 50.0, "latency": "100ms", "expires": now.Add(defaultFailureInjectionDuration).Format(time.RFC3339)},
+	}, rules)
+
+	code, rules = do(http.MethodPost, "ingester=ingester-0&failure_percent=100&duration=1m")
+	require.Equal(t, http.StatusOK, code)
+	require.Len(t, rules, 2)
+	require.Equal(t, "ingester-0", rules[0]["ingester"])
+
+	// expired rules are dropped
+	now = now.Add(2 * time.Minute)
+	code, rules = do(http.MethodGet, "")
+	require.Equal(t, http.StatusOK, code)
+	require.Len(t, rules, 1)
+	require.Equal(t, "ingester-1", rules[0]["ingester"])
+
+	code, rules = do(http.MethodDelete, "ingester=ingester-1")
+	require.Equal(t, http.StatusOK, code)
+	require.Empty(t, rules)
+
+	code, _ = do(http.MethodPut, "")
+	require.Equal(t, http.StatusMethodNotAllowed, code)
+}
+
+func TestFailureInjectorInject(t *testing.T) {
+	f := newFailureInjector(kitlog.NewNopLogger())
+	f.random = func() float64 { return 0.5 }
+
+	ingester := ring.InstanceDesc{Id: "ingester-0", Addr: "10.0.0.1:9095"}
+
+	// no rule
+	require.NoError(t, f.inject(context.Background(), ingester))
+
+	// rules match on id or address
+	f.set(failureRule{Ingester: "10.0.0.1:9095", FailurePercent: 60, Expires: time.Now().Add(time.Minute)})
+	require.Error(t, f.inject(context.Background(), ingester))
+
+	f.set(failureRule{Ingester: "ingester-0", FailurePercent: 40, Expires: time.Now().Add(time.Minute)})
+	require.NoError(t, f.inject(context.Background(), ingester))
+
+	// latency honors the context
+	f.set(failureRule{Ingester: "ingester-0", Latency: time.Hour, Expires: time.Now().Add(time.Minute)})
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	require.ErrorIs(t, f.inject(ctx, ingester), context.DeadlineExceeded)
+
+	// expired rules are ignored
+	f.set(failureRule{Ingester: "ingester-0", FailurePercent: 100, Expires: time.Now().Add(-time.Minute)})
+	f.remove("10.0.0.1:9095")
+	require.NoError(t, f.inject(context.Background(), ingester))
+}
+
+func TestDistributorFailureInjection(t *testing.T) {
+	limits := overrides.Config{}
+	limits.RegisterFlagsAndApplyDefaults(&flag.FlagSet{})
+
+	d := prepare(t, limits, nil)
+
+	// disabled by default
+	w := httptest.NewRecorder()
+	d.FailureInjectionHandler(w, httptest.NewRequest(http.MethodGet, "/distributor/failure_injection", nil))
+	require.Equal(t, http.StatusNotFound, w.Code)
+
+	d.failureInjector = newFailureInjector(kitlog.NewNopLogger())
+
+	push := func() error {
+		b := test.MakeBatch(100, []byte{})
+		_, err := d.PushTraces(ctx, batchesToTraces(t, []*v1.ResourceSpans{b}))
+		return err
+	}
+
+	// a single failing ingester is tolerated by the replication factor of 3
+	d.failureInjector.set(failureRule{Ingester: "ingester0", FailurePercent: 100, Expires: time.Now().Add(time.Minute)})
+	require.NoError(t, push())
+
+	// with 4 of 5 ingesters failing every replication set has at least two failures
+	for _, ingester := range []string{"ingester1", "ingester2", "ingester3"} {
+		d.failureInjector.set(failureRule{Ingester: ingester, FailurePercent: 100, Expires: time.Now().Add(time.Minute)})
+	}
+	require.Error(t, push())
+
+	d.failureInjector.remove("")
+	require.NoError(t, push())
+}
@@ -0,0 +1,89 @@
+package distributor
+
+import (
+	"sync"
+	"time"
+)
+
+// ingestionRateMeterWindow is the period the ingestion rate of a tenant is measured over
+const ingestionRateMeterWindow = 10 * time.Second
+
+// ingestionRateMeter measures the bytes per second received by this distributor for each tenant. Rejected pushes are
+// measured as well so the rate is the demand the limit is compared against.
+type ingestionRateMeter struct {
+	now func() time.Time
+
+	mtx     sync.Mutex
+	tenants map[string]*tenantIngestionRate
+}
+
+type tenantIngestionRate struct {
+	start    time.Time // start of the current window
+	bytes    int64     // bytes received in the current window
+	previous float64   // rate of the previous window, negative until the first window is complete
+}
+
+func newIngestionRateMeter() *ingestionRateMeter {
+	return &ingestionRateMeter{
+		now:     time.Now,
+		tenants: map[string]*tenantIngestionRate{},
+	}
+}
+
+func (m *ingestionRateMeter) record(userID string, bytes int) {
+	if m == nil {
+		return
+	}
+
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	now := m.now()
+	r, ok := m.tenants[userID]
+	if !ok {
+		r = &tenantIngestionRate{start: now, previous: -1}
+		m.tenants[userID] = r
+	}
+	r.advance(now)
+	r.bytes += int64(bytes)
+}
+
+// rate returns the bytes per second of the last complete window, or of the current window if the tenant started
+// pushing within the last window
+func (m *ingestionRateMeter) rate(userID string) float64 {
+	if m == nil {
+		return 0
+	}
+
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	r, ok := m.tenants[userID]
+	if !ok {
+		return 0
+	}
+
+	now := m.now()
+	r.advance(now)
+	if r.previous >= 0 {
+		return r.previous
+	}
+	return float64(r.bytes) / max(now.Sub(r.start).Seconds(), 1)
+}
+
+func (r *tenantIngestionRate) advance(now time.Time) {
+	elapsed := now.Sub(r.start)
+	if elapsed < ingestionRateMeterWindow {
+		return
+	}
+
+	if elapsed < 2*ingestionRateMeterWindow {
+		r.previous = float64(r.bytes) / ingestionRateMeterWindow.Seconds()
+		r.start = r.start.Add(ingestionRateMeterWindow)
+	} else {
+		// no traffic in the previous window
+		r.previous = 0
+		r.start = now
+	}
+	r.bytes = 0
+}
@@ -0,0 +1,41 @@
+package distributor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIngestionRateMeter(t *testing.T) {
+	now := time.Now()
+	m := newIngestionRateMeter()
+	m.now = func() time.Time { return now }
+
+	require.Zero(t, m.rate("test"))
+
+	// the rate of the current window is used until the first window is complete
+	m.record("test", 100)
+	now = now.Add(4 * time.Second)
+	m.record("test", 100)
+	require.Equal(t, 50.0, m.rate("test"))
+
+	// then the rate of the last complete window
+	now = now.Add(8 * time.Second)
+	m.record("test", 1000)
+	require.Equal(t, 20.0, m.rate("test"))
+
+	now = now.Add(9 * time.Second)
+	require.Equal(t, 100.0, m.rate("test"))
+
+	// windows without traffic
+	now = now.Add(time.Minute)
+	require.Zero(t, m.rate("test"))
+
+	// tenants are measured separately
+	require.Zero(t, m.rate("other"))
+
+	var nilMeter *ingestionRateMeter
+	nilMeter.record("test", 100)
+	require.Zero(t, nilMeter.rate("test"))
+}
@@ -0,0 +1,290 @@
+package distributor
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/grafana/dskit/kv"
+	"github.com/grafana/dskit/kv/memberlist"
+	"github.com/grafana/dskit/services"
+	jsoniter "github.com/json-iterator/go"
+
+	"github.com/grafana/tempo/modules/overrides"
+)
+
+const (
+	ingestionRatesKey = "distributor-ingestion-rates"
+
+	// rates of distributors that haven't updated for this many periods are ignored
+	staleIngestionRatePeriods = 3
+	// a distributor gets at least this fraction of an even share of the limit so traffic that shifts to it
+	// isn't rejected before the next update
+	minIngestionRateShare = 0.1
+)
+
+type RateSharingConfig struct {
+	// shares the per tenant ingestion rate of each distributor through the distributor ring kvstore. the
+	// global rate strategy then splits the limit by each distributor's share of the traffic instead of evenly
+	Enabled      bool          `yaml:"enabled"`
+	UpdatePeriod time.Duration `yaml:"update_period"`
+}
+
+// IngestionRates is the state shared between distributors. It holds the recent ingestion rate in bytes per second
+// of each tenant on each distributor.
+type IngestionRates struct {
+	Instances map[string]InstanceIngestionRates `json:"instances"`
+}
+
+type InstanceIngestionRates struct {
+	UpdatedAt time.Time          `json:"updated_at"`
+	Tenants   map[string]float64 `json:"tenants"`
+}
+
+var _ memberlist.Mergeable = (*IngestionRates)(nil)
+
+// Merge implements the memberlist.Mergeable interface. The most recently updated rates of each instance win.
+func (r *IngestionRates) Merge(mergeable memberlist.Mergeable, _ bool) (memberlist.Mergeable, error) {
+	if mergeable == nil {
+		return nil, nil
+	}
+	other, ok := mergeable.(*IngestionRates)
+	if !ok {
+		return nil, fmt.Errorf("expected *distributor.IngestionRates, got %T", mergeable)
+	}
+	if other == nil {
+		return nil, nil
+	}
+
+	if r.Instances == nil {
+		r.Instances = map[string]InstanceIngestionRates{}
+	}
+
+	change := &IngestionRates{Instances: map[string]InstanceIngestionRates{}}
+	for id, rates := range other.Instances {
+		if current, ok := r.Instances[id]; ok && !rates.UpdatedAt.After(current.UpdatedAt) {
+			continue
+		}
+		r.Instances[id] = rates
+		change.Instances[id] = rates
+	}
+
+	if len(change.Instances) == 0 {
+		return nil, nil
+	}
+	return change, nil
+}
+
+// MergeContent implements the memberlist.Mergeable interface.
+func (r *IngestionRates) MergeContent() []string {
+	ids := make([]string, 0, len(r.Instances))
+	for id := range r.Instances {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// RemoveTombstones implements the memberlist.Mergeable interface. It removes instances that haven't updated
+// their rates since the limit.
+func (r *IngestionRates) RemoveTombstones(limit time.Time) (total, removed int) {
+	for id, rates := range r.Instances {
+		if !limit.IsZero() && rates.UpdatedAt.Before(limit) {
+			delete(r.Instances, id)
+			removed++
+		}
+	}
+	return 0, removed
+}
+
+// Clone implements the memberlist.Mergeable interface.
+func (r *IngestionRates) Clone() memberlist.Mergeable {
+	clone := &IngestionRates{Instances: make(map[string]InstanceIngestionRates, len(r.Instances))}
+	for id, rates := range r.Instances {
+		tenants := make(map[string]float64, len(rates.Tenants))
+		for t, rate := range rates.Tenants {
+			tenants[t] = rate
+		}
+		clone.Instances[id] = InstanceIngestionRates{UpdatedAt: rates.UpdatedAt, Tenants: tenants}
+	}
+	return clone
+}
+
+var IngestionRatesCodec = ingestionRatesCodec{}
+
+type ingestionRatesCodec struct{}
+
+func (ingestionRatesCodec) Decode(data []byte) (interface{}, error) {
+	var rates IngestionRates
+	if err := jsoniter.ConfigFastest.Unmarshal(data, &rates); err != nil {
+		return nil, err
+	}
+	return &rates, nil
+}
+
+func (ingestionRatesCodec) Encode(obj interface{}) ([]byte, error) {
+	return jsoniter.ConfigFastest.Marshal(obj)
+}
+
+func (ingestionRatesCodec) CodecID() string { return "distributor.ingestionRatesCodec" }
+
+// ingestionRateSharing measures the per tenant ingestion rate of this distributor and periodically publishes it to
+// the kvstore. It keeps the sum of the recent rates of all distributors to calculate this distributor's share.
+type ingestionRateSharing struct {
+	services.Service
+
+	instanceID string
+	kv         kv.Client
+	period     time.Duration
+	now        func() time.Time
+	logger     log.Logger
+
+	mtx        sync.Mutex
+	received   map[string]int64   // bytes received per tenant since the last update
+	local      map[string]float64 // rates of this distributor at the last update
+	totals     map[string]float64 // sum of the recent rates of all distributors
+	lastUpdate time.Time
+}
+
+func newIngestionRateSharing(cfg RateSharingConfig, instanceID string, kvClient kv.Client, logger log.Logger) *ingestionRateSharing {
+	s := &ingestionRateSharing{
+		instanceID: instanceID,
+		kv:         kvClient,
+		period:     cfg.UpdatePeriod,
+		now:        time.Now,
+		logger:     logger,
+		received:   map[string]int64{},
+		local:      map[string]float64{},
+		totals:     map[string]float64{},
+	}
+	s.lastUpdate = s.now()
+
+	s.Service = services.NewTimerService(cfg.UpdatePeriod, nil, s.iteration, nil)
+	return s
+}
+
+// record adds the bytes received for a tenant. rejected pushes are recorded as well so the share follows the
+// demand of each distributor and not the traffic it was allowed to accept.
+func (s *ingestionRateSharing) record(userID string, bytes int) {
+	if s == nil {
+		return
+	}
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	s.received[userID] += int64(bytes)
+}
+
+// share returns the fraction of the tenant's traffic received by this distributor. ok is false if there is no
+// recent traffic for the tenant.
+func (s *ingestionRateSharing) share(userID string) (share float64, ok bool) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	total := s.totals[userID]
+	if total <= 0 {
+		return 0, false
+	}
+	return s.local[userID] / total, true
+}
+
+func (s *ingestionRateSharing) iteration(ctx context.Context) error {
+	err := s.update(ctx)
+	if err != nil {
+		level.Warn(s.logger).Log("msg", "failed to update shared ingestion rates", "err", err)
+	}
+	// never fail the service. the global strategy falls back to an even split without recent rates
+	return nil
+}
+
+func (s *ingestionRateSharing) update(ctx context.Context) error {
+	now := s.now()
+
+	s.mtx.Lock()
+	elapsed := now.Sub(s.lastUpdate).Seconds()
+	local := make(map[string]float64, len(s.received))
+	for userID, bytes := range s.received {
+		if elapsed > 0 && bytes > 0 {
+			local[userID] = float64(bytes) / elapsed
+		}
+	}
+	s.received = map[string]int64{}
+	s.local = local
+	s.lastUpdate = now
+	s.mtx.Unlock()
+
+	own := InstanceIngestionRates{UpdatedAt: now, Tenants: local}
+	err := s.kv.CAS(ctx, ingestionRatesKey, func(in interface{}) (out interface{}, retry bool, err error) {
+		rates, _ := in.(*IngestionRates)
+		if rates == nil {
+			rates = &IngestionRates{}
+		}
+		if rates.Instances == nil {
+			rates.Instances = map[string]InstanceIngestionRates{}
+		}
+		rates.Instances[s.instanceID] = own
+		return rates, true, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	val, err := s.kv.Get(ctx, ingestionRatesKey)
+	if err != nil {
+		return err
+	}
+
+	totals := map[string]float64{}
+	if rates, ok := val.(*IngestionRates); ok && rates != nil {
+		stale := now.Add(-staleIngestionRatePeriods * s.period)
+		for id, instance := range rates.Instances {
+			if id == s.instanceID || instance.UpdatedAt.Before(stale) {
+				continue
+			}
+			for userID, rate := range instance.Tenants {
+				totals[userID] += rate
+			}
+		}
+	}
+	// always use the rates just measured for this distributor
+	for userID, rate := range local {
+		totals[userID] += rate
+	}
+
+	s.mtx.Lock()
+	s.totals = totals
+	s.mtx.Unlock()
+
+	return nil
+}
+
+// sharedStrategy splits the global limit between distributors by their share of the tenant's recent traffic.
+// It falls back to an even split like the globalStrategy if there is no recent traffic for the tenant.
+type sharedStrategy struct {
+	globalStrategy
+	rates *ingestionRateSharing
+}
+
+func newSharedIngestionRateStrategy(limits overrides.Interface, ring ReadLifecycler, rates *ingestionRateSharing) *sharedStrategy {
+	return &sharedStrategy{
+		globalStrategy: globalStrategy{
+			limits: limits,
+			ring:   ring,
+		},
+		rates: rates,
+	}
+}
+
+func (s *sharedStrategy) Limit(userID string) float64 {
+	numDistributors := s.ring.HealthyInstancesCount()
+
+	share, ok := s.rates.share(userID)
+	if !ok || numDistributors <= 1 {
+		return s.globalStrategy.Limit(userID)
+	}
+
+	return s.limits.IngestionRateLimitBytes(userID) * max(share, minIngestionRateShare/float64(numDistributors))
+}
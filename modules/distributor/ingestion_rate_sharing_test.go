@@ -0,0 +1,118 @@
+package distributor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/grafana/dskit/kv/consul"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/modules/overrides"
+)
+
+func TestIngestionRatesMerge(t *testing.T) {
+	now := time.Now()
+
+	rates := &IngestionRates{Instances: map[string]InstanceIngestionRates{
+		"a": {UpdatedAt: now, Tenants: map[string]float64{"test": 10}},
+		"b": {UpdatedAt: now, Tenants: map[string]float64{"test": 20}},
+	}}
+
+	change, err := rates.Merge(&IngestionRates{Instances: map[string]InstanceIngestionRates{
+		"a": {UpdatedAt: now.Add(-time.Second), Tenants: map[string]float64{"test": 1}}, // older, ignored
+		"b": {UpdatedAt: now.Add(time.Second), Tenants: map[string]float64{"test": 2}},
+		"c": {UpdatedAt: now, Tenants: map[string]float64{"test": 3}},
+	}}, false)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"b", "c"}, change.MergeContent())
+	require.Equal(t, 10.0, rates.Instances["a"].Tenants["test"])
+	require.Equal(t, 2.0, rates.Instances["b"].Tenants["test"])
+	require.Equal(t, 3.0, rates.Instances["c"].Tenants["test"])
+
+	// merging the same state again is a no-op
+	change, err = rates.Merge(rates.Clone(), false)
+	require.NoError(t, err)
+	require.Nil(t, change)
+
+	_, removed := rates.RemoveTombstones(now.Add(500 * time.Millisecond))
+	require.Equal(t, 2, removed)
+	require.ElementsMatch(t, []string{"b"}, rates.MergeContent())
+
+	// round trip through the codec
+	b, err := IngestionRatesCodec.Encode(rates)
+	require.NoError(t, err)
+	decoded, err := IngestionRatesCodec.Decode(b)
+	require.NoError(t, err)
+	require.Equal(t, 2.0, decoded.(*IngestionRates).Instances["b"].Tenants["test"])
+}
+
+func TestSharedIngestionRateStrategy(t *testing.T) {
+	kvClient, closer := consul.NewInMemoryClient(IngestionRatesCodec, log.NewNopLogger(), nil)
+	t.Cleanup(func() { _ = closer.Close() })
+
+	o, err := overrides.NewOverrides(overrides.Config{
+		Defaults: overrides.Overrides{
+			Ingestion: overrides.IngestionOverrides{
+				RateStrategy:   overrides.GlobalIngestionRateStrategy,
+				RateLimitBytes: 1000,
+				BurstSizeBytes: 2,
+			},
+		},
+	}, nil, prometheus.NewRegistry())
+	require.NoError(t, err)
+
+	ring := newReadLifecyclerMock()
+	ring.On("HealthyInstancesCount").Return(2)
+
+	now := time.Now()
+	cfg := RateSharingConfig{Enabled: true, UpdatePeriod: time.Second}
+
+	busy := newIngestionRateSharing(cfg, "busy", kvClient, log.NewNopLogger())
+	idle := newIngestionRateSharing(cfg, "idle", kvClient, log.NewNopLogger())
+	busy.lastUpdate = now
+	idle.lastUpdate = now
+
+	busyStrategy := newSharedIngestionRateStrategy(o, ring, busy)
+	idleStrategy := newSharedIngestionRateStrategy(o, ring, idle)
+
+	// without recent traffic the limit is split evenly
+	require.Equal(t, 500.0, busyStrategy.Limit("test"))
+	require.Equal(t, 500.0, idleStrategy.Limit("test"))
+
+	// two update rounds so both distributors have seen the rates published by the other
+	ctx := context.Background()
+	for i := 1; i <= 2; i++ {
+		ts := now.Add(time.Duration(i) * time.Second)
+		busy.now = func() time.Time { return ts }
+		idle.now = func() time.Time { return ts }
+
+		busy.record("test", 900)
+		idle.record("test", 100)
+		idle.record("other", 100)
+
+		require.NoError(t, busy.update(ctx))
+		require.NoError(t, idle.update(ctx))
+	}
+
+	require.InDelta(t, 900.0, busyStrategy.Limit("test"), 0.001)
+	require.InDelta(t, 100.0, idleStrategy.Limit("test"), 0.001)
+
+	// the busy distributor has no traffic for the other tenant. it still gets a minimum share
+	require.InDelta(t, 1000.0, idleStrategy.Limit("other"), 0.001)
+	require.InDelta(t, 50.0, busyStrategy.Limit("other"), 0.001)
+	require.Equal(t, 2, busyStrategy.Burst("test"))
+
+	// rates of distributors that stopped updating are ignored
+	idle.now = func() time.Time { return now.Add(12 * time.Second) }
+	idle.record("test", 100)
+	require.NoError(t, idle.update(ctx))
+	require.InDelta(t, 1000.0, idleStrategy.Limit("test"), 0.001)
+}
+
+func TestIngestionRateSharingNil(t *testing.T) {
+	var s *ingestionRateSharing
+	s.record("test", 100)
+}
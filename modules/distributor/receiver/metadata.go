@@ -0,0 +1,95 @@
+package receiver
+
+import (
+	"context"
+	"net"
+
+	"go.opentelemetry.io/collector/client"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+// Resource attributes added to received spans if receiver metadata is enabled
+const (
+	AttributeReceiverName          = "tempo.receiver.name"
+	AttributeReceiverProtocol      = "tempo.receiver.protocol"
+	AttributeReceiverClientAddress = "tempo.receiver.client_address"
+	AttributeReceiverUserAgent     = "tempo.receiver.user_agent"
+)
+
+// receiverMetadataConsumer adds the receiver, protocol, client address and user agent of the request to the resource
+// of every batch
+type receiverMetadataConsumer struct {
+	receiver string
+	next     consumer.Traces
+}
+
+func newReceiverMetadataConsumer(receiver string, next consumer.Traces) consumer.Traces {
+	return &receiverMetadataConsumer{
+		receiver: receiver,
+		next:     next,
+	}
+}
+
+func (c *receiverMetadataConsumer) Capabilities() consumer.Capabilities {
+	return consumer.Capabilities{MutatesData: true}
+}
+
+func (c *receiverMetadataConsumer) ConsumeTraces(ctx context.Context, td ptrace.Traces) error {
+	var (
+		info          = client.FromContext(ctx)
+		protocol      string
+		clientAddress string
+		userAgent     string
+	)
+
+	// the otel grpc servers set the peer and the http servers the client info
+	if p, ok := peer.FromContext(ctx); ok {
+		protocol = "grpc"
+		clientAddress = hostFromAddr(p.Addr)
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			if v := md.Get("user-agent"); len(v) > 0 {
+				userAgent = v[0]
+			}
+		}
+	} else if info.Addr != nil {
+		protocol = "http"
+		clientAddress = hostFromAddr(info.Addr)
+	}
+	if v := info.Metadata.Get("User-Agent"); userAgent == "" && len(v) > 0 {
+		userAgent = v[0]
+	}
+
+	rss := td.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		attrs := rss.At(i).Resource().Attributes()
+		attrs.PutStr(AttributeReceiverName, c.receiver)
+		if protocol != "" {
+			attrs.PutStr(AttributeReceiverProtocol, protocol)
+		}
+		if clientAddress != "" {
+			attrs.PutStr(AttributeReceiverClientAddress, clientAddress)
+		}
+		if userAgent != "" {
+			attrs.PutStr(AttributeReceiverUserAgent, userAgent)
+		}
+	}
+
+	return c.next.ConsumeTraces(ctx, td)
+}
+
+// hostFromAddr returns the ip of the address without the port
+func hostFromAddr(addr net.Addr) string {
+	if addr == nil {
+		return ""
+	}
+
+	s := addr.String()
+	host, _, err := net.SplitHostPort(s)
+	if err != nil {
+		return s
+	}
+	return host
+}
@@ -0,0 +1,84 @@
+package receiver
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/client"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+func TestReceiverMetadataConsumer(t *testing.T) {
+	addr := &net.TCPAddr{IP: net.ParseIP("10.0.0.1"), Port: 4317}
+
+	tcs := []struct {
+		name     string
+		ctx      context.Context
+		expected map[string]any
+	}{
+		{
+			name: "grpc",
+			ctx: metadata.NewIncomingContext(
+				peer.NewContext(context.Background(), &peer.Peer{Addr: addr}),
+				metadata.Pairs("user-agent", "OTel-OTLP-Exporter-Go/1.0"),
+			),
+			expected: map[string]any{
+				AttributeReceiverName:          "otlp",
+				AttributeReceiverProtocol:      "grpc",
+				AttributeReceiverClientAddress: "10.0.0.1",
+				AttributeReceiverUserAgent:     "OTel-OTLP-Exporter-Go/1.0",
+			},
+		},
+		{
+			name: "http",
+			ctx: client.NewContext(context.Background(), client.Info{
+				Addr:     addr,
+				Metadata: client.NewMetadata(map[string][]string{"User-Agent": {"curl/8.0"}}),
+			}),
+			expected: map[string]any{
+				AttributeReceiverName:          "otlp",
+				AttributeReceiverProtocol:      "http",
+				AttributeReceiverClientAddress: "10.0.0.1",
+				AttributeReceiverUserAgent:     "curl/8.0",
+			},
+		},
+		{
+			name: "no request",
+			ctx:  context.Background(),
+			expected: map[string]any{
+				AttributeReceiverName: "otlp",
+			},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			td := ptrace.NewTraces()
+			rs := td.ResourceSpans().AppendEmpty()
+			rs.Resource().Attributes().PutStr("service.name", "test")
+			td.ResourceSpans().AppendEmpty()
+
+			var received ptrace.Traces
+			next := ConsumeTracesFunc(func(_ context.Context, td ptrace.Traces) error {
+				received = td
+				return nil
+			})
+
+			require.NoError(t, newReceiverMetadataConsumer("otlp", next).ConsumeTraces(tc.ctx, td))
+			require.Equal(t, 2, received.ResourceSpans().Len())
+
+			for i := 0; i < received.ResourceSpans().Len(); i++ {
+				attrs := received.ResourceSpans().At(i).Resource().Attributes().AsRaw()
+				for k, v := range tc.expected {
+					require.Equal(t, v, attrs[k])
+				}
+				delete(attrs, "service.name")
+				require.Len(t, attrs, len(tc.expected))
+			}
+		})
+	}
+}
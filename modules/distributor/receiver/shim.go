@@ -140,7 +140,7 @@ func (m *mapProvider) Scheme() string { return "mock" }
 
 func (m *mapProvider) Shutdown(context.Context) error { return nil }
 
-func New(receiverCfg map[string]interface{}, pusher TracesPusher, middleware Middleware, retryAfterDuration time.Duration, logLevel dslog.Level) (services.Service, error) {
+func New(receiverCfg map[string]interface{}, pusher TracesPusher, middleware Middleware, retryAfterDuration time.Duration, receiverMetadata bool, logLevel dslog.Level) (services.Service, error) {
 	shim := &receiversShim{
 		pusher: pusher,
 		logger: log.NewRateLimitedLogger(logsPerSecond, level.Error(log.Logger)),
@@ -270,7 +270,12 @@ func New(receiverCfg map[string]interface{}, pusher TracesPusher, middleware Mid
 			cfg = jaegerRecvCfg
 		}
 
-		receiver, err := factoryBase.CreateTracesReceiver(ctx, params, cfg, middleware.Wrap(shim))
+		var next consumer.Traces = shim
+		if receiverMetadata {
+			next = newReceiverMetadataConsumer(componentID.String(), next)
+		}
+
+		receiver, err := factoryBase.CreateTracesReceiver(ctx, params, cfg, middleware.Wrap(next))
 		if err != nil {
 			return nil, err
 		}
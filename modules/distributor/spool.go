@@ -0,0 +1,279 @@
+package distributor
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/grafana/dskit/services"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/grafana/tempo/modules/overrides"
+	"github.com/grafana/tempo/pkg/tempopb"
+	v1 "github.com/grafana/tempo/pkg/tempopb/trace/v1"
+)
+
+const spoolTmpSuffix = ".tmp"
+
+var errSpoolFull = errors.New("spool is full")
+
+var (
+	metricSpoolBytes = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: "tempo",
+		Name:      "distributor_spool_bytes",
+		Help:      "The size of the batches spooled to disk while they couldn't be written to the ingesters.",
+	})
+	metricSpooledSpans = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "tempo",
+		Name:      "distributor_spooled_spans_total",
+		Help:      "The total number of spans spooled to disk because they couldn't be written to the ingesters.",
+	}, []string{"tenant"})
+	metricSpoolReplayedSpans = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "tempo",
+		Name:      "distributor_spool_replayed_spans_total",
+		Help:      "The total number of spooled spans written to the ingesters.",
+	}, []string{"tenant"})
+)
+
+type SpoolConfig struct {
+	// entries are retried every retry period until they are written or older than the max age. the push is
+	// acknowledged to the client once its batches are spooled
+	Enabled      bool          `yaml:"enabled"`
+	Path         string        `yaml:"path"`
+	MaxSizeBytes int           `yaml:"max_size_bytes"`
+	MaxAge       time.Duration `yaml:"max_age"`
+	RetryPeriod  time.Duration `yaml:"retry_period"`
+}
+
+// spoolSendFunc writes spooled batches to the ingesters
+type spoolSendFunc func(ctx context.Context, userID string, batches []*v1.ResourceSpans) error
+
+// spool is a bounded on-disk queue of the batches that couldn't be written to the ingesters. Each entry is a file
+// named after the time it was spooled so entries are retried in order and survive a restart.
+type spool struct {
+	services.Service
+
+	cfg    SpoolConfig
+	send   spoolSendFunc
+	now    func() time.Time
+	logger log.Logger
+
+	mtx  sync.Mutex
+	size int64
+	seq  uint64
+}
+
+func newSpool(cfg SpoolConfig, send spoolSendFunc, logger log.Logger) (*spool, error) {
+	if cfg.Path == "" {
+		return nil, fmt.Errorf("spool path must be set")
+	}
+	if cfg.MaxSizeBytes <= 0 {
+		return nil, fmt.Errorf("spool max size must be greater than 0")
+	}
+	if cfg.RetryPeriod <= 0 {
+		return nil, fmt.Errorf("spool retry period must be greater than 0")
+	}
+
+	if err := os.MkdirAll(cfg.Path, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create spool directory: %w", err)
+	}
+
+	s := &spool{
+		cfg:    cfg,
+		send:   send,
+		now:    time.Now,
+		logger: logger,
+	}
+
+	// entries spooled before a restart are retried. partially written ones are removed
+	entries, err := os.ReadDir(cfg.Path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read spool directory: %w", err)
+	}
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		if strings.HasSuffix(e.Name(), spoolTmpSuffix) {
+			_ = os.Remove(filepath.Join(cfg.Path, e.Name()))
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read spool entry: %w", err)
+		}
+		s.size += info.Size()
+	}
+	metricSpoolBytes.Set(float64(s.size))
+
+	s.Service = services.NewTimerService(cfg.RetryPeriod, nil, s.iteration, nil)
+	return s, nil
+}
+
+// add spools the batches of the tenant. It returns errSpoolFull if they don't fit in the max size.
+func (s *spool) add(userID string, batches []*v1.ResourceSpans, spanCount int) error {
+	trace, err := (&tempopb.Trace{Batches: batches}).Marshal()
+	if err != nil {
+		return err
+	}
+
+	buff := binary.AppendUvarint(make([]byte, 0, binary.MaxVarintLen64+len(userID)+len(trace)), uint64(len(userID)))
+	buff = append(buff, userID...)
+	buff = append(buff, trace...)
+
+	s.mtx.Lock()
+	if s.size+int64(len(buff)) > int64(s.cfg.MaxSizeBytes) {
+		s.mtx.Unlock()
+		return errSpoolFull
+	}
+	// reserve the space while the entry is written
+	s.size += int64(len(buff))
+	s.seq++
+	name := fmt.Sprintf("%020d-%010d", s.now().UnixNano(), s.seq)
+	s.mtx.Unlock()
+
+	// entries are renamed once written so a crash never leaves a partial entry behind
+	path := filepath.Join(s.cfg.Path, name)
+	err = os.WriteFile(path+spoolTmpSuffix, buff, 0o600)
+	if err == nil {
+		err = os.Rename(path+spoolTmpSuffix, path)
+	}
+	if err != nil {
+		_ = os.Remove(path + spoolTmpSuffix)
+		s.release(int64(len(buff)))
+		return fmt.Errorf("failed to write spool entry: %w", err)
+	}
+
+	metricSpoolBytes.Set(float64(s.currentSize()))
+	metricSpooledSpans.WithLabelValues(userID).Add(float64(spanCount))
+	return nil
+}
+
+func (s *spool) iteration(ctx context.Context) error {
+	s.retry(ctx)
+	// never fail the service. entries are retried on the next iteration
+	return nil
+}
+
+// retry writes the spooled entries to the ingesters from oldest to newest. It stops at the first entry that fails
+// so the ingesters aren't flooded while they are unavailable. Entries older than the max age are dropped.
+func (s *spool) retry(ctx context.Context) {
+	entries, err := os.ReadDir(s.cfg.Path)
+	if err != nil {
+		level.Error(s.logger).Log("msg", "failed to read spool directory", "err", err)
+		return
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+
+	for _, e := range entries {
+		if ctx.Err() != nil {
+			return
+		}
+		if e.IsDir() || strings.HasSuffix(e.Name(), spoolTmpSuffix) {
+			continue
+		}
+
+		path := filepath.Join(s.cfg.Path, e.Name())
+		buff, err := os.ReadFile(path)
+		if err != nil {
+			level.Error(s.logger).Log("msg", "failed to read spool entry", "entry", e.Name(), "err", err)
+			return
+		}
+
+		userID, batches, err := decodeSpoolEntry(buff)
+		if err != nil {
+			level.Error(s.logger).Log("msg", "dropping corrupt spool entry", "entry", e.Name(), "err", err)
+			s.remove(path, len(buff))
+			continue
+		}
+		spanCount := countSpans(batches)
+
+		if s.expired(e.Name()) {
+			level.Warn(s.logger).Log("msg", "dropping spool entry older than the max age", "entry", e.Name(), "tenant", userID, "spans", spanCount)
+			overrides.RecordDiscardedSpans(spanCount, reasonInternalError, userID)
+			s.remove(path, len(buff))
+			continue
+		}
+
+		if err := s.send(ctx, userID, batches); err != nil {
+			level.Warn(s.logger).Log("msg", "failed to write spooled batches to the ingesters", "entry", e.Name(), "tenant", userID, "err", err)
+			return
+		}
+
+		metricSpoolReplayedSpans.WithLabelValues(userID).Add(float64(spanCount))
+		s.remove(path, len(buff))
+	}
+}
+
+// expired returns true if the entry was spooled longer than the max age ago. A max age of 0 keeps entries until
+// they are written.
+func (s *spool) expired(name string) bool {
+	if s.cfg.MaxAge <= 0 {
+		return false
+	}
+
+	ts, _, _ := strings.Cut(name, "-")
+	nanos, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return true
+	}
+	return s.now().Sub(time.Unix(0, nanos)) > s.cfg.MaxAge
+}
+
+func (s *spool) remove(path string, size int) {
+	if err := os.Remove(path); err != nil {
+		level.Error(s.logger).Log("msg", "failed to remove spool entry", "path", path, "err", err)
+		return
+	}
+	s.release(int64(size))
+	metricSpoolBytes.Set(float64(s.currentSize()))
+}
+
+func (s *spool) release(size int64) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	s.size -= size
+}
+
+func (s *spool) currentSize() int64 {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	return s.size
+}
+
+func decodeSpoolEntry(buff []byte) (string, []*v1.ResourceSpans, error) {
+	l, n := binary.Uvarint(buff)
+	if n <= 0 || uint64(len(buff)-n) < l {
+		return "", nil, fmt.Errorf("invalid spool entry header")
+	}
+	userID := string(buff[n : n+int(l)])
+
+	trace := &tempopb.Trace{}
+	if err := trace.Unmarshal(buff[n+int(l):]); err != nil {
+		return "", nil, err
+	}
+	return userID, trace.Batches, nil
+}
+
+func countSpans(batches []*v1.ResourceSpans) int {
+	count := 0
+	for _, b := range batches {
+		for _, ss := range b.ScopeSpans {
+			count += len(ss.Spans)
+		}
+	}
+	return count
+}
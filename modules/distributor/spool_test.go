@@ -0,0 +1,141 @@
+package distributor
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/stretchr/testify/require"
+
+	v1 "github.com/grafana/tempo/pkg/tempopb/trace/v1"
+	"github.com/grafana/tempo/pkg/util/test"
+)
+
+type spooledPush struct {
+	userID  string
+	batches []*v1.ResourceSpans
+}
+
+func TestSpool(t *testing.T) {
+	var (
+		dir    = t.TempDir()
+		now    = time.Unix(1000, 0)
+		failed = true
+		sent   []spooledPush
+	)
+	send := func(_ context.Context, userID string, batches []*v1.ResourceSpans) error {
+		if failed {
+			return errors.New("ingesters unavailable")
+		}
+		sent = append(sent, spooledPush{userID: userID, batches: batches})
+		return nil
+	}
+
+	cfg := SpoolConfig{
+		Enabled:      true,
+		Path:         dir,
+		MaxSizeBytes: 100_000,
+		MaxAge:       time.Minute,
+		RetryPeriod:  time.Second,
+	}
+	s, err := newSpool(cfg, send, log.NewNopLogger())
+	require.NoError(t, err)
+	s.now = func() time.Time { return now }
+
+	first := []*v1.ResourceSpans{test.MakeBatch(2, nil)}
+	second := []*v1.ResourceSpans{test.MakeBatch(3, nil)}
+	require.NoError(t, s.add("a", first, 2))
+	now = now.Add(time.Second)
+	require.NoError(t, s.add("b", second, 3))
+	size := s.currentSize()
+	require.Greater(t, size, int64(0))
+
+	// entries are kept while the ingesters are unavailable
+	s.retry(context.Background())
+	require.Empty(t, sent)
+	require.Equal(t, size, s.currentSize())
+
+	// entries and their size are restored on restart
+	s, err = newSpool(cfg, send, log.NewNopLogger())
+	require.NoError(t, err)
+	s.now = func() time.Time { return now }
+	require.Equal(t, size, s.currentSize())
+
+	// entries are written in order and removed
+	failed = false
+	s.retry(context.Background())
+	require.Len(t, sent, 2)
+	require.Equal(t, "a", sent[0].userID)
+	require.Equal(t, first, sent[0].batches)
+	require.Equal(t, "b", sent[1].userID)
+	require.Equal(t, second, sent[1].batches)
+	require.Zero(t, s.currentSize())
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Empty(t, entries)
+}
+
+func TestSpoolMaxSize(t *testing.T) {
+	batches := []*v1.ResourceSpans{test.MakeBatch(10, nil)}
+
+	s, err := newSpool(SpoolConfig{
+		Path:         t.TempDir(),
+		MaxSizeBytes: 1,
+		RetryPeriod:  time.Second,
+	}, nil, log.NewNopLogger())
+	require.NoError(t, err)
+
+	require.ErrorIs(t, s.add("test", batches, 10), errSpoolFull)
+	require.Zero(t, s.currentSize())
+}
+
+func TestSpoolMaxAge(t *testing.T) {
+	var (
+		dir  = t.TempDir()
+		now  = time.Unix(1000, 0)
+		sent int
+	)
+	send := func(context.Context, string, []*v1.ResourceSpans) error {
+		sent++
+		return nil
+	}
+
+	s, err := newSpool(SpoolConfig{
+		Path:         dir,
+		MaxSizeBytes: 100_000,
+		MaxAge:       time.Minute,
+		RetryPeriod:  time.Second,
+	}, send, log.NewNopLogger())
+	require.NoError(t, err)
+	s.now = func() time.Time { return now }
+
+	require.NoError(t, s.add("test", []*v1.ResourceSpans{test.MakeBatch(1, nil)}, 1))
+
+	// expired entries are dropped instead of written
+	now = now.Add(2 * time.Minute)
+	s.retry(context.Background())
+	require.Zero(t, sent)
+	require.Zero(t, s.currentSize())
+}
+
+func TestSpoolRemovesPartialEntries(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "00000000000000000001-0000000001"+spoolTmpSuffix), []byte("partial"), 0o600))
+
+	s, err := newSpool(SpoolConfig{
+		Path:         dir,
+		MaxSizeBytes: 100_000,
+		RetryPeriod:  time.Second,
+	}, nil, log.NewNopLogger())
+	require.NoError(t, err)
+	require.Zero(t, s.currentSize())
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Empty(t, entries)
+}
@@ -0,0 +1,133 @@
+package distributor
+
+import (
+	"context"
+	"io"
+	"sync"
+
+	"github.com/gogo/status"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"google.golang.org/grpc/codes"
+
+	"github.com/grafana/tempo/pkg/boundedwaitgroup"
+	"github.com/grafana/tempo/pkg/tempopb"
+	v1 "github.com/grafana/tempo/pkg/tempopb/trace/v1"
+)
+
+const defaultStreamingPushMaxInFlight = 16
+
+var (
+	metricPushStreams = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: "tempo",
+		Name:      "distributor_push_streams",
+		Help:      "The number of open streams pushing traces to the distributor.",
+	})
+	metricPushStreamRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "tempo",
+		Name:      "distributor_push_stream_requests_total",
+		Help:      "The total number of requests received over push streams by status code.",
+	}, []string{"code"})
+)
+
+type StreamingPushConfig struct {
+	// enables the StreamingPusher grpc service that agents can push spans to over a long-lived stream
+	Enabled bool `yaml:"enabled"`
+	// the number of requests of a stream processed concurrently. the stream isn't read while the limit is reached
+	MaxInFlight int `yaml:"max_in_flight"`
+}
+
+// streamingPusher pushes the span batches received over a stream through the distributor
+type streamingPusher struct {
+	d           *Distributor
+	maxInFlight int
+}
+
+// StreamingPusher returns the tempopb.StreamingPusherServer of the distributor
+func (d *Distributor) StreamingPusher() tempopb.StreamingPusherServer {
+	maxInFlight := d.cfg.StreamingPush.MaxInFlight
+	if maxInFlight <= 0 {
+		maxInFlight = 1
+	}
+
+	return &streamingPusher{
+		d:           d,
+		maxInFlight: maxInFlight,
+	}
+}
+
+// PushTraces implements tempopb.StreamingPusherServer. Requests are pushed concurrently and acknowledged in the order
+// they complete.
+func (p *streamingPusher) PushTraces(stream tempopb.StreamingPusher_PushTracesServer) error {
+	metricPushStreams.Inc()
+	defer metricPushStreams.Dec()
+
+	var (
+		ctx     = stream.Context()
+		wg      = boundedwaitgroup.New(uint(p.maxInFlight))
+		sendMtx sync.Mutex
+		sendErr error
+	)
+	// responses can't be sent once the stream returned
+	defer wg.Wait()
+
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		// blocks while the max in flight requests are processed which stops reading the stream and pushes back on
+		// the client through the grpc flow control
+		wg.Add(1)
+
+		sendMtx.Lock()
+		err = sendErr
+		sendMtx.Unlock()
+		if err != nil {
+			wg.Done()
+			return err
+		}
+
+		go func() {
+			defer wg.Done()
+
+			resp := &tempopb.PushTracesResponse{Id: req.Id}
+			if err := p.push(ctx, req.Batches); err != nil {
+				st, _ := status.FromError(err)
+				resp.ErrorCode = uint32(st.Code())
+				resp.ErrorMessage = st.Message()
+			}
+			metricPushStreamRequests.WithLabelValues(codes.Code(resp.ErrorCode).String()).Inc()
+
+			sendMtx.Lock()
+			defer sendMtx.Unlock()
+			if sendErr == nil {
+				sendErr = stream.Send(resp)
+			}
+		}()
+	}
+
+	wg.Wait()
+	return sendErr
+}
+
+func (p *streamingPusher) push(ctx context.Context, batches []*v1.ResourceSpans) error {
+	// tempopb.Trace is wire-compatible with ExportTraceServiceRequest
+	buff, err := (&tempopb.Trace{Batches: batches}).Marshal()
+	if err != nil {
+		return err
+	}
+
+	traces, err := (&ptrace.ProtoUnmarshaler{}).UnmarshalTraces(buff)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid batches: %s", err)
+	}
+
+	_, err = p.d.PushTraces(ctx, traces)
+	return err
+}
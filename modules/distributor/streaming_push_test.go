@@ -0,0 +1,83 @@
+package distributor
+
+import (
+	"context"
+	"flag"
+	"io"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+
+	"github.com/grafana/tempo/modules/overrides"
+	"github.com/grafana/tempo/pkg/tempopb"
+	v1 "github.com/grafana/tempo/pkg/tempopb/trace/v1"
+	"github.com/grafana/tempo/pkg/util/test"
+)
+
+type mockPushTracesStream struct {
+	grpc.ServerStream
+
+	ctx       context.Context
+	requests  []*tempopb.PushTracesRequest
+	responses []*tempopb.PushTracesResponse
+}
+
+func (s *mockPushTracesStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *mockPushTracesStream) Recv() (*tempopb.PushTracesRequest, error) {
+	if len(s.requests) == 0 {
+		return nil, io.EOF
+	}
+	req := s.requests[0]
+	s.requests = s.requests[1:]
+	return req, nil
+}
+
+func (s *mockPushTracesStream) Send(resp *tempopb.PushTracesResponse) error {
+	s.responses = append(s.responses, resp)
+	return nil
+}
+
+func TestStreamingPush(t *testing.T) {
+	limits := overrides.Config{}
+	limits.RegisterFlagsAndApplyDefaults(&flag.FlagSet{})
+
+	for _, maxInFlight := range []int{0, 1, 4} {
+		d := prepare(t, limits, nil)
+		d.cfg.StreamingPush.MaxInFlight = maxInFlight
+
+		invalid := test.MakeBatch(1, nil)
+		invalid.ScopeSpans[0].Spans[0].TraceId = []byte{0x01}
+		stream := &mockPushTracesStream{ctx: ctx}
+		for i := 0; i < 10; i++ {
+			batches := []*v1.ResourceSpans{test.MakeBatch(5, []byte{})}
+			if i == 3 {
+				batches = []*v1.ResourceSpans{invalid}
+			}
+			stream.requests = append(stream.requests, &tempopb.PushTracesRequest{Id: uint64(i), Batches: batches})
+		}
+
+		require.NoError(t, d.StreamingPusher().PushTraces(stream))
+
+		// every request is acknowledged
+		require.Len(t, stream.responses, 10)
+		sort.Slice(stream.responses, func(i, j int) bool {
+			return stream.responses[i].Id < stream.responses[j].Id
+		})
+		for i, resp := range stream.responses {
+			require.Equal(t, uint64(i), resp.Id)
+			if i == 3 {
+				require.Equal(t, uint32(codes.InvalidArgument), resp.ErrorCode)
+				require.NotEmpty(t, resp.ErrorMessage)
+				continue
+			}
+			require.Zero(t, resp.ErrorCode)
+			require.Empty(t, resp.ErrorMessage)
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package frontend
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/grafana/dskit/user"
+	jsoniter "github.com/json-iterator/go"
+
+	"github.com/grafana/tempo/pkg/api"
+	"github.com/grafana/tempo/tempodb"
+	"github.com/grafana/tempo/tempodb/encoding/common"
+)
+
+const (
+	maxAnnotationTextLength  = 4096
+	maxAnnotationsPerTrace   = 100
+	maxAnnotationRequestSize = 16 * 1024
+)
+
+// annotationsStore reads and writes the annotations of traces. It is implemented by tempodb.
+type annotationsStore interface {
+	TraceAnnotations(ctx context.Context, tenantID string, traceID common.ID) ([]*tempodb.Annotation, error)
+	AddTraceAnnotation(ctx context.Context, tenantID string, traceID common.ID, a *tempodb.Annotation) error
+}
+
+type annotationRequest struct {
+	Text   string `json:"text"`
+	Author string `json:"author"`
+}
+
+type annotationsResponse struct {
+	Annotations []*tempodb.Annotation `json:"annotations"`
+}
+
+// AnnotationsHandler lists the annotations of a trace on GET and adds an annotation to the trace on POST.
+type AnnotationsHandler struct {
+	store  annotationsStore
+	now    func() time.Time
+	logger log.Logger
+}
+
+func NewAnnotationsHandler(store annotationsStore, logger log.Logger) *AnnotationsHandler {
+	return &AnnotationsHandler{
+		store:  store,
+		now:    time.Now,
+		logger: logger,
+	}
+}
+
+// ServeHTTP implements http.Handler
+func (h *AnnotationsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	tenantID, err := user.ExtractOrgID(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	traceID, err := api.ParseTraceID(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		h.list(w, r, tenantID, traceID)
+	case http.MethodPost:
+		h.add(w, r, tenantID, traceID)
+	default:
+		w.Header().Set("Allow", "GET, POST")
+		http.Error(w, fmt.Sprintf("method %s not allowed", r.Method), http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *AnnotationsHandler) list(w http.ResponseWriter, r *http.Request, tenantID string, traceID common.ID) {
+	annotations, err := h.store.TraceAnnotations(r.Context(), tenantID, traceID)
+	if err != nil {
+		level.Error(h.logger).Log("msg", "failed to read trace annotations", "tenant", tenantID, "err", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if annotations == nil {
+		annotations = []*tempodb.Annotation{}
+	}
+
+	writeAnnotationsJSON(w, http.StatusOK, &annotationsResponse{Annotations: annotations})
+}
+
+func (h *AnnotationsHandler) add(w http.ResponseWriter, r *http.Request, tenantID string, traceID common.ID) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxAnnotationRequestSize+1))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if len(body) > maxAnnotationRequestSize {
+		http.Error(w, errRequestEntityTooLarge.Error(), http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	req := &annotationRequest{}
+	if err := jsoniter.Unmarshal(body, req); err != nil {
+		http.Error(w, fmt.Sprintf("invalid annotation: %s", err), http.StatusBadRequest)
+		return
+	}
+	req.Text = strings.TrimSpace(req.Text)
+	if req.Text == "" {
+		http.Error(w, "annotation text is required", http.StatusBadRequest)
+		return
+	}
+	if len(req.Text) > maxAnnotationTextLength {
+		http.Error(w, fmt.Sprintf("annotation text is longer than %d bytes", maxAnnotationTextLength), http.StatusBadRequest)
+		return
+	}
+
+	existing, err := h.store.TraceAnnotations(r.Context(), tenantID, traceID)
+	if err != nil {
+		level.Error(h.logger).Log("msg", "failed to read trace annotations", "tenant", tenantID, "err", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if len(existing) >= maxAnnotationsPerTrace {
+		http.Error(w, fmt.Sprintf("trace has the maximum of %d annotations", maxAnnotationsPerTrace), http.StatusBadRequest)
+		return
+	}
+
+	annotation := &tempodb.Annotation{
+		Text:      req.Text,
+		Author:    req.Author,
+		CreatedAt: h.now().UTC(),
+	}
+	err = h.store.AddTraceAnnotation(r.Context(), tenantID, traceID, annotation)
+	if err != nil {
+		level.Error(h.logger).Log("msg", "failed to add trace annotation", "tenant", tenantID, "err", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	writeAnnotationsJSON(w, http.StatusOK, annotation)
+}
+
+func writeAnnotationsJSON(w http.ResponseWriter, status int, v interface{}) {
+	buff, err := jsoniter.Marshal(v)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set(api.HeaderContentType, api.HeaderAcceptJSON)
+	w.WriteHeader(status)
+	_, _ = w.Write(buff)
+}
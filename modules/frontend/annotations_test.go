@@ -0,0 +1,97 @@
+package frontend
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/gorilla/mux"
+	"github.com/grafana/dskit/user"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/tempodb"
+	"github.com/grafana/tempo/tempodb/encoding/common"
+)
+
+type mockAnnotationsStore struct {
+	annotations map[string][]*tempodb.Annotation
+}
+
+func (m *mockAnnotationsStore) TraceAnnotations(_ context.Context, tenantID string, traceID common.ID) ([]*tempodb.Annotation, error) {
+	return m.annotations[tenantID+string(traceID)], nil
+}
+
+func (m *mockAnnotationsStore) AddTraceAnnotation(_ context.Context, tenantID string, traceID common.ID, a *tempodb.Annotation) error {
+	m.annotations[tenantID+string(traceID)] = append(m.annotations[tenantID+string(traceID)], a)
+	return nil
+}
+
+func TestAnnotationsHandler(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	store := &mockAnnotationsStore{annotations: map[string][]*tempodb.Annotation{}}
+	h := NewAnnotationsHandler(store, log.NewNopLogger())
+	h.now = func() time.Time { return now }
+
+	do := func(method, tenant, traceID, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/api/traces/"+traceID+"/annotations", strings.NewReader(body))
+		req = mux.SetURLVars(req, map[string]string{"traceID": traceID})
+		if tenant != "" {
+			req = req.WithContext(user.InjectOrgID(req.Context(), tenant))
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// no annotations
+	rec := do(http.MethodGet, "test", "1234", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.JSONEq(t, `{"annotations":[]}`, rec.Body.String())
+
+	rec = do(http.MethodPost, "test", "1234", `{"text":" root cause ","author":"alice"}`)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.JSONEq(t, `{"text":"root cause","author":"alice","createdAt":"2024-01-01T00:00:00Z"}`, rec.Body.String())
+
+	rec = do(http.MethodGet, "test", "1234", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.JSONEq(t, `{"annotations":[{"text":"root cause","author":"alice","createdAt":"2024-01-01T00:00:00Z"}]}`, rec.Body.String())
+
+	// annotations are per tenant
+	rec = do(http.MethodGet, "other", "1234", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.JSONEq(t, `{"annotations":[]}`, rec.Body.String())
+
+	tcs := []struct {
+		name     string
+		method   string
+		tenant   string
+		traceID  string
+		body     string
+		expected int
+	}{
+		{name: "no tenant", method: http.MethodGet, traceID: "1234", expected: http.StatusBadRequest},
+		{name: "invalid trace id", method: http.MethodGet, tenant: "test", traceID: "zz", expected: http.StatusBadRequest},
+		{name: "invalid body", method: http.MethodPost, tenant: "test", traceID: "1234", body: "{", expected: http.StatusBadRequest},
+		{name: "empty text", method: http.MethodPost, tenant: "test", traceID: "1234", body: `{"text":"  "}`, expected: http.StatusBadRequest},
+		{name: "text too long", method: http.MethodPost, tenant: "test", traceID: "1234", body: `{"text":"` + strings.Repeat("a", maxAnnotationTextLength+1) + `"}`, expected: http.StatusBadRequest},
+		{name: "body too large", method: http.MethodPost, tenant: "test", traceID: "1234", body: string(bytes.Repeat([]byte(" "), maxAnnotationRequestSize+1)), expected: http.StatusRequestEntityTooLarge},
+		{name: "method not allowed", method: http.MethodDelete, tenant: "test", traceID: "1234", expected: http.StatusMethodNotAllowed},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := do(tc.method, tc.tenant, tc.traceID, tc.body)
+			require.Equal(t, tc.expected, rec.Code)
+		})
+	}
+
+	// too many annotations
+	for i := 1; i < maxAnnotationsPerTrace; i++ {
+		require.Equal(t, http.StatusOK, do(http.MethodPost, "test", "1234", `{"text":"note"}`).Code)
+	}
+	require.Equal(t, http.StatusBadRequest, do(http.MethodPost, "test", "1234", `{"text":"note"}`).Code)
+}
@@ -0,0 +1,285 @@
+package frontend
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/go-kit/log" //nolint:all //deprecated
+	"github.com/go-kit/log/level"
+	"github.com/gorilla/mux"
+	"github.com/grafana/dskit/httpgrpc"
+	"github.com/grafana/dskit/user"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	AuditLogSinkLog     = "log"
+	AuditLogSinkWebhook = "webhook"
+
+	auditWebhookQueueSize = 1000
+)
+
+var metricAuditEventsDropped = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "tempo",
+	Name:      "query_frontend_audit_events_dropped_total",
+	Help:      "Total audit events that were not delivered to the webhook by reason.",
+}, []string{"reason"})
+
+type AuditLogConfig struct {
+	// Enabled records every query with the tenant, user, endpoint, trace id or query and the status of the response
+	Enabled bool `yaml:"enabled"`
+	// Sink is where events are written to. log writes them to the tempo log, webhook posts them as json to WebhookURL
+	Sink string `yaml:"sink"`
+	// WebhookURL receives a POST with a json body for each event
+	WebhookURL string `yaml:"webhook_url,omitempty"`
+	// WebhookTimeout is the maximum time to wait for the webhook per event
+	WebhookTimeout time.Duration `yaml:"webhook_timeout,omitempty"`
+	// UserHeader is the header or grpc metadata key set by the authentication proxy in front of tempo with the
+	// authenticated user
+	UserHeader string `yaml:"user_header"`
+}
+
+func (cfg *AuditLogConfig) Validate() error {
+	if !cfg.Enabled {
+		return nil
+	}
+
+	switch cfg.Sink {
+	case AuditLogSinkLog:
+	case AuditLogSinkWebhook:
+		u, err := url.Parse(cfg.WebhookURL)
+		if err != nil {
+			return fmt.Errorf("audit log webhook url is invalid: %w", err)
+		}
+		if u.Scheme != "http" && u.Scheme != "https" {
+			return errors.New("audit log webhook url must be an http or https url")
+		}
+	default:
+		return fmt.Errorf("audit log sink %s is invalid. must be one of %s or %s", cfg.Sink, AuditLogSinkLog, AuditLogSinkWebhook)
+	}
+
+	return nil
+}
+
+// auditEvent records a single query
+type auditEvent struct {
+	Time     time.Time `json:"time"`
+	Tenant   string    `json:"tenant"`
+	User     string    `json:"user,omitempty"`
+	Endpoint string    `json:"endpoint"`
+	TraceID  string    `json:"trace_id,omitempty"`
+	Query    string    `json:"query,omitempty"`
+	Status   int       `json:"status"`
+}
+
+type auditSink interface {
+	write(e auditEvent)
+}
+
+// auditLogger records the queries received by the frontend. a nil auditLogger records nothing
+type auditLogger struct {
+	sink       auditSink
+	userHeader string
+	now        func() time.Time
+}
+
+// newAuditLogger returns nil if the audit log is disabled
+func newAuditLogger(cfg AuditLogConfig, logger log.Logger) *auditLogger {
+	if !cfg.Enabled {
+		return nil
+	}
+
+	var sink auditSink
+	switch cfg.Sink {
+	case AuditLogSinkWebhook:
+		sink = newAuditWebhookSink(cfg.WebhookURL, cfg.WebhookTimeout, logger)
+	default:
+		sink = &auditLogSink{logger: logger}
+	}
+
+	return &auditLogger{
+		sink:       sink,
+		userHeader: cfg.UserHeader,
+		now:        time.Now,
+	}
+}
+
+// recordHTTP records a query received over http. the trace id is taken from the path and the query from
+// the query parameters of the request
+func (a *auditLogger) recordHTTP(r *http.Request, statusCode int) {
+	if a == nil {
+		return
+	}
+
+	endpoint := r.URL.Path
+	if route := mux.CurrentRoute(r); route != nil {
+		if tmpl, err := route.GetPathTemplate(); err == nil {
+			endpoint = tmpl
+		}
+	}
+
+	e := a.event(r.Context(), endpoint)
+	if a.userHeader != "" {
+		e.User = r.Header.Get(a.userHeader)
+	}
+	e.TraceID = mux.Vars(r)["traceID"]
+	params := r.URL.Query()
+	for _, p := range []string{"q", "query", "tags"} {
+		if v := params.Get(p); v != "" {
+			e.Query = v
+			break
+		}
+	}
+	e.Status = statusCode
+
+	a.sink.write(e)
+}
+
+// recordGRPC records a query received over grpc. the status is derived from the error returned by the handler
+func (a *auditLogger) recordGRPC(ctx context.Context, endpoint string, traceID []byte, query string, err error) {
+	if a == nil {
+		return
+	}
+
+	e := a.event(ctx, endpoint)
+	if a.userHeader != "" {
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			if v := md.Get(a.userHeader); len(v) > 0 {
+				e.User = v[0]
+			}
+		}
+	}
+	if len(traceID) > 0 {
+		e.TraceID = fmt.Sprintf("%x", traceID)
+	}
+	e.Query = query
+	e.Status = grpcErrorToHTTPStatus(err)
+
+	a.sink.write(e)
+}
+
+func (a *auditLogger) event(ctx context.Context, endpoint string) auditEvent {
+	tenant, _ := user.ExtractOrgID(ctx)
+	return auditEvent{
+		Time:     a.now(),
+		Tenant:   tenant,
+		Endpoint: endpoint,
+	}
+}
+
+// grpcErrorToHTTPStatus returns the http status code equivalent of the error returned by a grpc handler so
+// events of both apis can be compared
+func grpcErrorToHTTPStatus(err error) int {
+	if err == nil {
+		return http.StatusOK
+	}
+	if resp, ok := httpgrpc.HTTPResponseFromError(err); ok {
+		return int(resp.Code)
+	}
+	if errors.Is(err, context.Canceled) {
+		return StatusClientClosedRequest
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return http.StatusGatewayTimeout
+	}
+
+	switch status.Code(err) {
+	case codes.InvalidArgument:
+		return http.StatusBadRequest
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.Canceled:
+		return StatusClientClosedRequest
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+type auditLogSink struct {
+	logger log.Logger
+}
+
+func (s *auditLogSink) write(e auditEvent) {
+	level.Info(s.logger).Log(
+		"msg", "query audit",
+		"tenant", e.Tenant,
+		"user", e.User,
+		"endpoint", e.Endpoint,
+		"trace_id", e.TraceID,
+		"query", e.Query,
+		"status", e.Status)
+}
+
+// auditWebhookSink posts events to a webhook in the background so a slow webhook doesn't delay queries. events
+// are dropped if the queue is full.
+type auditWebhookSink struct {
+	url    string
+	client *http.Client
+	queue  chan auditEvent
+	logger log.Logger
+}
+
+func newAuditWebhookSink(webhookURL string, timeout time.Duration, logger log.Logger) *auditWebhookSink {
+	s := &auditWebhookSink{
+		url:    webhookURL,
+		client: &http.Client{Timeout: timeout},
+		queue:  make(chan auditEvent, auditWebhookQueueSize),
+		logger: logger,
+	}
+	go s.run()
+	return s
+}
+
+func (s *auditWebhookSink) write(e auditEvent) {
+	select {
+	case s.queue <- e:
+	default:
+		metricAuditEventsDropped.WithLabelValues("queue_full").Inc()
+	}
+}
+
+func (s *auditWebhookSink) run() {
+	for e := range s.queue {
+		err := s.post(e)
+		if err != nil {
+			metricAuditEventsDropped.WithLabelValues("failed").Inc()
+			level.Warn(s.logger).Log("msg", "failed to send audit event to webhook", "tenant", e.Tenant, "endpoint", e.Endpoint, "err", err)
+		}
+	}
+}
+
+func (s *auditWebhookSink) post(e auditEvent) error {
+	body, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.client.Post(s.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+	return nil
+}
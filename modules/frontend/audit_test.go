@@ -0,0 +1,161 @@
+package frontend
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/gorilla/mux"
+	"github.com/grafana/dskit/httpgrpc"
+	"github.com/grafana/dskit/user"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/grafana/tempo/modules/frontend/pipeline"
+	"github.com/grafana/tempo/pkg/api"
+)
+
+type mockAuditSink struct {
+	mtx    sync.Mutex
+	events []auditEvent
+}
+
+func (s *mockAuditSink) write(e auditEvent) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.events = append(s.events, e)
+}
+
+func TestAuditLogConfigValidate(t *testing.T) {
+	tcs := []struct {
+		name string
+		cfg  AuditLogConfig
+		err  string
+	}{
+		{
+			name: "disabled",
+			cfg:  AuditLogConfig{Sink: "unknown"},
+		},
+		{
+			name: "log",
+			cfg:  AuditLogConfig{Enabled: true, Sink: AuditLogSinkLog},
+		},
+		{
+			name: "webhook",
+			cfg:  AuditLogConfig{Enabled: true, Sink: AuditLogSinkWebhook, WebhookURL: "https://audit.example.com/tempo"},
+		},
+		{
+			name: "webhook without url",
+			cfg:  AuditLogConfig{Enabled: true, Sink: AuditLogSinkWebhook},
+			err:  "audit log webhook url must be an http or https url",
+		},
+		{
+			name: "unknown sink",
+			cfg:  AuditLogConfig{Enabled: true, Sink: "kafka"},
+			err:  "audit log sink kafka is invalid. must be one of log or webhook",
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.cfg.Validate()
+			if tc.err == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.EqualError(t, err, tc.err)
+		})
+	}
+}
+
+func TestAuditLogHTTP(t *testing.T) {
+	sink := &mockAuditSink{}
+	audit := &auditLogger{sink: sink, userHeader: "X-Grafana-User", now: time.Now}
+
+	next := pipeline.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		if strings.Contains(req.URL.Path, "fail") {
+			return nil, httpgrpc.Errorf(http.StatusBadRequest, "bad request")
+		}
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(""))}, nil
+	})
+
+	router := mux.NewRouter()
+	router.Handle(api.PathTraces, newHandler(nil, next, audit, nil, log.NewNopLogger()))
+	router.Handle(api.PathSearch, newHandler(nil, next, audit, nil, log.NewNopLogger()))
+	router.Handle("/fail", newHandler(nil, next, audit, nil, log.NewNopLogger()))
+
+	for _, uri := range []string{"/api/traces/1234", "/api/search?q=%7B%7D", "/fail?tags=foo%3Dbar"} {
+		req := httptest.NewRequest(http.MethodGet, uri, nil)
+		req.Header.Set("X-Grafana-User", "admin")
+		req = req.WithContext(user.InjectOrgID(req.Context(), "test"))
+		router.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	require.Len(t, sink.events, 3)
+	for _, e := range sink.events {
+		require.Equal(t, "test", e.Tenant)
+		require.Equal(t, "admin", e.User)
+	}
+
+	require.Equal(t, api.PathTraces, sink.events[0].Endpoint)
+	require.Equal(t, "1234", sink.events[0].TraceID)
+	require.Equal(t, http.StatusOK, sink.events[0].Status)
+
+	require.Equal(t, api.PathSearch, sink.events[1].Endpoint)
+	require.Equal(t, "{}", sink.events[1].Query)
+
+	require.Equal(t, "/fail", sink.events[2].Endpoint)
+	require.Equal(t, "foo=bar", sink.events[2].Query)
+	require.Equal(t, http.StatusBadRequest, sink.events[2].Status)
+}
+
+func TestAuditLogGRPC(t *testing.T) {
+	sink := &mockAuditSink{}
+	audit := &auditLogger{sink: sink, userHeader: "X-Grafana-User", now: time.Now}
+
+	ctx := user.InjectOrgID(context.Background(), "test")
+	ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("x-grafana-user", "admin"))
+
+	audit.recordGRPC(ctx, "/tempopb.StreamingQuerier/FindTraceByID", []byte{0x12, 0x34}, "", nil)
+	audit.recordGRPC(ctx, "/tempopb.StreamingQuerier/Search", nil, "{}", status.Error(codes.InvalidArgument, "invalid"))
+
+	require.Equal(t, []auditEvent{
+		{Time: sink.events[0].Time, Tenant: "test", User: "admin", Endpoint: "/tempopb.StreamingQuerier/FindTraceByID", TraceID: "1234", Status: http.StatusOK},
+		{Time: sink.events[1].Time, Tenant: "test", User: "admin", Endpoint: "/tempopb.StreamingQuerier/Search", Query: "{}", Status: http.StatusBadRequest},
+	}, sink.events)
+
+	// a nil audit logger records nothing
+	var disabled *auditLogger
+	disabled.recordGRPC(ctx, "/tempopb.StreamingQuerier/Search", nil, "{}", nil)
+}
+
+func TestAuditLogWebhook(t *testing.T) {
+	received := make(chan auditEvent, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var e auditEvent
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&e))
+		received <- e
+	}))
+	defer srv.Close()
+
+	audit := newAuditLogger(AuditLogConfig{Enabled: true, Sink: AuditLogSinkWebhook, WebhookURL: srv.URL, WebhookTimeout: time.Second}, log.NewNopLogger())
+	audit.recordGRPC(user.InjectOrgID(context.Background(), "test"), "/tempopb.StreamingQuerier/Search", nil, "{}", context.Canceled)
+
+	select {
+	case e := <-received:
+		require.Equal(t, "test", e.Tenant)
+		require.Equal(t, "{}", e.Query)
+		require.Equal(t, StatusClientClosedRequest, e.Status)
+	case <-time.After(5 * time.Second):
+		t.Fatal("webhook didn't receive the event")
+	}
+}
@@ -0,0 +1,206 @@
+package frontend
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/grafana/dskit/user"
+
+	"github.com/grafana/tempo/pkg/api"
+	"github.com/grafana/tempo/tempodb"
+	"github.com/grafana/tempo/tempodb/backend"
+)
+
+// blocksStore reads the metas of the blocks and their quarantine. It is implemented by tempodb.
+type blocksStore interface {
+	BlockMetas(tenantID string) []*backend.BlockMeta
+	BlockMeta(ctx context.Context, tenantID string, blockID uuid.UUID) (*backend.BlockMeta, error)
+	QuarantinedBlocks(ctx context.Context, tenantID string) ([]*tempodb.QuarantinedBlock, error)
+	ReleaseQuarantinedBlock(ctx context.Context, tenantID string, blockID uuid.UUID) error
+}
+
+type blockSummary struct {
+	BlockID         uuid.UUID        `json:"blockID"`
+	StartTime       time.Time        `json:"startTime"`
+	EndTime         time.Time        `json:"endTime"`
+	TotalObjects    int              `json:"totalObjects"`
+	Size            uint64           `json:"size"`
+	CompactionLevel uint8            `json:"compactionLevel"`
+	Encoding        backend.Encoding `json:"encoding"`
+	Version         string           `json:"format"`
+	Quarantined     bool             `json:"quarantined,omitempty"`
+}
+
+type blocksResponse struct {
+	Blocks []*blockSummary `json:"blocks"`
+}
+
+// BlocksHandler lists the blocks of the tenant in the blocklist of the query-frontend and returns the meta of a single
+// block as stored in the backend, so operators can find out why a trace isn't found without access to the bucket.
+type BlocksHandler struct {
+	store  blocksStore
+	logger log.Logger
+}
+
+func NewBlocksHandler(store blocksStore, logger log.Logger) *BlocksHandler {
+	return &BlocksHandler{
+		store:  store,
+		logger: logger,
+	}
+}
+
+// ServeHTTP implements http.Handler
+func (h *BlocksHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	tenantID, err := user.ExtractOrgID(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", "GET")
+		http.Error(w, fmt.Sprintf("method %s not allowed", r.Method), http.StatusMethodNotAllowed)
+		return
+	}
+
+	if _, ok := mux.Vars(r)[api.URLParamBlockID]; ok {
+		h.block(w, r, tenantID)
+		return
+	}
+	h.list(w, r, tenantID)
+}
+
+func (h *BlocksHandler) list(w http.ResponseWriter, r *http.Request, tenantID string) {
+	metas := h.store.BlockMetas(tenantID)
+
+	quarantined, err := h.store.QuarantinedBlocks(r.Context(), tenantID)
+	if err != nil {
+		level.Error(h.logger).Log("msg", "failed to read quarantined blocks", "tenant", tenantID, "err", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	isQuarantined := make(map[uuid.UUID]bool, len(quarantined))
+	for _, q := range quarantined {
+		isQuarantined[q.BlockID] = true
+	}
+
+	blocks := make([]*blockSummary, 0, len(metas))
+	for _, m := range metas {
+		blocks = append(blocks, &blockSummary{
+			BlockID:         m.BlockID,
+			StartTime:       m.StartTime,
+			EndTime:         m.EndTime,
+			TotalObjects:    m.TotalObjects,
+			Size:            m.Size,
+			CompactionLevel: m.CompactionLevel,
+			Encoding:        m.Encoding,
+			Version:         m.Version,
+			Quarantined:     isQuarantined[m.BlockID],
+		})
+	}
+	sort.Slice(blocks, func(i, j int) bool {
+		return blocks[i].StartTime.Before(blocks[j].StartTime)
+	})
+
+	writeAnnotationsJSON(w, http.StatusOK, &blocksResponse{Blocks: blocks})
+}
+
+func (h *BlocksHandler) block(w http.ResponseWriter, r *http.Request, tenantID string) {
+	blockID, err := api.ParseBlockID(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	meta, err := h.store.BlockMeta(r.Context(), tenantID, blockID)
+	if errors.Is(err, backend.ErrDoesNotExist) {
+		http.Error(w, fmt.Sprintf("block %s not found", blockID), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		level.Error(h.logger).Log("msg", "failed to read block meta", "tenant", tenantID, "block", blockID, "err", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	writeAnnotationsJSON(w, http.StatusOK, meta)
+}
+
+// BlockQuarantineHandler returns why a block is quarantined on GET and releases the block on DELETE. Released blocks
+// are read again by the queries and the compactions, and are quarantined again if they are still corrupted.
+type BlockQuarantineHandler struct {
+	store  blocksStore
+	logger log.Logger
+}
+
+func NewBlockQuarantineHandler(store blocksStore, logger log.Logger) *BlockQuarantineHandler {
+	return &BlockQuarantineHandler{
+		store:  store,
+		logger: logger,
+	}
+}
+
+// ServeHTTP implements http.Handler
+func (h *BlockQuarantineHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	tenantID, err := user.ExtractOrgID(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	blockID, err := api.ParseBlockID(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		h.get(w, r, tenantID, blockID)
+	case http.MethodDelete:
+		h.release(w, r, tenantID, blockID)
+	default:
+		w.Header().Set("Allow", "GET, DELETE")
+		http.Error(w, fmt.Sprintf("method %s not allowed", r.Method), http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *BlockQuarantineHandler) get(w http.ResponseWriter, r *http.Request, tenantID string, blockID uuid.UUID) {
+	quarantined, err := h.store.QuarantinedBlocks(r.Context(), tenantID)
+	if err != nil {
+		level.Error(h.logger).Log("msg", "failed to read quarantined blocks", "tenant", tenantID, "err", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	for _, q := range quarantined {
+		if q.BlockID == blockID {
+			writeAnnotationsJSON(w, http.StatusOK, q)
+			return
+		}
+	}
+	http.Error(w, fmt.Sprintf("block %s is not quarantined", blockID), http.StatusNotFound)
+}
+
+func (h *BlockQuarantineHandler) release(w http.ResponseWriter, r *http.Request, tenantID string, blockID uuid.UUID) {
+	err := h.store.ReleaseQuarantinedBlock(r.Context(), tenantID, blockID)
+	if errors.Is(err, backend.ErrDoesNotExist) {
+		http.Error(w, fmt.Sprintf("block %s is not quarantined", blockID), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		level.Error(h.logger).Log("msg", "failed to release quarantined block", "tenant", tenantID, "block", blockID, "err", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,198 @@
+package frontend
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/grafana/dskit/user"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/pkg/api"
+	"github.com/grafana/tempo/tempodb"
+	"github.com/grafana/tempo/tempodb/backend"
+)
+
+type mockBlocksStore struct {
+	metas       map[string][]*backend.BlockMeta
+	quarantined map[string][]*tempodb.QuarantinedBlock
+	err         error
+}
+
+func (m *mockBlocksStore) BlockMetas(tenantID string) []*backend.BlockMeta {
+	return m.metas[tenantID]
+}
+
+func (m *mockBlocksStore) BlockMeta(_ context.Context, tenantID string, blockID uuid.UUID) (*backend.BlockMeta, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	for _, meta := range m.metas[tenantID] {
+		if meta.BlockID == blockID {
+			return meta, nil
+		}
+	}
+	return nil, backend.ErrDoesNotExist
+}
+
+func (m *mockBlocksStore) QuarantinedBlocks(_ context.Context, tenantID string) ([]*tempodb.QuarantinedBlock, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	return m.quarantined[tenantID], nil
+}
+
+func (m *mockBlocksStore) ReleaseQuarantinedBlock(_ context.Context, tenantID string, blockID uuid.UUID) error {
+	if m.err != nil {
+		return m.err
+	}
+	for i, q := range m.quarantined[tenantID] {
+		if q.BlockID == blockID {
+			m.quarantined[tenantID] = append(m.quarantined[tenantID][:i], m.quarantined[tenantID][i+1:]...)
+			return nil
+		}
+	}
+	return backend.ErrDoesNotExist
+}
+
+func TestBlocksHandler(t *testing.T) {
+	blockID := uuid.MustParse("00000000-0000-0000-0000-000000000001")
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	store := &mockBlocksStore{metas: map[string][]*backend.BlockMeta{
+		"test": {
+			{BlockID: uuid.MustParse("00000000-0000-0000-0000-000000000002"), TenantID: "test", StartTime: start.Add(time.Hour), EndTime: start.Add(2 * time.Hour), Version: "vParquet4", Encoding: backend.EncNone},
+			{BlockID: blockID, TenantID: "test", StartTime: start, EndTime: start.Add(time.Hour), TotalObjects: 10, Size: 100, CompactionLevel: 1, Version: "vParquet4", Encoding: backend.EncNone, DataEncoding: "v2"},
+		},
+	}}
+
+	router := mux.NewRouter()
+	h := NewBlocksHandler(store, log.NewNopLogger())
+	router.Handle(api.PathBlocks, h)
+	router.Handle(api.PathBlock, h)
+
+	do := func(method, tenant, path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		if tenant != "" {
+			req = req.WithContext(user.InjectOrgID(req.Context(), tenant))
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// blocks are sorted by start time
+	rec := do(http.MethodGet, "test", "/api/blocks")
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.JSONEq(t, `{"blocks":[
+		{"blockID":"00000000-0000-0000-0000-000000000001","startTime":"2024-01-01T00:00:00Z","endTime":"2024-01-01T01:00:00Z","totalObjects":10,"size":100,"compactionLevel":1,"encoding":"none","format":"vParquet4"},
+		{"blockID":"00000000-0000-0000-0000-000000000002","startTime":"2024-01-01T01:00:00Z","endTime":"2024-01-01T02:00:00Z","totalObjects":0,"size":0,"compactionLevel":0,"encoding":"none","format":"vParquet4"}
+	]}`, rec.Body.String())
+
+	// blocks are per tenant
+	rec = do(http.MethodGet, "other", "/api/blocks")
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.JSONEq(t, `{"blocks":[]}`, rec.Body.String())
+
+	rec = do(http.MethodGet, "test", "/api/blocks/"+blockID.String())
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Contains(t, rec.Body.String(), `"dataEncoding":"v2"`)
+
+	tcs := []struct {
+		name     string
+		method   string
+		tenant   string
+		path     string
+		err      error
+		expected int
+	}{
+		{name: "no tenant", method: http.MethodGet, path: "/api/blocks", expected: http.StatusBadRequest},
+		{name: "method not allowed", method: http.MethodPost, tenant: "test", path: "/api/blocks", expected: http.StatusMethodNotAllowed},
+		{name: "invalid block id", method: http.MethodGet, tenant: "test", path: "/api/blocks/1234", expected: http.StatusBadRequest},
+		{name: "block of other tenant", method: http.MethodGet, tenant: "other", path: "/api/blocks/" + blockID.String(), expected: http.StatusNotFound},
+		{name: "backend error", method: http.MethodGet, tenant: "test", path: "/api/blocks/" + blockID.String(), err: errors.New("error"), expected: http.StatusInternalServerError},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			store.err = tc.err
+			defer func() { store.err = nil }()
+
+			rec := do(tc.method, tc.tenant, tc.path)
+			require.Equal(t, tc.expected, rec.Code)
+		})
+	}
+}
+
+func TestBlockQuarantineHandler(t *testing.T) {
+	blockID := uuid.MustParse("00000000-0000-0000-0000-000000000001")
+	quarantinedAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	store := &mockBlocksStore{
+		metas: map[string][]*backend.BlockMeta{
+			"test": {{BlockID: blockID, TenantID: "test", StartTime: quarantinedAt, EndTime: quarantinedAt, Version: "vParquet4", Encoding: backend.EncNone}},
+		},
+		quarantined: map[string][]*tempodb.QuarantinedBlock{
+			"test": {{BlockID: blockID, QuarantinedAt: quarantinedAt, Reason: "checksum mismatch"}},
+		},
+	}
+
+	router := mux.NewRouter()
+	router.Handle(api.PathBlocks, NewBlocksHandler(store, log.NewNopLogger()))
+	router.Handle(api.PathBlockQuarantine, NewBlockQuarantineHandler(store, log.NewNopLogger()))
+
+	do := func(method, tenant, path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		if tenant != "" {
+			req = req.WithContext(user.InjectOrgID(req.Context(), tenant))
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// quarantined blocks are flagged in the list
+	rec := do(http.MethodGet, "test", "/api/blocks")
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Contains(t, rec.Body.String(), `"quarantined":true`)
+
+	rec = do(http.MethodGet, "test", "/api/blocks/"+blockID.String()+"/quarantine")
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.JSONEq(t, `{"blockID":"00000000-0000-0000-0000-000000000001","quarantinedAt":"2024-01-01T00:00:00Z","reason":"checksum mismatch"}`, rec.Body.String())
+
+	rec = do(http.MethodDelete, "test", "/api/blocks/"+blockID.String()+"/quarantine")
+	require.Equal(t, http.StatusNoContent, rec.Code)
+
+	// released blocks are no longer quarantined
+	rec = do(http.MethodGet, "test", "/api/blocks")
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.NotContains(t, rec.Body.String(), `"quarantined"`)
+
+	tcs := []struct {
+		name     string
+		method   string
+		tenant   string
+		path     string
+		err      error
+		expected int
+	}{
+		{name: "no tenant", method: http.MethodGet, path: "/api/blocks/" + blockID.String() + "/quarantine", expected: http.StatusBadRequest},
+		{name: "method not allowed", method: http.MethodPost, tenant: "test", path: "/api/blocks/" + blockID.String() + "/quarantine", expected: http.StatusMethodNotAllowed},
+		{name: "invalid block id", method: http.MethodGet, tenant: "test", path: "/api/blocks/1234/quarantine", expected: http.StatusBadRequest},
+		{name: "block not quarantined", method: http.MethodGet, tenant: "test", path: "/api/blocks/" + blockID.String() + "/quarantine", expected: http.StatusNotFound},
+		{name: "release of block not quarantined", method: http.MethodDelete, tenant: "test", path: "/api/blocks/" + blockID.String() + "/quarantine", expected: http.StatusNotFound},
+		{name: "backend error", method: http.MethodDelete, tenant: "test", path: "/api/blocks/" + blockID.String() + "/quarantine", err: errors.New("error"), expected: http.StatusInternalServerError},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			store.err = tc.err
+			defer func() { store.err = nil }()
+
+			rec := do(tc.method, tc.tenant, tc.path)
+			require.Equal(t, tc.expected, rec.Code)
+		})
+	}
+}
@@ -1,9 +1,14 @@
 package frontend
 
 import (
+	"encoding/hex"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/grafana/tempo/pkg/api"
+	"github.com/grafana/tempo/pkg/model/trace"
+	"github.com/grafana/tempo/tempodb"
 	"github.com/grafana/tempo/tempodb/backend"
 )
 
@@ -11,12 +16,19 @@ const (
 	cacheKeyPrefixSearchJob       = "sj:"
 	cacheKeyPrefixSearchTag       = "st:"
 	cacheKeyPrefixSearchTagValues = "stv:"
+	cacheKeyPrefixTraceByID       = "tid:"
 )
 
 func searchJobCacheKey(tenant string, queryHash uint64, start int64, end int64, meta *backend.BlockMeta, startPage, pagesToSearch int) string {
 	return cacheKey(cacheKeyPrefixSearchJob, tenant, queryHash, start, end, meta, startPage, pagesToSearch)
 }
 
+// cacheableBlock returns true if jobs against the block may be cached. blocks ending after the cutoff overlap the window
+// still served by the ingesters. a zero cutoff allows all blocks.
+func cacheableBlock(meta *backend.BlockMeta, cutoff time.Time) bool {
+	return cutoff.IsZero() || !meta.EndTime.After(cutoff)
+}
+
 // cacheKey returns a string that can be used as a cache key for a backend search job. if a valid key cannot be calculated
 // it returns an empty string.
 func cacheKey(prefix string, tenant string, queryHash uint64, start int64, end int64, meta *backend.BlockMeta, startPage, pagesToSearch int) string {
@@ -56,3 +68,69 @@ func cacheKey(prefix string, tenant string, queryHash uint64, start int64, end i
 
 	return sb.String()
 }
+
+// traceByIDCacheKey returns a string that can be used as a cache key for a fully combined trace by id response.
+// the stripped parts of the trace are included b/c the cached body is the final trace. the content type is not b/c the
+// trace is cached in proto and served in the requested format. the query mode, the block range and the early exit
+// criterion are included b/c they limit where the trace is searched and may return a partial trace. the defaults are
+// left out of the key.
+func traceByIDCacheKey(tenant string, traceID []byte, blockStart, blockEnd, queryMode string, start, end int64, strip trace.StripOptions, stop string) string {
+	stripped := strip.String()
+	if queryMode == api.QueryModeAll {
+		queryMode = ""
+	}
+	if blockStart == tempodb.BlockIDMin && blockEnd == tempodb.BlockIDMax {
+		blockStart, blockEnd = "", ""
+	}
+	if stop == api.TraceByIDStopNever {
+		stop = ""
+	}
+
+	sb := strings.Builder{}
+	sb.Grow(len(cacheKeyPrefixTraceByID) +
+		len(tenant) +
+		1 + // :
+		len(traceID)*2 +
+		1 + // :
+		20 + // start
+		1 + // :
+		20 + // end
+		1 + // :
+		len(stripped) +
+		6 + // :mode=
+		len(queryMode) +
+		8 + // :blocks=
+		len(blockStart) +
+		1 + // -
+		len(blockEnd) +
+		6 + // :stop=
+		len(stop))
+	sb.WriteString(cacheKeyPrefixTraceByID)
+	sb.WriteString(tenant)
+	sb.WriteString(":")
+	sb.WriteString(hex.EncodeToString(traceID))
+	sb.WriteString(":")
+	sb.WriteString(strconv.FormatInt(start, 10))
+	sb.WriteString(":")
+	sb.WriteString(strconv.FormatInt(end, 10))
+	if stripped != "" {
+		sb.WriteString(":")
+		sb.WriteString(stripped)
+	}
+	if queryMode != "" {
+		sb.WriteString(":mode=")
+		sb.WriteString(queryMode)
+	}
+	if blockStart != "" || blockEnd != "" {
+		sb.WriteString(":blocks=")
+		sb.WriteString(blockStart)
+		sb.WriteString("-")
+		sb.WriteString(blockEnd)
+	}
+	if stop != "" {
+		sb.WriteString(":stop=")
+		sb.WriteString(stop)
+	}
+
+	return sb.String()
+}
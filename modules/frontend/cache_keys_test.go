@@ -1,11 +1,15 @@
 package frontend
 
 import (
+	"flag"
 	"testing"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/grafana/tempo/pkg/api"
+	"github.com/grafana/tempo/pkg/model/trace"
 	"github.com/grafana/tempo/pkg/tempopb"
+	"github.com/grafana/tempo/tempodb"
 	"github.com/grafana/tempo/tempodb/backend"
 	"github.com/stretchr/testify/require"
 )
@@ -163,3 +167,46 @@ func BenchmarkCacheKeyForJob(b *testing.B) {
 		}
 	}
 }
+
+func TestTraceByIDCacheKey(t *testing.T) {
+	require.Equal(t, "tid:foo:0102:0:0", traceByIDCacheKey("foo", []byte{0x01, 0x02}, tempodb.BlockIDMin, tempodb.BlockIDMax, api.QueryModeAll, 0, 0, trace.StripOptions{}, ""))
+	require.Equal(t, "tid:foo|bar:0102:10:20", traceByIDCacheKey("foo|bar", []byte{0x01, 0x02}, tempodb.BlockIDMin, tempodb.BlockIDMax, api.QueryModeAll, 10, 20, trace.StripOptions{}, ""))
+	require.Equal(t, "tid:foo:0102:0:0:events,links", traceByIDCacheKey("foo", []byte{0x01, 0x02}, tempodb.BlockIDMin, tempodb.BlockIDMax, api.QueryModeAll, 0, 0, trace.StripOptions{Links: true, Events: true}, ""))
+
+	// stopping early may return a partial trace. never is the default
+	require.Equal(t, "tid:foo:0102:0:0", traceByIDCacheKey("foo", []byte{0x01, 0x02}, tempodb.BlockIDMin, tempodb.BlockIDMax, api.QueryModeAll, 0, 0, trace.StripOptions{}, "never"))
+	require.Equal(t, "tid:foo:0102:0:0:stop=first_block", traceByIDCacheKey("foo", []byte{0x01, 0x02}, tempodb.BlockIDMin, tempodb.BlockIDMax, api.QueryModeAll, 0, 0, trace.StripOptions{}, "first_block"))
+	require.Equal(t, "tid:foo:0102:0:0:events:stop=complete", traceByIDCacheKey("foo", []byte{0x01, 0x02}, tempodb.BlockIDMin, tempodb.BlockIDMax, api.QueryModeAll, 0, 0, trace.StripOptions{Events: true}, "complete"))
+
+	// the query mode and block range limit where the trace is searched. all blocks and all sources are the default
+	require.Equal(t, "tid:foo:0102:0:0:mode=ingesters", traceByIDCacheKey("foo", []byte{0x01, 0x02}, "", "", api.QueryModeIngesters, 0, 0, trace.StripOptions{}, ""))
+	require.Equal(t, "tid:foo:0102:0:0:mode=blocks", traceByIDCacheKey("foo", []byte{0x01, 0x02}, tempodb.BlockIDMin, tempodb.BlockIDMax, api.QueryModeBlocks, 0, 0, trace.StripOptions{}, ""))
+	require.Equal(t, "tid:foo:0102:0:0:blocks=00000000-0000-0000-0000-000000000000-40000000-0000-0000-0000-000000000000",
+		traceByIDCacheKey("foo", []byte{0x01, 0x02}, tempodb.BlockIDMin, "40000000-0000-0000-0000-000000000000", api.QueryModeAll, 0, 0, trace.StripOptions{}, ""))
+	require.Equal(t, "tid:foo:0102:0:0:mode=blocks:blocks=40000000-0000-0000-0000-000000000000-FFFFFFFF-FFFF-FFFF-FFFF-FFFFFFFFFFFF:stop=complete",
+		traceByIDCacheKey("foo", []byte{0x01, 0x02}, "40000000-0000-0000-0000-000000000000", tempodb.BlockIDMax, api.QueryModeBlocks, 0, 0, trace.StripOptions{}, "complete"))
+}
+
+func TestCacheableBlock(t *testing.T) {
+	cutoff := time.Unix(100, 0)
+
+	require.True(t, cacheableBlock(&backend.BlockMeta{EndTime: time.Unix(50, 0)}, cutoff))
+	require.True(t, cacheableBlock(&backend.BlockMeta{EndTime: time.Unix(100, 0)}, cutoff))
+	require.False(t, cacheableBlock(&backend.BlockMeta{EndTime: time.Unix(150, 0)}, cutoff))
+	require.True(t, cacheableBlock(&backend.BlockMeta{EndTime: time.Unix(150, 0)}, time.Time{}))
+}
+
+func TestSearchSharderConfigCacheCutoff(t *testing.T) {
+	now := time.Unix(1000, 0)
+
+	cfg := SearchSharderConfig{QueryIngestersUntil: 100 * time.Second}
+	require.Equal(t, time.Unix(900, 0), cfg.cacheCutoff(now))
+
+	cfg.CacheRecentJobs = true
+	require.True(t, cfg.cacheCutoff(now).IsZero())
+
+	// the jobs of all blocks are cached by default
+	defaults := Config{}
+	defaults.RegisterFlagsAndApplyDefaults("", flag.NewFlagSet("", flag.ContinueOnError))
+	require.True(t, defaults.Search.Sharder.cacheCutoff(now).IsZero())
+}
@@ -43,6 +43,7 @@ func NewSearch(limit int) Combiner {
 
 					final.Metrics.InspectedBytes += partial.Metrics.InspectedBytes
 					final.Metrics.InspectedTraces += partial.Metrics.InspectedTraces
+					final.Metrics.SkippedBlocks += partial.Metrics.SkippedBlocks
 				} else {
 					final.Metrics.TotalBlocks += partial.Metrics.TotalBlocks
 					final.Metrics.TotalJobs += partial.Metrics.TotalJobs
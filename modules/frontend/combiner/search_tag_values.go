@@ -1,6 +1,8 @@
 package combiner
 
 import (
+	"sort"
+
 	"github.com/grafana/tempo/pkg/tempopb"
 	"github.com/grafana/tempo/pkg/util"
 )
@@ -10,29 +12,34 @@ var (
 	_ GRPCCombiner[*tempopb.SearchTagValuesV2Response] = (*genericCombiner[*tempopb.SearchTagValuesV2Response])(nil)
 )
 
+// NewSearchTagValues returns a tag values combiner. the values of all responses are collected and sorted, and only then
+// cut at limitBytes so the same values are returned no matter the order the responses arrive in. a cut response is
+// flagged as partial.
 func NewSearchTagValues(limitBytes int) Combiner {
-	// Distinct collector with no limit
-	d := util.NewDistinctStringCollector(limitBytes)
+	// Distinct collector with no limit. the values are cut at limitBytes in finalize
+	d := util.NewDistinctStringCollector(0)
+	partial := false
 
 	return &genericCombiner[*tempopb.SearchTagValuesResponse]{
 		httpStatusCode: 200,
 		new:            func() *tempopb.SearchTagValuesResponse { return &tempopb.SearchTagValuesResponse{} },
 		current:        &tempopb.SearchTagValuesResponse{TagValues: make([]string, 0)},
-		combine: func(partial, final *tempopb.SearchTagValuesResponse, _ PipelineResponse) error {
-			for _, v := range partial.TagValues {
+		combine: func(resp, final *tempopb.SearchTagValuesResponse, _ PipelineResponse) error {
+			partial = partial || resp.Partial
+			for _, v := range resp.TagValues {
 				d.Collect(v)
 			}
 			return nil
 		},
 		finalize: func(final *tempopb.SearchTagValuesResponse) (*tempopb.SearchTagValuesResponse, error) {
-			final.TagValues = d.Strings()
+			var truncated bool
+			final.TagValues, truncated = truncateValues(d.Strings(), limitBytes, func(v string) int { return len(v) })
+			final.Partial = partial || truncated
 			return final, nil
 		},
-		quit: func(_ *tempopb.SearchTagValuesResponse) bool {
-			return d.Exceeded()
-		},
 		diff: func(response *tempopb.SearchTagValuesResponse) (*tempopb.SearchTagValuesResponse, error) {
 			response.TagValues = d.Diff()
+			response.Partial = partial
 			return response, nil
 		},
 	}
@@ -42,16 +49,20 @@ func NewTypedSearchTagValues(limitBytes int) GRPCCombiner[*tempopb.SearchTagValu
 	return NewSearchTagValues(limitBytes).(GRPCCombiner[*tempopb.SearchTagValuesResponse])
 }
 
+// NewSearchTagValuesV2 returns a tag values combiner. like NewSearchTagValues the values of all responses are sorted by
+// value and type and then cut at limitBytes, so the same values are always returned in the same order.
 func NewSearchTagValuesV2(limitBytes int) Combiner {
-	// Distinct collector with no limit
-	d := util.NewDistinctValueCollector(limitBytes, func(tv tempopb.TagValue) int { return len(tv.Type) + len(tv.Value) })
+	// Distinct collector with no limit. the values are cut at limitBytes in finalize
+	d := util.NewDistinctValueCollector(0, tagValueSize)
+	partial := false
 
 	return &genericCombiner[*tempopb.SearchTagValuesV2Response]{
 		httpStatusCode: 200,
 		current:        &tempopb.SearchTagValuesV2Response{TagValues: []*tempopb.TagValue{}},
 		new:            func() *tempopb.SearchTagValuesV2Response { return &tempopb.SearchTagValuesV2Response{} },
-		combine: func(partial, final *tempopb.SearchTagValuesV2Response, _ PipelineResponse) error {
-			for _, v := range partial.TagValues {
+		combine: func(resp, final *tempopb.SearchTagValuesV2Response, _ PipelineResponse) error {
+			partial = partial || resp.Partial
+			for _, v := range resp.TagValues {
 				d.Collect(*v)
 			}
 			return nil
@@ -63,11 +74,12 @@ func NewSearchTagValuesV2(limitBytes int) Combiner {
 				v2 := v
 				final.TagValues = append(final.TagValues, &v2)
 			}
+			sortTagValues(final.TagValues)
+			var truncated bool
+			final.TagValues, truncated = truncateValues(final.TagValues, limitBytes, func(v *tempopb.TagValue) int { return tagValueSize(*v) })
+			final.Partial = partial || truncated
 			return final, nil
 		},
-		quit: func(_ *tempopb.SearchTagValuesV2Response) bool {
-			return d.Exceeded()
-		},
 		diff: func(response *tempopb.SearchTagValuesV2Response) (*tempopb.SearchTagValuesV2Response, error) {
 			diff := d.Diff()
 			response.TagValues = make([]*tempopb.TagValue, 0, len(diff))
@@ -75,6 +87,8 @@ func NewSearchTagValuesV2(limitBytes int) Combiner {
 				v2 := v
 				response.TagValues = append(response.TagValues, &v2)
 			}
+			sortTagValues(response.TagValues)
+			response.Partial = partial
 			return response, nil
 		},
 	}
@@ -83,3 +97,33 @@ func NewSearchTagValuesV2(limitBytes int) Combiner {
 func NewTypedSearchTagValuesV2(limitBytes int) GRPCCombiner[*tempopb.SearchTagValuesV2Response] {
 	return NewSearchTagValuesV2(limitBytes).(GRPCCombiner[*tempopb.SearchTagValuesV2Response])
 }
+
+// sortTagValues sorts by value and then by type
+func sortTagValues(values []*tempopb.TagValue) {
+	sort.Slice(values, func(i, j int) bool {
+		if values[i].Value != values[j].Value {
+			return values[i].Value < values[j].Value
+		}
+		return values[i].Type < values[j].Type
+	})
+}
+
+func tagValueSize(tv tempopb.TagValue) int {
+	return len(tv.Type) + len(tv.Value)
+}
+
+// truncateValues cuts the sorted values at the first value that doesn't fit in limitBytes. a limit of 0 is unlimited.
+func truncateValues[T any](values []T, limitBytes int, size func(T) int) ([]T, bool) {
+	if limitBytes <= 0 {
+		return values, false
+	}
+
+	total := 0
+	for i, v := range values {
+		total += size(v)
+		if total > limitBytes {
+			return values[:i], true
+		}
+	}
+	return values, false
+}
@@ -107,30 +107,28 @@ func TestTagsCombiner(t *testing.T) {
 			limit:              2,
 		},
 		{
-			name:               "SearchTagValues - limited",
-			factory:            NewSearchTagValues,
-			result1:            &tempopb.SearchTagValuesResponse{TagValues: []string{"tag1"}},
-			result2:            &tempopb.SearchTagValuesResponse{TagValues: []string{"tag2", "tag3"}},
-			expectedResult:     &tempopb.SearchTagValuesResponse{TagValues: []string{"tag1"}},
-			actualResult:       &tempopb.SearchTagValuesResponse{},
-			sort:               func(m proto.Message) { sort.Strings(m.(*tempopb.SearchTagValuesResponse).TagValues) },
-			expectedShouldQuit: true,
-			limit:              5,
+			name:           "SearchTagValues - limited",
+			factory:        NewSearchTagValues,
+			result1:        &tempopb.SearchTagValuesResponse{TagValues: []string{"tag1"}},
+			result2:        &tempopb.SearchTagValuesResponse{TagValues: []string{"tag2", "tag3"}},
+			expectedResult: &tempopb.SearchTagValuesResponse{TagValues: []string{"tag1"}, Partial: true},
+			actualResult:   &tempopb.SearchTagValuesResponse{},
+			sort:           func(m proto.Message) { sort.Strings(m.(*tempopb.SearchTagValuesResponse).TagValues) },
+			limit:          5,
 		},
 		{
 			name:           "SearchTagValuesV2 - limited",
 			factory:        NewSearchTagValuesV2,
 			result1:        &tempopb.SearchTagValuesV2Response{TagValues: []*tempopb.TagValue{{Value: "v1", Type: "string"}}},
 			result2:        &tempopb.SearchTagValuesV2Response{TagValues: []*tempopb.TagValue{{Value: "v2", Type: "string"}, {Value: "v3", Type: "string"}}},
-			expectedResult: &tempopb.SearchTagValuesV2Response{TagValues: []*tempopb.TagValue{{Value: "v1", Type: "string"}}},
+			expectedResult: &tempopb.SearchTagValuesV2Response{TagValues: []*tempopb.TagValue{{Value: "v1", Type: "string"}}, Partial: true},
 			actualResult:   &tempopb.SearchTagValuesV2Response{},
 			sort: func(m proto.Message) {
 				sort.Slice(m.(*tempopb.SearchTagValuesV2Response).TagValues, func(i, j int) bool {
 					return m.(*tempopb.SearchTagValuesV2Response).TagValues[i].Value < m.(*tempopb.SearchTagValuesV2Response).TagValues[j].Value
 				})
 			},
-			expectedShouldQuit: true,
-			limit:              10,
+			limit: 10,
 		},
 	}
 	for _, tc := range tests {
@@ -232,3 +230,62 @@ func testGRPCCombiner[T proto.Message](t *testing.T, combiner GRPCCombiner[T], r
 	sort(actualFinal)
 	require.Equal(t, expectedFinal, actualFinal)
 }
+
+func TestTagValuesCombinerPartial(t *testing.T) {
+	// a partial job response marks the combined response as partial even if the limit is not exceeded
+	c := NewTypedSearchTagValues(0)
+	require.NoError(t, c.AddResponse(toHTTPResponse(t, &tempopb.SearchTagValuesResponse{TagValues: []string{"b"}}, 200)))
+	require.NoError(t, c.AddResponse(toHTTPResponse(t, &tempopb.SearchTagValuesResponse{TagValues: []string{"a"}, Partial: true}, 200)))
+
+	final, err := c.GRPCFinal()
+	require.NoError(t, err)
+	require.Equal(t, &tempopb.SearchTagValuesResponse{TagValues: []string{"a", "b"}, Partial: true}, final)
+
+	// v2 values are sorted by value and type
+	c2 := NewTypedSearchTagValuesV2(0)
+	require.NoError(t, c2.AddResponse(toHTTPResponse(t, &tempopb.SearchTagValuesV2Response{TagValues: []*tempopb.TagValue{{Type: "string", Value: "b"}, {Type: "string", Value: "a"}}}, 200)))
+	require.NoError(t, c2.AddResponse(toHTTPResponse(t, &tempopb.SearchTagValuesV2Response{TagValues: []*tempopb.TagValue{{Type: "int", Value: "a"}}, Partial: true}, 200)))
+
+	final2, err := c2.GRPCFinal()
+	require.NoError(t, err)
+	require.Equal(t, &tempopb.SearchTagValuesV2Response{
+		TagValues: []*tempopb.TagValue{{Type: "int", Value: "a"}, {Type: "string", Value: "a"}, {Type: "string", Value: "b"}},
+		Partial:   true,
+	}, final2)
+}
+
+func TestTagValuesCombinerTruncationIsDeterministic(t *testing.T) {
+	// the values are cut at the limit after sorting, so the result doesn't depend on the order of the responses
+	resp1 := &tempopb.SearchTagValuesResponse{TagValues: []string{"d", "e"}}
+	resp2 := &tempopb.SearchTagValuesResponse{TagValues: []string{"b", "a"}}
+	resp3 := &tempopb.SearchTagValuesResponse{TagValues: []string{"c"}}
+
+	for _, order := range [][]*tempopb.SearchTagValuesResponse{{resp1, resp2, resp3}, {resp3, resp1, resp2}, {resp2, resp3, resp1}} {
+		c := NewTypedSearchTagValues(3)
+		for _, resp := range order {
+			require.NoError(t, c.AddResponse(toHTTPResponse(t, resp, 200)))
+		}
+		require.False(t, c.ShouldQuit())
+
+		final, err := c.GRPCFinal()
+		require.NoError(t, err)
+		require.Equal(t, &tempopb.SearchTagValuesResponse{TagValues: []string{"a", "b", "c"}, Partial: true}, final)
+	}
+
+	v2resp1 := &tempopb.SearchTagValuesV2Response{TagValues: []*tempopb.TagValue{{Type: "string", Value: "c"}}}
+	v2resp2 := &tempopb.SearchTagValuesV2Response{TagValues: []*tempopb.TagValue{{Type: "string", Value: "b"}, {Type: "int", Value: "a"}}}
+
+	for _, order := range [][]*tempopb.SearchTagValuesV2Response{{v2resp1, v2resp2}, {v2resp2, v2resp1}} {
+		c := NewTypedSearchTagValuesV2(11)
+		for _, resp := range order {
+			require.NoError(t, c.AddResponse(toHTTPResponse(t, resp, 200)))
+		}
+
+		final, err := c.GRPCFinal()
+		require.NoError(t, err)
+		require.Equal(t, &tempopb.SearchTagValuesV2Response{
+			TagValues: []*tempopb.TagValue{{Type: "int", Value: "a"}, {Type: "string", Value: "b"}},
+			Partial:   true,
+		}, final)
+	}
+}
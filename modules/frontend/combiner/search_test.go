@@ -287,6 +287,31 @@ func TestSearchResponseCombiner(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "skipped blocks",
+			response1: toHTTPResponse(t, &tempopb.SearchResponse{
+				Metrics: &tempopb.SearchMetrics{
+					SkippedBlocks: 1,
+				},
+			}, 200),
+			response2: toHTTPResponse(t, &tempopb.SearchResponse{
+				Metrics: &tempopb.SearchMetrics{
+					InspectedTraces: 5,
+					InspectedBytes:  7,
+					SkippedBlocks:   2,
+				},
+			}, 200),
+			expectedStatus: 200,
+			expectedResponse: &tempopb.SearchResponse{
+				Traces: []*tempopb.TraceSearchMetadata{},
+				Metrics: &tempopb.SearchMetrics{
+					InspectedTraces: 5,
+					InspectedBytes:  7,
+					SkippedBlocks:   3,
+					CompletedJobs:   2,
+				},
+			},
+		},
 	}
 
 	for _, tc := range tests {
@@ -5,40 +5,70 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strconv"
 	"strings"
 	"sync"
 
 	"github.com/gogo/protobuf/jsonpb"
 	"github.com/gogo/protobuf/proto"
+	"github.com/gogo/status"
 	"github.com/grafana/tempo/pkg/api"
 	"github.com/grafana/tempo/pkg/model/trace"
 	"github.com/grafana/tempo/pkg/tempopb"
+	v1 "github.com/grafana/tempo/pkg/tempopb/trace/v1"
+	"google.golang.org/grpc/codes"
 )
 
 const (
 	internalErrorMsg = "internal error"
 )
 
+var _ GRPCCombiner[*tempopb.TraceByIDResponse] = (*traceByIDCombiner)(nil)
+
 type traceByIDCombiner struct {
 	mu sync.Mutex
 
 	c           *trace.Combiner
 	contentType string
+	strip       trace.StripOptions
 
 	code          int
 	statusMessage string
+
+	// streaming state. diffSpans tracks every span that has been returned by a diff and diffBatches holds
+	// the batches found since the last diff
+	metrics     *tempopb.TraceByIDMetrics
+	trackDiffs  bool
+	diffSpans   map[string]struct{}
+	diffBatches []*v1.ResourceSpans
 }
 
 // NewTraceByID returns a trace id combiner. The trace by id combiner has a few different behaviors then the others
 // - 404 is a valid response code. if all downstream jobs return 404 then it will return 404 with no body
 // - translate tempopb.TraceByIDResponse to tempopb.Trace. all other combiners pass the same object through
 // - runs the zipkin dedupe logic on the fully combined trace
+// - removes the parts of the trace the request asked to strip
 // - encode the returned trace as either json or proto depending on the request
-func NewTraceByID(maxBytes int, contentType string) Combiner {
+func NewTraceByID(maxBytes int, contentType string, strip trace.StripOptions) Combiner {
 	return &traceByIDCombiner{
 		c:           trace.NewCombiner(maxBytes),
 		code:        http.StatusNotFound,
 		contentType: contentType,
+		strip:       strip,
+		metrics:     &tempopb.TraceByIDMetrics{},
+	}
+}
+
+// NewTypedTraceByID returns a trace id combiner that can be used for streaming. Each diff contains the spans
+// that were found since the previous diff and the final response contains the complete, deduped trace.
+// totalJobs is the number of jobs the request was sharded into and is reported back in the metrics.
+func NewTypedTraceByID(maxBytes int, totalJobs int) GRPCCombiner[*tempopb.TraceByIDResponse] {
+	return &traceByIDCombiner{
+		c:          trace.NewCombiner(maxBytes),
+		code:       http.StatusNotFound,
+		metrics:    &tempopb.TraceByIDMetrics{TotalJobs: uint32(totalJobs)},
+		trackDiffs: true,
+		diffSpans:  map[string]struct{}{},
 	}
 }
 
@@ -50,6 +80,8 @@ func (c *traceByIDCombiner) AddResponse(r PipelineResponse) error {
 		return nil
 	}
 
+	c.metrics.CompletedJobs++
+
 	res := r.HTTPResponse()
 	if res.StatusCode == http.StatusNotFound {
 		// 404s are not considered errors, so we don't need to do anything.
@@ -81,6 +113,14 @@ func (c *traceByIDCombiner) AddResponse(r PipelineResponse) error {
 		c.statusMessage = internalErrorMsg
 		return fmt.Errorf("error unmarshalling response body: %w", err)
 	}
+	if resp.Metrics != nil {
+		c.metrics.SkippedBlocks += resp.Metrics.SkippedBlocks
+	}
+
+	// the combiner is destructive so record new spans for the next diff before consuming the trace
+	if c.trackDiffs {
+		c.recordDiff(resp.Trace)
+	}
 
 	// Consume the trace
 	_, err = c.c.Consume(resp.Trace)
@@ -111,6 +151,8 @@ func (c *traceByIDCombiner) HTTPFinal() (*http.Response, error) {
 	deduper := newDeduper()
 	traceResult = deduper.dedupe(traceResult)
 
+	trace.Strip(traceResult, c.strip)
+
 	// marshal in the requested format
 	var buff []byte
 	var err error
@@ -128,16 +170,137 @@ func (c *traceByIDCombiner) HTTPFinal() (*http.Response, error) {
 		return &http.Response{}, fmt.Errorf("error marshalling response: %w content type: %s", err, c.contentType)
 	}
 
+	header := http.Header{
+		api.HeaderContentType: {c.contentType},
+	}
+	// the trace may be partial if blocks were skipped
+	if c.metrics.SkippedBlocks > 0 {
+		header.Set(api.HeaderSkippedBlocks, strconv.FormatUint(uint64(c.metrics.SkippedBlocks), 10))
+	}
+
 	return &http.Response{
-		StatusCode: http.StatusOK,
-		Header: http.Header{
-			api.HeaderContentType: {c.contentType},
-		},
+		StatusCode:    http.StatusOK,
+		Header:        header,
 		Body:          io.NopCloser(bytes.NewReader(buff)),
 		ContentLength: int64(len(buff)),
 	}, nil
 }
 
+// GRPCFinal returns the complete, deduped trace. A trace that was not found is returned as a NotFound error.
+func (c *traceByIDCombiner) GRPCFinal() (*tempopb.TraceByIDResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.grpcError(); err != nil {
+		return nil, err
+	}
+
+	traceResult, _ := c.c.Result()
+	if traceResult == nil {
+		traceResult = &tempopb.Trace{}
+	}
+
+	// dedupe duplicate span ids
+	deduper := newDeduper()
+	traceResult = deduper.dedupe(traceResult)
+
+	return &tempopb.TraceByIDResponse{
+		Trace:   traceResult,
+		Metrics: c.currentMetrics(),
+	}, nil
+}
+
+// GRPCDiff returns the spans found since the last call to GRPCDiff along with the current job metrics.
+// Unlike GRPCFinal, a trace that has not been found yet is not an error. It is expected that
+// more jobs are still outstanding.
+func (c *traceByIDCombiner) GRPCDiff() (*tempopb.TraceByIDResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.code != http.StatusNotFound {
+		if err := c.grpcError(); err != nil {
+			return nil, err
+		}
+	}
+
+	resp := &tempopb.TraceByIDResponse{
+		Metrics: c.currentMetrics(),
+	}
+	if len(c.diffBatches) > 0 {
+		resp.Trace = &tempopb.Trace{Batches: c.diffBatches}
+		c.diffBatches = nil
+	}
+
+	return resp, nil
+}
+
+// recordDiff copies all spans from the passed trace that have not been seen before into diffBatches.
+// must be called under lock
+func (c *traceByIDCombiner) recordDiff(tr *tempopb.Trace) {
+	if tr == nil {
+		return
+	}
+
+	for _, b := range tr.Batches {
+		var scopeSpans []*v1.ScopeSpans
+
+		for _, ss := range b.ScopeSpans {
+			var spans []*v1.Span
+
+			for _, s := range ss.Spans {
+				key := fmt.Sprintf("%x:%d", s.SpanId, s.Kind)
+				if _, ok := c.diffSpans[key]; ok {
+					continue
+				}
+				c.diffSpans[key] = struct{}{}
+				spans = append(spans, s)
+			}
+
+			if len(spans) > 0 {
+				scopeSpans = append(scopeSpans, &v1.ScopeSpans{
+					Scope:     ss.Scope,
+					Spans:     spans,
+					SchemaUrl: ss.SchemaUrl,
+				})
+			}
+		}
+
+		if len(scopeSpans) > 0 {
+			c.diffBatches = append(c.diffBatches, &v1.ResourceSpans{
+				Resource:   b.Resource,
+				ScopeSpans: scopeSpans,
+				SchemaUrl:  b.SchemaUrl,
+			})
+		}
+	}
+}
+
+// currentMetrics returns a copy of the job metrics so they can be safely sent outside of the lock.
+// must be called under lock
+func (c *traceByIDCombiner) currentMetrics() *tempopb.TraceByIDMetrics {
+	return &tempopb.TraceByIDMetrics{
+		CompletedJobs: c.metrics.CompletedJobs,
+		TotalJobs:     c.metrics.TotalJobs,
+		SkippedBlocks: c.metrics.SkippedBlocks,
+	}
+}
+
+// grpcError translates the current status code into a grpc error. must be called under lock
+func (c *traceByIDCombiner) grpcError() error {
+	switch {
+	case c.code == http.StatusOK:
+		return nil
+	case c.code == http.StatusNotFound:
+		return status.Error(codes.NotFound, "trace not found")
+	case c.code/100 == 5:
+		return status.Error(codes.Internal, c.statusMessage)
+	case c.code == http.StatusTooManyRequests:
+		return status.Error(codes.ResourceExhausted, c.statusMessage)
+	default:
+		return status.Error(codes.InvalidArgument, c.statusMessage)
+	}
+}
+
 func (c *traceByIDCombiner) StatusCode() int {
 	c.mu.Lock()
 	defer c.mu.Unlock()
@@ -9,48 +9,51 @@ import (
 
 	"github.com/gogo/protobuf/jsonpb"
 	"github.com/gogo/protobuf/proto"
+	"github.com/gogo/status"
 	"github.com/grafana/tempo/pkg/api"
+	"github.com/grafana/tempo/pkg/model/trace"
 	"github.com/grafana/tempo/pkg/tempopb"
 	"github.com/grafana/tempo/pkg/util/test"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
 )
 
 func TestTraceByIDShouldQuit(t *testing.T) {
 	// new combiner should not quit
-	c := NewTraceByID(0, api.HeaderAcceptJSON)
+	c := NewTraceByID(0, api.HeaderAcceptJSON, trace.StripOptions{})
 	should := c.ShouldQuit()
 	require.False(t, should)
 
 	// 500 response should quit
-	c = NewTraceByID(0, api.HeaderAcceptJSON)
+	c = NewTraceByID(0, api.HeaderAcceptJSON, trace.StripOptions{})
 	err := c.AddResponse(toHTTPResponse(t, &tempopb.SearchResponse{}, 500))
 	require.NoError(t, err)
 	should = c.ShouldQuit()
 	require.True(t, should)
 
 	// 429 response should quit
-	c = NewTraceByID(0, api.HeaderAcceptJSON)
+	c = NewTraceByID(0, api.HeaderAcceptJSON, trace.StripOptions{})
 	err = c.AddResponse(toHTTPProtoResponse(t, &tempopb.SearchResponse{}, 429))
 	require.NoError(t, err)
 	should = c.ShouldQuit()
 	require.True(t, should)
 
 	// 404 response should not quit
-	c = NewTraceByID(0, api.HeaderAcceptJSON)
+	c = NewTraceByID(0, api.HeaderAcceptJSON, trace.StripOptions{})
 	err = c.AddResponse(toHTTPProtoResponse(t, &tempopb.SearchResponse{}, 404))
 	require.NoError(t, err)
 	should = c.ShouldQuit()
 	require.False(t, should)
 
 	// unparseable body should not quit, but should return an error
-	c = NewTraceByID(0, api.HeaderAcceptJSON)
+	c = NewTraceByID(0, api.HeaderAcceptJSON, trace.StripOptions{})
 	err = c.AddResponse(&pipelineResponse{&http.Response{Body: io.NopCloser(strings.NewReader("foo")), StatusCode: 200}})
 	require.Error(t, err)
 	should = c.ShouldQuit()
 	require.False(t, should)
 
 	// trace too large, should not quit but should return an error
-	c = NewTraceByID(1, api.HeaderAcceptJSON)
+	c = NewTraceByID(1, api.HeaderAcceptJSON, trace.StripOptions{})
 	err = c.AddResponse(toHTTPProtoResponse(t, &tempopb.TraceByIDResponse{
 		Trace:   test.MakeTrace(1, nil),
 		Metrics: &tempopb.TraceByIDMetrics{},
@@ -64,7 +67,7 @@ func TestTraceByIDHonorsContentType(t *testing.T) {
 	expected := test.MakeTrace(2, nil)
 
 	// json
-	c := NewTraceByID(0, api.HeaderAcceptJSON)
+	c := NewTraceByID(0, api.HeaderAcceptJSON, trace.StripOptions{})
 	err := c.AddResponse(toHTTPProtoResponse(t, &tempopb.TraceByIDResponse{Trace: expected}, 200))
 	require.NoError(t, err)
 
@@ -77,7 +80,7 @@ func TestTraceByIDHonorsContentType(t *testing.T) {
 	require.Equal(t, expected, actual)
 
 	// proto
-	c = NewTraceByID(0, api.HeaderAcceptProtobuf)
+	c = NewTraceByID(0, api.HeaderAcceptProtobuf, trace.StripOptions{})
 	err = c.AddResponse(toHTTPProtoResponse(t, &tempopb.TraceByIDResponse{Trace: expected}, 200))
 	require.NoError(t, err)
 
@@ -92,6 +95,110 @@ func TestTraceByIDHonorsContentType(t *testing.T) {
 	require.Equal(t, expected, actual)
 }
 
+func TestTraceByIDStrip(t *testing.T) {
+	tr := test.MakeTrace(2, nil)
+
+	c := NewTraceByID(0, api.HeaderAcceptProtobuf, trace.StripOptions{Events: true, Links: true})
+	err := c.AddResponse(toHTTPProtoResponse(t, &tempopb.TraceByIDResponse{Trace: proto.Clone(tr).(*tempopb.Trace)}, 200))
+	require.NoError(t, err)
+
+	resp, err := c.HTTPFinal()
+	require.NoError(t, err)
+
+	actual := &tempopb.Trace{}
+	buff, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.NoError(t, proto.Unmarshal(buff, actual))
+
+	trace.Strip(tr, trace.StripOptions{Events: true, Links: true})
+	require.Equal(t, tr, actual)
+}
+
+func TestTraceByIDSkippedBlocks(t *testing.T) {
+	c := NewTraceByID(0, api.HeaderAcceptJSON, trace.StripOptions{})
+	err := c.AddResponse(toHTTPProtoResponse(t, &tempopb.TraceByIDResponse{Trace: test.MakeTrace(2, nil), Metrics: &tempopb.TraceByIDMetrics{SkippedBlocks: 2}}, 200))
+	require.NoError(t, err)
+	err = c.AddResponse(toHTTPProtoResponse(t, &tempopb.TraceByIDResponse{Trace: test.MakeTrace(2, nil), Metrics: &tempopb.TraceByIDMetrics{SkippedBlocks: 1}}, 200))
+	require.NoError(t, err)
+
+	resp, err := c.HTTPFinal()
+	require.NoError(t, err)
+	require.Equal(t, "3", resp.Header.Get(api.HeaderSkippedBlocks))
+
+	// traces found in all blocks don't have the header
+	c = NewTraceByID(0, api.HeaderAcceptJSON, trace.StripOptions{})
+	err = c.AddResponse(toHTTPProtoResponse(t, &tempopb.TraceByIDResponse{Trace: test.MakeTrace(2, nil), Metrics: &tempopb.TraceByIDMetrics{}}, 200))
+	require.NoError(t, err)
+
+	resp, err = c.HTTPFinal()
+	require.NoError(t, err)
+	require.Empty(t, resp.Header.Get(api.HeaderSkippedBlocks))
+}
+
+func TestTraceByIDDiffs(t *testing.T) {
+	splitTrace := test.MakeTrace(2, nil)
+	trace1 := &tempopb.Trace{Batches: splitTrace.Batches[:1]}
+	trace2 := &tempopb.Trace{Batches: splitTrace.Batches[1:]}
+
+	c := NewTypedTraceByID(0, 3)
+
+	// a trace that hasn't been found yet is not an error during streaming
+	err := c.AddResponse(toHTTPProtoResponse(t, nil, 404))
+	require.NoError(t, err)
+	diff, err := c.GRPCDiff()
+	require.NoError(t, err)
+	require.Nil(t, diff.Trace)
+	require.Equal(t, &tempopb.TraceByIDMetrics{CompletedJobs: 1, TotalJobs: 3}, diff.Metrics)
+
+	// first diff contains only the first partial trace
+	err = c.AddResponse(toHTTPProtoResponse(t, &tempopb.TraceByIDResponse{Trace: proto.Clone(trace1).(*tempopb.Trace)}, 200))
+	require.NoError(t, err)
+	diff, err = c.GRPCDiff()
+	require.NoError(t, err)
+	require.True(t, proto.Equal(trace1, diff.Trace))
+	require.Equal(t, &tempopb.TraceByIDMetrics{CompletedJobs: 2, TotalJobs: 3}, diff.Metrics)
+
+	// second diff drops the already returned spans
+	err = c.AddResponse(toHTTPProtoResponse(t, &tempopb.TraceByIDResponse{Trace: proto.Clone(splitTrace).(*tempopb.Trace)}, 200))
+	require.NoError(t, err)
+	diff, err = c.GRPCDiff()
+	require.NoError(t, err)
+	require.True(t, proto.Equal(trace2, diff.Trace))
+	require.Equal(t, &tempopb.TraceByIDMetrics{CompletedJobs: 3, TotalJobs: 3}, diff.Metrics)
+
+	// nothing new
+	diff, err = c.GRPCDiff()
+	require.NoError(t, err)
+	require.Nil(t, diff.Trace)
+
+	// final has the complete trace
+	final, err := c.GRPCFinal()
+	require.NoError(t, err)
+	require.Equal(t, len(splitTrace.Batches), len(final.Trace.Batches))
+	require.Equal(t, uint32(3), final.Metrics.CompletedJobs)
+}
+
+func TestTraceByIDGRPCErrors(t *testing.T) {
+	// not found is only an error on the final response
+	c := NewTypedTraceByID(0, 1)
+	err := c.AddResponse(toHTTPProtoResponse(t, nil, 404))
+	require.NoError(t, err)
+	_, err = c.GRPCFinal()
+	require.Equal(t, codes.NotFound, status.Code(err))
+
+	c = NewTypedTraceByID(0, 1)
+	err = c.AddResponse(toHTTPProtoResponse(t, nil, 500))
+	require.NoError(t, err)
+	_, err = c.GRPCDiff()
+	require.Equal(t, codes.Internal, status.Code(err))
+
+	c = NewTypedTraceByID(0, 1)
+	err = c.AddResponse(toHTTPProtoResponse(t, nil, 429))
+	require.NoError(t, err)
+	_, err = c.GRPCFinal()
+	require.Equal(t, codes.ResourceExhausted, status.Code(err))
+}
+
 func toHTTPProtoResponse(t *testing.T, pb proto.Message, statusCode int) PipelineResponse {
 	var body []byte
 
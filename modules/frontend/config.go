@@ -10,6 +10,7 @@ import (
 
 	"github.com/grafana/tempo/modules/frontend/transport"
 	v1 "github.com/grafana/tempo/modules/frontend/v1"
+	"github.com/grafana/tempo/pkg/tenantusage"
 	"github.com/grafana/tempo/pkg/usagestats"
 )
 
@@ -18,29 +19,73 @@ var statVersion = usagestats.NewString("frontend_version")
 type Config struct {
 	Config                    v1.Config       `yaml:",inline"`
 	MaxRetries                int             `yaml:"max_retries,omitempty"`
+	RetryMinBackoff           time.Duration   `yaml:"retry_min_backoff,omitempty"`
+	RetryMaxBackoff           time.Duration   `yaml:"retry_max_backoff,omitempty"`
 	Search                    SearchConfig    `yaml:"search"`
 	TraceByID                 TraceByIDConfig `yaml:"trace_by_id"`
 	Metrics                   MetricsConfig   `yaml:"metrics"`
 	MultiTenantQueriesEnabled bool            `yaml:"multi_tenant_queries_enabled"`
 	ResponseConsumers         int             `yaml:"response_consumers"`
 
+	// remote clusters that trace by id and search requests are federated to
+	Federation FederationConfig `yaml:"federation,omitempty"`
+
+	// queries that take longer than this are logged with their parameters and metrics. 0 disables
+	LogQueriesLongerThan time.Duration `yaml:"log_queries_longer_than,omitempty"`
+
 	// the maximum time limit that tempo will work on an api request. this includes both
 	// grpc and http requests and applies to all "api" frontend query endpoints such as
 	// traceql, tag search, tag value search, trace by id and all streaming gRPC endpoints.
 	// 0 disables
 	APITimeout time.Duration `yaml:"api_timeout,omitempty"`
+
+	// compression of the http responses of the query endpoints. the encoding is picked from the
+	// Accept-Encoding header of the request
+	ResponseCompression ResponseCompressionConfig `yaml:"response_compression"`
+
+	// records who queried which trace or search query for compliance
+	AuditLog AuditLogConfig `yaml:"audit_log"`
+
+	// serves the endpoints to add and list the annotations of a trace. annotations are written to the trace backend
+	// so the query-frontend needs write access to it
+	TraceAnnotationsEnabled bool `yaml:"trace_annotations_enabled"`
+
+	// serves the endpoints to delete traces and list the deleted traces. tombstones are written to the trace backend
+	// so the query-frontend needs write access to it
+	TraceTombstonesEnabled bool `yaml:"trace_tombstones_enabled"`
+
+	// serves the endpoints to list the blocks of a tenant and read the meta of a block for debugging
+	BlockInspectionEnabled bool `yaml:"block_inspection_enabled"`
+
+	// accounts the bytes inspected by the queries per tenant. nil if the tenant usage is disabled
+	TenantUsage *tenantusage.Tracker `yaml:"-"`
+}
+
+type ResponseCompressionConfig struct {
+	// responses smaller than this are not compressed
+	MinSizeBytes int `yaml:"min_size_bytes"`
+	// compress with zstd instead of gzip if the client accepts it
+	Zstd bool `yaml:"zstd"`
 }
 
 type SearchConfig struct {
 	Timeout time.Duration       `yaml:"timeout,omitempty"`
 	Sharder SearchSharderConfig `yaml:",inline"`
 	SLO     SLOConfig           `yaml:",inline"`
+
+	// number of traces at the top of search results that are requested in the background and stored in the
+	// frontend-trace-by-id cache. 0 disables
+	PrefetchTraces int `yaml:"prefetch_traces,omitempty"`
 }
 
 type TraceByIDConfig struct {
 	QueryShards      int       `yaml:"query_shards,omitempty"`
 	ConcurrentShards int       `yaml:"concurrent_shards,omitempty"`
 	SLO              SLOConfig `yaml:",inline"`
+
+	// traces with a span that ended more recently than this are considered in flight and
+	// are never stored in the frontend-trace-by-id cache.
+	CacheMinTraceAge time.Duration `yaml:"cache_min_trace_age,omitempty"`
 }
 
 type MetricsConfig struct {
@@ -60,6 +105,7 @@ func (cfg *Config) RegisterFlagsAndApplyDefaults(string, *flag.FlagSet) {
 	}
 
 	cfg.Config.MaxOutstandingPerTenant = 2000
+	cfg.Config.QueueFullBehavior = v1.QueueFullReject
 	cfg.Config.MaxBatchSize = 5
 	cfg.MaxRetries = 2
 	cfg.ResponseConsumers = 10
@@ -73,12 +119,14 @@ func (cfg *Config) RegisterFlagsAndApplyDefaults(string, *flag.FlagSet) {
 			ConcurrentRequests:    defaultConcurrentRequests,
 			TargetBytesPerRequest: defaultTargetBytesPerRequest,
 			IngesterShards:        1,
+			CacheRecentJobs:       true,
 		},
 		SLO: slo,
 	}
 	cfg.TraceByID = TraceByIDConfig{
-		QueryShards: 50,
-		SLO:         slo,
+		QueryShards:      50,
+		SLO:              slo,
+		CacheMinTraceAge: 15 * time.Minute,
 	}
 	cfg.Metrics = MetricsConfig{
 		Sharder: QueryRangeSharderConfig{
@@ -94,6 +142,17 @@ func (cfg *Config) RegisterFlagsAndApplyDefaults(string, *flag.FlagSet) {
 
 	// enable multi tenant queries by default
 	cfg.MultiTenantQueriesEnabled = true
+
+	cfg.ResponseCompression = ResponseCompressionConfig{
+		MinSizeBytes: 1024,
+		Zstd:         true,
+	}
+
+	cfg.AuditLog = AuditLogConfig{
+		Sink:           AuditLogSinkLog,
+		WebhookTimeout: 5 * time.Second,
+		UserHeader:     "X-Grafana-User",
+	}
 }
 
 type CortexNoQuerierLimits struct{}
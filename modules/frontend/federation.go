@@ -0,0 +1,350 @@
+package frontend
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/go-kit/log" //nolint:all //deprecated
+	"github.com/go-kit/log/level"
+	"github.com/gogo/protobuf/jsonpb"
+	"github.com/gogo/protobuf/proto"
+	"github.com/grafana/dskit/user"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/grafana/tempo/modules/frontend/combiner"
+	"github.com/grafana/tempo/modules/frontend/pipeline"
+	"github.com/grafana/tempo/pkg/api"
+	"github.com/grafana/tempo/pkg/tempopb"
+	v1_common "github.com/grafana/tempo/pkg/tempopb/common/v1"
+	v1_resource "github.com/grafana/tempo/pkg/tempopb/resource/v1"
+)
+
+const (
+	// HeaderFederated is set on requests sent to remote clusters. a frontend never federates a request
+	// that carries it which prevents clusters that federate each other from looping.
+	HeaderFederated = "X-Tempo-Federated"
+
+	// FederatedClusterAttribute is the resource attribute added to spans returned by a remote cluster
+	FederatedClusterAttribute = "tempo.cluster"
+)
+
+var metricFederatedRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "tempo",
+	Name:      "query_frontend_federated_requests_total",
+	Help:      "Total requests sent to remote clusters by result.",
+}, []string{"cluster", "op", "result"})
+
+type FederationConfig struct {
+	Clusters []FederatedClusterConfig `yaml:"clusters,omitempty"`
+}
+
+type FederatedClusterConfig struct {
+	// Name is used to attribute results to the cluster
+	Name string `yaml:"name"`
+	// Endpoint is the base url of the remote cluster's query frontend including any api prefix. e.g. http://tempo-eu:3200
+	Endpoint string `yaml:"endpoint"`
+	// Headers are added to every request to the remote cluster. this can be used for authentication.
+	Headers map[string]string `yaml:"headers,omitempty"`
+	// Timeout is the maximum time to wait for the remote cluster. 0 waits until the request is cancelled.
+	Timeout time.Duration `yaml:"timeout,omitempty"`
+}
+
+func (cfg *FederationConfig) Validate() error {
+	names := make(map[string]struct{}, len(cfg.Clusters))
+
+	for _, c := range cfg.Clusters {
+		if c.Name == "" {
+			return errors.New("federated cluster name must be set")
+		}
+		if _, ok := names[c.Name]; ok {
+			return fmt.Errorf("federated cluster name %s is not unique", c.Name)
+		}
+		names[c.Name] = struct{}{}
+
+		u, err := url.Parse(c.Endpoint)
+		if err != nil {
+			return fmt.Errorf("federated cluster %s endpoint is invalid: %w", c.Name, err)
+		}
+		if u.Scheme == "" || u.Host == "" {
+			return fmt.Errorf("federated cluster %s endpoint must include a scheme and host: %s", c.Name, c.Endpoint)
+		}
+	}
+
+	return nil
+}
+
+type federatedClusterKey struct{}
+
+type federatedCluster struct {
+	cfg    FederatedClusterConfig
+	client *http.Client
+}
+
+// federationConvertFn converts a successful response from a remote cluster into the
+// response format the local combiner expects and attributes the results to the cluster.
+type federationConvertFn func(cluster string, body []byte) (*http.Response, error)
+
+type asyncFederationSharder struct {
+	next      pipeline.AsyncRoundTripper[combiner.PipelineResponse]
+	clusters  []*federatedCluster
+	apiPrefix string
+	op        string
+	logger    log.Logger
+
+	accept string
+	// convert is applied to a 200 from a remote cluster
+	convert federationConvertFn
+	// empty is returned in place of a failed remote request so the query succeeds with partial results
+	empty func() *http.Response
+	// jobMetrics builds the response that announces the remote jobs to the combiner. optional
+	jobMetrics func(totalJobs int) (pipeline.Responses[combiner.PipelineResponse], error)
+}
+
+// newAsyncTraceIDFederationSharder fans trace by id requests out to the local pipeline and every remote cluster
+func newAsyncTraceIDFederationSharder(cfg FederationConfig, apiPrefix string, logger log.Logger) pipeline.AsyncMiddleware[combiner.PipelineResponse] {
+	return newAsyncFederationSharder(cfg, apiPrefix, logger, func(s *asyncFederationSharder) {
+		s.op = traceByIDOp
+		s.accept = api.HeaderAcceptProtobuf
+		s.convert = federatedTraceByIDResponse
+		s.empty = func() *http.Response {
+			return &http.Response{
+				StatusCode: http.StatusNotFound,
+				Header:     http.Header{},
+				Body:       io.NopCloser(strings.NewReader("")),
+			}
+		}
+	})
+}
+
+// newAsyncSearchFederationSharder fans search requests out to the local pipeline and every remote cluster
+func newAsyncSearchFederationSharder(cfg FederationConfig, apiPrefix string, logger log.Logger) pipeline.AsyncMiddleware[combiner.PipelineResponse] {
+	return newAsyncFederationSharder(cfg, apiPrefix, logger, func(s *asyncFederationSharder) {
+		s.op = searchOp
+		s.accept = api.HeaderAcceptJSON
+		s.convert = federatedSearchResponse
+		s.empty = func() *http.Response {
+			// an empty metrics object is required for the search combiner to count the job as completed
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{},
+				Body:       io.NopCloser(strings.NewReader(`{"metrics":{}}`)),
+			}
+		}
+		s.jobMetrics = func(totalJobs int) (pipeline.Responses[combiner.PipelineResponse], error) {
+			m := jsonpb.Marshaler{}
+			body, err := m.MarshalToString(&tempopb.SearchResponse{
+				Metrics: &tempopb.SearchMetrics{
+					TotalJobs: uint32(totalJobs),
+				},
+			})
+			if err != nil {
+				return nil, err
+			}
+			return pipeline.NewSuccessfulResponse(body), nil
+		}
+	})
+}
+
+func newAsyncFederationSharder(cfg FederationConfig, apiPrefix string, logger log.Logger, opt func(*asyncFederationSharder)) pipeline.AsyncMiddleware[combiner.PipelineResponse] {
+	if len(cfg.Clusters) == 0 {
+		return pipeline.NewNoopMiddleware()
+	}
+
+	clusters := make([]*federatedCluster, 0, len(cfg.Clusters))
+	for _, c := range cfg.Clusters {
+		clusters = append(clusters, &federatedCluster{
+			cfg:    c,
+			client: &http.Client{Timeout: c.Timeout},
+		})
+	}
+
+	return pipeline.AsyncMiddlewareFunc[combiner.PipelineResponse](func(next pipeline.AsyncRoundTripper[combiner.PipelineResponse]) pipeline.AsyncRoundTripper[combiner.PipelineResponse] {
+		s := &asyncFederationSharder{
+			next:      next,
+			clusters:  clusters,
+			apiPrefix: apiPrefix,
+			logger:    logger,
+		}
+		opt(s)
+		return s
+	})
+}
+
+// RoundTrip sends the request down the local pipeline and to each remote cluster
+func (s *asyncFederationSharder) RoundTrip(r *http.Request) (pipeline.Responses[combiner.PipelineResponse], error) {
+	// requests from another cluster are only answered locally
+	if r.Header.Get(HeaderFederated) != "" {
+		return s.next.RoundTrip(r)
+	}
+
+	ctx := r.Context()
+	tenant, err := user.ExtractOrgID(ctx)
+	if err != nil {
+		return pipeline.NewBadRequest(err), nil
+	}
+
+	var jobMetricsResponse pipeline.Responses[combiner.PipelineResponse]
+	if s.jobMetrics != nil {
+		jobMetricsResponse, err = s.jobMetrics(len(s.clusters))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	reqCh := make(chan *http.Request, len(s.clusters)+1)
+	reqCh <- r
+	for _, c := range s.clusters {
+		remoteReq, err := s.buildRemoteRequest(ctx, r, tenant, c)
+		if err != nil {
+			close(reqCh)
+			return nil, err
+		}
+		reqCh <- remoteReq
+	}
+	close(reqCh)
+
+	return pipeline.NewAsyncSharderChan(ctx, len(s.clusters)+1, reqCh, jobMetricsResponse, pipeline.AsyncRoundTripperFunc[combiner.PipelineResponse](s.route)), nil
+}
+
+// route sends requests built for a remote cluster to the cluster and everything else down the local pipeline
+func (s *asyncFederationSharder) route(r *http.Request) (pipeline.Responses[combiner.PipelineResponse], error) {
+	c, ok := r.Context().Value(federatedClusterKey{}).(*federatedCluster)
+	if !ok {
+		return s.next.RoundTrip(r)
+	}
+
+	return pipeline.NewHTTPToAsyncResponse(s.roundTripRemote(r, c)), nil
+}
+
+// roundTripRemote never returns an error. a failing remote cluster is logged and replaced with an empty response
+// so that an outage in one region doesn't fail queries everywhere.
+func (s *asyncFederationSharder) roundTripRemote(r *http.Request, c *federatedCluster) *http.Response {
+	result := "success"
+	resp, err := c.client.Do(r)
+	if err == nil {
+		defer func() { _ = resp.Body.Close() }()
+
+		switch resp.StatusCode {
+		case http.StatusOK:
+			var body []byte
+			body, err = io.ReadAll(resp.Body)
+			if err == nil {
+				var converted *http.Response
+				converted, err = s.convert(c.cfg.Name, body)
+				if err == nil {
+					metricFederatedRequests.WithLabelValues(c.cfg.Name, s.op, result).Inc()
+					return converted
+				}
+			}
+		case http.StatusNotFound:
+			result = "not_found"
+		default:
+			msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+			err = fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, string(msg))
+		}
+	}
+
+	if err != nil {
+		result = "failure"
+		level.Warn(s.logger).Log("msg", "federated request failed", "cluster", c.cfg.Name, "url", r.URL.String(), "err", err)
+	}
+	metricFederatedRequests.WithLabelValues(c.cfg.Name, s.op, result).Inc()
+
+	return s.empty()
+}
+
+// buildRemoteRequest builds a request against the remote cluster's public api. the path is the local path
+// with the local api prefix removed and the query parameters are passed unchanged.
+func (s *asyncFederationSharder) buildRemoteRequest(ctx context.Context, parent *http.Request, tenant string, c *federatedCluster) (*http.Request, error) {
+	u, err := url.Parse(strings.TrimSuffix(c.cfg.Endpoint, "/") + strings.TrimPrefix(parent.URL.Path, s.apiPrefix))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build url for federated cluster %s: %w", c.cfg.Name, err)
+	}
+	u.RawQuery = parent.URL.RawQuery
+
+	req, err := http.NewRequestWithContext(context.WithValue(ctx, federatedClusterKey{}, c), http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build request for federated cluster %s: %w", c.cfg.Name, err)
+	}
+
+	for k, v := range c.cfg.Headers {
+		req.Header.Set(k, v)
+	}
+	req.Header.Set(user.OrgIDHeaderName, tenant)
+	req.Header.Set(api.HeaderAccept, s.accept)
+	req.Header.Set(HeaderFederated, "true")
+
+	return req, nil
+}
+
+// federatedTraceByIDResponse converts a trace returned by a remote frontend into a querier response and adds
+// the cluster name as a resource attribute to every batch
+func federatedTraceByIDResponse(cluster string, body []byte) (*http.Response, error) {
+	trace := &tempopb.Trace{}
+	if err := proto.Unmarshal(body, trace); err != nil {
+		return nil, fmt.Errorf("error unmarshalling trace: %w", err)
+	}
+
+	for _, b := range trace.Batches {
+		if b.Resource == nil {
+			b.Resource = &v1_resource.Resource{}
+		}
+		b.Resource.Attributes = append(b.Resource.Attributes, &v1_common.KeyValue{
+			Key:   FederatedClusterAttribute,
+			Value: &v1_common.AnyValue{Value: &v1_common.AnyValue_StringValue{StringValue: cluster}},
+		})
+	}
+
+	buff, err := proto.Marshal(&tempopb.TraceByIDResponse{Trace: trace})
+	if err != nil {
+		return nil, fmt.Errorf("error marshalling trace by id response: %w", err)
+	}
+
+	return &http.Response{
+		StatusCode:    http.StatusOK,
+		Header:        http.Header{api.HeaderContentType: {api.HeaderAcceptProtobuf}},
+		Body:          io.NopCloser(bytes.NewReader(buff)),
+		ContentLength: int64(len(buff)),
+	}, nil
+}
+
+// federatedSearchResponse attributes every trace returned by a remote frontend to the cluster. the remote cluster
+// is counted as a single job so only the inspected metrics are passed on.
+func federatedSearchResponse(cluster string, body []byte) (*http.Response, error) {
+	resp := &tempopb.SearchResponse{}
+	if err := jsonpb.Unmarshal(bytes.NewReader(body), resp); err != nil {
+		return nil, fmt.Errorf("error unmarshalling search response: %w", err)
+	}
+
+	for _, t := range resp.Traces {
+		t.Clusters = []string{cluster}
+	}
+
+	metrics := &tempopb.SearchMetrics{}
+	if resp.Metrics != nil {
+		metrics.InspectedTraces = resp.Metrics.InspectedTraces
+		metrics.InspectedBytes = resp.Metrics.InspectedBytes
+	}
+	resp.Metrics = metrics
+
+	m := jsonpb.Marshaler{}
+	bodyString, err := m.MarshalToString(resp)
+	if err != nil {
+		return nil, fmt.Errorf("error marshalling search response: %w", err)
+	}
+
+	return &http.Response{
+		StatusCode:    http.StatusOK,
+		Header:        http.Header{api.HeaderContentType: {api.HeaderAcceptJSON}},
+		Body:          io.NopCloser(strings.NewReader(bodyString)),
+		ContentLength: int64(len(bodyString)),
+	}, nil
+}
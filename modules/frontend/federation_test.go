@@ -0,0 +1,220 @@
+package frontend
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"testing"
+
+	"github.com/gogo/protobuf/jsonpb"
+	"github.com/gogo/protobuf/proto"
+	"github.com/gorilla/mux"
+	"github.com/grafana/dskit/user"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/atomic"
+
+	"github.com/grafana/tempo/modules/frontend/pipeline"
+	"github.com/grafana/tempo/pkg/api"
+	"github.com/grafana/tempo/pkg/search"
+	"github.com/grafana/tempo/pkg/tempopb"
+	"github.com/grafana/tempo/pkg/util/test"
+)
+
+func TestFederationConfigValidate(t *testing.T) {
+	tcs := []struct {
+		name     string
+		clusters []FederatedClusterConfig
+		err      string
+	}{
+		{
+			name: "no clusters",
+		},
+		{
+			name:     "valid",
+			clusters: []FederatedClusterConfig{{Name: "eu", Endpoint: "http://tempo-eu:3200"}, {Name: "us", Endpoint: "https://tempo-us/tempo"}},
+		},
+		{
+			name:     "missing name",
+			clusters: []FederatedClusterConfig{{Endpoint: "http://tempo-eu:3200"}},
+			err:      "federated cluster name must be set",
+		},
+		{
+			name:     "duplicate name",
+			clusters: []FederatedClusterConfig{{Name: "eu", Endpoint: "http://tempo-eu:3200"}, {Name: "eu", Endpoint: "http://tempo-us:3200"}},
+			err:      "federated cluster name eu is not unique",
+		},
+		{
+			name:     "missing scheme",
+			clusters: []FederatedClusterConfig{{Name: "eu", Endpoint: "tempo-eu:3200"}},
+			err:      "federated cluster eu endpoint must include a scheme and host: tempo-eu:3200",
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := FederationConfig{Clusters: tc.clusters}
+			err := cfg.Validate()
+			if tc.err == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.EqualError(t, err, tc.err)
+		})
+	}
+}
+
+func TestFederationSearch(t *testing.T) {
+	var remoteReqs atomic.Int32
+
+	eu := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		remoteReqs.Inc()
+
+		require.Equal(t, "/api/search", r.URL.Path)
+		require.Equal(t, "{}", r.URL.Query().Get("q"))
+		require.Equal(t, "single-tenant", r.Header.Get(user.OrgIDHeaderName))
+		require.Equal(t, "true", r.Header.Get(HeaderFederated))
+		require.Equal(t, "Bearer eu", r.Header.Get("Authorization"))
+
+		m := jsonpb.Marshaler{}
+		require.NoError(t, m.Marshal(w, &tempopb.SearchResponse{
+			Traces: []*tempopb.TraceSearchMetadata{
+				{TraceID: "1", RootServiceName: "svc"},
+				{TraceID: "2", RootServiceName: "svc"},
+			},
+			Metrics: &tempopb.SearchMetrics{
+				InspectedTraces: 10,
+				InspectedBytes:  100,
+				TotalJobs:       5,
+				CompletedJobs:   5,
+			},
+		}))
+	}))
+	defer eu.Close()
+
+	// a failing cluster doesn't fail the query
+	us := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		remoteReqs.Inc()
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer us.Close()
+
+	f := frontendWithSettings(t, nil, nil, nil, nil, func(cfg *Config) {
+		cfg.Federation = FederationConfig{
+			Clusters: []FederatedClusterConfig{
+				{Name: "eu", Endpoint: eu.URL, Headers: map[string]string{"Authorization": "Bearer eu"}},
+				{Name: "us", Endpoint: us.URL},
+			},
+		}
+	})
+
+	httpReq := httptest.NewRequest("GET", "/api/search", nil)
+	httpReq, err := api.BuildSearchRequest(httpReq, &tempopb.SearchRequest{
+		Query: "{}",
+		Start: 1,
+		End:   100000,
+		Limit: 10,
+	})
+	require.NoError(t, err)
+	httpReq = httpReq.WithContext(user.InjectOrgID(httpReq.Context(), "single-tenant"))
+
+	httpResp := httptest.NewRecorder()
+	f.SearchHandler.ServeHTTP(httpResp, httpReq)
+	require.Equal(t, http.StatusOK, httpResp.Code)
+
+	actualResp := &tempopb.SearchResponse{}
+	require.NoError(t, jsonpb.Unmarshal(httpResp.Body, actualResp))
+	sort.Slice(actualResp.Traces, func(i, j int) bool {
+		return actualResp.Traces[i].TraceID < actualResp.Traces[j].TraceID
+	})
+
+	require.Equal(t, &tempopb.SearchResponse{
+		Traces: []*tempopb.TraceSearchMetadata{
+			{TraceID: "1", RootServiceName: "svc", Clusters: []string{"eu"}},
+			{TraceID: "2", RootServiceName: "svc", Clusters: []string{"eu"}},
+		},
+		Metrics: &tempopb.SearchMetrics{
+			InspectedTraces: 14, // 4 local jobs + eu
+			InspectedBytes:  104,
+			TotalBlocks:     2,
+			TotalJobs:       6, // 4 local jobs + 1 per cluster
+			TotalBlockBytes: 4 * defaultTargetBytesPerRequest,
+			CompletedJobs:   6,
+		},
+	}, actualResp)
+	require.Equal(t, int32(2), remoteReqs.Load())
+
+	// requests that were already federated are only answered locally
+	remoteReqs.Store(0)
+	httpReq.Header.Set(HeaderFederated, "true")
+
+	httpResp = httptest.NewRecorder()
+	f.SearchHandler.ServeHTTP(httpResp, httpReq)
+	require.Equal(t, http.StatusOK, httpResp.Code)
+
+	actualResp = &tempopb.SearchResponse{}
+	require.NoError(t, jsonpb.Unmarshal(httpResp.Body, actualResp))
+	require.Equal(t, []*tempopb.TraceSearchMetadata{{TraceID: "1", RootServiceName: search.RootSpanNotYetReceivedText}}, actualResp.Traces)
+	require.Equal(t, int32(0), remoteReqs.Load())
+}
+
+func TestFederationTraceByID(t *testing.T) {
+	localTrace := test.MakeTrace(1, []byte{0x12, 0x34})
+	remoteTrace := test.MakeTrace(1, []byte{0x12, 0x34})
+
+	remote := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/api/traces/1234", r.URL.Path)
+		require.Equal(t, api.HeaderAcceptProtobuf, r.Header.Get(api.HeaderAccept))
+
+		b, err := proto.Marshal(remoteTrace)
+		require.NoError(t, err)
+		_, _ = w.Write(b)
+	}))
+	defer remote.Close()
+
+	next := pipeline.RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		if r.RequestURI != "/querier/api/traces/1234?mode=ingesters" {
+			return &http.Response{StatusCode: http.StatusNotFound, Body: io.NopCloser(bytes.NewReader(nil))}, nil
+		}
+
+		b, err := proto.Marshal(&tempopb.TraceByIDResponse{
+			Trace:   proto.Clone(localTrace).(*tempopb.Trace),
+			Metrics: &tempopb.TraceByIDMetrics{},
+		})
+		require.NoError(t, err)
+
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewReader(b))}, nil
+	})
+
+	f := frontendWithSettings(t, next, nil, nil, nil, func(cfg *Config) {
+		cfg.Federation = FederationConfig{
+			Clusters: []FederatedClusterConfig{{Name: "eu", Endpoint: remote.URL}},
+		}
+	})
+
+	req := httptest.NewRequest("GET", "/api/traces/1234", nil)
+	req = req.WithContext(user.InjectOrgID(req.Context(), "single-tenant"))
+	req = mux.SetURLVars(req, map[string]string{"traceID": "1234"})
+	req.Header.Set(api.HeaderAccept, api.HeaderAcceptProtobuf)
+
+	httpResp := httptest.NewRecorder()
+	f.TraceByIDHandler.ServeHTTP(httpResp, req)
+	require.Equal(t, http.StatusOK, httpResp.Code)
+
+	actualTrace := &tempopb.Trace{}
+	require.NoError(t, proto.Unmarshal(httpResp.Body.Bytes(), actualTrace))
+	require.Len(t, actualTrace.Batches, len(localTrace.Batches)+len(remoteTrace.Batches))
+
+	// only batches from the remote cluster are attributed
+	attributed := 0
+	for _, b := range actualTrace.Batches {
+		for _, kv := range b.Resource.Attributes {
+			if kv.Key == FederatedClusterAttribute {
+				require.Equal(t, "eu", kv.Value.GetStringValue())
+				attributed++
+			}
+		}
+	}
+	require.Equal(t, len(remoteTrace.Batches), attributed)
+}
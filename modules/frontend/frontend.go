@@ -7,6 +7,7 @@ import (
 	"net/url"
 	"path"
 	"strings"
+	"time"
 
 	"github.com/go-kit/log"
 	"github.com/go-kit/log/level" //nolint:all //deprecated
@@ -32,6 +33,7 @@ type (
 	streamingTagValuesHandler   func(req *tempopb.SearchTagValuesRequest, srv tempopb.StreamingQuerier_SearchTagValuesServer) error
 	streamingTagValuesV2Handler func(req *tempopb.SearchTagValuesRequest, srv tempopb.StreamingQuerier_SearchTagValuesV2Server) error
 	streamingQueryRangeHandler  func(req *tempopb.QueryRangeRequest, srv tempopb.StreamingQuerier_MetricsQueryRangeServer) error
+	streamingTraceIDHandler     func(req *tempopb.TraceByIDRequest, srv tempopb.StreamingQuerier_FindTraceByIDServer) error
 )
 
 type QueryFrontend struct {
@@ -44,6 +46,9 @@ type QueryFrontend struct {
 	streamingTagValues                                                                         streamingTagValuesHandler
 	streamingTagValuesV2                                                                       streamingTagValuesV2Handler
 	streamingQueryRange                                                                        streamingQueryRangeHandler
+	streamingTraceID                                                                           streamingTraceIDHandler
+	audit                                                                                      *auditLogger
+	rateLimiter                                                                                *queryRateLimiter
 	logger                                                                                     log.Logger
 }
 
@@ -79,41 +84,62 @@ func New(cfg Config, next http.RoundTripper, o overrides.Interface, reader tempo
 		return nil, fmt.Errorf("frontend metrics interval should be greater than 0")
 	}
 
-	retryWare := pipeline.NewRetryWare(cfg.MaxRetries, registerer)
+	if err := cfg.Federation.Validate(); err != nil {
+		return nil, fmt.Errorf("frontend federation config is invalid: %w", err)
+	}
+
+	if err := cfg.AuditLog.Validate(); err != nil {
+		return nil, fmt.Errorf("frontend audit log config is invalid: %w", err)
+	}
+
+	if cfg.RetryMinBackoff < 0 || cfg.RetryMaxBackoff < 0 {
+		return nil, fmt.Errorf("frontend retry backoff should be greater than or equal to 0")
+	}
+
+	if cfg.RetryMinBackoff > 0 && cfg.RetryMaxBackoff < cfg.RetryMinBackoff {
+		return nil, fmt.Errorf("frontend retry max backoff should be greater than or equal to retry min backoff")
+	}
+
+	cfg.Search.Sharder.TraceTombstonesEnabled = cfg.TraceTombstonesEnabled
+
+	retryWare := pipeline.NewRetryWare(cfg.MaxRetries, cfg.RetryMinBackoff, cfg.RetryMaxBackoff, registerer)
 	cacheWare := pipeline.NewCachingWare(cacheProvider, cache.RoleFrontendSearch, logger)
 	statusCodeWare := pipeline.NewStatusCodeAdjustWare()
+	queryStatsWare := pipeline.NewQueryStatsWare()
 	traceIDStatusCodeWare := pipeline.NewStatusCodeAdjustWareWithAllowedCode(http.StatusNotFound)
 
 	tracePipeline := pipeline.Build(
 		[]pipeline.AsyncMiddleware[combiner.PipelineResponse]{
-			multiTenantMiddleware(cfg, logger),
+			newAsyncTraceIDFederationSharder(cfg.Federation, apiPrefix, logger),
+			multiTenantMiddleware(cfg, o, logger),
 			newAsyncTraceIDSharder(&cfg.TraceByID, logger),
 		},
-		[]pipeline.Middleware{traceIDStatusCodeWare, retryWare},
+		[]pipeline.Middleware{traceIDStatusCodeWare, retryWare, queryStatsWare},
 		next)
 
 	searchPipeline := pipeline.Build(
 		[]pipeline.AsyncMiddleware[combiner.PipelineResponse]{
-			multiTenantMiddleware(cfg, logger),
+			newAsyncSearchFederationSharder(cfg.Federation, apiPrefix, logger),
+			multiTenantMiddleware(cfg, o, logger),
 			newAsyncSearchSharder(reader, o, cfg.Search.Sharder, logger),
 		},
-		[]pipeline.Middleware{cacheWare, statusCodeWare, retryWare},
+		[]pipeline.Middleware{cacheWare, statusCodeWare, retryWare, queryStatsWare},
 		next)
 
 	searchTagsPipeline := pipeline.Build(
 		[]pipeline.AsyncMiddleware[combiner.PipelineResponse]{
-			multiTenantMiddleware(cfg, logger),
+			multiTenantMiddleware(cfg, o, logger),
 			newAsyncTagSharder(reader, o, cfg.Search.Sharder, parseTagsRequest, logger),
 		},
-		[]pipeline.Middleware{cacheWare, statusCodeWare, retryWare},
+		[]pipeline.Middleware{cacheWare, statusCodeWare, retryWare, queryStatsWare},
 		next)
 
 	searchTagValuesPipeline := pipeline.Build(
 		[]pipeline.AsyncMiddleware[combiner.PipelineResponse]{
-			multiTenantMiddleware(cfg, logger),
+			multiTenantMiddleware(cfg, o, logger),
 			newAsyncTagSharder(reader, o, cfg.Search.Sharder, parseTagValuesRequest, logger),
 		},
-		[]pipeline.Middleware{cacheWare, statusCodeWare, retryWare},
+		[]pipeline.Middleware{cacheWare, statusCodeWare, retryWare, queryStatsWare},
 		next)
 
 	// metrics summary
@@ -121,74 +147,122 @@ func New(cfg Config, next http.RoundTripper, o overrides.Interface, reader tempo
 		[]pipeline.AsyncMiddleware[combiner.PipelineResponse]{
 			multiTenantUnsupportedMiddleware(cfg, logger),
 		},
-		[]pipeline.Middleware{statusCodeWare, retryWare},
+		[]pipeline.Middleware{statusCodeWare, retryWare, queryStatsWare},
 		next)
 
 	// traceql metrics
 	queryRangePipeline := pipeline.Build(
 		[]pipeline.AsyncMiddleware[combiner.PipelineResponse]{
-			multiTenantMiddleware(cfg, logger),
+			multiTenantMiddleware(cfg, o, logger),
 			newAsyncQueryRangeSharder(reader, o, cfg.Metrics.Sharder, logger),
 		},
-		[]pipeline.Middleware{cacheWare, statusCodeWare, retryWare},
+		[]pipeline.Middleware{cacheWare, statusCodeWare, retryWare, queryStatsWare},
 		next)
 
-	traces := newTraceIDHandler(cfg, o, tracePipeline, logger)
-	search := newSearchHTTPHandler(cfg, searchPipeline, logger)
+	traceByIDCache := newTraceByIDCache(cacheProvider, cfg.TraceByID.CacheMinTraceAge, logger)
+
+	traces := newTraceIDHandler(cfg, o, tracePipeline, reader, traceByIDCache, logger)
+	prefetcher := newTraceByIDPrefetcher(cfg, traces, traceByIDCache, apiPrefix, logger)
+	search := newSearchHTTPHandler(cfg, searchPipeline, prefetcher, logger)
 	searchTags := newTagHTTPHandler(cfg, searchTagsPipeline, o, combiner.NewSearchTags, logger)
 	searchTagsV2 := newTagHTTPHandler(cfg, searchTagsPipeline, o, combiner.NewSearchTagsV2, logger)
 	searchTagValues := newTagHTTPHandler(cfg, searchTagValuesPipeline, o, combiner.NewSearchTagValues, logger)
 	searchTagValuesV2 := newTagHTTPHandler(cfg, searchTagValuesPipeline, o, combiner.NewSearchTagValuesV2, logger)
 	metrics := newMetricsSummaryHandler(metricsPipeline, logger)
 	queryrange := newMetricsQueryRangeHTTPHandler(cfg, queryRangePipeline, logger)
+	audit := newAuditLogger(cfg.AuditLog, logger)
+	rateLimiter := newQueryRateLimiter(o)
 
 	return &QueryFrontend{
 		// http/discrete
-		TraceByIDHandler:          newHandler(cfg.Config.LogQueryRequestHeaders, traces, logger),
-		SearchHandler:             newHandler(cfg.Config.LogQueryRequestHeaders, search, logger),
-		SearchTagsHandler:         newHandler(cfg.Config.LogQueryRequestHeaders, searchTags, logger),
-		SearchTagsV2Handler:       newHandler(cfg.Config.LogQueryRequestHeaders, searchTagsV2, logger),
-		SearchTagsValuesHandler:   newHandler(cfg.Config.LogQueryRequestHeaders, searchTagValues, logger),
-		SearchTagsValuesV2Handler: newHandler(cfg.Config.LogQueryRequestHeaders, searchTagValuesV2, logger),
-		MetricsSummaryHandler:     newHandler(cfg.Config.LogQueryRequestHeaders, metrics, logger),
-		MetricsQueryRangeHandler:  newHandler(cfg.Config.LogQueryRequestHeaders, queryrange, logger),
+		TraceByIDHandler:          newHandler(cfg.Config.LogQueryRequestHeaders, traces, audit, rateLimiter, logger),
+		SearchHandler:             newHandler(cfg.Config.LogQueryRequestHeaders, search, audit, rateLimiter, logger),
+		SearchTagsHandler:         newHandler(cfg.Config.LogQueryRequestHeaders, searchTags, audit, rateLimiter, logger),
+		SearchTagsV2Handler:       newHandler(cfg.Config.LogQueryRequestHeaders, searchTagsV2, audit, rateLimiter, logger),
+		SearchTagsValuesHandler:   newHandler(cfg.Config.LogQueryRequestHeaders, searchTagValues, audit, rateLimiter, logger),
+		SearchTagsValuesV2Handler: newHandler(cfg.Config.LogQueryRequestHeaders, searchTagValuesV2, audit, rateLimiter, logger),
+		MetricsSummaryHandler:     newHandler(cfg.Config.LogQueryRequestHeaders, metrics, audit, rateLimiter, logger),
+		MetricsQueryRangeHandler:  newHandler(cfg.Config.LogQueryRequestHeaders, queryrange, audit, rateLimiter, logger),
 
 		// grpc/streaming
-		streamingSearch:      newSearchStreamingGRPCHandler(cfg, searchPipeline, apiPrefix, logger),
+		streamingSearch:      newSearchStreamingGRPCHandler(cfg, searchPipeline, prefetcher, apiPrefix, logger),
 		streamingTags:        newTagStreamingGRPCHandler(cfg, searchTagsPipeline, apiPrefix, o, logger),
 		streamingTagsV2:      newTagV2StreamingGRPCHandler(cfg, searchTagsPipeline, apiPrefix, o, logger),
 		streamingTagValues:   newTagValuesStreamingGRPCHandler(cfg, searchTagValuesPipeline, apiPrefix, o, logger),
 		streamingTagValuesV2: newTagValuesV2StreamingGRPCHandler(cfg, searchTagValuesPipeline, apiPrefix, o, logger),
 		streamingQueryRange:  newQueryRangeStreamingGRPCHandler(cfg, queryRangePipeline, apiPrefix, logger),
+		streamingTraceID:     newTraceIDStreamingGRPCHandler(cfg, o, tracePipeline, apiPrefix, logger),
 
 		cacheProvider: cacheProvider,
+		audit:         audit,
+		rateLimiter:   rateLimiter,
 		logger:        logger,
 	}, nil
 }
 
 // Search implements StreamingQuerierServer interface for streaming search
 func (q *QueryFrontend) Search(req *tempopb.SearchRequest, srv tempopb.StreamingQuerier_SearchServer) error {
-	return q.streamingSearch(req, srv)
+	err := q.rateLimiter.checkGRPC(srv.Context())
+	if err == nil {
+		err = q.streamingSearch(req, srv)
+	}
+	q.audit.recordGRPC(srv.Context(), "/tempopb.StreamingQuerier/Search", nil, req.Query, err)
+	return err
 }
 
 func (q *QueryFrontend) SearchTags(req *tempopb.SearchTagsRequest, srv tempopb.StreamingQuerier_SearchTagsServer) error {
-	return q.streamingTags(req, srv)
+	err := q.rateLimiter.checkGRPC(srv.Context())
+	if err == nil {
+		err = q.streamingTags(req, srv)
+	}
+	q.audit.recordGRPC(srv.Context(), "/tempopb.StreamingQuerier/SearchTags", nil, "", err)
+	return err
 }
 
 func (q *QueryFrontend) SearchTagsV2(req *tempopb.SearchTagsRequest, srv tempopb.StreamingQuerier_SearchTagsV2Server) error {
-	return q.streamingTagsV2(req, srv)
+	err := q.rateLimiter.checkGRPC(srv.Context())
+	if err == nil {
+		err = q.streamingTagsV2(req, srv)
+	}
+	q.audit.recordGRPC(srv.Context(), "/tempopb.StreamingQuerier/SearchTagsV2", nil, "", err)
+	return err
 }
 
 func (q *QueryFrontend) SearchTagValues(req *tempopb.SearchTagValuesRequest, srv tempopb.StreamingQuerier_SearchTagValuesServer) error {
-	return q.streamingTagValues(req, srv)
+	err := q.rateLimiter.checkGRPC(srv.Context())
+	if err == nil {
+		err = q.streamingTagValues(req, srv)
+	}
+	q.audit.recordGRPC(srv.Context(), "/tempopb.StreamingQuerier/SearchTagValues", nil, req.Query, err)
+	return err
 }
 
 func (q *QueryFrontend) SearchTagValuesV2(req *tempopb.SearchTagValuesRequest, srv tempopb.StreamingQuerier_SearchTagValuesV2Server) error {
-	return q.streamingTagValuesV2(req, srv)
+	err := q.rateLimiter.checkGRPC(srv.Context())
+	if err == nil {
+		err = q.streamingTagValuesV2(req, srv)
+	}
+	q.audit.recordGRPC(srv.Context(), "/tempopb.StreamingQuerier/SearchTagValuesV2", nil, req.Query, err)
+	return err
 }
 
 func (q *QueryFrontend) MetricsQueryRange(req *tempopb.QueryRangeRequest, srv tempopb.StreamingQuerier_MetricsQueryRangeServer) error {
-	return q.streamingQueryRange(req, srv)
+	err := q.rateLimiter.checkGRPC(srv.Context())
+	if err == nil {
+		err = q.streamingQueryRange(req, srv)
+	}
+	q.audit.recordGRPC(srv.Context(), "/tempopb.StreamingQuerier/MetricsQueryRange", nil, req.Query, err)
+	return err
+}
+
+// FindTraceByID implements StreamingQuerierServer interface for streaming trace by id
+func (q *QueryFrontend) FindTraceByID(req *tempopb.TraceByIDRequest, srv tempopb.StreamingQuerier_FindTraceByIDServer) error {
+	err := q.rateLimiter.checkGRPC(srv.Context())
+	if err == nil {
+		err = q.streamingTraceID(req, srv)
+	}
+	q.audit.recordGRPC(srv.Context(), "/tempopb.StreamingQuerier/FindTraceByID", req.TraceID, "", err)
+	return err
 }
 
 // newSpanMetricsMiddleware creates a new frontend middleware to handle metrics-generator requests.
@@ -247,9 +321,24 @@ func prepareRequestForQueriers(req *http.Request, tenant string, originalURI str
 	req.RequestURI = uri
 }
 
-func multiTenantMiddleware(cfg Config, logger log.Logger) pipeline.AsyncMiddleware[combiner.PipelineResponse] {
+// checkMaxQueryLookback returns an error if the query starts further back than the max query lookback of the tenant
+func checkMaxQueryLookback(o overrides.Interface, tenantID string, start time.Time) error {
+	maxLookback := o.MaxQueryLookback(tenantID)
+	if maxLookback == 0 {
+		return nil
+	}
+
+	earliest := time.Now().Add(-maxLookback)
+	if start.Before(earliest) {
+		return fmt.Errorf("start %s exceeds the max query lookback of %s. queries can't start before %s",
+			start.UTC().Format(time.RFC3339), maxLookback, earliest.UTC().Format(time.RFC3339))
+	}
+	return nil
+}
+
+func multiTenantMiddleware(cfg Config, o overrides.Interface, logger log.Logger) pipeline.AsyncMiddleware[combiner.PipelineResponse] {
 	if cfg.MultiTenantQueriesEnabled {
-		return pipeline.NewMultiTenantMiddleware(logger)
+		return pipeline.NewMultiTenantMiddleware(logger, o.AllowedTenantFederation)
 	}
 
 	return pipeline.NewNoopMiddleware()
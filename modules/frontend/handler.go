@@ -17,6 +17,7 @@ import (
 	"github.com/grafana/dskit/user"
 	"github.com/opentracing/opentracing-go"
 
+	"github.com/grafana/tempo/modules/frontend/pipeline"
 	"github.com/grafana/tempo/pkg/util/tracing"
 )
 
@@ -25,6 +26,8 @@ const (
 	StatusClientClosedRequest = 499
 	// nil response in ServeHTTP
 	NilResponseError = "nil resp in ServeHTTP"
+	// HeaderQueryStats is set on every response with statistics about the jobs executed for the query
+	HeaderQueryStats = "X-Tempo-Query-Stats"
 )
 
 var (
@@ -39,13 +42,17 @@ type handler struct {
 	roundTripper           http.RoundTripper
 	logger                 log.Logger
 	logQueryRequestHeaders flagext.StringSliceCSV
+	audit                  *auditLogger
+	rateLimiter            *queryRateLimiter
 }
 
 // newHandler creates a handler
-func newHandler(LogQueryRequestHeaders flagext.StringSliceCSV, rt http.RoundTripper, logger log.Logger) http.Handler {
+func newHandler(LogQueryRequestHeaders flagext.StringSliceCSV, rt http.RoundTripper, audit *auditLogger, rateLimiter *queryRateLimiter, logger log.Logger) http.Handler {
 	return &handler{
 		logQueryRequestHeaders: LogQueryRequestHeaders,
 		roundTripper:           rt,
+		audit:                  audit,
+		rateLimiter:            rateLimiter,
 		logger:                 logger,
 	}
 }
@@ -67,6 +74,15 @@ func (f *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		span.SetTag("orgID", orgID)
 	}
 
+	if err := f.rateLimiter.checkHTTP(w, r); err != nil {
+		level.Info(f.logger).Log("msg", "query rate limited", "tenant", orgID, "url", r.URL.RequestURI(), "err", err)
+		f.audit.recordHTTP(r, http.StatusTooManyRequests)
+		return
+	}
+
+	stats := pipeline.NewQueryStats()
+	r = pipeline.ContextAddQueryStats(stats, r)
+
 	resp, err := f.roundTripper.RoundTrip(r)
 	elapsed := time.Since(start)
 
@@ -91,6 +107,7 @@ func (f *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 			"response_size", 0,
 		)
 		level.Info(f.logger).Log(logMessage...)
+		f.audit.recordHTTP(r, grpcErrorToHTTPStatus(err))
 		return
 	}
 
@@ -104,11 +121,13 @@ func (f *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 			"response_size", 0,
 		)
 		level.Info(f.logger).Log(logMessage...)
+		f.audit.recordHTTP(r, grpcErrorToHTTPStatus(err))
 		return
 	}
 
 	// write headers, status code and body
 	copyHeader(w.Header(), resp.Header)
+	w.Header().Set(HeaderQueryStats, stats.Format(elapsed))
 	w.WriteHeader(resp.StatusCode)
 	if resp.Body != nil {
 		_, _ = io.Copy(w, resp.Body)
@@ -131,6 +150,7 @@ func (f *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		"status", statusCode,
 	)
 	level.Info(f.logger).Log(logMessage...)
+	f.audit.recordHTTP(r, statusCode)
 }
 
 func formatRequestHeaders(h *http.Header, headersToLog []string) (fields []interface{}) {
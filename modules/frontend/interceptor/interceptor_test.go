@@ -115,3 +115,7 @@ func (s *mockService) SearchTagValuesV2(*tempopb.SearchTagValuesRequest, tempopb
 func (s *mockService) MetricsQueryRange(*tempopb.QueryRangeRequest, tempopb.StreamingQuerier_MetricsQueryRangeServer) error {
 	return nil
 }
+
+func (s *mockService) FindTraceByID(*tempopb.TraceByIDRequest, tempopb.StreamingQuerier_FindTraceByIDServer) error {
+	return nil
+}
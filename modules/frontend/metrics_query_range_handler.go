@@ -17,11 +17,13 @@ import (
 
 	"github.com/grafana/tempo/pkg/api"
 	"github.com/grafana/tempo/pkg/tempopb"
+	"github.com/grafana/tempo/pkg/tenantusage"
 )
 
 // newQueryRangeStreamingGRPCHandler returns a handler that streams results from the HTTP handler
 func newQueryRangeStreamingGRPCHandler(cfg Config, next pipeline.AsyncRoundTripper[combiner.PipelineResponse], apiPrefix string, logger log.Logger) streamingQueryRangeHandler {
 	postSLOHook := metricsSLOPostHook(cfg.Metrics.SLO)
+	slowQueries := newSlowQueryLogger(cfg.LogQueriesLongerThan, logger)
 	downstreamPath := path.Join(apiPrefix, api.PathMetricsQueryRange)
 
 	return func(req *tempopb.QueryRangeRequest, srv tempopb.StreamingQuerier_MetricsQueryRangeServer) error {
@@ -56,7 +58,9 @@ func newQueryRangeStreamingGRPCHandler(cfg Config, next pipeline.AsyncRoundTripp
 			bytesProcessed = finalResponse.Metrics.InspectedBytes
 		}
 		postSLOHook(nil, tenant, bytesProcessed, duration, err)
+		cfg.TenantUsage.Add(tenant, tenantusage.Queried, bytesProcessed)
 		logQueryRangeResult(logger, tenant, duration.Seconds(), req, finalResponse, err)
+		slowQueries.log(metricsOp, tenant, httpReq.URL.Query(), queryRangeMetrics(finalResponse), duration, err)
 		return err
 	}
 }
@@ -64,6 +68,7 @@ func newQueryRangeStreamingGRPCHandler(cfg Config, next pipeline.AsyncRoundTripp
 // newMetricsQueryRangeHTTPHandler returns a handler that returns a single response from the HTTP handler
 func newMetricsQueryRangeHTTPHandler(cfg Config, next pipeline.AsyncRoundTripper[combiner.PipelineResponse], logger log.Logger) http.RoundTripper {
 	postSLOHook := metricsSLOPostHook(cfg.Metrics.SLO)
+	slowQueries := newSlowQueryLogger(cfg.LogQueriesLongerThan, logger)
 
 	return pipeline.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
 		tenant, _ := user.ExtractOrgID(req.Context())
@@ -105,11 +110,22 @@ func newMetricsQueryRangeHTTPHandler(cfg Config, next pipeline.AsyncRoundTripper
 
 		duration := time.Since(start)
 		postSLOHook(resp, tenant, bytesProcessed, duration, err)
+		cfg.TenantUsage.Add(tenant, tenantusage.Queried, bytesProcessed)
 		logQueryRangeResult(logger, tenant, duration.Seconds(), queryRangeReq, queryRangeResp, err)
+		slowQueries.log(metricsOp, tenant, api.BuildQueryRangeRequest(nil, queryRangeReq).URL.Query(), queryRangeMetrics(queryRangeResp), duration, err)
+		pipeline.QueryStatsFromContext(req.Context()).SetSearchMetrics(queryRangeMetrics(queryRangeResp))
 		return resp, err
 	})
 }
 
+// queryRangeMetrics returns the metrics of a possibly nil query range response
+func queryRangeMetrics(resp *tempopb.QueryRangeResponse) *tempopb.SearchMetrics {
+	if resp == nil {
+		return nil
+	}
+	return resp.Metrics
+}
+
 func logQueryRangeResult(logger log.Logger, tenantID string, durationSeconds float64, req *tempopb.QueryRangeRequest, resp *tempopb.QueryRangeResponse, err error) {
 	if resp == nil {
 		level.Info(logger).Log(
@@ -98,6 +98,11 @@ func (s queryRangeSharder) RoundTrip(r *http.Request) (pipeline.Responses[combin
 		return pipeline.NewBadRequest(err), nil
 	}
 
+	// enforce max query lookback
+	if err := checkMaxQueryLookback(s.overrides, tenantID, time.Unix(0, int64(req.Start))); err != nil {
+		return pipeline.NewBadRequest(err), nil
+	}
+
 	var (
 		allowUnsafe           = s.overrides.UnsafeQueryHints(tenantID)
 		samplingRate          = s.samplingRate(expr, allowUnsafe)
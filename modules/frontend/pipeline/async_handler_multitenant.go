@@ -3,6 +3,7 @@ package pipeline
 import (
 	"context"
 	"errors"
+	"fmt"
 	"net/http"
 	"strings"
 
@@ -13,23 +14,31 @@ import (
 	"github.com/grafana/tempo/modules/frontend/combiner"
 )
 
-var ErrMultiTenantUnsupported = errors.New("multi-tenant query unsupported")
+var (
+	ErrMultiTenantUnsupported     = errors.New("multi-tenant query unsupported")
+	ErrTenantFederationNotAllowed = errors.New("tenant not allowed in multi-tenant query")
+)
+
+// TenantFederationFunc returns true if the tenant is allowed to be included in a multi-tenant query
+type TenantFederationFunc func(tenantID string) bool
 
 type tenantRoundTripper struct {
 	next   AsyncRoundTripper[combiner.PipelineResponse]
 	logger log.Logger
 
-	resolver tenant.Resolver
+	resolver        tenant.Resolver
+	allowFederation TenantFederationFunc
 }
 
-// NewMultiTenantMiddleware returns a middleware that takes a request and fans it out to each tenant
-// It currently accepts a success and failure counter, to prevent metrics collisions with
-func NewMultiTenantMiddleware(logger log.Logger) AsyncMiddleware[combiner.PipelineResponse] {
+// NewMultiTenantMiddleware returns a middleware that takes a request and fans it out to each tenant.
+// allowFederation is checked for every tenant in a multi-tenant query. if it is nil all tenants are allowed.
+func NewMultiTenantMiddleware(logger log.Logger, allowFederation TenantFederationFunc) AsyncMiddleware[combiner.PipelineResponse] {
 	return AsyncMiddlewareFunc[combiner.PipelineResponse](func(next AsyncRoundTripper[combiner.PipelineResponse]) AsyncRoundTripper[combiner.PipelineResponse] {
 		return &tenantRoundTripper{
-			next:     next,
-			logger:   logger,
-			resolver: tenant.NewMultiResolver(),
+			next:            next,
+			logger:          logger,
+			resolver:        tenant.NewMultiResolver(),
+			allowFederation: allowFederation,
 		}
 	})
 }
@@ -48,6 +57,15 @@ func (t *tenantRoundTripper) RoundTrip(req *http.Request) (Responses[combiner.Pi
 		return t.next.RoundTrip(req)
 	}
 
+	// every tenant has to opt in to be queried alongside other tenants
+	if t.allowFederation != nil {
+		for _, tenantID := range tenants {
+			if !t.allowFederation(tenantID) {
+				return NewBadRequest(fmt.Errorf("%w: %s", ErrTenantFederationNotAllowed, tenantID)), nil
+			}
+		}
+	}
+
 	// join tenants for logger because list value type is unsupported.
 	_ = level.Debug(t.logger).Log("msg", "handling multi-tenant query", "tenants", strings.Join(tenants, ","))
 
@@ -36,7 +36,7 @@ func TestMultiTenant(t *testing.T) {
 
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
-			tenantMiddleware := NewMultiTenantMiddleware(log.NewNopLogger())
+			tenantMiddleware := NewMultiTenantMiddleware(log.NewNopLogger(), nil)
 
 			var reqCount atomic.Int32
 
@@ -109,6 +109,76 @@ func TestMultiTenant(t *testing.T) {
 	}
 }
 
+func TestMultiTenantFederationNotAllowed(t *testing.T) {
+	tests := []struct {
+		name           string
+		tenants        string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "single tenant not allowed",
+			tenants:        "denied",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "foo",
+		},
+		{
+			name:           "all tenants allowed",
+			tenants:        "tenant-1|tenant-2",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "foo",
+		},
+		{
+			name:           "one tenant not allowed",
+			tenants:        "tenant-1|denied",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   ErrTenantFederationNotAllowed.Error() + ": denied",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			allowFederation := func(tenantID string) bool {
+				return tenantID != "denied"
+			}
+
+			var reqCount atomic.Int32
+			next := AsyncRoundTripperFunc[combiner.PipelineResponse](func(*http.Request) (Responses[combiner.PipelineResponse], error) {
+				reqCount.Inc()
+				return NewSuccessfulResponse("foo"), nil
+			})
+
+			rt := NewMultiTenantMiddleware(log.NewNopLogger(), allowFederation).Wrap(next)
+
+			req := httptest.NewRequest("GET", "/", nil)
+			req = req.WithContext(user.InjectOrgID(context.Background(), tc.tenants))
+
+			resps, err := rt.RoundTrip(req)
+			require.NoError(t, err)
+
+			for {
+				r, done, err := resps.Next(context.Background())
+				if done {
+					break
+				}
+				require.NoError(t, err)
+
+				res := r.HTTPResponse()
+				require.Equal(t, tc.expectedStatus, res.StatusCode)
+
+				actualBody, err := io.ReadAll(res.Body)
+				require.NoError(t, err)
+				require.Equal(t, tc.expectedBody, string(actualBody))
+			}
+
+			// denied queries never reach the next round tripper
+			if tc.expectedStatus != http.StatusOK {
+				require.Equal(t, int32(0), reqCount.Load())
+			}
+		})
+	}
+}
+
 func TestMultiTenantNotSupported(t *testing.T) {
 	tests := []struct {
 		name         string
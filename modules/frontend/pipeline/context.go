@@ -21,3 +21,18 @@ var contextEchoAdditionalData = struct{}{}
 func ContextAddAdditionalData(val any, req *http.Request) *http.Request {
 	return req.WithContext(context.WithValue(req.Context(), contextEchoAdditionalData, val))
 }
+
+// contextQueryStatsKey is used to collect query statistics through the pipeline. It stores a *QueryStats value.
+// it uses its own type so it doesn't collide with the keys above.
+type contextQueryStatsKey struct{}
+
+func ContextAddQueryStats(stats *QueryStats, req *http.Request) *http.Request {
+	return req.WithContext(context.WithValue(req.Context(), contextQueryStatsKey{}, stats))
+}
+
+// QueryStatsFromContext returns the query stats stored in the context or nil if there are none. All methods on
+// a nil *QueryStats are safe to call.
+func QueryStatsFromContext(ctx context.Context) *QueryStats {
+	stats, _ := ctx.Value(contextQueryStatsKey{}).(*QueryStats)
+	return stats
+}
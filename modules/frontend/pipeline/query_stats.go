@@ -0,0 +1,125 @@
+package pipeline
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"go.uber.org/atomic"
+
+	"github.com/grafana/tempo/pkg/tempopb"
+)
+
+// QueryStats collects statistics about a single query as its jobs move through the pipeline. It is safe for
+// concurrent use and all methods can be called on a nil *QueryStats.
+type QueryStats struct {
+	jobs         atomic.Uint64
+	ingesterJobs atomic.Uint64
+	cacheHits    atomic.Uint64
+	cacheMisses  atomic.Uint64
+	querierTime  atomic.Duration
+
+	inspectedBlocks atomic.Uint64
+	inspectedBytes  atomic.Uint64
+}
+
+func NewQueryStats() *QueryStats {
+	return &QueryStats{}
+}
+
+func (s *QueryStats) addJob(ingester bool, duration time.Duration) {
+	if s == nil {
+		return
+	}
+
+	s.jobs.Inc()
+	if ingester {
+		s.ingesterJobs.Inc()
+	}
+	s.querierTime.Add(duration)
+}
+
+func (s *QueryStats) addCacheLookup(hit bool) {
+	if s == nil {
+		return
+	}
+
+	if hit {
+		s.cacheHits.Inc()
+	} else {
+		s.cacheMisses.Inc()
+	}
+}
+
+// SetSearchMetrics records the blocks and bytes inspected from the combined metrics of a search or metrics query
+func (s *QueryStats) SetSearchMetrics(m *tempopb.SearchMetrics) {
+	if s == nil || m == nil {
+		return
+	}
+
+	s.inspectedBlocks.Store(uint64(m.TotalBlocks))
+	s.inspectedBytes.Store(m.InspectedBytes)
+}
+
+// Jobs returns the number of jobs sent to the queriers. Retries are counted as separate jobs.
+func (s *QueryStats) Jobs() uint64 {
+	if s == nil {
+		return 0
+	}
+	return s.jobs.Load()
+}
+
+// IngesterJobs returns the number of jobs that queried ingesters or metrics-generators for recent data.
+func (s *QueryStats) IngesterJobs() uint64 {
+	if s == nil {
+		return 0
+	}
+	return s.ingesterJobs.Load()
+}
+
+// CacheHitRatio returns the ratio of jobs answered from the cache. ok is false if no job was eligible for caching.
+func (s *QueryStats) CacheHitRatio() (ratio float64, ok bool) {
+	if s == nil {
+		return 0, false
+	}
+
+	hits, misses := s.cacheHits.Load(), s.cacheMisses.Load()
+	if hits+misses == 0 {
+		return 0, false
+	}
+	return float64(hits) / float64(hits+misses), true
+}
+
+// QuerierTime returns the sum of the time spent waiting on every job. Jobs run in parallel so this is usually
+// larger than the duration of the query.
+func (s *QueryStats) QuerierTime() time.Duration {
+	if s == nil {
+		return 0
+	}
+	return s.querierTime.Load()
+}
+
+// Format returns the stats as a comma separated list of key=value pairs. total is the wall time of the whole query.
+func (s *QueryStats) Format(total time.Duration) string {
+	if s == nil {
+		return ""
+	}
+
+	fields := []string{
+		fmt.Sprintf("total_seconds=%.3f", total.Seconds()),
+		fmt.Sprintf("querier_seconds=%.3f", s.QuerierTime().Seconds()),
+		fmt.Sprintf("jobs=%d", s.Jobs()),
+		fmt.Sprintf("ingester_jobs=%d", s.IngesterJobs()),
+		fmt.Sprintf("cache_hits=%d", s.cacheHits.Load()),
+		fmt.Sprintf("cache_misses=%d", s.cacheMisses.Load()),
+	}
+	if ratio, ok := s.CacheHitRatio(); ok {
+		fields = append(fields, fmt.Sprintf("cache_hit_ratio=%.2f", ratio))
+	}
+	fields = append(fields,
+		fmt.Sprintf("inspected_blocks=%d", s.inspectedBlocks.Load()),
+		fmt.Sprintf("inspected_bytes=%d", s.inspectedBytes.Load()),
+	)
+
+	return strings.Join(fields, ", ")
+}
@@ -39,6 +39,7 @@ func (c cachingWare) RoundTrip(req *http.Request) (*http.Response, error) {
 	key, ok := req.Context().Value(contextCacheKey).(string)
 	if ok && len(key) > 0 {
 		body := c.cache.fetchBytes(key)
+		QueryStatsFromContext(req.Context()).addCacheLookup(len(body) > 0)
 		if len(body) > 0 {
 			resp := &http.Response{
 				Header:     http.Header{},
@@ -0,0 +1,45 @@
+package pipeline
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/grafana/tempo/pkg/api"
+)
+
+type queryStatsWare struct {
+	next http.RoundTripper
+}
+
+// NewQueryStatsWare records every job and the time spent waiting on it in the QueryStats of the request context.
+// It should be the last sync middleware so retries are counted as separate jobs and cached jobs are not counted.
+func NewQueryStatsWare() Middleware {
+	return MiddlewareFunc(func(next http.RoundTripper) http.RoundTripper {
+		return queryStatsWare{
+			next: next,
+		}
+	})
+}
+
+// RoundTrip implements http.RoundTripper
+func (c queryStatsWare) RoundTrip(req *http.Request) (*http.Response, error) {
+	stats := QueryStatsFromContext(req.Context())
+	if stats == nil {
+		return c.next.RoundTrip(req)
+	}
+
+	start := time.Now()
+	resp, err := c.next.RoundTrip(req)
+	stats.addJob(isIngesterJob(req), time.Since(start))
+
+	return resp, err
+}
+
+// isIngesterJob returns true if the job doesn't target backend blocks. backend jobs either have a block id or, for
+// trace by id, the blocks query mode.
+func isIngesterJob(req *http.Request) bool {
+	if api.IsSearchBlock(req) {
+		return false
+	}
+	return req.URL.Query().Get(api.QueryModeKey) != api.QueryModeBlocks
+}
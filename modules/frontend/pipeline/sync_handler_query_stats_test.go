@@ -0,0 +1,63 @@
+package pipeline
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/pkg/tempopb"
+)
+
+func TestQueryStatsWare(t *testing.T) {
+	next := RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		time.Sleep(10 * time.Millisecond)
+		return &http.Response{StatusCode: http.StatusOK}, nil
+	})
+	rt := NewQueryStatsWare().Wrap(next)
+
+	stats := NewQueryStats()
+	for _, url := range []string{
+		"http://example.com/api/search?blockID=1234",
+		"http://example.com/api/search?start=1&end=2",
+		"http://example.com/api/traces/1234?mode=blocks",
+		"http://example.com/api/traces/1234?mode=ingesters",
+	} {
+		req := ContextAddQueryStats(stats, httptest.NewRequest("GET", url, nil))
+		_, err := rt.RoundTrip(req)
+		require.NoError(t, err)
+	}
+
+	require.Equal(t, uint64(4), stats.Jobs())
+	require.Equal(t, uint64(2), stats.IngesterJobs())
+	require.GreaterOrEqual(t, stats.QuerierTime(), 40*time.Millisecond)
+
+	// requests without stats are passed through
+	_, err := rt.RoundTrip(httptest.NewRequest("GET", "http://example.com/api/search", nil))
+	require.NoError(t, err)
+	require.Equal(t, uint64(4), stats.Jobs())
+}
+
+func TestQueryStatsFormat(t *testing.T) {
+	stats := NewQueryStats()
+	stats.addJob(true, time.Second)
+	stats.addJob(false, 2*time.Second)
+	stats.addCacheLookup(true)
+	stats.addCacheLookup(false)
+	stats.addCacheLookup(false)
+	stats.addCacheLookup(false)
+	stats.SetSearchMetrics(&tempopb.SearchMetrics{TotalBlocks: 3, InspectedBytes: 1024})
+
+	require.Equal(t, "total_seconds=1.500, querier_seconds=3.000, jobs=2, ingester_jobs=1, cache_hits=1, cache_misses=3, cache_hit_ratio=0.25, inspected_blocks=3, inspected_bytes=1024",
+		stats.Format(1500*time.Millisecond))
+
+	// nil stats are safe to use
+	var nilStats *QueryStats
+	nilStats.addJob(true, time.Second)
+	nilStats.addCacheLookup(true)
+	nilStats.SetSearchMetrics(&tempopb.SearchMetrics{})
+	require.Equal(t, uint64(0), nilStats.Jobs())
+	require.Equal(t, "", nilStats.Format(time.Second))
+}
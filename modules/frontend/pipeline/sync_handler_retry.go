@@ -6,7 +6,9 @@ import (
 	"io"
 	"net/http"
 	"strings"
+	"time"
 
+	"github.com/grafana/dskit/backoff"
 	"github.com/grafana/dskit/httpgrpc"
 	"github.com/grafana/tempo/modules/frontend/queue"
 	"github.com/opentracing/opentracing-go"
@@ -16,7 +18,10 @@ import (
 	"github.com/prometheus/client_golang/prometheus/promauto"
 )
 
-func NewRetryWare(maxRetries int, registerer prometheus.Registerer) Middleware {
+// NewRetryWare creates a middleware that retries requests that fail with an error or a 5xx up to maxRetries times.
+// If minBackoff is greater than 0 the middleware waits an exponentially increasing, jittered amount of time between
+// minBackoff and maxBackoff before each retry.
+func NewRetryWare(maxRetries int, minBackoff, maxBackoff time.Duration, registerer prometheus.Registerer) Middleware {
 	retriesCount := promauto.With(registerer).NewHistogram(prometheus.HistogramOpts{
 		Namespace: "tempo",
 		Name:      "query_frontend_retries",
@@ -28,6 +33,8 @@ func NewRetryWare(maxRetries int, registerer prometheus.Registerer) Middleware {
 		return retryWare{
 			next:         next,
 			maxRetries:   maxRetries,
+			minBackoff:   minBackoff,
+			maxBackoff:   maxBackoff,
 			retriesCount: retriesCount,
 		}
 	})
@@ -36,6 +43,8 @@ func NewRetryWare(maxRetries int, registerer prometheus.Registerer) Middleware {
 type retryWare struct {
 	next         http.RoundTripper
 	maxRetries   int
+	minBackoff   time.Duration
+	maxBackoff   time.Duration
 	retriesCount prometheus.Histogram
 }
 
@@ -52,6 +61,14 @@ func (r retryWare) RoundTrip(req *http.Request) (*http.Response, error) {
 	tries := 0
 	defer func() { r.retriesCount.Observe(float64(tries)) }()
 
+	var b *backoff.Backoff
+	if r.minBackoff > 0 {
+		b = backoff.New(ctx, backoff.Config{
+			MinBackoff: r.minBackoff,
+			MaxBackoff: r.maxBackoff,
+		})
+	}
+
 	for {
 		if ctx.Err() != nil {
 			return nil, ctx.Err()
@@ -112,6 +129,15 @@ func (r retryWare) RoundTrip(req *http.Request) (*http.Response, error) {
 			ot_log.Int("status_code", statusCode),
 			ot_log.String("errMsg", errMsg),
 		)
+
+		// the response is discarded. close the body so the connection can be reused
+		if resp != nil && resp.Body != nil {
+			_ = resp.Body.Close()
+		}
+
+		if b != nil {
+			b.Wait()
+		}
 	}
 }
 
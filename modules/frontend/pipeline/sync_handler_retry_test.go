@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/grafana/dskit/httpgrpc"
 	"github.com/prometheus/client_golang/prometheus"
@@ -109,7 +110,7 @@ func TestRetry(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			try.Store(0)
 
-			retryWare := NewRetryWare(tc.maxRetries, prometheus.NewRegistry())
+			retryWare := NewRetryWare(tc.maxRetries, 0, 0, prometheus.NewRegistry())
 			handler := retryWare.Wrap(tc.handler)
 
 			req := httptest.NewRequest("GET", "http://example.com", nil)
@@ -133,7 +134,7 @@ func TestRetry_CancelledRequest(t *testing.T) {
 	req, err := http.NewRequestWithContext(ctx, "GET", "http://example.com", nil)
 	require.NoError(t, err)
 
-	_, err = NewRetryWare(5, prometheus.NewRegistry()).
+	_, err = NewRetryWare(5, 0, 0, prometheus.NewRegistry()).
 		Wrap(RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
 			try.Inc()
 			return nil, ctx.Err()
@@ -148,7 +149,7 @@ func TestRetry_CancelledRequest(t *testing.T) {
 	req, err = http.NewRequestWithContext(ctx, "GET", "http://example.com", nil)
 	require.NoError(t, err)
 
-	_, err = NewRetryWare(5, prometheus.NewRegistry()).
+	_, err = NewRetryWare(5, 0, 0, prometheus.NewRegistry()).
 		Wrap(RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
 			try.Inc()
 			cancel()
@@ -158,3 +159,36 @@ func TestRetry_CancelledRequest(t *testing.T) {
 	require.Equal(t, int32(1), try.Load())
 	require.Equal(t, ctx.Err(), err)
 }
+
+func TestRetry_Backoff(t *testing.T) {
+	var try atomic.Int32
+
+	handler := RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		try.Inc()
+		return &http.Response{StatusCode: 500}, nil
+	})
+
+	req := httptest.NewRequest("GET", "http://example.com", nil)
+
+	// waits between each try
+	start := time.Now()
+	res, err := NewRetryWare(3, 50*time.Millisecond, 50*time.Millisecond, prometheus.NewRegistry()).Wrap(handler).RoundTrip(req)
+	require.NoError(t, err)
+	require.Equal(t, 500, res.StatusCode)
+	require.Equal(t, int32(3), try.Load())
+	require.GreaterOrEqual(t, time.Since(start), 100*time.Millisecond)
+
+	// cancelling the request stops the backoff
+	try.Store(0)
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	req, err = http.NewRequestWithContext(ctx, "GET", "http://example.com", nil)
+	require.NoError(t, err)
+
+	start = time.Now()
+	_, err = NewRetryWare(3, time.Minute, time.Minute, prometheus.NewRegistry()).Wrap(handler).RoundTrip(req)
+	require.Equal(t, context.Canceled, err)
+	require.Equal(t, int32(1), try.Load())
+	require.Less(t, time.Since(start), time.Minute)
+}
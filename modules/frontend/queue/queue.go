@@ -58,14 +58,20 @@ type RequestQueue struct {
 
 	queueLength       *prometheus.GaugeVec   // Per user and reason.
 	discardedRequests *prometheus.CounterVec // Per user.
+
+	onShed func(Request)
 }
 
-func NewRequestQueue(maxOutstandingPerTenant int, forgetDelay time.Duration, queueLength *prometheus.GaugeVec, discardedRequests *prometheus.CounterVec) *RequestQueue {
+// NewRequestQueue creates a queue that holds up to maxOutstandingPerTenant requests per tenant. If onShed is not nil,
+// the oldest request of a full tenant queue is removed and passed to onShed to make room for a new request instead of
+// rejecting the new request.
+func NewRequestQueue(maxOutstandingPerTenant int, forgetDelay time.Duration, queueLength *prometheus.GaugeVec, discardedRequests *prometheus.CounterVec, onShed func(Request)) *RequestQueue {
 	q := &RequestQueue{
 		queues:                  newUserQueues(maxOutstandingPerTenant, forgetDelay),
 		connectedQuerierWorkers: atomic.NewInt32(0),
 		queueLength:             queueLength,
 		discardedRequests:       discardedRequests,
+		onShed:                  onShed,
 	}
 
 	q.cond = contextCond{Cond: sync.NewCond(&q.mtx)}
@@ -101,9 +107,30 @@ func (q *RequestQueue) EnqueueRequest(userID string, req Request, maxQueriers in
 		q.cond.Broadcast()
 		return nil
 	default:
-		q.discardedRequests.WithLabelValues(userID).Inc()
-		return ErrTooManyRequests
 	}
+
+	// the queue is full. shed the oldest request to make room if configured. requests are only dequeued by queriers
+	// under the write lock but other requests of the tenant may be enqueued concurrently so both can fail
+	if q.onShed != nil {
+		select {
+		case shed := <-queue:
+			q.queueLength.WithLabelValues(userID).Dec()
+			q.discardedRequests.WithLabelValues(userID).Inc()
+			q.onShed(shed)
+		default:
+		}
+
+		select {
+		case queue <- req:
+			q.queueLength.WithLabelValues(userID).Inc()
+			q.cond.Broadcast()
+			return nil
+		default:
+		}
+	}
+
+	q.discardedRequests.WithLabelValues(userID).Inc()
+	return ErrTooManyRequests
 }
 
 // getQueueUnderRlock attempts to get the queue for the given user under read lock. if it is not
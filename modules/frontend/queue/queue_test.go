@@ -160,7 +160,7 @@ func queueWithListeners(ctx context.Context, listeners int, batchSize int, liste
 		Name: "test_discarded",
 	}, []string{"user"})
 
-	q := NewRequestQueue(100_000, 0, g, c)
+	q := NewRequestQueue(100_000, 0, g, c, nil)
 	start := make(chan struct{})
 
 	for i := 0; i < listeners; i++ {
@@ -192,7 +192,7 @@ func TestRequestQueue_GetNextRequestForQuerier_ShouldGetRequestAfterReshardingBe
 
 	queue := NewRequestQueue(1, forgetDelay,
 		prometheus.NewGaugeVec(prometheus.GaugeOpts{}, []string{"user"}),
-		prometheus.NewCounterVec(prometheus.CounterOpts{}, []string{"user"}))
+		prometheus.NewCounterVec(prometheus.CounterOpts{}, []string{"user"}), nil)
 
 	// Start the queue service.
 	ctx := context.Background()
@@ -380,3 +380,36 @@ func assertChanNotReceived(t *testing.T, c chan struct{}, wait time.Duration, ms
 		// OK!
 	}
 }
+
+func TestRequestQueueFull(t *testing.T) {
+	g := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "test_len"}, []string{"user"})
+	c := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "test_discarded"}, []string{"user"})
+
+	first, second, third := "first", "second", "third"
+
+	// the new request is rejected
+	q := NewRequestQueue(2, 0, g, c, nil)
+	require.NoError(t, q.EnqueueRequest("test", first, 0))
+	require.NoError(t, q.EnqueueRequest("test", second, 0))
+	require.ErrorIs(t, q.EnqueueRequest("test", third, 0), ErrTooManyRequests)
+
+	reqs, _, err := q.GetNextRequestForQuerier(context.Background(), FirstUser(), "", make([]Request, 3))
+	require.NoError(t, err)
+	require.Equal(t, []Request{first, second}, reqs)
+
+	// the oldest request is shed
+	var shed []Request
+	q = NewRequestQueue(2, 0, g, c, func(r Request) { shed = append(shed, r) })
+	require.NoError(t, q.EnqueueRequest("test", first, 0))
+	require.NoError(t, q.EnqueueRequest("test", second, 0))
+	require.NoError(t, q.EnqueueRequest("test", third, 0))
+	require.Equal(t, []Request{first}, shed)
+
+	reqs, _, err = q.GetNextRequestForQuerier(context.Background(), FirstUser(), "", make([]Request, 3))
+	require.NoError(t, err)
+	require.Equal(t, []Request{second, third}, reqs)
+
+	discarded, err := test.GetCounterVecValue(c, "test")
+	require.NoError(t, err)
+	require.Equal(t, float64(2), discarded)
+}
@@ -0,0 +1,131 @@
+package frontend
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/grafana/dskit/limiter"
+	"github.com/grafana/dskit/tenant"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"golang.org/x/time/rate"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/grafana/tempo/modules/overrides"
+)
+
+// queryRateLimiterRecheckPeriod is how often the limits of a tenant are reloaded from the overrides
+const queryRateLimiterRecheckPeriod = 10 * time.Second
+
+var metricQueriesRateLimited = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "tempo",
+	Name:      "query_frontend_queries_rate_limited_total",
+	Help:      "Total queries rejected because the tenant exceeded its max queries per second.",
+}, []string{"tenant"})
+
+// queryRateStrategy limits the queries of a tenant to its max_queries_per_second override. the burst defaults to one
+// second of queries
+type queryRateStrategy struct {
+	limits overrides.Interface
+}
+
+func (s *queryRateStrategy) Limit(tenantID string) float64 {
+	limit := s.limits.MaxQueriesPerSecond(tenantID)
+	if limit <= 0 {
+		return float64(rate.Inf)
+	}
+	return limit
+}
+
+func (s *queryRateStrategy) Burst(tenantID string) int {
+	burst := s.limits.QueryBurstSize(tenantID)
+	if burst <= 0 {
+		burst = int(math.Ceil(s.limits.MaxQueriesPerSecond(tenantID)))
+	}
+	return max(burst, 1)
+}
+
+// queryRateLimitedError is returned for a query of a tenant that exceeded its max queries per second
+type queryRateLimitedError struct {
+	tenantID   string
+	limit      float64
+	retryAfter time.Duration
+}
+
+func (e *queryRateLimitedError) Error() string {
+	return fmt.Sprintf("tenant %s exceeded its limit of %g queries per second, retry after %s", e.tenantID, e.limit, e.retryAfter)
+}
+
+// queryRateLimiter enforces the max queries per second of the tenants in each query frontend. a nil queryRateLimiter
+// allows every query
+type queryRateLimiter struct {
+	limits  overrides.Interface
+	limiter *limiter.RateLimiter
+	now     func() time.Time
+}
+
+func newQueryRateLimiter(o overrides.Interface) *queryRateLimiter {
+	return &queryRateLimiter{
+		limits:  o,
+		limiter: limiter.NewRateLimiter(&queryRateStrategy{limits: o}, queryRateLimiterRecheckPeriod),
+		now:     time.Now,
+	}
+}
+
+// check returns a *queryRateLimitedError if any tenant of the query exceeded its max queries per second. requests
+// without a tenant are left to the handlers to reject
+func (l *queryRateLimiter) check(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+
+	tenants, err := tenant.TenantIDs(ctx)
+	if err != nil {
+		return nil
+	}
+
+	now := l.now()
+	for _, tenantID := range tenants {
+		limit := l.limits.MaxQueriesPerSecond(tenantID)
+		if limit <= 0 {
+			continue
+		}
+		if l.limiter.AllowN(now, tenantID, 1) {
+			continue
+		}
+
+		metricQueriesRateLimited.WithLabelValues(tenantID).Inc()
+		return &queryRateLimitedError{
+			tenantID:   tenantID,
+			limit:      limit,
+			retryAfter: time.Duration(math.Ceil(1/limit)) * time.Second,
+		}
+	}
+	return nil
+}
+
+// checkHTTP writes a 429 with the Retry-After header and returns an error if any tenant of the request exceeded its
+// max queries per second
+func (l *queryRateLimiter) checkHTTP(w http.ResponseWriter, r *http.Request) error {
+	err := l.check(r.Context())
+	if err == nil {
+		return nil
+	}
+
+	w.Header().Set("Retry-After", strconv.Itoa(int(err.(*queryRateLimitedError).retryAfter.Seconds())))
+	http.Error(w, err.Error(), http.StatusTooManyRequests)
+	return err
+}
+
+// checkGRPC returns a resource exhausted error if any tenant of the streaming query exceeded its max queries per second
+func (l *queryRateLimiter) checkGRPC(ctx context.Context) error {
+	if err := l.check(ctx); err != nil {
+		return status.Error(codes.ResourceExhausted, err.Error())
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package frontend
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/grafana/dskit/user"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/grafana/tempo/modules/frontend/pipeline"
+	"github.com/grafana/tempo/modules/overrides"
+)
+
+func TestQueryRateLimiter(t *testing.T) {
+	o, err := overrides.NewOverrides(overrides.Config{
+		Defaults: overrides.Overrides{
+			Read: overrides.ReadOverrides{
+				MaxQueriesPerSecond: 1,
+				QueryBurstSize:      2,
+			},
+		},
+	}, nil, prometheus.NewRegistry())
+	require.NoError(t, err)
+
+	now := time.Now()
+	limiter := newQueryRateLimiter(o)
+	limiter.now = func() time.Time { return now }
+
+	next := pipeline.RoundTripperFunc(func(*http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(""))}, nil
+	})
+	handler := newHandler(nil, next, nil, limiter, log.NewNopLogger())
+
+	query := func(tenant string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/search", nil)
+		req = req.WithContext(user.InjectOrgID(req.Context(), tenant))
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// the burst is allowed
+	require.Equal(t, http.StatusOK, query("a").Code)
+	require.Equal(t, http.StatusOK, query("a").Code)
+
+	rec := query("a")
+	require.Equal(t, http.StatusTooManyRequests, rec.Code)
+	require.Equal(t, "1", rec.Header().Get("Retry-After"))
+	require.Contains(t, rec.Body.String(), "tenant a exceeded its limit of 1 queries per second")
+
+	// tenants are limited separately, including in multi-tenant queries
+	require.Equal(t, http.StatusOK, query("b").Code)
+	require.Equal(t, http.StatusTooManyRequests, query("b|a").Code)
+
+	// streaming queries are limited too
+	err = limiter.checkGRPC(user.InjectOrgID(context.Background(), "a"))
+	require.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+	now = now.Add(time.Second)
+	require.Equal(t, http.StatusOK, query("a").Code)
+
+	// no limit
+	o, err = overrides.NewOverrides(overrides.Config{}, nil, prometheus.NewRegistry())
+	require.NoError(t, err)
+	handler = newHandler(nil, next, nil, newQueryRateLimiter(o), log.NewNopLogger())
+	for i := 0; i < 100; i++ {
+		require.Equal(t, http.StatusOK, query("a").Code)
+	}
+}
@@ -20,11 +20,13 @@ import (
 
 	"github.com/grafana/tempo/pkg/api"
 	"github.com/grafana/tempo/pkg/tempopb"
+	"github.com/grafana/tempo/pkg/tenantusage"
 )
 
 // newSearchStreamingGRPCHandler returns a handler that streams results from the HTTP handler
-func newSearchStreamingGRPCHandler(cfg Config, next pipeline.AsyncRoundTripper[combiner.PipelineResponse], apiPrefix string, logger log.Logger) streamingSearchHandler {
+func newSearchStreamingGRPCHandler(cfg Config, next pipeline.AsyncRoundTripper[combiner.PipelineResponse], prefetcher *traceByIDPrefetcher, apiPrefix string, logger log.Logger) streamingSearchHandler {
 	postSLOHook := searchSLOPostHook(cfg.Search.SLO)
+	slowQueries := newSlowQueryLogger(cfg.LogQueriesLongerThan, logger)
 	downstreamPath := path.Join(apiPrefix, api.PathSearch)
 
 	return func(req *tempopb.SearchRequest, srv tempopb.StreamingQuerier_SearchServer) error {
@@ -65,14 +67,20 @@ func newSearchStreamingGRPCHandler(cfg Config, next pipeline.AsyncRoundTripper[c
 			bytesProcessed = finalResponse.Metrics.InspectedBytes
 		}
 		postSLOHook(nil, tenant, bytesProcessed, duration, err)
+		cfg.TenantUsage.Add(tenant, tenantusage.Queried, bytesProcessed)
 		logResult(logger, tenant, duration.Seconds(), req, finalResponse, nil, err)
+		slowQueries.log(searchOp, tenant, httpReq.URL.Query(), searchMetrics(finalResponse), duration, err)
+		if err == nil {
+			prefetcher.prefetch(tenant, finalResponse)
+		}
 		return err
 	}
 }
 
 // newSearchHTTPHandler returns a handler that returns a single response from the HTTP handler
-func newSearchHTTPHandler(cfg Config, next pipeline.AsyncRoundTripper[combiner.PipelineResponse], logger log.Logger) http.RoundTripper {
+func newSearchHTTPHandler(cfg Config, next pipeline.AsyncRoundTripper[combiner.PipelineResponse], prefetcher *traceByIDPrefetcher, logger log.Logger) http.RoundTripper {
 	postSLOHook := searchSLOPostHook(cfg.Search.SLO)
+	slowQueries := newSlowQueryLogger(cfg.LogQueriesLongerThan, logger)
 
 	return pipeline.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
 		tenant, _ := user.ExtractOrgID(req.Context())
@@ -117,11 +125,27 @@ func newSearchHTTPHandler(cfg Config, next pipeline.AsyncRoundTripper[combiner.P
 
 		duration := time.Since(start)
 		postSLOHook(resp, tenant, bytesProcessed, duration, err)
+		cfg.TenantUsage.Add(tenant, tenantusage.Queried, bytesProcessed)
 		logResult(logger, tenant, duration.Seconds(), searchReq, searchResp, resp, err)
+		if normalizedReq, buildErr := api.BuildSearchRequest(nil, searchReq); buildErr == nil {
+			slowQueries.log(searchOp, tenant, normalizedReq.URL.Query(), searchMetrics(searchResp), duration, err)
+		}
+		pipeline.QueryStatsFromContext(req.Context()).SetSearchMetrics(searchMetrics(searchResp))
+		if err == nil && resp != nil && resp.StatusCode == http.StatusOK {
+			prefetcher.prefetch(tenant, searchResp)
+		}
 		return resp, err
 	})
 }
 
+// searchMetrics returns the metrics of a possibly nil search response
+func searchMetrics(resp *tempopb.SearchResponse) *tempopb.SearchMetrics {
+	if resp == nil {
+		return nil
+	}
+	return resp.Metrics
+}
+
 // adjusts the limit based on provided config
 func adjustLimit(limit, defaultLimit, maxLimit uint32) (uint32, error) {
 	if limit == 0 {
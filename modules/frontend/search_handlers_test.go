@@ -739,3 +739,28 @@ func frontendWithSettings(t *testing.T, next http.RoundTripper, rdr tempodb.Read
 
 	return f
 }
+
+func TestSearchQueryStatsHeader(t *testing.T) {
+	f := frontendWithSettings(t, nil, nil, nil, nil)
+
+	httpReq := httptest.NewRequest("GET", "/api/search", nil)
+	httpReq, err := api.BuildSearchRequest(httpReq, &tempopb.SearchRequest{
+		Query: "{}",
+		Start: 1,
+		End:   100000,
+		Limit: 10,
+	})
+	require.NoError(t, err)
+	httpReq = httpReq.WithContext(user.InjectOrgID(httpReq.Context(), "foo"))
+
+	httpResp := httptest.NewRecorder()
+	f.SearchHandler.ServeHTTP(httpResp, httpReq)
+	require.Equal(t, http.StatusOK, httpResp.Code)
+
+	stats := httpResp.Header().Get(HeaderQueryStats)
+	require.Contains(t, stats, "jobs=4,")
+	require.Contains(t, stats, "ingester_jobs=0,")
+	require.Contains(t, stats, "inspected_blocks=2,")
+	require.Contains(t, stats, "inspected_bytes=4")
+	require.NotContains(t, stats, "cache_hit_ratio") // no cache configured
+}
@@ -6,7 +6,8 @@ import (
 	"net/http"
 	"time"
 
-	"github.com/go-kit/log" //nolint:all deprecated
+	"github.com/go-kit/log"       //nolint:all deprecated
+	"github.com/go-kit/log/level" //nolint:all //deprecated
 	"github.com/gogo/protobuf/jsonpb"
 	"github.com/grafana/dskit/user"
 	"github.com/opentracing/opentracing-go"
@@ -36,6 +37,23 @@ type SearchSharderConfig struct {
 	QueryBackendAfter     time.Duration `yaml:"query_backend_after,omitempty"`
 	QueryIngestersUntil   time.Duration `yaml:"query_ingesters_until,omitempty"`
 	IngesterShards        int           `yaml:"ingester_shards,omitempty"`
+
+	// jobs against blocks are cached by default. blocks are immutable, but setting this to false stops caching the jobs
+	// of blocks that overlap the query_ingesters_until window. the ingester jobs are never cached.
+	CacheRecentJobs bool `yaml:"cache_recent_jobs,omitempty"`
+
+	// set from the frontend config. cached jobs are versioned by the number of trace tombstones of the tenant
+	TraceTombstonesEnabled bool `yaml:"-"`
+}
+
+// cacheCutoff returns the time after which blocks are considered to overlap the ingester window. jobs for blocks that end
+// after this time are not cached. a zero time is returned if all jobs can be cached.
+func (cfg SearchSharderConfig) cacheCutoff(now time.Time) time.Time {
+	if cfg.CacheRecentJobs {
+		return time.Time{}
+	}
+
+	return now.Add(-cfg.QueryIngestersUntil)
 }
 
 type asyncSearchSharder struct {
@@ -90,6 +108,13 @@ func (s asyncSearchSharder) RoundTrip(r *http.Request) (pipeline.Responses[combi
 		return pipeline.NewBadRequest(fmt.Errorf("range specified by start and end exceeds %s. received start=%d end=%d", maxDuration, searchReq.Start, searchReq.End)), nil
 	}
 
+	// enforce max query lookback. requests without a start only search the ingesters
+	if searchReq.Start != 0 {
+		if err := checkMaxQueryLookback(s.overrides, tenantID, time.Unix(int64(searchReq.Start), 0)); err != nil {
+			return pipeline.NewBadRequest(err), nil
+		}
+	}
+
 	// buffer of shards+1 allows us to insert ingestReq and metrics
 	reqCh := make(chan *http.Request, s.cfg.IngesterShards+1)
 
@@ -151,6 +176,17 @@ func (s *asyncSearchSharder) blockMetas(start, end int64, tenantID string) []*ba
 	return metas
 }
 
+// blockMetasForDuration returns the blocks that may contain traces with a duration between minDuration and maxDuration
+func blockMetasForDuration(metas []*backend.BlockMeta, minDuration, maxDuration time.Duration) []*backend.BlockMeta {
+	filtered := metas[:0]
+	for _, m := range metas {
+		if m.MayContainDuration(minDuration, maxDuration) {
+			filtered = append(filtered, m)
+		}
+	}
+	return filtered
+}
+
 // backendRequest builds backend requests to search backend blocks. backendRequest takes ownership of reqCh and closes it.
 // it returns 3 int values: totalBlocks, totalBlockBytes, and estimated jobs
 func (s *asyncSearchSharder) backendRequests(ctx context.Context, tenantID string, parent *http.Request, searchReq *tempopb.SearchRequest, reqCh chan<- *http.Request, errFn func(error)) (totalJobs, totalBlocks int, totalBlockBytes uint64) {
@@ -174,6 +210,11 @@ func (s *asyncSearchSharder) backendRequests(ctx context.Context, tenantID strin
 	// get block metadata of blocks in start, end duration
 	blocks = s.blockMetas(int64(start), int64(end), tenantID)
 
+	// skip blocks without traces in the requested duration range. traceql queries don't use the min and max duration
+	if !api.IsTraceQLQuery(searchReq) && (searchReq.MinDurationMs > 0 || searchReq.MaxDurationMs > 0) {
+		blocks = blockMetasForDuration(blocks, time.Duration(searchReq.MinDurationMs)*time.Millisecond, time.Duration(searchReq.MaxDurationMs)*time.Millisecond)
+	}
+
 	targetBytesPerRequest := s.cfg.TargetBytesPerRequest
 
 	// calculate metrics to return to the caller
@@ -188,8 +229,9 @@ func (s *asyncSearchSharder) backendRequests(ctx context.Context, tenantID strin
 		totalBlockBytes += b.Size
 	}
 
+	queryHash := s.queryHash(ctx, tenantID, searchReq)
 	go func() {
-		buildBackendRequests(ctx, tenantID, parent, searchReq, blocks, targetBytesPerRequest, reqCh, errFn)
+		buildBackendRequests(ctx, tenantID, parent, searchReq, queryHash, blocks, targetBytesPerRequest, s.cfg.cacheCutoff(time.Now()), reqCh, errFn)
 	}()
 
 	return
@@ -297,11 +339,9 @@ func backendRange(start, end uint32, queryBackendAfter time.Duration) (uint32, u
 
 // buildBackendRequests returns a slice of requests that cover all blocks in the store
 // that are covered by start/end.
-func buildBackendRequests(ctx context.Context, tenantID string, parent *http.Request, searchReq *tempopb.SearchRequest, metas []*backend.BlockMeta, bytesPerRequest int, reqCh chan<- *http.Request, errFn func(error)) {
+func buildBackendRequests(ctx context.Context, tenantID string, parent *http.Request, searchReq *tempopb.SearchRequest, queryHash uint64, metas []*backend.BlockMeta, bytesPerRequest int, cacheCutoff time.Time, reqCh chan<- *http.Request, errFn func(error)) {
 	defer close(reqCh)
 
-	queryHash := hashForSearchRequest(searchReq)
-
 	for _, m := range metas {
 		pages := pagesPerRequest(m, bytesPerRequest)
 		if pages == 0 {
@@ -338,7 +378,7 @@ func buildBackendRequests(ctx context.Context, tenantID string, parent *http.Req
 
 			prepareRequestForQueriers(subR, tenantID, subR.URL.Path, subR.URL.Query())
 			key := searchJobCacheKey(tenantID, queryHash, int64(searchReq.Start), int64(searchReq.End), m, startPage, pages)
-			if len(key) > 0 {
+			if len(key) > 0 && cacheableBlock(m, cacheCutoff) {
 				subR = pipeline.ContextAddCacheKey(key, subR)
 			}
 
@@ -352,6 +392,23 @@ func buildBackendRequests(ctx context.Context, tenantID string, parent *http.Req
 	}
 }
 
+// queryHash returns the hash used in the cache keys of the jobs. if trace tombstones are enabled the number of
+// tombstones of the tenant is added to the hash so jobs cached before a trace was deleted are not served anymore. a 0
+// hash is returned if the tombstones can't be read, which disables caching.
+func (s *asyncSearchSharder) queryHash(ctx context.Context, tenantID string, searchReq *tempopb.SearchRequest) uint64 {
+	hash := hashForSearchRequest(searchReq)
+	if hash == 0 || !s.cfg.TraceTombstonesEnabled {
+		return hash
+	}
+
+	tombstones, err := s.reader.TraceTombstonesCount(ctx, tenantID)
+	if err != nil {
+		level.Warn(s.logger).Log("msg", "search: failed to read trace tombstones. jobs are not cached", "tenant", tenantID, "err", err)
+		return 0
+	}
+	return fnv1a.AddUint64(hash, uint64(tombstones))
+}
+
 // hashForSearchRequest returns a uint64 hash of the query. if the query is invalid it returns a 0 hash.
 // before hashing the query is forced into a canonical form so equivalent queries will hash to the same value.
 func hashForSearchRequest(searchRequest *tempopb.SearchRequest) uint64 {
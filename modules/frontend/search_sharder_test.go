@@ -29,6 +29,7 @@ import (
 	"github.com/grafana/tempo/pkg/api"
 	"github.com/grafana/tempo/pkg/tempopb"
 	"github.com/grafana/tempo/pkg/traceql"
+	"github.com/grafana/tempo/pkg/util"
 	"github.com/grafana/tempo/tempodb"
 	"github.com/grafana/tempo/tempodb/backend"
 	"github.com/grafana/tempo/tempodb/blocklist"
@@ -39,7 +40,8 @@ var _ tempodb.Reader = (*mockReader)(nil)
 
 // implements tempodb.Reader interface
 type mockReader struct {
-	metas []*backend.BlockMeta
+	metas      []*backend.BlockMeta
+	tombstoned map[string]bool
 }
 
 func (m *mockReader) SearchTags(context.Context, *backend.BlockMeta, string, common.SearchOptions) (*tempopb.SearchTagsResponse, error) {
@@ -66,6 +68,10 @@ func (m *mockReader) Find(context.Context, string, common.ID, string, string, in
 	return nil, nil, nil
 }
 
+func (m *mockReader) FindWithCallback(context.Context, string, common.ID, string, string, int64, int64, common.SearchOptions, tempodb.FindTraceFunc) ([]error, error) {
+	return nil, nil
+}
+
 func (m *mockReader) BlockMetas(string) []*backend.BlockMeta {
 	return m.metas
 }
@@ -79,7 +85,47 @@ func (m *mockReader) Fetch(context.Context, *backend.BlockMeta, traceql.FetchSpa
 }
 
 func (m *mockReader) EnablePolling(context.Context, blocklist.JobSharder) {}
-func (m *mockReader) Shutdown()                                           {}
+
+func (m *mockReader) SetBlocklistPoll(time.Duration) {}
+func (m *mockReader) UpdateBlocklist(string, []*backend.BlockMeta, []*backend.BlockMeta, []*backend.CompactedBlockMeta, []*backend.CompactedBlockMeta) {
+}
+
+func (m *mockReader) BlockMeta(context.Context, string, uuid.UUID) (*backend.BlockMeta, error) {
+	return nil, nil
+}
+
+func (m *mockReader) TraceAnnotations(context.Context, string, common.ID) ([]*tempodb.Annotation, error) {
+	return nil, nil
+}
+func (m *mockReader) TenantDeletion(context.Context, string) (*tempodb.TenantDeletion, error) {
+	return nil, nil
+}
+func (m *mockReader) TenantDeletions(context.Context) ([]*tempodb.TenantDeletion, error) {
+	return nil, nil
+}
+func (m *mockReader) TenantStats() []*tempodb.TenantStats {
+	return nil
+}
+func (m *mockReader) StorageQuotasExceeded(context.Context) ([]*tempodb.StorageQuotaExceeded, error) {
+	return nil, nil
+}
+func (m *mockReader) TraceTombstones(context.Context, string) ([]*tempodb.TraceTombstone, error) {
+	return nil, nil
+}
+func (m *mockReader) TraceTombstoned(_ context.Context, _ string, traceID common.ID) (bool, error) {
+	return m.tombstoned[util.TraceIDToHexString(traceID)], nil
+}
+
+func (m *mockReader) TraceTombstonesCount(context.Context, string) (int, error) {
+	return len(m.tombstoned), nil
+}
+func (m *mockReader) QuarantinedBlocks(context.Context, string) ([]*tempodb.QuarantinedBlock, error) {
+	return nil, nil
+}
+func (m *mockReader) ReleaseQuarantinedBlock(context.Context, string, uuid.UUID) error {
+	return nil
+}
+func (m *mockReader) Shutdown() {}
 
 //nolint:all deprecated
 
@@ -227,7 +273,7 @@ func TestBuildBackendRequests(t *testing.T) {
 		reqCh := make(chan *http.Request)
 
 		go func() {
-			buildBackendRequests(ctx, "test", req, searchReq, tc.metas, tc.targetBytesPerRequest, reqCh, cancelCause)
+			buildBackendRequests(ctx, "test", req, searchReq, hashForSearchRequest(searchReq), tc.metas, tc.targetBytesPerRequest, time.Time{}, reqCh, cancelCause)
 		}()
 
 		actualURIs := []string{}
@@ -334,6 +380,55 @@ func TestBackendRequests(t *testing.T) {
 	}
 }
 
+func TestBackendRequestsSkipsBlocksByDuration(t *testing.T) {
+	bm := backend.NewBlockMeta("test", uuid.New(), "wdwad", backend.EncGZIP, "asdf")
+	bm.StartTime = time.Unix(100, 0)
+	bm.EndTime = time.Unix(200, 0)
+	bm.Size = defaultTargetBytesPerRequest
+	bm.TotalRecords = 1
+	bm.DurationAdded(100 * time.Millisecond)
+
+	// blocks without a duration histogram are always searched
+	unknown := backend.NewBlockMeta("test", uuid.New(), "wdwad", backend.EncGZIP, "asdf")
+	unknown.StartTime = time.Unix(100, 0)
+	unknown.EndTime = time.Unix(200, 0)
+	unknown.Size = defaultTargetBytesPerRequest
+	unknown.TotalRecords = 1
+
+	s := &asyncSearchSharder{
+		cfg:    SearchSharderConfig{},
+		reader: &mockReader{metas: []*backend.BlockMeta{bm, unknown}},
+	}
+
+	tests := []struct {
+		request        string
+		expectedBlocks int
+	}{
+		{request: "/?tags=foo%3Dbar&start=100&end=200", expectedBlocks: 2},
+		{request: "/?tags=foo%3Dbar&minDuration=50ms&maxDuration=150ms&start=100&end=200", expectedBlocks: 2},
+		{request: "/?tags=foo%3Dbar&minDuration=1s&start=100&end=200", expectedBlocks: 1},
+		{request: "/?tags=foo%3Dbar&maxDuration=10ms&start=100&end=200", expectedBlocks: 1},
+		// traceql queries don't use the min and max duration
+		{request: "/?q=%7B%7D&minDuration=1s&start=100&end=200", expectedBlocks: 2},
+	}
+	for _, tc := range tests {
+		t.Run(tc.request, func(t *testing.T) {
+			r := httptest.NewRequest("GET", tc.request, nil)
+			searchReq, err := api.ParseSearchRequest(r)
+			require.NoError(t, err)
+
+			reqCh := make(chan *http.Request)
+			ctx, cancelCause := context.WithCancelCause(context.Background())
+
+			_, blocks, _ := s.backendRequests(ctx, "test", r, searchReq, reqCh, cancelCause)
+			require.Equal(t, tc.expectedBlocks, blocks)
+
+			for range reqCh {
+			}
+		})
+	}
+}
+
 func TestIngesterRequests(t *testing.T) {
 	nownow := time.Now()
 
@@ -738,7 +833,7 @@ func TestSearchSharderRoundTripBadRequest(t *testing.T) {
 	// bad request
 	req = httptest.NewRequest("GET", "/?start=asdf&end=1500", nil)
 	resp, err = testRT.RoundTrip(req)
-	testBadRequestFromResponses(t, resp, err, "invalid start: strconv.ParseInt: parsing \"asdf\": invalid syntax")
+	testBadRequestFromResponses(t, resp, err, "invalid start: \"asdf\" is neither an RFC3339 nor a unix timestamp")
 
 	// test max duration error with overrides
 	o, err = overrides.NewOverrides(overrides.Config{
@@ -761,6 +856,55 @@ func TestSearchSharderRoundTripBadRequest(t *testing.T) {
 	req = req.WithContext(user.InjectOrgID(req.Context(), "blerg"))
 	resp, err = testRT.RoundTrip(req)
 	testBadRequestFromResponses(t, resp, err, "range specified by start and end exceeds 1m0s. received start=1000 end=1500")
+
+	// test max query lookback with overrides
+	o, err = overrides.NewOverrides(overrides.Config{
+		Defaults: overrides.Overrides{
+			Read: overrides.ReadOverrides{
+				MaxQueryLookback: model.Duration(time.Hour),
+			},
+		},
+	}, nil, prometheus.NewRegistry())
+	require.NoError(t, err)
+
+	sharder = newAsyncSearchSharder(&mockReader{}, o, SearchSharderConfig{
+		ConcurrentRequests:    defaultConcurrentRequests,
+		TargetBytesPerRequest: defaultTargetBytesPerRequest,
+		MaxDuration:           5 * time.Minute,
+	}, log.NewNopLogger())
+	testRT = sharder.Wrap(next)
+
+	start := time.Unix(1000, 0)
+	req = httptest.NewRequest("GET", fmt.Sprintf("/?start=%d&end=%d", start.Unix(), start.Unix()+60), nil)
+	req = req.WithContext(user.InjectOrgID(req.Context(), "blerg"))
+	resp, err = testRT.RoundTrip(req)
+	require.NoError(t, err)
+	r, done, err := resp.Next(context.Background())
+	require.NoError(t, err)
+	require.True(t, done)
+	require.Equal(t, http.StatusBadRequest, r.HTTPResponse().StatusCode)
+	body, err := io.ReadAll(r.HTTPResponse().Body)
+	require.NoError(t, err)
+	require.Contains(t, string(body), "start 1970-01-01T00:16:40Z exceeds the max query lookback of 1h0m0s")
+}
+
+func TestCheckMaxQueryLookback(t *testing.T) {
+	o, err := overrides.NewOverrides(overrides.Config{
+		Defaults: overrides.Overrides{
+			Read: overrides.ReadOverrides{
+				MaxQueryLookback: model.Duration(time.Hour),
+			},
+		},
+	}, nil, prometheus.NewRegistry())
+	require.NoError(t, err)
+
+	require.NoError(t, checkMaxQueryLookback(o, "test", time.Now().Add(-30*time.Minute)))
+	require.Error(t, checkMaxQueryLookback(o, "test", time.Now().Add(-2*time.Hour)))
+
+	// no limit
+	o, err = overrides.NewOverrides(overrides.Config{}, nil, prometheus.NewRegistry())
+	require.NoError(t, err)
+	require.NoError(t, checkMaxQueryLookback(o, "test", time.Unix(0, 0)))
 }
 
 func testBadRequestFromResponses(t *testing.T, resp pipeline.Responses[combiner.PipelineResponse], err error, expectedBody string) {
@@ -866,3 +1010,27 @@ func TestHashTraceQLQuery(t *testing.T) {
 	h2 = hashForSearchRequest(&tempopb.SearchRequest{Query: "{ span.foo = `bar` }", SpansPerSpanSet: 2})
 	require.NotEqual(t, h1, h2)
 }
+
+func TestQueryHashTraceTombstones(t *testing.T) {
+	ctx := context.Background()
+	req := &tempopb.SearchRequest{Query: "{ span.foo = `bar` }"}
+	reader := &mockReader{tombstoned: map[string]bool{}}
+
+	// without tombstones the hash is the hash of the request
+	s := &asyncSearchSharder{reader: reader, logger: log.NewNopLogger()}
+	require.Equal(t, hashForSearchRequest(req), s.queryHash(ctx, "test", req))
+
+	// deleting a trace changes the hash so jobs cached before are not served
+	s.cfg.TraceTombstonesEnabled = true
+	before := s.queryHash(ctx, "test", req)
+	require.NotZero(t, before)
+
+	reader.tombstoned["1234"] = true
+	after := s.queryHash(ctx, "test", req)
+	require.NotZero(t, after)
+	require.NotEqual(t, before, after)
+	require.Equal(t, after, s.queryHash(ctx, "test", req))
+
+	// requests that aren't cached stay uncached
+	require.Zero(t, s.queryHash(ctx, "test", &tempopb.SearchRequest{}))
+}
@@ -0,0 +1,66 @@
+package frontend
+
+import (
+	"net/url"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/grafana/tempo/pkg/tempopb"
+)
+
+var slowQueriesCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "tempo",
+	Name:      "query_frontend_slow_queries_total",
+	Help:      "Total queries that took longer than log_queries_longer_than per tenant and endpoint.",
+}, []string{"tenant", "op"})
+
+// slowQueryLogger logs queries that take longer than the configured threshold. a threshold of 0 disables it
+type slowQueryLogger struct {
+	threshold time.Duration
+	logger    log.Logger
+}
+
+func newSlowQueryLogger(threshold time.Duration, logger log.Logger) slowQueryLogger {
+	return slowQueryLogger{
+		threshold: threshold,
+		logger:    logger,
+	}
+}
+
+// log logs the query if it took longer than the threshold. params are the normalized query parameters of the request
+// and metrics are the combined metrics of the response. metrics is nil for endpoints that don't report them.
+func (s slowQueryLogger) log(op, tenant string, params url.Values, metrics *tempopb.SearchMetrics, duration time.Duration, err error) {
+	if s.threshold <= 0 || duration < s.threshold {
+		return
+	}
+
+	slowQueriesCounter.WithLabelValues(tenant, op).Inc()
+
+	logMessage := []interface{}{
+		"msg", "slow query detected",
+		"tenant", tenant,
+		"op", op,
+		"params", params.Encode(),
+		"duration_seconds", duration.Seconds(),
+	}
+
+	if metrics != nil {
+		logMessage = append(logMessage,
+			"total_blocks", metrics.TotalBlocks,
+			"total_block_bytes", metrics.TotalBlockBytes,
+			"total_jobs", metrics.TotalJobs,
+			"completed_jobs", metrics.CompletedJobs,
+			"inspected_bytes", metrics.InspectedBytes,
+			"inspected_traces", metrics.InspectedTraces,
+			"inspected_spans", metrics.InspectedSpans,
+		)
+	}
+
+	logMessage = append(logMessage, "err", err)
+
+	level.Warn(s.logger).Log(logMessage...)
+}
@@ -0,0 +1,73 @@
+package frontend
+
+import (
+	"bytes"
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/pkg/tempopb"
+)
+
+func TestSlowQueryLogger(t *testing.T) {
+	tcs := []struct {
+		name      string
+		threshold time.Duration
+		duration  time.Duration
+		metrics   *tempopb.SearchMetrics
+		err       error
+
+		expected string
+	}{
+		{
+			name:     "disabled",
+			duration: time.Hour,
+		},
+		{
+			name:      "faster than threshold",
+			threshold: time.Second,
+			duration:  500 * time.Millisecond,
+		},
+		{
+			name:      "slow query",
+			threshold: time.Second,
+			duration:  2 * time.Second,
+			metrics: &tempopb.SearchMetrics{
+				TotalBlocks:     3,
+				TotalBlockBytes: 300,
+				TotalJobs:       6,
+				CompletedJobs:   5,
+				InspectedBytes:  200,
+				InspectedTraces: 10,
+				InspectedSpans:  20,
+			},
+			expected: `level=warn msg="slow query detected" tenant=foo op=search params="end=20&q=%7B%7D&start=10" duration_seconds=2 total_blocks=3 total_block_bytes=300 total_jobs=6 completed_jobs=5 inspected_bytes=200 inspected_traces=10 inspected_spans=20 err=null` + "\n",
+		},
+		{
+			name:      "slow query without metrics",
+			threshold: time.Second,
+			duration:  time.Second,
+			err:       errors.New("failed"),
+			expected:  `level=warn msg="slow query detected" tenant=foo op=search params="end=20&q=%7B%7D&start=10" duration_seconds=1 err=failed` + "\n",
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			l := newSlowQueryLogger(tc.threshold, log.NewLogfmtLogger(buf))
+
+			params := url.Values{}
+			params.Set("q", "{}")
+			params.Set("start", "10")
+			params.Set("end", "20")
+
+			l.log(searchOp, "foo", params, tc.metrics, tc.duration, tc.err)
+			require.Equal(t, tc.expected, buf.String())
+		})
+	}
+}
@@ -211,6 +211,13 @@ func (s searchTagSharder) RoundTrip(r *http.Request) (pipeline.Responses[combine
 			" received start=%d end=%d", maxDuration, searchReq.start(), searchReq.end())), nil
 	}
 
+	// enforce max query lookback. requests without a start only search the ingesters
+	if searchReq.start() != 0 {
+		if err := checkMaxQueryLookback(s.overrides, tenantID, time.Unix(int64(searchReq.start()), 0)); err != nil {
+			return pipeline.NewBadRequest(err), nil
+		}
+	}
+
 	// build request to search ingester based on query_ingesters_until config and time range
 	// pass subCtx in requests, so we can cancel and exit early
 	ingesterReq, err := s.ingesterRequest(ctx, tenantID, r, searchReq)
@@ -284,6 +291,7 @@ func (s searchTagSharder) buildBackendRequests(ctx context.Context, tenantID str
 
 	hash := searchReq.hash()
 	keyPrefix := searchReq.keyPrefix()
+	cacheCutoff := s.cfg.cacheCutoff(time.Now())
 
 	for _, m := range metas {
 		pages := pagesPerRequest(m, bytesPerRequest)
@@ -303,7 +311,7 @@ func (s searchTagSharder) buildBackendRequests(ctx context.Context, tenantID str
 			prepareRequestForQueriers(subR, tenantID, parent.URL.Path, subR.URL.Query())
 
 			key := cacheKey(keyPrefix, tenantID, hash, int64(searchReq.start()), int64(searchReq.end()), m, startPage, pages)
-			if len(key) > 0 {
+			if len(key) > 0 && cacheableBlock(m, cacheCutoff) {
 				subR = pipeline.ContextAddCacheKey(key, subR)
 			}
 
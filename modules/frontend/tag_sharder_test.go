@@ -312,7 +312,7 @@ func TestTagsSearchSharderRoundTripBadRequest(t *testing.T) {
 	req = httptest.NewRequest("GET", "/?start=asdf&end=1500", nil)
 	req = req.WithContext(user.InjectOrgID(req.Context(), "blerg"))
 	resp, err = testRT.RoundTrip(req)
-	testBadRequestFromResponses(t, resp, err, "invalid start: strconv.ParseInt: parsing \"asdf\": invalid syntax")
+	testBadRequestFromResponses(t, resp, err, "invalid start: \"asdf\" is neither an RFC3339 nor a unix timestamp")
 
 	// test max duration error with overrides
 	o, err = overrides.NewOverrides(overrides.Config{
@@ -0,0 +1,40 @@
+package frontend
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/grafana/tempo/tempodb"
+)
+
+// tenantsStore summarizes the blocks of the tenants. It is implemented by tempodb.
+type tenantsStore interface {
+	TenantStats() []*tempodb.TenantStats
+}
+
+type tenantsResponse struct {
+	Tenants []*tempodb.TenantStats `json:"tenants"`
+}
+
+// TenantsHandler lists all the tenants in the blocklist of the query-frontend with the stats of their blocks. It is
+// an admin endpoint and isn't scoped to the tenant of the request.
+type TenantsHandler struct {
+	store tenantsStore
+}
+
+func NewTenantsHandler(store tenantsStore) *TenantsHandler {
+	return &TenantsHandler{
+		store: store,
+	}
+}
+
+// ServeHTTP implements http.Handler
+func (h *TenantsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", "GET")
+		http.Error(w, fmt.Sprintf("method %s not allowed", r.Method), http.StatusMethodNotAllowed)
+		return
+	}
+
+	writeAnnotationsJSON(w, http.StatusOK, &tenantsResponse{Tenants: h.store.TenantStats()})
+}
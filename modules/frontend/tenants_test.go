@@ -0,0 +1,48 @@
+package frontend
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/tempodb"
+)
+
+type mockTenantsStore struct {
+	stats []*tempodb.TenantStats
+}
+
+func (m *mockTenantsStore) TenantStats() []*tempodb.TenantStats {
+	return m.stats
+}
+
+func TestTenantsHandler(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	store := &mockTenantsStore{}
+	h := NewTenantsHandler(store)
+
+	do := func(method string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(method, "/status/tenants", nil))
+		return rec
+	}
+
+	rec := do(http.MethodGet)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.JSONEq(t, `{"tenants":null}`, rec.Body.String())
+
+	store.stats = []*tempodb.TenantStats{
+		{TenantID: "test", Blocks: 2, CompactedBlocks: 1, Bytes: 100, OldestBlock: start, NewestBlock: start.Add(time.Hour)},
+	}
+	rec = do(http.MethodGet)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.JSONEq(t, `{"tenants":[
+		{"tenantID":"test","blocks":2,"compactedBlocks":1,"bytes":100,"oldestBlock":"2024-01-01T00:00:00Z","newestBlock":"2024-01-01T01:00:00Z"}
+	]}`, rec.Body.String())
+
+	rec = do(http.MethodPost)
+	require.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
@@ -0,0 +1,129 @@
+package frontend
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/grafana/dskit/user"
+	jsoniter "github.com/json-iterator/go"
+
+	"github.com/grafana/tempo/pkg/util"
+	"github.com/grafana/tempo/tempodb"
+	"github.com/grafana/tempo/tempodb/encoding/common"
+)
+
+const (
+	maxTombstonesPerRequest = 1000
+	maxTombstoneRequestSize = 64 * 1024
+)
+
+// tombstonesStore reads and writes the tombstones of deleted traces. It is implemented by tempodb.
+type tombstonesStore interface {
+	TraceTombstones(ctx context.Context, tenantID string) ([]*tempodb.TraceTombstone, error)
+	AddTraceTombstones(ctx context.Context, tenantID string, traceIDs []common.ID) error
+}
+
+type tombstonesRequest struct {
+	TraceIDs []string `json:"traceIDs"`
+}
+
+type tombstonesResponse struct {
+	Tombstones []*tempodb.TraceTombstone `json:"tombstones"`
+}
+
+// TombstonesHandler lists the tombstones of the tenant on GET and deletes the requested traces on POST. Deleted traces
+// are filtered from the query results right away and removed from the blocks by the compactors.
+type TombstonesHandler struct {
+	store  tombstonesStore
+	logger log.Logger
+}
+
+func NewTombstonesHandler(store tombstonesStore, logger log.Logger) *TombstonesHandler {
+	return &TombstonesHandler{
+		store:  store,
+		logger: logger,
+	}
+}
+
+// ServeHTTP implements http.Handler
+func (h *TombstonesHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	tenantID, err := user.ExtractOrgID(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		h.list(w, r, tenantID)
+	case http.MethodPost:
+		h.add(w, r, tenantID)
+	default:
+		w.Header().Set("Allow", "GET, POST")
+		http.Error(w, fmt.Sprintf("method %s not allowed", r.Method), http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *TombstonesHandler) list(w http.ResponseWriter, r *http.Request, tenantID string) {
+	tombstones, err := h.store.TraceTombstones(r.Context(), tenantID)
+	if err != nil {
+		level.Error(h.logger).Log("msg", "failed to read trace tombstones", "tenant", tenantID, "err", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if tombstones == nil {
+		tombstones = []*tempodb.TraceTombstone{}
+	}
+
+	writeAnnotationsJSON(w, http.StatusOK, &tombstonesResponse{Tombstones: tombstones})
+}
+
+func (h *TombstonesHandler) add(w http.ResponseWriter, r *http.Request, tenantID string) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxTombstoneRequestSize+1))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if len(body) > maxTombstoneRequestSize {
+		http.Error(w, errRequestEntityTooLarge.Error(), http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	req := &tombstonesRequest{}
+	if err := jsoniter.Unmarshal(body, req); err != nil {
+		http.Error(w, fmt.Sprintf("invalid tombstones request: %s", err), http.StatusBadRequest)
+		return
+	}
+	if len(req.TraceIDs) == 0 {
+		http.Error(w, "at least one trace id is required", http.StatusBadRequest)
+		return
+	}
+	if len(req.TraceIDs) > maxTombstonesPerRequest {
+		http.Error(w, fmt.Sprintf("at most %d trace ids can be deleted per request", maxTombstonesPerRequest), http.StatusBadRequest)
+		return
+	}
+
+	traceIDs := make([]common.ID, 0, len(req.TraceIDs))
+	for _, id := range req.TraceIDs {
+		traceID, err := util.HexStringToTraceID(id)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("invalid trace id %q: %s", id, err), http.StatusBadRequest)
+			return
+		}
+		traceIDs = append(traceIDs, traceID)
+	}
+
+	err = h.store.AddTraceTombstones(r.Context(), tenantID, traceIDs)
+	if err != nil {
+		level.Error(h.logger).Log("msg", "failed to add trace tombstones", "tenant", tenantID, "err", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	level.Info(h.logger).Log("msg", "added trace tombstones", "tenant", tenantID, "traces", len(traceIDs))
+	w.WriteHeader(http.StatusAccepted)
+}
@@ -0,0 +1,89 @@
+package frontend
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-kit/log"
+	"github.com/grafana/dskit/user"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/pkg/util"
+	"github.com/grafana/tempo/tempodb"
+	"github.com/grafana/tempo/tempodb/encoding/common"
+)
+
+type mockTombstonesStore struct {
+	tombstones map[string][]*tempodb.TraceTombstone
+}
+
+func (m *mockTombstonesStore) TraceTombstones(_ context.Context, tenantID string) ([]*tempodb.TraceTombstone, error) {
+	return m.tombstones[tenantID], nil
+}
+
+func (m *mockTombstonesStore) AddTraceTombstones(_ context.Context, tenantID string, traceIDs []common.ID) error {
+	for _, id := range traceIDs {
+		m.tombstones[tenantID] = append(m.tombstones[tenantID], &tempodb.TraceTombstone{TraceID: util.TraceIDToHexString(id)})
+	}
+	return nil
+}
+
+func TestTombstonesHandler(t *testing.T) {
+	store := &mockTombstonesStore{tombstones: map[string][]*tempodb.TraceTombstone{}}
+	h := NewTombstonesHandler(store, log.NewNopLogger())
+
+	do := func(method, tenant, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/api/tombstones", strings.NewReader(body))
+		if tenant != "" {
+			req = req.WithContext(user.InjectOrgID(req.Context(), tenant))
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// no tombstones
+	rec := do(http.MethodGet, "test", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.JSONEq(t, `{"tombstones":[]}`, rec.Body.String())
+
+	rec = do(http.MethodPost, "test", `{"traceIDs":["1234","0000abcd"]}`)
+	require.Equal(t, http.StatusAccepted, rec.Code)
+
+	rec = do(http.MethodGet, "test", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.JSONEq(t, `{"tombstones":[{"traceID":"1234","requestedAt":"0001-01-01T00:00:00Z"},{"traceID":"abcd","requestedAt":"0001-01-01T00:00:00Z"}]}`, rec.Body.String())
+
+	// tombstones are per tenant
+	rec = do(http.MethodGet, "other", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.JSONEq(t, `{"tombstones":[]}`, rec.Body.String())
+
+	tcs := []struct {
+		name     string
+		method   string
+		tenant   string
+		body     string
+		expected int
+	}{
+		{name: "no tenant", method: http.MethodGet, expected: http.StatusBadRequest},
+		{name: "invalid body", method: http.MethodPost, tenant: "test", body: "{", expected: http.StatusBadRequest},
+		{name: "no trace ids", method: http.MethodPost, tenant: "test", body: `{"traceIDs":[]}`, expected: http.StatusBadRequest},
+		{name: "invalid trace id", method: http.MethodPost, tenant: "test", body: `{"traceIDs":["1234","zz"]}`, expected: http.StatusBadRequest},
+		{name: "too many trace ids", method: http.MethodPost, tenant: "test", body: `{"traceIDs":["1"` + strings.Repeat(`,"1"`, maxTombstonesPerRequest) + `]}`, expected: http.StatusBadRequest},
+		{name: "body too large", method: http.MethodPost, tenant: "test", body: strings.Repeat(" ", maxTombstoneRequestSize+1), expected: http.StatusRequestEntityTooLarge},
+		{name: "method not allowed", method: http.MethodDelete, tenant: "test", expected: http.StatusMethodNotAllowed},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := do(tc.method, tc.tenant, tc.body)
+			require.Equal(t, tc.expected, rec.Code)
+		})
+	}
+
+	// invalid requests add no tombstones
+	require.Len(t, store.tombstones["test"], 2)
+}
@@ -0,0 +1,123 @@
+package frontend
+
+import (
+	"bytes"
+	"context"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level" //nolint:all //deprecated
+	"github.com/gogo/protobuf/jsonpb"
+	"github.com/gogo/protobuf/proto"
+
+	"github.com/grafana/tempo/pkg/api"
+	"github.com/grafana/tempo/pkg/cache"
+	"github.com/grafana/tempo/pkg/tempopb"
+)
+
+// traceByIDCache stores fully combined trace by id responses. a trace is only cached once its most recent
+// span ended at least minTraceAge ago. younger traces may still be receiving spans and caching them would
+// return stale, partial traces for the lifetime of the cache entry.
+type traceByIDCache struct {
+	c           cache.Cache
+	minTraceAge time.Duration
+	now         func() time.Time
+}
+
+func newTraceByIDCache(cacheProvider cache.Provider, minTraceAge time.Duration, logger log.Logger) *traceByIDCache {
+	var c cache.Cache
+	if cacheProvider != nil {
+		c = cacheProvider.CacheFor(cache.RoleFrontendTraceByID)
+	}
+
+	level.Info(logger).Log("msg", "init frontend cache", "role", cache.RoleFrontendTraceByID, "enabled", c != nil)
+
+	if c == nil {
+		return nil
+	}
+
+	return &traceByIDCache{
+		c:           c,
+		minTraceAge: minTraceAge,
+		now:         time.Now,
+	}
+}
+
+// fetch returns the cached trace for the key encoded in contentType or nil if it doesn't exist
+func (t *traceByIDCache) fetch(ctx context.Context, key string, contentType string) []byte {
+	_, bufs, _ := t.c.Fetch(ctx, []string{key})
+	if len(bufs) != 1 {
+		return nil
+	}
+
+	// traces are cached in proto
+	if contentType == api.HeaderAcceptProtobuf {
+		return bufs[0]
+	}
+
+	trace := &tempopb.Trace{}
+	if err := proto.Unmarshal(bufs[0], trace); err != nil {
+		return nil
+	}
+	body, err := new(jsonpb.Marshaler).MarshalToString(trace)
+	if err != nil {
+		return nil
+	}
+	return []byte(body)
+}
+
+// store caches the body in proto if it is a complete trace. body is expected to be a tempopb.Trace encoded in
+// contentType
+func (t *traceByIDCache) store(ctx context.Context, key string, contentType string, body []byte) {
+	if len(body) == 0 {
+		return
+	}
+
+	trace := &tempopb.Trace{}
+	var err error
+	if contentType == api.HeaderAcceptProtobuf {
+		err = proto.Unmarshal(body, trace)
+	} else {
+		err = (&jsonpb.Unmarshaler{AllowUnknownFields: true}).Unmarshal(bytes.NewReader(body), trace)
+	}
+	if err != nil {
+		return
+	}
+
+	lastEnd, ok := traceLastEndTime(trace)
+	if !ok {
+		return
+	}
+
+	if t.now().Sub(lastEnd) < t.minTraceAge {
+		return
+	}
+
+	if contentType != api.HeaderAcceptProtobuf {
+		body, err = proto.Marshal(trace)
+		if err != nil {
+			return
+		}
+	}
+
+	t.c.Store(ctx, []string{key}, [][]byte{body})
+}
+
+// traceLastEndTime returns the latest end time of any span in the trace. it returns false if the trace has no spans.
+func traceLastEndTime(trace *tempopb.Trace) (time.Time, bool) {
+	var lastEnd uint64
+	found := false
+
+	for _, b := range trace.Batches {
+		for _, ss := range b.ScopeSpans {
+			for _, s := range ss.Spans {
+				found = true
+				if s.EndTimeUnixNano > lastEnd {
+					lastEnd = s.EndTimeUnixNano
+				}
+			}
+		}
+	}
+
+	return time.Unix(0, int64(lastEnd)), found
+}
@@ -1,23 +1,116 @@
 package frontend
 
 import (
+	"bytes"
+	"context"
 	"io"
 	"net/http"
+	"net/url"
+	"path"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/go-kit/log"
 	"github.com/go-kit/log/level" //nolint:all //deprecated
+	"github.com/gogo/status"
+	"github.com/grafana/dskit/tenant"
 	"github.com/grafana/dskit/user"
 	"github.com/grafana/tempo/modules/frontend/combiner"
 	"github.com/grafana/tempo/modules/frontend/pipeline"
 	"github.com/grafana/tempo/modules/overrides"
 	"github.com/grafana/tempo/pkg/api"
+	"github.com/grafana/tempo/pkg/tempopb"
+	"github.com/grafana/tempo/pkg/util"
+	"github.com/grafana/tempo/tempodb"
+	"google.golang.org/grpc/codes"
 )
 
+// newTraceIDStreamingGRPCHandler returns a handler that streams partial traces as they are found by the sharded jobs
+func newTraceIDStreamingGRPCHandler(cfg Config, o overrides.Interface, next pipeline.AsyncRoundTripper[combiner.PipelineResponse], apiPrefix string, logger log.Logger) streamingTraceIDHandler {
+	postSLOHook := traceByIDSLOPostHook(cfg.TraceByID.SLO)
+	slowQueries := newSlowQueryLogger(cfg.LogQueriesLongerThan, logger)
+
+	return func(req *tempopb.TraceByIDRequest, srv tempopb.StreamingQuerier_FindTraceByIDServer) error {
+		ctx := srv.Context()
+		tenantID, err := user.ExtractOrgID(ctx)
+		if err != nil {
+			level.Error(logger).Log("msg", "trace id streaming: failed to extract tenant id", "err", err)
+			return status.Errorf(codes.InvalidArgument, "extract tenant id: %s", err.Error())
+		}
+
+		if len(req.TraceID) == 0 {
+			return status.Error(codes.InvalidArgument, "trace id is required")
+		}
+
+		// the block range and query mode are passed on like the query parameters of the http api
+		params := url.Values{}
+		if req.BlockStart != "" {
+			params.Set(api.BlockStartKey, req.BlockStart)
+		}
+		if req.BlockEnd != "" {
+			params.Set(api.BlockEndKey, req.BlockEnd)
+		}
+		if req.QueryMode != "" {
+			params.Set(api.QueryModeKey, req.QueryMode)
+		}
+
+		traceID := util.TraceIDToHexString(req.TraceID)
+		downstreamPath := path.Join(apiPrefix, strings.Replace(api.PathTraces, "{traceID}", traceID, 1))
+		httpReq := (&http.Request{
+			URL:    &url.URL{Path: downstreamPath, RawQuery: params.Encode()},
+			Header: http.Header{},
+			Body:   io.NopCloser(bytes.NewReader([]byte{})),
+		}).WithContext(ctx)
+
+		if _, _, _, _, _, err := api.ValidateAndSanitizeRequest(httpReq); err != nil {
+			return status.Error(codes.InvalidArgument, err.Error())
+		}
+
+		// enforce all communication internal to Tempo to be in protobuf bytes
+		httpReq.Header.Set(api.HeaderAccept, api.HeaderAcceptProtobuf)
+		prepareRequestForQueriers(httpReq, tenantID, downstreamPath, nil)
+
+		// every tenant is sharded into the same number of jobs
+		totalJobs := cfg.TraceByID.QueryShards
+		if cfg.MultiTenantQueriesEnabled {
+			if tenants, err := tenant.TenantIDs(ctx); err == nil && len(tenants) > 1 {
+				totalJobs *= len(tenants)
+			}
+		}
+		// every federated cluster is a single job
+		totalJobs += len(cfg.Federation.Clusters)
+
+		level.Info(logger).Log(
+			"msg", "trace id streaming request",
+			"tenant", tenantID,
+			"traceID", traceID)
+
+		c := combiner.NewTypedTraceByID(o.MaxBytesPerTrace(tenantID), totalJobs)
+		collector := pipeline.NewGRPCCollector[*tempopb.TraceByIDResponse](next, cfg.ResponseConsumers, c, srv.Send)
+
+		start := time.Now()
+		err = collector.RoundTrip(httpReq)
+
+		elapsed := time.Since(start)
+		postSLOHook(nil, tenantID, 0, elapsed, err)
+		slowQueries.log(traceByIDOp, tenantID, traceByIDParams(req.TraceID, 0, 0), nil, elapsed, err)
+
+		level.Info(logger).Log(
+			"msg", "trace id streaming response",
+			"tenant", tenantID,
+			"traceID", traceID,
+			"duration_seconds", elapsed.Seconds(),
+			"err", err)
+
+		return err
+	}
+}
+
 // newTraceIDHandler creates a http.handler for trace by id requests
-func newTraceIDHandler(cfg Config, o overrides.Interface, next pipeline.AsyncRoundTripper[combiner.PipelineResponse], logger log.Logger) http.RoundTripper {
+func newTraceIDHandler(cfg Config, o overrides.Interface, next pipeline.AsyncRoundTripper[combiner.PipelineResponse], reader tempodb.Reader, c *traceByIDCache, logger log.Logger) http.RoundTripper {
 	postSLOHook := traceByIDSLOPostHook(cfg.TraceByID.SLO)
+	slowQueries := newSlowQueryLogger(cfg.LogQueriesLongerThan, logger)
 
 	return pipeline.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
 		tenant, err := user.ExtractOrgID(req.Context())
@@ -31,7 +124,7 @@ func newTraceIDHandler(cfg Config, o overrides.Interface, next pipeline.AsyncRou
 		}
 
 		// validate traceID
-		_, err = api.ParseTraceID(req)
+		traceID, err := api.ParseTraceID(req)
 		if err != nil {
 			return &http.Response{
 				StatusCode: http.StatusBadRequest,
@@ -41,7 +134,7 @@ func newTraceIDHandler(cfg Config, o overrides.Interface, next pipeline.AsyncRou
 		}
 
 		// validate start and end parameter
-		_, _, _, _, _, reqErr := api.ValidateAndSanitizeRequest(req)
+		blockStart, blockEnd, queryMode, start, end, reqErr := api.ValidateAndSanitizeRequest(req)
 		if reqErr != nil {
 			return &http.Response{
 				StatusCode: http.StatusBadRequest,
@@ -50,12 +143,52 @@ func newTraceIDHandler(cfg Config, o overrides.Interface, next pipeline.AsyncRou
 			}, nil
 		}
 
+		// validate the early exit criterion. it's passed on to the queriers
+		stop, err := api.ParseTraceByIDStop(req)
+		if err != nil {
+			return &http.Response{
+				StatusCode: http.StatusBadRequest,
+				Body:       io.NopCloser(strings.NewReader(err.Error())),
+				Header:     http.Header{},
+			}, nil
+		}
+
+		// parts of the trace to omit from the response
+		strip, err := api.ParseTraceStripOptions(req)
+		if err != nil {
+			return &http.Response{
+				StatusCode: http.StatusBadRequest,
+				Body:       io.NopCloser(strings.NewReader(err.Error())),
+				Header:     http.Header{},
+			}, nil
+		}
+
 		// check marshalling format
 		marshallingFormat := api.HeaderAcceptJSON
 		if req.Header.Get(api.HeaderAccept) == api.HeaderAcceptProtobuf {
 			marshallingFormat = api.HeaderAcceptProtobuf
 		}
 
+		var cacheKey string
+		if c != nil && !skipTraceByIDCache(req.Context(), cfg, reader, tenant, traceID, logger) {
+			cacheKey = traceByIDCacheKey(tenant, traceID, blockStart, blockEnd, queryMode, start, end, strip, stop)
+			if body := c.fetch(req.Context(), cacheKey, marshallingFormat); len(body) > 0 {
+				level.Info(logger).Log(
+					"msg", "trace id response from cache",
+					"tenant", tenant,
+					"path", req.URL.Path)
+
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Header: http.Header{
+						api.HeaderContentType: {marshallingFormat},
+					},
+					Body:          io.NopCloser(bytes.NewReader(body)),
+					ContentLength: int64(len(body)),
+				}, nil
+			}
+		}
+
 		// enforce all communication internal to Tempo to be in protobuf bytes
 		req.Header.Set(api.HeaderAccept, api.HeaderAcceptProtobuf)
 		prepareRequestForQueriers(req, tenant, req.RequestURI, nil)
@@ -65,14 +198,23 @@ func newTraceIDHandler(cfg Config, o overrides.Interface, next pipeline.AsyncRou
 			"tenant", tenant,
 			"path", req.URL.Path)
 
-		combiner := combiner.NewTraceByID(o.MaxBytesPerTrace(tenant), marshallingFormat)
+		combiner := combiner.NewTraceByID(o.MaxBytesPerTrace(tenant), marshallingFormat, strip)
 		rt := pipeline.NewHTTPCollector(next, cfg.ResponseConsumers, combiner)
 
-		start := time.Now()
+		startTime := time.Now()
 		resp, err := rt.RoundTrip(req)
 
-		elapsed := time.Since(start)
+		elapsed := time.Since(startTime)
 		postSLOHook(resp, tenant, 0, elapsed, err)
+		slowQueries.log(traceByIDOp, tenant, traceByIDParams(traceID, start, end), nil, elapsed, err)
+
+		if cacheKey != "" && err == nil && resp != nil && resp.StatusCode == http.StatusOK {
+			body, readErr := io.ReadAll(resp.Body)
+			resp.Body = io.NopCloser(bytes.NewReader(body))
+			if readErr == nil {
+				c.store(req.Context(), cacheKey, marshallingFormat, body)
+			}
+		}
 
 		level.Info(logger).Log(
 			"msg", "trace id response",
@@ -84,3 +226,30 @@ func newTraceIDHandler(cfg Config, o overrides.Interface, next pipeline.AsyncRou
 		return resp, err
 	})
 }
+
+// traceByIDParams returns the normalized parameters of a trace by id request for logging
+func traceByIDParams(traceID []byte, start, end int64) url.Values {
+	params := url.Values{}
+	params.Set("traceID", util.TraceIDToHexString(traceID))
+	if start != 0 || end != 0 {
+		params.Set("start", strconv.FormatInt(start, 10))
+		params.Set("end", strconv.FormatInt(end, 10))
+	}
+	return params
+}
+
+// skipTraceByIDCache returns true if trace tombstones are enabled and the trace was deleted. cached responses of
+// deleted traces must not be served and their responses are not cached. if the tombstones can't be read the cache is
+// skipped too and the queriers filter the trace.
+func skipTraceByIDCache(ctx context.Context, cfg Config, reader tempodb.Reader, tenant string, traceID []byte, logger log.Logger) bool {
+	if !cfg.TraceTombstonesEnabled {
+		return false
+	}
+
+	tombstoned, err := reader.TraceTombstoned(ctx, tenant, traceID)
+	if err != nil {
+		level.Warn(logger).Log("msg", "trace id: failed to read trace tombstones. bypassing cache", "tenant", tenant, "err", err)
+		return true
+	}
+	return tombstoned
+}
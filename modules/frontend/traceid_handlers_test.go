@@ -6,18 +6,27 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"sort"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/gogo/protobuf/proto"
+	"github.com/gogo/status"
+	"github.com/google/uuid"
 	"github.com/gorilla/mux"
 	"github.com/grafana/dskit/user"
 	"github.com/grafana/tempo/modules/frontend/pipeline"
+	"github.com/grafana/tempo/pkg/api"
+	"github.com/grafana/tempo/pkg/cache"
 	"github.com/grafana/tempo/pkg/model/trace"
 	"github.com/grafana/tempo/pkg/tempopb"
 	"github.com/grafana/tempo/pkg/util/test"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/atomic"
+	"google.golang.org/grpc/codes"
 )
 
 func TestTraceIDHandler(t *testing.T) {
@@ -231,3 +240,326 @@ func TestTraceIDHandler(t *testing.T) {
 		})
 	}
 }
+
+func TestTraceIDStreaming(t *testing.T) {
+	splitTrace := test.MakeTrace(2, []byte{0x12, 0x34})
+	trace1 := &tempopb.Trace{Batches: splitTrace.Batches[:1]}
+	trace2 := &tempopb.Trace{Batches: splitTrace.Batches[1:]}
+
+	next := pipeline.RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		testTrace := trace2
+		if r.RequestURI == "/querier/api/traces/1234?mode=ingesters" {
+			testTrace = trace1
+		}
+
+		resBytes, err := proto.Marshal(&tempopb.TraceByIDResponse{
+			Trace:   proto.Clone(testTrace).(*tempopb.Trace),
+			Metrics: &tempopb.TraceByIDMetrics{},
+		})
+		require.NoError(t, err)
+
+		return &http.Response{
+			Body:       io.NopCloser(bytes.NewReader(resBytes)),
+			StatusCode: 200,
+		}, nil
+	})
+
+	f := frontendWithSettings(t, next, nil, nil, nil)
+
+	srv := newMockStreamingServer[*tempopb.TraceByIDResponse]("blerg", nil)
+	err := f.FindTraceByID(&tempopb.TraceByIDRequest{TraceID: []byte{0x12, 0x34}}, srv)
+	require.NoError(t, err)
+
+	// the last response is the complete trace
+	final := *srv.lastResponse.Load()
+	trace.SortTrace(splitTrace)
+	trace.SortTrace(final.Trace)
+	require.True(t, proto.Equal(splitTrace, final.Trace))
+	require.Equal(t, &tempopb.TraceByIDMetrics{CompletedJobs: minQueryShards, TotalJobs: minQueryShards}, final.Metrics)
+
+	// missing trace id is rejected
+	err = f.FindTraceByID(&tempopb.TraceByIDRequest{}, newMockStreamingServer[*tempopb.TraceByIDResponse]("blerg", nil))
+	require.Error(t, err)
+}
+
+func TestTraceIDStreamingPassesQueryParams(t *testing.T) {
+	var uris []string
+	var mtx sync.Mutex
+	next := pipeline.RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		mtx.Lock()
+		uris = append(uris, r.RequestURI)
+		mtx.Unlock()
+
+		resBytes, err := proto.Marshal(&tempopb.TraceByIDResponse{Metrics: &tempopb.TraceByIDMetrics{}})
+		require.NoError(t, err)
+
+		return &http.Response{
+			Body:       io.NopCloser(bytes.NewReader(resBytes)),
+			StatusCode: 200,
+		}, nil
+	})
+
+	f := frontendWithSettings(t, next, nil, nil, nil)
+
+	blockStart := "00000000-0000-0000-0000-000000000000"
+	blockEnd := "7fffffff-ffff-ffff-ffff-ffffffffffff"
+	err := f.FindTraceByID(&tempopb.TraceByIDRequest{
+		TraceID:    []byte{0x12, 0x34},
+		BlockStart: blockStart,
+		BlockEnd:   blockEnd,
+		QueryMode:  api.QueryModeBlocks,
+	}, newMockStreamingServer[*tempopb.TraceByIDResponse]("blerg", nil))
+	require.NoError(t, err)
+
+	// the block shards are limited to the range of the request and cover all of it
+	require.NotEmpty(t, uris)
+	var ranges [][2]uuid.UUID
+	for _, uri := range uris {
+		u, err := url.Parse(uri)
+		require.NoError(t, err)
+		require.Equal(t, api.QueryModeBlocks, u.Query().Get(api.QueryModeKey))
+		require.Len(t, u.Query()[api.BlockStartKey], 1)
+		require.Len(t, u.Query()[api.BlockEndKey], 1)
+
+		start, err := uuid.Parse(u.Query().Get(api.BlockStartKey))
+		require.NoError(t, err)
+		end, err := uuid.Parse(u.Query().Get(api.BlockEndKey))
+		require.NoError(t, err)
+		ranges = append(ranges, [2]uuid.UUID{start, end})
+	}
+	sort.Slice(ranges, func(i, j int) bool { return bytes.Compare(ranges[i][0][:], ranges[j][0][:]) < 0 })
+	require.Equal(t, blockStart, ranges[0][0].String())
+	require.Equal(t, blockEnd, ranges[len(ranges)-1][1].String())
+	for i := 1; i < len(ranges); i++ {
+		require.Equal(t, ranges[i-1][1], ranges[i][0])
+	}
+
+	// invalid parameters are rejected
+	err = f.FindTraceByID(&tempopb.TraceByIDRequest{TraceID: []byte{0x12, 0x34}, QueryMode: "foo"}, newMockStreamingServer[*tempopb.TraceByIDResponse]("blerg", nil))
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	err = f.FindTraceByID(&tempopb.TraceByIDRequest{TraceID: []byte{0x12, 0x34}, BlockStart: "foo"}, newMockStreamingServer[*tempopb.TraceByIDResponse]("blerg", nil))
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestTraceIDHandlerAccessesCache(t *testing.T) {
+	oldTrace := test.MakeTrace(2, []byte{0x01, 0x02})
+	for _, b := range oldTrace.Batches {
+		for _, ss := range b.ScopeSpans {
+			for _, s := range ss.Spans {
+				s.EndTimeUnixNano = uint64(time.Now().Add(-time.Hour).UnixNano())
+			}
+		}
+	}
+	recentTrace := test.MakeTrace(2, []byte{0x03, 0x04})
+
+	tcs := []struct {
+		name        string
+		trace       *tempopb.Trace
+		expectCache bool
+	}{
+		{
+			name:        "completed trace is cached",
+			trace:       oldTrace,
+			expectCache: true,
+		},
+		{
+			name:        "in flight trace is not cached",
+			trace:       recentTrace,
+			expectCache: false,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			calls := atomic.NewInt32(0)
+			next := pipeline.RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+				calls.Inc()
+
+				var resBytes []byte
+				var err error
+				if r.RequestURI == "/querier/api/traces/1234?mode=ingesters" {
+					resBytes, err = proto.Marshal(&tempopb.TraceByIDResponse{Trace: tc.trace, Metrics: &tempopb.TraceByIDMetrics{}})
+				} else {
+					resBytes, err = proto.Marshal(&tempopb.TraceByIDResponse{Metrics: &tempopb.TraceByIDMetrics{}})
+				}
+				require.NoError(t, err)
+
+				return &http.Response{
+					Body:       io.NopCloser(bytes.NewReader(resBytes)),
+					StatusCode: 200,
+				}, nil
+			})
+
+			c := cache.NewMockCache()
+			p := test.NewMockProvider()
+			require.NoError(t, p.AddCache(cache.RoleFrontendTraceByID, c))
+
+			f := frontendWithSettings(t, next, nil, nil, p, func(cfg *Config) {
+				cfg.TraceByID.CacheMinTraceAge = 15 * time.Minute
+			})
+
+			query := func() *tempopb.Trace {
+				req := httptest.NewRequest("GET", "/api/traces/1234", nil)
+				req = req.WithContext(user.InjectOrgID(req.Context(), "blerg"))
+				req = mux.SetURLVars(req, map[string]string{"traceID": "1234"})
+				req.Header.Set("Accept", "application/protobuf")
+
+				httpResp := httptest.NewRecorder()
+				f.TraceByIDHandler.ServeHTTP(httpResp, req)
+				resp := httpResp.Result()
+				require.Equal(t, http.StatusOK, resp.StatusCode)
+				require.Equal(t, "application/protobuf", resp.Header.Get("Content-Type"))
+
+				body, err := io.ReadAll(resp.Body)
+				require.NoError(t, err)
+				actual := &tempopb.Trace{}
+				require.NoError(t, proto.Unmarshal(body, actual))
+				return actual
+			}
+
+			first := query()
+			require.Equal(t, int32(2), calls.Load())
+
+			second := query()
+			trace.SortTrace(first)
+			trace.SortTrace(second)
+			require.True(t, proto.Equal(first, second))
+
+			if tc.expectCache {
+				require.Equal(t, int32(2), calls.Load())
+			} else {
+				require.Equal(t, int32(4), calls.Load())
+			}
+		})
+	}
+}
+
+func TestTraceIDHandlerSkipsCacheForTombstonedTraces(t *testing.T) {
+	oldTrace := test.MakeTrace(2, []byte{0x01, 0x02})
+	for _, b := range oldTrace.Batches {
+		for _, ss := range b.ScopeSpans {
+			for _, s := range ss.Spans {
+				s.EndTimeUnixNano = uint64(time.Now().Add(-time.Hour).UnixNano())
+			}
+		}
+	}
+
+	calls := atomic.NewInt32(0)
+	next := pipeline.RoundTripperFunc(func(_ *http.Request) (*http.Response, error) {
+		calls.Inc()
+
+		resBytes, err := proto.Marshal(&tempopb.TraceByIDResponse{Trace: oldTrace, Metrics: &tempopb.TraceByIDMetrics{}})
+		require.NoError(t, err)
+
+		return &http.Response{
+			Body:       io.NopCloser(bytes.NewReader(resBytes)),
+			StatusCode: 200,
+		}, nil
+	})
+
+	c := cache.NewMockCache()
+	p := test.NewMockProvider()
+	require.NoError(t, p.AddCache(cache.RoleFrontendTraceByID, c))
+
+	reader := &mockReader{tombstoned: map[string]bool{}}
+	f := frontendWithSettings(t, next, reader, nil, p, func(cfg *Config) {
+		cfg.TraceByID.CacheMinTraceAge = 15 * time.Minute
+		cfg.TraceTombstonesEnabled = true
+	})
+
+	query := func() {
+		req := httptest.NewRequest("GET", "/api/traces/1234", nil)
+		req = req.WithContext(user.InjectOrgID(req.Context(), "blerg"))
+		req = mux.SetURLVars(req, map[string]string{"traceID": "1234"})
+		req.Header.Set("Accept", "application/protobuf")
+
+		httpResp := httptest.NewRecorder()
+		f.TraceByIDHandler.ServeHTTP(httpResp, req)
+		require.Equal(t, http.StatusOK, httpResp.Code)
+	}
+
+	// the trace is cached
+	query()
+	require.Equal(t, int32(2), calls.Load())
+	query()
+	require.Equal(t, int32(2), calls.Load())
+
+	// once the trace is deleted the cached response isn't served anymore
+	reader.tombstoned["1234"] = true
+	query()
+	require.Equal(t, int32(4), calls.Load())
+	query()
+	require.Equal(t, int32(6), calls.Load())
+}
+
+func TestTraceIDHandlerCachesEarlyExitSeparately(t *testing.T) {
+	makeTrace := func(id byte) *tempopb.Trace {
+		tr := test.MakeTrace(1, []byte{id})
+		for _, b := range tr.Batches {
+			for _, ss := range b.ScopeSpans {
+				for _, s := range ss.Spans {
+					s.EndTimeUnixNano = uint64(time.Now().Add(-time.Hour).UnixNano())
+				}
+			}
+		}
+		return tr
+	}
+	partialTrace := makeTrace(0x01)
+	fullTrace := makeTrace(0x02)
+
+	calls := atomic.NewInt32(0)
+	next := pipeline.RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		calls.Inc()
+
+		// the queriers return a partial trace when they stop early
+		tr := fullTrace
+		if r.URL.Query().Get(api.TraceByIDStopKey) == api.TraceByIDStopFirstBlock {
+			tr = partialTrace
+		}
+
+		resBytes, err := proto.Marshal(&tempopb.TraceByIDResponse{Trace: tr, Metrics: &tempopb.TraceByIDMetrics{}})
+		require.NoError(t, err)
+
+		return &http.Response{
+			Body:       io.NopCloser(bytes.NewReader(resBytes)),
+			StatusCode: 200,
+		}, nil
+	})
+
+	c := cache.NewMockCache()
+	p := test.NewMockProvider()
+	require.NoError(t, p.AddCache(cache.RoleFrontendTraceByID, c))
+
+	f := frontendWithSettings(t, next, nil, nil, p, func(cfg *Config) {
+		cfg.TraceByID.CacheMinTraceAge = 15 * time.Minute
+	})
+
+	query := func(target string) *tempopb.Trace {
+		req := httptest.NewRequest("GET", target, nil)
+		req = req.WithContext(user.InjectOrgID(req.Context(), "blerg"))
+		req = mux.SetURLVars(req, map[string]string{"traceID": "1234"})
+		req.Header.Set("Accept", "application/protobuf")
+
+		httpResp := httptest.NewRecorder()
+		f.TraceByIDHandler.ServeHTTP(httpResp, req)
+		require.Equal(t, http.StatusOK, httpResp.Code)
+
+		actual := &tempopb.Trace{}
+		require.NoError(t, proto.Unmarshal(httpResp.Body.Bytes(), actual))
+		trace.SortTrace(actual)
+		return actual
+	}
+
+	// the partial trace of an early exit isn't returned to a plain request
+	expectedPartial := query("/api/traces/1234?stop=first_block")
+	expectedFull := query("/api/traces/1234")
+	require.False(t, proto.Equal(expectedPartial, expectedFull))
+	queried := calls.Load()
+
+	// both responses are served from their own cache entries
+	require.True(t, proto.Equal(expectedPartial, query("/api/traces/1234?stop=first_block")))
+	require.True(t, proto.Equal(expectedFull, query("/api/traces/1234")))
+	require.True(t, proto.Equal(expectedFull, query("/api/traces/1234?stop=never")))
+	require.Equal(t, queried, calls.Load())
+}
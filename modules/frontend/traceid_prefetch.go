@@ -0,0 +1,133 @@
+package frontend
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level" //nolint:all //deprecated
+	"github.com/gorilla/mux"
+	"github.com/grafana/dskit/user"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/grafana/tempo/pkg/api"
+	"github.com/grafana/tempo/pkg/tempopb"
+)
+
+const (
+	// maxConcurrentPrefetches limits the trace by id requests issued in the background. prefetches are dropped
+	// instead of queued when the limit is reached so they never compete with user queries for long.
+	maxConcurrentPrefetches = 10
+	prefetchTimeout         = time.Minute
+)
+
+var metricPrefetchedTraces = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "tempo",
+	Name:      "query_frontend_prefetched_traces_total",
+	Help:      "Total number of traces from search results prefetched into the trace by id cache.",
+}, []string{"result"})
+
+// traceByIDPrefetcher requests the top traces of a search response in the background so the trace by id
+// handler stores them in the frontend-trace-by-id cache. a user clicking on a search result is then served
+// from the cache.
+type traceByIDPrefetcher struct {
+	traces      http.RoundTripper
+	count       int
+	minTraceAge time.Duration
+	apiPrefix   string
+	sem         chan struct{}
+	now         func() time.Time
+	logger      log.Logger
+}
+
+// newTraceByIDPrefetcher returns nil if prefetching is disabled or the trace by id cache is not configured.
+// all methods are safe to call on a nil prefetcher.
+func newTraceByIDPrefetcher(cfg Config, traces http.RoundTripper, c *traceByIDCache, apiPrefix string, logger log.Logger) *traceByIDPrefetcher {
+	if cfg.Search.PrefetchTraces <= 0 || c == nil {
+		return nil
+	}
+
+	return &traceByIDPrefetcher{
+		traces:      traces,
+		count:       cfg.Search.PrefetchTraces,
+		minTraceAge: cfg.TraceByID.CacheMinTraceAge,
+		apiPrefix:   apiPrefix,
+		sem:         make(chan struct{}, maxConcurrentPrefetches),
+		now:         time.Now,
+		logger:      logger,
+	}
+}
+
+// prefetch requests the first traces of the search response in the background. traces that are too young to
+// be cached are skipped.
+func (p *traceByIDPrefetcher) prefetch(tenant string, resp *tempopb.SearchResponse) {
+	if p == nil || resp == nil {
+		return
+	}
+
+	prefetched := 0
+	for _, t := range resp.Traces {
+		if prefetched >= p.count {
+			break
+		}
+
+		end := time.Unix(0, int64(t.StartTimeUnixNano)).Add(time.Duration(t.DurationMs) * time.Millisecond)
+		if p.now().Sub(end) < p.minTraceAge {
+			metricPrefetchedTraces.WithLabelValues("skipped").Inc()
+			continue
+		}
+		prefetched++
+
+		select {
+		case p.sem <- struct{}{}:
+		default:
+			metricPrefetchedTraces.WithLabelValues("dropped").Inc()
+			continue
+		}
+
+		go func(traceID string) {
+			defer func() { <-p.sem }()
+			p.fetch(tenant, traceID)
+		}(t.TraceID)
+	}
+}
+
+func (p *traceByIDPrefetcher) fetch(tenant, traceID string) {
+	// the search request is complete by now. use a new context so the prefetch isn't canceled with it
+	ctx, cancel := context.WithTimeout(user.InjectOrgID(context.Background(), tenant), prefetchTimeout)
+	defer cancel()
+
+	// the request has the defaults of the trace by id api so it's cached under the key of trace lookups without
+	// parameters. traces are cached in proto and served in any format
+	uri := path.Join(p.apiPrefix, strings.Replace(api.PathTraces, "{traceID}", traceID, 1))
+	req := (&http.Request{
+		Method:     http.MethodGet,
+		URL:        &url.URL{Path: uri},
+		RequestURI: uri,
+		Header:     http.Header{api.HeaderAccept: {api.HeaderAcceptProtobuf}},
+		Body:       io.NopCloser(bytes.NewReader([]byte{})),
+	}).WithContext(ctx)
+	req = mux.SetURLVars(req, map[string]string{api.URLParamTraceID: traceID})
+
+	resp, err := p.traces.RoundTrip(req)
+	if err != nil {
+		metricPrefetchedTraces.WithLabelValues("failed").Inc()
+		level.Debug(p.logger).Log("msg", "trace prefetch failed", "tenant", tenant, "traceID", traceID, "err", err)
+		return
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	_ = resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		metricPrefetchedTraces.WithLabelValues("failed").Inc()
+		return
+	}
+	metricPrefetchedTraces.WithLabelValues("success").Inc()
+}
@@ -0,0 +1,159 @@
+package frontend
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/gogo/protobuf/jsonpb"
+	"github.com/gogo/protobuf/proto"
+	"github.com/gorilla/mux"
+	"github.com/grafana/dskit/user"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/atomic"
+
+	"github.com/grafana/tempo/modules/frontend/pipeline"
+	"github.com/grafana/tempo/pkg/api"
+	"github.com/grafana/tempo/pkg/cache"
+	"github.com/grafana/tempo/pkg/tempopb"
+	"github.com/grafana/tempo/pkg/util"
+	"github.com/grafana/tempo/pkg/util/test"
+)
+
+func TestTraceByIDPrefetcher(t *testing.T) {
+	mtx := sync.Mutex{}
+	fetched := []string{}
+
+	traces := pipeline.RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		tenant, err := user.ExtractOrgID(r.Context())
+		require.NoError(t, err)
+		require.Equal(t, "foo", tenant)
+		require.Equal(t, api.HeaderAcceptProtobuf, r.Header.Get(api.HeaderAccept))
+
+		traceID, err := api.ParseTraceID(r)
+		require.NoError(t, err)
+
+		mtx.Lock()
+		fetched = append(fetched, util.TraceIDToHexString(traceID))
+		mtx.Unlock()
+
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewReader(nil))}, nil
+	})
+
+	cfg := Config{}
+	cfg.Search.PrefetchTraces = 2
+	cfg.TraceByID.CacheMinTraceAge = time.Minute
+
+	// disabled without the trace by id cache
+	require.Nil(t, newTraceByIDPrefetcher(cfg, traces, nil, "", log.NewNopLogger()))
+
+	p := newTraceByIDPrefetcher(cfg, traces, &traceByIDCache{}, "", log.NewNopLogger())
+	require.NotNil(t, p)
+
+	now := time.Now()
+	old := uint64(now.Add(-time.Hour).UnixNano())
+	p.prefetch("foo", &tempopb.SearchResponse{
+		Traces: []*tempopb.TraceSearchMetadata{
+			{TraceID: "1", StartTimeUnixNano: uint64(now.UnixNano())}, // too young to be cached
+			{TraceID: "2", StartTimeUnixNano: old},
+			{TraceID: "3", StartTimeUnixNano: uint64(now.Add(-2 * time.Minute).UnixNano()), DurationMs: 90_000}, // ended 30s ago
+			{TraceID: "4", StartTimeUnixNano: old},
+			{TraceID: "5", StartTimeUnixNano: old}, // over the limit
+		},
+	})
+
+	require.Eventually(t, func() bool {
+		mtx.Lock()
+		defer mtx.Unlock()
+		return len(fetched) == 2
+	}, 5*time.Second, 10*time.Millisecond)
+
+	mtx.Lock()
+	sort.Strings(fetched)
+	require.Equal(t, []string{"2", "4"}, fetched)
+	mtx.Unlock()
+
+	// a nil prefetcher does nothing
+	var nilPrefetcher *traceByIDPrefetcher
+	nilPrefetcher.prefetch("foo", &tempopb.SearchResponse{Traces: []*tempopb.TraceSearchMetadata{{TraceID: "1"}}})
+}
+
+func TestTraceByIDPrefetcherCachesTraceForLookups(t *testing.T) {
+	tr := test.MakeTrace(2, []byte{0x01, 0x02})
+	for _, b := range tr.Batches {
+		for _, ss := range b.ScopeSpans {
+			for _, s := range ss.Spans {
+				s.EndTimeUnixNano = uint64(time.Now().Add(-time.Hour).UnixNano())
+			}
+		}
+	}
+
+	calls := atomic.NewInt32(0)
+	next := pipeline.RoundTripperFunc(func(*http.Request) (*http.Response, error) {
+		calls.Inc()
+
+		resBytes, err := proto.Marshal(&tempopb.TraceByIDResponse{Trace: tr, Metrics: &tempopb.TraceByIDMetrics{}})
+		require.NoError(t, err)
+
+		return &http.Response{
+			Body:       io.NopCloser(bytes.NewReader(resBytes)),
+			StatusCode: 200,
+		}, nil
+	})
+
+	p := test.NewMockProvider()
+	require.NoError(t, p.AddCache(cache.RoleFrontendTraceByID, cache.NewMockCache()))
+
+	f := frontendWithSettings(t, next, nil, nil, p, func(cfg *Config) {
+		cfg.TraceByID.CacheMinTraceAge = 15 * time.Minute
+	})
+
+	// prefetch through the trace by id handler
+	traces := pipeline.RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		rec := httptest.NewRecorder()
+		f.TraceByIDHandler.ServeHTTP(rec, r)
+		return rec.Result(), nil
+	})
+	cfg := Config{}
+	cfg.Search.PrefetchTraces = 1
+	prefetcher := newTraceByIDPrefetcher(cfg, traces, &traceByIDCache{}, "", log.NewNopLogger())
+	prefetcher.prefetch("blerg", &tempopb.SearchResponse{
+		Traces: []*tempopb.TraceSearchMetadata{{TraceID: "102"}},
+	})
+
+	require.Eventually(t, func() bool {
+		return calls.Load() > 0 && len(prefetcher.sem) == 0
+	}, 5*time.Second, 10*time.Millisecond)
+	prefetchCalls := calls.Load()
+
+	// lookups in any format are served from the cache
+	for _, accept := range []string{api.HeaderAcceptProtobuf, api.HeaderAcceptJSON} {
+		req := httptest.NewRequest("GET", "/api/traces/0102", nil)
+		req = req.WithContext(user.InjectOrgID(req.Context(), "blerg"))
+		req = mux.SetURLVars(req, map[string]string{"traceID": "0102"})
+		req.Header.Set(api.HeaderAccept, accept)
+
+		rec := httptest.NewRecorder()
+		f.TraceByIDHandler.ServeHTTP(rec, req)
+		resp := rec.Result()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Equal(t, accept, resp.Header.Get(api.HeaderContentType))
+
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		actual := &tempopb.Trace{}
+		if accept == api.HeaderAcceptProtobuf {
+			require.NoError(t, proto.Unmarshal(body, actual))
+		} else {
+			require.NoError(t, jsonpb.Unmarshal(bytes.NewReader(body), actual))
+		}
+		require.Len(t, actual.Batches, len(tr.Batches))
+	}
+	require.Equal(t, prefetchCalls, calls.Load())
+}
@@ -1,17 +1,20 @@
 package frontend
 
 import (
+	"bytes"
 	"context"
 	"encoding/hex"
 	"net/http"
 
 	"github.com/go-kit/log" //nolint:all //deprecated
+	"github.com/google/uuid"
 	"github.com/grafana/dskit/user"
 	"github.com/opentracing/opentracing-go"
 
 	"github.com/grafana/tempo/modules/frontend/combiner"
 	"github.com/grafana/tempo/modules/frontend/pipeline"
 	"github.com/grafana/tempo/modules/querier"
+	"github.com/grafana/tempo/pkg/api"
 	"github.com/grafana/tempo/pkg/blockboundary"
 )
 
@@ -60,31 +63,65 @@ func (s asyncTraceSharder) RoundTrip(r *http.Request) (pipeline.Responses[combin
 	}, s.next), nil
 }
 
-// buildShardedRequests returns a slice of requests sharded on the precalculated
-// block boundaries
+// buildShardedRequests returns a slice of requests sharded on the precalculated block boundaries. The block range
+// and query mode of the parent request limit the shards: the block range of a shard is the intersection with the
+// range of the request and the shards outside of it are skipped.
 func (s *asyncTraceSharder) buildShardedRequests(ctx context.Context, parent *http.Request) ([]*http.Request, error) {
 	userID, err := user.ExtractOrgID(parent.Context())
 	if err != nil {
 		return nil, err
 	}
 
-	reqs := make([]*http.Request, s.cfg.QueryShards)
+	blockStart, blockEnd, queryMode, _, _, err := api.ValidateAndSanitizeRequest(parent)
+	if err != nil {
+		return nil, err
+	}
+
+	reqs := make([]*http.Request, 0, s.cfg.QueryShards)
+	if queryMode == querier.QueryModeAll || queryMode == querier.QueryModeIngesters {
+		// ingester query
+		req := parent.Clone(ctx)
+		q := req.URL.Query()
+		q.Del(querier.BlockStartKey)
+		q.Del(querier.BlockEndKey)
+		q.Set(querier.QueryModeKey, querier.QueryModeIngesters)
+		prepareRequestForQueriers(req, userID, req.URL.Path, q)
+		reqs = append(reqs, req)
+	}
+
+	if queryMode == querier.QueryModeIngesters {
+		return reqs, nil
+	}
+
+	start, err := uuid.Parse(blockStart)
+	if err != nil {
+		return nil, err
+	}
+	end, err := uuid.Parse(blockEnd)
+	if err != nil {
+		return nil, err
+	}
+
 	// build sharded block queries
-	for i := 0; i < len(s.blockBoundaries); i++ {
-		reqs[i] = parent.Clone(ctx)
-
-		q := reqs[i].URL.Query()
-		if i == 0 {
-			// ingester query
-			q.Add(querier.QueryModeKey, querier.QueryModeIngesters)
-		} else {
-			// block queries
-			q.Add(querier.BlockStartKey, hex.EncodeToString(s.blockBoundaries[i-1]))
-			q.Add(querier.BlockEndKey, hex.EncodeToString(s.blockBoundaries[i]))
-			q.Add(querier.QueryModeKey, querier.QueryModeBlocks)
+	for i := 1; i < len(s.blockBoundaries); i++ {
+		shardStart, shardEnd := s.blockBoundaries[i-1], s.blockBoundaries[i]
+		if bytes.Compare(start[:], shardStart) > 0 {
+			shardStart = start[:]
+		}
+		if bytes.Compare(end[:], shardEnd) < 0 {
+			shardEnd = end[:]
+		}
+		if bytes.Compare(shardStart, shardEnd) > 0 {
+			continue
 		}
 
-		prepareRequestForQueriers(reqs[i], userID, reqs[i].URL.Path, q)
+		req := parent.Clone(ctx)
+		q := req.URL.Query()
+		q.Set(querier.BlockStartKey, hex.EncodeToString(shardStart))
+		q.Set(querier.BlockEndKey, hex.EncodeToString(shardEnd))
+		q.Set(querier.QueryModeKey, querier.QueryModeBlocks)
+		prepareRequestForQueriers(req, userID, req.URL.Path, q)
+		reqs = append(reqs, req)
 	}
 
 	return reqs, nil
@@ -31,3 +31,41 @@ func TestBuildShardedRequests(t *testing.T) {
 	require.Equal(t, "/querier?mode=ingesters", shardedReqs[0].RequestURI)
 	require.Equal(t, "/querier?blockEnd=ffffffffffffffffffffffffffffffff&blockStart=00000000000000000000000000000000&mode=blocks", shardedReqs[1].RequestURI)
 }
+
+func TestBuildShardedRequestsWithBlockRange(t *testing.T) {
+	queryShards := 5
+
+	sharder := &asyncTraceSharder{
+		cfg: &TraceByIDConfig{
+			QueryShards: queryShards,
+		},
+		blockBoundaries: blockboundary.CreateBlockBoundaries(queryShards - 1),
+	}
+
+	ctx := user.InjectOrgID(context.Background(), "blerg")
+
+	// the range of the request covers the second shard and part of the third one
+	req := httptest.NewRequest("GET", "/?blockStart=40000000-0000-0000-0000-000000000000&blockEnd=a0000000-0000-0000-0000-000000000000", nil).WithContext(ctx)
+	shardedReqs, err := sharder.buildShardedRequests(ctx, req)
+	require.NoError(t, err)
+	require.Len(t, shardedReqs, 4)
+
+	require.Equal(t, "/querier?mode=ingesters", shardedReqs[0].RequestURI)
+	require.Equal(t, "/querier?blockEnd=40000000000000000000000000000000&blockStart=40000000000000000000000000000000&mode=blocks", shardedReqs[1].RequestURI)
+	require.Equal(t, "/querier?blockEnd=80000000000000000000000000000000&blockStart=40000000000000000000000000000000&mode=blocks", shardedReqs[2].RequestURI)
+	require.Equal(t, "/querier?blockEnd=a0000000000000000000000000000000&blockStart=80000000000000000000000000000000&mode=blocks", shardedReqs[3].RequestURI)
+
+	// only the block shards are queried in the blocks mode
+	req = httptest.NewRequest("GET", "/?blockStart=c0000000-0000-0000-0000-000000000001&mode=blocks", nil).WithContext(ctx)
+	shardedReqs, err = sharder.buildShardedRequests(ctx, req)
+	require.NoError(t, err)
+	require.Len(t, shardedReqs, 1)
+	require.Equal(t, "/querier?blockEnd=ffffffffffffffffffffffffffffffff&blockStart=c0000000000000000000000000000001&mode=blocks", shardedReqs[0].RequestURI)
+
+	// and only the ingesters in the ingesters mode
+	req = httptest.NewRequest("GET", "/?mode=ingesters", nil).WithContext(ctx)
+	shardedReqs, err = sharder.buildShardedRequests(ctx, req)
+	require.NoError(t, err)
+	require.Len(t, shardedReqs, 1)
+	require.Equal(t, "/querier?mode=ingesters", shardedReqs[0].RequestURI)
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"flag"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/grafana/dskit/flagext"
@@ -25,9 +26,23 @@ import (
 	"github.com/grafana/tempo/pkg/validation"
 )
 
+const (
+	// QueueFullReject rejects new requests of a tenant while its queue is full
+	QueueFullReject = "reject"
+	// QueueFullShedOldest fails the oldest request of a tenant to make room for a new one while its queue is full
+	QueueFullShedOldest = "shed_oldest"
+)
+
+var metricQueueOldestRequestAgeDesc = prometheus.NewDesc(
+	"tempo_query_frontend_queue_oldest_request_age_seconds",
+	"Age of the oldest query request in the queue.",
+	[]string{"user"}, nil,
+)
+
 // Config for a Frontend.
 type Config struct {
 	MaxOutstandingPerTenant int                    `yaml:"max_outstanding_per_tenant"`
+	QueueFullBehavior       string                 `yaml:"queue_full_behavior"`
 	QuerierForgetDelay      time.Duration          `yaml:"querier_forget_delay"`
 	MaxBatchSize            int                    `yaml:"max_batch_size"`
 	LogQueryRequestHeaders  flagext.StringSliceCSV `yaml:"log_query_request_headers"`
@@ -36,6 +51,7 @@ type Config struct {
 // RegisterFlags adds the flags required to config this to the given FlagSet.
 func (cfg *Config) RegisterFlags(f *flag.FlagSet) {
 	f.IntVar(&cfg.MaxOutstandingPerTenant, "querier.max-outstanding-requests-per-tenant", 2000, "Maximum number of outstanding requests per tenant per frontend; requests beyond this error with HTTP 429.")
+	f.StringVar(&cfg.QueueFullBehavior, "query-frontend.queue-full-behavior", QueueFullReject, "What to do with a new request of a tenant whose queue is full. reject fails the new request, shed_oldest fails the oldest request of the tenant. Failed requests error with HTTP 429.")
 	f.DurationVar(&cfg.QuerierForgetDelay, "query-frontend.querier-forget-delay", 0, "If a querier disconnects without sending notification about graceful shutdown, the query-frontend will keep the querier in the tenant's shard until the forget delay has passed. This feature is useful to reduce the blast radius when shuffle-sharding is enabled.")
 	f.Var(&cfg.LogQueryRequestHeaders, "query-frontend.log-query-request-headers", "Comma-separated list of request header names to include in query logs. Applies to both query stats and slow queries logs.")
 }
@@ -57,6 +73,10 @@ type Frontend struct {
 	requestQueue *queue.RequestQueue
 	activeUsers  *util.ActiveUsersCleanupService
 
+	// queued requests per tenant to report the age of the oldest one
+	queuedMtx sync.Mutex
+	queued    map[string]map[*request]struct{}
+
 	// Subservices manager.
 	subservices        *services.Manager
 	subservicesWatcher *services.FailureWatcher
@@ -70,6 +90,7 @@ type Frontend struct {
 }
 
 type request struct {
+	userID      string
 	enqueueTime time.Time
 	queueSpan   opentracing.Span
 	originalCtx context.Context
@@ -85,12 +106,16 @@ func New(cfg Config, limits Limits, log log.Logger, registerer prometheus.Regist
 	if cfg.MaxBatchSize <= 0 {
 		return nil, errors.New("max_batch_size must be positive")
 	}
+	if cfg.QueueFullBehavior != QueueFullReject && cfg.QueueFullBehavior != QueueFullShedOldest {
+		return nil, fmt.Errorf("queue_full_behavior must be %s or %s", QueueFullReject, QueueFullShedOldest)
+	}
 	batchBucketSize := float64(cfg.MaxBatchSize) / float64(batchBucketCount)
 
 	f := &Frontend{
 		cfg:    cfg,
 		log:    log,
 		limits: limits,
+		queued: map[string]map[*request]struct{}{},
 		queueLength: promauto.With(registerer).NewGaugeVec(prometheus.GaugeOpts{
 			Name: "tempo_query_frontend_queue_length",
 			Help: "Number of queries in the queue.",
@@ -111,7 +136,11 @@ func New(cfg Config, limits Limits, log log.Logger, registerer prometheus.Regist
 		}),
 	}
 
-	f.requestQueue = queue.NewRequestQueue(cfg.MaxOutstandingPerTenant, cfg.QuerierForgetDelay, f.queueLength, f.discardedRequests)
+	var onShed func(queue.Request)
+	if cfg.QueueFullBehavior == QueueFullShedOldest {
+		onShed = f.shedRequest
+	}
+	f.requestQueue = queue.NewRequestQueue(cfg.MaxOutstandingPerTenant, cfg.QuerierForgetDelay, f.queueLength, f.discardedRequests, onShed)
 	f.activeUsers = util.NewActiveUsersCleanupWithDefaultValues(f.cleanupInactiveUserMetrics)
 
 	var err error
@@ -125,6 +154,21 @@ func New(cfg Config, limits Limits, log log.Logger, registerer prometheus.Regist
 		Help: "Number of worker clients currently connected to the frontend.",
 	}, f.requestQueue.GetConnectedQuerierWorkersMetric)
 
+	if registerer != nil {
+		if err := registerer.Register(f); err != nil {
+			var alreadyRegistered prometheus.AlreadyRegisteredError
+			if !errors.As(err, &alreadyRegistered) {
+				return nil, fmt.Errorf("failed to register the queue age collector: %w", err)
+			}
+
+			// replace the collector of a previous frontend so the queue of this one is reported
+			registerer.Unregister(alreadyRegistered.ExistingCollector)
+			if err := registerer.Register(f); err != nil {
+				return nil, fmt.Errorf("failed to register the queue age collector: %w", err)
+			}
+		}
+	}
+
 	f.Service = services.NewBasicService(f.starting, f.running, f.stopping)
 	return f, nil
 }
@@ -228,6 +272,7 @@ func (f *Frontend) Process(server frontendv1pb.Frontend_ProcessServer) error {
 		reqBatch.clear()
 		for _, reqWrapper := range reqSlice {
 			req := reqWrapper.(*request)
+			f.untrackQueued(req)
 
 			f.queueDuration.Observe(time.Since(req.enqueueTime).Seconds())
 			req.queueSpan.Finish()
@@ -374,7 +419,72 @@ func (f *Frontend) queueRequest(ctx context.Context, req *request) error {
 	joinedTenantID := tenant.JoinTenantIDs(tenantIDs)
 	f.activeUsers.UpdateUserTimestamp(joinedTenantID, now)
 
-	return f.requestQueue.EnqueueRequest(joinedTenantID, req, maxQueriers)
+	// the request is tracked first as it can be dequeued before EnqueueRequest returns
+	req.userID = joinedTenantID
+	f.trackQueued(req)
+
+	err = f.requestQueue.EnqueueRequest(joinedTenantID, req, maxQueriers)
+	if err != nil {
+		f.untrackQueued(req)
+	}
+	return err
+}
+
+// shedRequest fails a request that was removed from a full queue to make room for a newer one
+func (f *Frontend) shedRequest(r queue.Request) {
+	req := r.(*request)
+	f.untrackQueued(req)
+	req.queueSpan.Finish()
+
+	// the error channel is buffered and nothing else writes to it until the request is dequeued
+	select {
+	case req.err <- queue.ErrTooManyRequests:
+	default:
+	}
+}
+
+func (f *Frontend) trackQueued(req *request) {
+	f.queuedMtx.Lock()
+	defer f.queuedMtx.Unlock()
+
+	reqs := f.queued[req.userID]
+	if reqs == nil {
+		reqs = map[*request]struct{}{}
+		f.queued[req.userID] = reqs
+	}
+	reqs[req] = struct{}{}
+}
+
+func (f *Frontend) untrackQueued(req *request) {
+	f.queuedMtx.Lock()
+	defer f.queuedMtx.Unlock()
+
+	reqs := f.queued[req.userID]
+	delete(reqs, req)
+	if len(reqs) == 0 {
+		delete(f.queued, req.userID)
+	}
+}
+
+func (f *Frontend) Describe(ch chan<- *prometheus.Desc) {
+	ch <- metricQueueOldestRequestAgeDesc
+}
+
+// Collect reports the age of the oldest queued request of every tenant with queued requests
+func (f *Frontend) Collect(ch chan<- prometheus.Metric) {
+	f.queuedMtx.Lock()
+	defer f.queuedMtx.Unlock()
+
+	now := time.Now()
+	for userID, reqs := range f.queued {
+		oldest := now
+		for req := range reqs {
+			if req.enqueueTime.Before(oldest) {
+				oldest = req.enqueueTime
+			}
+		}
+		ch <- prometheus.MustNewConstMetric(metricQueueOldestRequestAgeDesc, prometheus.GaugeValue, now.Sub(oldest).Seconds(), userID)
+	}
 }
 
 // CheckReady determines if the query frontend is ready.  Function parameters/return
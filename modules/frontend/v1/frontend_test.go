@@ -0,0 +1,95 @@
+package v1
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/grafana/dskit/httpgrpc"
+	"github.com/grafana/dskit/user"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/modules/frontend/queue"
+)
+
+type mockLimits struct{}
+
+func (mockLimits) MaxQueriersPerUser(string) int { return 0 }
+
+func TestFrontendQueueFullShedOldest(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	f, err := New(Config{
+		MaxOutstandingPerTenant: 1,
+		QueueFullBehavior:       QueueFullShedOldest,
+		MaxBatchSize:            1,
+	}, mockLimits{}, log.NewNopLogger(), reg)
+	require.NoError(t, err)
+
+	ctx := user.InjectOrgID(context.Background(), "test")
+
+	// the first request waits in the queue as no querier is connected
+	errs := make(chan error, 1)
+	go func() {
+		_, err := f.RoundTripGRPC(ctx, &httpgrpc.HTTPRequest{Url: "/first"})
+		errs <- err
+	}()
+	require.Eventually(t, func() bool {
+		return testutil.CollectAndCount(f, "tempo_query_frontend_queue_oldest_request_age_seconds") == 1
+	}, time.Second, 10*time.Millisecond)
+
+	// the second request takes its place and the first one fails
+	secondCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		_, _ = f.RoundTripGRPC(secondCtx, &httpgrpc.HTTPRequest{Url: "/second"})
+	}()
+
+	select {
+	case err := <-errs:
+		require.ErrorIs(t, err, queue.ErrTooManyRequests)
+	case <-time.After(time.Second):
+		t.Fatal("first request wasn't shed")
+	}
+	require.Equal(t, 1, testutil.CollectAndCount(f, "tempo_query_frontend_queue_oldest_request_age_seconds"))
+}
+
+func TestFrontendQueueAgeCollectorAlreadyRegistered(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	previous := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "tempo_query_frontend_queue_oldest_request_age_seconds",
+		Help: "Age of the oldest query request in the queue.",
+	}, []string{"user"})
+	previous.WithLabelValues("previous").Set(1)
+	reg.MustRegister(previous)
+
+	f, err := New(Config{
+		MaxOutstandingPerTenant: 1,
+		QueueFullBehavior:       QueueFullReject,
+		MaxBatchSize:            1,
+	}, mockLimits{}, log.NewNopLogger(), reg)
+	require.NoError(t, err)
+
+	// the new frontend replaced the previous collector
+	require.Zero(t, testutil.CollectAndCount(reg, "tempo_query_frontend_queue_oldest_request_age_seconds"))
+
+	ctx, cancel := context.WithCancel(user.InjectOrgID(context.Background(), "test"))
+	defer cancel()
+	go func() {
+		_, _ = f.RoundTripGRPC(ctx, &httpgrpc.HTTPRequest{Url: "/queued"})
+	}()
+	require.Eventually(t, func() bool {
+		return testutil.CollectAndCount(reg, "tempo_query_frontend_queue_oldest_request_age_seconds") == 1
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestFrontendInvalidQueueFullBehavior(t *testing.T) {
+	_, err := New(Config{
+		MaxOutstandingPerTenant: 1,
+		QueueFullBehavior:       "drop",
+		MaxBatchSize:            1,
+	}, mockLimits{}, log.NewNopLogger(), nil)
+	require.Error(t, err)
+}
@@ -21,6 +21,7 @@ type Config struct {
 	MaxBlockDuration     time.Duration         `yaml:"max_block_duration"`
 	MaxBlockBytes        uint64                `yaml:"max_block_bytes"`
 	CompleteBlockTimeout time.Duration         `yaml:"complete_block_timeout"`
+	MaxDiskBytes         uint64                `yaml:"max_disk_bytes"`
 	MaxLiveTraces        uint64                `yaml:"max_live_traces"`
 	FilterServerSpans    bool                  `yaml:"filter_server_spans"`
 	FlushToStorage       bool                  `yaml:"flush_to_storage"`
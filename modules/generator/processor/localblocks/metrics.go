@@ -68,6 +68,12 @@ var (
 		Name:      "flushed_blocks",
 		Help:      "Number of blocks flushed by the local blocks processor",
 	}, []string{"tenant"})
+	metricDiskBudgetDeletedBlocks = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: subsystem,
+		Name:      "disk_budget_deleted_blocks_total",
+		Help:      "Number of complete blocks deleted before the complete block timeout because the local blocks exceeded max_disk_bytes",
+	}, []string{"tenant"})
 	metricFlushQueueSize = promauto.NewGaugeVec(prometheus.GaugeOpts{
 		Namespace: namespace,
 		Subsystem: subsystem,
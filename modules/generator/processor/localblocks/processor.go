@@ -412,6 +412,9 @@ func (p *Processor) GetMetrics(ctx context.Context, req *tempopb.SpanMetricsRequ
 		blocks = append(blocks, b)
 	}
 
+	// The time range covered by the searched blocks
+	var dataStart, dataEnd uint32
+
 	m := traceqlmetrics.NewMetricsResults()
 	for _, b := range blocks {
 
@@ -451,16 +454,30 @@ func (p *Processor) GetMetrics(ctx context.Context, req *tempopb.SpanMetricsRequ
 		}
 
 		m.Combine(r)
+		if !meta.StartTime.IsZero() {
+			dataStart, dataEnd = coveredRange(dataStart, dataEnd, blockStart, blockEnd)
+		}
 
 		if req.Limit > 0 && m.SpanCount >= int(req.Limit) {
 			break
 		}
 	}
 
+	// Limit the covered range to the requested range
+	if req.Start > 0 && req.End > 0 {
+		dataStart = max(dataStart, req.Start)
+		dataEnd = min(dataEnd, req.End)
+		if dataEnd < dataStart {
+			dataStart, dataEnd = 0, 0
+		}
+	}
+
 	resp := &tempopb.SpanMetricsResponse{
 		SpanCount: uint64(m.SpanCount),
 		Estimated: m.Estimated,
 		Metrics:   make([]*tempopb.SpanMetrics, 0, len(m.Series)),
+		Start:     dataStart,
+		End:       dataEnd,
 	}
 
 	var rawHistorgram *tempopb.RawHistogram
@@ -494,6 +511,14 @@ func (p *Processor) GetMetrics(ctx context.Context, req *tempopb.SpanMetricsRequ
 	return resp, nil
 }
 
+// coveredRange extends the range start-end with the range of a block. An empty range is start=end=0.
+func coveredRange(start, end, blockStart, blockEnd uint32) (uint32, uint32) {
+	if start == 0 && end == 0 {
+		return blockStart, blockEnd
+	}
+	return min(start, blockStart), max(end, blockEnd)
+}
+
 // QueryRange returns metrics.
 func (p *Processor) QueryRange(ctx context.Context, req *tempopb.QueryRangeRequest) (traceql.SeriesSet, error) {
 	ctx, cancel := context.WithCancel(ctx)
@@ -661,9 +686,51 @@ func (p *Processor) deleteOldBlocks() (err error) {
 		}
 	}
 
+	if p.Cfg.MaxDiskBytes > 0 {
+		err = p.deleteBlocksOverDiskBudget()
+	}
+
 	return
 }
 
+// deleteBlocksOverDiskBudget deletes the oldest complete blocks while the local blocks use more than max_disk_bytes.
+// Blocks that still have to be flushed to storage are kept. Must be called under blocksMtx.
+func (p *Processor) deleteBlocksOverDiskBudget() error {
+	used := p.blockBytes()
+	if used <= p.Cfg.MaxDiskBytes {
+		return nil
+	}
+
+	blocks := make([]*ingester.LocalBlock, 0, len(p.completeBlocks))
+	for _, b := range p.completeBlocks {
+		if p.Cfg.FlushToStorage && b.FlushedTime().IsZero() {
+			continue
+		}
+		blocks = append(blocks, b)
+	}
+	sort.Slice(blocks, func(i, j int) bool {
+		return blocks[i].BlockMeta().EndTime.Before(blocks[j].BlockMeta().EndTime)
+	})
+
+	for _, b := range blocks {
+		if used <= p.Cfg.MaxDiskBytes {
+			break
+		}
+
+		id := b.BlockMeta().BlockID
+		level.Info(p.logger).Log("msg", "deleting complete block over disk budget", "block", id.String(), "used", used, "max", p.Cfg.MaxDiskBytes)
+		err := p.wal.LocalBackend().ClearBlock(id, p.tenant)
+		if err != nil {
+			return err
+		}
+		delete(p.completeBlocks, id)
+		used -= min(used, b.BlockMeta().Size)
+		metricDiskBudgetDeletedBlocks.WithLabelValues(p.tenant).Inc()
+	}
+
+	return nil
+}
+
 func (p *Processor) cutIdleTraces(immediate bool) error {
 	p.liveTracesMtx.Lock()
 
@@ -867,6 +934,11 @@ func (p *Processor) recordBlockBytes() {
 	p.blocksMtx.RLock()
 	defer p.blocksMtx.RUnlock()
 
+	metricBlockSize.WithLabelValues(p.tenant).Set(float64(p.blockBytes()))
+}
+
+// blockBytes returns the size of the head, wal and complete blocks. Must be called under blocksMtx.
+func (p *Processor) blockBytes() uint64 {
 	sum := uint64(0)
 
 	if p.headBlock != nil {
@@ -879,7 +951,7 @@ func (p *Processor) recordBlockBytes() {
 		sum += b.BlockMeta().Size
 	}
 
-	metricBlockSize.WithLabelValues(p.tenant).Set(float64(sum))
+	return sum
 }
 
 func metricSeriesToProto(series traceqlmetrics.MetricSeries) []*tempopb.KeyValue {
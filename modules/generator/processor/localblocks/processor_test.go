@@ -2,6 +2,7 @@ package localblocks
 
 import (
 	"context"
+	"math"
 	"os"
 	"path/filepath"
 	"sync"
@@ -68,6 +69,20 @@ func (m *mockWriter) CompleteBlockWithBackend(context.Context, common.WALBlock,
 
 func (m *mockWriter) WAL() *wal.WAL { return nil }
 
+func (m *mockWriter) SetBlocklistNotifier(tempodb.BlocklistNotifier) {}
+
+func (m *mockWriter) AddTraceAnnotation(context.Context, string, common.ID, *tempodb.Annotation) error {
+	return nil
+}
+
+func (m *mockWriter) MarkTenantForDeletion(context.Context, string) (*tempodb.TenantDeletion, error) {
+	return nil, nil
+}
+
+func (m *mockWriter) AddTraceTombstones(context.Context, string, []common.ID) error {
+	return nil
+}
+
 func TestProcessorDoesNotRace(t *testing.T) {
 	wal, err := wal.New(&wal.Config{
 		Filepath: t.TempDir(),
@@ -249,6 +264,145 @@ func TestReplicationFactor(t *testing.T) {
 	p.blocksMtx.Unlock()
 }
 
+func TestDeleteBlocksOverDiskBudget(t *testing.T) {
+	wal, err := wal.New(&wal.Config{
+		Filepath: t.TempDir(),
+		Version:  encoding.DefaultEncoding().Version(),
+	})
+	require.NoError(t, err)
+
+	cfg := Config{
+		FlushCheckPeriod:     time.Minute,
+		TraceIdlePeriod:      time.Minute,
+		CompleteBlockTimeout: time.Hour,
+		Block: &common.BlockConfig{
+			BloomShardSizeBytes: 100_000,
+			BloomFP:             0.05,
+			Version:             encoding.DefaultEncoding().Version(),
+		},
+		Metrics: MetricsConfig{
+			ConcurrentBlocks:  10,
+			TimeOverlapCutoff: 0.2,
+		},
+		MaxDiskBytes: math.MaxUint64,
+	}
+
+	p, err := New(cfg, "fake", wal, nil, &mockOverrides{})
+	require.NoError(t, err)
+	defer p.Shutdown(context.TODO())
+
+	for i := 0; i < 2; i++ {
+		tr := test.MakeTrace(10, test.ValidTraceID(nil))
+		p.PushSpans(context.TODO(), &tempopb.PushSpansRequest{
+			Batches: tr.Batches,
+		})
+		require.NoError(t, p.cutIdleTraces(true))
+		require.NoError(t, p.cutBlocks(true))
+		require.NoError(t, p.completeBlock())
+	}
+	require.Len(t, p.completeBlocks, 2)
+
+	// within the budget
+	require.NoError(t, p.deleteOldBlocks())
+	require.Len(t, p.completeBlocks, 2)
+
+	// the oldest block is deleted first
+	var newest *backend.BlockMeta
+	for _, b := range p.completeBlocks {
+		if newest == nil {
+			newest = b.BlockMeta()
+			continue
+		}
+		b.BlockMeta().EndTime = newest.EndTime.Add(-time.Minute)
+	}
+	p.Cfg.MaxDiskBytes = p.blockBytes() - 1
+	require.NoError(t, p.deleteOldBlocks())
+	require.Len(t, p.completeBlocks, 1)
+	require.Contains(t, p.completeBlocks, newest.BlockID)
+
+	p.Cfg.MaxDiskBytes = 1
+	require.NoError(t, p.deleteOldBlocks())
+	require.Empty(t, p.completeBlocks)
+}
+
+func TestGetMetricsCoveredRange(t *testing.T) {
+	wal, err := wal.New(&wal.Config{
+		Filepath: t.TempDir(),
+		Version:  encoding.DefaultEncoding().Version(),
+	})
+	require.NoError(t, err)
+
+	cfg := Config{
+		FlushCheckPeriod:     time.Minute,
+		TraceIdlePeriod:      time.Minute,
+		CompleteBlockTimeout: time.Hour,
+		Block: &common.BlockConfig{
+			BloomShardSizeBytes: 100_000,
+			BloomFP:             0.05,
+			Version:             encoding.DefaultEncoding().Version(),
+		},
+		Metrics: MetricsConfig{
+			ConcurrentBlocks:  10,
+			TimeOverlapCutoff: 0.2,
+		},
+	}
+
+	p, err := New(cfg, "fake", wal, nil, &mockOverrides{})
+	require.NoError(t, err)
+	defer p.Shutdown(context.TODO())
+
+	ctx := context.Background()
+
+	// no data
+	resp, err := p.GetMetrics(ctx, &tempopb.SpanMetricsRequest{Query: "{}", GroupBy: "status"})
+	require.NoError(t, err)
+	require.Zero(t, resp.Start)
+	require.Zero(t, resp.End)
+
+	tr := test.MakeTrace(10, test.ValidTraceID(nil))
+	p.PushSpans(ctx, &tempopb.PushSpansRequest{
+		Batches: tr.Batches,
+	})
+	require.NoError(t, p.cutIdleTraces(true))
+	require.NoError(t, p.cutBlocks(true))
+	require.NoError(t, p.completeBlock())
+	require.Len(t, p.completeBlocks, 1)
+
+	now := time.Now().Truncate(time.Second)
+	for _, b := range p.completeBlocks {
+		b.BlockMeta().StartTime = now.Add(-10 * time.Minute)
+		b.BlockMeta().EndTime = now.Add(-5 * time.Minute)
+	}
+
+	// the range of the block
+	resp, err = p.GetMetrics(ctx, &tempopb.SpanMetricsRequest{Query: "{}", GroupBy: "status"})
+	require.NoError(t, err)
+	require.Equal(t, uint32(now.Add(-10*time.Minute).Unix()), resp.Start)
+	require.Equal(t, uint32(now.Add(-5*time.Minute).Unix()), resp.End)
+
+	// the part of the block within the requested range
+	resp, err = p.GetMetrics(ctx, &tempopb.SpanMetricsRequest{
+		Query:   "{}",
+		GroupBy: "status",
+		Start:   uint32(now.Add(-30 * time.Minute).Unix()),
+		End:     uint32(now.Add(-7 * time.Minute).Unix()),
+	})
+	require.NoError(t, err)
+	require.Equal(t, uint32(now.Add(-10*time.Minute).Unix()), resp.Start)
+	require.Equal(t, uint32(now.Add(-7*time.Minute).Unix()), resp.End)
+
+	// the requested range doesn't overlap the block
+	resp, err = p.GetMetrics(ctx, &tempopb.SpanMetricsRequest{
+		Query:   "{}",
+		GroupBy: "status",
+		Start:   uint32(now.Add(-4 * time.Minute).Unix()),
+		End:     uint32(now.Unix()),
+	})
+	require.NoError(t, err)
+	require.Zero(t, resp.Start)
+	require.Zero(t, resp.End)
+}
+
 func verifyReplicationFactor(t *testing.T, b common.BackendBlock) {
 	require.Equal(t, 1, int(b.BlockMeta().ReplicationFactor))
 }
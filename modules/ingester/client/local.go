@@ -0,0 +1,72 @@
+package client
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+
+	"github.com/grafana/tempo/pkg/tempopb"
+)
+
+// LocalClient is a querier client for an ingester running in the same process. Requests are passed to the ingester
+// directly instead of being marshaled and sent over grpc.
+type LocalClient struct {
+	server tempopb.QuerierServer
+}
+
+var _ tempopb.QuerierClient = (*LocalClient)(nil)
+
+// NewLocal returns a client that calls the given ingester directly.
+func NewLocal(server tempopb.QuerierServer) *LocalClient {
+	return &LocalClient{server: server}
+}
+
+func (c *LocalClient) FindTraceByID(ctx context.Context, req *tempopb.TraceByIDRequest, _ ...grpc.CallOption) (*tempopb.TraceByIDResponse, error) {
+	return c.server.FindTraceByID(ctx, req)
+}
+
+// FindTraceByIDStream is not supported. Traces are split in chunks to stay below the grpc max message size which
+// doesn't apply in process, so callers fall back to FindTraceByID.
+func (c *LocalClient) FindTraceByIDStream(context.Context, *tempopb.TraceByIDRequest, ...grpc.CallOption) (tempopb.Querier_FindTraceByIDStreamClient, error) {
+	return nil, status.Error(codes.Unimplemented, "streaming is not supported by the local ingester client")
+}
+
+func (c *LocalClient) SearchRecent(ctx context.Context, req *tempopb.SearchRequest, _ ...grpc.CallOption) (*tempopb.SearchResponse, error) {
+	return c.server.SearchRecent(ctx, req)
+}
+
+func (c *LocalClient) SearchBlock(ctx context.Context, req *tempopb.SearchBlockRequest, _ ...grpc.CallOption) (*tempopb.SearchResponse, error) {
+	return c.server.SearchBlock(ctx, req)
+}
+
+func (c *LocalClient) SearchTags(ctx context.Context, req *tempopb.SearchTagsRequest, _ ...grpc.CallOption) (*tempopb.SearchTagsResponse, error) {
+	return c.server.SearchTags(ctx, req)
+}
+
+func (c *LocalClient) SearchTagsV2(ctx context.Context, req *tempopb.SearchTagsRequest, _ ...grpc.CallOption) (*tempopb.SearchTagsV2Response, error) {
+	return c.server.SearchTagsV2(ctx, req)
+}
+
+func (c *LocalClient) SearchTagValues(ctx context.Context, req *tempopb.SearchTagValuesRequest, _ ...grpc.CallOption) (*tempopb.SearchTagValuesResponse, error) {
+	return c.server.SearchTagValues(ctx, req)
+}
+
+func (c *LocalClient) SearchTagValuesV2(ctx context.Context, req *tempopb.SearchTagValuesRequest, _ ...grpc.CallOption) (*tempopb.SearchTagValuesV2Response, error) {
+	return c.server.SearchTagValuesV2(ctx, req)
+}
+
+// Check implements grpc_health_v1.HealthClient. The ingester is in the same process so it is always reachable.
+func (c *LocalClient) Check(context.Context, *grpc_health_v1.HealthCheckRequest, ...grpc.CallOption) (*grpc_health_v1.HealthCheckResponse, error) {
+	return &grpc_health_v1.HealthCheckResponse{Status: grpc_health_v1.HealthCheckResponse_SERVING}, nil
+}
+
+func (c *LocalClient) Watch(context.Context, *grpc_health_v1.HealthCheckRequest, ...grpc.CallOption) (grpc_health_v1.Health_WatchClient, error) {
+	return nil, status.Error(codes.Unimplemented, "watch is not supported by the local ingester client")
+}
+
+func (c *LocalClient) Close() error {
+	return nil
+}
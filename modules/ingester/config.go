@@ -28,6 +28,15 @@ type Config struct {
 	OverrideRingKey      string        `yaml:"override_ring_key"`
 	FlushAllOnShutdown   bool          `yaml:"flush_all_on_shutdown"`
 
+	// traces returned by FindTraceByIDStream are split into chunks of roughly this size
+	TraceByIDChunkSizeBytes int `yaml:"trace_by_id_chunk_size_bytes"`
+
+	// how often the tenants marked for deletion are read from the backend. their traces are rejected. 0 disables
+	TenantDeletionCheckPeriod time.Duration `yaml:"tenant_deletion_check_period"`
+	// how often the tenants that exceed their storage quota are read from the backend. their traces are rejected. 0
+	// disables
+	StorageQuotaCheckPeriod time.Duration `yaml:"storage_quota_check_period"`
+
 	DedicatedColumns             backend.DedicatedColumns `yaml:"-"`
 	AutocompleteFilteringEnabled bool                     `yaml:"-"`
 }
@@ -44,6 +53,9 @@ func (cfg *Config) RegisterFlagsAndApplyDefaults(prefix string, f *flag.FlagSet)
 	cfg.FlushCheckPeriod = 10 * time.Second
 	cfg.FlushOpTimeout = 5 * time.Minute
 	cfg.FlushAllOnShutdown = false
+	cfg.TraceByIDChunkSizeBytes = 1024 * 1024
+	cfg.TenantDeletionCheckPeriod = time.Minute
+	cfg.StorageQuotaCheckPeriod = time.Minute
 
 	f.DurationVar(&cfg.MaxTraceIdle, prefix+".trace-idle-period", 10*time.Second, "Duration after which to consider a trace complete if no spans have been received")
 	f.DurationVar(&cfg.MaxBlockDuration, prefix+".max-block-duration", 30*time.Minute, "Maximum duration which the head block can be appended to before cutting it.")
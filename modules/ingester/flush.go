@@ -184,6 +184,20 @@ func (i *Ingester) sweepInstance(instance *instance, immediate bool) {
 		}, !immediate)
 	}
 
+	// the traces that request a longer retention are cut to blocks of their own
+	blockIDs, err := instance.CutRetentionBlocksIfReady(i.cfg.MaxBlockDuration, i.cfg.MaxBlockBytes, immediate)
+	if err != nil {
+		level.Error(log.WithUserID(instance.instanceID, log.Logger)).Log("msg", "failed to cut retention block", "err", err)
+	}
+	for _, blockID := range blockIDs {
+		level.Info(log.Logger).Log("msg", "retention head block cut. enqueueing flush op", "userid", instance.instanceID, "block", blockID)
+		i.enqueue(&flushOp{
+			kind:    opKindComplete,
+			userID:  instance.instanceID,
+			blockID: blockID,
+		}, !immediate)
+	}
+
 	// dump any blocks that have been flushed for awhile
 	err = instance.ClearFlushedBlocks(i.cfg.CompleteBlockTimeout)
 	if err != nil {
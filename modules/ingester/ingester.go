@@ -23,6 +23,7 @@ import (
 	"github.com/grafana/tempo/modules/storage"
 	"github.com/grafana/tempo/pkg/flushqueues"
 	"github.com/grafana/tempo/pkg/model"
+	"github.com/grafana/tempo/pkg/model/trace"
 	v1 "github.com/grafana/tempo/pkg/model/v1"
 	v2 "github.com/grafana/tempo/pkg/model/v2"
 	"github.com/grafana/tempo/pkg/tempopb"
@@ -57,6 +58,14 @@ type Ingester struct {
 	instances    map[string]*instance
 	pushErr      atomic.Error
 
+	// tenants marked for deletion. their traces are rejected
+	deletedTenantsMtx sync.RWMutex
+	deletedTenants    map[string]struct{}
+
+	// tenants that exceed their storage quota and reject traces
+	overQuotaTenantsMtx sync.RWMutex
+	overQuotaTenants    map[string]struct{}
+
 	lifecycler   *ring.Lifecycler
 	store        storage.Store
 	local        *local.Backend
@@ -115,6 +124,13 @@ func (i *Ingester) starting(ctx context.Context) error {
 		return fmt.Errorf("failed to rediscover local blocks: %w", err)
 	}
 
+	if i.cfg.TenantDeletionCheckPeriod > 0 {
+		i.updateDeletedTenants(ctx)
+	}
+	if i.cfg.StorageQuotaCheckPeriod > 0 {
+		i.updateOverQuotaTenants(ctx)
+	}
+
 	i.flushQueuesDone.Add(i.cfg.ConcurrentFlushes)
 	for j := 0; j < i.cfg.ConcurrentFlushes; j++ {
 		go i.flushLoop(j)
@@ -138,11 +154,31 @@ func (i *Ingester) loop(ctx context.Context) error {
 	flushTicker := time.NewTicker(i.cfg.FlushCheckPeriod)
 	defer flushTicker.Stop()
 
+	var tenantDeletionC <-chan time.Time
+	if i.cfg.TenantDeletionCheckPeriod > 0 {
+		tenantDeletionTicker := time.NewTicker(i.cfg.TenantDeletionCheckPeriod)
+		defer tenantDeletionTicker.Stop()
+		tenantDeletionC = tenantDeletionTicker.C
+	}
+
+	var storageQuotaC <-chan time.Time
+	if i.cfg.StorageQuotaCheckPeriod > 0 {
+		storageQuotaTicker := time.NewTicker(i.cfg.StorageQuotaCheckPeriod)
+		defer storageQuotaTicker.Stop()
+		storageQuotaC = storageQuotaTicker.C
+	}
+
 	for {
 		select {
 		case <-flushTicker.C:
 			i.sweepAllInstances(false)
 
+		case <-tenantDeletionC:
+			i.updateDeletedTenants(ctx)
+
+		case <-storageQuotaC:
+			i.updateOverQuotaTenants(ctx)
+
 		case <-ctx.Done():
 			return nil
 
@@ -249,6 +285,13 @@ func (i *Ingester) PushBytesV2(ctx context.Context, req *tempopb.PushBytesReques
 		return nil, err
 	}
 
+	if i.tenantDeleted(instanceID) {
+		return nil, status.Errorf(codes.FailedPrecondition, "tenant %s is marked for deletion", instanceID)
+	}
+	if i.tenantOverQuota(instanceID) {
+		return nil, status.Errorf(codes.FailedPrecondition, "tenant %s exceeded its storage quota", instanceID)
+	}
+
 	instance, err := i.getOrCreateInstance(instanceID)
 	if err != nil {
 		level.Warn(log.Logger).Log(err.Error())
@@ -258,6 +301,58 @@ func (i *Ingester) PushBytesV2(ctx context.Context, req *tempopb.PushBytesReques
 	return instance.PushBytesRequest(ctx, req), nil
 }
 
+// updateDeletedTenants reads the tenants marked for deletion from the backend
+func (i *Ingester) updateDeletedTenants(ctx context.Context) {
+	deletions, err := i.store.TenantDeletions(ctx)
+	if err != nil {
+		level.Error(log.Logger).Log("msg", "failed to read tenant deletions", "err", err)
+		return
+	}
+
+	deletedTenants := make(map[string]struct{}, len(deletions))
+	for _, d := range deletions {
+		deletedTenants[d.TenantID] = struct{}{}
+	}
+
+	i.deletedTenantsMtx.Lock()
+	i.deletedTenants = deletedTenants
+	i.deletedTenantsMtx.Unlock()
+}
+
+func (i *Ingester) tenantDeleted(instanceID string) bool {
+	i.deletedTenantsMtx.RLock()
+	defer i.deletedTenantsMtx.RUnlock()
+
+	_, ok := i.deletedTenants[instanceID]
+	return ok
+}
+
+// updateOverQuotaTenants reads the tenants that exceed their storage quota from the backend
+func (i *Ingester) updateOverQuotaTenants(ctx context.Context) {
+	quotas, err := i.store.StorageQuotasExceeded(ctx)
+	if err != nil {
+		level.Error(log.Logger).Log("msg", "failed to read storage quotas", "err", err)
+		return
+	}
+
+	overQuotaTenants := make(map[string]struct{}, len(quotas))
+	for _, q := range quotas {
+		overQuotaTenants[q.TenantID] = struct{}{}
+	}
+
+	i.overQuotaTenantsMtx.Lock()
+	i.overQuotaTenants = overQuotaTenants
+	i.overQuotaTenantsMtx.Unlock()
+}
+
+func (i *Ingester) tenantOverQuota(instanceID string) bool {
+	i.overQuotaTenantsMtx.RLock()
+	defer i.overQuotaTenantsMtx.RUnlock()
+
+	_, ok := i.overQuotaTenants[instanceID]
+	return ok
+}
+
 // FindTraceByID implements tempopb.Querier.f
 func (i *Ingester) FindTraceByID(ctx context.Context, req *tempopb.TraceByIDRequest) (*tempopb.TraceByIDResponse, error) {
 	if !validation.ValidTraceID(req.TraceID) {
@@ -289,6 +384,32 @@ func (i *Ingester) FindTraceByID(ctx context.Context, req *tempopb.TraceByIDRequ
 	}, nil
 }
 
+// FindTraceByIDStream implements tempopb.Querier. The trace is sent in chunks of batches so traces larger than the
+// grpc max message size can be returned.
+func (i *Ingester) FindTraceByIDStream(req *tempopb.TraceByIDRequest, srv tempopb.Querier_FindTraceByIDStreamServer) error {
+	resp, err := i.FindTraceByID(srv.Context(), req)
+	if err != nil {
+		return err
+	}
+
+	chunks := trace.Split(resp.Trace, i.cfg.TraceByIDChunkSizeBytes)
+	for _, chunk := range chunks {
+		err = srv.Send(&tempopb.TraceByIDResponse{
+			Trace: chunk,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Addr returns the address the ingester is registered with in the ring
+func (i *Ingester) Addr() string {
+	return i.lifecycler.Addr
+}
+
 func (i *Ingester) CheckReady(ctx context.Context) error {
 	if err := i.lifecycler.CheckReady(ctx); err != nil {
 		return fmt.Errorf("ingester check ready failed: %w", err)
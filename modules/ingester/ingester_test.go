@@ -5,6 +5,7 @@ import (
 	"crypto/rand"
 	"flag"
 	"os"
+	"path"
 	"testing"
 	"time"
 
@@ -17,6 +18,9 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	"github.com/grafana/tempo/modules/overrides"
 	"github.com/grafana/tempo/modules/storage"
@@ -125,6 +129,57 @@ func TestFullTraceReturned(t *testing.T) {
 	require.True(t, proto.Equal(testTrace, foundTrace.Trace))
 }
 
+func TestFindTraceByIDStream(t *testing.T) {
+	ctx := user.InjectOrgID(context.Background(), "test")
+	ingester, traces, traceIDs := defaultIngester(t, t.TempDir())
+
+	// split every trace into multiple chunks
+	ingester.cfg.TraceByIDChunkSizeBytes = 100
+
+	for pos, traceID := range traceIDs {
+		srv := &mockFindTraceByIDStreamServer{ctx: ctx}
+		err := ingester.FindTraceByIDStream(&tempopb.TraceByIDRequest{TraceID: traceID}, srv)
+		require.NoError(t, err)
+		require.Greater(t, len(srv.responses), 1)
+
+		var expected, actual []*v1.Span
+		for _, b := range traces[pos].Batches {
+			for _, ss := range b.ScopeSpans {
+				expected = append(expected, ss.Spans...)
+			}
+		}
+		for _, resp := range srv.responses {
+			for _, b := range resp.Trace.Batches {
+				for _, ss := range b.ScopeSpans {
+					actual = append(actual, ss.Spans...)
+				}
+			}
+		}
+		require.Equal(t, expected, actual)
+	}
+
+	// nothing is sent for a trace that doesn't exist
+	srv := &mockFindTraceByIDStreamServer{ctx: ctx}
+	err := ingester.FindTraceByIDStream(&tempopb.TraceByIDRequest{TraceID: test.ValidTraceID(nil)}, srv)
+	require.NoError(t, err)
+	require.Empty(t, srv.responses)
+}
+
+type mockFindTraceByIDStreamServer struct {
+	grpc.ServerStream
+	ctx       context.Context
+	responses []*tempopb.TraceByIDResponse
+}
+
+func (m *mockFindTraceByIDStreamServer) Context() context.Context {
+	return m.ctx
+}
+
+func (m *mockFindTraceByIDStreamServer) Send(resp *tempopb.TraceByIDResponse) error {
+	m.responses = append(m.responses, resp)
+	return nil
+}
+
 func TestWal(t *testing.T) {
 	tmpDir := t.TempDir()
 
@@ -327,6 +382,57 @@ func TestIngesterStartingReadOnly(t *testing.T) {
 	require.ErrorIs(t, err, ErrStarting)
 }
 
+func TestIngesterRejectsDeletedTenants(t *testing.T) {
+	ctx := user.InjectOrgID(context.Background(), "test")
+	ingester := defaultIngesterModule(t, t.TempDir())
+
+	id := test.ValidTraceID(nil)
+	batch := test.MakeBatch(10, id)
+	_, err := ingester.PushBytesV2(ctx, makePushBytesRequest(id, batch))
+	require.NoError(t, err)
+
+	_, err = ingester.store.MarkTenantForDeletion(ctx, "test")
+	require.NoError(t, err)
+	ingester.updateDeletedTenants(ctx)
+
+	_, err = ingester.PushBytesV2(ctx, makePushBytesRequest(id, batch))
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	// other tenants are accepted
+	_, err = ingester.PushBytesV2(user.InjectOrgID(ctx, "other"), makePushBytesRequest(id, batch))
+	require.NoError(t, err)
+}
+
+func TestIngesterRejectsTenantsOverStorageQuota(t *testing.T) {
+	tmpDir := t.TempDir()
+	ctx := user.InjectOrgID(context.Background(), "test")
+	ingester := defaultIngesterModule(t, tmpDir)
+
+	id := test.ValidTraceID(nil)
+	batch := test.MakeBatch(10, id)
+	_, err := ingester.PushBytesV2(ctx, makePushBytesRequest(id, batch))
+	require.NoError(t, err)
+
+	// the compactors mark the tenants over their storage quota
+	quotaDir := path.Join(tmpDir, backend.StorageQuotasKeyPath, "test")
+	require.NoError(t, os.MkdirAll(quotaDir, 0o755))
+	require.NoError(t, os.WriteFile(path.Join(quotaDir, "quota.json"), []byte(`{"tenantID":"test","blocks":2,"maxBlocks":1}`), 0o644))
+	ingester.updateOverQuotaTenants(ctx)
+
+	_, err = ingester.PushBytesV2(ctx, makePushBytesRequest(id, batch))
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	// other tenants are accepted
+	_, err = ingester.PushBytesV2(user.InjectOrgID(ctx, "other"), makePushBytesRequest(id, batch))
+	require.NoError(t, err)
+
+	// traces are accepted again once the mark is removed
+	require.NoError(t, os.RemoveAll(quotaDir))
+	ingester.updateOverQuotaTenants(ctx)
+	_, err = ingester.PushBytesV2(ctx, makePushBytesRequest(id, batch))
+	require.NoError(t, err)
+}
+
 func TestFlush(t *testing.T) {
 	tmpDir := t.TempDir()
 
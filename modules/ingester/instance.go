@@ -39,15 +39,50 @@ var (
 	errMaxLiveTraces = errors.New(overrides.ErrorPrefixLiveTracesExceeded)
 )
 
-func newTraceTooLargeError(traceID common.ID, instanceID string, maxBytes, reqSize int) error {
+// limitError is returned for the traces discarded by a limit. It carries the state of the limit so the distributor can
+// report it to the client.
+type limitError struct {
+	err     error
+	details *tempopb.PushLimitDetails
+}
+
+func (e *limitError) Error() string { return e.err.Error() }
+func (e *limitError) Unwrap() error { return e.err }
+
+func newTraceTooLargeError(traceID common.ID, instanceID string, maxBytes, traceSize, reqSize int) error {
 	level.Warn(log.Logger).Log("msg", fmt.Sprintf("%s: max size of trace (%d) exceeded while adding %d bytes to trace %s for tenant %s",
 		overrides.ErrorPrefixTraceTooLarge, maxBytes, reqSize, hex.EncodeToString(traceID), instanceID))
-	return errTraceTooLarge
+	return &limitError{
+		err: errTraceTooLarge,
+		details: &tempopb.PushLimitDetails{
+			Reason:     tempopb.PushErrorReason_TRACE_TOO_LARGE,
+			Limit:      "max_bytes_per_trace",
+			Configured: int64(maxBytes),
+			Effective:  int64(maxBytes),
+			Measured:   int64(traceSize + reqSize),
+		},
+	}
 }
 
-func newMaxLiveTracesError(instanceID string, limit string) error {
+func newMaxLiveTracesError(instanceID string, limit string, details *tempopb.PushLimitDetails) error {
 	level.Warn(log.Logger).Log("msg", fmt.Sprintf("%s: max live traces exceeded for tenant %s: %v", overrides.ErrorPrefixLiveTracesExceeded, instanceID, limit))
-	return errMaxLiveTraces
+	return &limitError{err: errMaxLiveTraces, details: details}
+}
+
+// addLimitDetails adds the state of the limit that discarded a trace. Only the first trace discarded for each reason is
+// reported.
+func addLimitDetails(limitDetails []*tempopb.PushLimitDetails, pushError error) []*tempopb.PushLimitDetails {
+	var limitErr *limitError
+	if !errors.As(pushError, &limitErr) {
+		return limitDetails
+	}
+
+	for _, d := range limitDetails {
+		if d.Reason == limitErr.details.Reason {
+			return limitDetails
+		}
+	}
+	return append(limitDetails, limitErr.details)
 }
 
 const (
@@ -90,6 +125,9 @@ type instance struct {
 
 	headBlockMtx sync.RWMutex
 	headBlock    common.WALBlock
+	// head blocks of the traces that request a longer retention with the tempo.retention attribute by retention. The
+	// traces are kept apart so the retention of the other traces isn't extended.
+	retentionHeadBlocks map[time.Duration]*retentionHeadBlock
 
 	blocksMtx        sync.RWMutex
 	completingBlocks []common.WALBlock
@@ -115,11 +153,18 @@ type instance struct {
 	autocompleteFilteringEnabled bool
 }
 
+type retentionHeadBlock struct {
+	common.WALBlock
+	created time.Time
+}
+
 func newInstance(instanceID string, limiter *Limiter, overrides ingesterOverrides, writer tempodb.Writer, l *local.Backend, autocompleteFiltering bool, dedicatedColumns backend.DedicatedColumns) (*instance, error) {
 	i := &instance{
 		traces:     map[uint32]*liveTrace{},
 		traceSizes: map[uint32]uint32{},
 
+		retentionHeadBlocks: map[time.Duration]*retentionHeadBlock{},
+
 		instanceID:         instanceID,
 		tracesCreatedTotal: metricTracesCreatedTotal.WithLabelValues(instanceID),
 		bytesReceivedTotal: metricBytesReceivedTotal,
@@ -150,6 +195,7 @@ func (i *instance) PushBytesRequest(ctx context.Context, req *tempopb.PushBytesR
 	for j := range req.Traces {
 		err := i.PushBytes(ctx, req.Ids[j].Slice, req.Traces[j].Slice)
 		pr.ErrorsByTrace = i.addTraceError(pr.ErrorsByTrace, err, len(req.Traces), j)
+		pr.LimitDetails = addLimitDetails(pr.LimitDetails, err)
 	}
 
 	return pr
@@ -196,9 +242,10 @@ func (i *instance) PushBytes(ctx context.Context, id, traceBytes []byte) error {
 	}
 
 	// check for max traces before grabbing the lock to better load shed
-	err := i.limiter.AssertMaxTracesPerUser(i.instanceID, int(i.traceCount.Load()))
+	liveTraces := int(i.traceCount.Load())
+	err := i.limiter.AssertMaxTracesPerUser(i.instanceID, liveTraces)
 	if err != nil {
-		return newMaxLiveTracesError(i.instanceID, err.Error())
+		return newMaxLiveTracesError(i.instanceID, err.Error(), i.limiter.maxTracesDetails(i.instanceID, liveTraces))
 	}
 
 	return i.push(ctx, id, traceBytes)
@@ -215,7 +262,7 @@ func (i *instance) push(ctx context.Context, id, traceBytes []byte) error {
 		prevSize := int(i.traceSizes[tkn])
 		reqSize := len(traceBytes)
 		if prevSize+reqSize > maxBytes {
-			return newTraceTooLargeError(id, i.instanceID, maxBytes, reqSize)
+			return newTraceTooLargeError(id, i.instanceID, maxBytes, prevSize, reqSize)
 		}
 	}
 
@@ -271,7 +318,12 @@ func (i *instance) CutCompleteTraces(cutoff time.Duration, immediate bool) error
 
 	i.headBlockMtx.Lock()
 	defer i.headBlockMtx.Unlock()
-	return i.headBlock.Flush()
+	for _, b := range i.headBlocks() {
+		if err := b.Flush(); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // CutBlockIfReady cuts a completingBlock from the HeadBlock if ready.
@@ -317,6 +369,36 @@ func (i *instance) CutBlockIfReady(maxBlockLifetime time.Duration, maxBlockBytes
 	return uuid.Nil, nil
 }
 
+// CutRetentionBlocksIfReady cuts completingBlocks from the head blocks of the traces that request a longer retention
+// if they are ready. Returns the IDs of the blocks that were cut.
+func (i *instance) CutRetentionBlocksIfReady(maxBlockLifetime time.Duration, maxBlockBytes uint64, immediate bool) ([]uuid.UUID, error) {
+	i.headBlockMtx.Lock()
+	defer i.headBlockMtx.Unlock()
+
+	now := time.Now()
+	var blockIDs []uuid.UUID
+	for retention, b := range i.retentionHeadBlocks {
+		if !b.created.Add(maxBlockLifetime).Before(now) && b.DataLength() < maxBlockBytes && !immediate {
+			continue
+		}
+
+		err := b.Flush()
+		if err != nil {
+			return blockIDs, fmt.Errorf("failed to flush retention head block: %w", err)
+		}
+
+		// the same lock order as CutBlockIfReady
+		i.blocksMtx.Lock()
+		i.completingBlocks = append(i.completingBlocks, b.WALBlock)
+		i.blocksMtx.Unlock()
+
+		delete(i.retentionHeadBlocks, retention)
+		blockIDs = append(blockIDs, b.BlockMeta().BlockID)
+	}
+
+	return blockIDs, nil
+}
+
 // CompleteBlock moves a completingBlock to a completeBlock. The new completeBlock has the same ID.
 func (i *instance) CompleteBlock(blockID uuid.UUID) error {
 	i.blocksMtx.Lock()
@@ -436,24 +518,28 @@ func (i *instance) FindTraceByID(ctx context.Context, id []byte) (*tempopb.Trace
 		return nil, err
 	}
 
-	// headBlock
+	// headBlocks
 	i.headBlockMtx.RLock()
-	tr, err := i.headBlock.FindTraceByID(ctx, id, searchOpts)
-	i.headBlockMtx.RUnlock()
-	if err != nil {
-		return nil, fmt.Errorf("headBlock.FindTraceByID failed: %w", err)
-	}
-	_, err = combiner.Consume(tr)
-	if err != nil {
-		return nil, err
+	for _, b := range i.headBlocks() {
+		tr, err := b.FindTraceByID(ctx, id, searchOpts)
+		if err != nil {
+			i.headBlockMtx.RUnlock()
+			return nil, fmt.Errorf("headBlock.FindTraceByID failed: %w", err)
+		}
+		_, err = combiner.Consume(tr)
+		if err != nil {
+			i.headBlockMtx.RUnlock()
+			return nil, err
+		}
 	}
+	i.headBlockMtx.RUnlock()
 
 	i.blocksMtx.RLock()
 	defer i.blocksMtx.RUnlock()
 
 	// completingBlock
 	for _, c := range i.completingBlocks {
-		tr, err = c.FindTraceByID(ctx, id, searchOpts)
+		tr, err := c.FindTraceByID(ctx, id, searchOpts)
 		if err != nil {
 			return nil, fmt.Errorf("completingBlock.FindTraceByID failed: %w", err)
 		}
@@ -537,6 +623,40 @@ func (i *instance) resetHeadBlock() error {
 	return nil
 }
 
+// headBlocks returns the head block and the head blocks of the traces that request a longer retention. It should be
+// called under lock.
+func (i *instance) headBlocks() []common.WALBlock {
+	blocks := make([]common.WALBlock, 0, 1+len(i.retentionHeadBlocks))
+	if i.headBlock != nil {
+		blocks = append(blocks, i.headBlock)
+	}
+	for _, b := range i.retentionHeadBlocks {
+		blocks = append(blocks, b.WALBlock)
+	}
+	return blocks
+}
+
+// retentionHeadBlock returns the head block of the traces that request the retention. It should be called under lock.
+func (i *instance) retentionHeadBlock(retention time.Duration) (common.WALBlock, error) {
+	if b, ok := i.retentionHeadBlocks[retention]; ok {
+		return b.WALBlock, nil
+	}
+
+	meta := &backend.BlockMeta{
+		BlockID:          uuid.New(),
+		TenantID:         i.instanceID,
+		DedicatedColumns: i.getDedicatedColumns(),
+		Retention:        retention,
+	}
+	b, err := i.writer.WAL().NewBlock(meta, model.CurrentEncoding)
+	if err != nil {
+		return nil, err
+	}
+
+	i.retentionHeadBlocks[retention] = &retentionHeadBlock{WALBlock: b, created: time.Now()}
+	return b, nil
+}
+
 func (i *instance) getDedicatedColumns() backend.DedicatedColumns {
 	if cols := i.overrides.DedicatedColumns(i.instanceID); cols != nil {
 		err := cols.Validate()
@@ -572,11 +692,24 @@ func (i *instance) tracesToCut(cutoff time.Duration, immediate bool) []*liveTrac
 }
 
 func (i *instance) writeTraceToHeadBlock(id common.ID, b []byte, start, end uint32) error {
+	retention, err := traceRetention(b)
+	if err != nil {
+		return err
+	}
+
 	i.headBlockMtx.Lock()
 	defer i.headBlockMtx.Unlock()
 
+	headBlock := i.headBlock
+	if retention > 0 {
+		headBlock, err = i.retentionHeadBlock(retention)
+		if err != nil {
+			return err
+		}
+	}
+
 	i.tracesCreatedTotal.Inc()
-	err := i.headBlock.Append(id, b, start, end)
+	err = headBlock.Append(id, b, start, end)
 	if err != nil {
 		return err
 	}
@@ -584,6 +717,20 @@ func (i *instance) writeTraceToHeadBlock(id common.ID, b []byte, start, end uint
 	return nil
 }
 
+// traceRetention returns the retention the trace requests with the tempo.retention attribute
+func traceRetention(b []byte) (time.Duration, error) {
+	// most traces don't request a retention so only the traces that contain the attribute are decoded
+	if !bytes.Contains(b, []byte(common.RetentionAttribute)) {
+		return 0, nil
+	}
+
+	tr, err := model.MustNewObjectDecoder(model.CurrentEncoding).PrepareForRead(b)
+	if err != nil {
+		return 0, err
+	}
+	return common.TraceRetention(tr), nil
+}
+
 func (i *instance) rediscoverLocalBlocks(ctx context.Context) ([]*LocalBlock, error) {
 	ids, _, err := i.localReader.Blocks(ctx, i.instanceID)
 	if err != nil {
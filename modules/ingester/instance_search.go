@@ -116,8 +116,10 @@ func (i *instance) Search(ctx context.Context, req *tempopb.SearchRequest) (*tem
 	// that eventually a deadlock will occur.
 	i.headBlockMtx.RLock()
 	span.LogFields(ot_log.String("msg", "acquired headblock mtx"))
-	if includeBlock(i.headBlock.BlockMeta(), req) {
-		search(i.headBlock.BlockMeta().BlockID, i.headBlock, "headBlock")
+	for _, b := range i.headBlocks() {
+		if includeBlock(b.BlockMeta(), req) {
+			search(b.BlockMeta().BlockID, b, "headBlock")
+		}
 	}
 	i.headBlockMtx.RUnlock()
 	if err := anyErr.Load(); err != nil {
@@ -219,11 +221,14 @@ func (i *instance) SearchTags(ctx context.Context, scope string) (*tempopb.Searc
 
 	i.headBlockMtx.RLock()
 	span.LogFields(ot_log.String("msg", "acquired headblock mtx"))
-	err = search(ctx, i.headBlock, distinctValues, "headBlock")
-	i.headBlockMtx.RUnlock()
-	if err != nil {
-		return nil, fmt.Errorf("unexpected error searching head block (%s): %w", i.headBlock.BlockMeta().BlockID, err)
+	for _, b := range i.headBlocks() {
+		err = search(ctx, b, distinctValues, "headBlock")
+		if err != nil {
+			i.headBlockMtx.RUnlock()
+			return nil, fmt.Errorf("unexpected error searching head block (%s): %w", b.BlockMeta().BlockID, err)
+		}
 	}
+	i.headBlockMtx.RUnlock()
 
 	i.blocksMtx.RLock()
 	defer i.blocksMtx.RUnlock()
@@ -340,11 +345,14 @@ func (i *instance) SearchTagValues(ctx context.Context, tagName string) (*tempop
 	}
 
 	i.headBlockMtx.RLock()
-	err = search(i.headBlock, distinctValues)
-	i.headBlockMtx.RUnlock()
-	if err != nil {
-		return nil, fmt.Errorf("unexpected error searching head block (%s): %w", i.headBlock.BlockMeta().BlockID, err)
+	for _, b := range i.headBlocks() {
+		err = search(b, distinctValues)
+		if err != nil {
+			i.headBlockMtx.RUnlock()
+			return nil, fmt.Errorf("unexpected error searching head block (%s): %w", b.BlockMeta().BlockID, err)
+		}
 	}
+	i.headBlockMtx.RUnlock()
 
 	i.blocksMtx.RLock()
 	defer i.blocksMtx.RUnlock()
@@ -366,6 +374,7 @@ func (i *instance) SearchTagValues(ctx context.Context, tagName string) (*tempop
 
 	return &tempopb.SearchTagValuesResponse{
 		TagValues: distinctValues.Strings(),
+		Partial:   distinctValues.Exceeded(),
 	}, nil
 }
 
@@ -428,18 +437,19 @@ func (i *instance) SearchTagValuesV2(ctx context.Context, req *tempopb.SearchTag
 	// that eventually a deadlock will occur.
 	i.headBlockMtx.RLock()
 	span.LogFields(ot_log.String("msg", "acquired headblock mtx"))
-	if i.headBlock != nil {
-		wg.Add(1)
-		go func() {
-			span, ctx := opentracing.StartSpanFromContext(ctx, "instance.SearchTagValuesV2.headBlock")
-			defer span.Finish()
-			defer i.headBlockMtx.RUnlock()
-			defer wg.Done()
-			if err := searchBlock(ctx, i.headBlock); err != nil {
-				anyErr.Store(fmt.Errorf("unexpected error searching head block (%s): %w", i.headBlock.BlockMeta().BlockID, err))
+	wg.Add(1)
+	go func() {
+		span, ctx := opentracing.StartSpanFromContext(ctx, "instance.SearchTagValuesV2.headBlock")
+		defer span.Finish()
+		defer i.headBlockMtx.RUnlock()
+		defer wg.Done()
+		for _, b := range i.headBlocks() {
+			if err := searchBlock(ctx, b); err != nil {
+				anyErr.Store(fmt.Errorf("unexpected error searching head block (%s): %w", b.BlockMeta().BlockID, err))
+				return
 			}
-		}()
-	}
+		}
+	}()
 
 	i.blocksMtx.RLock()
 	defer i.blocksMtx.RUnlock()
@@ -481,7 +491,9 @@ func (i *instance) SearchTagValuesV2(ctx context.Context, req *tempopb.SearchTag
 		level.Warn(log.Logger).Log("msg", "size of tag values in instance exceeded limit, reduce cardinality or size of tags", "tag", req.TagName, "userID", userID, "limit", limit, "total", valueCollector.TotalDataSize())
 	}
 
-	resp := &tempopb.SearchTagValuesV2Response{}
+	resp := &tempopb.SearchTagValuesV2Response{
+		Partial: valueCollector.Exceeded(),
+	}
 
 	for _, v := range valueCollector.Values() {
 		v2 := v
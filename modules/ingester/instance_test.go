@@ -19,8 +19,10 @@ import (
 	"github.com/grafana/tempo/pkg/model"
 	"github.com/grafana/tempo/pkg/model/trace"
 	"github.com/grafana/tempo/pkg/tempopb"
+	v1_common "github.com/grafana/tempo/pkg/tempopb/common/v1"
 	v1_trace "github.com/grafana/tempo/pkg/tempopb/trace/v1"
 	"github.com/grafana/tempo/pkg/util/test"
+	"github.com/grafana/tempo/tempodb/encoding/common"
 )
 
 const testTenantID = "fake"
@@ -321,11 +323,22 @@ func TestInstanceLimits(t *testing.T) {
 					require.True(t, errored)
 					require.Zero(t, maxLiveCount, "push %d failed: %w", j, err)
 					require.NotZero(t, traceTooLargeCount, "push %d failed: %w", j, err)
+					require.Len(t, response.LimitDetails, 1)
+					require.Equal(t, "max_bytes_per_trace", response.LimitDetails[0].Limit)
+					require.Equal(t, int64(1000), response.LimitDetails[0].Configured)
+					require.Greater(t, response.LimitDetails[0].Measured, int64(1000))
 				} else if push.expectsError && push.errorReason == maxLiveTraces {
 					errored, maxLiveCount, traceTooLargeCount := CheckPushBytesError(response)
 					require.True(t, errored)
 					require.NotZero(t, maxLiveCount, "push %d failed: %w", j, err)
 					require.Zero(t, traceTooLargeCount, "push %d failed: %w", j, err)
+					require.Equal(t, []*tempopb.PushLimitDetails{{
+						Reason:     tempopb.PushErrorReason_MAX_LIVE_TRACES,
+						Limit:      "max_traces_per_user",
+						Configured: 4,
+						Effective:  4,
+						Measured:   4,
+					}}, response.LimitDetails)
 				} else {
 					errored, _, _ := CheckPushBytesError(response)
 					require.False(t, errored, "push %d failed: %w", j, err)
@@ -502,6 +515,62 @@ func TestInstanceCutBlockIfReady(t *testing.T) {
 	}
 }
 
+func TestInstanceCutsBlocksPerRetention(t *testing.T) {
+	i, _ := defaultInstance(t)
+	dec := model.MustNewSegmentDecoder(model.CurrentEncoding)
+
+	retentions := []string{"", "", "30d", "30d", "90d"}
+	traces := make([]*tempopb.Trace, 0, len(retentions))
+	ids := make([][]byte, 0, len(retentions))
+	for _, retention := range retentions {
+		id := test.ValidTraceID(nil)
+		tr := test.MakeTrace(1, id)
+		if retention != "" {
+			// the attribute is read back first from the blocks
+			tr.Batches[0].Resource.Attributes = append([]*v1_common.KeyValue{{
+				Key:   common.RetentionAttribute,
+				Value: &v1_common.AnyValue{Value: &v1_common.AnyValue_StringValue{StringValue: retention}},
+			}}, tr.Batches[0].Resource.Attributes...)
+		}
+		trace.SortTrace(tr)
+
+		b, err := dec.PrepareForWrite(tr, 0, 0)
+		require.NoError(t, err)
+		require.NoError(t, i.PushBytes(context.Background(), id, b))
+
+		traces = append(traces, tr)
+		ids = append(ids, id)
+	}
+
+	require.NoError(t, i.CutCompleteTraces(0, true))
+	require.Len(t, i.retentionHeadBlocks, 2)
+	require.Equal(t, 2, i.headBlock.BlockMeta().TotalObjects)
+	queryAll(t, i, ids, traces)
+
+	// the traces of every retention are cut to a block of their own
+	blockIDs, err := i.CutRetentionBlocksIfReady(time.Hour, 100000, false)
+	require.NoError(t, err)
+	require.Empty(t, blockIDs)
+
+	blockIDs, err = i.CutRetentionBlocksIfReady(time.Hour, 100000, true)
+	require.NoError(t, err)
+	require.Len(t, blockIDs, 2)
+	require.Empty(t, i.retentionHeadBlocks)
+
+	blockID, err := i.CutBlockIfReady(time.Hour, 100000, true)
+	require.NoError(t, err)
+	blockIDs = append(blockIDs, blockID)
+
+	retentionsByBlock := map[time.Duration]int{}
+	for _, blockID := range blockIDs {
+		require.NoError(t, i.CompleteBlock(blockID))
+		meta := i.GetBlockToBeFlushed(blockID).BlockMeta()
+		retentionsByBlock[meta.Retention] = meta.TotalObjects
+	}
+	require.Equal(t, map[time.Duration]int{0: 2, 30 * 24 * time.Hour: 2, 90 * 24 * time.Hour: 1}, retentionsByBlock)
+	queryAll(t, i, ids, traces)
+}
+
 func TestInstanceMetrics(t *testing.T) {
 	i, _ := defaultInstance(t)
 	cutAndVerify := func(v int) {
@@ -5,6 +5,7 @@ import (
 	"math"
 
 	"github.com/grafana/tempo/modules/overrides"
+	"github.com/grafana/tempo/pkg/tempopb"
 )
 
 const (
@@ -48,6 +49,27 @@ func (l *Limiter) AssertMaxTracesPerUser(userID string, traces int) error {
 	return fmt.Errorf(errMaxTracesPerUserLimitExceeded, localLimit, globalLimit, actualLimit)
 }
 
+// maxTracesDetails returns the state of the live traces limit of the tenant. The limit is the local or the global limit,
+// whichever is lower on this ingester.
+func (l *Limiter) maxTracesDetails(userID string, traces int) *tempopb.PushLimitDetails {
+	details := &tempopb.PushLimitDetails{
+		Reason:    tempopb.PushErrorReason_MAX_LIVE_TRACES,
+		Limit:     "max_traces_per_user",
+		Effective: int64(l.maxTracesPerUser(userID)),
+		Measured:  int64(traces),
+	}
+
+	localLimit := l.limits.MaxLocalTracesPerUser(userID)
+	if localLimit == 0 || int64(localLimit) != details.Effective {
+		details.Limit = "max_global_traces_per_user"
+		details.Configured = int64(l.limits.MaxGlobalTracesPerUser(userID))
+		return details
+	}
+
+	details.Configured = int64(localLimit)
+	return details
+}
+
 func (l *Limiter) maxTracesPerUser(userID string) int {
 	localLimit := l.limits.MaxLocalTracesPerUser(userID)
 
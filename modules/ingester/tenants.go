@@ -0,0 +1,61 @@
+package ingester
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/grafana/tempo/pkg/util"
+)
+
+// tenantStats are the traces and the blocks of a tenant held by this ingester
+type tenantStats struct {
+	TenantID       string `json:"tenantID"`
+	LiveTraces     int    `json:"liveTraces"`
+	WALBlocks      int    `json:"walBlocks"`
+	CompleteBlocks int    `json:"completeBlocks"`
+	Bytes          uint64 `json:"bytes"`
+}
+
+type tenantsResponse struct {
+	Tenants []*tenantStats `json:"tenants"`
+}
+
+// TenantsHandler lists the tenants with traces or blocks in this ingester sorted by ID
+func (i *Ingester) TenantsHandler(w http.ResponseWriter, _ *http.Request) {
+	instances := i.getInstances()
+
+	resp := &tenantsResponse{Tenants: make([]*tenantStats, 0, len(instances))}
+	for _, inst := range instances {
+		resp.Tenants = append(resp.Tenants, inst.stats())
+	}
+	sort.Slice(resp.Tenants, func(i, j int) bool { return resp.Tenants[i].TenantID < resp.Tenants[j].TenantID })
+
+	util.WriteJSONResponse(w, resp)
+}
+
+func (i *instance) stats() *tenantStats {
+	s := &tenantStats{
+		TenantID:   i.instanceID,
+		LiveTraces: int(i.traceCount.Load()),
+	}
+
+	i.headBlockMtx.RLock()
+	for _, b := range i.headBlocks() {
+		s.WALBlocks++
+		s.Bytes += b.DataLength()
+	}
+	i.headBlockMtx.RUnlock()
+
+	i.blocksMtx.RLock()
+	defer i.blocksMtx.RUnlock()
+
+	for _, b := range i.completingBlocks {
+		s.WALBlocks++
+		s.Bytes += b.DataLength()
+	}
+	for _, b := range i.completeBlocks {
+		s.CompleteBlocks++
+		s.Bytes += b.BlockMeta().Size
+	}
+	return s
+}
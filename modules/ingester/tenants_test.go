@@ -0,0 +1,48 @@
+package ingester
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/grafana/dskit/user"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/pkg/util/test"
+)
+
+func TestTenantsHandler(t *testing.T) {
+	ingester := defaultIngesterModule(t, t.TempDir())
+
+	for _, tenant := range []string{"b", "a"} {
+		id := test.ValidTraceID(nil)
+		_, err := ingester.PushBytesV2(user.InjectOrgID(context.Background(), tenant), makePushBytesRequest(id, test.MakeBatch(10, id)))
+		require.NoError(t, err)
+	}
+
+	get := func() *tenantsResponse {
+		rec := httptest.NewRecorder()
+		ingester.TenantsHandler(rec, httptest.NewRequest(http.MethodGet, "/ingester/tenants", nil))
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		resp := &tenantsResponse{}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), resp))
+		return resp
+	}
+
+	resp := get()
+	require.Len(t, resp.Tenants, 2)
+	require.Equal(t, "a", resp.Tenants[0].TenantID)
+	require.Equal(t, "b", resp.Tenants[1].TenantID)
+	require.Equal(t, 1, resp.Tenants[0].LiveTraces)
+	require.Equal(t, 1, resp.Tenants[0].WALBlocks)
+	require.Equal(t, 0, resp.Tenants[0].CompleteBlocks)
+
+	// live traces are moved to the head block
+	ingester.sweepAllInstances(true)
+	resp = get()
+	require.Equal(t, 0, resp.Tenants[0].LiveTraces)
+	require.NotZero(t, resp.Tenants[0].Bytes)
+}
@@ -36,7 +36,7 @@ func (t *liveTrace) Push(_ context.Context, instanceID string, trace []byte) err
 	if t.maxBytes != 0 {
 		reqSize := len(trace)
 		if t.currentBytes+reqSize > t.maxBytes {
-			return newTraceTooLargeError(t.traceID, instanceID, t.maxBytes, reqSize)
+			return newTraceTooLargeError(t.traceID, instanceID, t.maxBytes, t.currentBytes, reqSize)
 		}
 
 		t.currentBytes += reqSize
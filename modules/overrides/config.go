@@ -37,6 +37,11 @@ const (
 	// ErrorPrefixRateLimited is used to flag batches that have exceeded the spans/second of the tenant
 	ErrorPrefixRateLimited = "RATE_LIMITED"
 
+	// StorageQuotaActionDelete deletes the oldest blocks of a tenant that exceeds its storage quota
+	StorageQuotaActionDelete = "delete"
+	// StorageQuotaActionReject rejects the traces of a tenant that exceeds its storage quota
+	StorageQuotaActionReject = "reject"
+
 	// metrics
 	MetricMaxLocalTracesPerUser           = "max_local_traces_per_user"
 	MetricMaxGlobalTracesPerUser          = "max_global_traces_per_user"
@@ -47,6 +52,10 @@ const (
 	MetricIngestionBurstSizeBytes         = "ingestion_burst_size_bytes"
 	MetricBlockRetention                  = "block_retention"
 	MetricCompactionWindow                = "compaction_window"
+	MetricMaxStoredBytes                  = "max_stored_bytes"
+	MetricMaxStoredBlocks                 = "max_stored_blocks"
+	MetricMaxQueriesPerSecond             = "max_queries_per_second"
+	MetricQueryBurstSize                  = "query_burst_size"
 	MetricMetricsGeneratorMaxActiveSeries = "metrics_generator_max_active_series"
 	MetricsGeneratorDryRunEnabled         = "metrics_generator_dry_run_enabled"
 )
@@ -149,14 +158,35 @@ type ReadOverrides struct {
 	// QueryFrontend enforced overrides
 	MaxSearchDuration  model.Duration `yaml:"max_search_duration,omitempty" json:"max_search_duration,omitempty"`
 	MaxMetricsDuration model.Duration `yaml:"max_metrics_duration,omitempty" json:"max_metrics_duration,omitempty"`
+	MaxQueryLookback   model.Duration `yaml:"max_query_lookback,omitempty" json:"max_query_lookback,omitempty"`
+
+	// MaxQueriesPerSecond and QueryBurstSize limit the rate of the queries of the tenant received by each query frontend
+	MaxQueriesPerSecond int `yaml:"max_queries_per_second,omitempty" json:"max_queries_per_second,omitempty"`
+	QueryBurstSize      int `yaml:"query_burst_size,omitempty" json:"query_burst_size,omitempty"`
 
 	UnsafeQueryHints bool `yaml:"unsafe_query_hints,omitempty" json:"unsafe_query_hints,omitempty"`
+
+	// DisallowTenantFederation excludes the tenant from multi-tenant queries. it's unset by default so tenants with
+	// their own overrides that don't mention it can still be queried with other tenants
+	DisallowTenantFederation bool `yaml:"disallow_tenant_federation,omitempty" json:"disallow_tenant_federation,omitempty"`
+
+	// Querier enforced overrides
+	QueryTimeout model.Duration `yaml:"query_timeout,omitempty" json:"query_timeout,omitempty"`
 }
 
 type CompactionOverrides struct {
 	// Compactor enforced overrides.
 	BlockRetention   model.Duration `yaml:"block_retention,omitempty" json:"block_retention,omitempty"`
 	CompactionWindow model.Duration `yaml:"compaction_window,omitempty" json:"compaction_window,omitempty"`
+
+	// pauses or de-prioritizes the compactions of a tenant, for example during incident recovery
+	CompactionDisabled bool `yaml:"compaction_disabled,omitempty" json:"compaction_disabled,omitempty"`
+	CompactionPriority int  `yaml:"compaction_priority,omitempty" json:"compaction_priority,omitempty"`
+
+	// storage quota of a tenant, enforced by the compactors. 0 disables the quota
+	MaxStoredBytes     int    `yaml:"max_stored_bytes,omitempty" json:"max_stored_bytes,omitempty"`
+	MaxStoredBlocks    int    `yaml:"max_stored_blocks,omitempty" json:"max_stored_blocks,omitempty"`
+	StorageQuotaAction string `yaml:"storage_quota_action,omitempty" json:"storage_quota_action,omitempty"`
 }
 
 type GlobalOverrides struct {
@@ -275,5 +305,9 @@ func (c *Config) Collect(ch chan<- prometheus.Metric) {
 	ch <- prometheus.MustNewConstMetric(metricLimitsDesc, prometheus.GaugeValue, float64(c.Defaults.Read.MaxBlocksPerTagValuesQuery), MetricMaxBlocksPerTagValuesQuery)
 	ch <- prometheus.MustNewConstMetric(metricLimitsDesc, prometheus.GaugeValue, float64(c.Defaults.Global.MaxBytesPerTrace), MetricMaxBytesPerTrace)
 	ch <- prometheus.MustNewConstMetric(metricLimitsDesc, prometheus.GaugeValue, float64(c.Defaults.Compaction.BlockRetention), MetricBlockRetention)
+	ch <- prometheus.MustNewConstMetric(metricLimitsDesc, prometheus.GaugeValue, float64(c.Defaults.Compaction.MaxStoredBytes), MetricMaxStoredBytes)
+	ch <- prometheus.MustNewConstMetric(metricLimitsDesc, prometheus.GaugeValue, float64(c.Defaults.Compaction.MaxStoredBlocks), MetricMaxStoredBlocks)
+	ch <- prometheus.MustNewConstMetric(metricLimitsDesc, prometheus.GaugeValue, float64(c.Defaults.Read.MaxQueriesPerSecond), MetricMaxQueriesPerSecond)
+	ch <- prometheus.MustNewConstMetric(metricLimitsDesc, prometheus.GaugeValue, float64(c.Defaults.Read.QueryBurstSize), MetricQueryBurstSize)
 	ch <- prometheus.MustNewConstMetric(metricLimitsDesc, prometheus.GaugeValue, float64(c.Defaults.MetricsGenerator.MaxActiveSeries), MetricMetricsGeneratorMaxActiveSeries)
 }
@@ -53,13 +53,23 @@ func (c *Overrides) toLegacy() LegacyOverrides {
 		MetricsGeneratorProcessorLocalBlocksCompleteBlockTimeout:                    c.MetricsGenerator.Processor.LocalBlocks.CompleteBlockTimeout,
 		MetricsGeneratorIngestionSlack:                                              c.MetricsGenerator.IngestionSlack,
 
-		BlockRetention:   c.Compaction.BlockRetention,
-		CompactionWindow: c.Compaction.CompactionWindow,
+		BlockRetention:     c.Compaction.BlockRetention,
+		CompactionWindow:   c.Compaction.CompactionWindow,
+		CompactionDisabled: c.Compaction.CompactionDisabled,
+		CompactionPriority: c.Compaction.CompactionPriority,
+		MaxStoredBytes:     c.Compaction.MaxStoredBytes,
+		MaxStoredBlocks:    c.Compaction.MaxStoredBlocks,
+		StorageQuotaAction: c.Compaction.StorageQuotaAction,
 
 		MaxBytesPerTagValuesQuery:  c.Read.MaxBytesPerTagValuesQuery,
 		MaxBlocksPerTagValuesQuery: c.Read.MaxBlocksPerTagValuesQuery,
 		MaxSearchDuration:          c.Read.MaxSearchDuration,
+		MaxQueryLookback:           c.Read.MaxQueryLookback,
+		MaxQueriesPerSecond:        c.Read.MaxQueriesPerSecond,
+		QueryBurstSize:             c.Read.QueryBurstSize,
 		UnsafeQueryHints:           c.Read.UnsafeQueryHints,
+		DisallowTenantFederation:   c.Read.DisallowTenantFederation,
+		QueryTimeout:               c.Read.QueryTimeout,
 
 		MaxBytesPerTrace: c.Global.MaxBytesPerTrace,
 
@@ -115,17 +125,30 @@ type LegacyOverrides struct {
 	MetricsGeneratorIngestionSlack                                              time.Duration                    `yaml:"metrics_generator_ingestion_time_range_slack" json:"metrics_generator_ingestion_time_range_slack"`
 
 	// Compactor enforced limits.
-	BlockRetention   model.Duration `yaml:"block_retention" json:"block_retention"`
-	CompactionWindow model.Duration `yaml:"compaction_window" json:"compaction_window"`
+	BlockRetention     model.Duration `yaml:"block_retention" json:"block_retention"`
+	CompactionWindow   model.Duration `yaml:"compaction_window" json:"compaction_window"`
+	CompactionDisabled bool           `yaml:"compaction_disabled" json:"compaction_disabled"`
+	CompactionPriority int            `yaml:"compaction_priority" json:"compaction_priority"`
+	MaxStoredBytes     int            `yaml:"max_stored_bytes" json:"max_stored_bytes"`
+	MaxStoredBlocks    int            `yaml:"max_stored_blocks" json:"max_stored_blocks"`
+	StorageQuotaAction string         `yaml:"storage_quota_action" json:"storage_quota_action"`
 
 	// Querier and Ingester enforced limits.
 	MaxBytesPerTagValuesQuery  int `yaml:"max_bytes_per_tag_values_query" json:"max_bytes_per_tag_values_query"`
 	MaxBlocksPerTagValuesQuery int `yaml:"max_blocks_per_tag_values_query" json:"max_blocks_per_tag_values_query"`
 
 	// QueryFrontend enforced limits
-	MaxSearchDuration  model.Duration `yaml:"max_search_duration" json:"max_search_duration"`
-	MaxMetricsDuration model.Duration `yaml:"max_metrics_duration" json:"max_metrics_duration"`
-	UnsafeQueryHints   bool           `yaml:"unsafe_query_hints" json:"unsafe_query_hints"`
+	MaxSearchDuration   model.Duration `yaml:"max_search_duration" json:"max_search_duration"`
+	MaxMetricsDuration  model.Duration `yaml:"max_metrics_duration" json:"max_metrics_duration"`
+	MaxQueryLookback    model.Duration `yaml:"max_query_lookback" json:"max_query_lookback"`
+	MaxQueriesPerSecond int            `yaml:"max_queries_per_second" json:"max_queries_per_second"`
+	QueryBurstSize      int            `yaml:"query_burst_size" json:"query_burst_size"`
+	UnsafeQueryHints    bool           `yaml:"unsafe_query_hints" json:"unsafe_query_hints"`
+
+	DisallowTenantFederation bool `yaml:"disallow_tenant_federation" json:"disallow_tenant_federation"`
+
+	// Querier enforced limits
+	QueryTimeout model.Duration `yaml:"query_timeout" json:"query_timeout"`
 
 	// MaxBytesPerTrace is enforced in the Ingester, Compactor, Querier (Search) and Serverless (Search). It
 	//  is not used when doing a trace by id lookup.
@@ -150,11 +173,21 @@ func (l *LegacyOverrides) toNewLimits() Overrides {
 			MaxBlocksPerTagValuesQuery: l.MaxBlocksPerTagValuesQuery,
 			MaxSearchDuration:          l.MaxSearchDuration,
 			MaxMetricsDuration:         l.MaxMetricsDuration,
+			MaxQueryLookback:           l.MaxQueryLookback,
+			MaxQueriesPerSecond:        l.MaxQueriesPerSecond,
+			QueryBurstSize:             l.QueryBurstSize,
 			UnsafeQueryHints:           l.UnsafeQueryHints,
+			DisallowTenantFederation:   l.DisallowTenantFederation,
+			QueryTimeout:               l.QueryTimeout,
 		},
 		Compaction: CompactionOverrides{
-			BlockRetention:   l.BlockRetention,
-			CompactionWindow: l.CompactionWindow,
+			BlockRetention:     l.BlockRetention,
+			CompactionWindow:   l.CompactionWindow,
+			CompactionDisabled: l.CompactionDisabled,
+			CompactionPriority: l.CompactionPriority,
+			MaxStoredBytes:     l.MaxStoredBytes,
+			MaxStoredBlocks:    l.MaxStoredBlocks,
+			StorageQuotaAction: l.StorageQuotaAction,
 		},
 		MetricsGenerator: MetricsGeneratorOverrides{
 			RingSize:           l.MetricsGeneratorRingSize,
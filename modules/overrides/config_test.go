@@ -6,6 +6,7 @@ import (
 	"flag"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/brianvoe/gofakeit/v6"
 	"github.com/stretchr/testify/assert"
@@ -277,6 +278,8 @@ func TestOverrides_AssertUserConfigurableOverridesAreASubsetOfRuntimeOverrides(t
 	//  To fix this we should migrate the various time.Duration to a similar type like client.Duration and
 	//  verify they operate the same when marshalling/unmshalling yaml.
 	userConfigurableOverrides.MetricsGenerator.CollectionInterval = nil
+	// the block retention is a model.Duration in overrides.Overrides, which doesn't accept negative durations
+	userConfigurableOverrides.Compaction.BlockRetention = &client.Duration{Duration: 48 * time.Hour}
 
 	// encode to json
 	var buf bytes.Buffer
@@ -18,6 +18,11 @@ type Service interface {
 	Interface
 }
 
+// Reloader is implemented by overrides services that can switch to a new per-tenant overrides file without a restart
+type Reloader interface {
+	ReloadPerTenantOverrides(path string, period time.Duration) error
+}
+
 type Interface interface {
 	prometheus.Collector
 
@@ -70,10 +75,20 @@ type Interface interface {
 	MetricsGeneratorProcessorServiceGraphsEnableVirtualNodeLabel(userID string) bool
 	MetricsGeneratorProcessorSpanMetricsTargetInfoExcludedDimensions(userID string) []string
 	BlockRetention(userID string) time.Duration
+	CompactionDisabled(userID string) bool
+	CompactionPriority(userID string) int
+	MaxStoredBytes(userID string) int
+	MaxStoredBlocks(userID string) int
+	StorageQuotaAction(userID string) string
 	MaxSearchDuration(userID string) time.Duration
 	MaxMetricsDuration(userID string) time.Duration
+	MaxQueryLookback(userID string) time.Duration
+	MaxQueriesPerSecond(userID string) float64
+	QueryBurstSize(userID string) int
 	DedicatedColumns(userID string) backend.DedicatedColumns
 	UnsafeQueryHints(userID string) bool
+	AllowedTenantFederation(userID string) bool
+	QueryTimeout(userID string) time.Duration
 
 	// Management API
 	WriteStatusRuntimeConfig(w io.Writer, r *http.Request) error
@@ -1,6 +1,9 @@
 package overrides
 
 import (
+	"reflect"
+	"strings"
+
 	"github.com/prometheus/client_golang/prometheus"
 )
 
@@ -30,3 +33,18 @@ func NewOverrides(cfg Config, validator Validator, registerer prometheus.Registe
 
 	return overrides, err
 }
+
+// fieldByYAMLKey returns the field of the struct with the yaml key, or an invalid value if there is none
+func fieldByYAMLKey(v reflect.Value, key string) reflect.Value {
+	for i := 0; i < v.NumField(); i++ {
+		if yamlKey(v.Type().Field(i)) == key {
+			return v.Field(i)
+		}
+	}
+	return reflect.Value{}
+}
+
+func yamlKey(f reflect.StructField) string {
+	key, _, _ := strings.Cut(f.Tag.Get("yaml"), ",")
+	return key
+}
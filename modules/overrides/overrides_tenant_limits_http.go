@@ -0,0 +1,105 @@
+package overrides
+
+import (
+	"net/http"
+	"reflect"
+
+	"github.com/gorilla/mux"
+	"github.com/grafana/dskit/user"
+
+	userconfigurableoverrides "github.com/grafana/tempo/modules/overrides/userconfigurable/client"
+	"github.com/grafana/tempo/pkg/util"
+)
+
+type tenantLimitsResponse struct {
+	Tenant                 string     `json:"tenant"`
+	RuntimeOverridesSource string     `json:"runtime_overrides_source"`
+	Limits                 *Overrides `json:"limits"`
+}
+
+// EffectiveOverrides returns the limits enforced for the tenant: the runtime overrides of the tenant, of the wildcard
+// tenant or the defaults, with the user-configurable overrides applied on top.
+func EffectiveOverrides(o Interface, userID string) *Overrides {
+	if u, ok := o.(*userConfigurableOverridesManager); ok {
+		return u.effectiveOverrides(userID)
+	}
+
+	limits := *o.GetRuntimeOverridesFor(userID)
+	return &limits
+}
+
+// effectiveOverrides returns the runtime overrides of the tenant with every user-configurable limit set by the tenant
+// on top. The user-configurable limits have the yaml keys of the overrides they replace, except for the processors
+// that are merged with the processors of the runtime overrides.
+func (o *userConfigurableOverridesManager) effectiveOverrides(userID string) *Overrides {
+	limits := *o.Interface.GetRuntimeOverridesFor(userID)
+	if userLimits := o.getTenantLimits(userID); userLimits != nil {
+		applyUserConfigurableLimits(reflect.ValueOf(&limits).Elem(), reflect.ValueOf(userLimits).Elem())
+	}
+	limits.MetricsGenerator.Processors = o.MetricsGeneratorProcessors(userID)
+
+	return &limits
+}
+
+var userConfigurableDurationType = reflect.TypeOf(userconfigurableoverrides.Duration{})
+
+// applyUserConfigurableLimits sets the fields of the overrides to the user-configurable limits that are set. The
+// fields are matched by their yaml key.
+func applyUserConfigurableLimits(overrides, limits reflect.Value) {
+	for i := 0; i < limits.NumField(); i++ {
+		field := fieldByYAMLKey(overrides, yamlKey(limits.Type().Field(i)))
+		if !field.IsValid() {
+			continue
+		}
+
+		value := limits.Field(i)
+		switch value.Kind() {
+		case reflect.Struct:
+			if field.Kind() == reflect.Struct {
+				applyUserConfigurableLimits(field, value)
+			}
+			continue
+		case reflect.Pointer, reflect.Map:
+			if value.IsNil() {
+				continue
+			}
+		}
+		if value.Kind() == reflect.Pointer {
+			value = value.Elem()
+		}
+
+		if value.Type() == userConfigurableDurationType {
+			field.SetInt(int64(value.Interface().(userconfigurableoverrides.Duration).Duration))
+			continue
+		}
+		field.Set(value.Convert(field.Type()))
+	}
+}
+
+// TenantLimitsHandler returns the limits enforced for the tenant in the path as JSON. The tenant must be the tenant of
+// the request.
+func TenantLimitsHandler(o Interface) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		tenant := mux.Vars(req)["tenant"]
+		if tenant == "" {
+			http.Error(w, "tenant ID can't be empty", http.StatusBadRequest)
+			return
+		}
+
+		orgID, err := user.ExtractOrgID(req.Context())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		if tenant != orgID {
+			http.Error(w, "tenant ID doesn't match the tenant of the request", http.StatusForbidden)
+			return
+		}
+
+		util.WriteJSONResponse(w, &tenantLimitsResponse{
+			Tenant:                 tenant,
+			RuntimeOverridesSource: RuntimeOverridesSource(o, tenant),
+			Limits:                 EffectiveOverrides(o, tenant),
+		})
+	}
+}
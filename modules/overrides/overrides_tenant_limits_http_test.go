@@ -0,0 +1,146 @@
+package overrides
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/grafana/dskit/user"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	userconfigurableoverrides "github.com/grafana/tempo/modules/overrides/userconfigurable/client"
+	filterconfig "github.com/grafana/tempo/pkg/spanfilter/config"
+)
+
+func TestTenantLimitsHandler(t *testing.T) {
+	tenantID := "test"
+
+	_, mgr, cleanup := localUserConfigOverrides(t, Overrides{Global: GlobalOverrides{MaxBytesPerTrace: 1000}}, toYamlBytes(t, perTenantRuntimeOverrides(tenantID)))
+	defer cleanup()
+
+	mgr.tenantLimits[tenantID] = &userconfigurableoverrides.Limits{
+		Forwarders: &[]string{"my-other-forwarder"},
+		Global: userconfigurableoverrides.LimitsGlobal{
+			MaxBytesPerTrace: intPtr(5000),
+		},
+	}
+
+	router := mux.NewRouter()
+	router.Path("/api/overrides/{tenant}").HandlerFunc(TenantLimitsHandler(mgr))
+
+	request := func(tenant, orgID string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/overrides/"+tenant, nil)
+		if orgID != "" {
+			req = req.WithContext(user.InjectOrgID(req.Context(), orgID))
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	get := func(tenant string) *tenantLimitsResponse {
+		rec := request(tenant, tenant)
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+		var resp tenantLimitsResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		return &resp
+	}
+
+	// runtime overrides with the user-configurable overrides on top
+	resp := get(tenantID)
+	assert.Equal(t, tenantID, resp.Tenant)
+	assert.Equal(t, tenantID, resp.RuntimeOverridesSource)
+	assert.Equal(t, 400, resp.Limits.Ingestion.RateLimitBytes)
+	assert.Equal(t, model.Duration(360*time.Hour), resp.Limits.Compaction.BlockRetention)
+	assert.Equal(t, 5000, resp.Limits.Global.MaxBytesPerTrace)
+	assert.Equal(t, []string{"my-other-forwarder"}, resp.Limits.Forwarders)
+	assert.Equal(t, 15*time.Second, resp.Limits.MetricsGenerator.CollectionInterval)
+
+	// the runtime overrides are not changed
+	assert.Equal(t, []string{"fwd", "fwd-2"}, mgr.GetRuntimeOverridesFor(tenantID).Forwarders)
+
+	// tenants without overrides get the defaults
+	resp = get("other")
+	assert.Equal(t, "default overrides", resp.RuntimeOverridesSource)
+	assert.Equal(t, 1000, resp.Limits.Global.MaxBytesPerTrace)
+	assert.Empty(t, resp.Limits.Forwarders)
+
+	// the limits are only returned to the tenant of the request
+	assert.Equal(t, http.StatusUnauthorized, request(tenantID, "").Code)
+	assert.Equal(t, http.StatusForbidden, request(tenantID, "other").Code)
+}
+
+func TestEffectiveOverridesMatchTheManager(t *testing.T) {
+	tenantID := "test"
+
+	_, mgr, cleanup := localUserConfigOverrides(t, Overrides{}, toYamlBytes(t, perTenantRuntimeOverrides(tenantID)))
+	defer cleanup()
+
+	// every user-configurable limit is set
+	mgr.tenantLimits[tenantID] = &userconfigurableoverrides.Limits{
+		Forwarders: &[]string{"my-other-forwarder"},
+		MetricsGenerator: userconfigurableoverrides.LimitsMetricsGenerator{
+			Processors:         map[string]struct{}{"local-blocks": {}},
+			DisableCollection:  boolPtr(true),
+			CollectionInterval: &userconfigurableoverrides.Duration{Duration: time.Minute},
+			Processor: userconfigurableoverrides.LimitsMetricsGeneratorProcessor{
+				ServiceGraphs: userconfigurableoverrides.LimitsMetricsGeneratorProcessorServiceGraphs{
+					Dimensions:                            &[]string{"sg-dimension"},
+					EnableClientServerPrefix:              boolPtr(true),
+					EnableMessagingSystemLatencyHistogram: boolPtr(true),
+					EnableVirtualNodeLabel:                boolPtr(true),
+					PeerAttributes:                        &[]string{"peer"},
+					HistogramBuckets:                      &[]float64{1, 2},
+				},
+				SpanMetrics: userconfigurableoverrides.LimitsMetricsGeneratorProcessorSpanMetrics{
+					Dimensions:                   &[]string{"sm-dimension"},
+					EnableTargetInfo:             boolPtr(true),
+					FilterPolicies:               &[]filterconfig.FilterPolicy{{Include: &filterconfig.PolicyMatch{MatchType: filterconfig.Strict}}},
+					HistogramBuckets:             &[]float64{3, 4},
+					TargetInfoExcludedDimensions: &[]string{"excluded"},
+				},
+			},
+		},
+		Compaction: userconfigurableoverrides.LimitsCompaction{
+			BlockRetention: &userconfigurableoverrides.Duration{Duration: 2 * time.Hour},
+		},
+		Global: userconfigurableoverrides.LimitsGlobal{
+			MaxBytesPerTrace: intPtr(5000),
+		},
+	}
+
+	limits := EffectiveOverrides(mgr, tenantID)
+	assert.Equal(t, mgr.Forwarders(tenantID), limits.Forwarders)
+	assert.Equal(t, mgr.MetricsGeneratorProcessors(tenantID), map[string]struct{}(limits.MetricsGenerator.Processors))
+	assert.Equal(t, mgr.MetricsGeneratorDisableCollection(tenantID), limits.MetricsGenerator.DisableCollection)
+	assert.Equal(t, mgr.MetricsGeneratorCollectionInterval(tenantID), limits.MetricsGenerator.CollectionInterval)
+
+	serviceGraphs := limits.MetricsGenerator.Processor.ServiceGraphs
+	assert.Equal(t, mgr.MetricsGeneratorProcessorServiceGraphsDimensions(tenantID), serviceGraphs.Dimensions)
+	assert.Equal(t, mgr.MetricsGeneratorProcessorServiceGraphsEnableClientServerPrefix(tenantID), serviceGraphs.EnableClientServerPrefix)
+	assert.Equal(t, mgr.MetricsGeneratorProcessorServiceGraphsEnableMessagingSystemLatencyHistogram(tenantID), serviceGraphs.EnableMessagingSystemLatencyHistogram)
+	assert.Equal(t, mgr.MetricsGeneratorProcessorServiceGraphsEnableVirtualNodeLabel(tenantID), serviceGraphs.EnableVirtualNodeLabel)
+	assert.Equal(t, mgr.MetricsGeneratorProcessorServiceGraphsPeerAttributes(tenantID), serviceGraphs.PeerAttributes)
+	assert.Equal(t, mgr.MetricsGeneratorProcessorServiceGraphsHistogramBuckets(tenantID), serviceGraphs.HistogramBuckets)
+
+	spanMetrics := limits.MetricsGenerator.Processor.SpanMetrics
+	assert.Equal(t, mgr.MetricsGeneratorProcessorSpanMetricsDimensions(tenantID), spanMetrics.Dimensions)
+	assert.Equal(t, mgr.MetricsGeneratorProcessorSpanMetricsEnableTargetInfo(tenantID), spanMetrics.EnableTargetInfo)
+	assert.Equal(t, mgr.MetricsGeneratorProcessorSpanMetricsFilterPolicies(tenantID), spanMetrics.FilterPolicies)
+	assert.Equal(t, mgr.MetricsGeneratorProcessorSpanMetricsHistogramBuckets(tenantID), spanMetrics.HistogramBuckets)
+	assert.Equal(t, mgr.MetricsGeneratorProcessorSpanMetricsTargetInfoExcludedDimensions(tenantID), spanMetrics.TargetInfoExcludedDimensions)
+
+	assert.Equal(t, mgr.BlockRetention(tenantID), time.Duration(limits.Compaction.BlockRetention))
+	assert.Equal(t, mgr.MaxBytesPerTrace(tenantID), limits.Global.MaxBytesPerTrace)
+
+	// the processors of both layers are enabled
+	assert.Contains(t, limits.MetricsGenerator.Processors, "local-blocks")
+	assert.Greater(t, len(limits.MetricsGenerator.Processors), 1)
+}
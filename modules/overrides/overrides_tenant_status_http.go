@@ -26,6 +26,31 @@ type tenantStatusPageContents struct {
 	RuntimeOverridesSource    string    `json:"using_default_or_wildcard_runtime_overrides"`
 }
 
+// RuntimeOverridesSource returns where the runtime overrides of the tenant are set: the tenant ID if the tenant has
+// its own overrides, its override group, the pattern matching it or "default overrides".
+func RuntimeOverridesSource(o Interface, userID string) string {
+	if u, ok := o.(*userConfigurableOverridesManager); ok {
+		o = u.Interface
+	}
+	if r, ok := o.(*runtimeConfigOverridesManager); ok {
+		if source := r.overridesSource(userID); source != "" {
+			return source
+		}
+		return "default overrides"
+	}
+
+	runtimeTenants := o.GetTenantIDs()
+
+	switch {
+	case slices.Contains(runtimeTenants, userID):
+		return userID
+	case slices.Contains(runtimeTenants, wildcardTenant):
+		return wildcardTenant
+	default:
+		return "default overrides"
+	}
+}
+
 func TenantStatusHandler(o Interface) http.HandlerFunc {
 	return func(w http.ResponseWriter, req *http.Request) {
 		page := tenantStatusPageContents{
@@ -49,22 +74,7 @@ func TenantStatusHandler(o Interface) http.HandlerFunc {
 		}
 		page.RuntimeOverrides = string(runtimeOverrides)
 
-		var runtimeTenants []string
-		switch o := o.(type) {
-		case *runtimeConfigOverridesManager:
-			runtimeTenants = o.GetTenantIDs()
-		case *userConfigurableOverridesManager:
-			runtimeTenants = o.Interface.GetTenantIDs()
-		default:
-			util.WriteTextResponse(w, "Internal error happened when retrieving runtime overrides")
-		}
-		if slices.Contains(runtimeTenants, page.Tenant) {
-			page.RuntimeOverridesSource = page.Tenant
-		} else if slices.Contains(runtimeTenants, wildcardTenant) {
-			page.RuntimeOverridesSource = wildcardTenant
-		} else {
-			page.RuntimeOverridesSource = "default overrides"
-		}
+		page.RuntimeOverridesSource = RuntimeOverridesSource(o, page.Tenant)
 
 		// user-configurable overrides
 		if userConfigOverridesManager, ok := o.(*userConfigurableOverridesManager); ok {
@@ -6,15 +6,24 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"path"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/drone/envsubst"
 	"github.com/go-kit/log/level"
+	lru "github.com/hashicorp/golang-lru/v2"
 	"golang.org/x/exp/maps"
 
 	"github.com/grafana/dskit/runtimeconfig"
 	"github.com/grafana/dskit/services"
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/model"
 	"gopkg.in/yaml.v2"
 
 	"github.com/grafana/tempo/pkg/sharedconfig"
@@ -30,9 +39,37 @@ type Validator interface {
 
 // perTenantOverrides represents the overrides config file
 type perTenantOverrides struct {
+	// TenantLimits are keyed by tenant ID or by a pattern like dev-* matching many tenants
 	TenantLimits map[string]*Overrides `yaml:"overrides"`
+	// Groups are overrides shared by the tenants listed in each group
+	Groups map[string]*OverridesGroup `yaml:"override_groups,omitempty"`
 
 	ConfigType ConfigType `yaml:"-"` // ConfigType is the type of overrides config we are using: legacy or new
+
+	// patterns are the keys of TenantLimits containing a wildcard, the most specific first
+	patterns []string
+	// tenantGroups is the group of each tenant listed in a group
+	tenantGroups map[string]string
+
+	// tenantKeys and groupKeys are the yaml keys set in the overrides, so they are merged field by field
+	tenantKeys map[string]yaml.MapSlice
+	groupKeys  map[string]yaml.MapSlice
+	// resolved are the merged overrides of the tenants
+	resolved *lru.Cache[string, resolvedOverrides]
+}
+
+type resolvedOverrides struct {
+	limits *Overrides
+	source string
+}
+
+// maxResolvedTenants bounds the merged overrides that are kept, patterns can match any tenant
+const maxResolvedTenants = 10_000
+
+// OverridesGroup are the overrides of all the tenants in the group
+type OverridesGroup struct {
+	Tenants   []string   `yaml:"tenants"`
+	Overrides *Overrides `yaml:"overrides"`
 }
 
 func (o *perTenantOverrides) UnmarshalYAML(unmarshal func(interface{}) error) error {
@@ -43,7 +80,25 @@ func (o *perTenantOverrides) UnmarshalYAML(unmarshal func(interface{}) error) er
 	type rawConfig perTenantOverrides
 	if err := unmarshal((*rawConfig)(o)); err == nil {
 		o.ConfigType = ConfigTypeNew
-		return nil
+
+		// the same overrides with only the keys that are set
+		var keys struct {
+			TenantLimits map[string]yaml.MapSlice `yaml:"overrides"`
+			Groups       map[string]struct {
+				Tenants   []string      `yaml:"tenants"`
+				Overrides yaml.MapSlice `yaml:"overrides"`
+			} `yaml:"override_groups,omitempty"`
+		}
+		if err := unmarshal(&keys); err != nil {
+			return err
+		}
+		o.tenantKeys = keys.TenantLimits
+		o.groupKeys = make(map[string]yaml.MapSlice, len(keys.Groups))
+		for name, group := range keys.Groups {
+			o.groupKeys[name] = group.Overrides
+		}
+
+		return o.index()
 	}
 
 	var legacyConfig perTenantLegacyOverrides
@@ -54,16 +109,142 @@ func (o *perTenantOverrides) UnmarshalYAML(unmarshal func(interface{}) error) er
 	*o = legacyConfig.toNewOverrides()
 	o.ConfigType = ConfigTypeLegacy
 
-	return nil
+	return o.index()
 }
 
-// forUser returns limits for a given tenant, or nil if there are no tenant-specific limits.
-func (o *perTenantOverrides) forUser(userID string) *Overrides {
-	l, ok := o.TenantLimits[userID]
-	if !ok || l == nil {
-		return nil
+// index finds the wildcard patterns and the group of each tenant so the overrides of a tenant are resolved without
+// iterating over all the overrides
+func (o *perTenantOverrides) index() error {
+	o.patterns = o.patterns[:0]
+	for key := range o.TenantLimits {
+		if !strings.Contains(key, wildcardTenant) {
+			continue
+		}
+		if _, err := path.Match(key, ""); err != nil {
+			return fmt.Errorf("invalid tenant pattern %s: %w", key, err)
+		}
+		o.patterns = append(o.patterns, key)
+	}
+	// longer patterns are more specific, so * is always the last one
+	sort.Slice(o.patterns, func(i, j int) bool {
+		if len(o.patterns[i]) != len(o.patterns[j]) {
+			return len(o.patterns[i]) > len(o.patterns[j])
+		}
+		return o.patterns[i] < o.patterns[j]
+	})
+
+	o.tenantGroups = map[string]string{}
+	for name, group := range o.Groups {
+		if group == nil || group.Overrides == nil {
+			return fmt.Errorf("override group %s has no overrides", name)
+		}
+		for _, tenantID := range group.Tenants {
+			if other, ok := o.tenantGroups[tenantID]; ok {
+				return fmt.Errorf("tenant %s is in override groups %s and %s", tenantID, other, name)
+			}
+			o.tenantGroups[tenantID] = name
+		}
+	}
+
+	// the keys of overrides that were not read from yaml, like the converted legacy overrides, are the keys of their
+	// fields that are not empty
+	var err error
+	if o.tenantKeys, err = keysOf(o.tenantKeys, o.TenantLimits); err != nil {
+		return err
+	}
+	groupLimits := make(map[string]*Overrides, len(o.Groups))
+	for name, group := range o.Groups {
+		groupLimits[name] = group.Overrides
+	}
+	if o.groupKeys, err = keysOf(o.groupKeys, groupLimits); err != nil {
+		return err
+	}
+
+	o.resolved, err = lru.New[string, resolvedOverrides](maxResolvedTenants)
+	return err
+}
+
+func keysOf(keys map[string]yaml.MapSlice, limits map[string]*Overrides) (map[string]yaml.MapSlice, error) {
+	if keys == nil {
+		keys = make(map[string]yaml.MapSlice, len(limits))
+	}
+	for name, l := range limits {
+		if _, ok := keys[name]; ok || l == nil {
+			continue
+		}
+		b, err := yaml.Marshal(l)
+		if err != nil {
+			return nil, err
+		}
+		var set yaml.MapSlice
+		if err := yaml.Unmarshal(b, &set); err != nil {
+			return nil, err
+		}
+		keys[name] = set
+	}
+	return keys, nil
+}
+
+// resolve returns the limits of a tenant and where they are set, or nil if the defaults apply. The limits are merged
+// field by field: the fields set in the overrides of the tenant are used first, then the ones of its group, then the
+// ones of the patterns matching it from the most specific and then the defaults.
+func (o *perTenantOverrides) resolve(userID string, defaults *Overrides) (*Overrides, string) {
+	if r, ok := o.resolved.Get(userID); ok {
+		return r.limits, r.source
+	}
+
+	// the layers from the least specific
+	var layers []*Overrides
+	var layerKeys []yaml.MapSlice
+	var sources []string
+	add := func(l *Overrides, keys yaml.MapSlice, source string) {
+		layers = append(layers, l)
+		layerKeys = append(layerKeys, keys)
+		sources = append(sources, source)
+	}
+	for i := len(o.patterns) - 1; i >= 0; i-- {
+		pattern := o.patterns[i]
+		if ok, _ := path.Match(pattern, userID); ok && o.TenantLimits[pattern] != nil {
+			add(o.TenantLimits[pattern], o.tenantKeys[pattern], pattern)
+		}
+	}
+	if name, ok := o.tenantGroups[userID]; ok {
+		add(o.Groups[name].Overrides, o.groupKeys[name], "group "+name)
+	}
+	if l := o.TenantLimits[userID]; l != nil && !strings.Contains(userID, wildcardTenant) {
+		add(l, o.tenantKeys[userID], userID)
+	}
+
+	var r resolvedOverrides
+	if len(layers) > 0 {
+		limits := *defaults
+		for i, l := range layers {
+			mergeOverrides(reflect.ValueOf(&limits).Elem(), reflect.ValueOf(l).Elem(), layerKeys[i])
+		}
+		// the most specific source first
+		slices.Reverse(sources)
+		r = resolvedOverrides{limits: &limits, source: strings.Join(sources, ", ")}
+	}
+
+	o.resolved.Add(userID, r)
+	return r.limits, r.source
+}
+
+// mergeOverrides sets the fields of dst with the given yaml keys to the fields of src
+func mergeOverrides(dst, src reflect.Value, keys yaml.MapSlice) {
+	for _, item := range keys {
+		key, _ := item.Key.(string)
+		dstField, srcField := fieldByYAMLKey(dst, key), fieldByYAMLKey(src, key)
+		if !dstField.IsValid() || !srcField.IsValid() {
+			continue
+		}
+
+		if nested, ok := item.Value.(yaml.MapSlice); ok && dstField.Kind() == reflect.Struct {
+			mergeOverrides(dstField, srcField, nested)
+			continue
+		}
+		dstField.Set(srcField)
 	}
-	return l
 }
 
 // loadPerTenantOverrides is of type runtimeconfig.Loader
@@ -108,6 +289,12 @@ func loadPerTenantOverrides(validator Validator, typ ConfigType, expandEnv bool)
 					return nil, fmt.Errorf("validating overrides for %s failed: %w", tenant, err)
 				}
 			}
+			for name, group := range overrides.Groups {
+				err := validator.Validate(group.Overrides)
+				if err != nil {
+					return nil, fmt.Errorf("validating overrides for group %s failed: %w", name, err)
+				}
+			}
 		}
 
 		return overrides, nil
@@ -120,38 +307,43 @@ type runtimeConfigOverridesManager struct {
 	services.Service
 
 	defaultLimits    *Overrides
-	runtimeConfigMgr *runtimeconfig.Manager
+	runtimeConfigMgr atomic.Pointer[runtimeconfig.Manager]
+
+	// used to recreate the runtime config manager on reload
+	cfg        Config
+	validator  Validator
+	registerer prometheus.Registerer
+	reloadMtx  sync.Mutex
 
 	// Manager for subservices
 	subservices        *services.Manager
 	subservicesWatcher *services.FailureWatcher
 }
 
-var _ Interface = (*runtimeConfigOverridesManager)(nil)
+var (
+	_ Interface = (*runtimeConfigOverridesManager)(nil)
+	_ Reloader  = (*runtimeConfigOverridesManager)(nil)
+)
 
 func newRuntimeConfigOverrides(cfg Config, validator Validator, registerer prometheus.Registerer) (Service, error) {
-	var manager *runtimeconfig.Manager
 	subservices := []services.Service(nil)
 
+	o := &runtimeConfigOverridesManager{
+		defaultLimits: &cfg.Defaults,
+		cfg:           cfg,
+		validator:     validator,
+		registerer:    registerer,
+	}
+
 	if cfg.PerTenantOverrideConfig != "" {
-		runtimeCfg := runtimeconfig.Config{
-			LoadPath:     []string{cfg.PerTenantOverrideConfig},
-			ReloadPeriod: time.Duration(cfg.PerTenantOverridePeriod),
-			Loader:       loadPerTenantOverrides(validator, cfg.ConfigType, cfg.ExpandEnv),
-		}
-		runtimeCfgMgr, err := runtimeconfig.New(runtimeCfg, "overrides", prometheus.WrapRegistererWithPrefix("tempo_", registerer), log.Logger)
+		runtimeCfgMgr, err := o.newRuntimeConfigManager(cfg.PerTenantOverrideConfig, time.Duration(cfg.PerTenantOverridePeriod), registerer)
 		if err != nil {
-			return nil, fmt.Errorf("failed to create runtime config manager: %w", err)
+			return nil, err
 		}
-		manager = runtimeCfgMgr
+		o.runtimeConfigMgr.Store(runtimeCfgMgr)
 		subservices = append(subservices, runtimeCfgMgr)
 	}
 
-	o := &runtimeConfigOverridesManager{
-		runtimeConfigMgr: manager,
-		defaultLimits:    &cfg.Defaults,
-	}
-
 	if len(subservices) > 0 {
 		var err error
 		o.subservices, err = services.NewManager(subservices...)
@@ -167,6 +359,79 @@ func newRuntimeConfigOverrides(cfg Config, validator Validator, registerer prome
 	return o, nil
 }
 
+func (o *runtimeConfigOverridesManager) newRuntimeConfigManager(path string, period time.Duration, registerer prometheus.Registerer) (*runtimeconfig.Manager, error) {
+	runtimeCfg := runtimeconfig.Config{
+		LoadPath:     []string{path},
+		ReloadPeriod: period,
+		Loader:       loadPerTenantOverrides(o.validator, o.cfg.ConfigType, o.cfg.ExpandEnv),
+	}
+	runtimeCfgMgr, err := runtimeconfig.New(runtimeCfg, "overrides", prometheus.WrapRegistererWithPrefix("tempo_", registerer), log.Logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create runtime config manager: %w", err)
+	}
+	return runtimeCfgMgr, nil
+}
+
+// ReloadPerTenantOverrides switches to a new per-tenant overrides file and reload period. The new file is
+// loaded and validated before it replaces the current one. An empty path removes all per-tenant overrides.
+func (o *runtimeConfigOverridesManager) ReloadPerTenantOverrides(path string, period time.Duration) error {
+	o.reloadMtx.Lock()
+	defer o.reloadMtx.Unlock()
+
+	if path == o.cfg.PerTenantOverrideConfig && period == time.Duration(o.cfg.PerTenantOverridePeriod) {
+		return nil
+	}
+
+	var runtimeCfgMgr *runtimeconfig.Manager
+	if path != "" {
+		// the metrics of the current manager are replaced by the new one
+		registerer := o.registerer
+		if registerer != nil {
+			registerer = replacingRegisterer{registerer}
+		}
+
+		var err error
+		runtimeCfgMgr, err = o.newRuntimeConfigManager(path, period, registerer)
+		if err != nil {
+			return err
+		}
+		if err := services.StartAndAwaitRunning(context.Background(), runtimeCfgMgr); err != nil {
+			return fmt.Errorf("failed to load per tenant overrides from %s: %w", path, err)
+		}
+	}
+
+	if previous := o.runtimeConfigMgr.Swap(runtimeCfgMgr); previous != nil {
+		if err := services.StopAndAwaitTerminated(context.Background(), previous); err != nil {
+			level.Warn(log.Logger).Log("msg", "failed to stop previous runtime config manager", "err", err)
+		}
+	}
+
+	o.cfg.PerTenantOverrideConfig = path
+	o.cfg.PerTenantOverridePeriod = model.Duration(period)
+
+	level.Info(log.Logger).Log("msg", "per tenant overrides reloaded", "path", path, "period", period)
+	return nil
+}
+
+// replacingRegisterer unregisters existing collectors with the same descriptors before registering
+// so a component can be recreated without a duplicate registration panic
+type replacingRegisterer struct {
+	prometheus.Registerer
+}
+
+func (r replacingRegisterer) Register(c prometheus.Collector) error {
+	r.Registerer.Unregister(c)
+	return r.Registerer.Register(c)
+}
+
+func (r replacingRegisterer) MustRegister(cs ...prometheus.Collector) {
+	for _, c := range cs {
+		if err := r.Register(c); err != nil {
+			panic(err)
+		}
+	}
+}
+
 func (o *runtimeConfigOverridesManager) starting(ctx context.Context) error {
 	if o.subservices != nil {
 		err := services.StartManagerAndAwaitHealthy(ctx, o.subservices)
@@ -193,16 +458,24 @@ func (o *runtimeConfigOverridesManager) running(ctx context.Context) error {
 
 func (o *runtimeConfigOverridesManager) stopping(_ error) error {
 	if o.subservices != nil {
-		return services.StopManagerAndAwaitStopped(context.Background(), o.subservices)
+		if err := services.StopManagerAndAwaitStopped(context.Background(), o.subservices); err != nil {
+			return err
+		}
+	}
+
+	// a manager created by ReloadPerTenantOverrides is not part of the subservices
+	if m := o.runtimeConfigMgr.Load(); m != nil && m.State() == services.Running {
+		return services.StopAndAwaitTerminated(context.Background(), m)
 	}
 	return nil
 }
 
 func (o *runtimeConfigOverridesManager) tenantOverrides() *perTenantOverrides {
-	if o.runtimeConfigMgr == nil {
+	runtimeConfigMgr := o.runtimeConfigMgr.Load()
+	if runtimeConfigMgr == nil {
 		return nil
 	}
-	cfg, ok := o.runtimeConfigMgr.GetConfig().(*perTenantOverrides)
+	cfg, ok := runtimeConfigMgr.GetConfig().(*perTenantOverrides)
 	if !ok || cfg == nil {
 		return nil
 	}
@@ -277,7 +550,25 @@ func (o *runtimeConfigOverridesManager) GetTenantIDs() []string {
 		return nil
 	}
 
-	return maps.Keys(tenantOverrides.TenantLimits)
+	tenantIDs := maps.Keys(tenantOverrides.TenantLimits)
+	for tenantID := range tenantOverrides.tenantGroups {
+		if _, ok := tenantOverrides.TenantLimits[tenantID]; !ok {
+			tenantIDs = append(tenantIDs, tenantID)
+		}
+	}
+	return tenantIDs
+}
+
+// overridesSource returns where the runtime overrides of the tenant are set: the tenant ID, its group or the pattern
+// matching it. It returns an empty string if the tenant uses the default overrides.
+func (o *runtimeConfigOverridesManager) overridesSource(userID string) string {
+	tenantOverrides := o.tenantOverrides()
+	if tenantOverrides == nil {
+		return ""
+	}
+
+	_, source := tenantOverrides.resolve(userID, o.defaultLimits)
+	return source
 }
 
 func (o *runtimeConfigOverridesManager) GetRuntimeOverridesFor(userID string) *Overrides {
@@ -348,6 +639,16 @@ func (o *runtimeConfigOverridesManager) UnsafeQueryHints(userID string) bool {
 	return o.getOverridesForUser(userID).Read.UnsafeQueryHints
 }
 
+// AllowedTenantFederation returns true if the tenant can be included in a multi-tenant query.
+func (o *runtimeConfigOverridesManager) AllowedTenantFederation(userID string) bool {
+	return !o.getOverridesForUser(userID).Read.DisallowTenantFederation
+}
+
+// QueryTimeout is the timeout of querier jobs for this tenant. 0 means the timeout in the querier configuration is used.
+func (o *runtimeConfigOverridesManager) QueryTimeout(userID string) time.Duration {
+	return time.Duration(o.getOverridesForUser(userID).Read.QueryTimeout)
+}
+
 // MaxSearchDuration is the duration of the max search duration for this tenant.
 func (o *runtimeConfigOverridesManager) MaxSearchDuration(userID string) time.Duration {
 	return time.Duration(o.getOverridesForUser(userID).Read.MaxSearchDuration)
@@ -357,6 +658,21 @@ func (o *runtimeConfigOverridesManager) MaxMetricsDuration(userID string) time.D
 	return time.Duration(o.getOverridesForUser(userID).Read.MaxMetricsDuration)
 }
 
+// MaxQueryLookback is how far back from now search, tag and metrics queries of this tenant can start. 0 is unlimited.
+func (o *runtimeConfigOverridesManager) MaxQueryLookback(userID string) time.Duration {
+	return time.Duration(o.getOverridesForUser(userID).Read.MaxQueryLookback)
+}
+
+// MaxQueriesPerSecond is the number of queries per second of this tenant allowed by each query frontend. 0 is unlimited.
+func (o *runtimeConfigOverridesManager) MaxQueriesPerSecond(userID string) float64 {
+	return float64(o.getOverridesForUser(userID).Read.MaxQueriesPerSecond)
+}
+
+// QueryBurstSize is the number of queries of this tenant allowed in a burst by each query frontend.
+func (o *runtimeConfigOverridesManager) QueryBurstSize(userID string) int {
+	return o.getOverridesForUser(userID).Read.QueryBurstSize
+}
+
 // MetricsGeneratorIngestionSlack is the max amount of time passed since a span's end time
 // for the span to be considered in metrics generation
 func (o *runtimeConfigOverridesManager) MetricsGeneratorIngestionSlack(userID string) time.Duration {
@@ -376,7 +692,12 @@ func (o *runtimeConfigOverridesManager) MetricsGeneratorRingSize(userID string)
 
 // MetricsGeneratorProcessors returns the metrics-generator processors enabled for this tenant.
 func (o *runtimeConfigOverridesManager) MetricsGeneratorProcessors(userID string) map[string]struct{} {
-	return o.getOverridesForUser(userID).MetricsGenerator.Processors.GetMap()
+	processors := o.getOverridesForUser(userID).MetricsGenerator.Processors
+	if processors == nil {
+		// GetMap would assign the empty map to the shared overrides which races with concurrent pushes
+		return map[string]struct{}{}
+	}
+	return processors
 }
 
 // MetricsGeneratorMaxActiveSeries is the maximum amount of active series in the metrics-generator
@@ -511,19 +832,43 @@ func (o *runtimeConfigOverridesManager) BlockRetention(userID string) time.Durat
 	return time.Duration(o.getOverridesForUser(userID).Compaction.BlockRetention)
 }
 
+// CompactionDisabled returns true if the compactors skip this tenant.
+func (o *runtimeConfigOverridesManager) CompactionDisabled(userID string) bool {
+	return o.getOverridesForUser(userID).Compaction.CompactionDisabled
+}
+
+// CompactionPriority is the priority of this tenant's compactions. Tenants with a lower priority are only compacted
+// while the tenants with a higher priority have no blocks to compact.
+func (o *runtimeConfigOverridesManager) CompactionPriority(userID string) int {
+	return o.getOverridesForUser(userID).Compaction.CompactionPriority
+}
+
+// MaxStoredBytes is the maximum size of the blocks stored for this tenant. 0 disables the limit.
+func (o *runtimeConfigOverridesManager) MaxStoredBytes(userID string) int {
+	return o.getOverridesForUser(userID).Compaction.MaxStoredBytes
+}
+
+// MaxStoredBlocks is the maximum number of blocks stored for this tenant. 0 disables the limit.
+func (o *runtimeConfigOverridesManager) MaxStoredBlocks(userID string) int {
+	return o.getOverridesForUser(userID).Compaction.MaxStoredBlocks
+}
+
+// StorageQuotaAction is what the compactors do when this tenant exceeds its storage quota: delete its oldest blocks
+// or reject its traces.
+func (o *runtimeConfigOverridesManager) StorageQuotaAction(userID string) string {
+	if action := o.getOverridesForUser(userID).Compaction.StorageQuotaAction; action != "" {
+		return action
+	}
+	return StorageQuotaActionDelete
+}
+
 func (o *runtimeConfigOverridesManager) DedicatedColumns(userID string) backend.DedicatedColumns {
 	return o.getOverridesForUser(userID).Storage.DedicatedColumns
 }
 
 func (o *runtimeConfigOverridesManager) getOverridesForUser(userID string) *Overrides {
 	if tenantOverrides := o.tenantOverrides(); tenantOverrides != nil {
-		l := tenantOverrides.forUser(userID)
-		if l != nil {
-			return l
-		}
-
-		l = tenantOverrides.forUser(wildcardTenant)
-		if l != nil {
+		if l, _ := tenantOverrides.resolve(userID, o.defaultLimits); l != nil {
 			return l
 		}
 	}
@@ -541,13 +886,23 @@ func (o *runtimeConfigOverridesManager) Collect(ch chan<- prometheus.Metric) {
 		return
 	}
 
-	for tenant, limits := range overrides.TenantLimits {
+	// the tenants and the patterns are reported with their merged overrides
+	for _, tenant := range o.GetTenantIDs() {
+		limits, _ := overrides.resolve(tenant, o.defaultLimits)
+		if limits == nil {
+			continue
+		}
+
 		ch <- prometheus.MustNewConstMetric(metricOverridesLimitsDesc, prometheus.GaugeValue, float64(limits.Ingestion.MaxLocalTracesPerUser), MetricMaxLocalTracesPerUser, tenant)
 		ch <- prometheus.MustNewConstMetric(metricOverridesLimitsDesc, prometheus.GaugeValue, float64(limits.Ingestion.MaxGlobalTracesPerUser), MetricMaxGlobalTracesPerUser, tenant)
 		ch <- prometheus.MustNewConstMetric(metricOverridesLimitsDesc, prometheus.GaugeValue, float64(limits.Ingestion.RateLimitBytes), MetricIngestionRateLimitBytes, tenant)
 		ch <- prometheus.MustNewConstMetric(metricOverridesLimitsDesc, prometheus.GaugeValue, float64(limits.Ingestion.BurstSizeBytes), MetricIngestionBurstSizeBytes, tenant)
 		ch <- prometheus.MustNewConstMetric(metricOverridesLimitsDesc, prometheus.GaugeValue, float64(limits.Global.MaxBytesPerTrace), MetricMaxBytesPerTrace, tenant)
 		ch <- prometheus.MustNewConstMetric(metricOverridesLimitsDesc, prometheus.GaugeValue, float64(limits.Compaction.BlockRetention), MetricBlockRetention, tenant)
+		ch <- prometheus.MustNewConstMetric(metricOverridesLimitsDesc, prometheus.GaugeValue, float64(limits.Compaction.MaxStoredBytes), MetricMaxStoredBytes, tenant)
+		ch <- prometheus.MustNewConstMetric(metricOverridesLimitsDesc, prometheus.GaugeValue, float64(limits.Compaction.MaxStoredBlocks), MetricMaxStoredBlocks, tenant)
+		ch <- prometheus.MustNewConstMetric(metricOverridesLimitsDesc, prometheus.GaugeValue, float64(limits.Read.MaxQueriesPerSecond), MetricMaxQueriesPerSecond, tenant)
+		ch <- prometheus.MustNewConstMetric(metricOverridesLimitsDesc, prometheus.GaugeValue, float64(limits.Read.QueryBurstSize), MetricQueryBurstSize, tenant)
 		ch <- prometheus.MustNewConstMetric(metricOverridesLimitsDesc, prometheus.GaugeValue, float64(limits.MetricsGenerator.MaxActiveSeries), MetricMetricsGeneratorMaxActiveSeries, tenant)
 
 		if limits.MetricsGenerator.DisableCollection {
@@ -172,7 +172,8 @@ func TestRuntimeConfigOverrides(t *testing.T) {
 			expectedMaxBytesPerTrace:    map[string]int{"user1": 8, "user2": 13},
 			expectedIngestionBurstSpans: map[string]int{"user1": 9, "user2": 14},
 			expectedIngestionRateSpans:  map[string]int{"user1": 10, "user2": 15},
-			expectedMaxSearchDuration:   map[string]int{"user1": 0, "user2": int(16 * time.Second)},
+			// user1 doesn't set a max search duration, so it's the one of the wildcard
+			expectedMaxSearchDuration: map[string]int{"user1": int(16 * time.Second), "user2": int(16 * time.Second)},
 		},
 	}
 
@@ -528,6 +529,26 @@ overrides:
 	}
 }
 
+func TestTenantFederationOverrides(t *testing.T) {
+	// per-tenant overrides aren't merged with the defaults. tenants with overrides that don't mention federation are
+	// still allowed in multi-tenant queries
+	perTenantOverrides := `
+overrides:
+  user2:
+    ingestion:
+      max_traces_per_user: 10
+  user3:
+    read:
+      disallow_tenant_federation: true
+`
+	overrides, cleanup := createAndInitializeRuntimeOverridesManager(t, Overrides{}, []byte(perTenantOverrides))
+	defer cleanup()
+
+	assert.True(t, overrides.AllowedTenantFederation("user1"))
+	assert.True(t, overrides.AllowedTenantFederation("user2"))
+	assert.False(t, overrides.AllowedTenantFederation("user3"))
+}
+
 func TestRemoteWriteHeaders(t *testing.T) {
 	cfg := Config{
 		Defaults: Overrides{
@@ -598,6 +619,158 @@ overrides:
 	require.NoError(t, services.StopAndAwaitTerminated(context.Background(), overrides))
 }
 
+func TestReloadPerTenantOverrides(t *testing.T) {
+	writeOverrides := func(maxBytes int) string {
+		overridesFile := filepath.Join(t.TempDir(), "Overrides.yaml")
+		perTenantOverrides := fmt.Sprintf(`
+overrides:
+  user1:
+    global:
+      max_bytes_per_trace: %d
+`, maxBytes)
+		require.NoError(t, os.WriteFile(overridesFile, []byte(perTenantOverrides), os.ModePerm))
+		return overridesFile
+	}
+
+	cfg := Config{
+		Defaults: Overrides{
+			Global: GlobalOverrides{MaxBytesPerTrace: 1},
+		},
+		PerTenantOverrideConfig: writeOverrides(2),
+		PerTenantOverridePeriod: model.Duration(time.Hour),
+	}
+
+	overrides, err := newRuntimeConfigOverrides(cfg, &mockValidator{}, prometheus.NewRegistry())
+	require.NoError(t, err)
+	require.NoError(t, services.StartAndAwaitRunning(context.TODO(), overrides))
+	assert.Equal(t, 2, overrides.MaxBytesPerTrace("user1"))
+
+	reloader := overrides.(Reloader)
+
+	// switch to a new file
+	require.NoError(t, reloader.ReloadPerTenantOverrides(writeOverrides(3), time.Hour))
+	assert.Equal(t, 3, overrides.MaxBytesPerTrace("user1"))
+
+	// an invalid file keeps the current overrides
+	invalidFile := filepath.Join(t.TempDir(), "Invalid.yaml")
+	require.NoError(t, os.WriteFile(invalidFile, []byte("not: [valid"), os.ModePerm))
+	require.Error(t, reloader.ReloadPerTenantOverrides(invalidFile, time.Hour))
+	assert.Equal(t, 3, overrides.MaxBytesPerTrace("user1"))
+
+	// an empty path removes the per tenant overrides
+	require.NoError(t, reloader.ReloadPerTenantOverrides("", time.Hour))
+	assert.Equal(t, 1, overrides.MaxBytesPerTrace("user1"))
+
+	require.NoError(t, services.StopAndAwaitTerminated(context.Background(), overrides))
+}
+
+func TestOverrideGroupsAndPatterns(t *testing.T) {
+	perTenantOverrides := `
+overrides:
+  tenant-a:
+    global:
+      max_bytes_per_trace: 2
+  dev-*:
+    global:
+      max_bytes_per_trace: 3
+  dev-team-*:
+    global:
+      max_bytes_per_trace: 4
+  "*":
+    global:
+      max_bytes_per_trace: 5
+override_groups:
+  premium:
+    tenants: [tenant-a, tenant-b, dev-team-1]
+    overrides:
+      global:
+        max_bytes_per_trace: 6
+`
+	overrides, cleanup := createAndInitializeRuntimeOverridesManager(t, Overrides{Global: GlobalOverrides{MaxBytesPerTrace: 1}}, []byte(perTenantOverrides))
+	defer cleanup()
+
+	for tenantID, expected := range map[string]struct {
+		maxBytesPerTrace int
+		source           string
+	}{
+		"tenant-a":   {2, "tenant-a, group premium, *"}, // the tenant has precedence over its group
+		"tenant-b":   {6, "group premium, *"},           // the group has precedence over the patterns
+		"dev-team-1": {6, "group premium, dev-team-*, dev-*, *"},
+		"dev-team-2": {4, "dev-team-*, dev-*, *"}, // the most specific pattern
+		"dev-2":      {3, "dev-*, *"},
+		"dev":        {5, "*"},
+		"other":      {5, "*"},
+	} {
+		assert.Equal(t, expected.maxBytesPerTrace, overrides.MaxBytesPerTrace(tenantID), tenantID)
+		assert.Equal(t, expected.source, RuntimeOverridesSource(overrides, tenantID), tenantID)
+	}
+
+	assert.ElementsMatch(t, []string{"tenant-a", "dev-*", "dev-team-*", "*", "tenant-b", "dev-team-1"}, overrides.GetTenantIDs())
+
+	// the fields are merged from the tenant, its group, the patterns and the defaults
+	perTenantOverrides = `
+overrides:
+  tenant-a:
+    global:
+      max_bytes_per_trace: 0
+  dev-*:
+    ingestion:
+      max_traces_per_user: 3
+  "*":
+    ingestion:
+      max_traces_per_user: 4
+      burst_size_bytes: 5
+override_groups:
+  premium:
+    tenants: [tenant-a]
+    overrides:
+      read:
+        max_search_duration: 6s
+`
+	defaults := Overrides{
+		Global:    GlobalOverrides{MaxBytesPerTrace: 1},
+		Ingestion: IngestionOverrides{MaxLocalTracesPerUser: 2, BurstSizeBytes: 2, RateLimitBytes: 2},
+	}
+	merged, cleanupMerged := createAndInitializeRuntimeOverridesManager(t, defaults, []byte(perTenantOverrides))
+	defer cleanupMerged()
+
+	assert.Equal(t, 0, merged.MaxBytesPerTrace("tenant-a")) // an explicit zero overrides the default
+	assert.Equal(t, 6*time.Second, merged.MaxSearchDuration("tenant-a"))
+	assert.Equal(t, 4, merged.MaxLocalTracesPerUser("tenant-a"))
+	assert.Equal(t, 5, merged.IngestionBurstSizeBytes("tenant-a"))
+	assert.Equal(t, float64(2), merged.IngestionRateLimitBytes("tenant-a"))
+	assert.Equal(t, "tenant-a, group premium, *", RuntimeOverridesSource(merged, "tenant-a"))
+
+	assert.Equal(t, 1, merged.MaxBytesPerTrace("dev-1"))
+	assert.Equal(t, 3, merged.MaxLocalTracesPerUser("dev-1"))
+	assert.Equal(t, 5, merged.IngestionBurstSizeBytes("dev-1"))
+
+	// a tenant can only be in one group
+	loader := loadPerTenantOverrides(nil, ConfigTypeNew, false)
+	_, err := loader(bytes.NewReader([]byte(`
+overrides: {}
+override_groups:
+  a:
+    tenants: [tenant-a]
+    overrides: {}
+  b:
+    tenants: [tenant-a]
+    overrides: {}
+`)))
+	assert.ErrorContains(t, err, "tenant tenant-a is in override groups")
+
+	// without the wildcard the defaults apply
+	overrides, cleanup = createAndInitializeRuntimeOverridesManager(t, Overrides{Global: GlobalOverrides{MaxBytesPerTrace: 1}}, []byte(`
+overrides:
+  dev-*:
+    global:
+      max_bytes_per_trace: 3
+`))
+	defer cleanup()
+	assert.Equal(t, 1, overrides.MaxBytesPerTrace("other"))
+	assert.Equal(t, "default overrides", RuntimeOverridesSource(overrides, "other"))
+}
+
 func createAndInitializeRuntimeOverridesManager(t *testing.T, defaultLimits Overrides, perTenantOverrides []byte) (Service, func()) {
 	cfg := Config{
 		Defaults: defaultLimits,
@@ -50,6 +50,25 @@ type UserConfigurableOverridesAPIConfig struct {
 	// user-configurable overrides requests will still be allowed.
 	// This check can be ignored by the caller by setting the query parameter skip-conflicting-overrides-check=true
 	CheckForConflictingRuntimeOverrides bool `yaml:"check_for_conflicting_runtime_overrides"`
+
+	// Bounds are the ranges within which tenants can set the limits of the compaction and of the traces. Limits
+	// without a maximum can't be set through the API.
+	Bounds UserConfigurableOverridesBounds `yaml:"bounds"`
+}
+
+type UserConfigurableOverridesBounds struct {
+	BlockRetention   DurationBounds `yaml:"block_retention"`
+	MaxBytesPerTrace IntBounds      `yaml:"max_bytes_per_trace"`
+}
+
+type DurationBounds struct {
+	Min time.Duration `yaml:"min"`
+	Max time.Duration `yaml:"max"`
+}
+
+type IntBounds struct {
+	Min int `yaml:"min"`
+	Max int `yaml:"max"`
 }
 
 func (cfg *UserConfigurableOverridesConfig) RegisterFlagsAndApplyDefaults(f *flag.FlagSet) {
@@ -86,6 +105,7 @@ type userConfigurableOverridesManager struct {
 var (
 	_ Service   = (*userConfigurableOverridesManager)(nil)
 	_ Interface = (*userConfigurableOverridesManager)(nil)
+	_ Reloader  = (*userConfigurableOverridesManager)(nil)
 )
 
 // newUserConfigOverrides wraps the given overrides with user-configurable overrides.
@@ -150,6 +170,15 @@ func (o *userConfigurableOverridesManager) stopping(error) error {
 	return services.StopManagerAndAwaitStopped(context.Background(), o.subservices)
 }
 
+// ReloadPerTenantOverrides passes the reload to the wrapped overrides
+func (o *userConfigurableOverridesManager) ReloadPerTenantOverrides(path string, period time.Duration) error {
+	r, ok := o.Interface.(Reloader)
+	if !ok {
+		return errors.New("wrapped overrides do not support reloading")
+	}
+	return r.ReloadPerTenantOverrides(path, period)
+}
+
 func (o *userConfigurableOverridesManager) reloadAllTenantLimits(ctx context.Context) error {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "userConfigurableOverridesManager.reloadAllTenantLimits")
 	defer span.Finish()
@@ -259,6 +288,13 @@ func (o *userConfigurableOverridesManager) MetricsGeneratorProcessorServiceGraph
 	return o.Interface.MetricsGeneratorProcessorServiceGraphsEnableClientServerPrefix(userID)
 }
 
+func (o *userConfigurableOverridesManager) MetricsGeneratorProcessorServiceGraphsEnableMessagingSystemLatencyHistogram(userID string) bool {
+	if enableMessagingSystemLatencyHistogram, ok := o.getTenantLimits(userID).GetMetricsGenerator().GetProcessor().GetServiceGraphs().GetEnableMessagingSystemLatencyHistogram(); ok {
+		return enableMessagingSystemLatencyHistogram
+	}
+	return o.Interface.MetricsGeneratorProcessorServiceGraphsEnableMessagingSystemLatencyHistogram(userID)
+}
+
 func (o *userConfigurableOverridesManager) MetricsGeneratorProcessorServiceGraphsEnableVirtualNodeLabel(userID string) bool {
 	if enableVirtualNodeLabel, ok := o.getTenantLimits(userID).GetMetricsGenerator().GetProcessor().GetServiceGraphs().GetEnableVirtualNodeLabel(); ok {
 		return enableVirtualNodeLabel
@@ -315,6 +351,20 @@ func (o *userConfigurableOverridesManager) MetricsGeneratorProcessorSpanMetricsT
 	return o.Interface.MetricsGeneratorProcessorSpanMetricsTargetInfoExcludedDimensions(userID)
 }
 
+func (o *userConfigurableOverridesManager) BlockRetention(userID string) time.Duration {
+	if blockRetention, ok := o.getTenantLimits(userID).GetCompaction().GetBlockRetention(); ok {
+		return blockRetention
+	}
+	return o.Interface.BlockRetention(userID)
+}
+
+func (o *userConfigurableOverridesManager) MaxBytesPerTrace(userID string) int {
+	if maxBytesPerTrace, ok := o.getTenantLimits(userID).GetGlobal().GetMaxBytesPerTrace(); ok {
+		return maxBytesPerTrace
+	}
+	return o.Interface.MaxBytesPerTrace(userID)
+}
+
 // statusUserConfigurableOverrides used to marshal userconfigurableoverrides.Limits for tenants
 type statusUserConfigurableOverrides struct {
 	TenantLimits tenantLimits `yaml:"user_configurable_overrides" json:"user_configurable_overrides"`
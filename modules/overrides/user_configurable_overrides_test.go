@@ -92,6 +92,8 @@ func TestUserConfigOverridesManager_allFields(t *testing.T) {
 	assert.Empty(t, mgr.MetricsGeneratorProcessorSpanMetricsFilterPolicies(tenant1))
 	assert.Empty(t, mgr.MetricsGeneratorProcessorSpanMetricsHistogramBuckets(tenant1))
 	assert.Empty(t, mgr.MetricsGeneratorProcessorSpanMetricsTargetInfoExcludedDimensions(tenant1))
+	assert.Equal(t, 0*time.Second, mgr.BlockRetention(tenant1))
+	assert.Equal(t, 0, mgr.MaxBytesPerTrace(tenant1))
 
 	// Inject user-configurable overrides
 	mgr.tenantLimits[tenant1] = &userconfigurableoverrides.Limits{
@@ -138,6 +140,12 @@ func TestUserConfigOverridesManager_allFields(t *testing.T) {
 				},
 			},
 		},
+		Compaction: userconfigurableoverrides.LimitsCompaction{
+			BlockRetention: &userconfigurableoverrides.Duration{Duration: 48 * time.Hour},
+		},
+		Global: userconfigurableoverrides.LimitsGlobal{
+			MaxBytesPerTrace: intPtr(10_000),
+		},
 	}
 
 	// Verify we can get the updated overrides
@@ -154,6 +162,8 @@ func TestUserConfigOverridesManager_allFields(t *testing.T) {
 	assert.Equal(t, true, mgr.MetricsGeneratorProcessorSpanMetricsEnableTargetInfo(tenant1))
 	assert.Equal(t, []float64{10, 20, 30, 40, 50}, mgr.MetricsGeneratorProcessorSpanMetricsHistogramBuckets(tenant1))
 	assert.Equal(t, []string{"some-label"}, mgr.MetricsGeneratorProcessorSpanMetricsTargetInfoExcludedDimensions(tenant1))
+	assert.Equal(t, 48*time.Hour, mgr.BlockRetention(tenant1))
+	assert.Equal(t, 10_000, mgr.MaxBytesPerTrace(tenant1))
 
 	filterPolicies := mgr.MetricsGeneratorProcessorSpanMetricsFilterPolicies(tenant1)
 	assert.NotEmpty(t, filterPolicies)
@@ -370,6 +380,10 @@ func boolPtr(b bool) *bool {
 	return &b
 }
 
+func intPtr(i int) *int {
+	return &i
+}
+
 // TestUserConfigOverridesManager_MergeRuntimeConfig tests that per tenant runtime overrides
 // are loaded correctly when userconfigurableoverrides are enabled
 func TestUserConfigOverridesManager_MergeRuntimeConfig(t *testing.T) {
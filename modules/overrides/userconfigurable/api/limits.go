@@ -32,6 +32,12 @@ func limitsFromOverrides(overrides overrides.Interface, userID string) *client.L
 				},
 			},
 		},
+		Compaction: client.LimitsCompaction{
+			BlockRetention: timePtr(overrides.BlockRetention(userID)),
+		},
+		Global: client.LimitsGlobal{
+			MaxBytesPerTrace: intPtr(overrides.MaxBytesPerTrace(userID)),
+		},
 	}
 }
 
@@ -43,6 +49,10 @@ func timePtr(t time.Duration) *client.Duration {
 	return &client.Duration{Duration: t}
 }
 
+func intPtr(i int) *int {
+	return &i
+}
+
 func strArrPtr(s []string) *[]string {
 	return &s
 }
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/model"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/grafana/tempo/modules/overrides"
@@ -50,6 +51,12 @@ func Test_limitsFromOverrides(t *testing.T) {
 					},
 				},
 			},
+			Compaction: overrides.CompactionOverrides{
+				BlockRetention: model.Duration(48 * time.Hour),
+			},
+			Global: overrides.GlobalOverrides{
+				MaxBytesPerTrace: 10_000,
+			},
 		},
 	}
 	overridesInt, err := overrides.NewOverrides(cfg, nil, prometheus.DefaultRegisterer)
@@ -114,6 +121,12 @@ func Test_limitsFromOverrides(t *testing.T) {
         ]
       }
     }
+  },
+  "compaction": {
+    "block_retention": "48h0m0s"
+  },
+  "global": {
+    "max_bytes_per_trace": 10000
   }
 }`
 	assert.Equal(t, expectedJSON, string(limitsJSON))
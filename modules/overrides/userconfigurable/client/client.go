@@ -22,6 +22,7 @@ import (
 	"github.com/grafana/tempo/tempodb/backend/gcs"
 	"github.com/grafana/tempo/tempodb/backend/local"
 	"github.com/grafana/tempo/tempodb/backend/s3"
+	"github.com/grafana/tempo/tempodb/backend/swift"
 )
 
 const (
@@ -58,6 +59,7 @@ type Config struct {
 	GCS   *gcs.Config         `yaml:"gcs"`
 	S3    *s3.Config          `yaml:"s3"`
 	Azure *azureconfig.Config `yaml:"azure"`
+	Swift *swift.Config       `yaml:"swift"`
 }
 
 func (c *Config) RegisterFlagsAndApplyDefaults(*flag.FlagSet) {
@@ -74,6 +76,8 @@ func (c *Config) RegisterFlagsAndApplyDefaults(*flag.FlagSet) {
 	c.S3.RegisterFlagsAndApplyDefaults("", dummyFlagSet)
 	c.Azure = &azureconfig.Config{}
 	c.Azure.RegisterFlagsAndApplyDefaults("", dummyFlagSet)
+	c.Swift = &swift.Config{}
+	c.Swift.RegisterFlagsAndApplyDefaults("", dummyFlagSet)
 }
 
 // Client is a collection of methods to manage overrides on a backend.
@@ -128,13 +132,15 @@ func initBackend(cfg *Config) (rw backend.VersionedReaderWriter, err error) {
 		rw, err = s3.NewVersionedReaderWriter(cfg.S3)
 	case backend.Azure:
 		rw, err = azure.NewVersionedReaderWriter(cfg.Azure)
+	case backend.Swift:
+		rw, err = swift.NewVersionedReaderWriter(cfg.Swift)
 	default:
 		err = fmt.Errorf("unknown backend %s", cfg.Backend)
 	}
 	if err != nil {
 		return nil, err
 	}
-	if cfg.Backend == backend.Local || cfg.Backend == backend.S3 || cfg.Backend == backend.Azure {
+	if cfg.Backend == backend.Local || cfg.Backend == backend.S3 || cfg.Backend == backend.Azure || cfg.Backend == backend.Swift {
 		level.Warn(log.Logger).Log(
 			"msg", "versioned backend requests are best-effort for the configured backend, concurrent requests modifying user-configurable overrides might cause data races",
 			"backend", cfg.Backend,
@@ -11,6 +11,9 @@ type Limits struct {
 	Forwarders *[]string `yaml:"forwarders,omitempty" json:"forwarders,omitempty"`
 
 	MetricsGenerator LimitsMetricsGenerator `yaml:"metrics_generator,omitempty" json:"metrics_generator,omitempty"`
+
+	Compaction LimitsCompaction `yaml:"compaction,omitempty" json:"compaction,omitempty"`
+	Global     LimitsGlobal     `yaml:"global,omitempty" json:"global,omitempty"`
 }
 
 func (l *Limits) GetForwarders() ([]string, bool) {
@@ -27,6 +30,42 @@ func (l *Limits) GetMetricsGenerator() *LimitsMetricsGenerator {
 	return nil
 }
 
+func (l *Limits) GetCompaction() *LimitsCompaction {
+	if l != nil {
+		return &l.Compaction
+	}
+	return nil
+}
+
+func (l *Limits) GetGlobal() *LimitsGlobal {
+	if l != nil {
+		return &l.Global
+	}
+	return nil
+}
+
+type LimitsCompaction struct {
+	BlockRetention *Duration `yaml:"block_retention,omitempty" json:"block_retention,omitempty"`
+}
+
+func (l *LimitsCompaction) GetBlockRetention() (time.Duration, bool) {
+	if l != nil && l.BlockRetention != nil {
+		return l.BlockRetention.Duration, true
+	}
+	return 0, false
+}
+
+type LimitsGlobal struct {
+	MaxBytesPerTrace *int `yaml:"max_bytes_per_trace,omitempty" json:"max_bytes_per_trace,omitempty"`
+}
+
+func (l *LimitsGlobal) GetMaxBytesPerTrace() (int, bool) {
+	if l != nil && l.MaxBytesPerTrace != nil {
+		return *l.MaxBytesPerTrace, true
+	}
+	return 0, false
+}
+
 type LimitsMetricsGenerator struct {
 	Processors         listtomap.ListToMap `yaml:"processors,omitempty" json:"processors,omitempty"`
 	DisableCollection  *bool               `yaml:"disable_collection,omitempty" json:"disable_collection,omitempty"`
@@ -105,6 +144,13 @@ func (l *LimitsMetricsGeneratorProcessorServiceGraphs) GetEnableClientServerPref
 	return false, false
 }
 
+func (l *LimitsMetricsGeneratorProcessorServiceGraphs) GetEnableMessagingSystemLatencyHistogram() (bool, bool) {
+	if l != nil && l.EnableMessagingSystemLatencyHistogram != nil {
+		return *l.EnableMessagingSystemLatencyHistogram, true
+	}
+	return false, false
+}
+
 func (l *LimitsMetricsGeneratorProcessorServiceGraphs) GetEnableVirtualNodeLabel() (bool, bool) {
 	if l != nil && l.EnableVirtualNodeLabel != nil {
 		return *l.EnableVirtualNodeLabel, true
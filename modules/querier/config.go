@@ -26,6 +26,7 @@ type Config struct {
 	SecondaryIngesterRing                  string        `yaml:"secondary_ingester_ring,omitempty"`
 
 	AutocompleteFilteringEnabled bool `yaml:"-"`
+	TraceTombstonesEnabled       bool `yaml:"-"`
 }
 
 type SearchConfig struct {
@@ -34,6 +35,9 @@ type SearchConfig struct {
 	HedgeRequestsAt   time.Duration `yaml:"external_hedge_requests_at"`
 	HedgeRequestsUpTo int           `yaml:"external_hedge_requests_up_to"`
 
+	// if true, search block requests that fail on an external endpoint are searched in the querier
+	ExternalFallbackToSelf bool `yaml:"external_fallback_to_self"`
+
 	// backends
 	ExternalBackend string                   `yaml:"external_backend"`
 	CloudRun        *external.CloudRunConfig `yaml:"google_cloud_run"`
@@ -45,6 +49,16 @@ type SearchConfig struct {
 
 type TraceByIDConfig struct {
 	QueryTimeout time.Duration `yaml:"query_timeout"`
+
+	// if true, traces are received from ingesters in chunks so they aren't limited by the grpc max message size
+	StreamFromIngesters bool `yaml:"stream_from_ingesters"`
+
+	// max number of blocks searched in parallel by a single trace by id job. 0 only limits by the storage pool
+	MaxConcurrentBlockQueries int `yaml:"max_concurrent_block_queries"`
+
+	// stop searching blocks once the ingesters answered and the combined trace has a root span and all its spans are
+	// connected. missing leaf spans in the blocks that weren't searched yet are not returned
+	StopOnCompleteTrace bool `yaml:"stop_on_complete_trace"`
 }
 
 type MetricsConfig struct {
@@ -62,6 +76,7 @@ type MetricsConfig struct {
 // RegisterFlagsAndApplyDefaults register flags.
 func (cfg *Config) RegisterFlagsAndApplyDefaults(prefix string, f *flag.FlagSet) {
 	cfg.TraceByID.QueryTimeout = 10 * time.Second
+	cfg.TraceByID.StreamFromIngesters = true
 	cfg.QueryRelevantIngesters = false
 	cfg.ExtraQueryDelay = 0
 	cfg.MaxConcurrentQueries = 20
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"sort"
 	"sync"
@@ -23,6 +24,8 @@ import (
 	"go.uber.org/atomic"
 	"go.uber.org/multierr"
 	"golang.org/x/sync/semaphore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	generator_client "github.com/grafana/tempo/modules/generator/client"
 	ingester_client "github.com/grafana/tempo/modules/ingester/client"
@@ -39,6 +42,7 @@ import (
 	"github.com/grafana/tempo/pkg/util"
 	"github.com/grafana/tempo/pkg/util/log"
 	"github.com/grafana/tempo/pkg/validation"
+	"github.com/grafana/tempo/tempodb"
 	"github.com/grafana/tempo/tempodb/backend"
 	"github.com/grafana/tempo/tempodb/encoding/common"
 )
@@ -54,6 +58,16 @@ var (
 		Name:      "querier_metrics_generator_clients",
 		Help:      "The current number of generator clients.",
 	})
+	metricExternalEndpointFallbacks = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "tempo",
+		Name:      "querier_external_endpoint_fallbacks_total",
+		Help:      "Total number of search block requests that failed on an external endpoint and were searched in the querier.",
+	})
+	metricTraceByIDStoppedEarly = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "tempo",
+		Name:      "querier_trace_by_id_stopped_early_total",
+		Help:      "Total number of trace by id lookups that stopped searching blocks before all of them were searched.",
+	}, []string{"stop"})
 )
 
 // Querier handlers queries.
@@ -76,10 +90,19 @@ type Querier struct {
 
 	searchPreferSelf *semaphore.Weighted
 
+	// localIngester returns the ingester running in the same process, or nil if there is none
+	localIngester func() LocalIngester
+
 	subservices        *services.Manager
 	subservicesWatcher *services.FailureWatcher
 }
 
+// LocalIngester is an ingester running in the same process as the querier
+type LocalIngester interface {
+	tempopb.QuerierServer
+	Addr() string
+}
+
 type responseFromIngesters struct {
 	addr     string
 	response interface{}
@@ -100,7 +123,12 @@ func New(
 	store storage.Store,
 	limits overrides.Interface,
 ) (*Querier, error) {
+	var q *Querier
+
 	var ingesterClientFactory ring_client.PoolAddrFunc = func(addr string) (ring_client.PoolClient, error) {
+		if local := q.localIngesterFor(addr); local != nil {
+			return ingester_client.NewLocal(local), nil
+		}
 		return ingester_client.New(addr, ingesterClientConfig)
 	}
 
@@ -134,7 +162,7 @@ func New(
 		ingesterPools = append(ingesterPools, pool)
 	}
 
-	q := &Querier{
+	q = &Querier{
 		cfg:           cfg,
 		ingesterRings: ingesterRings,
 		ingesterPools: ingesterPools,
@@ -156,6 +184,23 @@ func New(
 	return q, nil
 }
 
+// SetLocalIngester makes the querier call the ingester running in the same process directly instead of over grpc.
+// It must be called before the querier is started.
+func (q *Querier) SetLocalIngester(localIngester func() LocalIngester) {
+	q.localIngester = localIngester
+}
+
+func (q *Querier) localIngesterFor(addr string) LocalIngester {
+	if q.localIngester == nil {
+		return nil
+	}
+	local := q.localIngester()
+	if local == nil || local.Addr() != addr {
+		return nil
+	}
+	return local
+}
+
 func (q *Querier) CreateAndRegisterWorker(handler http.Handler) error {
 	q.cfg.Worker.MaxConcurrentRequests = q.cfg.MaxConcurrentQueries
 	worker, err := worker.NewQuerierWorker(
@@ -220,8 +265,9 @@ func (q *Querier) stopping(_ error) error {
 	return nil
 }
 
-// FindTraceByID implements tempopb.Querier.
-func (q *Querier) FindTraceByID(ctx context.Context, req *tempopb.TraceByIDRequest, timeStart int64, timeEnd int64) (*tempopb.TraceByIDResponse, error) {
+// FindTraceByID implements tempopb.Querier. stop is the criterion to stop searching the blocks early, one of the
+// api.TraceByIDStop values. If it's empty the criterion of the config is used.
+func (q *Querier) FindTraceByID(ctx context.Context, req *tempopb.TraceByIDRequest, timeStart int64, timeEnd int64, stop string) (*tempopb.TraceByIDResponse, error) {
 	if !validation.ValidTraceID(req.TraceID) {
 		return nil, errors.New("invalid trace id")
 	}
@@ -234,85 +280,264 @@ func (q *Querier) FindTraceByID(ctx context.Context, req *tempopb.TraceByIDReque
 	span, ctx := opentracing.StartSpanFromContext(ctx, "Querier.FindTraceByID")
 	defer span.Finish()
 
+	// deleted traces are not found even if they are still held by the ingesters or the blocks
+	if q.cfg.TraceTombstonesEnabled {
+		tombstoned, err := q.store.TraceTombstoned(ctx, userID, req.TraceID)
+		if err != nil {
+			return nil, fmt.Errorf("error reading trace tombstones in Querier.FindTraceByID: %w", err)
+		}
+		if tombstoned {
+			span.LogFields(ot_log.String("msg", "trace is tombstoned"))
+			return &tempopb.TraceByIDResponse{}, nil
+		}
+	}
+
+	if stop == "" {
+		stop = api.TraceByIDStopNever
+		if q.cfg.TraceByID.StopOnCompleteTrace {
+			stop = api.TraceByIDStopComplete
+		}
+	}
+
 	span.SetTag("queryMode", req.QueryMode)
+	span.SetTag("stop", stop)
 
 	maxBytes := q.limits.MaxBytesPerTrace(userID)
-	combiner := trace.NewCombiner(maxBytes)
-
-	var spanCount, spanCountTotal, traceCountTotal int
-	if req.QueryMode == QueryModeIngesters || req.QueryMode == QueryModeAll {
-		var getRSFn replicationSetFn
-		if q.cfg.QueryRelevantIngesters {
-			traceKey := util.TokenFor(userID, req.TraceID)
-			getRSFn = func(r ring.ReadRing) (ring.ReplicationSet, error) {
-				return r.Get(traceKey, ring.Read, nil, nil, nil)
-			}
-		}
+	combiner := newTraceByIDCombiner(maxBytes)
 
-		// get responses from all ingesters in parallel
-		span.LogFields(ot_log.String("msg", "searching ingesters"))
-		responses, err := q.forIngesterRings(ctx, userID, getRSFn, func(funcCtx context.Context, client tempopb.QuerierClient) (interface{}, error) {
-			return client.FindTraceByID(funcCtx, req)
-		})
-		if err != nil {
-			return nil, fmt.Errorf("error querying ingesters in Querier.FindTraceByID: %w", err)
-		}
+	// the ingesters and the blocks are searched at the same time and their partial traces are combined as they arrive
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
 
-		found := false
-		for _, r := range responses {
-			t := r.response.(*tempopb.TraceByIDResponse).Trace
-			if t != nil {
-				spanCount, err = combiner.Consume(t)
-				if err != nil {
-					return nil, err
-				}
-
-				spanCountTotal += spanCount
-				traceCountTotal++
-				found = true
+	var (
+		wg          sync.WaitGroup
+		ingesterErr error
+		ingestersCh = make(chan struct{}) // closed once all ingester responses are combined
+	)
+	if req.QueryMode == QueryModeIngesters || req.QueryMode == QueryModeAll {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer close(ingestersCh)
+			ingesterErr = q.findTraceByIDInIngesters(ctx, userID, req, combiner, span)
+			if ingesterErr != nil {
+				cancel() // the lookup fails anyway
 			}
-		}
-		span.LogFields(ot_log.String("msg", "done searching ingesters"),
-			ot_log.Bool("found", found),
-			ot_log.Int("combinedSpans", spanCountTotal),
-			ot_log.Int("combinedTraces", traceCountTotal))
+		}()
+	} else {
+		close(ingestersCh)
 	}
 
-	if req.QueryMode == QueryModeBlocks || req.QueryMode == QueryModeAll {
+	var (
+		searchStore   = req.QueryMode == QueryModeBlocks || req.QueryMode == QueryModeAll
+		partialTraces = atomic.NewInt32(0)
+		blockErrs     []error
+		skippedBlocks uint32
+		storeErr      error
+	)
+	if searchStore {
 		span.LogFields(ot_log.String("msg", "searching store"))
 		span.LogFields(ot_log.String("timeStart", fmt.Sprint(timeStart)))
 		span.LogFields(ot_log.String("timeEnd", fmt.Sprint(timeEnd)))
 
 		opts := common.DefaultSearchOptionsWithMaxBytes(maxBytes)
 		opts.BlockReplicationFactor = backend.DefaultReplicationFactor
-		partialTraces, blockErrs, err := q.store.Find(ctx, userID, req.TraceID, req.BlockStart, req.BlockEnd, timeStart, timeEnd, opts)
-		if err != nil {
-			retErr := fmt.Errorf("error querying store in Querier.FindTraceByID: %w", err)
+		opts.MaxConcurrentBlocks = q.cfg.TraceByID.MaxConcurrentBlockQueries
+
+		blockErrs, storeErr = q.store.FindWithCallback(ctx, userID, req.TraceID, req.BlockStart, req.BlockEnd, timeStart, timeEnd, opts, func(partialTrace *tempopb.Trace) bool {
+			partialTraces.Inc()
+			if err := combiner.consume(partialTrace); err != nil {
+				return true
+			}
+
+			if stop == api.TraceByIDStopNever {
+				return false
+			}
+			// the ingesters hold the most recent spans. the search never stops before they answered
+			select {
+			case <-ingestersCh:
+			default:
+				return false
+			}
+			// the blocks are searched newest first so the first block found holds the most recent version of the
+			// trace in the backend
+			if stop == api.TraceByIDStopFirstBlock || combiner.complete() {
+				metricTraceByIDStoppedEarly.WithLabelValues(stop).Inc()
+				return true
+			}
+			return false
+		})
+	}
+
+	// ingester errors take precedence over the block lookups they cancelled
+	wg.Wait()
+	if ingesterErr != nil {
+		return nil, ingesterErr
+	}
+
+	if searchStore {
+		if storeErr != nil {
+			retErr := fmt.Errorf("error querying store in Querier.FindTraceByID: %w", storeErr)
 			ot_log.Error(retErr)
 			return nil, retErr
 		}
 
-		if len(blockErrs) > 0 {
-			return nil, multierr.Combine(blockErrs...)
+		// the quarantined blocks that may hold the trace are skipped and reported so the trace can be flagged as
+		// partial
+		failedBlocks := blockErrs[:0]
+		for _, err := range blockErrs {
+			if errors.Is(err, tempodb.ErrBlockQuarantined) {
+				skippedBlocks++
+				continue
+			}
+			failedBlocks = append(failedBlocks, err)
+		}
+		if len(failedBlocks) > 0 {
+			return nil, multierr.Combine(failedBlocks...)
 		}
 
 		span.LogFields(
 			ot_log.String("msg", "done searching store"),
-			ot_log.Int("foundPartialTraces", len(partialTraces)))
+			ot_log.Int("foundPartialTraces", int(partialTraces.Load())))
+	}
 
-		for _, partialTrace := range partialTraces {
-			_, err = combiner.Consume(partialTrace)
-			if err != nil {
-				return nil, err
+	completeTrace, err := combiner.result()
+	if err != nil {
+		return nil, err
+	}
+
+	return &tempopb.TraceByIDResponse{
+		Trace:   completeTrace,
+		Metrics: &tempopb.TraceByIDMetrics{SkippedBlocks: skippedBlocks},
+	}, nil
+}
+
+// findTraceByIDInIngesters searches all ingesters in parallel and combines their responses.
+func (q *Querier) findTraceByIDInIngesters(ctx context.Context, userID string, req *tempopb.TraceByIDRequest, combiner *traceByIDCombiner, span opentracing.Span) error {
+	var getRSFn replicationSetFn
+	if q.cfg.QueryRelevantIngesters {
+		traceKey := util.TokenFor(userID, req.TraceID)
+		getRSFn = func(r ring.ReadRing) (ring.ReplicationSet, error) {
+			return r.Get(traceKey, ring.Read, nil, nil, nil)
+		}
+	}
+
+	// get responses from all ingesters in parallel
+	span.LogFields(ot_log.String("msg", "searching ingesters"))
+	responses, err := q.forIngesterRings(ctx, userID, getRSFn, func(funcCtx context.Context, client tempopb.QuerierClient) (interface{}, error) {
+		return q.findTraceByIDInIngester(funcCtx, client, req)
+	})
+	if err != nil {
+		return fmt.Errorf("error querying ingesters in Querier.FindTraceByID: %w", err)
+	}
+
+	found := false
+	traceCountTotal := 0
+	for _, r := range responses {
+		t := r.response.(*tempopb.TraceByIDResponse).Trace
+		if t != nil {
+			if err := combiner.consume(t); err != nil {
+				return err
 			}
+
+			traceCountTotal++
+			found = true
 		}
 	}
+	span.LogFields(ot_log.String("msg", "done searching ingesters"),
+		ot_log.Bool("found", found),
+		ot_log.Int("combinedTraces", traceCountTotal))
+
+	return nil
+}
+
+// traceByIDCombiner combines the partial traces found in the ingesters and blocks. It is safe for concurrent use.
+type traceByIDCombiner struct {
+	mtx          sync.Mutex
+	combiner     *trace.Combiner
+	completeness *trace.Completeness
+	err          error
+}
+
+func newTraceByIDCombiner(maxBytes int) *traceByIDCombiner {
+	return &traceByIDCombiner{
+		combiner:     trace.NewCombiner(maxBytes),
+		completeness: trace.NewCompleteness(),
+	}
+}
+
+func (c *traceByIDCombiner) consume(tr *tempopb.Trace) error {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if c.err != nil {
+		return c.err
+	}
+	// the combiner takes over the partial trace, so its spans are tracked first
+	c.completeness.Add(tr)
+	_, c.err = c.combiner.Consume(tr)
+	return c.err
+}
+
+// complete returns true if the combined trace has a root span and all its spans are connected
+func (c *traceByIDCombiner) complete() bool {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	return c.err == nil && c.completeness.IsComplete()
+}
+
+func (c *traceByIDCombiner) result() (*tempopb.Trace, error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if c.err != nil {
+		return nil, c.err
+	}
+	tr, _ := c.combiner.Result()
+	return tr, nil
+}
+
+// findTraceByIDInIngester finds the trace in an ingester. If streaming is enabled the trace is received in chunks and
+// reassembled so traces larger than the grpc max message size can be returned. It falls back to the unary rpc for
+// ingesters that don't support streaming.
+func (q *Querier) findTraceByIDInIngester(ctx context.Context, client tempopb.QuerierClient, req *tempopb.TraceByIDRequest) (*tempopb.TraceByIDResponse, error) {
+	if !q.cfg.TraceByID.StreamFromIngesters {
+		return client.FindTraceByID(ctx, req)
+	}
 
-	completeTrace, _ := combiner.Result()
+	stream, err := client.FindTraceByIDStream(ctx, req)
+	if status.Code(err) == codes.Unimplemented {
+		return client.FindTraceByID(ctx, req)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var tr *tempopb.Trace
+	for {
+		resp, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if status.Code(err) == codes.Unimplemented {
+			return client.FindTraceByID(ctx, req)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if resp.Trace == nil {
+			continue
+		}
+		if tr == nil {
+			tr = &tempopb.Trace{}
+		}
+		tr.Batches = append(tr.Batches, resp.Trace.Batches...)
+	}
 
 	return &tempopb.TraceByIDResponse{
-		Trace:   completeTrace,
-		Metrics: &tempopb.TraceByIDMetrics{},
+		Trace: tr,
 	}, nil
 }
 
@@ -475,7 +700,7 @@ func (q *Querier) SearchRecent(ctx context.Context, req *tempopb.SearchRequest)
 		return nil, fmt.Errorf("error querying ingesters in Querier.Search: %w", err)
 	}
 
-	return q.postProcessIngesterSearchResults(req, responses), nil
+	return q.filterTombstonedTraces(ctx, userID, q.postProcessIngesterSearchResults(req, responses))
 }
 
 func (q *Querier) SearchTagsBlocks(ctx context.Context, req *tempopb.SearchTagsBlockRequest) (*tempopb.SearchTagsResponse, error) {
@@ -594,8 +819,11 @@ func (q *Querier) SearchTagValues(ctx context.Context, req *tempopb.SearchTagVal
 	if err != nil {
 		return nil, fmt.Errorf("error querying ingesters in Querier.SearchTagValues: %w", err)
 	}
+	partial := false
 	for _, resp := range lookupResults {
-		for _, res := range resp.response.(*tempopb.SearchTagValuesResponse).TagValues {
+		ingesterResp := resp.response.(*tempopb.SearchTagValuesResponse)
+		partial = partial || ingesterResp.Partial
+		for _, res := range ingesterResp.TagValues {
 			distinctValues.Collect(res)
 		}
 	}
@@ -606,6 +834,7 @@ func (q *Querier) SearchTagValues(ctx context.Context, req *tempopb.SearchTagVal
 
 	resp := &tempopb.SearchTagValuesResponse{
 		TagValues: distinctValues.Strings(),
+		Partial:   partial || distinctValues.Exceeded(),
 	}
 
 	return resp, nil
@@ -639,8 +868,11 @@ func (q *Querier) SearchTagValuesV2(ctx context.Context, req *tempopb.SearchTagV
 	if err != nil {
 		return nil, fmt.Errorf("error querying ingesters in Querier.SearchTagValues: %w", err)
 	}
+	partial := false
 	for _, resp := range lookupResults {
-		for _, res := range resp.response.(*tempopb.SearchTagValuesV2Response).TagValues {
+		ingesterResp := resp.response.(*tempopb.SearchTagValuesV2Response)
+		partial = partial || ingesterResp.Partial
+		for _, res := range ingesterResp.TagValues {
 			distinctValues.Collect(*res)
 		}
 	}
@@ -649,7 +881,9 @@ func (q *Querier) SearchTagValuesV2(ctx context.Context, req *tempopb.SearchTagV
 		level.Warn(log.Logger).Log("msg", "size of tag values in instance exceeded limit, reduce cardinality or size of tags", "tag", req.TagName, "userID", userID, "limit", limit, "total", distinctValues.TotalDataSize())
 	}
 
-	return valuesToV2Response(distinctValues), nil
+	resp := valuesToV2Response(distinctValues)
+	resp.Partial = resp.Partial || partial
+	return resp, nil
 }
 
 func (q *Querier) SpanMetricsSummary(
@@ -738,12 +972,47 @@ func (q *Querier) SpanMetricsSummary(
 	for _, x := range xxx {
 		resp.Summaries = append(resp.Summaries, x)
 	}
+	setSpanMetricsRates(resp.Summaries, results)
 
 	return resp, nil
 }
 
+// setSpanMetricsRates sets the rates of the summaries. The span rate is over the time range covered by the data the
+// generators searched, which can be shorter than the requested time range.
+func setSpanMetricsRates(summaries []*tempopb.SpanMetricsSummary, results []*tempopb.SpanMetricsResponse) {
+	var start, end uint32
+	for _, r := range results {
+		if r.End == 0 {
+			continue
+		}
+		if end == 0 {
+			start, end = r.Start, r.End
+			continue
+		}
+		start = min(start, r.Start)
+		end = max(end, r.End)
+	}
+
+	var seconds float64
+	if end > 0 {
+		// data within a single second is rated over one second
+		seconds = max(float64(end-start), 1)
+	}
+
+	for _, x := range summaries {
+		if seconds > 0 {
+			x.Rate = float64(x.SpanCount) / seconds
+		}
+		if x.SpanCount > 0 {
+			x.ErrorRate = float64(x.ErrorSpanCount) / float64(x.SpanCount)
+		}
+	}
+}
+
 func valuesToV2Response(distinctValues *util.DistinctValueCollector[tempopb.TagValue]) *tempopb.SearchTagValuesV2Response {
-	resp := &tempopb.SearchTagValuesV2Response{}
+	resp := &tempopb.SearchTagValuesV2Response{
+		Partial: distinctValues.Exceeded(),
+	}
 	for _, v := range distinctValues.Values() {
 		v2 := v
 		resp.TagValues = append(resp.TagValues, &v2)
@@ -754,6 +1023,19 @@ func valuesToV2Response(distinctValues *util.DistinctValueCollector[tempopb.TagV
 
 // SearchBlock searches the specified subset of the block for the passed tags.
 func (q *Querier) SearchBlock(ctx context.Context, req *tempopb.SearchBlockRequest) (*tempopb.SearchResponse, error) {
+	tenantID, err := user.ExtractOrgID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error extracting org id in Querier.SearchBlock: %w", err)
+	}
+
+	resp, err := q.searchBlock(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return q.filterTombstonedTraces(ctx, tenantID, resp)
+}
+
+func (q *Querier) searchBlock(ctx context.Context, req *tempopb.SearchBlockRequest) (*tempopb.SearchResponse, error) {
 	// if we have no external configuration always search in the querier
 	if q.cfg.Search.ExternalBackend == "" && len(q.cfg.Search.ExternalEndpoints) == 0 {
 		return q.internalSearchBlock(ctx, req)
@@ -772,7 +1054,40 @@ func (q *Querier) SearchBlock(ctx context.Context, req *tempopb.SearchBlockReque
 	}
 	maxBytes := q.limits.MaxBytesPerTrace(tenantID)
 
-	return q.externalClient.Search(ctx, maxBytes, req)
+	resp, err := q.externalClient.Search(ctx, maxBytes, req)
+	if err != nil && q.cfg.Search.ExternalFallbackToSelf && ctx.Err() == nil {
+		// the external endpoint is unavailable or overloaded. search locally instead of failing the job
+		metricExternalEndpointFallbacks.Inc()
+		return q.internalSearchBlock(ctx, req)
+	}
+
+	return resp, err
+}
+
+// filterTombstonedTraces removes the deleted traces from the search results. The blocks still hold them until the
+// compactors rewrite the blocks.
+func (q *Querier) filterTombstonedTraces(ctx context.Context, tenantID string, resp *tempopb.SearchResponse) (*tempopb.SearchResponse, error) {
+	if !q.cfg.TraceTombstonesEnabled || resp == nil || len(resp.Traces) == 0 {
+		return resp, nil
+	}
+
+	traces := resp.Traces[:0]
+	for _, tr := range resp.Traces {
+		traceID, err := util.HexStringToTraceID(tr.TraceID)
+		if err != nil {
+			traces = append(traces, tr)
+			continue
+		}
+		tombstoned, err := q.store.TraceTombstoned(ctx, tenantID, traceID)
+		if err != nil {
+			return nil, fmt.Errorf("error reading trace tombstones: %w", err)
+		}
+		if !tombstoned {
+			traces = append(traces, tr)
+		}
+	}
+	resp.Traces = traces
+	return resp, nil
 }
 
 func (q *Querier) internalSearchBlock(ctx context.Context, req *tempopb.SearchBlockRequest) (*tempopb.SearchResponse, error) {
@@ -814,15 +1129,21 @@ func (q *Querier) internalSearchBlock(ctx context.Context, req *tempopb.SearchBl
 	opts.TotalPages = int(req.PagesToSearch)
 	opts.MaxBytes = q.limits.MaxBytesPerTrace(tenantID)
 
+	var resp *tempopb.SearchResponse
 	if api.IsTraceQLQuery(req.SearchReq) {
 		fetcher := traceql.NewSpansetFetcherWrapper(func(ctx context.Context, req traceql.FetchSpansRequest) (traceql.FetchSpansResponse, error) {
 			return q.store.Fetch(ctx, meta, req, opts)
 		})
 
-		return q.engine.ExecuteSearch(ctx, req.SearchReq, fetcher)
+		resp, err = q.engine.ExecuteSearch(ctx, req.SearchReq, fetcher)
+	} else {
+		resp, err = q.store.Search(ctx, meta, req.SearchReq, opts)
 	}
-
-	return q.store.Search(ctx, meta, req.SearchReq, opts)
+	// quarantined blocks are counted so the frontend can report the results as partial
+	if errors.Is(err, tempodb.ErrBlockQuarantined) {
+		return &tempopb.SearchResponse{Metrics: &tempopb.SearchMetrics{SkippedBlocks: 1}}, nil
+	}
+	return resp, err
 }
 
 func (q *Querier) internalTagsSearchBlock(ctx context.Context, req *tempopb.SearchTagsBlockRequest) (*tempopb.SearchTagsResponse, error) {
@@ -870,7 +1191,11 @@ func (q *Querier) internalTagsSearchBlock(ctx context.Context, req *tempopb.Sear
 	opts.StartPage = int(req.StartPage)
 	opts.TotalPages = int(req.PagesToSearch)
 
-	return q.store.SearchTags(ctx, meta, req.SearchReq.Scope, opts)
+	resp, err := q.store.SearchTags(ctx, meta, req.SearchReq.Scope, opts)
+	if errors.Is(err, tempodb.ErrBlockQuarantined) {
+		return &tempopb.SearchTagsResponse{}, nil
+	}
+	return resp, err
 }
 
 func (q *Querier) internalTagsSearchBlockV2(ctx context.Context, req *tempopb.SearchTagsBlockRequest) (*tempopb.SearchTagsV2Response, error) {
@@ -964,6 +1289,9 @@ func (q *Querier) internalTagValuesSearchBlock(ctx context.Context, req *tempopb
 	opts.TotalPages = int(req.PagesToSearch)
 
 	values, err := q.store.SearchTagValues(ctx, meta, req.SearchReq.TagName, opts)
+	if errors.Is(err, tempodb.ErrBlockQuarantined) {
+		return &tempopb.SearchTagValuesResponse{Partial: true}, nil
+	}
 	if err != nil {
 		return &tempopb.SearchTagValuesResponse{}, err
 	}
@@ -1013,7 +1341,11 @@ func (q *Querier) internalTagValuesSearchBlockV2(ctx context.Context, req *tempo
 
 	query := traceql.ExtractMatchers(req.SearchReq.Query)
 	if !q.cfg.AutocompleteFilteringEnabled || traceql.IsEmptyQuery(query) {
-		return q.store.SearchTagValuesV2(ctx, meta, req.SearchReq, common.DefaultSearchOptions())
+		resp, err := q.store.SearchTagValuesV2(ctx, meta, req.SearchReq, common.DefaultSearchOptions())
+		if errors.Is(err, tempodb.ErrBlockQuarantined) {
+			return &tempopb.SearchTagValuesV2Response{Partial: true}, nil
+		}
+		return resp, err
 	}
 
 	tag, err := traceql.ParseIdentifier(req.SearchReq.TagName)
@@ -1027,6 +1359,9 @@ func (q *Querier) internalTagValuesSearchBlockV2(ctx context.Context, req *tempo
 
 	valueCollector := util.NewDistinctValueCollector(q.limits.MaxBytesPerTagValuesQuery(tenantID), func(v tempopb.TagValue) int { return len(v.Type) + len(v.Value) })
 	err = q.engine.ExecuteTagValues(ctx, tag, query, traceql.MakeCollectTagValueFunc(valueCollector.Collect), fetcher)
+	if errors.Is(err, tempodb.ErrBlockQuarantined) {
+		return &tempopb.SearchTagValuesV2Response{Partial: true}, nil
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -2,22 +2,39 @@ package querier
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"sort"
 	"testing"
 	"time"
 
+	"github.com/gogo/protobuf/proto"
+	"github.com/google/uuid"
 	"github.com/grafana/dskit/user"
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/model"
 	"github.com/stretchr/testify/require"
 	"github.com/uber-go/atomic"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	generator_client "github.com/grafana/tempo/modules/generator/client"
 	ingester_client "github.com/grafana/tempo/modules/ingester/client"
 	"github.com/grafana/tempo/modules/overrides"
 	"github.com/grafana/tempo/modules/querier/external"
+	"github.com/grafana/tempo/modules/storage"
+	"github.com/grafana/tempo/pkg/api"
+	"github.com/grafana/tempo/pkg/model/trace"
 	"github.com/grafana/tempo/pkg/tempopb"
+	v1 "github.com/grafana/tempo/pkg/tempopb/trace/v1"
+	"github.com/grafana/tempo/pkg/util"
+	"github.com/grafana/tempo/pkg/util/test"
+	"github.com/grafana/tempo/tempodb"
+	"github.com/grafana/tempo/tempodb/encoding/common"
 )
 
 func TestQuerierUsesSearchExternalEndpoint(t *testing.T) {
@@ -97,6 +114,44 @@ func TestQuerierUsesSearchExternalEndpoint(t *testing.T) {
 	}
 }
 
+func TestQuerierSearchExternalEndpointFallback(t *testing.T) {
+	numExternalRequests := atomic.NewInt32(0)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		numExternalRequests.Inc()
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	ctx := user.InjectOrgID(context.Background(), "blerg")
+
+	for _, fallback := range []bool{false, true} {
+		numExternalRequests.Store(0)
+
+		o, err := overrides.NewOverrides(overrides.Config{}, nil, prometheus.NewRegistry())
+		require.NoError(t, err)
+
+		q, err := New(Config{
+			Search: SearchConfig{
+				ExternalEndpoints:      []string{srv.URL},
+				ExternalFallbackToSelf: fallback,
+			},
+		}, ingester_client.Config{}, nil, generator_client.Config{}, nil, nil, o)
+		require.NoError(t, err)
+
+		// the local search fails on the empty block id. the error tells us where the request was executed
+		_, err = q.SearchBlock(ctx, &tempopb.SearchBlockRequest{})
+		require.Error(t, err)
+		require.Equal(t, int32(1), numExternalRequests.Load())
+
+		if fallback {
+			require.Contains(t, err.Error(), "invalid UUID")
+		} else {
+			require.Contains(t, err.Error(), "external endpoint returned 500")
+		}
+	}
+}
+
 func TestVirtualTagsDoesntHitBackend(t *testing.T) {
 	o, err := overrides.NewOverrides(overrides.Config{}, nil, prometheus.DefaultRegisterer)
 	require.NoError(t, err)
@@ -184,3 +239,357 @@ func TestVirtualTagsDoesntHitBackend(t *testing.T) {
 	})
 	require.Error(t, err)
 }
+
+func TestQuerierQueryTimeout(t *testing.T) {
+	cfg := overrides.Config{}
+	cfg.Defaults.Read.QueryTimeout = model.Duration(5 * time.Second)
+	o, err := overrides.NewOverrides(cfg, nil, prometheus.NewRegistry())
+	require.NoError(t, err)
+
+	q, err := New(Config{}, ingester_client.Config{}, nil, generator_client.Config{}, nil, nil, o)
+	require.NoError(t, err)
+
+	// the override replaces the configured timeout
+	req := httptest.NewRequest("GET", "/api/search", nil)
+	req = req.WithContext(user.InjectOrgID(req.Context(), "blerg"))
+	require.Equal(t, 5*time.Second, q.queryTimeout(req, 30*time.Second))
+
+	// requests without a tenant use the configured timeout
+	req = httptest.NewRequest("GET", "/api/search", nil)
+	require.Equal(t, 30*time.Second, q.queryTimeout(req, 30*time.Second))
+}
+
+func TestFindTraceByIDInIngester(t *testing.T) {
+	o, err := overrides.NewOverrides(overrides.Config{}, nil, prometheus.NewRegistry())
+	require.NoError(t, err)
+
+	traceID := test.ValidTraceID(nil)
+	expected := test.MakeTrace(3, traceID)
+	// split by batch so the reassembled trace equals the original
+	maxBatchSize := 0
+	for _, b := range expected.Batches {
+		maxBatchSize = max(maxBatchSize, b.Size())
+	}
+	chunks := trace.Split(expected, maxBatchSize)
+	require.Greater(t, len(chunks), 1)
+
+	ctx := context.Background()
+	req := &tempopb.TraceByIDRequest{TraceID: traceID}
+
+	tcs := []struct {
+		name          string
+		stream        bool
+		client        *mockQuerierClient
+		expectedTrace *tempopb.Trace
+		expectedUnary bool
+	}{
+		{
+			name:          "stream",
+			stream:        true,
+			client:        &mockQuerierClient{chunks: chunks},
+			expectedTrace: expected,
+		},
+		{
+			name:   "stream not found",
+			stream: true,
+			client: &mockQuerierClient{},
+		},
+		{
+			name:          "stream unimplemented",
+			stream:        true,
+			client:        &mockQuerierClient{trace: expected, streamErr: status.Error(codes.Unimplemented, "unimplemented")},
+			expectedTrace: expected,
+			expectedUnary: true,
+		},
+		{
+			name:          "unary",
+			client:        &mockQuerierClient{trace: expected},
+			expectedTrace: expected,
+			expectedUnary: true,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			q, err := New(Config{TraceByID: TraceByIDConfig{StreamFromIngesters: tc.stream}}, ingester_client.Config{}, nil, generator_client.Config{}, nil, nil, o)
+			require.NoError(t, err)
+
+			resp, err := q.findTraceByIDInIngester(ctx, tc.client, req)
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedUnary, tc.client.unaryCalled)
+			if tc.expectedTrace == nil {
+				require.Nil(t, resp.Trace)
+				return
+			}
+			require.True(t, proto.Equal(tc.expectedTrace, resp.Trace))
+		})
+	}
+
+	// errors while streaming are returned
+	q, err := New(Config{TraceByID: TraceByIDConfig{StreamFromIngesters: true}}, ingester_client.Config{}, nil, generator_client.Config{}, nil, nil, o)
+	require.NoError(t, err)
+	_, err = q.findTraceByIDInIngester(ctx, &mockQuerierClient{chunks: chunks, streamErr: errors.New("broken stream")}, req)
+	require.EqualError(t, err, "broken stream")
+}
+
+type mockLocalIngester struct {
+	tempopb.QuerierServer
+	addr  string
+	trace *tempopb.Trace
+}
+
+func (m *mockLocalIngester) Addr() string {
+	return m.addr
+}
+
+func (m *mockLocalIngester) FindTraceByID(context.Context, *tempopb.TraceByIDRequest) (*tempopb.TraceByIDResponse, error) {
+	return &tempopb.TraceByIDResponse{Trace: m.trace}, nil
+}
+
+func TestLocalIngester(t *testing.T) {
+	o, err := overrides.NewOverrides(overrides.Config{}, nil, prometheus.NewRegistry())
+	require.NoError(t, err)
+
+	traceID := test.ValidTraceID(nil)
+	expected := test.MakeTrace(3, traceID)
+
+	q, err := New(Config{TraceByID: TraceByIDConfig{StreamFromIngesters: true}}, ingester_client.Config{}, nil, generator_client.Config{}, nil, nil, o)
+	require.NoError(t, err)
+	require.Nil(t, q.localIngesterFor("ingester:9095"))
+
+	// the local ingester isn't initialized yet
+	var local LocalIngester
+	q.SetLocalIngester(func() LocalIngester { return local })
+	require.Nil(t, q.localIngesterFor("ingester:9095"))
+
+	local = &mockLocalIngester{addr: "ingester:9095", trace: expected}
+	require.Nil(t, q.localIngesterFor("other:9095"))
+	require.Equal(t, local, q.localIngesterFor("ingester:9095"))
+
+	// the local client doesn't stream and falls back to the unary call
+	resp, err := q.findTraceByIDInIngester(context.Background(), ingester_client.NewLocal(local), &tempopb.TraceByIDRequest{TraceID: traceID})
+	require.NoError(t, err)
+	require.True(t, proto.Equal(expected, resp.Trace))
+}
+
+type mockStore struct {
+	storage.Store
+	partialTraces []*tempopb.Trace
+	tombstoned    map[string]bool
+	blockErrs     []error
+}
+
+func (m *mockStore) TraceTombstoned(_ context.Context, _ string, traceID common.ID) (bool, error) {
+	return m.tombstoned[util.TraceIDToHexString(traceID)], nil
+}
+
+func (m *mockStore) TraceTombstonesCount(context.Context, string) (int, error) {
+	return len(m.tombstoned), nil
+}
+
+func (m *mockStore) FindWithCallback(_ context.Context, _ string, _ common.ID, _, _ string, _, _ int64, _ common.SearchOptions, fn tempodb.FindTraceFunc) ([]error, error) {
+	for _, tr := range m.partialTraces {
+		if fn(tr) {
+			break
+		}
+	}
+	return m.blockErrs, nil
+}
+
+func TestFindTraceByIDStop(t *testing.T) {
+	o, err := overrides.NewOverrides(overrides.Config{}, nil, prometheus.NewRegistry())
+	require.NoError(t, err)
+
+	ctx := user.InjectOrgID(context.Background(), "blerg")
+	traceID := test.ValidTraceID(nil)
+
+	partialTrace := func(spanID, parentSpanID []byte) *tempopb.Trace {
+		return &tempopb.Trace{Batches: []*v1.ResourceSpans{{ScopeSpans: []*v1.ScopeSpans{{Spans: []*v1.Span{
+			{TraceId: traceID, SpanId: spanID, ParentSpanId: parentSpanID},
+		}}}}}}
+	}
+	spanCount := func(tr *tempopb.Trace) int {
+		count := 0
+		for _, b := range tr.Batches {
+			for _, ss := range b.ScopeSpans {
+				count += len(ss.Spans)
+			}
+		}
+		return count
+	}
+
+	tcs := []struct {
+		name          string
+		cfgStop       bool
+		stop          string
+		expectedSpans int
+	}{
+		{name: "all blocks", expectedSpans: 3},
+		// the trace is complete once the root span is found
+		{name: "stop on complete trace", cfgStop: true, expectedSpans: 2},
+		{name: "request complete", stop: api.TraceByIDStopComplete, expectedSpans: 2},
+		{name: "request never overrides config", cfgStop: true, stop: api.TraceByIDStopNever, expectedSpans: 3},
+		{name: "request first block", stop: api.TraceByIDStopFirstBlock, expectedSpans: 1},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			store := &mockStore{partialTraces: []*tempopb.Trace{
+				partialTrace([]byte{2}, []byte{1}),
+				partialTrace([]byte{1}, nil),
+				partialTrace([]byte{3}, []byte{1}),
+			}}
+
+			q, err := New(Config{TraceByID: TraceByIDConfig{StopOnCompleteTrace: tc.cfgStop}}, ingester_client.Config{}, nil, generator_client.Config{}, nil, store, o)
+			require.NoError(t, err)
+
+			resp, err := q.FindTraceByID(ctx, &tempopb.TraceByIDRequest{TraceID: traceID, QueryMode: QueryModeBlocks}, 0, 0, tc.stop)
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedSpans, spanCount(resp.Trace))
+		})
+	}
+}
+
+func TestFindTraceByIDSkipsQuarantinedBlocks(t *testing.T) {
+	o, err := overrides.NewOverrides(overrides.Config{}, nil, prometheus.NewRegistry())
+	require.NoError(t, err)
+
+	ctx := user.InjectOrgID(context.Background(), "blerg")
+	traceID := test.ValidTraceID(nil)
+
+	store := &mockStore{
+		partialTraces: []*tempopb.Trace{test.MakeTrace(1, traceID)},
+		blockErrs:     []error{fmt.Errorf("blockID: %s: %w", uuid.New(), tempodb.ErrBlockQuarantined)},
+	}
+	q, err := New(Config{}, ingester_client.Config{}, nil, generator_client.Config{}, nil, store, o)
+	require.NoError(t, err)
+
+	// quarantined blocks are reported
+	resp, err := q.FindTraceByID(ctx, &tempopb.TraceByIDRequest{TraceID: traceID, QueryMode: QueryModeBlocks}, 0, 0, "")
+	require.NoError(t, err)
+	require.NotNil(t, resp.Trace)
+	require.Equal(t, uint32(1), resp.Metrics.SkippedBlocks)
+
+	// other block errors fail the lookup
+	store.blockErrs = append(store.blockErrs, errors.New("error"))
+	_, err = q.FindTraceByID(ctx, &tempopb.TraceByIDRequest{TraceID: traceID, QueryMode: QueryModeBlocks}, 0, 0, "")
+	require.Error(t, err)
+}
+
+func TestTombstonedTraces(t *testing.T) {
+	o, err := overrides.NewOverrides(overrides.Config{}, nil, prometheus.NewRegistry())
+	require.NoError(t, err)
+
+	ctx := user.InjectOrgID(context.Background(), "blerg")
+	traceID := test.ValidTraceID(nil)
+
+	store := &mockStore{
+		partialTraces: []*tempopb.Trace{test.MakeTrace(1, traceID)},
+		tombstoned:    map[string]bool{util.TraceIDToHexString(traceID): true},
+	}
+	// tombstones are not checked unless they are enabled
+	q, err := New(Config{}, ingester_client.Config{}, nil, generator_client.Config{}, nil, store, o)
+	require.NoError(t, err)
+	resp, err := q.FindTraceByID(ctx, &tempopb.TraceByIDRequest{TraceID: traceID, QueryMode: QueryModeBlocks}, 0, 0, "")
+	require.NoError(t, err)
+	require.NotNil(t, resp.Trace)
+
+	q, err = New(Config{TraceTombstonesEnabled: true}, ingester_client.Config{}, nil, generator_client.Config{}, nil, store, o)
+	require.NoError(t, err)
+
+	// tombstoned traces are not found
+	resp, err = q.FindTraceByID(ctx, &tempopb.TraceByIDRequest{TraceID: traceID, QueryMode: QueryModeBlocks}, 0, 0, "")
+	require.NoError(t, err)
+	require.Nil(t, resp.Trace)
+
+	// and are filtered from search results
+	search, err := q.filterTombstonedTraces(ctx, "blerg", &tempopb.SearchResponse{Traces: []*tempopb.TraceSearchMetadata{
+		{TraceID: util.TraceIDToHexString(traceID)},
+		{TraceID: "1234"},
+	}})
+	require.NoError(t, err)
+	require.Len(t, search.Traces, 1)
+	require.Equal(t, "1234", search.Traces[0].TraceID)
+}
+
+type mockQuerierClient struct {
+	tempopb.QuerierClient
+
+	trace       *tempopb.Trace
+	chunks      []*tempopb.Trace
+	streamErr   error
+	unaryCalled bool
+}
+
+func (m *mockQuerierClient) FindTraceByID(context.Context, *tempopb.TraceByIDRequest, ...grpc.CallOption) (*tempopb.TraceByIDResponse, error) {
+	m.unaryCalled = true
+	return &tempopb.TraceByIDResponse{Trace: m.trace}, nil
+}
+
+func (m *mockQuerierClient) FindTraceByIDStream(context.Context, *tempopb.TraceByIDRequest, ...grpc.CallOption) (tempopb.Querier_FindTraceByIDStreamClient, error) {
+	return &mockFindTraceByIDStreamClient{chunks: m.chunks, err: m.streamErr}, nil
+}
+
+type mockFindTraceByIDStreamClient struct {
+	grpc.ClientStream
+
+	chunks []*tempopb.Trace
+	err    error
+}
+
+// Recv returns the chunks and then err, or io.EOF if err is nil
+func (m *mockFindTraceByIDStreamClient) Recv() (*tempopb.TraceByIDResponse, error) {
+	if len(m.chunks) == 0 {
+		if m.err != nil {
+			return nil, m.err
+		}
+		return nil, io.EOF
+	}
+
+	chunk := m.chunks[0]
+	m.chunks = m.chunks[1:]
+	return &tempopb.TraceByIDResponse{Trace: chunk}, nil
+}
+
+func TestSetSpanMetricsRates(t *testing.T) {
+	tcs := []struct {
+		name         string
+		results      []*tempopb.SpanMetricsResponse
+		expectedRate float64
+	}{
+		{
+			name:    "no data",
+			results: []*tempopb.SpanMetricsResponse{{}},
+		},
+		{
+			name:         "rate over the covered range",
+			results:      []*tempopb.SpanMetricsResponse{{Start: 100, End: 200}},
+			expectedRate: 1,
+		},
+		{
+			name:         "covered ranges of generators are combined",
+			results:      []*tempopb.SpanMetricsResponse{{Start: 150, End: 200}, {}, {Start: 100, End: 150}},
+			expectedRate: 1,
+		},
+		{
+			name:         "data within a second",
+			results:      []*tempopb.SpanMetricsResponse{{Start: 100, End: 100}},
+			expectedRate: 100,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			summaries := []*tempopb.SpanMetricsSummary{
+				{SpanCount: 100, ErrorSpanCount: 25},
+				{},
+			}
+			setSpanMetricsRates(summaries, tc.results)
+
+			require.Equal(t, tc.expectedRate, summaries[0].Rate)
+			require.Equal(t, 0.25, summaries[0].ErrorRate)
+			require.Zero(t, summaries[1].Rate)
+			require.Zero(t, summaries[1].ErrorRate)
+		})
+	}
+}
@@ -0,0 +1,294 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/google/uuid"
+	"github.com/grafana/dskit/kv"
+	"github.com/grafana/dskit/kv/memberlist"
+	"github.com/grafana/dskit/services"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/grafana/tempo/tempodb"
+	"github.com/grafana/tempo/tempodb/backend"
+)
+
+const (
+	blocklistUpdatesKey = "blocklist-updates"
+
+	blocklistUpdatesTimeout = 10 * time.Second
+)
+
+var (
+	metricBlocklistUpdatesSent = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "tempo",
+		Name:      "blocklist_updates_sent_total",
+		Help:      "Total number of blocklist changes announced by this instance.",
+	})
+	metricBlocklistUpdatesApplied = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "tempo",
+		Name:      "blocklist_updates_applied_total",
+		Help:      "Total number of announced blocklist changes applied to the blocklist of this instance.",
+	})
+	metricBlocklistUpdatesFailed = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "tempo",
+		Name:      "blocklist_updates_failed_total",
+		Help:      "Total number of blocklist changes that couldn't be announced.",
+	})
+)
+
+type BlocklistUpdatesConfig struct {
+	// announces the blocks written, compacted and deleted by the ingesters, generators and compactors through the
+	// kvstore. the queriers apply them to their blocklist within seconds instead of waiting for the next poll
+	Enabled bool      `yaml:"enabled"`
+	KVStore kv.Config `yaml:"kvstore"`
+	// how long an announced change is kept in the kvstore. it should be longer than the blocklist poll
+	TTL time.Duration `yaml:"ttl"`
+}
+
+func (cfg *BlocklistUpdatesConfig) Validate() error {
+	if !cfg.Enabled {
+		return nil
+	}
+	if cfg.TTL <= 0 {
+		return errors.New("blocklist updates ttl must be greater than 0")
+	}
+	return nil
+}
+
+// BlocklistUpdates is the state shared through the kvstore. It holds the most recent change of each block.
+type BlocklistUpdates struct {
+	Changes map[string]BlocklistChange `json:"changes"`
+}
+
+// BlocklistChange is a block that was written, marked compacted or deleted.
+type BlocklistChange struct {
+	TenantID  string                      `json:"tenant_id"`
+	BlockID   uuid.UUID                   `json:"block_id"`
+	UpdatedAt time.Time                   `json:"updated_at"`
+	Added     *backend.BlockMeta          `json:"added,omitempty"`
+	Compacted *backend.CompactedBlockMeta `json:"compacted,omitempty"`
+	Deleted   bool                        `json:"deleted,omitempty"`
+}
+
+func blocklistChangeKey(tenantID string, blockID uuid.UUID) string {
+	return tenantID + "/" + blockID.String()
+}
+
+var _ memberlist.Mergeable = (*BlocklistUpdates)(nil)
+
+// Merge implements the memberlist.Mergeable interface. The most recent change of each block wins.
+func (u *BlocklistUpdates) Merge(mergeable memberlist.Mergeable, _ bool) (memberlist.Mergeable, error) {
+	if mergeable == nil {
+		return nil, nil
+	}
+	other, ok := mergeable.(*BlocklistUpdates)
+	if !ok {
+		return nil, fmt.Errorf("expected *storage.BlocklistUpdates, got %T", mergeable)
+	}
+	if other == nil {
+		return nil, nil
+	}
+
+	if u.Changes == nil {
+		u.Changes = map[string]BlocklistChange{}
+	}
+
+	change := &BlocklistUpdates{Changes: map[string]BlocklistChange{}}
+	for k, c := range other.Changes {
+		if current, ok := u.Changes[k]; ok && !c.UpdatedAt.After(current.UpdatedAt) {
+			continue
+		}
+		u.Changes[k] = c
+		change.Changes[k] = c
+	}
+
+	if len(change.Changes) == 0 {
+		return nil, nil
+	}
+	return change, nil
+}
+
+// MergeContent implements the memberlist.Mergeable interface.
+func (u *BlocklistUpdates) MergeContent() []string {
+	keys := make([]string, 0, len(u.Changes))
+	for k := range u.Changes {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// RemoveTombstones implements the memberlist.Mergeable interface. It removes the changes made before the limit.
+func (u *BlocklistUpdates) RemoveTombstones(limit time.Time) (total, removed int) {
+	for k, c := range u.Changes {
+		if !limit.IsZero() && c.UpdatedAt.Before(limit) {
+			delete(u.Changes, k)
+			removed++
+		}
+	}
+	return 0, removed
+}
+
+// Clone implements the memberlist.Mergeable interface.
+func (u *BlocklistUpdates) Clone() memberlist.Mergeable {
+	clone := &BlocklistUpdates{Changes: make(map[string]BlocklistChange, len(u.Changes))}
+	for k, c := range u.Changes {
+		clone.Changes[k] = c
+	}
+	return clone
+}
+
+var BlocklistUpdatesCodec = blocklistUpdatesCodec{}
+
+type blocklistUpdatesCodec struct{}
+
+func (blocklistUpdatesCodec) Decode(data []byte) (interface{}, error) {
+	var updates BlocklistUpdates
+	if err := jsoniter.ConfigFastest.Unmarshal(data, &updates); err != nil {
+		return nil, err
+	}
+	return &updates, nil
+}
+
+func (blocklistUpdatesCodec) Encode(obj interface{}) ([]byte, error) {
+	return jsoniter.ConfigFastest.Marshal(obj)
+}
+
+func (blocklistUpdatesCodec) CodecID() string { return "storage.blocklistUpdatesCodec" }
+
+// blocklistUpdates announces the blocklist changes of this instance and applies the changes announced by the other
+// instances to the reader.
+type blocklistUpdates struct {
+	services.Service
+
+	kv     kv.Client
+	ttl    time.Duration
+	reader tempodb.Reader
+	now    func() time.Time
+	logger log.Logger
+
+	mtx     sync.Mutex
+	applied map[string]time.Time // time of the last change applied for each block
+}
+
+var _ tempodb.BlocklistNotifier = (*blocklistUpdates)(nil)
+
+func newBlocklistUpdates(cfg BlocklistUpdatesConfig, kvClient kv.Client, reader tempodb.Reader, logger log.Logger) *blocklistUpdates {
+	u := &blocklistUpdates{
+		kv:      kvClient,
+		ttl:     cfg.TTL,
+		reader:  reader,
+		now:     time.Now,
+		logger:  logger,
+		applied: map[string]time.Time{},
+	}
+
+	u.Service = services.NewBasicService(nil, u.running, nil)
+	return u
+}
+
+func (u *blocklistUpdates) running(ctx context.Context) error {
+	u.kv.WatchKey(ctx, blocklistUpdatesKey, func(in interface{}) bool {
+		if updates, ok := in.(*BlocklistUpdates); ok && updates != nil {
+			u.apply(updates)
+		}
+		return true
+	})
+	return nil
+}
+
+// BlocklistUpdated implements tempodb.BlocklistNotifier
+func (u *blocklistUpdates) BlocklistUpdated(tenantID string, add []*backend.BlockMeta, _ []*backend.BlockMeta, compactedAdd []*backend.CompactedBlockMeta, compactedRemove []*backend.CompactedBlockMeta) {
+	now := u.now()
+
+	changes := make([]BlocklistChange, 0, len(add)+len(compactedAdd)+len(compactedRemove))
+	for _, m := range add {
+		changes = append(changes, BlocklistChange{TenantID: tenantID, BlockID: m.BlockID, UpdatedAt: now, Added: m})
+	}
+	// the removed blocks are the compacted blocks added
+	for _, m := range compactedAdd {
+		changes = append(changes, BlocklistChange{TenantID: tenantID, BlockID: m.BlockID, UpdatedAt: now, Compacted: m})
+	}
+	for _, m := range compactedRemove {
+		changes = append(changes, BlocklistChange{TenantID: tenantID, BlockID: m.BlockID, UpdatedAt: now, Deleted: true})
+	}
+	if len(changes) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), blocklistUpdatesTimeout)
+	defer cancel()
+
+	err := u.kv.CAS(ctx, blocklistUpdatesKey, func(in interface{}) (out interface{}, retry bool, err error) {
+		updates, _ := in.(*BlocklistUpdates)
+		if updates == nil {
+			updates = &BlocklistUpdates{}
+		}
+		if updates.Changes == nil {
+			updates.Changes = map[string]BlocklistChange{}
+		}
+
+		// drop the expired changes so the value doesn't grow with every block
+		expired := now.Add(-u.ttl)
+		for k, c := range updates.Changes {
+			if c.UpdatedAt.Before(expired) {
+				delete(updates.Changes, k)
+			}
+		}
+		for _, c := range changes {
+			updates.Changes[blocklistChangeKey(c.TenantID, c.BlockID)] = c
+		}
+		return updates, true, nil
+	})
+	if err != nil {
+		level.Warn(u.logger).Log("msg", "failed to announce blocklist changes", "tenantID", tenantID, "err", err)
+		metricBlocklistUpdatesFailed.Add(float64(len(changes)))
+		return
+	}
+
+	metricBlocklistUpdatesSent.Add(float64(len(changes)))
+}
+
+// apply applies the changes that haven't been applied yet to the reader
+func (u *blocklistUpdates) apply(updates *BlocklistUpdates) {
+	u.mtx.Lock()
+	defer u.mtx.Unlock()
+
+	expired := u.now().Add(-u.ttl)
+	for k, t := range u.applied {
+		if t.Before(expired) {
+			delete(u.applied, k)
+		}
+	}
+
+	for k, c := range updates.Changes {
+		if c.UpdatedAt.Before(expired) {
+			continue
+		}
+		if t, ok := u.applied[k]; ok && !c.UpdatedAt.After(t) {
+			continue
+		}
+		u.applied[k] = c.UpdatedAt
+
+		switch {
+		case c.Added != nil:
+			u.reader.UpdateBlocklist(c.TenantID, []*backend.BlockMeta{c.Added}, nil, nil, nil)
+		case c.Compacted != nil:
+			u.reader.UpdateBlocklist(c.TenantID, nil, []*backend.BlockMeta{&c.Compacted.BlockMeta}, []*backend.CompactedBlockMeta{c.Compacted}, nil)
+		case c.Deleted:
+			meta := backend.BlockMeta{BlockID: c.BlockID, TenantID: c.TenantID}
+			u.reader.UpdateBlocklist(c.TenantID, nil, []*backend.BlockMeta{&meta}, nil, []*backend.CompactedBlockMeta{{BlockMeta: meta}})
+		default:
+			continue
+		}
+		metricBlocklistUpdatesApplied.Inc()
+	}
+}
@@ -0,0 +1,129 @@
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/google/uuid"
+	"github.com/grafana/dskit/kv/consul"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/tempodb"
+	"github.com/grafana/tempo/tempodb/backend"
+)
+
+type blocklistUpdate struct {
+	tenantID        string
+	add             []*backend.BlockMeta
+	remove          []*backend.BlockMeta
+	compactedAdd    []*backend.CompactedBlockMeta
+	compactedRemove []*backend.CompactedBlockMeta
+}
+
+type mockBlocklistReader struct {
+	tempodb.Reader
+	updates []blocklistUpdate
+}
+
+func (m *mockBlocklistReader) UpdateBlocklist(tenantID string, add []*backend.BlockMeta, remove []*backend.BlockMeta, compactedAdd []*backend.CompactedBlockMeta, compactedRemove []*backend.CompactedBlockMeta) {
+	m.updates = append(m.updates, blocklistUpdate{tenantID, add, remove, compactedAdd, compactedRemove})
+}
+
+func TestBlocklistUpdates(t *testing.T) {
+	kvClient, closer := consul.NewInMemoryClient(BlocklistUpdatesCodec, log.NewNopLogger(), nil)
+	t.Cleanup(func() { _ = closer.Close() })
+
+	cfg := BlocklistUpdatesConfig{Enabled: true, TTL: 10 * time.Minute}
+	now := time.Now()
+
+	compactor := newBlocklistUpdates(cfg, kvClient, &mockBlocklistReader{}, log.NewNopLogger())
+	compactor.now = func() time.Time { return now }
+
+	reader := &mockBlocklistReader{}
+	querier := newBlocklistUpdates(cfg, kvClient, reader, log.NewNopLogger())
+	querier.now = func() time.Time { return now }
+
+	added := &backend.BlockMeta{BlockID: uuid.New(), TenantID: "test"}
+	compacted := &backend.CompactedBlockMeta{BlockMeta: backend.BlockMeta{BlockID: uuid.New(), TenantID: "test"}, CompactedTime: now}
+	deleted := &backend.CompactedBlockMeta{BlockMeta: backend.BlockMeta{BlockID: uuid.New(), TenantID: "test"}}
+
+	compactor.BlocklistUpdated("test", []*backend.BlockMeta{added}, []*backend.BlockMeta{&compacted.BlockMeta}, []*backend.CompactedBlockMeta{compacted}, []*backend.CompactedBlockMeta{deleted})
+
+	get := func() *BlocklistUpdates {
+		val, err := kvClient.Get(context.Background(), blocklistUpdatesKey)
+		require.NoError(t, err)
+		return val.(*BlocklistUpdates)
+	}
+
+	updates := get()
+	require.Len(t, updates.Changes, 3)
+
+	querier.apply(updates)
+	require.Len(t, reader.updates, 3)
+	for _, u := range reader.updates {
+		require.Equal(t, "test", u.tenantID)
+		switch {
+		case len(u.add) == 1:
+			require.Equal(t, added.BlockID, u.add[0].BlockID)
+		case len(u.compactedAdd) == 1:
+			require.Equal(t, compacted.BlockID, u.compactedAdd[0].BlockID)
+			require.Equal(t, compacted.BlockID, u.remove[0].BlockID)
+		default:
+			require.Equal(t, deleted.BlockID, u.compactedRemove[0].BlockID)
+			require.Equal(t, deleted.BlockID, u.remove[0].BlockID)
+		}
+	}
+
+	// changes are only applied once
+	querier.apply(updates)
+	require.Len(t, reader.updates, 3)
+
+	// expired changes are dropped on the next announcement
+	now = now.Add(time.Hour)
+	compactor.BlocklistUpdated("test", nil, nil, nil, []*backend.CompactedBlockMeta{{BlockMeta: *added}})
+
+	updates = get()
+	require.Len(t, updates.Changes, 1)
+	require.True(t, updates.Changes[blocklistChangeKey("test", added.BlockID)].Deleted)
+
+	querier.apply(updates)
+	require.Len(t, reader.updates, 4)
+}
+
+func TestBlocklistUpdatesMerge(t *testing.T) {
+	now := time.Now()
+	blockID := uuid.New()
+	key := blocklistChangeKey("test", blockID)
+
+	u := &BlocklistUpdates{Changes: map[string]BlocklistChange{
+		key: {TenantID: "test", BlockID: blockID, UpdatedAt: now, Deleted: true},
+	}}
+
+	// older changes are ignored
+	change, err := u.Merge(&BlocklistUpdates{Changes: map[string]BlocklistChange{
+		key: {TenantID: "test", BlockID: blockID, UpdatedAt: now.Add(-time.Second), Added: &backend.BlockMeta{BlockID: blockID}},
+	}}, false)
+	require.NoError(t, err)
+	require.Nil(t, change)
+	require.True(t, u.Changes[key].Deleted)
+
+	other := uuid.New()
+	change, err = u.Merge(&BlocklistUpdates{Changes: map[string]BlocklistChange{
+		blocklistChangeKey("test", other): {TenantID: "test", BlockID: other, UpdatedAt: now, Added: &backend.BlockMeta{BlockID: other}},
+	}}, false)
+	require.NoError(t, err)
+	require.Equal(t, []string{blocklistChangeKey("test", other)}, change.MergeContent())
+	require.Len(t, u.Changes, 2)
+
+	_, removed := u.RemoveTombstones(now.Add(time.Second))
+	require.Equal(t, 2, removed)
+	require.Empty(t, u.Changes)
+}
+
+func TestBlocklistUpdatesConfigValidate(t *testing.T) {
+	require.NoError(t, (&BlocklistUpdatesConfig{}).Validate())
+	require.NoError(t, (&BlocklistUpdatesConfig{Enabled: true, TTL: time.Minute}).Validate())
+	require.EqualError(t, (&BlocklistUpdatesConfig{Enabled: true}).Validate(), "blocklist updates ttl must be greater than 0")
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/grafana/tempo/tempodb/backend/gcs"
 	"github.com/grafana/tempo/tempodb/backend/local"
 	"github.com/grafana/tempo/tempodb/backend/s3"
+	"github.com/grafana/tempo/tempodb/backend/swift"
 	"github.com/grafana/tempo/tempodb/encoding"
 	"github.com/grafana/tempo/tempodb/encoding/common"
 	"github.com/grafana/tempo/tempodb/pool"
@@ -20,7 +21,8 @@ import (
 
 // Config is the Tempo storage configuration
 type Config struct {
-	Trace tempodb.Config `yaml:"trace"`
+	Trace            tempodb.Config         `yaml:"trace"`
+	BlocklistUpdates BlocklistUpdatesConfig `yaml:"blocklist_updates"`
 }
 
 // RegisterFlagsAndApplyDefaults registers the flags.
@@ -30,9 +32,12 @@ func (cfg *Config) RegisterFlagsAndApplyDefaults(prefix string, f *flag.FlagSet)
 	cfg.Trace.BlocklistPollTenantIndexBuilders = tempodb.DefaultTenantIndexBuilders
 	cfg.Trace.BlocklistPollTolerateConsecutiveErrors = tempodb.DefaultTolerateConsecutiveErrors
 
-	f.StringVar(&cfg.Trace.Backend, util.PrefixConfig(prefix, "trace.backend"), "", "Trace backend (s3, azure, gcs, local)")
+	f.StringVar(&cfg.Trace.Backend, util.PrefixConfig(prefix, "trace.backend"), "", "Trace backend (s3, azure, gcs, swift, local)")
 	f.DurationVar(&cfg.Trace.BlocklistPoll, util.PrefixConfig(prefix, "trace.blocklist_poll"), tempodb.DefaultBlocklistPoll, "Period at which to run the maintenance cycle.")
 
+	cfg.BlocklistUpdates.KVStore.Store = "memberlist"
+	cfg.BlocklistUpdates.TTL = 10 * time.Minute
+
 	cfg.Trace.WAL = &wal.Config{}
 	f.StringVar(&cfg.Trace.WAL.Filepath, util.PrefixConfig(prefix, "trace.wal.path"), "/var/tempo/wal", "Path at which store WAL blocks.")
 	cfg.Trace.WAL.Encoding = backend.EncSnappy
@@ -55,9 +60,16 @@ func (cfg *Config) RegisterFlagsAndApplyDefaults(prefix string, f *flag.FlagSet)
 	cfg.Trace.GCS = &gcs.Config{}
 	cfg.Trace.GCS.RegisterFlagsAndApplyDefaults(util.PrefixConfig(prefix, "trace"), f)
 
+	cfg.Trace.Swift = &swift.Config{}
+	cfg.Trace.Swift.RegisterFlagsAndApplyDefaults(util.PrefixConfig(prefix, "trace"), f)
+
 	cfg.Trace.Local = &local.Config{}
 	cfg.Trace.Local.RegisterFlagsAndApplyDefaults(util.PrefixConfig(prefix, "trace"), f)
 
+	cfg.Trace.Retry.RegisterFlagsAndApplyDefaults(util.PrefixConfig(prefix, "trace"), f)
+	cfg.Trace.RateLimit.RegisterFlagsAndApplyDefaults(util.PrefixConfig(prefix, "trace"), f)
+	cfg.Trace.Encryption.RegisterFlagsAndApplyDefaults(util.PrefixConfig(prefix, "trace"), f)
+
 	cfg.Trace.BackgroundCache = &cache.BackgroundConfig{}
 	cfg.Trace.BackgroundCache.WriteBackBuffer = 10000
 	cfg.Trace.BackgroundCache.WriteBackGoroutines = 10
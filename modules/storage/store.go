@@ -2,9 +2,12 @@ package storage
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/go-kit/log"
+	"github.com/grafana/dskit/kv"
 	"github.com/grafana/dskit/services"
+	"github.com/prometheus/client_golang/prometheus"
 
 	"github.com/grafana/tempo/pkg/cache"
 	"github.com/grafana/tempo/pkg/usagestats"
@@ -37,6 +40,8 @@ type store struct {
 	tempodb.Reader
 	tempodb.Writer
 	tempodb.Compactor
+
+	blocklistUpdates *blocklistUpdates
 }
 
 // NewStore creates a new Tempo Store using configuration supplied.
@@ -60,15 +65,34 @@ func NewStore(cfg Config, cacheProvider cache.Provider, logger log.Logger) (Stor
 		Compactor: c,
 	}
 
+	if cfg.BlocklistUpdates.Enabled {
+		if err := cfg.BlocklistUpdates.Validate(); err != nil {
+			return nil, err
+		}
+
+		kvClient, err := kv.NewClient(cfg.BlocklistUpdates.KVStore, BlocklistUpdatesCodec, kv.RegistererWithKVName(prometheus.WrapRegistererWithPrefix("tempo_", prometheus.DefaultRegisterer), blocklistUpdatesKey), logger)
+		if err != nil {
+			return nil, fmt.Errorf("unable to initialize blocklist updates kv client: %w", err)
+		}
+		s.blocklistUpdates = newBlocklistUpdates(cfg.BlocklistUpdates, kvClient, r, logger)
+		w.SetBlocklistNotifier(s.blocklistUpdates)
+	}
+
 	s.Service = services.NewIdleService(s.starting, s.stopping)
 	return s, nil
 }
 
-func (s *store) starting(_ context.Context) error {
+func (s *store) starting(ctx context.Context) error {
+	if s.blocklistUpdates != nil {
+		return services.StartAndAwaitRunning(ctx, s.blocklistUpdates)
+	}
 	return nil
 }
 
 func (s *store) stopping(_ error) error {
+	if s.blocklistUpdates != nil {
+		_ = services.StopAndAwaitTerminated(context.Background(), s.blocklistUpdates)
+	}
 	s.Reader.Shutdown()
 
 	return nil
@@ -18,6 +18,7 @@ import (
 
 	"github.com/grafana/dskit/httpgrpc"
 
+	"github.com/grafana/tempo/pkg/model/trace"
 	"github.com/grafana/tempo/pkg/tempopb"
 	"github.com/grafana/tempo/pkg/traceql"
 	"github.com/grafana/tempo/pkg/util"
@@ -26,6 +27,7 @@ import (
 
 const (
 	URLParamTraceID = "traceID"
+	URLParamBlockID = "blockID"
 	// search
 	urlParamQuery           = "q"
 	urlParamTags            = "tags"
@@ -59,6 +61,9 @@ const (
 	// search tags
 	urlParamScope = "scope"
 
+	// trace by id
+	urlParamExclude = "exclude"
+
 	// generator summary
 	urlParamGroupBy = "groupBy"
 	// urlParamMetric  = "metric"
@@ -68,10 +73,18 @@ const (
 	HeaderAcceptProtobuf = "application/protobuf"
 	HeaderAcceptJSON     = "application/json"
 
+	// HeaderSkippedBlocks is the number of quarantined blocks skipped by a trace lookup
+	HeaderSkippedBlocks = "X-Tempo-Skipped-Blocks"
+
 	PathPrefixQuerier   = "/querier"
 	PathPrefixGenerator = "/generator"
 
 	PathTraces             = "/api/traces/{traceID}"
+	PathTraceAnnotations   = "/api/traces/{traceID}/annotations"
+	PathTraceTombstones    = "/api/tombstones"
+	PathBlocks             = "/api/blocks"
+	PathBlock              = "/api/blocks/{" + URLParamBlockID + "}"
+	PathBlockQuarantine    = "/api/blocks/{" + URLParamBlockID + "}/quarantine"
 	PathSearch             = "/api/search"
 	PathSearchTags         = "/api/search/tags"
 	PathSearchTagValues    = "/api/search/tag/{" + MuxVarTagName + "}/values"
@@ -84,6 +97,8 @@ const (
 
 	// PathOverrides user configurable overrides
 	PathOverrides = "/api/overrides"
+	// PathOverridesTenant read-only limits enforced for a tenant
+	PathOverridesTenant = "/api/overrides/{tenant}"
 
 	PathSearchTagValuesV2 = "/api/v2/search/tag/{" + MuxVarTagName + "}/values"
 	PathSearchTagsV2      = "/api/v2/search/tags"
@@ -95,6 +110,12 @@ const (
 	BlockStartKey      = "blockStart"
 	BlockEndKey        = "blockEnd"
 
+	// criterion to stop searching the blocks of a trace by id lookup before all of them are searched
+	TraceByIDStopKey        = "stop"
+	TraceByIDStopNever      = "never"
+	TraceByIDStopComplete   = "complete"
+	TraceByIDStopFirstBlock = "first_block"
+
 	defaultLimit           = 20
 	defaultSpansPerSpanSet = 3
 	defaultSince           = 1 * time.Hour
@@ -115,6 +136,45 @@ func ParseTraceID(r *http.Request) ([]byte, error) {
 	return byteID, nil
 }
 
+// ParseBlockID parses the block id from the path of the block inspection endpoint
+func ParseBlockID(r *http.Request) (uuid.UUID, error) {
+	blockID, ok := mux.Vars(r)[URLParamBlockID]
+	if !ok {
+		return uuid.UUID{}, fmt.Errorf("please provide a blockID")
+	}
+
+	id, err := uuid.Parse(blockID)
+	if err != nil {
+		return uuid.UUID{}, fmt.Errorf("invalid blockID %q: %w", blockID, err)
+	}
+
+	return id, nil
+}
+
+// ParseTraceStripOptions parses the parts of the trace to omit from a trace by id response. e.g. exclude=events,links
+func ParseTraceStripOptions(r *http.Request) (trace.StripOptions, error) {
+	s, _ := extractQueryParam(r, urlParamExclude)
+
+	opts, ok := trace.ParseStripOptions(s)
+	if !ok {
+		return trace.StripOptions{}, fmt.Errorf("invalid exclude %s. valid values are %s, %s and %s", s, trace.StripEvents, trace.StripLinks, trace.StripAttributes)
+	}
+
+	return opts, nil
+}
+
+// ParseTraceByIDStop parses the criterion to stop a trace by id lookup early. e.g. stop=first_block. It returns an
+// empty string if the request doesn't set one.
+func ParseTraceByIDStop(r *http.Request) (string, error) {
+	s, _ := extractQueryParam(r, TraceByIDStopKey)
+
+	switch s {
+	case "", TraceByIDStopNever, TraceByIDStopComplete, TraceByIDStopFirstBlock:
+		return s, nil
+	}
+	return "", fmt.Errorf("invalid stop %s. valid values are %s, %s and %s", s, TraceByIDStopNever, TraceByIDStopComplete, TraceByIDStopFirstBlock)
+}
+
 // ParseSearchRequest takes an http.Request and decodes query params to create a tempopb.SearchRequest
 func ParseSearchRequest(r *http.Request) (*tempopb.SearchRequest, error) {
 	req := &tempopb.SearchRequest{
@@ -123,19 +183,19 @@ func ParseSearchRequest(r *http.Request) (*tempopb.SearchRequest, error) {
 	}
 
 	if s, ok := extractQueryParam(r, urlParamStart); ok {
-		start, err := strconv.ParseInt(s, 10, 32)
+		start, err := parseUnixSeconds(s)
 		if err != nil {
 			return nil, fmt.Errorf("invalid start: %w", err)
 		}
-		req.Start = uint32(start)
+		req.Start = start
 	}
 
 	if s, ok := extractQueryParam(r, urlParamEnd); ok {
-		end, err := strconv.ParseInt(s, 10, 32)
+		end, err := parseUnixSeconds(s)
 		if err != nil {
 			return nil, fmt.Errorf("invalid end: %w", err)
 		}
-		req.End = uint32(end)
+		req.End = end
 	}
 
 	query, queryFound := extractQueryParam(r, urlParamQuery)
@@ -270,19 +330,19 @@ func ParseSpanMetricsRequest(r *http.Request) (*tempopb.SpanMetricsRequest, erro
 	}
 
 	if s, ok := extractQueryParam(r, urlParamStart); ok {
-		start, err := strconv.ParseInt(s, 10, 32)
+		start, err := parseUnixSeconds(s)
 		if err != nil {
 			return nil, fmt.Errorf("invalid start: %w", err)
 		}
-		req.Start = uint32(start)
+		req.Start = start
 	}
 
 	if s, ok := extractQueryParam(r, urlParamEnd); ok {
-		end, err := strconv.ParseInt(s, 10, 32)
+		end, err := parseUnixSeconds(s)
 		if err != nil {
 			return nil, fmt.Errorf("invalid end: %w", err)
 		}
-		req.End = uint32(end)
+		req.End = end
 	}
 
 	return req, nil
@@ -307,19 +367,19 @@ func ParseSpanMetricsSummaryRequest(r *http.Request) (*tempopb.SpanMetricsSummar
 	}
 
 	if s, ok := extractQueryParam(r, urlParamStart); ok {
-		start, err := strconv.ParseInt(s, 10, 32)
+		start, err := parseUnixSeconds(s)
 		if err != nil {
 			return nil, fmt.Errorf("invalid start: %w", err)
 		}
-		req.Start = uint32(start)
+		req.Start = start
 	}
 
 	if s, ok := extractQueryParam(r, urlParamEnd); ok {
-		end, err := strconv.ParseInt(s, 10, 32)
+		end, err := parseUnixSeconds(s)
 		if err != nil {
 			return nil, fmt.Errorf("invalid end: %w", err)
 		}
-		req.End = uint32(end)
+		req.End = end
 	}
 
 	return req, nil
@@ -416,8 +476,9 @@ func BuildQueryRangeRequest(req *http.Request, searchReq *tempopb.QueryRangeRequ
 	}
 
 	q := req.URL.Query()
-	q.Set(urlParamStart, strconv.FormatUint(searchReq.Start, 10))
-	q.Set(urlParamEnd, strconv.FormatUint(searchReq.End, 10))
+	// RFC3339 is unambiguous for any timestamp. unix timestamps close to the epoch are mistaken for a smaller unit
+	q.Set(urlParamStart, time.Unix(0, int64(searchReq.Start)).UTC().Format(time.RFC3339Nano))
+	q.Set(urlParamEnd, time.Unix(0, int64(searchReq.End)).UTC().Format(time.RFC3339Nano))
 	q.Set(urlParamStep, time.Duration(searchReq.Step).String())
 	q.Set(urlParamShard, strconv.FormatUint(uint64(searchReq.ShardID), 10))
 	q.Set(urlParamShardCount, strconv.FormatUint(uint64(searchReq.ShardCount), 10))
@@ -486,7 +547,9 @@ func determineBounds(now time.Time, startString, endString, sinceString string)
 	return start, end, nil
 }
 
-// parseTimestamp parses a ns unix timestamp from a string
+// parseTimestamp parses an RFC3339 timestamp or a unix timestamp in seconds, milliseconds, microseconds or nanoseconds.
+// The unit of a unix timestamp is picked by its magnitude so any timestamp after 1973 is parsed correctly regardless
+// of its unit. Unix timestamps in seconds may have a fractional part.
 // if the value is empty it returns a default value passed as second parameter
 func parseTimestamp(value string, def time.Time) (time.Time, error) {
 	if value == "" {
@@ -500,17 +563,42 @@ func parseTimestamp(value string, def time.Time) (time.Time, error) {
 			return time.Unix(int64(s), int64(ns*float64(time.Second))), nil
 		}
 	}
-	nanos, err := strconv.ParseInt(value, 10, 64)
+	ts, err := strconv.ParseInt(value, 10, 64)
 	if err != nil {
 		if ts, err := time.Parse(time.RFC3339Nano, value); err == nil {
 			return ts, nil
 		}
-		return time.Time{}, err
+		return time.Time{}, fmt.Errorf("%q is neither an RFC3339 nor a unix timestamp", value)
+	}
+
+	abs := ts
+	if abs < 0 {
+		abs = -abs
+	}
+	switch {
+	case abs < 1e11:
+		return time.Unix(ts, 0), nil
+	case abs < 1e14:
+		return time.UnixMilli(ts), nil
+	case abs < 1e17:
+		return time.UnixMicro(ts), nil
+	default:
+		return time.Unix(0, ts), nil
 	}
-	if len(value) <= 10 {
-		return time.Unix(nanos, 0), nil
+}
+
+// parseUnixSeconds parses a timestamp in any of the formats accepted by parseTimestamp and returns it in unix seconds
+func parseUnixSeconds(value string) (uint32, error) {
+	ts, err := parseTimestamp(value, time.Time{})
+	if err != nil {
+		return 0, err
+	}
+
+	s := ts.Unix()
+	if s < 0 || s > math.MaxUint32 {
+		return 0, fmt.Errorf("%q is out of range", value)
 	}
-	return time.Unix(0, nanos), nil
+	return uint32(s), nil
 }
 
 func step(r *http.Request, start, end time.Time) (time.Duration, error) {
@@ -708,21 +796,21 @@ func ValidateAndSanitizeRequest(r *http.Request) (string, string, string, int64,
 	}
 
 	if s, ok := extractQueryParam(r, urlParamStart); ok {
-		var err error
-		startTime, err = strconv.ParseInt(s, 10, 64)
+		ts, err := parseTimestamp(s, time.Time{})
 		if err != nil {
 			return "", "", "", 0, 0, fmt.Errorf("invalid start: %w", err)
 		}
+		startTime = ts.Unix()
 	} else {
 		startTime = 0
 	}
 
 	if s, ok := extractQueryParam(r, urlParamEnd); ok {
-		var err error
-		endTime, err = strconv.ParseInt(s, 10, 64)
+		ts, err := parseTimestamp(s, time.Time{})
 		if err != nil {
 			return "", "", "", 0, 0, fmt.Errorf("invalid end: %w", err)
 		}
+		endTime = ts.Unix()
 	} else {
 		endTime = 0
 	}
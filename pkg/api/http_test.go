@@ -13,6 +13,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/grafana/tempo/cmd/tempo-query/tempo"
+	"github.com/grafana/tempo/pkg/model/trace"
 	"github.com/grafana/tempo/pkg/tempopb"
 )
 
@@ -666,6 +667,38 @@ func TestExtractServerlessParam(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestParseTraceStripOptions(t *testing.T) {
+	r := httptest.NewRequest("GET", "http://example.com", nil)
+	opts, err := ParseTraceStripOptions(r)
+	require.NoError(t, err)
+	assert.True(t, opts.IsEmpty())
+
+	r = httptest.NewRequest("GET", "http://example.com?exclude=events,links", nil)
+	opts, err = ParseTraceStripOptions(r)
+	require.NoError(t, err)
+	assert.Equal(t, trace.StripOptions{Events: true, Links: true}, opts)
+
+	r = httptest.NewRequest("GET", "http://example.com?exclude=spans", nil)
+	_, err = ParseTraceStripOptions(r)
+	assert.EqualError(t, err, "invalid exclude spans. valid values are events, links and attributes")
+}
+
+func TestParseTraceByIDStop(t *testing.T) {
+	r := httptest.NewRequest("GET", "http://example.com", nil)
+	stop, err := ParseTraceByIDStop(r)
+	require.NoError(t, err)
+	assert.Equal(t, "", stop)
+
+	r = httptest.NewRequest("GET", "http://example.com?stop=first_block", nil)
+	stop, err = ParseTraceByIDStop(r)
+	require.NoError(t, err)
+	assert.Equal(t, TraceByIDStopFirstBlock, stop)
+
+	r = httptest.NewRequest("GET", "http://example.com?stop=soon", nil)
+	_, err = ParseTraceByIDStop(r)
+	assert.EqualError(t, err, "invalid stop soon. valid values are never, complete and first_block")
+}
+
 func Test_parseTimestamp(t *testing.T) {
 	now := time.Now()
 
@@ -678,6 +711,8 @@ func Test_parseTimestamp(t *testing.T) {
 	}{
 		{"default", "", now, now, false},
 		{"unix timestamp", "1571332130", now, time.Unix(1571332130, 0), false},
+		{"unix milli timestamp", "1571334162051", now, time.UnixMilli(1571334162051), false},
+		{"unix micro timestamp", "1571334162051000", now, time.UnixMicro(1571334162051000), false},
 		{"unix nano timestamp", "1571334162051000000", now, time.Unix(0, 1571334162051000000), false},
 		{"unix timestamp with subseconds", "1571332130.934", now, time.Unix(1571332130, 934*1e6), false},
 		{"RFC3339 format", "2002-10-02T15:00:00Z", now, time.Date(2002, 10, 0o2, 15, 0, 0, 0, time.UTC), false},
@@ -698,6 +733,25 @@ func Test_parseTimestamp(t *testing.T) {
 	}
 }
 
+func TestParseSearchRequestTimestamps(t *testing.T) {
+	// all units and RFC3339 timestamps resolve to the same unix seconds
+	for _, ts := range []string{"1571332130", "1571332130000", "1571332130000000", "1571332130000000000", "2019-10-17T17:08:50Z", "2019-10-17T19:08:50+02:00"} {
+		t.Run(ts, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/api/search?start="+url.QueryEscape(ts)+"&end=2019-10-17T18:00:00Z", nil)
+			req, err := ParseSearchRequest(r)
+			require.NoError(t, err)
+			require.Equal(t, uint32(1571332130), req.Start)
+			require.Equal(t, uint32(1571335200), req.End)
+		})
+	}
+
+	_, err := ParseSearchRequest(httptest.NewRequest("GET", "/api/search?start=-10&end=20", nil))
+	require.EqualError(t, err, `invalid start: "-10" is out of range`)
+
+	_, err = ParseSearchRequest(httptest.NewRequest("GET", "/api/search?start=yesterday&end=20", nil))
+	require.EqualError(t, err, `invalid start: "yesterday" is neither an RFC3339 nor a unix timestamp`)
+}
+
 func TestQueryRangeRoundtrip(t *testing.T) {
 	tcs := []struct {
 		name string
@@ -402,19 +402,19 @@ func parseSearchTagValuesRequest(r *http.Request, enforceTraceQL bool) (*tempopb
 	}
 
 	if s, ok := extractQueryParam(r, urlParamStart); ok {
-		start, err := strconv.ParseInt(s, 10, 32)
+		start, err := parseUnixSeconds(s)
 		if err != nil {
 			return nil, fmt.Errorf("invalid start: %w", err)
 		}
-		req.Start = uint32(start)
+		req.Start = start
 	}
 
 	if s, ok := extractQueryParam(r, urlParamEnd); ok {
-		end, err := strconv.ParseInt(s, 10, 32)
+		end, err := parseUnixSeconds(s)
 		if err != nil {
 			return nil, fmt.Errorf("invalid end: %w", err)
 		}
-		req.End = uint32(end)
+		req.End = end
 	}
 
 	return req, nil
@@ -432,19 +432,19 @@ func ParseSearchTagsRequest(r *http.Request) (*tempopb.SearchTagsRequest, error)
 	req.Scope = scope
 
 	if s, ok := extractQueryParam(r, urlParamStart); ok {
-		start, err := strconv.ParseInt(s, 10, 32)
+		start, err := parseUnixSeconds(s)
 		if err != nil {
 			return nil, fmt.Errorf("invalid start: %w", err)
 		}
-		req.Start = uint32(start)
+		req.Start = start
 	}
 
 	if s, ok := extractQueryParam(r, urlParamEnd); ok {
-		end, err := strconv.ParseInt(s, 10, 32)
+		end, err := parseUnixSeconds(s)
 		if err != nil {
 			return nil, fmt.Errorf("invalid end: %w", err)
 		}
-		req.End = uint32(end)
+		req.End = end
 	}
 	return req, nil
 }
@@ -15,12 +15,16 @@ import (
 type BackgroundConfig struct {
 	WriteBackGoroutines int `yaml:"writeback_goroutines"`
 	WriteBackBuffer     int `yaml:"writeback_buffer"`
+	// WriteBackMaxItemSize skips the write back of items larger than this so they don't take up the buffer.
+	// 0 disables the limit
+	WriteBackMaxItemSize int `yaml:"writeback_max_item_size"`
 }
 
 // RegisterFlagsWithPrefix adds the flags required to config this to the given FlagSet
 func (cfg *BackgroundConfig) RegisterFlagsWithPrefix(prefix string, description string, f *flag.FlagSet) {
 	f.IntVar(&cfg.WriteBackGoroutines, prefix+"background.write-back-concurrency", 10, description+"At what concurrency to write back to cache.")
 	f.IntVar(&cfg.WriteBackBuffer, prefix+"background.write-back-buffer", 10000, description+"How many key batches to buffer for background write-back.")
+	f.IntVar(&cfg.WriteBackMaxItemSize, prefix+"background.write-back-max-item-size", 0, description+"The maximum size of an item written back to cache. Bigger items are not stored. If set to 0, no maximum size is enforced.")
 }
 
 type backgroundCache struct {
@@ -31,8 +35,11 @@ type backgroundCache struct {
 	bgWrites chan backgroundWrite
 	name     string
 
-	droppedWriteBack prometheus.Counter
-	queueLength      prometheus.Gauge
+	maxItemSize int
+
+	droppedWriteBack   prometheus.Counter
+	oversizedWriteBack prometheus.Counter
+	queueLength        prometheus.Gauge
 }
 
 type backgroundWrite struct {
@@ -47,12 +54,21 @@ func NewBackground(name string, cfg BackgroundConfig, cache Cache, reg prometheu
 		quit:     make(chan struct{}),
 		bgWrites: make(chan backgroundWrite, cfg.WriteBackBuffer),
 		name:     name,
+
+		maxItemSize: cfg.WriteBackMaxItemSize,
+
 		droppedWriteBack: promauto.With(reg).NewCounter(prometheus.CounterOpts{
 			Namespace:   "tempo",
 			Name:        "cache_dropped_background_writes_total",
 			Help:        "Total count of dropped write backs to cache.",
 			ConstLabels: prometheus.Labels{"name": name},
 		}),
+		oversizedWriteBack: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+			Namespace:   "tempo",
+			Name:        "cache_oversized_background_writes_total",
+			Help:        "Total count of write backs to cache skipped because the item is larger than the max item size.",
+			ConstLabels: prometheus.Labels{"name": name},
+		}),
 
 		queueLength: promauto.With(reg).NewGauge(prometheus.GaugeOpts{
 			Namespace:   "tempo",
@@ -82,6 +98,10 @@ const keysPerBatch = 100
 
 // Store writes keys for the cache in the background.
 func (c *backgroundCache) Store(ctx context.Context, keys []string, bufs [][]byte) {
+	if c.maxItemSize > 0 {
+		keys, bufs = c.skipOversized(keys, bufs)
+	}
+
 	for len(keys) > 0 {
 		num := keysPerBatch
 		if num > len(keys) {
@@ -108,6 +128,30 @@ func (c *backgroundCache) Store(ctx context.Context, keys []string, bufs [][]byt
 	}
 }
 
+// skipOversized returns the items that are not larger than the max item size. The passed slices are not modified.
+func (c *backgroundCache) skipOversized(keys []string, bufs [][]byte) ([]string, [][]byte) {
+	for i := range bufs {
+		if len(bufs[i]) <= c.maxItemSize {
+			continue
+		}
+
+		// copy the items that fit on the first oversized one
+		fitKeys := append(make([]string, 0, len(keys)), keys[:i]...)
+		fitBufs := append(make([][]byte, 0, len(bufs)), bufs[:i]...)
+		for j := i; j < len(bufs); j++ {
+			if len(bufs[j]) > c.maxItemSize {
+				c.oversizedWriteBack.Inc()
+				continue
+			}
+			fitKeys = append(fitKeys, keys[j])
+			fitBufs = append(fitBufs, bufs[j])
+		}
+		return fitKeys, fitBufs
+	}
+
+	return keys, bufs
+}
+
 func (c *backgroundCache) writeBackLoop() {
 	defer c.wg.Done()
 
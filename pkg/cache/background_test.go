@@ -5,9 +5,12 @@ import (
 	crand "crypto/rand"
 	"math/rand"
 	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/grafana/tempo/pkg/cache"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/require"
 )
 
@@ -25,6 +28,34 @@ func TestBackground(t *testing.T) {
 	testCacheMiss(t, c)
 }
 
+func TestBackgroundMaxItemSize(t *testing.T) {
+	reg := prometheus.NewPedanticRegistry()
+	c := cache.NewBackground("mock", cache.BackgroundConfig{
+		WriteBackGoroutines:  1,
+		WriteBackBuffer:      100,
+		WriteBackMaxItemSize: 2,
+	}, cache.NewMockCache(), reg)
+
+	keys := []string{"small", "large", "fits"}
+	bufs := [][]byte{{1}, {1, 2, 3}, {1, 2}}
+	c.Store(context.Background(), keys, bufs)
+	cache.Flush(c)
+
+	// the passed slices are not modified
+	require.Equal(t, []string{"small", "large", "fits"}, keys)
+
+	found, foundBufs, missing := c.Fetch(context.Background(), keys)
+	require.Equal(t, []string{"small", "fits"}, found)
+	require.Equal(t, [][]byte{{1}, {1, 2}}, foundBufs)
+	require.Equal(t, []string{"large"}, missing)
+
+	require.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(`
+		# HELP tempo_cache_oversized_background_writes_total Total count of write backs to cache skipped because the item is larger than the max item size.
+		# TYPE tempo_cache_oversized_background_writes_total counter
+		tempo_cache_oversized_background_writes_total{name="mock"} 1
+	`), "tempo_cache_oversized_background_writes_total"))
+}
+
 func fillCache(cache cache.Cache) ([]string, [][]byte) {
 	// put a set of chunks, larger than background batch size, with varying timestamps and values
 	keys := []string{}
@@ -10,14 +10,15 @@ type Role string
 
 const (
 	// individual roles
-	RoleNone             Role = "none"
-	RoleBloom            Role = "bloom"
-	RoleTraceIDIdx       Role = "trace-id-index"
-	RoleParquetFooter    Role = "parquet-footer"
-	RoleParquetColumnIdx Role = "parquet-column-idx"
-	RoleParquetOffsetIdx Role = "parquet-offset-idx"
-	RoleFrontendSearch   Role = "frontend-search"
-	RoleParquetPage      Role = "parquet-page"
+	RoleNone              Role = "none"
+	RoleBloom             Role = "bloom"
+	RoleTraceIDIdx        Role = "trace-id-index"
+	RoleParquetFooter     Role = "parquet-footer"
+	RoleParquetColumnIdx  Role = "parquet-column-idx"
+	RoleParquetOffsetIdx  Role = "parquet-offset-idx"
+	RoleFrontendSearch    Role = "frontend-search"
+	RoleFrontendTraceByID Role = "frontend-trace-by-id"
+	RoleParquetPage       Role = "parquet-page"
 )
 
 // Provider is an object that can return a cache for a requested role
@@ -0,0 +1,109 @@
+package cache
+
+import (
+	"context"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru/v2"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// InMemoryConfig is config to make an InMemory cache
+type InMemoryConfig struct {
+	MaxItems int           `yaml:"max_items"`
+	TTL      time.Duration `yaml:"ttl"`
+}
+
+type inMemoryEntry struct {
+	buf     []byte
+	expires time.Time
+}
+
+// InMemory is a process local LRU cache. Entries are evicted once MaxItems is exceeded or on first
+// access after their TTL has elapsed.
+type InMemory struct {
+	cfg   InMemoryConfig
+	cache *lru.Cache[string, inMemoryEntry]
+	now   func() time.Time
+
+	items  prometheus.Gauge
+	hits   prometheus.Counter
+	misses prometheus.Counter
+}
+
+// NewInMemory makes a new InMemory cache.
+func NewInMemory(cfg InMemoryConfig, name string, reg prometheus.Registerer) (*InMemory, error) {
+	c, err := lru.New[string, inMemoryEntry](cfg.MaxItems)
+	if err != nil {
+		return nil, err
+	}
+
+	return &InMemory{
+		cfg:   cfg,
+		cache: c,
+		now:   time.Now,
+		items: promauto.With(reg).NewGauge(prometheus.GaugeOpts{
+			Namespace:   "tempo",
+			Name:        "inmemory_cache_items",
+			Help:        "Number of items currently held in the in-memory cache.",
+			ConstLabels: prometheus.Labels{"name": name},
+		}),
+		hits: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+			Namespace:   "tempo",
+			Name:        "inmemory_cache_hits_total",
+			Help:        "Total count of keys found in the in-memory cache.",
+			ConstLabels: prometheus.Labels{"name": name},
+		}),
+		misses: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+			Namespace:   "tempo",
+			Name:        "inmemory_cache_misses_total",
+			Help:        "Total count of keys not found in the in-memory cache.",
+			ConstLabels: prometheus.Labels{"name": name},
+		}),
+	}, nil
+}
+
+// Store adds keys and bufs to the cache.
+func (c *InMemory) Store(_ context.Context, keys []string, bufs [][]byte) {
+	var expires time.Time
+	if c.cfg.TTL > 0 {
+		expires = c.now().Add(c.cfg.TTL)
+	}
+
+	for i := range keys {
+		c.cache.Add(keys[i], inMemoryEntry{buf: bufs[i], expires: expires})
+	}
+	c.items.Set(float64(c.cache.Len()))
+}
+
+// Fetch gets keys from the cache. The keys that are found must be in the order of the keys requested.
+func (c *InMemory) Fetch(_ context.Context, keys []string) (found []string, bufs [][]byte, missed []string) {
+	now := c.now()
+
+	for _, key := range keys {
+		entry, ok := c.cache.Get(key)
+		if ok && !entry.expires.IsZero() && now.After(entry.expires) {
+			c.cache.Remove(key)
+			ok = false
+		}
+
+		if !ok {
+			missed = append(missed, key)
+			continue
+		}
+
+		found = append(found, key)
+		bufs = append(bufs, entry.buf)
+	}
+
+	c.hits.Add(float64(len(found)))
+	c.misses.Add(float64(len(missed)))
+	c.items.Set(float64(c.cache.Len()))
+	return
+}
+
+// Stop implements Cache
+func (c *InMemory) Stop() {
+	c.cache.Purge()
+}
@@ -0,0 +1,45 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInMemory(t *testing.T) {
+	c, err := NewInMemory(InMemoryConfig{MaxItems: 2, TTL: time.Minute}, "test", prometheus.NewRegistry())
+	require.NoError(t, err)
+
+	now := time.Unix(100, 0)
+	c.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	c.Store(ctx, []string{"a", "b"}, [][]byte{[]byte("1"), []byte("2")})
+
+	found, bufs, missed := c.Fetch(ctx, []string{"a", "b", "c"})
+	require.Equal(t, []string{"a", "b"}, found)
+	require.Equal(t, [][]byte{[]byte("1"), []byte("2")}, bufs)
+	require.Equal(t, []string{"c"}, missed)
+
+	// adding a third key evicts the least recently used
+	c.Store(ctx, []string{"c"}, [][]byte{[]byte("3")})
+	found, _, missed = c.Fetch(ctx, []string{"a", "b", "c"})
+	require.Equal(t, []string{"b", "c"}, found)
+	require.Equal(t, []string{"a"}, missed)
+
+	// expired entries are not returned
+	now = now.Add(2 * time.Minute)
+	found, _, missed = c.Fetch(ctx, []string{"b", "c"})
+	require.Empty(t, found)
+	require.Equal(t, []string{"b", "c"}, missed)
+
+	c.Stop()
+}
+
+func TestInMemoryInvalidSize(t *testing.T) {
+	_, err := NewInMemory(InMemoryConfig{MaxItems: 0}, "test", prometheus.NewRegistry())
+	require.Error(t, err)
+}
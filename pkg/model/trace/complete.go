@@ -0,0 +1,63 @@
+package trace
+
+import (
+	"github.com/grafana/tempo/pkg/tempopb"
+)
+
+// IsComplete returns true if the trace has a single root span and the parent of every other span is in the trace.
+// Missing spans that don't break the tree, i.e. leaf spans, can't be detected.
+func IsComplete(tr *tempopb.Trace) bool {
+	c := NewCompleteness()
+	c.Add(tr)
+	return c.IsComplete()
+}
+
+// Completeness tracks if the parts of a trace added so far make up a complete trace as defined by IsComplete. Each
+// part is only read once, so checking the completeness after every part costs as much as checking it once. Spans
+// added more than once are counted once.
+type Completeness struct {
+	spans          map[string]struct{}
+	missingParents map[string]struct{}
+	roots          int
+}
+
+func NewCompleteness() *Completeness {
+	return &Completeness{
+		spans:          map[string]struct{}{},
+		missingParents: map[string]struct{}{},
+	}
+}
+
+// Add adds the spans of a part of the trace. The part is not modified or retained.
+func (c *Completeness) Add(tr *tempopb.Trace) {
+	if tr == nil {
+		return
+	}
+
+	for _, b := range tr.Batches {
+		for _, ss := range b.ScopeSpans {
+			for _, s := range ss.Spans {
+				id := string(s.SpanId)
+				if _, ok := c.spans[id]; ok {
+					continue
+				}
+				c.spans[id] = struct{}{}
+				delete(c.missingParents, id)
+
+				if len(s.ParentSpanId) == 0 {
+					c.roots++
+					continue
+				}
+				if _, ok := c.spans[string(s.ParentSpanId)]; !ok {
+					c.missingParents[string(s.ParentSpanId)] = struct{}{}
+				}
+			}
+		}
+	}
+}
+
+// IsComplete returns true if the spans added so far have a single root span and the parent of every other span was
+// added
+func (c *Completeness) IsComplete() bool {
+	return c.roots == 1 && len(c.missingParents) == 0
+}
@@ -0,0 +1,80 @@
+package trace
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/pkg/tempopb"
+	v1 "github.com/grafana/tempo/pkg/tempopb/trace/v1"
+)
+
+func TestIsComplete(t *testing.T) {
+	span := func(id, parent byte) *v1.Span {
+		s := &v1.Span{SpanId: []byte{id}}
+		if parent != 0 {
+			s.ParentSpanId = []byte{parent}
+		}
+		return s
+	}
+	trace := func(spans ...*v1.Span) *tempopb.Trace {
+		return &tempopb.Trace{Batches: []*v1.ResourceSpans{{ScopeSpans: []*v1.ScopeSpans{{Spans: spans}}}}}
+	}
+
+	tcs := []struct {
+		name     string
+		trace    *tempopb.Trace
+		expected bool
+	}{
+		{name: "nil", trace: nil},
+		{name: "empty", trace: &tempopb.Trace{}},
+		{name: "root only", trace: trace(span(1, 0)), expected: true},
+		{name: "connected", trace: trace(span(1, 0), span(2, 1), span(3, 2), span(4, 1)), expected: true},
+		{name: "no root", trace: trace(span(2, 1), span(3, 2))},
+		{name: "missing parent", trace: trace(span(1, 0), span(3, 2))},
+		{name: "two roots", trace: trace(span(1, 0), span(2, 0))},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, IsComplete(tc.trace))
+		})
+	}
+}
+
+func TestCompleteness(t *testing.T) {
+	span := func(id, parent byte) *v1.Span {
+		s := &v1.Span{SpanId: []byte{id}}
+		if parent != 0 {
+			s.ParentSpanId = []byte{parent}
+		}
+		return s
+	}
+	trace := func(spans ...*v1.Span) *tempopb.Trace {
+		return &tempopb.Trace{Batches: []*v1.ResourceSpans{{ScopeSpans: []*v1.ScopeSpans{{Spans: spans}}}}}
+	}
+
+	c := NewCompleteness()
+	require.False(t, c.IsComplete())
+
+	// children added before their parents
+	c.Add(trace(span(3, 2), span(4, 1)))
+	require.False(t, c.IsComplete())
+
+	c.Add(trace(span(2, 1)))
+	require.False(t, c.IsComplete())
+
+	c.Add(trace(span(1, 0)))
+	require.True(t, c.IsComplete())
+
+	// spans found in more than one part are counted once
+	c.Add(trace(span(1, 0), span(2, 1)))
+	require.True(t, c.IsComplete())
+
+	c.Add(nil)
+	require.True(t, c.IsComplete())
+
+	// a second root
+	c.Add(trace(span(5, 0)))
+	require.False(t, c.IsComplete())
+}
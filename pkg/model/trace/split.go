@@ -0,0 +1,77 @@
+package trace
+
+import (
+	"github.com/grafana/tempo/pkg/tempopb"
+	v1 "github.com/grafana/tempo/pkg/tempopb/trace/v1"
+)
+
+// Split splits a trace into chunks of batches that are roughly at most maxBytes large. Batches larger than maxBytes
+// are split by span into several batches that share the resource and scope of the original batch. A single span
+// larger than maxBytes is returned in a chunk on its own. Combining the chunks returns the original trace.
+func Split(tr *tempopb.Trace, maxBytes int) []*tempopb.Trace {
+	if tr == nil {
+		return nil
+	}
+	if maxBytes <= 0 || tr.Size() <= maxBytes {
+		return []*tempopb.Trace{tr}
+	}
+
+	var (
+		chunks []*tempopb.Trace
+		chunk  = &tempopb.Trace{}
+		size   int
+	)
+
+	add := func(b *v1.ResourceSpans, sz int) {
+		if size > 0 && size+sz > maxBytes {
+			chunks = append(chunks, chunk)
+			chunk = &tempopb.Trace{}
+			size = 0
+		}
+		chunk.Batches = append(chunk.Batches, b)
+		size += sz
+	}
+
+	for _, b := range tr.Batches {
+		sz := b.Size()
+		if sz <= maxBytes {
+			add(b, sz)
+			continue
+		}
+
+		for _, ss := range b.ScopeSpans {
+			var (
+				current     *v1.ResourceSpans
+				currentSize int
+			)
+			for _, s := range ss.Spans {
+				spanSize := s.Size()
+				if current != nil && currentSize+spanSize > maxBytes {
+					add(current, currentSize)
+					current = nil
+				}
+				if current == nil {
+					current = &v1.ResourceSpans{
+						Resource:  b.Resource,
+						SchemaUrl: b.SchemaUrl,
+						ScopeSpans: []*v1.ScopeSpans{{
+							Scope:     ss.Scope,
+							SchemaUrl: ss.SchemaUrl,
+						}},
+					}
+					currentSize = current.Size()
+				}
+				current.ScopeSpans[0].Spans = append(current.ScopeSpans[0].Spans, s)
+				currentSize += spanSize
+			}
+			if current != nil {
+				add(current, currentSize)
+			}
+		}
+	}
+
+	if len(chunk.Batches) > 0 {
+		chunks = append(chunks, chunk)
+	}
+	return chunks
+}
@@ -0,0 +1,92 @@
+package trace
+
+import (
+	"testing"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/pkg/tempopb"
+	v1 "github.com/grafana/tempo/pkg/tempopb/trace/v1"
+	"github.com/grafana/tempo/pkg/util/test"
+)
+
+func TestSplit(t *testing.T) {
+	traceID := test.ValidTraceID(nil)
+	tr := test.MakeTraceWithSpanCount(3, 20, traceID)
+	tr.Batches = append(tr.Batches, test.MakeBatch(200, traceID)) // a batch larger than the chunk size
+
+	spanSize := tr.Batches[0].ScopeSpans[0].Spans[0].Size()
+
+	tcs := []struct {
+		name           string
+		maxBytes       int
+		expectedChunks int
+	}{
+		{name: "no limit", maxBytes: 0, expectedChunks: 1},
+		{name: "larger than trace", maxBytes: tr.Size(), expectedChunks: 1},
+		{name: "batches", maxBytes: tr.Batches[0].Size() * 3},
+		{name: "spans", maxBytes: spanSize * 10},
+		{name: "smaller than span", maxBytes: 1, expectedChunks: 260},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			chunks := Split(tr, tc.maxBytes)
+			if tc.expectedChunks > 0 {
+				require.Len(t, chunks, tc.expectedChunks)
+			} else {
+				require.Greater(t, len(chunks), 1)
+			}
+
+			for _, chunk := range chunks {
+				require.NotEmpty(t, chunk.Batches)
+				// a chunk only exceeds the limit by the resource and scope of the batches it holds
+				if tc.maxBytes > spanSize && len(chunk.Batches) > 1 {
+					require.LessOrEqual(t, chunk.Size(), tc.maxBytes+len(chunk.Batches)*64)
+				}
+			}
+
+			// reassembling the chunks returns all spans in order
+			require.Equal(t, spanIDs(tr), spanIDs(reassemble(chunks)))
+		})
+	}
+
+	require.Nil(t, Split(nil, 100))
+}
+
+func TestSplitKeepsResourceAndScope(t *testing.T) {
+	tr := &tempopb.Trace{Batches: []*v1.ResourceSpans{test.MakeBatch(50, nil)}}
+
+	chunks := Split(tr, tr.Size()/5)
+	require.Greater(t, len(chunks), 1)
+
+	for _, chunk := range chunks {
+		for _, b := range chunk.Batches {
+			require.True(t, proto.Equal(tr.Batches[0].Resource, b.Resource))
+			for _, ss := range b.ScopeSpans {
+				require.True(t, proto.Equal(tr.Batches[0].ScopeSpans[0].Scope, ss.Scope))
+			}
+		}
+	}
+}
+
+func reassemble(chunks []*tempopb.Trace) *tempopb.Trace {
+	tr := &tempopb.Trace{}
+	for _, chunk := range chunks {
+		tr.Batches = append(tr.Batches, chunk.Batches...)
+	}
+	return tr
+}
+
+func spanIDs(tr *tempopb.Trace) [][]byte {
+	var ids [][]byte
+	for _, b := range tr.Batches {
+		for _, ss := range b.ScopeSpans {
+			for _, s := range ss.Spans {
+				ids = append(ids, s.SpanId)
+			}
+		}
+	}
+	return ids
+}
@@ -0,0 +1,118 @@
+package trace
+
+import (
+	"strings"
+
+	"github.com/grafana/tempo/pkg/tempopb"
+	v1_common "github.com/grafana/tempo/pkg/tempopb/common/v1"
+)
+
+const (
+	StripEvents     = "events"
+	StripLinks      = "links"
+	StripAttributes = "attributes"
+
+	// service.name is kept when stripping attributes. without it the trace can't be visualized
+	serviceNameAttribute = "service.name"
+)
+
+// StripOptions controls which parts of a trace are removed by Strip.
+type StripOptions struct {
+	Events     bool
+	Links      bool
+	Attributes bool
+}
+
+// ParseStripOptions parses a comma separated list of the parts of a trace to remove. e.g. "events,links"
+func ParseStripOptions(s string) (StripOptions, bool) {
+	opts := StripOptions{}
+	if s == "" {
+		return opts, true
+	}
+
+	for _, part := range strings.Split(s, ",") {
+		switch strings.TrimSpace(part) {
+		case StripEvents:
+			opts.Events = true
+		case StripLinks:
+			opts.Links = true
+		case StripAttributes:
+			opts.Attributes = true
+		default:
+			return StripOptions{}, false
+		}
+	}
+
+	return opts, true
+}
+
+// IsEmpty returns true if nothing is removed from the trace.
+func (o StripOptions) IsEmpty() bool {
+	return !o.Events && !o.Links && !o.Attributes
+}
+
+// String returns the options in the same format accepted by ParseStripOptions. The order is stable so
+// it can be used in cache keys.
+func (o StripOptions) String() string {
+	parts := make([]string, 0, 3)
+	if o.Attributes {
+		parts = append(parts, StripAttributes)
+	}
+	if o.Events {
+		parts = append(parts, StripEvents)
+	}
+	if o.Links {
+		parts = append(parts, StripLinks)
+	}
+	return strings.Join(parts, ",")
+}
+
+// Strip removes span events, span links and attributes from the trace in place. When removing attributes the
+// resource attribute service.name is kept.
+func Strip(t *tempopb.Trace, opts StripOptions) {
+	if t == nil || opts.IsEmpty() {
+		return
+	}
+
+	for _, b := range t.Batches {
+		if opts.Attributes && b.Resource != nil {
+			b.Resource.Attributes = keepServiceName(b.Resource.Attributes)
+		}
+
+		for _, ss := range b.ScopeSpans {
+			if opts.Attributes && ss.Scope != nil {
+				ss.Scope.Attributes = nil
+			}
+
+			for _, s := range ss.Spans {
+				if opts.Events {
+					s.Events = nil
+					s.DroppedEventsCount = 0
+				}
+				if opts.Links {
+					s.Links = nil
+					s.DroppedLinksCount = 0
+				}
+				if opts.Attributes {
+					s.Attributes = nil
+					s.DroppedAttributesCount = 0
+					for _, e := range s.Events {
+						e.Attributes = nil
+					}
+					for _, l := range s.Links {
+						l.Attributes = nil
+					}
+				}
+			}
+		}
+	}
+}
+
+func keepServiceName(attrs []*v1_common.KeyValue) []*v1_common.KeyValue {
+	for _, a := range attrs {
+		if a.Key == serviceNameAttribute {
+			return []*v1_common.KeyValue{a}
+		}
+	}
+	return nil
+}
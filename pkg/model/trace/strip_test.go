@@ -0,0 +1,106 @@
+package trace
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/pkg/tempopb"
+	v1_common "github.com/grafana/tempo/pkg/tempopb/common/v1"
+	v1_resource "github.com/grafana/tempo/pkg/tempopb/resource/v1"
+	v1 "github.com/grafana/tempo/pkg/tempopb/trace/v1"
+)
+
+func TestParseStripOptions(t *testing.T) {
+	tcs := []struct {
+		input    string
+		expected StripOptions
+		ok       bool
+	}{
+		{input: "", expected: StripOptions{}, ok: true},
+		{input: "events", expected: StripOptions{Events: true}, ok: true},
+		{input: "links, attributes", expected: StripOptions{Links: true, Attributes: true}, ok: true},
+		{input: "events,links,attributes", expected: StripOptions{Events: true, Links: true, Attributes: true}, ok: true},
+		{input: "spans"},
+		{input: "events,"},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.input, func(t *testing.T) {
+			actual, ok := ParseStripOptions(tc.input)
+			require.Equal(t, tc.ok, ok)
+			require.Equal(t, tc.expected, actual)
+
+			if ok {
+				// string is stable and round trips
+				roundTrip, ok := ParseStripOptions(actual.String())
+				require.True(t, ok)
+				require.Equal(t, actual, roundTrip)
+			}
+		})
+	}
+
+	require.Equal(t, "attributes,events,links", StripOptions{Events: true, Links: true, Attributes: true}.String())
+}
+
+func TestStrip(t *testing.T) {
+	kv := func(k string) *v1_common.KeyValue {
+		return &v1_common.KeyValue{Key: k, Value: &v1_common.AnyValue{Value: &v1_common.AnyValue_StringValue{StringValue: "v"}}}
+	}
+
+	makeTrace := func() *tempopb.Trace {
+		return &tempopb.Trace{
+			Batches: []*v1.ResourceSpans{
+				{
+					Resource: &v1_resource.Resource{Attributes: []*v1_common.KeyValue{kv("cluster"), kv("service.name")}},
+					ScopeSpans: []*v1.ScopeSpans{
+						{
+							Scope: &v1_common.InstrumentationScope{Name: "scope", Attributes: []*v1_common.KeyValue{kv("scope")}},
+							Spans: []*v1.Span{
+								{
+									Name:                   "span",
+									SpanId:                 []byte{0x01},
+									Attributes:             []*v1_common.KeyValue{kv("span")},
+									DroppedAttributesCount: 1,
+									Events:                 []*v1.Span_Event{{Name: "event", Attributes: []*v1_common.KeyValue{kv("event")}}},
+									DroppedEventsCount:     2,
+									Links:                  []*v1.Span_Link{{SpanId: []byte{0x02}, Attributes: []*v1_common.KeyValue{kv("link")}}},
+									DroppedLinksCount:      3,
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	// nothing to strip
+	tr := makeTrace()
+	Strip(tr, StripOptions{})
+	require.Equal(t, makeTrace(), tr)
+
+	// events and links
+	tr = makeTrace()
+	Strip(tr, StripOptions{Events: true, Links: true})
+	expected := makeTrace()
+	span := expected.Batches[0].ScopeSpans[0].Spans[0]
+	span.Events, span.DroppedEventsCount = nil, 0
+	span.Links, span.DroppedLinksCount = nil, 0
+	require.Equal(t, expected, tr)
+
+	// attributes keep service.name
+	tr = makeTrace()
+	Strip(tr, StripOptions{Attributes: true})
+	expected = makeTrace()
+	expected.Batches[0].Resource.Attributes = []*v1_common.KeyValue{kv("service.name")}
+	expected.Batches[0].ScopeSpans[0].Scope.Attributes = nil
+	span = expected.Batches[0].ScopeSpans[0].Spans[0]
+	span.Attributes, span.DroppedAttributesCount = nil, 0
+	span.Events[0].Attributes = nil
+	span.Links[0].Attributes = nil
+	require.Equal(t, expected, tr)
+
+	// nil trace is a no-op
+	Strip(nil, StripOptions{Events: true})
+}
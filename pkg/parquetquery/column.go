@@ -51,6 +51,21 @@ func (h *ColumnChunkHelper) NextPage() (parquet.Page, error) {
 	return h.pages.ReadPage()
 }
 
+// SeekToRow positions the pages at the row, relative to the start of the row group. The next page is the one with the
+// row.
+func (h *ColumnChunkHelper) SeekToRow(row int64) error {
+	if h.firstPage != nil {
+		parquet.Release(h.firstPage)
+		h.firstPage = nil
+	}
+
+	if h.pages == nil {
+		h.pages = h.ColumnChunk.Pages()
+	}
+
+	return h.pages.SeekToRow(row)
+}
+
 func (h *ColumnChunkHelper) Close() error {
 	if h.firstPage != nil {
 		parquet.Release(h.firstPage)
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io"
 	"math"
+	"sort"
 	"sync"
 	"sync/atomic"
 
@@ -553,6 +554,9 @@ type SyncIterator struct {
 
 	intern   bool
 	interner *intern.Interner
+
+	// valuePerRow is set if the column has a value per row, so its pages start at row boundaries
+	valuePerRow *bool
 }
 
 var _ Iterator = (*SyncIterator)(nil)
@@ -865,15 +869,25 @@ func (c *SyncIterator) next() (RowNumber, *pq.Value, error) {
 		}
 
 		if c.currPage == nil {
-			pg, err := c.currChunk.NextPage()
-			if pg == nil || errors.Is(err, io.EOF) {
-				// This row group is exhausted
+			done, err := c.skipPagesByIndex()
+			if err != nil {
+				return EmptyRowNumber(), nil, err
+			}
+			if done {
+				// The rest of the row group is filtered out
 				c.closeCurrRowGroup()
 				continue
 			}
-			if err != nil {
+
+			pg, err := c.currChunk.NextPage()
+			if err != nil && !errors.Is(err, io.EOF) {
 				return EmptyRowNumber(), nil, err
 			}
+			if pg == nil || err != nil {
+				// This row group is exhausted
+				c.closeCurrRowGroup()
+				continue
+			}
 			if c.filter != nil && !c.filter.KeepPage(pg) {
 				// This page filtered out
 				c.curr.Skip(pg.NumRows())
@@ -921,6 +935,68 @@ func (c *SyncIterator) next() (RowNumber, *pq.Value, error) {
 	}
 }
 
+// skipPagesByIndex skips the next pages of the current column chunk that the filter rejects by their min and max
+// values in the column index, without reading them. It only skips pages of columns with a value per row, after the
+// first page is read so the dictionary is loaded. It returns true if all the remaining pages are skipped.
+func (c *SyncIterator) skipPagesByIndex() (bool, error) {
+	pred, ok := c.filter.(PageIndexPredicate)
+	if !ok || c.currChunk.pages == nil || !c.hasValuePerRow() {
+		return false, nil
+	}
+
+	ci, err := c.currChunk.ColumnIndex()
+	if err != nil || ci == nil {
+		return false, nil
+	}
+	oi, err := c.currChunk.OffsetIndex()
+	if err != nil || oi == nil || oi.NumPages() != ci.NumPages() {
+		return false, nil
+	}
+
+	// the next page starts at the next row
+	row := int64(c.curr[0] - c.currRowGroupMin[0])
+	page := sort.Search(oi.NumPages(), func(i int) bool { return oi.FirstRowIndex(i) > row }) - 1
+	if page < 0 || oi.FirstRowIndex(page) != row {
+		return false, nil
+	}
+
+	next := page
+	for next < ci.NumPages() && !ci.NullPage(next) && !pred.KeepPageBounds(ci.MinValue(next), ci.MaxValue(next)) {
+		next++
+	}
+	if next == page {
+		return false, nil
+	}
+	if next == ci.NumPages() {
+		return true, nil
+	}
+
+	if err := c.currChunk.SeekToRow(oi.FirstRowIndex(next)); err != nil {
+		return false, err
+	}
+	c.curr.Skip(oi.FirstRowIndex(next) - row)
+	return false, nil
+}
+
+func (c *SyncIterator) hasValuePerRow() bool {
+	if c.valuePerRow == nil {
+		valuePerRow := false
+		if len(c.rgs) > 0 || c.currRowGroup != nil {
+			rg := c.currRowGroup
+			if rg == nil {
+				rg = c.rgs[0]
+			}
+			columns := rg.Schema().Columns()
+			if c.column < len(columns) {
+				leaf, ok := rg.Schema().Lookup(columns[c.column]...)
+				valuePerRow = ok && leaf.MaxRepetitionLevel == 0
+			}
+		}
+		c.valuePerRow = &valuePerRow
+	}
+	return *c.valuePerRow
+}
+
 func (c *SyncIterator) setRowGroup(rg pq.RowGroup, min, max RowNumber, cc *ColumnChunkHelper) {
 	c.closeCurrRowGroup()
 	c.curr = min
@@ -1126,13 +1202,13 @@ func (c *ColumnIterator) iterate(ctx context.Context, readSize int) {
 			}()
 			for {
 				pg, err := col.NextPage()
-				if pg == nil || errors.Is(err, io.EOF) {
-					break
-				}
-				if err != nil {
+				if err != nil && !errors.Is(err, io.EOF) {
 					c.storeErr("column iterator read page", err)
 					return
 				}
+				if pg == nil || err != nil {
+					break
+				}
 
 				stop := func(pg pq.Page) (stop bool) {
 					defer pq.Release(pg)
@@ -194,6 +194,79 @@ func testColumnIteratorPredicate(t *testing.T, makeIter makeTestIterFn) {
 	}
 }
 
+type countingPagesPredicate struct {
+	*IntBetweenPredicate
+	pages int
+}
+
+func (p *countingPagesPredicate) KeepPage(page parquet.Page) bool {
+	p.pages++
+	return p.IntBetweenPredicate.KeepPage(page)
+}
+
+func TestSyncIteratorSkipsPagesByIndex(t *testing.T) {
+	type T struct{ A int }
+
+	rows := []T{}
+	for i := 0; i < 2000; i++ {
+		rows = append(rows, T{i})
+	}
+
+	f, err := os.CreateTemp(t.TempDir(), "data.parquet")
+	require.NoError(t, err)
+	w := parquet.NewGenericWriter[T](f, parquet.PageBufferSize(100))
+	for i := 0; i < len(rows); i += 1000 {
+		_, err = w.Write(rows[i : i+1000])
+		require.NoError(t, err)
+		require.NoError(t, w.Flush())
+	}
+	require.NoError(t, w.Close())
+	stat, err := f.Stat()
+	require.NoError(t, err)
+	pf, err := parquet.OpenFile(f, stat.Size())
+	require.NoError(t, err)
+
+	idx, _ := GetColumnIndexByPath(pf, "A")
+	pages := 0
+	for _, rg := range pf.RowGroups() {
+		oi, err := rg.ColumnChunks()[idx].OffsetIndex()
+		require.NoError(t, err)
+		pages += oi.NumPages()
+	}
+	require.Greater(t, pages, 20)
+
+	for _, tc := range []struct {
+		name     string
+		min, max int64
+		expected []int32
+	}{
+		{name: "middle of the row groups", min: 1500, max: 1502, expected: []int32{1500, 1501, 1502}},
+		{name: "end of the row group", min: 999, max: 1000, expected: []int32{999, 1000}},
+		{name: "no match", min: 5000, max: 6000},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			pred := &countingPagesPredicate{IntBetweenPredicate: NewIntBetweenPredicate(tc.min, tc.max)}
+			iter := NewSyncIterator(context.TODO(), pf.RowGroups(), idx, "A", 1000, pred, "A")
+			defer iter.Close()
+
+			var actual []int32
+			for {
+				res, err := iter.Next()
+				require.NoError(t, err)
+				if res == nil {
+					break
+				}
+				require.Equal(t, RowNumber{res.ToMap()["A"][0].Int32(), -1, -1, -1, -1, -1}, res.RowNumber)
+				actual = append(actual, res.RowNumber[0])
+			}
+			require.Equal(t, tc.expected, actual)
+
+			// only the first page of a row group and the pages with matches are read
+			require.LessOrEqual(t, pred.pages, 4)
+		})
+	}
+}
+
 func TestColumnIteratorExitEarly(t *testing.T) {
 	type T struct{ A int }
 
@@ -19,6 +19,14 @@ type Predicate interface {
 	KeepValue(pq.Value) bool
 }
 
+// PageIndexPredicate is a Predicate that can check the min and max values of a page in the column index. The
+// SyncIterator skips the pages it rejects without reading them.
+type PageIndexPredicate interface {
+	Predicate
+
+	KeepPageBounds(min, max pq.Value) bool
+}
+
 // StringInPredicate checks for any of the given strings.
 // Case sensitive exact byte matching
 type StringInPredicate struct {
@@ -238,7 +246,7 @@ type IntBetweenPredicate struct {
 	min, max int64
 }
 
-var _ Predicate = (*IntBetweenPredicate)(nil)
+var _ PageIndexPredicate = (*IntBetweenPredicate)(nil)
 
 func NewIntBetweenPredicate(min, max int64) *IntBetweenPredicate {
 	return &IntBetweenPredicate{min, max}
@@ -252,9 +260,7 @@ func (p *IntBetweenPredicate) KeepColumnChunk(c *ColumnChunkHelper) bool {
 	ci, err := c.ColumnIndex()
 	if err == nil && ci != nil {
 		for i := 0; i < ci.NumPages(); i++ {
-			min := ci.MinValue(i).Int64()
-			max := ci.MaxValue(i).Int64()
-			if p.max >= min && p.min <= max {
+			if p.KeepPageBounds(ci.MinValue(i), ci.MaxValue(i)) {
 				return true
 			}
 		}
@@ -271,11 +277,15 @@ func (p *IntBetweenPredicate) KeepValue(v pq.Value) bool {
 
 func (p *IntBetweenPredicate) KeepPage(page pq.Page) bool {
 	if min, max, ok := page.Bounds(); ok {
-		return p.max >= min.Int64() && p.min <= max.Int64()
+		return p.KeepPageBounds(min, max)
 	}
 	return true
 }
 
+func (p *IntBetweenPredicate) KeepPageBounds(min, max pq.Value) bool {
+	return p.max >= min.Int64() && p.min <= max.Int64()
+}
+
 // GenericPredicate with callbacks to evaluate data of type T
 // Fn evaluates a single data point and is required. Optionally,
 // a RangeFn can evaluate a min/max range and is used to
@@ -230,6 +230,9 @@ func (m *TraceByIDResponse) GetMetrics() *TraceByIDMetrics {
 }
 
 type TraceByIDMetrics struct {
+	CompletedJobs uint32 `protobuf:"varint,1,opt,name=completedJobs,proto3" json:"completedJobs,omitempty"`
+	TotalJobs     uint32 `protobuf:"varint,2,opt,name=totalJobs,proto3" json:"totalJobs,omitempty"`
+	SkippedBlocks uint32 `protobuf:"varint,3,opt,name=skippedBlocks,proto3" json:"skippedBlocks,omitempty"`
 }
 
 func (m *TraceByIDMetrics) Reset()         { *m = TraceByIDMetrics{} }
@@ -265,6 +268,27 @@ func (m *TraceByIDMetrics) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_TraceByIDMetrics proto.InternalMessageInfo
 
+func (m *TraceByIDMetrics) GetCompletedJobs() uint32 {
+	if m != nil {
+		return m.CompletedJobs
+	}
+	return 0
+}
+
+func (m *TraceByIDMetrics) GetTotalJobs() uint32 {
+	if m != nil {
+		return m.TotalJobs
+	}
+	return 0
+}
+
+func (m *TraceByIDMetrics) GetSkippedBlocks() uint32 {
+	if m != nil {
+		return m.SkippedBlocks
+	}
+	return 0
+}
+
 // SearchRequest takes no block parameters and implies a "recent traces" search
 type SearchRequest struct {
 	// case insensitive partial match
@@ -624,6 +648,7 @@ type TraceSearchMetadata struct {
 	SpanSet           *SpanSet                 `protobuf:"bytes,6,opt,name=spanSet,proto3" json:"spanSet,omitempty"`
 	SpanSets          []*SpanSet               `protobuf:"bytes,7,rep,name=spanSets,proto3" json:"spanSets,omitempty"`
 	ServiceStats      map[string]*ServiceStats `protobuf:"bytes,8,rep,name=serviceStats,proto3" json:"serviceStats,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Clusters          []string                 `protobuf:"bytes,9,rep,name=clusters,proto3" json:"clusters,omitempty"`
 }
 
 func (m *TraceSearchMetadata) Reset()         { *m = TraceSearchMetadata{} }
@@ -715,6 +740,13 @@ func (m *TraceSearchMetadata) GetServiceStats() map[string]*ServiceStats {
 	return nil
 }
 
+func (m *TraceSearchMetadata) GetClusters() []string {
+	if m != nil {
+		return m.Clusters
+	}
+	return nil
+}
+
 type ServiceStats struct {
 	SpanCount  uint32 `protobuf:"varint,1,opt,name=spanCount,proto3" json:"spanCount,omitempty"`
 	ErrorCount uint32 `protobuf:"varint,2,opt,name=errorCount,proto3" json:"errorCount,omitempty"`
@@ -911,6 +943,7 @@ type SearchMetrics struct {
 	TotalJobs       uint32 `protobuf:"varint,5,opt,name=totalJobs,proto3" json:"totalJobs,omitempty"`
 	TotalBlockBytes uint64 `protobuf:"varint,6,opt,name=totalBlockBytes,proto3" json:"totalBlockBytes,omitempty"`
 	InspectedSpans  uint64 `protobuf:"varint,7,opt,name=inspectedSpans,proto3" json:"inspectedSpans,omitempty"`
+	SkippedBlocks   uint32 `protobuf:"varint,8,opt,name=skippedBlocks,proto3" json:"skippedBlocks,omitempty"`
 }
 
 func (m *SearchMetrics) Reset()         { *m = SearchMetrics{} }
@@ -995,6 +1028,13 @@ func (m *SearchMetrics) GetInspectedSpans() uint64 {
 	return 0
 }
 
+func (m *SearchMetrics) GetSkippedBlocks() uint32 {
+	if m != nil {
+		return m.SkippedBlocks
+	}
+	return 0
+}
+
 type SearchTagsRequest struct {
 	Scope string `protobuf:"bytes,1,opt,name=scope,proto3" json:"scope,omitempty"`
 	Start uint32 `protobuf:"varint,3,opt,name=start,proto3" json:"start,omitempty"`
@@ -1531,6 +1571,7 @@ func (m *SearchTagValuesRequest) GetEnd() uint32 {
 
 type SearchTagValuesResponse struct {
 	TagValues []string `protobuf:"bytes,1,rep,name=tagValues,proto3" json:"tagValues,omitempty"`
+	Partial   bool     `protobuf:"varint,2,opt,name=partial,proto3" json:"partial,omitempty"`
 }
 
 func (m *SearchTagValuesResponse) Reset()         { *m = SearchTagValuesResponse{} }
@@ -1573,6 +1614,13 @@ func (m *SearchTagValuesResponse) GetTagValues() []string {
 	return nil
 }
 
+func (m *SearchTagValuesResponse) GetPartial() bool {
+	if m != nil {
+		return m.Partial
+	}
+	return false
+}
+
 type TagValue struct {
 	Type  string `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
 	Value string `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
@@ -1627,6 +1675,7 @@ func (m *TagValue) GetValue() string {
 
 type SearchTagValuesV2Response struct {
 	TagValues []*TagValue `protobuf:"bytes,1,rep,name=tagValues,proto3" json:"tagValues,omitempty"`
+	Partial   bool        `protobuf:"varint,2,opt,name=partial,proto3" json:"partial,omitempty"`
 }
 
 func (m *SearchTagValuesV2Response) Reset()         { *m = SearchTagValuesV2Response{} }
@@ -1669,6 +1718,13 @@ func (m *SearchTagValuesV2Response) GetTagValues() []*TagValue {
 	return nil
 }
 
+func (m *SearchTagValuesV2Response) GetPartial() bool {
+	if m != nil {
+		return m.Partial
+	}
+	return false
+}
+
 type Trace struct {
 	Batches []*v11.ResourceSpans `protobuf:"bytes,1,rep,name=batches,proto3" json:"batches,omitempty"`
 }
@@ -1715,7 +1771,8 @@ func (m *Trace) GetBatches() []*v11.ResourceSpans {
 
 // Write
 type PushResponse struct {
-	ErrorsByTrace []PushErrorReason `protobuf:"varint,1,rep,packed,name=errorsByTrace,proto3,enum=tempopb.PushErrorReason" json:"errorsByTrace,omitempty"`
+	ErrorsByTrace []PushErrorReason   `protobuf:"varint,1,rep,packed,name=errorsByTrace,proto3,enum=tempopb.PushErrorReason" json:"errorsByTrace,omitempty"`
+	LimitDetails  []*PushLimitDetails `protobuf:"bytes,2,rep,name=limitDetails,proto3" json:"limitDetails,omitempty"`
 }
 
 func (m *PushResponse) Reset()         { *m = PushResponse{} }
@@ -1758,6 +1815,89 @@ func (m *PushResponse) GetErrorsByTrace() []PushErrorReason {
 	return nil
 }
 
+func (m *PushResponse) GetLimitDetails() []*PushLimitDetails {
+	if m != nil {
+		return m.LimitDetails
+	}
+	return nil
+}
+
+type PushLimitDetails struct {
+	Reason     PushErrorReason `protobuf:"varint,1,opt,name=reason,proto3,enum=tempopb.PushErrorReason" json:"reason,omitempty"`
+	Limit      string          `protobuf:"bytes,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	Configured int64           `protobuf:"varint,3,opt,name=configured,proto3" json:"configured,omitempty"`
+	Effective  int64           `protobuf:"varint,4,opt,name=effective,proto3" json:"effective,omitempty"`
+	Measured   int64           `protobuf:"varint,5,opt,name=measured,proto3" json:"measured,omitempty"`
+}
+
+func (m *PushLimitDetails) Reset()         { *m = PushLimitDetails{} }
+func (m *PushLimitDetails) String() string { return proto.CompactTextString(m) }
+func (*PushLimitDetails) ProtoMessage()    {}
+func (*PushLimitDetails) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f22805646f4f62b6, []int{24}
+}
+func (m *PushLimitDetails) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *PushLimitDetails) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_PushLimitDetails.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *PushLimitDetails) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PushLimitDetails.Merge(m, src)
+}
+func (m *PushLimitDetails) XXX_Size() int {
+	return m.Size()
+}
+func (m *PushLimitDetails) XXX_DiscardUnknown() {
+	xxx_messageInfo_PushLimitDetails.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PushLimitDetails proto.InternalMessageInfo
+
+func (m *PushLimitDetails) GetReason() PushErrorReason {
+	if m != nil {
+		return m.Reason
+	}
+	return PushErrorReason_NO_ERROR
+}
+
+func (m *PushLimitDetails) GetLimit() string {
+	if m != nil {
+		return m.Limit
+	}
+	return ""
+}
+
+func (m *PushLimitDetails) GetConfigured() int64 {
+	if m != nil {
+		return m.Configured
+	}
+	return 0
+}
+
+func (m *PushLimitDetails) GetEffective() int64 {
+	if m != nil {
+		return m.Effective
+	}
+	return 0
+}
+
+func (m *PushLimitDetails) GetMeasured() int64 {
+	if m != nil {
+		return m.Measured
+	}
+	return 0
+}
+
 // PushBytesRequest pushes slices of traces, ids and searchdata. Traces are
 // encoded using the
 //  current BatchDecoder in ./pkg/model
@@ -1774,7 +1914,7 @@ func (m *PushBytesRequest) Reset()         { *m = PushBytesRequest{} }
 func (m *PushBytesRequest) String() string { return proto.CompactTextString(m) }
 func (*PushBytesRequest) ProtoMessage()    {}
 func (*PushBytesRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{24}
+	return fileDescriptor_f22805646f4f62b6, []int{25}
 }
 func (m *PushBytesRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1812,7 +1952,7 @@ func (m *PushSpansRequest) Reset()         { *m = PushSpansRequest{} }
 func (m *PushSpansRequest) String() string { return proto.CompactTextString(m) }
 func (*PushSpansRequest) ProtoMessage()    {}
 func (*PushSpansRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{25}
+	return fileDescriptor_f22805646f4f62b6, []int{26}
 }
 func (m *PushSpansRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1848,6 +1988,121 @@ func (m *PushSpansRequest) GetBatches() []*v11.ResourceSpans {
 	return nil
 }
 
+type PushTracesRequest struct {
+	// chosen by the client and returned in the response to this request
+	Id      uint64               `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Batches []*v11.ResourceSpans `protobuf:"bytes,2,rep,name=batches,proto3" json:"batches,omitempty"`
+}
+
+func (m *PushTracesRequest) Reset()         { *m = PushTracesRequest{} }
+func (m *PushTracesRequest) String() string { return proto.CompactTextString(m) }
+func (*PushTracesRequest) ProtoMessage()    {}
+func (*PushTracesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f22805646f4f62b6, []int{27}
+}
+func (m *PushTracesRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *PushTracesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_PushTracesRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *PushTracesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PushTracesRequest.Merge(m, src)
+}
+func (m *PushTracesRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *PushTracesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_PushTracesRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PushTracesRequest proto.InternalMessageInfo
+
+func (m *PushTracesRequest) GetId() uint64 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+func (m *PushTracesRequest) GetBatches() []*v11.ResourceSpans {
+	if m != nil {
+		return m.Batches
+	}
+	return nil
+}
+
+type PushTracesResponse struct {
+	// id of the acknowledged request
+	Id uint64 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	// grpc status code of the error. 0 if the batches were accepted
+	ErrorCode    uint32 `protobuf:"varint,2,opt,name=errorCode,proto3" json:"errorCode,omitempty"`
+	ErrorMessage string `protobuf:"bytes,3,opt,name=errorMessage,proto3" json:"errorMessage,omitempty"`
+}
+
+func (m *PushTracesResponse) Reset()         { *m = PushTracesResponse{} }
+func (m *PushTracesResponse) String() string { return proto.CompactTextString(m) }
+func (*PushTracesResponse) ProtoMessage()    {}
+func (*PushTracesResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f22805646f4f62b6, []int{28}
+}
+func (m *PushTracesResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *PushTracesResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_PushTracesResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *PushTracesResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PushTracesResponse.Merge(m, src)
+}
+func (m *PushTracesResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *PushTracesResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_PushTracesResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PushTracesResponse proto.InternalMessageInfo
+
+func (m *PushTracesResponse) GetId() uint64 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+func (m *PushTracesResponse) GetErrorCode() uint32 {
+	if m != nil {
+		return m.ErrorCode
+	}
+	return 0
+}
+
+func (m *PushTracesResponse) GetErrorMessage() string {
+	if m != nil {
+		return m.ErrorMessage
+	}
+	return ""
+}
+
 type TraceBytes struct {
 	// pre-marshalled Traces
 	Traces [][]byte `protobuf:"bytes,1,rep,name=traces,proto3" json:"traces,omitempty"`
@@ -1857,7 +2112,7 @@ func (m *TraceBytes) Reset()         { *m = TraceBytes{} }
 func (m *TraceBytes) String() string { return proto.CompactTextString(m) }
 func (*TraceBytes) ProtoMessage()    {}
 func (*TraceBytes) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{26}
+	return fileDescriptor_f22805646f4f62b6, []int{29}
 }
 func (m *TraceBytes) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1905,7 +2160,7 @@ func (m *LinkSlice) Reset()         { *m = LinkSlice{} }
 func (m *LinkSlice) String() string { return proto.CompactTextString(m) }
 func (*LinkSlice) ProtoMessage()    {}
 func (*LinkSlice) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{27}
+	return fileDescriptor_f22805646f4f62b6, []int{30}
 }
 func (m *LinkSlice) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1953,7 +2208,7 @@ func (m *SpanMetricsRequest) Reset()         { *m = SpanMetricsRequest{} }
 func (m *SpanMetricsRequest) String() string { return proto.CompactTextString(m) }
 func (*SpanMetricsRequest) ProtoMessage()    {}
 func (*SpanMetricsRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{28}
+	return fileDescriptor_f22805646f4f62b6, []int{31}
 }
 func (m *SpanMetricsRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2029,7 +2284,7 @@ func (m *SpanMetricsSummaryRequest) Reset()         { *m = SpanMetricsSummaryReq
 func (m *SpanMetricsSummaryRequest) String() string { return proto.CompactTextString(m) }
 func (*SpanMetricsSummaryRequest) ProtoMessage()    {}
 func (*SpanMetricsSummaryRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{29}
+	return fileDescriptor_f22805646f4f62b6, []int{32}
 }
 func (m *SpanMetricsSummaryRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2098,13 +2353,15 @@ type SpanMetricsResponse struct {
 	SpanCount      uint64         `protobuf:"varint,2,opt,name=spanCount,proto3" json:"spanCount,omitempty"`
 	ErrorSpanCount uint64         `protobuf:"varint,3,opt,name=errorSpanCount,proto3" json:"errorSpanCount,omitempty"`
 	Metrics        []*SpanMetrics `protobuf:"bytes,4,rep,name=metrics,proto3" json:"metrics,omitempty"`
+	Start          uint32         `protobuf:"varint,5,opt,name=start,proto3" json:"start,omitempty"`
+	End            uint32         `protobuf:"varint,6,opt,name=end,proto3" json:"end,omitempty"`
 }
 
 func (m *SpanMetricsResponse) Reset()         { *m = SpanMetricsResponse{} }
 func (m *SpanMetricsResponse) String() string { return proto.CompactTextString(m) }
 func (*SpanMetricsResponse) ProtoMessage()    {}
 func (*SpanMetricsResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{30}
+	return fileDescriptor_f22805646f4f62b6, []int{33}
 }
 func (m *SpanMetricsResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2161,6 +2418,20 @@ func (m *SpanMetricsResponse) GetMetrics() []*SpanMetrics {
 	return nil
 }
 
+func (m *SpanMetricsResponse) GetStart() uint32 {
+	if m != nil {
+		return m.Start
+	}
+	return 0
+}
+
+func (m *SpanMetricsResponse) GetEnd() uint32 {
+	if m != nil {
+		return m.End
+	}
+	return 0
+}
+
 type RawHistogram struct {
 	Bucket uint64 `protobuf:"varint,1,opt,name=bucket,proto3" json:"bucket,omitempty"`
 	Count  uint64 `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
@@ -2170,7 +2441,7 @@ func (m *RawHistogram) Reset()         { *m = RawHistogram{} }
 func (m *RawHistogram) String() string { return proto.CompactTextString(m) }
 func (*RawHistogram) ProtoMessage()    {}
 func (*RawHistogram) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{31}
+	return fileDescriptor_f22805646f4f62b6, []int{34}
 }
 func (m *RawHistogram) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2222,7 +2493,7 @@ func (m *KeyValue) Reset()         { *m = KeyValue{} }
 func (m *KeyValue) String() string { return proto.CompactTextString(m) }
 func (*KeyValue) ProtoMessage()    {}
 func (*KeyValue) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{32}
+	return fileDescriptor_f22805646f4f62b6, []int{35}
 }
 func (m *KeyValue) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2275,7 +2546,7 @@ func (m *SpanMetrics) Reset()         { *m = SpanMetrics{} }
 func (m *SpanMetrics) String() string { return proto.CompactTextString(m) }
 func (*SpanMetrics) ProtoMessage()    {}
 func (*SpanMetrics) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{33}
+	return fileDescriptor_f22805646f4f62b6, []int{36}
 }
 func (m *SpanMetrics) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2333,13 +2604,15 @@ type SpanMetricsSummary struct {
 	P95            uint64      `protobuf:"varint,5,opt,name=p95,proto3" json:"p95,omitempty"`
 	P90            uint64      `protobuf:"varint,6,opt,name=p90,proto3" json:"p90,omitempty"`
 	P50            uint64      `protobuf:"varint,7,opt,name=p50,proto3" json:"p50,omitempty"`
+	Rate           float64     `protobuf:"fixed64,8,opt,name=rate,proto3" json:"rate,omitempty"`
+	ErrorRate      float64     `protobuf:"fixed64,9,opt,name=errorRate,proto3" json:"errorRate,omitempty"`
 }
 
 func (m *SpanMetricsSummary) Reset()         { *m = SpanMetricsSummary{} }
 func (m *SpanMetricsSummary) String() string { return proto.CompactTextString(m) }
 func (*SpanMetricsSummary) ProtoMessage()    {}
 func (*SpanMetricsSummary) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{34}
+	return fileDescriptor_f22805646f4f62b6, []int{37}
 }
 func (m *SpanMetricsSummary) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2417,6 +2690,20 @@ func (m *SpanMetricsSummary) GetP50() uint64 {
 	return 0
 }
 
+func (m *SpanMetricsSummary) GetRate() float64 {
+	if m != nil {
+		return m.Rate
+	}
+	return 0
+}
+
+func (m *SpanMetricsSummary) GetErrorRate() float64 {
+	if m != nil {
+		return m.ErrorRate
+	}
+	return 0
+}
+
 type SpanMetricsSummaryResponse struct {
 	Summaries []*SpanMetricsSummary `protobuf:"bytes,1,rep,name=summaries,proto3" json:"summaries,omitempty"`
 }
@@ -2425,7 +2712,7 @@ func (m *SpanMetricsSummaryResponse) Reset()         { *m = SpanMetricsSummaryRe
 func (m *SpanMetricsSummaryResponse) String() string { return proto.CompactTextString(m) }
 func (*SpanMetricsSummaryResponse) ProtoMessage()    {}
 func (*SpanMetricsSummaryResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{35}
+	return fileDescriptor_f22805646f4f62b6, []int{38}
 }
 func (m *SpanMetricsSummaryResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2476,7 +2763,7 @@ func (m *TraceQLStatic) Reset()         { *m = TraceQLStatic{} }
 func (m *TraceQLStatic) String() string { return proto.CompactTextString(m) }
 func (*TraceQLStatic) ProtoMessage()    {}
 func (*TraceQLStatic) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{36}
+	return fileDescriptor_f22805646f4f62b6, []int{39}
 }
 func (m *TraceQLStatic) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2570,7 +2857,7 @@ func (m *SpanMetricsData) Reset()         { *m = SpanMetricsData{} }
 func (m *SpanMetricsData) String() string { return proto.CompactTextString(m) }
 func (*SpanMetricsData) ProtoMessage()    {}
 func (*SpanMetricsData) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{37}
+	return fileDescriptor_f22805646f4f62b6, []int{40}
 }
 func (m *SpanMetricsData) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2623,7 +2910,7 @@ func (m *SpanMetricsResult) Reset()         { *m = SpanMetricsResult{} }
 func (m *SpanMetricsResult) String() string { return proto.CompactTextString(m) }
 func (*SpanMetricsResult) ProtoMessage()    {}
 func (*SpanMetricsResult) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{38}
+	return fileDescriptor_f22805646f4f62b6, []int{41}
 }
 func (m *SpanMetricsResult) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2684,7 +2971,7 @@ func (m *SpanMetricsResultPoint) Reset()         { *m = SpanMetricsResultPoint{}
 func (m *SpanMetricsResultPoint) String() string { return proto.CompactTextString(m) }
 func (*SpanMetricsResultPoint) ProtoMessage()    {}
 func (*SpanMetricsResultPoint) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{39}
+	return fileDescriptor_f22805646f4f62b6, []int{42}
 }
 func (m *SpanMetricsResultPoint) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2764,7 +3051,7 @@ func (m *QueryRangeRequest) Reset()         { *m = QueryRangeRequest{} }
 func (m *QueryRangeRequest) String() string { return proto.CompactTextString(m) }
 func (*QueryRangeRequest) ProtoMessage()    {}
 func (*QueryRangeRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{40}
+	return fileDescriptor_f22805646f4f62b6, []int{43}
 }
 func (m *QueryRangeRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2907,7 +3194,7 @@ func (m *QueryRangeResponse) Reset()         { *m = QueryRangeResponse{} }
 func (m *QueryRangeResponse) String() string { return proto.CompactTextString(m) }
 func (*QueryRangeResponse) ProtoMessage()    {}
 func (*QueryRangeResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{41}
+	return fileDescriptor_f22805646f4f62b6, []int{44}
 }
 func (m *QueryRangeResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2960,7 +3247,7 @@ func (m *Sample) Reset()         { *m = Sample{} }
 func (m *Sample) String() string { return proto.CompactTextString(m) }
 func (*Sample) ProtoMessage()    {}
 func (*Sample) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{42}
+	return fileDescriptor_f22805646f4f62b6, []int{45}
 }
 func (m *Sample) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -3017,7 +3304,7 @@ func (m *TimeSeries) Reset()         { *m = TimeSeries{} }
 func (m *TimeSeries) String() string { return proto.CompactTextString(m) }
 func (*TimeSeries) ProtoMessage()    {}
 func (*TimeSeries) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{43}
+	return fileDescriptor_f22805646f4f62b6, []int{46}
 }
 func (m *TimeSeries) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -3097,8 +3384,11 @@ func init() {
 	proto.RegisterType((*SearchTagValuesV2Response)(nil), "tempopb.SearchTagValuesV2Response")
 	proto.RegisterType((*Trace)(nil), "tempopb.Trace")
 	proto.RegisterType((*PushResponse)(nil), "tempopb.PushResponse")
+	proto.RegisterType((*PushLimitDetails)(nil), "tempopb.PushLimitDetails")
 	proto.RegisterType((*PushBytesRequest)(nil), "tempopb.PushBytesRequest")
 	proto.RegisterType((*PushSpansRequest)(nil), "tempopb.PushSpansRequest")
+	proto.RegisterType((*PushTracesRequest)(nil), "tempopb.PushTracesRequest")
+	proto.RegisterType((*PushTracesResponse)(nil), "tempopb.PushTracesResponse")
 	proto.RegisterType((*TraceBytes)(nil), "tempopb.TraceBytes")
 	proto.RegisterType((*LinkSlice)(nil), "tempopb.LinkSlice")
 	proto.RegisterType((*SpanMetricsRequest)(nil), "tempopb.SpanMetricsRequest")
@@ -3122,173 +3412,189 @@ func init() {
 func init() { proto.RegisterFile("pkg/tempopb/tempo.proto", fileDescriptor_f22805646f4f62b6) }
 
 var fileDescriptor_f22805646f4f62b6 = []byte{
-	// 2646 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xec, 0x5a, 0xcd, 0x6e, 0x23, 0xc7,
-	0xf1, 0xd7, 0x88, 0x1f, 0x22, 0x8b, 0x94, 0x44, 0xf5, 0xae, 0x65, 0x2e, 0xd7, 0xd6, 0xea, 0x3f,
-	0x5e, 0xf8, 0xaf, 0xf8, 0x43, 0xd2, 0xd2, 0xbb, 0xb0, 0xd7, 0x4e, 0x1c, 0xac, 0x56, 0xca, 0x5a,
-	0xb6, 0xbe, 0xdc, 0x94, 0x65, 0x23, 0x30, 0x20, 0x8c, 0xc8, 0x5e, 0xee, 0x40, 0xe4, 0x0c, 0x3d,
-	0x33, 0x54, 0x96, 0x39, 0x06, 0x48, 0x80, 0x00, 0x39, 0xe4, 0x90, 0x1c, 0x7c, 0xcc, 0x29, 0xc8,
-	0x39, 0x8f, 0x10, 0x20, 0x30, 0x10, 0xc4, 0x30, 0x90, 0x8b, 0x91, 0x83, 0x11, 0xd8, 0x87, 0x3c,
-	0x40, 0x5e, 0x20, 0xa8, 0xea, 0xee, 0x99, 0x1e, 0x72, 0xa4, 0xf5, 0x26, 0x6b, 0xc4, 0x07, 0x9f,
-	0xd4, 0x55, 0x5d, 0x5d, 0x5d, 0xdd, 0x55, 0xfd, 0xab, 0xaa, 0xa1, 0xe0, 0xe9, 0xc1, 0x69, 0x77,
-	0x2d, 0x12, 0xfd, 0x81, 0x3f, 0x38, 0x91, 0x7f, 0x57, 0x07, 0x81, 0x1f, 0xf9, 0x6c, 0x46, 0x31,
-	0x1b, 0x8b, 0x6d, 0xbf, 0xdf, 0xf7, 0xbd, 0xb5, 0xb3, 0x1b, 0x6b, 0x72, 0x24, 0x05, 0x1a, 0x2f,
-	0x77, 0xdd, 0xe8, 0xc1, 0xf0, 0x64, 0xb5, 0xed, 0xf7, 0xd7, 0xba, 0x7e, 0xd7, 0x5f, 0x23, 0xf6,
-	0xc9, 0xf0, 0x3e, 0x51, 0x44, 0xd0, 0x48, 0x89, 0x5f, 0x8e, 0x02, 0xa7, 0x2d, 0x50, 0x0b, 0x0d,
-	0x24, 0xd7, 0xfe, 0x85, 0x05, 0xb5, 0x43, 0xa4, 0x37, 0x46, 0xdb, 0x9b, 0x5c, 0x7c, 0x34, 0x14,
-	0x61, 0xc4, 0xea, 0x30, 0x43, 0x32, 0xdb, 0x9b, 0x75, 0x6b, 0xd9, 0x5a, 0xa9, 0x72, 0x4d, 0xb2,
-	0x25, 0x80, 0x93, 0x9e, 0xdf, 0x3e, 0x6d, 0x45, 0x4e, 0x10, 0xd5, 0xa7, 0x97, 0xad, 0x95, 0x32,
-	0x37, 0x38, 0xac, 0x01, 0x25, 0xa2, 0xb6, 0xbc, 0x4e, 0x3d, 0x47, 0xb3, 0x31, 0xcd, 0x9e, 0x81,
-	0xf2, 0x47, 0x43, 0x11, 0x8c, 0x76, 0xfd, 0x8e, 0xa8, 0x17, 0x68, 0x32, 0x61, 0xd8, 0x1e, 0x2c,
-	0x18, 0x76, 0x84, 0x03, 0xdf, 0x0b, 0x05, 0xbb, 0x0e, 0x05, 0xda, 0x99, 0xcc, 0xa8, 0x34, 0xe7,
-	0x56, 0xd5, 0x9d, 0xac, 0x92, 0x28, 0x97, 0x93, 0xec, 0x15, 0x98, 0xe9, 0x8b, 0x28, 0x70, 0xdb,
-	0x21, 0x59, 0x54, 0x69, 0x5e, 0x49, 0xcb, 0xa1, 0xca, 0x5d, 0x29, 0xc0, 0xb5, 0xa4, 0xcd, 0x8c,
-	0x73, 0xab, 0x49, 0xfb, 0xd3, 0x69, 0x98, 0x6d, 0x09, 0x27, 0x68, 0x3f, 0xd0, 0x37, 0xf1, 0x3a,
-	0xe4, 0x0f, 0x9d, 0x6e, 0x58, 0xb7, 0x96, 0x73, 0x2b, 0x95, 0xe6, 0x72, 0xac, 0x37, 0x25, 0xb5,
-	0x8a, 0x22, 0x5b, 0x5e, 0x14, 0x8c, 0x36, 0xf2, 0x9f, 0x7c, 0x71, 0x6d, 0x8a, 0xd3, 0x1a, 0x76,
-	0x1d, 0x66, 0x77, 0x5d, 0x6f, 0x73, 0x18, 0x38, 0x91, 0xeb, 0x7b, 0xbb, 0xd2, 0xb8, 0x59, 0x9e,
-	0x66, 0x92, 0x94, 0xf3, 0xd0, 0x90, 0xca, 0x29, 0x29, 0x93, 0xc9, 0x2e, 0x43, 0x61, 0xc7, 0xed,
-	0xbb, 0x51, 0x3d, 0x4f, 0xb3, 0x92, 0x40, 0x6e, 0x48, 0x8e, 0x28, 0x48, 0x2e, 0x11, 0xac, 0x06,
-	0x39, 0xe1, 0x75, 0xea, 0x45, 0xe2, 0xe1, 0x10, 0xe5, 0xde, 0xc5, 0x8b, 0xae, 0x97, 0xe8, 0xd6,
-	0x25, 0xc1, 0x56, 0x60, 0xbe, 0x35, 0x70, 0xbc, 0xf0, 0x40, 0x04, 0xf8, 0xb7, 0x25, 0xa2, 0x7a,
-	0x99, 0xd6, 0x8c, 0xb3, 0x1b, 0xaf, 0x42, 0x39, 0x3e, 0x22, 0xaa, 0x3f, 0x15, 0x23, 0xf2, 0x48,
-	0x99, 0xe3, 0x10, 0xd5, 0x9f, 0x39, 0xbd, 0xa1, 0x50, 0xf1, 0x20, 0x89, 0xd7, 0xa7, 0x5f, 0xb3,
-	0xec, 0x3f, 0xe7, 0x80, 0xc9, 0xab, 0xda, 0xc0, 0x28, 0xd0, 0xb7, 0x7a, 0x13, 0xca, 0xa1, 0xbe,
-	0x40, 0xe5, 0xda, 0xc5, 0xec, 0xab, 0xe5, 0x89, 0x20, 0x46, 0x25, 0xc5, 0xd2, 0xf6, 0xa6, 0xda,
-	0x48, 0x93, 0x18, 0x59, 0x74, 0xf4, 0x03, 0xa7, 0x2b, 0xd4, 0xfd, 0x25, 0x0c, 0xbc, 0xe1, 0x81,
-	0xd3, 0x15, 0xe1, 0xa1, 0x2f, 0x55, 0xab, 0x3b, 0x4c, 0x33, 0x31, 0x72, 0x85, 0xd7, 0xf6, 0x3b,
-	0xae, 0xd7, 0x55, 0xc1, 0x19, 0xd3, 0xa8, 0xc1, 0xf5, 0x3a, 0xe2, 0x21, 0xaa, 0x6b, 0xb9, 0x3f,
-	0x15, 0xea, 0x6e, 0xd3, 0x4c, 0x66, 0x43, 0x35, 0xf2, 0x23, 0xa7, 0xc7, 0x45, 0xdb, 0x0f, 0x3a,
-	0x61, 0x7d, 0x86, 0x84, 0x52, 0x3c, 0x94, 0xe9, 0x38, 0x91, 0xb3, 0xa5, 0x77, 0x92, 0x0e, 0x49,
-	0xf1, 0xf0, 0x9c, 0x67, 0x22, 0x08, 0x5d, 0xdf, 0x23, 0x7f, 0x94, 0xb9, 0x26, 0x19, 0x83, 0x7c,
-	0x88, 0xdb, 0xc3, 0xb2, 0xb5, 0x92, 0xe7, 0x34, 0xc6, 0x17, 0x79, 0xdf, 0xf7, 0x23, 0x11, 0x90,
-	0x61, 0x15, 0xda, 0xd3, 0xe0, 0xb0, 0x4d, 0xa8, 0x75, 0x44, 0xc7, 0x6d, 0x3b, 0x91, 0xe8, 0xdc,
-	0xf5, 0x7b, 0xc3, 0xbe, 0x17, 0xd6, 0xab, 0x14, 0xcd, 0xf5, 0xf8, 0xca, 0x37, 0xd3, 0x02, 0x7c,
-	0x62, 0x85, 0xfd, 0x27, 0x0b, 0xe6, 0xc7, 0xa4, 0xd8, 0x4d, 0x28, 0x84, 0x6d, 0x7f, 0x20, 0x6f,
-	0x7c, 0xae, 0xb9, 0x74, 0x9e, 0xba, 0xd5, 0x16, 0x4a, 0x71, 0x29, 0x8c, 0x67, 0xf0, 0x9c, 0xbe,
-	0x8e, 0x15, 0x1a, 0xb3, 0x1b, 0x90, 0x8f, 0x46, 0x03, 0xf9, 0xca, 0xe7, 0x9a, 0xcf, 0x9e, 0xab,
-	0xe8, 0x70, 0x34, 0x10, 0x9c, 0x44, 0xed, 0x6b, 0x50, 0x20, 0xb5, 0xac, 0x04, 0xf9, 0xd6, 0xc1,
-	0x9d, 0xbd, 0xda, 0x14, 0xab, 0x42, 0x89, 0x6f, 0xb5, 0xf6, 0xdf, 0xe3, 0x77, 0xb7, 0x6a, 0x96,
-	0xcd, 0x20, 0x8f, 0xe2, 0x0c, 0xa0, 0xd8, 0x3a, 0xe4, 0xdb, 0x7b, 0xf7, 0x6a, 0x53, 0xf6, 0x43,
-	0x98, 0xd3, 0xd1, 0xa5, 0x00, 0xe6, 0x26, 0x14, 0x09, 0x43, 0xf4, 0x0b, 0x7f, 0x26, 0x8d, 0x1c,
-	0x52, 0x7a, 0x57, 0x44, 0x0e, 0x7a, 0x88, 0x2b, 0x59, 0xb6, 0x3e, 0x0e, 0x38, 0xe3, 0xd1, 0x3b,
-	0x81, 0x36, 0x7f, 0xcb, 0xc1, 0xa5, 0x0c, 0x8d, 0xe3, 0x48, 0x5b, 0x4e, 0x90, 0x76, 0x05, 0xe6,
-	0x03, 0xdf, 0x8f, 0x5a, 0x22, 0x38, 0x73, 0xdb, 0x62, 0x2f, 0xb9, 0xb2, 0x71, 0x36, 0x46, 0x27,
-	0xb2, 0x48, 0x3d, 0xc9, 0x49, 0xe0, 0x4d, 0x33, 0xd9, 0x4b, 0xb0, 0x40, 0x4f, 0xe2, 0xd0, 0xed,
-	0x8b, 0xf7, 0x3c, 0xf7, 0xe1, 0x9e, 0xe3, 0xf9, 0xf4, 0x12, 0xf2, 0x7c, 0x72, 0x02, 0xa3, 0xaa,
-	0x93, 0x40, 0x92, 0x84, 0x17, 0x83, 0xc3, 0x5e, 0x80, 0x99, 0x50, 0x61, 0x46, 0x91, 0x6e, 0xa0,
-	0x96, 0xdc, 0x80, 0xe4, 0x73, 0x2d, 0xc0, 0x5e, 0x82, 0x92, 0x1a, 0xe2, 0x9b, 0xc8, 0x65, 0x0a,
-	0xc7, 0x12, 0x8c, 0x43, 0x35, 0x94, 0x87, 0x6b, 0x45, 0x4e, 0x14, 0xd6, 0x4b, 0xb4, 0x62, 0xf5,
-	0x22, 0xbf, 0xac, 0xb6, 0x8c, 0x05, 0x04, 0x52, 0x3c, 0xa5, 0xa3, 0x71, 0x04, 0x0b, 0x13, 0x22,
-	0x19, 0x38, 0xf6, 0xa2, 0x89, 0x63, 0x95, 0xe6, 0x53, 0x86, 0x53, 0x93, 0xc5, 0x26, 0xbc, 0xed,
-	0x40, 0xd5, 0x9c, 0x22, 0x1c, 0x1a, 0x38, 0xde, 0x5d, 0x7f, 0xe8, 0x45, 0xa4, 0x18, 0x71, 0x48,
-	0x33, 0xf0, 0x4e, 0x45, 0x10, 0xf8, 0x81, 0x9c, 0x96, 0xc9, 0xc0, 0xe0, 0xd8, 0x3f, 0xb7, 0x60,
-	0x46, 0xdd, 0x07, 0x7b, 0x0e, 0x0a, 0xb8, 0x50, 0x87, 0xe5, 0x6c, 0xea, 0xc2, 0xb8, 0x9c, 0xc3,
-	0xe0, 0xe9, 0x3b, 0x51, 0xfb, 0x81, 0xe8, 0x28, 0x6d, 0x9a, 0x64, 0x6f, 0x00, 0x38, 0x51, 0x14,
-	0xb8, 0x27, 0xc3, 0x48, 0x60, 0x46, 0x41, 0x1d, 0x57, 0x63, 0x1d, 0xaa, 0x8a, 0x38, 0xbb, 0xb1,
-	0xfa, 0x8e, 0x18, 0x1d, 0xe1, 0x69, 0xb8, 0x21, 0x8e, 0x6f, 0x3d, 0x8f, 0xdb, 0xb0, 0x45, 0x28,
-	0xe2, 0x46, 0x71, 0x6c, 0x2a, 0x2a, 0xf3, 0x09, 0x67, 0x86, 0x57, 0xee, 0xbc, 0xf0, 0xba, 0x0e,
-	0xb3, 0x3a, 0x98, 0x90, 0x0e, 0x55, 0x20, 0xa6, 0x99, 0x63, 0xa7, 0x28, 0x3c, 0xde, 0x29, 0x3e,
-	0x8e, 0x73, 0xb9, 0x7a, 0x8c, 0xf8, 0xa2, 0x5c, 0x2f, 0x1c, 0x88, 0x76, 0x24, 0x3a, 0x87, 0xfa,
-	0xd1, 0x53, 0xbe, 0x1b, 0x63, 0xb3, 0xe7, 0x61, 0x2e, 0x66, 0x6d, 0x8c, 0x70, 0xf3, 0x69, 0xb2,
-	0x6f, 0x8c, 0xcb, 0x96, 0xa1, 0x42, 0xe8, 0x4e, 0xc9, 0x4d, 0x67, 0x6e, 0x93, 0x85, 0x07, 0x6d,
-	0xfb, 0xfd, 0x41, 0x4f, 0x44, 0xa2, 0xf3, 0xb6, 0x7f, 0x12, 0xea, 0xdc, 0x93, 0x62, 0x62, 0xdc,
-	0xd0, 0x22, 0x92, 0x90, 0x8f, 0x2d, 0x61, 0xa0, 0xdd, 0x89, 0x4a, 0x69, 0x4e, 0x91, 0xcc, 0x19,
-	0x67, 0xa7, 0xec, 0xa6, 0x1c, 0x4e, 0x39, 0xc8, 0xb4, 0x9b, 0xb8, 0xf6, 0xbb, 0xf8, 0x1e, 0xf0,
-	0x6a, 0x30, 0xab, 0xeb, 0xa4, 0x7c, 0x59, 0xc3, 0xb9, 0x74, 0xb6, 0x82, 0xeb, 0xb8, 0xc4, 0xc8,
-	0x65, 0x94, 0x18, 0xf9, 0xb8, 0xc4, 0xb0, 0x3f, 0xcd, 0xc1, 0x62, 0xa2, 0x33, 0x95, 0xed, 0x5f,
-	0x9b, 0xcc, 0xf6, 0x8d, 0x31, 0xbc, 0x34, 0xec, 0xf8, 0x2e, 0xe3, 0x7f, 0x3b, 0x32, 0xfe, 0xe7,
-	0x39, 0xb8, 0x1a, 0x3b, 0x87, 0x9e, 0x57, 0xda, 0xab, 0x3f, 0x98, 0xf4, 0xea, 0xb5, 0x49, 0xaf,
-	0xca, 0x85, 0xdf, 0xb9, 0xf6, 0x5b, 0xe5, 0xda, 0x75, 0x5d, 0x94, 0xcb, 0x67, 0xa7, 0x4a, 0xa1,
-	0x06, 0x94, 0x22, 0xa7, 0x8b, 0xb5, 0x82, 0xcc, 0x3a, 0x65, 0x1e, 0xd3, 0xf6, 0xdb, 0x70, 0x39,
-	0x59, 0x71, 0xd4, 0x8c, 0xd7, 0x34, 0xa1, 0x48, 0x30, 0xa1, 0xf3, 0x54, 0xd6, 0xbb, 0x3e, 0x6a,
-	0xca, 0xfa, 0x4f, 0x49, 0xda, 0x6f, 0x98, 0xe0, 0xa3, 0x26, 0xe3, 0x94, 0x62, 0x19, 0x29, 0x85,
-	0x41, 0x3e, 0xc2, 0xde, 0x6b, 0x9a, 0x8c, 0xa1, 0xb1, 0x3d, 0x30, 0x50, 0x26, 0x15, 0x5b, 0x54,
-	0x49, 0x49, 0x73, 0xe3, 0x4a, 0x4a, 0x92, 0x08, 0x61, 0xd4, 0x66, 0xea, 0xf6, 0x84, 0x88, 0x04,
-	0xd8, 0xf2, 0x19, 0xc0, 0x56, 0x48, 0x80, 0xed, 0x55, 0x78, 0x7a, 0x62, 0x47, 0x75, 0x7a, 0x84,
-	0x6d, 0xcd, 0x54, 0x57, 0x96, 0x30, 0xec, 0x9b, 0x50, 0xd2, 0x4b, 0xe8, 0x28, 0xa3, 0x18, 0x5a,
-	0x69, 0x9c, 0xdd, 0x35, 0xd9, 0x3b, 0x70, 0x65, 0x6c, 0x3b, 0xe3, 0xba, 0xd7, 0xc6, 0x37, 0xac,
-	0x34, 0x17, 0x92, 0xc2, 0x48, 0xcd, 0x98, 0x36, 0x6c, 0x40, 0x81, 0x52, 0x1a, 0xbb, 0x0d, 0x33,
-	0x27, 0x54, 0x1b, 0xe8, 0x75, 0xc9, 0x5b, 0x95, 0x5f, 0x03, 0xce, 0x6e, 0xac, 0x72, 0x11, 0xfa,
-	0xc3, 0xa0, 0x2d, 0x28, 0x47, 0x70, 0x2d, 0x6f, 0xef, 0x41, 0xf5, 0x60, 0x18, 0x26, 0x25, 0xf3,
-	0x9b, 0x30, 0x4b, 0x45, 0x4b, 0xb8, 0x31, 0x3a, 0x54, 0xbd, 0x79, 0x6e, 0x65, 0xce, 0x08, 0x40,
-	0x94, 0xde, 0x42, 0x09, 0x2e, 0x9c, 0xd0, 0xf7, 0x78, 0x5a, 0xdc, 0xfe, 0x9d, 0x05, 0x35, 0x14,
-	0xa1, 0x94, 0xa5, 0xbd, 0xf7, 0x72, 0x5c, 0x87, 0xa3, 0xb7, 0xab, 0x1b, 0x4f, 0x61, 0x1f, 0xfd,
-	0xf7, 0x2f, 0xae, 0xcd, 0x1e, 0x04, 0xc2, 0xe9, 0xf5, 0xfc, 0xb6, 0x94, 0xd6, 0x05, 0xf8, 0xff,
-	0x43, 0xce, 0xed, 0xc8, 0xc2, 0xe6, 0x5c, 0x59, 0x94, 0x60, 0xb7, 0x00, 0x24, 0xe6, 0x6c, 0x3a,
-	0x91, 0x53, 0xcf, 0x5f, 0x24, 0x6f, 0x08, 0xda, 0xbb, 0xd2, 0x44, 0x79, 0x13, 0xca, 0xc4, 0xff,
-	0xe2, 0x0a, 0xaf, 0x03, 0xa8, 0x6f, 0x0d, 0x98, 0xa5, 0x17, 0x53, 0x3d, 0x47, 0x55, 0x1f, 0xca,
-	0x7e, 0x13, 0xca, 0x3b, 0xae, 0x77, 0xda, 0xea, 0xb9, 0x6d, 0x6c, 0x89, 0x0a, 0x3d, 0xd7, 0x3b,
-	0xd5, 0x7b, 0x5d, 0x9d, 0xdc, 0x0b, 0xf7, 0x58, 0xc5, 0x05, 0x5c, 0x4a, 0xda, 0x3f, 0xb3, 0x80,
-	0x21, 0x53, 0x37, 0x1f, 0x49, 0x5e, 0x97, 0xe1, 0x6f, 0x99, 0xe1, 0x5f, 0x87, 0x99, 0x6e, 0xe0,
-	0x0f, 0x07, 0x1b, 0xfa, 0x59, 0x68, 0x12, 0xe5, 0x7b, 0xf4, 0xa9, 0x41, 0x56, 0x6f, 0x92, 0xf8,
-	0xda, 0xcf, 0xe5, 0x97, 0x16, 0x5c, 0x31, 0x8c, 0x68, 0x0d, 0xfb, 0x7d, 0x27, 0x18, 0xfd, 0x6f,
-	0x6c, 0xf9, 0x83, 0x05, 0x97, 0x52, 0x17, 0x92, 0xbc, 0x5b, 0x11, 0x46, 0x6e, 0x1f, 0x31, 0x91,
-	0x2c, 0x29, 0xf1, 0x84, 0x91, 0x2e, 0xe2, 0x65, 0xdd, 0x67, 0x14, 0xf1, 0xcf, 0xc3, 0x1c, 0x85,
-	0x73, 0x2b, 0x16, 0x91, 0xa6, 0x8d, 0x71, 0xd9, 0x6a, 0xd2, 0x22, 0xe6, 0xc9, 0x83, 0x97, 0x53,
-	0x25, 0xfc, 0x44, 0x83, 0xf8, 0x7d, 0xa8, 0x72, 0xe7, 0x27, 0x6f, 0xb9, 0x61, 0xe4, 0x77, 0x03,
-	0xa7, 0x8f, 0x41, 0x72, 0x32, 0x6c, 0x9f, 0x0a, 0xd9, 0x47, 0xe4, 0xb9, 0xa2, 0xf0, 0xec, 0x6d,
-	0xc3, 0x32, 0x49, 0xd8, 0x6f, 0x43, 0x49, 0x17, 0xc1, 0x19, 0x7d, 0xcd, 0x4b, 0xe9, 0xbe, 0x66,
-	0x31, 0xdd, 0x4b, 0xbd, 0xbb, 0x83, 0xcd, 0x8b, 0xdb, 0xd6, 0x08, 0xf4, 0x1b, 0x0b, 0x2a, 0x86,
-	0x89, 0x6c, 0x03, 0x16, 0x7a, 0x4e, 0x24, 0xbc, 0xf6, 0xe8, 0xf8, 0x81, 0x36, 0x4f, 0x45, 0x65,
-	0xd2, 0x21, 0x99, 0xb6, 0xf3, 0x9a, 0x92, 0x4f, 0x4e, 0xf3, 0x3d, 0x28, 0x86, 0x22, 0x70, 0xd5,
-	0xf3, 0x36, 0x51, 0x2b, 0xae, 0xdd, 0x95, 0x00, 0x1e, 0x5c, 0xe2, 0x85, 0xba, 0x58, 0x45, 0xd9,
-	0x7f, 0x4d, 0x47, 0xb7, 0x0a, 0xac, 0xc9, 0x96, 0xeb, 0x11, 0xde, 0x9a, 0xce, 0xf4, 0x56, 0x62,
-	0x5f, 0xee, 0x51, 0xf6, 0xd5, 0x20, 0x37, 0xb8, 0x7d, 0x5b, 0x35, 0x2c, 0x38, 0x94, 0x9c, 0x5b,
-	0x14, 0x78, 0xc4, 0xb9, 0x25, 0x39, 0xeb, 0xaa, 0x4a, 0xc7, 0x21, 0x71, 0x6e, 0xad, 0xab, 0x72,
-	0x1c, 0x87, 0xf6, 0xfb, 0xd0, 0xc8, 0x7a, 0x27, 0x2a, 0x44, 0x6f, 0x43, 0x39, 0x24, 0x96, 0x2b,
-	0x26, 0x21, 0x20, 0x63, 0x5d, 0x22, 0x6d, 0xff, 0xd6, 0x82, 0xd9, 0x94, 0x63, 0x53, 0xd9, 0xa7,
-	0xa0, 0xb2, 0x4f, 0x15, 0x2c, 0x8f, 0x2e, 0x23, 0xc7, 0x2d, 0x0f, 0xa9, 0xfb, 0x74, 0xdf, 0x16,
-	0xb7, 0xee, 0x23, 0x25, 0x1b, 0x95, 0x32, 0xb7, 0x42, 0xa4, 0x4e, 0xe8, 0x70, 0x25, 0x6e, 0x9d,
-	0x20, 0xd5, 0x51, 0x07, 0xb3, 0x3a, 0xd4, 0x21, 0x46, 0x4e, 0x34, 0x94, 0xf5, 0x51, 0x81, 0x2b,
-	0x0a, 0x77, 0x3c, 0x75, 0xbd, 0x0e, 0x55, 0x44, 0x05, 0x4e, 0x63, 0x5b, 0xc8, 0xcf, 0x8d, 0xca,
-	0x70, 0x84, 0x59, 0x2c, 0x77, 0x02, 0x11, 0x0e, 0x7b, 0xd1, 0x61, 0x92, 0x1c, 0x0d, 0x0e, 0x96,
-	0x17, 0x92, 0x52, 0x61, 0xd3, 0xc8, 0x7c, 0x43, 0x24, 0xc1, 0x95, 0x24, 0xa2, 0xe0, 0xc2, 0xc4,
-	0x2c, 0x86, 0x49, 0xcf, 0x39, 0x11, 0x3d, 0xa3, 0x3e, 0x48, 0x18, 0x68, 0x07, 0x11, 0x47, 0x46,
-	0x3e, 0x36, 0x38, 0x6c, 0x0d, 0xa6, 0x23, 0x1d, 0x1a, 0xd7, 0xce, 0xb7, 0xe1, 0xc0, 0x77, 0xbd,
-	0x88, 0x4f, 0x47, 0x21, 0xbe, 0xa1, 0xc5, 0xec, 0x69, 0x72, 0x86, 0xab, 0x8c, 0x98, 0xe5, 0x34,
-	0xc6, 0xe8, 0x38, 0x73, 0x7a, 0xb4, 0xb1, 0xc5, 0x71, 0x88, 0x3d, 0x9f, 0x78, 0x28, 0xfa, 0x83,
-	0x9e, 0x13, 0x1c, 0xaa, 0xef, 0x43, 0x39, 0xfa, 0x12, 0x3f, 0xce, 0x66, 0x2f, 0x40, 0x4d, 0xb3,
-	0xf4, 0xf7, 0x62, 0x15, 0x9c, 0x13, 0x7c, 0xfb, 0x2f, 0x39, 0x58, 0xa0, 0x6f, 0xbf, 0xdc, 0xf1,
-	0xba, 0xe2, 0x62, 0x50, 0x8e, 0x41, 0x56, 0x01, 0x4d, 0x0a, 0x64, 0xe5, 0xd3, 0xa4, 0x6f, 0xcb,
-	0x58, 0xc6, 0x46, 0x62, 0xa0, 0xf6, 0xa4, 0x31, 0x02, 0x7a, 0xf8, 0xc0, 0x09, 0x3a, 0xdb, 0x9b,
-	0x0a, 0x8e, 0x35, 0x89, 0x37, 0x4d, 0x43, 0xf9, 0x18, 0x65, 0xe5, 0x6d, 0x70, 0xd2, 0xbf, 0x11,
-	0xcc, 0x8c, 0xfd, 0x46, 0x60, 0x36, 0x0d, 0xa5, 0x0b, 0x9a, 0x86, 0xf2, 0x23, 0x9b, 0x06, 0xc8,
-	0x6a, 0x1a, 0x8c, 0x52, 0xbd, 0x92, 0x2e, 0xd5, 0xcd, 0x76, 0xa2, 0x3a, 0xd6, 0x4e, 0xe8, 0x32,
-	0x7e, 0xf6, 0xdc, 0x32, 0x7e, 0xee, 0x6b, 0x95, 0xf1, 0xf3, 0x8f, 0x5d, 0xc6, 0x87, 0xc0, 0x4c,
-	0x67, 0x2a, 0xe4, 0x78, 0x31, 0x86, 0x32, 0x09, 0x1b, 0x97, 0x12, 0xb4, 0x77, 0xfb, 0xa2, 0x45,
-	0x53, 0x31, 0x98, 0x3d, 0xfe, 0x87, 0xcc, 0x3b, 0x50, 0x6c, 0x39, 0xfd, 0x41, 0x4f, 0xb0, 0xff,
-	0x83, 0x2a, 0x06, 0x6f, 0x18, 0x39, 0xfd, 0xc1, 0x71, 0x3f, 0x54, 0x60, 0x52, 0x89, 0x79, 0xf2,
-	0x57, 0x0b, 0x99, 0x78, 0x2c, 0x8a, 0x6c, 0x95, 0x60, 0x3e, 0xb6, 0x00, 0x12, 0x5b, 0xd8, 0x6d,
-	0x28, 0xd2, 0x53, 0x9b, 0xc4, 0xb9, 0xc9, 0x2f, 0x3c, 0xea, 0xf7, 0x15, 0xb5, 0x80, 0xad, 0xc1,
-	0x4c, 0x48, 0xc6, 0xe8, 0xbc, 0x32, 0x9f, 0x98, 0x4f, 0x7c, 0x25, 0xaf, 0xa5, 0xd8, 0x35, 0xa8,
-	0x0c, 0x02, 0xbf, 0x7f, 0xac, 0x36, 0x94, 0x1f, 0x4a, 0x01, 0x59, 0x3b, 0xc4, 0x79, 0xe1, 0x43,
-	0x98, 0x1f, 0x2b, 0x5f, 0x59, 0x15, 0x4a, 0x7b, 0xfb, 0xc7, 0x5b, 0x9c, 0xef, 0xf3, 0xda, 0x14,
-	0xbb, 0x04, 0xf3, 0xbb, 0x77, 0x3e, 0x38, 0xde, 0xd9, 0x3e, 0xda, 0x3a, 0x3e, 0xe4, 0x77, 0xee,
-	0x6e, 0xb5, 0x6a, 0x16, 0x32, 0x69, 0x7c, 0x7c, 0xb8, 0xbf, 0x7f, 0xbc, 0x73, 0x87, 0xdf, 0xdb,
-	0xaa, 0x4d, 0xb3, 0x05, 0x98, 0x7d, 0x6f, 0xef, 0x9d, 0xbd, 0xfd, 0xf7, 0xf7, 0xd4, 0xe2, 0x5c,
-	0xf3, 0x57, 0x16, 0x14, 0x51, 0xbd, 0x08, 0xd8, 0x0f, 0xa1, 0x1c, 0x17, 0xc1, 0xec, 0x4a, 0xaa,
-	0x76, 0x36, 0x0b, 0xe3, 0xc6, 0x53, 0xa9, 0x29, 0xed, 0x65, 0x7b, 0x8a, 0xdd, 0x81, 0x4a, 0x2c,
-	0x7c, 0xd4, 0xfc, 0x4f, 0x54, 0x34, 0xff, 0x69, 0x41, 0x4d, 0x39, 0xf8, 0x9e, 0xf0, 0x44, 0xe0,
-	0x44, 0x7e, 0x6c, 0x18, 0x55, 0xb0, 0x63, 0x5a, 0xcd, 0x72, 0xf8, 0x7c, 0xc3, 0xb6, 0x01, 0xee,
-	0x89, 0x48, 0x57, 0x0f, 0x57, 0xb3, 0xe1, 0x52, 0xea, 0x78, 0xe6, 0x1c, 0x2c, 0xd5, 0xaa, 0xee,
-	0x01, 0x24, 0x11, 0xce, 0x12, 0xf4, 0x9f, 0xc0, 0xb0, 0xc6, 0xd5, 0xcc, 0xb9, 0xf8, 0xa4, 0xbf,
-	0xcf, 0xc3, 0x0c, 0x4e, 0xb8, 0x22, 0x60, 0x6f, 0xc1, 0xec, 0x8f, 0x5c, 0xaf, 0x13, 0xff, 0xf8,
-	0xc7, 0x32, 0x7e, 0x2d, 0xd4, 0x6a, 0x1b, 0x59, 0x53, 0x86, 0x0b, 0xaa, 0xfa, 0xe7, 0x84, 0xb6,
-	0xf0, 0x22, 0x76, 0xce, 0x6f, 0x58, 0x8d, 0xa7, 0x27, 0xf8, 0xb1, 0x8a, 0x2d, 0xa8, 0x18, 0xbf,
-	0x8f, 0x99, 0xb7, 0x35, 0xf1, 0xab, 0xd9, 0x45, 0x6a, 0xee, 0x01, 0x24, 0x3d, 0x35, 0xbb, 0xe0,
-	0xeb, 0x5a, 0xe3, 0x6a, 0xe6, 0x5c, 0xac, 0xe8, 0x1d, 0x7d, 0x24, 0xd9, 0x9c, 0x5f, 0xa8, 0xea,
-	0xd9, 0xcc, 0x66, 0xdf, 0x50, 0x76, 0x04, 0xf3, 0x63, 0xbd, 0x2c, 0x7b, 0xd4, 0x27, 0xa2, 0xc6,
-	0xf2, 0xf9, 0x02, 0xb1, 0xde, 0x1f, 0x1b, 0x5f, 0x10, 0x74, 0x8f, 0xfc, 0x68, 0xcd, 0xf6, 0x79,
-	0x02, 0xa6, 0xcd, 0xcd, 0x7f, 0xe5, 0xa0, 0xd6, 0x8a, 0x02, 0xe1, 0xf4, 0x5d, 0xaf, 0xab, 0x43,
-	0xe6, 0x0d, 0x28, 0xaa, 0x1c, 0xf1, 0xb8, 0x2e, 0x5e, 0xb7, 0xf0, 0x3d, 0x3c, 0x11, 0xdf, 0xac,
-	0x5b, 0x6c, 0xf7, 0x09, 0x7a, 0x67, 0xdd, 0x62, 0x1f, 0x7c, 0x33, 0xfe, 0x59, 0xb7, 0xd8, 0x87,
-	0xdf, 0x9c, 0x87, 0xd6, 0x2d, 0x76, 0x00, 0x0b, 0x0a, 0x2b, 0x9e, 0x08, 0x3a, 0xac, 0x5b, 0xcd,
-	0x3f, 0x5a, 0x30, 0xa3, 0x11, 0xeb, 0x38, 0xb3, 0xcf, 0xb0, 0x2f, 0xaa, 0xbe, 0xd5, 0x36, 0xcf,
-	0x5d, 0x28, 0xf3, 0xc4, 0x51, 0x6d, 0xa3, 0xfe, 0xc9, 0x97, 0x4b, 0xd6, 0x67, 0x5f, 0x2e, 0x59,
-	0xff, 0xf8, 0x72, 0xc9, 0xfa, 0xf5, 0x57, 0x4b, 0x53, 0x9f, 0x7d, 0xb5, 0x34, 0xf5, 0xf9, 0x57,
-	0x4b, 0x53, 0x27, 0x45, 0xfa, 0xe7, 0x8e, 0x57, 0xfe, 0x1d, 0x00, 0x00, 0xff, 0xff, 0x35, 0x3c,
-	0x12, 0xda, 0x5d, 0x22, 0x00, 0x00,
+	// 2903 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xec, 0x5a, 0xcd, 0x6f, 0x1b, 0xc7,
+	0x15, 0xd7, 0xf2, 0x9b, 0x8f, 0xa4, 0x44, 0x8e, 0x1d, 0x87, 0xa1, 0x12, 0x59, 0xdd, 0x18, 0xad,
+	0x9a, 0x0f, 0x89, 0x66, 0x6c, 0x34, 0x4e, 0x9a, 0x14, 0x92, 0xa5, 0x3a, 0xb2, 0x25, 0x59, 0x1e,
+	0x2a, 0x4a, 0x50, 0x04, 0x11, 0x56, 0xe4, 0x88, 0x5e, 0x88, 0xdc, 0x65, 0x76, 0x87, 0xaa, 0x55,
+	0xf4, 0x54, 0xa0, 0x05, 0x0a, 0xb4, 0x40, 0x0f, 0xed, 0xa1, 0xc7, 0xfe, 0x0d, 0xed, 0x3d, 0x87,
+	0x02, 0x45, 0x80, 0x02, 0x41, 0x7a, 0x0b, 0x7a, 0x08, 0x8a, 0xe4, 0xd0, 0x63, 0xcf, 0x3d, 0xb5,
+	0x78, 0xf3, 0xb1, 0x5f, 0x5c, 0xc9, 0x76, 0xea, 0xa0, 0x39, 0xe4, 0xc4, 0x79, 0xbf, 0x79, 0xf3,
+	0xe6, 0xcd, 0xbc, 0x37, 0x6f, 0xde, 0x9b, 0x25, 0x3c, 0x3d, 0x3e, 0x1e, 0xac, 0x70, 0x36, 0x1a,
+	0xbb, 0xe3, 0x43, 0xf9, 0xbb, 0x3c, 0xf6, 0x5c, 0xee, 0x92, 0xa2, 0x02, 0x5b, 0x97, 0x7a, 0xee,
+	0x68, 0xe4, 0x3a, 0x2b, 0x27, 0x57, 0x57, 0x64, 0x4b, 0x32, 0xb4, 0x5e, 0x1e, 0xd8, 0xfc, 0xfe,
+	0xe4, 0x70, 0xb9, 0xe7, 0x8e, 0x56, 0x06, 0xee, 0xc0, 0x5d, 0x11, 0xf0, 0xe1, 0xe4, 0x48, 0x50,
+	0x82, 0x10, 0x2d, 0xc5, 0x7e, 0x91, 0x7b, 0x56, 0x8f, 0xa1, 0x14, 0xd1, 0x90, 0xa8, 0xf9, 0x0b,
+	0x03, 0xea, 0x7b, 0x48, 0xaf, 0x9d, 0x6e, 0xae, 0x53, 0xf6, 0xc1, 0x84, 0xf9, 0x9c, 0x34, 0xa1,
+	0x28, 0x78, 0x36, 0xd7, 0x9b, 0xc6, 0xa2, 0xb1, 0x54, 0xa5, 0x9a, 0x24, 0x0b, 0x00, 0x87, 0x43,
+	0xb7, 0x77, 0xdc, 0xe5, 0x96, 0xc7, 0x9b, 0x99, 0x45, 0x63, 0xa9, 0x4c, 0x23, 0x08, 0x69, 0x41,
+	0x49, 0x50, 0x1b, 0x4e, 0xbf, 0x99, 0x15, 0xbd, 0x01, 0x4d, 0x9e, 0x85, 0xf2, 0x07, 0x13, 0xe6,
+	0x9d, 0x6e, 0xbb, 0x7d, 0xd6, 0xcc, 0x8b, 0xce, 0x10, 0x30, 0x1d, 0x68, 0x44, 0xf4, 0xf0, 0xc7,
+	0xae, 0xe3, 0x33, 0x72, 0x05, 0xf2, 0x62, 0x66, 0xa1, 0x46, 0xa5, 0x33, 0xbb, 0xac, 0xf6, 0x64,
+	0x59, 0xb0, 0x52, 0xd9, 0x49, 0x5e, 0x81, 0xe2, 0x88, 0x71, 0xcf, 0xee, 0xf9, 0x42, 0xa3, 0x4a,
+	0xe7, 0x99, 0x38, 0x1f, 0x8a, 0xdc, 0x96, 0x0c, 0x54, 0x73, 0x9a, 0x3f, 0x85, 0x7a, 0xb2, 0x93,
+	0x5c, 0x81, 0x5a, 0xcf, 0x1d, 0x8d, 0x87, 0x8c, 0xb3, 0xfe, 0x6d, 0xf7, 0xd0, 0x17, 0xd3, 0xd6,
+	0x68, 0x1c, 0xc4, 0x75, 0x70, 0x97, 0x5b, 0x43, 0xc1, 0x91, 0x11, 0x1c, 0x21, 0x80, 0x32, 0xfc,
+	0x63, 0x7b, 0x3c, 0x66, 0xfd, 0x35, 0x5c, 0xb8, 0x2f, 0xb6, 0xa1, 0x46, 0xe3, 0xa0, 0xf9, 0x71,
+	0x06, 0x6a, 0x5d, 0x66, 0x79, 0xbd, 0xfb, 0x7a, 0xcf, 0x5f, 0x83, 0xdc, 0x9e, 0x35, 0xc0, 0x29,
+	0xb3, 0x4b, 0x95, 0xce, 0x62, 0xb0, 0x82, 0x18, 0xd7, 0x32, 0xb2, 0x6c, 0x38, 0xdc, 0x3b, 0x5d,
+	0xcb, 0x7d, 0xf4, 0xd9, 0xe5, 0x19, 0x2a, 0xc6, 0xe0, 0x9c, 0xdb, 0xb6, 0xb3, 0x3e, 0xf1, 0x2c,
+	0x6e, 0xbb, 0xce, 0xb6, 0xd6, 0x2a, 0x0e, 0x0a, 0x2e, 0xeb, 0x41, 0x84, 0x4b, 0x69, 0x16, 0x03,
+	0xc9, 0x45, 0xc8, 0x6f, 0xd9, 0x23, 0x9b, 0x37, 0x73, 0xa2, 0x57, 0x12, 0x88, 0xfa, 0xc2, 0xe4,
+	0x79, 0x89, 0x0a, 0x82, 0xd4, 0x21, 0xcb, 0x9c, 0x7e, 0xb3, 0x20, 0x30, 0x6c, 0x22, 0xdf, 0x3d,
+	0x34, 0x69, 0xb3, 0x24, 0xec, 0x2b, 0x09, 0xb2, 0x04, 0x73, 0xdd, 0xb1, 0xe5, 0xf8, 0xbb, 0xcc,
+	0xc3, 0xdf, 0x2e, 0xe3, 0xcd, 0xb2, 0x18, 0x93, 0x84, 0x5b, 0xdf, 0x83, 0x72, 0xb0, 0x44, 0x14,
+	0x7f, 0xcc, 0x4e, 0x85, 0x11, 0xca, 0x14, 0x9b, 0x28, 0xfe, 0xc4, 0x1a, 0x4e, 0x98, 0xf2, 0x3c,
+	0x49, 0xbc, 0x96, 0x79, 0xd5, 0x30, 0xff, 0x92, 0x05, 0x22, 0xb7, 0x4a, 0xec, 0xb0, 0xde, 0xd5,
+	0x6b, 0x50, 0xf6, 0xf5, 0x06, 0x2a, 0x27, 0xba, 0x94, 0xbe, 0xb5, 0x34, 0x64, 0x44, 0xff, 0x17,
+	0x5e, 0xbb, 0xb9, 0xae, 0x26, 0xd2, 0x24, 0xda, 0x5e, 0x2c, 0x7d, 0xd7, 0x1a, 0x30, 0xb5, 0x7f,
+	0x21, 0x80, 0x3b, 0x3c, 0xb6, 0x06, 0xcc, 0xdf, 0x73, 0xa5, 0x68, 0xb5, 0x87, 0x71, 0x10, 0xcf,
+	0x08, 0x73, 0x7a, 0x6e, 0xdf, 0x76, 0x06, 0xea, 0x18, 0x04, 0x34, 0x4a, 0xb0, 0x9d, 0x3e, 0x7b,
+	0x80, 0xe2, 0xba, 0xf6, 0x4f, 0x98, 0xda, 0xdb, 0x38, 0x48, 0x4c, 0xa8, 0x0a, 0x87, 0xa3, 0xac,
+	0xe7, 0x7a, 0x7d, 0xbf, 0x59, 0x14, 0x4c, 0x31, 0x0c, 0x79, 0xfa, 0x16, 0xb7, 0x36, 0xf4, 0x4c,
+	0xd2, 0x20, 0x31, 0x0c, 0xd7, 0x79, 0xc2, 0x3c, 0xdf, 0x76, 0x1d, 0x61, 0x8f, 0x32, 0xd5, 0x24,
+	0x21, 0x90, 0xf3, 0x71, 0x7a, 0x58, 0x34, 0x96, 0x72, 0x54, 0xb4, 0xf1, 0xec, 0x1f, 0xb9, 0x2e,
+	0x67, 0x9e, 0x50, 0xac, 0x22, 0xe6, 0x8c, 0x20, 0x64, 0x1d, 0xea, 0x7d, 0xd6, 0xb7, 0x7b, 0x16,
+	0x67, 0xfd, 0x9b, 0xee, 0x70, 0x32, 0x72, 0xfc, 0x66, 0x55, 0x78, 0x73, 0x33, 0xd8, 0xf2, 0xf5,
+	0x38, 0x03, 0x9d, 0x1a, 0x61, 0xfe, 0xd9, 0x80, 0xb9, 0x04, 0x17, 0xb9, 0x06, 0x79, 0xbf, 0xe7,
+	0x8e, 0xe5, 0x8e, 0xcf, 0x76, 0x16, 0xce, 0x12, 0xb7, 0xdc, 0x45, 0x2e, 0x2a, 0x99, 0x71, 0x0d,
+	0x8e, 0x35, 0xd2, 0xbe, 0x22, 0xda, 0xe4, 0x2a, 0xe4, 0xf8, 0xe9, 0x58, 0xc6, 0x93, 0xd9, 0xce,
+	0x73, 0x67, 0x0a, 0xda, 0x3b, 0x1d, 0x33, 0x2a, 0x58, 0xcd, 0xcb, 0x90, 0x17, 0x62, 0x49, 0x09,
+	0x72, 0xdd, 0xdd, 0xd5, 0x9d, 0xfa, 0x0c, 0xa9, 0x42, 0x89, 0x6e, 0x74, 0xef, 0xbe, 0x4d, 0x6f,
+	0x6e, 0xd4, 0x0d, 0x93, 0x40, 0x0e, 0xd9, 0x09, 0x40, 0xa1, 0xbb, 0x47, 0x37, 0x77, 0x6e, 0xd5,
+	0x67, 0xcc, 0x07, 0x30, 0xab, 0xbd, 0x4b, 0x85, 0xb2, 0x6b, 0x50, 0x10, 0xd1, 0x4a, 0x9f, 0xf0,
+	0x67, 0xe3, 0x31, 0x4a, 0x72, 0x6f, 0x33, 0x6e, 0xa1, 0x85, 0xa8, 0xe2, 0x25, 0xed, 0x64, 0x68,
+	0x4b, 0x7a, 0xef, 0x54, 0x5c, 0xfb, 0x57, 0x16, 0x2e, 0xa4, 0x48, 0x4c, 0xc6, 0xf4, 0x72, 0x18,
+	0xd3, 0x97, 0x60, 0xce, 0x73, 0x5d, 0xde, 0x65, 0xde, 0x89, 0xdd, 0x63, 0x3b, 0xe1, 0x96, 0x25,
+	0x61, 0xf4, 0x4e, 0x84, 0x84, 0x78, 0xc1, 0x27, 0x43, 0x7c, 0x1c, 0x24, 0x2f, 0x41, 0x43, 0x1c,
+	0x89, 0x3d, 0x7b, 0xc4, 0xde, 0x76, 0xec, 0x07, 0x3b, 0x96, 0xe3, 0x8a, 0x93, 0x90, 0xa3, 0xd3,
+	0x1d, 0xe8, 0x55, 0xfd, 0x30, 0x24, 0xc9, 0xf0, 0x12, 0x41, 0xc8, 0x0b, 0x50, 0xf4, 0x55, 0xcc,
+	0x28, 0x88, 0x1d, 0xa8, 0x87, 0x3b, 0x20, 0x71, 0xaa, 0x19, 0xc8, 0x4b, 0x50, 0x52, 0x4d, 0x3c,
+	0x13, 0xd9, 0x54, 0xe6, 0x80, 0x83, 0x50, 0xa8, 0xfa, 0x72, 0x71, 0x5d, 0x6e, 0x71, 0xbf, 0x59,
+	0x12, 0x23, 0x96, 0xcf, 0xb3, 0xcb, 0x72, 0x37, 0x32, 0x40, 0x04, 0x29, 0x1a, 0x93, 0x81, 0x67,
+	0xbb, 0x37, 0x9c, 0xf8, 0x9c, 0x79, 0x7e, 0xb3, 0xbc, 0x98, 0xc5, 0xb3, 0xad, 0xe9, 0xd6, 0x3e,
+	0x34, 0xa6, 0x86, 0xa7, 0xc4, 0xb8, 0x17, 0xa3, 0x31, 0xae, 0xd2, 0x79, 0x2a, 0x62, 0xf0, 0x70,
+	0x70, 0x34, 0xf4, 0x6d, 0x41, 0x35, 0xda, 0x25, 0x62, 0xd4, 0xd8, 0x72, 0x6e, 0xba, 0x13, 0x87,
+	0xab, 0x1b, 0x2c, 0x04, 0x70, 0xbf, 0x99, 0xe7, 0xb9, 0x9e, 0xec, 0x96, 0x17, 0x45, 0x04, 0x31,
+	0x7f, 0x6e, 0x40, 0x51, 0xed, 0x15, 0x79, 0x1e, 0xf2, 0x38, 0x50, 0xbb, 0x6c, 0x2d, 0xb6, 0x99,
+	0x54, 0xf6, 0xa1, 0x63, 0x8d, 0x2c, 0xde, 0xbb, 0xcf, 0xfa, 0x4a, 0x9a, 0x26, 0xc9, 0xeb, 0x00,
+	0x16, 0xe7, 0x9e, 0x7d, 0x38, 0xe1, 0x0c, 0x6f, 0x1b, 0x94, 0x31, 0x1f, 0xc8, 0x50, 0xb9, 0xcc,
+	0xc9, 0xd5, 0xe5, 0x3b, 0xec, 0x74, 0x1f, 0x57, 0x43, 0x23, 0xec, 0x18, 0x07, 0x72, 0x38, 0x0d,
+	0xb9, 0x04, 0x05, 0x9c, 0x28, 0xf0, 0x5b, 0x45, 0xa5, 0x1e, 0xef, 0x54, 0xd7, 0xcb, 0x9e, 0xe5,
+	0x7a, 0x57, 0xa0, 0xa6, 0x1d, 0x0d, 0x69, 0x5f, 0x39, 0x69, 0x1c, 0x4c, 0xac, 0x22, 0xff, 0x78,
+	0xab, 0xf8, 0x30, 0xb8, 0xe7, 0x75, 0x8e, 0xb1, 0x04, 0x73, 0xb6, 0xe3, 0x8f, 0x59, 0x8f, 0xb3,
+	0xfe, 0x9e, 0x0e, 0x08, 0xe2, 0x2e, 0x4c, 0xc0, 0xe4, 0xdb, 0x30, 0x1b, 0x40, 0x6b, 0xa7, 0x38,
+	0x79, 0x46, 0xe8, 0x97, 0x40, 0xc9, 0x22, 0x54, 0x44, 0xe4, 0x8f, 0xe5, 0x1b, 0x51, 0x68, 0x3a,
+	0xaf, 0xc9, 0x3d, 0x34, 0xaf, 0xc9, 0x27, 0xf3, 0x9a, 0x25, 0x98, 0x0b, 0x45, 0x4a, 0x75, 0x0a,
+	0x42, 0x9d, 0x24, 0x1c, 0xd3, 0x5b, 0xdc, 0xef, 0xcd, 0x62, 0x42, 0x6f, 0x81, 0x4e, 0x67, 0x4a,
+	0xa5, 0xb4, 0x4c, 0xe9, 0x1e, 0x34, 0xe4, 0x06, 0x62, 0x5e, 0xa0, 0xaf, 0xf5, 0x8b, 0xfa, 0x42,
+	0x90, 0x2e, 0x21, 0x89, 0x30, 0x49, 0xc9, 0xa6, 0x24, 0x29, 0xb9, 0x20, 0x49, 0x31, 0x3f, 0xce,
+	0xc2, 0xa5, 0x50, 0x66, 0x2c, 0x5f, 0x78, 0x75, 0x3a, 0x5f, 0x68, 0x25, 0x22, 0x6e, 0x44, 0x8f,
+	0x6f, 0x72, 0x86, 0xaf, 0x47, 0xce, 0xf0, 0x69, 0x16, 0xe6, 0x03, 0xe3, 0x88, 0x43, 0x18, 0xb7,
+	0xea, 0x1b, 0xd3, 0x56, 0xbd, 0x3c, 0x6d, 0x55, 0x39, 0xf0, 0x1b, 0xd3, 0x7e, 0xad, 0x4c, 0xdb,
+	0x06, 0x12, 0x3d, 0x76, 0x2a, 0x99, 0x6a, 0x41, 0x89, 0x5b, 0x03, 0xcc, 0x36, 0xe4, 0xdd, 0x54,
+	0xa6, 0x01, 0x6d, 0xde, 0x86, 0x8b, 0xe1, 0x88, 0xfd, 0x4e, 0x30, 0xa6, 0x03, 0x05, 0x11, 0x26,
+	0xf4, 0x6d, 0x96, 0x76, 0xae, 0xf7, 0x3b, 0x32, 0x83, 0x54, 0x9c, 0xe6, 0xeb, 0xd0, 0x98, 0xea,
+	0x0c, 0x2e, 0x1e, 0x23, 0x72, 0xf1, 0x10, 0xc8, 0x71, 0xac, 0xde, 0x32, 0x42, 0x19, 0xd1, 0x36,
+	0xc7, 0x70, 0x29, 0xdd, 0xb7, 0x44, 0x2e, 0x26, 0xd5, 0x0d, 0x72, 0x31, 0x49, 0x62, 0x08, 0x13,
+	0x25, 0xb1, 0x2e, 0x70, 0x04, 0x11, 0x06, 0xb6, 0x5c, 0x4a, 0x60, 0xcb, 0x87, 0x81, 0xed, 0x1e,
+	0x3c, 0x3d, 0x35, 0xa3, 0x5a, 0x3d, 0x06, 0x77, 0x0d, 0xaa, 0x2d, 0x0b, 0x01, 0x54, 0x68, 0x6c,
+	0x79, 0xdc, 0xb6, 0x86, 0x62, 0xe2, 0x12, 0xd5, 0xa4, 0x79, 0x0d, 0x4a, 0x5a, 0x18, 0x21, 0x91,
+	0xe4, 0xb9, 0x2c, 0xb3, 0xe3, 0xf4, 0x8a, 0xcc, 0x3c, 0x82, 0x67, 0x12, 0x8a, 0x44, 0x0c, 0xb1,
+	0x92, 0x54, 0xa5, 0xd2, 0x69, 0x84, 0x49, 0x97, 0xea, 0x79, 0x34, 0xed, 0xd6, 0x20, 0x2f, 0x2e,
+	0x4b, 0x72, 0x03, 0x8a, 0x87, 0x22, 0xeb, 0xd0, 0x12, 0xc3, 0xf3, 0x2d, 0x5f, 0x3b, 0x4e, 0xae,
+	0x2e, 0x53, 0xe6, 0xbb, 0x13, 0xaf, 0xc7, 0xc4, 0xed, 0x43, 0x35, 0xbf, 0xf9, 0x6b, 0x03, 0xaa,
+	0xbb, 0x13, 0x3f, 0xcc, 0xd4, 0xdf, 0x84, 0x9a, 0xc8, 0x87, 0xfc, 0xb5, 0xd3, 0x3d, 0xf5, 0xf8,
+	0x90, 0x5d, 0x9a, 0x8d, 0x78, 0x2d, 0x72, 0x6f, 0x20, 0x07, 0x65, 0x96, 0xef, 0x3a, 0x34, 0xce,
+	0x4e, 0xde, 0x80, 0xea, 0x10, 0x8b, 0xe6, 0x75, 0xc6, 0x2d, 0x7b, 0x28, 0x7d, 0x22, 0xfa, 0x26,
+	0x81, 0xc3, 0xb7, 0x22, 0x0c, 0x34, 0xc6, 0x6e, 0xfe, 0xc9, 0x80, 0x7a, 0x92, 0x85, 0xb4, 0xa1,
+	0xe0, 0x89, 0xc9, 0x54, 0xe5, 0x72, 0xb6, 0x32, 0x8a, 0x0f, 0x0d, 0x23, 0xc4, 0x6a, 0xc3, 0x08,
+	0x02, 0x0f, 0x6d, 0xcf, 0x75, 0x8e, 0xec, 0xc1, 0xc4, 0x63, 0xf2, 0x85, 0x26, 0x4b, 0x23, 0x08,
+	0xba, 0x09, 0x3b, 0x3a, 0x62, 0x3d, 0x6e, 0x9f, 0x30, 0xe1, 0x6d, 0x59, 0x1a, 0x02, 0x78, 0xec,
+	0x46, 0xcc, 0xf2, 0xc5, 0xd8, 0xbc, 0xe8, 0x0c, 0x68, 0xf3, 0x0f, 0x4a, 0x6d, 0x91, 0x03, 0x68,
+	0x47, 0x7f, 0x39, 0x28, 0x7a, 0x70, 0x13, 0xaa, 0x6b, 0x4f, 0xe1, 0xa3, 0xc5, 0xdf, 0x3f, 0xbb,
+	0x5c, 0xdb, 0xf5, 0x98, 0x35, 0x1c, 0xba, 0x3d, 0xc9, 0xad, 0x98, 0xc8, 0x77, 0x20, 0x6b, 0xf7,
+	0x65, 0xa6, 0x78, 0x26, 0x2f, 0x72, 0x90, 0xeb, 0x00, 0x32, 0x3c, 0xaf, 0x5b, 0xdc, 0x6a, 0xe6,
+	0xce, 0xe3, 0x8f, 0x30, 0x9a, 0xdb, 0x52, 0x45, 0xe9, 0x00, 0x4a, 0xc5, 0xff, 0xc1, 0x73, 0xde,
+	0x87, 0x06, 0x8a, 0x93, 0xe9, 0x9a, 0x96, 0x37, 0x0b, 0x19, 0xbb, 0x2f, 0xac, 0x94, 0xa3, 0x19,
+	0xbb, 0x1f, 0x95, 0x9f, 0x79, 0x4c, 0xf9, 0x47, 0x40, 0xa2, 0xf2, 0x95, 0x7b, 0x26, 0x27, 0x40,
+	0x93, 0xc9, 0xf4, 0xbd, 0xcf, 0xf4, 0x73, 0x54, 0x00, 0x60, 0xdc, 0x17, 0xc4, 0x36, 0xf3, 0x7d,
+	0x7d, 0x49, 0x95, 0x69, 0x0c, 0x33, 0xaf, 0x00, 0xa8, 0xa7, 0x30, 0xce, 0x7c, 0xcc, 0xb7, 0x23,
+	0x85, 0x6a, 0x55, 0x1b, 0xc7, 0x7c, 0x13, 0xca, 0x5b, 0xb6, 0x73, 0xdc, 0x1d, 0xda, 0x3d, 0xac,
+	0xa3, 0xf3, 0x43, 0xdb, 0x39, 0xd6, 0x7b, 0x36, 0x3f, 0xbd, 0x26, 0x5c, 0xcb, 0x32, 0x0e, 0xa0,
+	0x92, 0xd3, 0xfc, 0x99, 0x01, 0x04, 0x41, 0x5d, 0xb1, 0x86, 0xa9, 0x9c, 0x8c, 0x78, 0x46, 0x34,
+	0xe2, 0x35, 0xa1, 0x38, 0xf0, 0xdc, 0xc9, 0x78, 0x4d, 0x47, 0x42, 0x4d, 0x86, 0x7e, 0x2d, 0xd3,
+	0x7a, 0x49, 0x3c, 0x72, 0x84, 0xfc, 0xa5, 0x01, 0xcf, 0x44, 0x94, 0xe8, 0x4e, 0x46, 0x23, 0xcb,
+	0x3b, 0xfd, 0xff, 0xe8, 0xf2, 0x37, 0x03, 0x2e, 0xc4, 0x36, 0x24, 0x0c, 0xd5, 0xcc, 0xe7, 0xf6,
+	0x08, 0xaf, 0x41, 0xa1, 0x49, 0x89, 0x86, 0x40, 0xbc, 0xba, 0x93, 0x05, 0x41, 0x08, 0x60, 0xee,
+	0x2d, 0x4c, 0xdb, 0x0d, 0x58, 0xa4, 0x6a, 0x09, 0x94, 0x2c, 0x87, 0xef, 0x0a, 0x39, 0x61, 0xc1,
+	0x8b, 0xb1, 0xda, 0x2e, 0xf9, 0xaa, 0xf0, 0xa8, 0xef, 0x7f, 0xe6, 0xf7, 0xa1, 0x4a, 0xad, 0x1f,
+	0xbf, 0x65, 0xfb, 0xdc, 0x1d, 0x78, 0xd6, 0x08, 0x9d, 0xe9, 0x70, 0xd2, 0x3b, 0x66, 0x5c, 0x39,
+	0xac, 0xa2, 0x50, 0x5e, 0x2f, 0xb2, 0x02, 0x49, 0x98, 0xb7, 0xa1, 0xa4, 0xab, 0xa8, 0x94, 0xc2,
+	0xf8, 0xa5, 0x78, 0x61, 0x7c, 0x29, 0x5e, 0xa8, 0xdf, 0xdb, 0xc2, 0xea, 0xd7, 0xee, 0xe9, 0x2b,
+	0xe8, 0xb7, 0x06, 0x54, 0x22, 0x4b, 0x21, 0x6b, 0xd0, 0x18, 0x5a, 0x9c, 0x39, 0xbd, 0xd3, 0x83,
+	0xfb, 0x5a, 0x3d, 0xe5, 0xbd, 0x61, 0x89, 0x1d, 0xd5, 0x9d, 0xd6, 0x15, 0x7f, 0xb8, 0x9a, 0xef,
+	0x42, 0xc1, 0x67, 0x9e, 0x1d, 0x1c, 0xe5, 0xf0, 0xda, 0x0a, 0x8a, 0x3f, 0xc5, 0x80, 0x0b, 0x97,
+	0xb7, 0x82, 0x32, 0x80, 0xa2, 0xcc, 0xff, 0xc4, 0x4f, 0x81, 0x72, 0xc0, 0xe9, 0x9a, 0xfd, 0x21,
+	0x56, 0xcd, 0xa4, 0x5a, 0x35, 0xd4, 0x2f, 0xfb, 0x30, 0xfd, 0xea, 0x90, 0x1d, 0xdf, 0xb8, 0xa1,
+	0x2a, 0x5e, 0x6c, 0x4a, 0xe4, 0x7a, 0x33, 0xaf, 0x91, 0xeb, 0x12, 0x69, 0xab, 0x32, 0x0f, 0x9b,
+	0x02, 0xb9, 0xde, 0x56, 0xf5, 0x1c, 0x36, 0x31, 0x27, 0xf0, 0x2c, 0xce, 0x44, 0x3e, 0x69, 0x50,
+	0xd1, 0x0e, 0x22, 0x12, 0xc5, 0x8e, 0xb2, 0xe8, 0x08, 0x01, 0xf3, 0x1d, 0x68, 0xa5, 0x9d, 0x40,
+	0xe5, 0xfc, 0x37, 0xa0, 0xec, 0x0b, 0xc8, 0x66, 0xd3, 0xc1, 0x25, 0x65, 0x5c, 0xc8, 0x6d, 0xfe,
+	0xce, 0x80, 0x5a, 0xcc, 0x15, 0x62, 0x09, 0x4b, 0x5e, 0x25, 0x2c, 0x55, 0x30, 0x1c, 0xb1, 0x7d,
+	0x59, 0x6a, 0x38, 0x48, 0x1d, 0x09, 0x0b, 0x19, 0xd4, 0x38, 0x42, 0x4a, 0xd6, 0xc6, 0x65, 0x6a,
+	0xf8, 0x48, 0x1d, 0x8a, 0xed, 0x28, 0x51, 0xe3, 0x10, 0xa9, 0xbe, 0xda, 0x0a, 0xa3, 0x8f, 0xe6,
+	0xf5, 0xb9, 0xc5, 0x27, 0x32, 0xd9, 0xce, 0x53, 0x45, 0xe1, 0x8c, 0xc7, 0xb6, 0xd3, 0x17, 0xdb,
+	0x91, 0xa7, 0xa2, 0x6d, 0x32, 0x98, 0x8b, 0x28, 0x8e, 0x17, 0x11, 0x5e, 0xc3, 0x1e, 0xf3, 0x27,
+	0x43, 0xbe, 0x17, 0xe6, 0x53, 0x11, 0x04, 0x73, 0x55, 0x49, 0x35, 0x33, 0xc9, 0x5c, 0x35, 0x16,
+	0x30, 0x26, 0x43, 0x4e, 0x15, 0x27, 0xc6, 0xd7, 0xc6, 0x54, 0x2f, 0xda, 0x62, 0x68, 0x1d, 0xb2,
+	0x61, 0x24, 0xd9, 0x0c, 0x01, 0xd4, 0x43, 0x10, 0xfb, 0x91, 0x14, 0x2e, 0x82, 0x90, 0x15, 0xc8,
+	0x70, 0xed, 0x4c, 0x97, 0xcf, 0xd6, 0x61, 0xd7, 0xb5, 0x1d, 0x4e, 0x33, 0xdc, 0xc7, 0x53, 0x77,
+	0x29, 0xbd, 0x5b, 0x18, 0xc3, 0x56, 0x4a, 0xd4, 0xa8, 0x68, 0xa3, 0x3f, 0x9d, 0xa8, 0xac, 0xce,
+	0xa0, 0xd8, 0xc4, 0x67, 0x06, 0xf6, 0x80, 0x8d, 0xc6, 0x43, 0xcb, 0xdb, 0x53, 0xcf, 0x95, 0x59,
+	0xf1, 0x09, 0x2a, 0x09, 0x93, 0x17, 0xa0, 0xae, 0x21, 0xfd, 0xf9, 0x42, 0xb9, 0xf3, 0x14, 0x6e,
+	0xfe, 0x35, 0x0b, 0x0d, 0xf1, 0x29, 0x82, 0x5a, 0xce, 0x80, 0x9d, 0x1f, 0xee, 0x83, 0x50, 0xa7,
+	0x42, 0x53, 0x2c, 0xd4, 0xc9, 0xc3, 0x8c, 0x4d, 0x5c, 0x8f, 0xcf, 0xd9, 0x58, 0xcd, 0x29, 0xda,
+	0x78, 0x55, 0xf8, 0xf7, 0x2d, 0xaf, 0xbf, 0xb9, 0xae, 0x02, 0xa5, 0x26, 0x71, 0xa7, 0x45, 0x53,
+	0x1e, 0x5f, 0x19, 0x31, 0x23, 0x48, 0xfc, 0xe3, 0x58, 0x31, 0xf1, 0x71, 0x2c, 0x5a, 0x81, 0x96,
+	0xce, 0xa9, 0x40, 0xcb, 0x0f, 0xad, 0x40, 0x21, 0xad, 0x02, 0x8d, 0xd4, 0x7d, 0x95, 0x78, 0xdd,
+	0x17, 0xad, 0x4d, 0xab, 0x89, 0xda, 0x54, 0xd7, 0x84, 0xb5, 0x33, 0x6b, 0xc2, 0xd9, 0x47, 0xaa,
+	0x09, 0xe7, 0x1e, 0xbb, 0x26, 0xf4, 0x81, 0x44, 0x8d, 0xa9, 0x22, 0xc7, 0x8b, 0x41, 0xf0, 0x93,
+	0x61, 0xe3, 0x42, 0x78, 0x3f, 0xd8, 0x23, 0xd6, 0x15, 0x5d, 0x41, 0xf8, 0x7b, 0xfc, 0x77, 0xf5,
+	0x55, 0x28, 0x74, 0x2d, 0x7c, 0x2e, 0x23, 0xdf, 0x82, 0x2a, 0x3a, 0xaf, 0xcf, 0xad, 0xd1, 0xf8,
+	0x60, 0xe4, 0xab, 0x60, 0x52, 0x09, 0x30, 0xf9, 0x11, 0x4d, 0x5e, 0x55, 0x86, 0xf0, 0x6c, 0x49,
+	0x98, 0xbf, 0x37, 0x00, 0x42, 0x5d, 0xc8, 0x0d, 0x28, 0x88, 0xa3, 0x36, 0x1d, 0xe7, 0xa6, 0x1f,
+	0x15, 0xd5, 0xe7, 0x3e, 0x35, 0x80, 0xac, 0x40, 0xd1, 0x17, 0xca, 0xe8, 0x9b, 0x68, 0x2e, 0x54,
+	0x5f, 0xe0, 0x8a, 0x5f, 0x73, 0x91, 0xcb, 0x50, 0x19, 0x7b, 0xee, 0xe8, 0x40, 0x4d, 0x28, 0xb3,
+	0x40, 0x40, 0x68, 0x4b, 0x20, 0x2f, 0xbc, 0x07, 0x73, 0x89, 0x4a, 0x02, 0xbf, 0x72, 0xec, 0xdc,
+	0x3d, 0xd8, 0xa0, 0xf4, 0x2e, 0xad, 0xcf, 0x90, 0x0b, 0x30, 0xb7, 0xbd, 0xfa, 0xee, 0xc1, 0xd6,
+	0xe6, 0xfe, 0xc6, 0xc1, 0x1e, 0x5d, 0xbd, 0xb9, 0xd1, 0xad, 0x1b, 0x08, 0x8a, 0xf6, 0xc1, 0xde,
+	0xdd, 0xbb, 0x07, 0x5b, 0xab, 0xf4, 0xd6, 0x46, 0x3d, 0x43, 0x1a, 0x50, 0x7b, 0x7b, 0xe7, 0xce,
+	0xce, 0xdd, 0x77, 0x76, 0xd4, 0xe0, 0x6c, 0xe7, 0x57, 0x06, 0x14, 0x50, 0x3c, 0xf3, 0xc8, 0x0f,
+	0xa0, 0x1c, 0x94, 0x09, 0x24, 0x5e, 0x14, 0x45, 0x4b, 0x87, 0xd6, 0x53, 0xb1, 0x2e, 0x6d, 0x65,
+	0x73, 0x86, 0xac, 0x42, 0x25, 0x60, 0xde, 0xef, 0x7c, 0x19, 0x11, 0x9d, 0xf7, 0x61, 0xae, 0xcb,
+	0x3d, 0x66, 0x8d, 0x6c, 0x67, 0xa0, 0xd4, 0xba, 0x03, 0x10, 0xe6, 0xda, 0xa4, 0x15, 0x1b, 0x19,
+	0x4b, 0xf0, 0x5b, 0xf3, 0xa9, 0x7d, 0x5a, 0xf6, 0x92, 0xd1, 0x36, 0x3a, 0xff, 0x34, 0xa0, 0xae,
+	0x1c, 0xe8, 0x16, 0x73, 0x98, 0x67, 0x71, 0x37, 0x58, 0xb8, 0x7c, 0xfb, 0x8c, 0x6b, 0x1d, 0x2d,
+	0x48, 0xce, 0x5e, 0xf8, 0x26, 0xc0, 0x2d, 0xc6, 0x95, 0x5c, 0x32, 0x9f, 0x1e, 0x8e, 0xa5, 0x8c,
+	0x67, 0xd3, 0x3b, 0x03, 0x51, 0xb7, 0x00, 0xc2, 0x13, 0x14, 0x59, 0xed, 0x54, 0x8c, 0x6c, 0xcd,
+	0xa7, 0xf6, 0x05, 0x3b, 0xf9, 0xef, 0x1c, 0x14, 0xb1, 0xc3, 0x66, 0x1e, 0x79, 0x0b, 0x6a, 0x3f,
+	0xb4, 0x9d, 0x7e, 0xf0, 0x55, 0x9d, 0xa4, 0x7c, 0x86, 0xd7, 0x62, 0x5b, 0x69, 0x5d, 0x81, 0x7a,
+	0xbb, 0x70, 0x21, 0x26, 0x49, 0x1a, 0xeb, 0x4b, 0xcb, 0x6b, 0x1b, 0x64, 0x15, 0xaa, 0xf2, 0x5c,
+	0x53, 0xd6, 0x63, 0x0e, 0x27, 0x67, 0x7c, 0x04, 0x6e, 0x3d, 0x3d, 0x85, 0x07, 0x4a, 0x6d, 0x40,
+	0x25, 0xf2, 0x81, 0x39, 0xba, 0xff, 0x53, 0x9f, 0x9d, 0xcf, 0x13, 0x73, 0x0b, 0x20, 0x7c, 0x52,
+	0x22, 0xe7, 0x3c, 0x2e, 0xb7, 0xe6, 0x53, 0xfb, 0x02, 0x41, 0x77, 0xa0, 0x1a, 0xe2, 0xfb, 0x9d,
+	0x73, 0x45, 0x3d, 0x97, 0xfa, 0xd6, 0x15, 0x11, 0xb6, 0x0f, 0x73, 0x89, 0x07, 0x1b, 0xf2, 0xb0,
+	0x17, 0xd2, 0xd6, 0xe2, 0xd9, 0x0c, 0x81, 0xdc, 0x1f, 0x41, 0x23, 0xd1, 0xb9, 0xdf, 0x79, 0xb8,
+	0x64, 0xf3, 0x2c, 0x86, 0xa8, 0xce, 0x9d, 0x0f, 0x73, 0x50, 0x0f, 0x8e, 0xb1, 0x76, 0xc2, 0xd7,
+	0xa1, 0x20, 0xc7, 0x3c, 0xb6, 0x89, 0xdb, 0x06, 0x9e, 0xb0, 0x27, 0x62, 0x9b, 0xb6, 0x41, 0xb6,
+	0x9f, 0xa0, 0x75, 0xda, 0x06, 0x79, 0xf7, 0xab, 0xb1, 0x4f, 0xdb, 0x20, 0xef, 0x7d, 0x75, 0x16,
+	0x6a, 0x1b, 0x64, 0x17, 0x1a, 0x2a, 0xfa, 0x3c, 0x91, 0x78, 0xd3, 0x36, 0xc8, 0xed, 0x27, 0x15,
+	0x65, 0xda, 0x46, 0xe7, 0x8f, 0x06, 0x14, 0x75, 0x3c, 0x3d, 0x48, 0xad, 0xcb, 0xcc, 0xf3, 0x6a,
+	0x0f, 0x35, 0xcb, 0xf3, 0xe7, 0xf2, 0x3c, 0xf1, 0x98, 0xbb, 0xd6, 0xfc, 0xe8, 0xf3, 0x05, 0xe3,
+	0x93, 0xcf, 0x17, 0x8c, 0x7f, 0x7c, 0xbe, 0x60, 0xfc, 0xe6, 0x8b, 0x85, 0x99, 0x4f, 0xbe, 0x58,
+	0x98, 0xf9, 0xf4, 0x8b, 0x85, 0x99, 0xc3, 0x82, 0xf8, 0x4f, 0xd7, 0x2b, 0xff, 0x1d, 0x00, 0x56,
+	0x06, 0xc3, 0x1a, 0x54, 0x26, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -3411,55 +3717,167 @@ var _Pusher_serviceDesc = grpc.ServiceDesc{
 	Metadata: "pkg/tempopb/tempo.proto",
 }
 
-// MetricsGeneratorClient is the client API for MetricsGenerator service.
+// StreamingPusherClient is the client API for StreamingPusher service.
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
-type MetricsGeneratorClient interface {
-	PushSpans(ctx context.Context, in *PushSpansRequest, opts ...grpc.CallOption) (*PushResponse, error)
-	GetMetrics(ctx context.Context, in *SpanMetricsRequest, opts ...grpc.CallOption) (*SpanMetricsResponse, error)
-	QueryRange(ctx context.Context, in *QueryRangeRequest, opts ...grpc.CallOption) (*QueryRangeResponse, error)
+type StreamingPusherClient interface {
+	// PushTraces pushes batches of spans over a long-lived stream. Every request is
+	// acknowledged by a response with the same id. The distributor processes a
+	// limited number of requests of a stream concurrently and stops reading the
+	// stream while the limit is reached.
+	PushTraces(ctx context.Context, opts ...grpc.CallOption) (StreamingPusher_PushTracesClient, error)
 }
 
-type metricsGeneratorClient struct {
+type streamingPusherClient struct {
 	cc *grpc.ClientConn
 }
 
-func NewMetricsGeneratorClient(cc *grpc.ClientConn) MetricsGeneratorClient {
-	return &metricsGeneratorClient{cc}
+func NewStreamingPusherClient(cc *grpc.ClientConn) StreamingPusherClient {
+	return &streamingPusherClient{cc}
 }
 
-func (c *metricsGeneratorClient) PushSpans(ctx context.Context, in *PushSpansRequest, opts ...grpc.CallOption) (*PushResponse, error) {
-	out := new(PushResponse)
-	err := c.cc.Invoke(ctx, "/tempopb.MetricsGenerator/PushSpans", in, out, opts...)
+func (c *streamingPusherClient) PushTraces(ctx context.Context, opts ...grpc.CallOption) (StreamingPusher_PushTracesClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_StreamingPusher_serviceDesc.Streams[0], "/tempopb.StreamingPusher/PushTraces", opts...)
 	if err != nil {
 		return nil, err
 	}
-	return out, nil
+	x := &streamingPusherPushTracesClient{stream}
+	return x, nil
 }
 
-func (c *metricsGeneratorClient) GetMetrics(ctx context.Context, in *SpanMetricsRequest, opts ...grpc.CallOption) (*SpanMetricsResponse, error) {
-	out := new(SpanMetricsResponse)
-	err := c.cc.Invoke(ctx, "/tempopb.MetricsGenerator/GetMetrics", in, out, opts...)
-	if err != nil {
-		return nil, err
-	}
-	return out, nil
+type StreamingPusher_PushTracesClient interface {
+	Send(*PushTracesRequest) error
+	Recv() (*PushTracesResponse, error)
+	grpc.ClientStream
 }
 
-func (c *metricsGeneratorClient) QueryRange(ctx context.Context, in *QueryRangeRequest, opts ...grpc.CallOption) (*QueryRangeResponse, error) {
-	out := new(QueryRangeResponse)
-	err := c.cc.Invoke(ctx, "/tempopb.MetricsGenerator/QueryRange", in, out, opts...)
-	if err != nil {
+type streamingPusherPushTracesClient struct {
+	grpc.ClientStream
+}
+
+func (x *streamingPusherPushTracesClient) Send(m *PushTracesRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *streamingPusherPushTracesClient) Recv() (*PushTracesResponse, error) {
+	m := new(PushTracesResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
 		return nil, err
 	}
-	return out, nil
+	return m, nil
 }
 
-// MetricsGeneratorServer is the server API for MetricsGenerator service.
-type MetricsGeneratorServer interface {
-	PushSpans(context.Context, *PushSpansRequest) (*PushResponse, error)
-	GetMetrics(context.Context, *SpanMetricsRequest) (*SpanMetricsResponse, error)
-	QueryRange(context.Context, *QueryRangeRequest) (*QueryRangeResponse, error)
+// StreamingPusherServer is the server API for StreamingPusher service.
+type StreamingPusherServer interface {
+	// PushTraces pushes batches of spans over a long-lived stream. Every request is
+	// acknowledged by a response with the same id. The distributor processes a
+	// limited number of requests of a stream concurrently and stops reading the
+	// stream while the limit is reached.
+	PushTraces(StreamingPusher_PushTracesServer) error
+}
+
+// UnimplementedStreamingPusherServer can be embedded to have forward compatible implementations.
+type UnimplementedStreamingPusherServer struct {
+}
+
+func (*UnimplementedStreamingPusherServer) PushTraces(srv StreamingPusher_PushTracesServer) error {
+	return status.Errorf(codes.Unimplemented, "method PushTraces not implemented")
+}
+
+func RegisterStreamingPusherServer(s *grpc.Server, srv StreamingPusherServer) {
+	s.RegisterService(&_StreamingPusher_serviceDesc, srv)
+}
+
+func _StreamingPusher_PushTraces_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(StreamingPusherServer).PushTraces(&streamingPusherPushTracesServer{stream})
+}
+
+type StreamingPusher_PushTracesServer interface {
+	Send(*PushTracesResponse) error
+	Recv() (*PushTracesRequest, error)
+	grpc.ServerStream
+}
+
+type streamingPusherPushTracesServer struct {
+	grpc.ServerStream
+}
+
+func (x *streamingPusherPushTracesServer) Send(m *PushTracesResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *streamingPusherPushTracesServer) Recv() (*PushTracesRequest, error) {
+	m := new(PushTracesRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+var _StreamingPusher_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "tempopb.StreamingPusher",
+	HandlerType: (*StreamingPusherServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "PushTraces",
+			Handler:       _StreamingPusher_PushTraces_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "pkg/tempopb/tempo.proto",
+}
+
+// MetricsGeneratorClient is the client API for MetricsGenerator service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type MetricsGeneratorClient interface {
+	PushSpans(ctx context.Context, in *PushSpansRequest, opts ...grpc.CallOption) (*PushResponse, error)
+	GetMetrics(ctx context.Context, in *SpanMetricsRequest, opts ...grpc.CallOption) (*SpanMetricsResponse, error)
+	QueryRange(ctx context.Context, in *QueryRangeRequest, opts ...grpc.CallOption) (*QueryRangeResponse, error)
+}
+
+type metricsGeneratorClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewMetricsGeneratorClient(cc *grpc.ClientConn) MetricsGeneratorClient {
+	return &metricsGeneratorClient{cc}
+}
+
+func (c *metricsGeneratorClient) PushSpans(ctx context.Context, in *PushSpansRequest, opts ...grpc.CallOption) (*PushResponse, error) {
+	out := new(PushResponse)
+	err := c.cc.Invoke(ctx, "/tempopb.MetricsGenerator/PushSpans", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *metricsGeneratorClient) GetMetrics(ctx context.Context, in *SpanMetricsRequest, opts ...grpc.CallOption) (*SpanMetricsResponse, error) {
+	out := new(SpanMetricsResponse)
+	err := c.cc.Invoke(ctx, "/tempopb.MetricsGenerator/GetMetrics", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *metricsGeneratorClient) QueryRange(ctx context.Context, in *QueryRangeRequest, opts ...grpc.CallOption) (*QueryRangeResponse, error) {
+	out := new(QueryRangeResponse)
+	err := c.cc.Invoke(ctx, "/tempopb.MetricsGenerator/QueryRange", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// MetricsGeneratorServer is the server API for MetricsGenerator service.
+type MetricsGeneratorServer interface {
+	PushSpans(context.Context, *PushSpansRequest) (*PushResponse, error)
+	GetMetrics(context.Context, *SpanMetricsRequest) (*SpanMetricsResponse, error)
+	QueryRange(context.Context, *QueryRangeRequest) (*QueryRangeResponse, error)
 }
 
 // UnimplementedMetricsGeneratorServer can be embedded to have forward compatible implementations.
@@ -3560,6 +3978,7 @@ var _MetricsGenerator_serviceDesc = grpc.ServiceDesc{
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
 type QuerierClient interface {
 	FindTraceByID(ctx context.Context, in *TraceByIDRequest, opts ...grpc.CallOption) (*TraceByIDResponse, error)
+	FindTraceByIDStream(ctx context.Context, in *TraceByIDRequest, opts ...grpc.CallOption) (Querier_FindTraceByIDStreamClient, error)
 	SearchRecent(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (*SearchResponse, error)
 	SearchBlock(ctx context.Context, in *SearchBlockRequest, opts ...grpc.CallOption) (*SearchResponse, error)
 	SearchTags(ctx context.Context, in *SearchTagsRequest, opts ...grpc.CallOption) (*SearchTagsResponse, error)
@@ -3585,6 +4004,38 @@ func (c *querierClient) FindTraceByID(ctx context.Context, in *TraceByIDRequest,
 	return out, nil
 }
 
+func (c *querierClient) FindTraceByIDStream(ctx context.Context, in *TraceByIDRequest, opts ...grpc.CallOption) (Querier_FindTraceByIDStreamClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Querier_serviceDesc.Streams[0], "/tempopb.Querier/FindTraceByIDStream", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &querierFindTraceByIDStreamClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Querier_FindTraceByIDStreamClient interface {
+	Recv() (*TraceByIDResponse, error)
+	grpc.ClientStream
+}
+
+type querierFindTraceByIDStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *querierFindTraceByIDStreamClient) Recv() (*TraceByIDResponse, error) {
+	m := new(TraceByIDResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func (c *querierClient) SearchRecent(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (*SearchResponse, error) {
 	out := new(SearchResponse)
 	err := c.cc.Invoke(ctx, "/tempopb.Querier/SearchRecent", in, out, opts...)
@@ -3642,6 +4093,7 @@ func (c *querierClient) SearchTagValuesV2(ctx context.Context, in *SearchTagValu
 // QuerierServer is the server API for Querier service.
 type QuerierServer interface {
 	FindTraceByID(context.Context, *TraceByIDRequest) (*TraceByIDResponse, error)
+	FindTraceByIDStream(*TraceByIDRequest, Querier_FindTraceByIDStreamServer) error
 	SearchRecent(context.Context, *SearchRequest) (*SearchResponse, error)
 	SearchBlock(context.Context, *SearchBlockRequest) (*SearchResponse, error)
 	SearchTags(context.Context, *SearchTagsRequest) (*SearchTagsResponse, error)
@@ -3657,6 +4109,9 @@ type UnimplementedQuerierServer struct {
 func (*UnimplementedQuerierServer) FindTraceByID(ctx context.Context, req *TraceByIDRequest) (*TraceByIDResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method FindTraceByID not implemented")
 }
+func (*UnimplementedQuerierServer) FindTraceByIDStream(req *TraceByIDRequest, srv Querier_FindTraceByIDStreamServer) error {
+	return status.Errorf(codes.Unimplemented, "method FindTraceByIDStream not implemented")
+}
 func (*UnimplementedQuerierServer) SearchRecent(ctx context.Context, req *SearchRequest) (*SearchResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method SearchRecent not implemented")
 }
@@ -3698,6 +4153,27 @@ func _Querier_FindTraceByID_Handler(srv interface{}, ctx context.Context, dec fu
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Querier_FindTraceByIDStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(TraceByIDRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(QuerierServer).FindTraceByIDStream(m, &querierFindTraceByIDStreamServer{stream})
+}
+
+type Querier_FindTraceByIDStreamServer interface {
+	Send(*TraceByIDResponse) error
+	grpc.ServerStream
+}
+
+type querierFindTraceByIDStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *querierFindTraceByIDStreamServer) Send(m *TraceByIDResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 func _Querier_SearchRecent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(SearchRequest)
 	if err := dec(in); err != nil {
@@ -3839,7 +4315,13 @@ var _Querier_serviceDesc = grpc.ServiceDesc{
 			Handler:    _Querier_SearchTagValuesV2_Handler,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "FindTraceByIDStream",
+			Handler:       _Querier_FindTraceByIDStream_Handler,
+			ServerStreams: true,
+		},
+	},
 	Metadata: "pkg/tempopb/tempo.proto",
 }
 
@@ -3853,6 +4335,7 @@ type StreamingQuerierClient interface {
 	SearchTagValues(ctx context.Context, in *SearchTagValuesRequest, opts ...grpc.CallOption) (StreamingQuerier_SearchTagValuesClient, error)
 	SearchTagValuesV2(ctx context.Context, in *SearchTagValuesRequest, opts ...grpc.CallOption) (StreamingQuerier_SearchTagValuesV2Client, error)
 	MetricsQueryRange(ctx context.Context, in *QueryRangeRequest, opts ...grpc.CallOption) (StreamingQuerier_MetricsQueryRangeClient, error)
+	FindTraceByID(ctx context.Context, in *TraceByIDRequest, opts ...grpc.CallOption) (StreamingQuerier_FindTraceByIDClient, error)
 }
 
 type streamingQuerierClient struct {
@@ -4055,6 +4538,38 @@ func (x *streamingQuerierMetricsQueryRangeClient) Recv() (*QueryRangeResponse, e
 	return m, nil
 }
 
+func (c *streamingQuerierClient) FindTraceByID(ctx context.Context, in *TraceByIDRequest, opts ...grpc.CallOption) (StreamingQuerier_FindTraceByIDClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_StreamingQuerier_serviceDesc.Streams[6], "/tempopb.StreamingQuerier/FindTraceByID", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &streamingQuerierFindTraceByIDClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type StreamingQuerier_FindTraceByIDClient interface {
+	Recv() (*TraceByIDResponse, error)
+	grpc.ClientStream
+}
+
+type streamingQuerierFindTraceByIDClient struct {
+	grpc.ClientStream
+}
+
+func (x *streamingQuerierFindTraceByIDClient) Recv() (*TraceByIDResponse, error) {
+	m := new(TraceByIDResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // StreamingQuerierServer is the server API for StreamingQuerier service.
 type StreamingQuerierServer interface {
 	Search(*SearchRequest, StreamingQuerier_SearchServer) error
@@ -4063,6 +4578,7 @@ type StreamingQuerierServer interface {
 	SearchTagValues(*SearchTagValuesRequest, StreamingQuerier_SearchTagValuesServer) error
 	SearchTagValuesV2(*SearchTagValuesRequest, StreamingQuerier_SearchTagValuesV2Server) error
 	MetricsQueryRange(*QueryRangeRequest, StreamingQuerier_MetricsQueryRangeServer) error
+	FindTraceByID(*TraceByIDRequest, StreamingQuerier_FindTraceByIDServer) error
 }
 
 // UnimplementedStreamingQuerierServer can be embedded to have forward compatible implementations.
@@ -4087,6 +4603,9 @@ func (*UnimplementedStreamingQuerierServer) SearchTagValuesV2(req *SearchTagValu
 func (*UnimplementedStreamingQuerierServer) MetricsQueryRange(req *QueryRangeRequest, srv StreamingQuerier_MetricsQueryRangeServer) error {
 	return status.Errorf(codes.Unimplemented, "method MetricsQueryRange not implemented")
 }
+func (*UnimplementedStreamingQuerierServer) FindTraceByID(req *TraceByIDRequest, srv StreamingQuerier_FindTraceByIDServer) error {
+	return status.Errorf(codes.Unimplemented, "method FindTraceByID not implemented")
+}
 
 func RegisterStreamingQuerierServer(s *grpc.Server, srv StreamingQuerierServer) {
 	s.RegisterService(&_StreamingQuerier_serviceDesc, srv)
@@ -4218,6 +4737,27 @@ func (x *streamingQuerierMetricsQueryRangeServer) Send(m *QueryRangeResponse) er
 	return x.ServerStream.SendMsg(m)
 }
 
+func _StreamingQuerier_FindTraceByID_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(TraceByIDRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(StreamingQuerierServer).FindTraceByID(m, &streamingQuerierFindTraceByIDServer{stream})
+}
+
+type StreamingQuerier_FindTraceByIDServer interface {
+	Send(*TraceByIDResponse) error
+	grpc.ServerStream
+}
+
+type streamingQuerierFindTraceByIDServer struct {
+	grpc.ServerStream
+}
+
+func (x *streamingQuerierFindTraceByIDServer) Send(m *TraceByIDResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 var _StreamingQuerier_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "tempopb.StreamingQuerier",
 	HandlerType: (*StreamingQuerierServer)(nil),
@@ -4253,6 +4793,11 @@ var _StreamingQuerier_serviceDesc = grpc.ServiceDesc{
 			Handler:       _StreamingQuerier_MetricsQueryRange_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "FindTraceByID",
+			Handler:       _StreamingQuerier_FindTraceByID_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "pkg/tempopb/tempo.proto",
 }
@@ -4483,6 +5028,21 @@ func (m *TraceByIDMetrics) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if m.SkippedBlocks != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.SkippedBlocks))
+		i--
+		dAtA[i] = 0x18
+	}
+	if m.TotalJobs != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.TotalJobs))
+		i--
+		dAtA[i] = 0x10
+	}
+	if m.CompletedJobs != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.CompletedJobs))
+		i--
+		dAtA[i] = 0x8
+	}
 	return len(dAtA) - i, nil
 }
 
@@ -4781,6 +5341,15 @@ func (m *TraceSearchMetadata) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if len(m.Clusters) > 0 {
+		for iNdEx := len(m.Clusters) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Clusters[iNdEx])
+			copy(dAtA[i:], m.Clusters[iNdEx])
+			i = encodeVarintTempo(dAtA, i, uint64(len(m.Clusters[iNdEx])))
+			i--
+			dAtA[i] = 0x4a
+		}
+	}
 	if len(m.ServiceStats) > 0 {
 		for k := range m.ServiceStats {
 			v := m.ServiceStats[k]
@@ -5037,6 +5606,11 @@ func (m *SearchMetrics) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if m.SkippedBlocks != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.SkippedBlocks))
+		i--
+		dAtA[i] = 0x40
+	}
 	if m.InspectedSpans != 0 {
 		i = encodeVarintTempo(dAtA, i, uint64(m.InspectedSpans))
 		i--
@@ -5504,6 +6078,16 @@ func (m *SearchTagValuesResponse) MarshalToSizedBuffer(dAtA []byte) (int, error)
 	_ = i
 	var l int
 	_ = l
+	if m.Partial {
+		i--
+		if m.Partial {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x10
+	}
 	if len(m.TagValues) > 0 {
 		for iNdEx := len(m.TagValues) - 1; iNdEx >= 0; iNdEx-- {
 			i -= len(m.TagValues[iNdEx])
@@ -5573,6 +6157,16 @@ func (m *SearchTagValuesV2Response) MarshalToSizedBuffer(dAtA []byte) (int, erro
 	_ = i
 	var l int
 	_ = l
+	if m.Partial {
+		i--
+		if m.Partial {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x10
+	}
 	if len(m.TagValues) > 0 {
 		for iNdEx := len(m.TagValues) - 1; iNdEx >= 0; iNdEx-- {
 			{
@@ -5647,6 +6241,20 @@ func (m *PushResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if len(m.LimitDetails) > 0 {
+		for iNdEx := len(m.LimitDetails) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.LimitDetails[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintTempo(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x12
+		}
+	}
 	if len(m.ErrorsByTrace) > 0 {
 		dAtA10 := make([]byte, len(m.ErrorsByTrace)*10)
 		var j9 int
@@ -5668,6 +6276,56 @@ func (m *PushResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
+func (m *PushLimitDetails) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *PushLimitDetails) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *PushLimitDetails) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Measured != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.Measured))
+		i--
+		dAtA[i] = 0x28
+	}
+	if m.Effective != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.Effective))
+		i--
+		dAtA[i] = 0x20
+	}
+	if m.Configured != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.Configured))
+		i--
+		dAtA[i] = 0x18
+	}
+	if len(m.Limit) > 0 {
+		i -= len(m.Limit)
+		copy(dAtA[i:], m.Limit)
+		i = encodeVarintTempo(dAtA, i, uint64(len(m.Limit)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if m.Reason != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.Reason))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
 func (m *PushBytesRequest) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
@@ -5770,7 +6428,7 @@ func (m *PushSpansRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
-func (m *TraceBytes) Marshal() (dAtA []byte, err error) {
+func (m *PushTracesRequest) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -5780,19 +6438,101 @@ func (m *TraceBytes) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *TraceBytes) MarshalTo(dAtA []byte) (int, error) {
+func (m *PushTracesRequest) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *TraceBytes) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *PushTracesRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
-	if len(m.Traces) > 0 {
-		for iNdEx := len(m.Traces) - 1; iNdEx >= 0; iNdEx-- {
-			i -= len(m.Traces[iNdEx])
+	if len(m.Batches) > 0 {
+		for iNdEx := len(m.Batches) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Batches[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintTempo(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x12
+		}
+	}
+	if m.Id != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.Id))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *PushTracesResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *PushTracesResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *PushTracesResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.ErrorMessage) > 0 {
+		i -= len(m.ErrorMessage)
+		copy(dAtA[i:], m.ErrorMessage)
+		i = encodeVarintTempo(dAtA, i, uint64(len(m.ErrorMessage)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if m.ErrorCode != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.ErrorCode))
+		i--
+		dAtA[i] = 0x10
+	}
+	if m.Id != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.Id))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *TraceBytes) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *TraceBytes) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *TraceBytes) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Traces) > 0 {
+		for iNdEx := len(m.Traces) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Traces[iNdEx])
 			copy(dAtA[i:], m.Traces[iNdEx])
 			i = encodeVarintTempo(dAtA, i, uint64(len(m.Traces[iNdEx])))
 			i--
@@ -5963,6 +6703,16 @@ func (m *SpanMetricsResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if m.End != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.End))
+		i--
+		dAtA[i] = 0x30
+	}
+	if m.Start != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.Start))
+		i--
+		dAtA[i] = 0x28
+	}
 	if len(m.Metrics) > 0 {
 		for iNdEx := len(m.Metrics) - 1; iNdEx >= 0; iNdEx-- {
 			{
@@ -6151,6 +6901,18 @@ func (m *SpanMetricsSummary) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if m.ErrorRate != 0 {
+		i -= 8
+		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(m.ErrorRate))))
+		i--
+		dAtA[i] = 0x49
+	}
+	if m.Rate != 0 {
+		i -= 8
+		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(m.Rate))))
+		i--
+		dAtA[i] = 0x41
+	}
 	if m.P50 != 0 {
 		i = encodeVarintTempo(dAtA, i, uint64(m.P50))
 		i--
@@ -6764,6 +7526,15 @@ func (m *TraceByIDMetrics) Size() (n int) {
 	}
 	var l int
 	_ = l
+	if m.CompletedJobs != 0 {
+		n += 1 + sovTempo(uint64(m.CompletedJobs))
+	}
+	if m.TotalJobs != 0 {
+		n += 1 + sovTempo(uint64(m.TotalJobs))
+	}
+	if m.SkippedBlocks != 0 {
+		n += 1 + sovTempo(uint64(m.SkippedBlocks))
+	}
 	return n
 }
 
@@ -6944,6 +7715,12 @@ func (m *TraceSearchMetadata) Size() (n int) {
 			n += mapEntrySize + 1 + sovTempo(uint64(mapEntrySize))
 		}
 	}
+	if len(m.Clusters) > 0 {
+		for _, s := range m.Clusters {
+			l = len(s)
+			n += 1 + l + sovTempo(uint64(l))
+		}
+	}
 	return n
 }
 
@@ -7042,6 +7819,9 @@ func (m *SearchMetrics) Size() (n int) {
 	if m.InspectedSpans != 0 {
 		n += 1 + sovTempo(uint64(m.InspectedSpans))
 	}
+	if m.SkippedBlocks != 0 {
+		n += 1 + sovTempo(uint64(m.SkippedBlocks))
+	}
 	return n
 }
 
@@ -7254,6 +8034,9 @@ func (m *SearchTagValuesResponse) Size() (n int) {
 			n += 1 + l + sovTempo(uint64(l))
 		}
 	}
+	if m.Partial {
+		n += 2
+	}
 	return n
 }
 
@@ -7286,6 +8069,9 @@ func (m *SearchTagValuesV2Response) Size() (n int) {
 			n += 1 + l + sovTempo(uint64(l))
 		}
 	}
+	if m.Partial {
+		n += 2
+	}
 	return n
 }
 
@@ -7317,6 +8103,37 @@ func (m *PushResponse) Size() (n int) {
 		}
 		n += 1 + sovTempo(uint64(l)) + l
 	}
+	if len(m.LimitDetails) > 0 {
+		for _, e := range m.LimitDetails {
+			l = e.Size()
+			n += 1 + l + sovTempo(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *PushLimitDetails) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Reason != 0 {
+		n += 1 + sovTempo(uint64(m.Reason))
+	}
+	l = len(m.Limit)
+	if l > 0 {
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	if m.Configured != 0 {
+		n += 1 + sovTempo(uint64(m.Configured))
+	}
+	if m.Effective != 0 {
+		n += 1 + sovTempo(uint64(m.Effective))
+	}
+	if m.Measured != 0 {
+		n += 1 + sovTempo(uint64(m.Measured))
+	}
 	return n
 }
 
@@ -7362,6 +8179,43 @@ func (m *PushSpansRequest) Size() (n int) {
 	return n
 }
 
+func (m *PushTracesRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Id != 0 {
+		n += 1 + sovTempo(uint64(m.Id))
+	}
+	if len(m.Batches) > 0 {
+		for _, e := range m.Batches {
+			l = e.Size()
+			n += 1 + l + sovTempo(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *PushTracesResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Id != 0 {
+		n += 1 + sovTempo(uint64(m.Id))
+	}
+	if m.ErrorCode != 0 {
+		n += 1 + sovTempo(uint64(m.ErrorCode))
+	}
+	l = len(m.ErrorMessage)
+	if l > 0 {
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	return n
+}
+
 func (m *TraceBytes) Size() (n int) {
 	if m == nil {
 		return 0
@@ -7465,6 +8319,12 @@ func (m *SpanMetricsResponse) Size() (n int) {
 			n += 1 + l + sovTempo(uint64(l))
 		}
 	}
+	if m.Start != 0 {
+		n += 1 + sovTempo(uint64(m.Start))
+	}
+	if m.End != 0 {
+		n += 1 + sovTempo(uint64(m.End))
+	}
 	return n
 }
 
@@ -7554,6 +8414,12 @@ func (m *SpanMetricsSummary) Size() (n int) {
 	if m.P50 != 0 {
 		n += 1 + sovTempo(uint64(m.P50))
 	}
+	if m.Rate != 0 {
+		n += 9
+	}
+	if m.ErrorRate != 0 {
+		n += 9
+	}
 	return n
 }
 
@@ -8128,6 +8994,63 @@ func (m *TraceByIDMetrics) Unmarshal(dAtA []byte) error {
 			return fmt.Errorf("proto: TraceByIDMetrics: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CompletedJobs", wireType)
+			}
+			m.CompletedJobs = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.CompletedJobs |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TotalJobs", wireType)
+			}
+			m.TotalJobs = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.TotalJobs |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SkippedBlocks", wireType)
+			}
+			m.SkippedBlocks = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.SkippedBlocks |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipTempo(dAtA[iNdEx:])
@@ -9436,6 +10359,38 @@ func (m *TraceSearchMetadata) Unmarshal(dAtA []byte) error {
 			}
 			m.ServiceStats[mapkey] = mapvalue
 			iNdEx = postIndex
+		case 9:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Clusters", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Clusters = append(m.Clusters, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipTempo(dAtA[iNdEx:])
@@ -10030,16 +10985,35 @@ func (m *SearchMetrics) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
-		default:
-			iNdEx = preIndex
-			skippy, err := skipTempo(dAtA[iNdEx:])
-			if err != nil {
-				return err
-			}
-			if (skippy < 0) || (iNdEx+skippy) < 0 {
-				return ErrInvalidLengthTempo
+		case 8:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SkippedBlocks", wireType)
 			}
-			if (iNdEx + skippy) > l {
+			m.SkippedBlocks = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.SkippedBlocks |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTempo(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if (iNdEx + skippy) > l {
 				return io.ErrUnexpectedEOF
 			}
 			iNdEx += skippy
@@ -11388,6 +12362,26 @@ func (m *SearchTagValuesResponse) Unmarshal(dAtA []byte) error {
 			}
 			m.TagValues = append(m.TagValues, string(dAtA[iNdEx:postIndex]))
 			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Partial", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Partial = bool(v != 0)
 		default:
 			iNdEx = preIndex
 			skippy, err := skipTempo(dAtA[iNdEx:])
@@ -11586,6 +12580,26 @@ func (m *SearchTagValuesV2Response) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Partial", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Partial = bool(v != 0)
 		default:
 			iNdEx = preIndex
 			skippy, err := skipTempo(dAtA[iNdEx:])
@@ -11789,6 +12803,198 @@ func (m *PushResponse) Unmarshal(dAtA []byte) error {
 			} else {
 				return fmt.Errorf("proto: wrong wireType = %d for field ErrorsByTrace", wireType)
 			}
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field LimitDetails", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.LimitDetails = append(m.LimitDetails, &PushLimitDetails{})
+			if err := m.LimitDetails[len(m.LimitDetails)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTempo(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *PushLimitDetails) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTempo
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: PushLimitDetails: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: PushLimitDetails: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Reason", wireType)
+			}
+			m.Reason = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Reason |= PushErrorReason(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Limit", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Limit = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Configured", wireType)
+			}
+			m.Configured = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Configured |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Effective", wireType)
+			}
+			m.Effective = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Effective |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Measured", wireType)
+			}
+			m.Measured = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Measured |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipTempo(dAtA[iNdEx:])
@@ -12049,6 +13255,229 @@ func (m *PushSpansRequest) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
+func (m *PushTracesRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTempo
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: PushTracesRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: PushTracesRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Id", wireType)
+			}
+			m.Id = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Id |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Batches", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Batches = append(m.Batches, &v11.ResourceSpans{})
+			if err := m.Batches[len(m.Batches)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTempo(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *PushTracesResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTempo
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: PushTracesResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: PushTracesResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Id", wireType)
+			}
+			m.Id = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Id |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ErrorCode", wireType)
+			}
+			m.ErrorCode = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ErrorCode |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ErrorMessage", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ErrorMessage = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTempo(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
 func (m *TraceBytes) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
@@ -12678,6 +14107,44 @@ func (m *SpanMetricsResponse) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Start", wireType)
+			}
+			m.Start = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Start |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field End", wireType)
+			}
+			m.End = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.End |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipTempo(dAtA[iNdEx:])
@@ -13219,6 +14686,28 @@ func (m *SpanMetricsSummary) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
+		case 8:
+			if wireType != 1 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Rate", wireType)
+			}
+			var v uint64
+			if (iNdEx + 8) > l {
+				return io.ErrUnexpectedEOF
+			}
+			v = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+			iNdEx += 8
+			m.Rate = float64(math.Float64frombits(v))
+		case 9:
+			if wireType != 1 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ErrorRate", wireType)
+			}
+			var v uint64
+			if (iNdEx + 8) > l {
+				return io.ErrUnexpectedEOF
+			}
+			v = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+			iNdEx += 8
+			m.ErrorRate = float64(math.Float64frombits(v))
 		default:
 			iNdEx = preIndex
 			skippy, err := skipTempo(dAtA[iNdEx:])
@@ -0,0 +1,19 @@
+package tenantusage
+
+import (
+	"flag"
+	"time"
+
+	"github.com/grafana/tempo/pkg/util"
+)
+
+type Config struct {
+	Enabled bool            `yaml:"enabled"`
+	Windows []time.Duration `yaml:"windows"`
+}
+
+// RegisterFlagsAndApplyDefaults registers the flags.
+func (cfg *Config) RegisterFlagsAndApplyDefaults(prefix string, f *flag.FlagSet) {
+	f.BoolVar(&cfg.Enabled, util.PrefixConfig(prefix, "enabled"), false, "Enable the per-tenant usage metrics and the /status/usage endpoint.")
+	cfg.Windows = []time.Duration{time.Hour, 24 * time.Hour}
+}
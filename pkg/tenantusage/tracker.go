@@ -0,0 +1,303 @@
+package tenantusage
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/tempo/pkg/util"
+)
+
+// Usage is a kind of bytes accounted to a tenant
+type Usage string
+
+const (
+	// Ingested are the proto bytes of the spans received by the distributors
+	Ingested Usage = "ingested"
+	// Queried are the bytes inspected by the searches and metrics queries of the query-frontends
+	Queried Usage = "queried"
+
+	// the windows are summed from buckets of this size
+	bucketDuration = time.Minute
+)
+
+var usages = []Usage{Ingested, Queried}
+
+var (
+	metricUsageBytesDesc = prometheus.NewDesc(
+		"tempo_tenant_usage_bytes_total",
+		"Total number of bytes ingested or queried by the tenant.",
+		[]string{"tenant", "usage"},
+		nil,
+	)
+	metricUsageWindowBytesDesc = prometheus.NewDesc(
+		"tempo_tenant_usage_window_bytes",
+		"Number of bytes ingested or queried by the tenant within the window.",
+		[]string{"tenant", "usage", "window"},
+		nil,
+	)
+	metricStoredBytesDesc = prometheus.NewDesc(
+		"tempo_tenant_usage_stored_bytes",
+		"Size of the blocks of the tenant in the blocklist.",
+		[]string{"tenant"},
+		nil,
+	)
+)
+
+// StoredBytesFunc returns the size of the blocks stored per tenant
+type StoredBytesFunc func() map[string]uint64
+
+// Tracker accounts the bytes ingested and queried per tenant over the configured windows so they can be used for
+// chargeback. The methods of a nil Tracker do nothing.
+type Tracker struct {
+	windows []time.Duration
+	buckets int64
+	now     func() time.Time
+
+	mtx     sync.Mutex
+	tenants map[string]map[Usage]*series
+
+	storedMtx   sync.RWMutex
+	storedBytes StoredBytesFunc
+}
+
+var _ prometheus.Collector = (*Tracker)(nil)
+
+func NewTracker(cfg Config) (*Tracker, error) {
+	if len(cfg.Windows) == 0 {
+		return nil, errors.New("at least one window is required")
+	}
+
+	var longest time.Duration
+	for _, w := range cfg.Windows {
+		if w < bucketDuration || w%bucketDuration != 0 {
+			return nil, fmt.Errorf("window %s must be a multiple of %s", w, bucketDuration)
+		}
+		longest = max(longest, w)
+	}
+
+	return &Tracker{
+		windows: cfg.Windows,
+		buckets: int64(longest / bucketDuration),
+		now:     time.Now,
+		tenants: map[string]map[Usage]*series{},
+	}, nil
+}
+
+// Add accounts bytes of the usage to the tenant
+func (t *Tracker) Add(tenantID string, u Usage, bytes uint64) {
+	if t == nil || bytes == 0 {
+		return
+	}
+
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	tenant, ok := t.tenants[tenantID]
+	if !ok {
+		tenant = map[Usage]*series{}
+		t.tenants[tenantID] = tenant
+	}
+	s, ok := tenant[u]
+	if !ok {
+		s = &series{buckets: make([]uint64, t.buckets)}
+		tenant[u] = s
+	}
+	s.add(t.bucket(), bytes)
+}
+
+// SetStoredBytes sets the function that returns the stored bytes of the tenants
+func (t *Tracker) SetStoredBytes(f StoredBytesFunc) {
+	if t == nil {
+		return
+	}
+
+	t.storedMtx.Lock()
+	t.storedBytes = f
+	t.storedMtx.Unlock()
+}
+
+// TenantUsage are the bytes of a tenant. Ingested and queried bytes are keyed by window.
+type TenantUsage struct {
+	TenantID      string            `json:"tenantID"`
+	StoredBytes   *uint64           `json:"storedBytes,omitempty"`
+	IngestedBytes map[string]uint64 `json:"ingestedBytes,omitempty"`
+	QueriedBytes  map[string]uint64 `json:"queriedBytes,omitempty"`
+}
+
+type usageResponse struct {
+	Windows []string       `json:"windows"`
+	Tenants []*TenantUsage `json:"tenants"`
+}
+
+// Usage returns the usage of all the tenants sorted by ID
+func (t *Tracker) Usage() []*TenantUsage {
+	tenants := map[string]*TenantUsage{}
+	get := func(tenantID string) *TenantUsage {
+		u, ok := tenants[tenantID]
+		if !ok {
+			u = &TenantUsage{TenantID: tenantID}
+			tenants[tenantID] = u
+		}
+		return u
+	}
+
+	for tenantID, bytes := range t.stored() {
+		b := bytes
+		get(tenantID).StoredBytes = &b
+	}
+
+	t.mtx.Lock()
+	bucket := t.bucket()
+	for tenantID, tenant := range t.tenants {
+		u := get(tenantID)
+		for usage, s := range tenant {
+			windows := make(map[string]uint64, len(t.windows))
+			for _, w := range t.windows {
+				windows[windowLabel(w)] = s.sum(bucket, int64(w/bucketDuration))
+			}
+			switch usage {
+			case Ingested:
+				u.IngestedBytes = windows
+			case Queried:
+				u.QueriedBytes = windows
+			}
+		}
+	}
+	t.mtx.Unlock()
+
+	usage := make([]*TenantUsage, 0, len(tenants))
+	for _, u := range tenants {
+		usage = append(usage, u)
+	}
+	sort.Slice(usage, func(i, j int) bool { return usage[i].TenantID < usage[j].TenantID })
+	return usage
+}
+
+// Handler returns the usage of all the tenants, or of the tenant in the tenant query parameter, as JSON
+func (t *Tracker) Handler(w http.ResponseWriter, r *http.Request) {
+	resp := &usageResponse{
+		Windows: make([]string, 0, len(t.windows)),
+		Tenants: t.Usage(),
+	}
+	for _, w := range t.windows {
+		resp.Windows = append(resp.Windows, windowLabel(w))
+	}
+
+	if tenantID := r.URL.Query().Get("tenant"); tenantID != "" {
+		tenants := make([]*TenantUsage, 0, 1)
+		for _, u := range resp.Tenants {
+			if u.TenantID == tenantID {
+				tenants = append(tenants, u)
+			}
+		}
+		resp.Tenants = tenants
+	}
+
+	util.WriteJSONResponse(w, resp)
+}
+
+// Describe implements prometheus.Collector
+func (t *Tracker) Describe(ch chan<- *prometheus.Desc) {
+	ch <- metricUsageBytesDesc
+	ch <- metricUsageWindowBytesDesc
+	ch <- metricStoredBytesDesc
+}
+
+// Collect implements prometheus.Collector. Tenants without usage in the longest window are removed.
+func (t *Tracker) Collect(ch chan<- prometheus.Metric) {
+	for tenantID, bytes := range t.stored() {
+		ch <- prometheus.MustNewConstMetric(metricStoredBytesDesc, prometheus.GaugeValue, float64(bytes), tenantID)
+	}
+
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	bucket := t.bucket()
+	for tenantID, tenant := range t.tenants {
+		for _, usage := range usages {
+			s, ok := tenant[usage]
+			if !ok {
+				continue
+			}
+			if s.sum(bucket, t.buckets) == 0 {
+				delete(tenant, usage)
+				continue
+			}
+
+			ch <- prometheus.MustNewConstMetric(metricUsageBytesDesc, prometheus.CounterValue, float64(s.total), tenantID, string(usage))
+			for _, w := range t.windows {
+				ch <- prometheus.MustNewConstMetric(metricUsageWindowBytesDesc, prometheus.GaugeValue, float64(s.sum(bucket, int64(w/bucketDuration))), tenantID, string(usage), windowLabel(w))
+			}
+		}
+		if len(tenant) == 0 {
+			delete(t.tenants, tenantID)
+		}
+	}
+}
+
+func (t *Tracker) stored() map[string]uint64 {
+	if t == nil {
+		return nil
+	}
+
+	t.storedMtx.RLock()
+	f := t.storedBytes
+	t.storedMtx.RUnlock()
+
+	if f == nil {
+		return nil
+	}
+	return f()
+}
+
+func (t *Tracker) bucket() int64 {
+	return t.now().UnixNano() / int64(bucketDuration)
+}
+
+func windowLabel(w time.Duration) string {
+	return model.Duration(w).String()
+}
+
+// series are the bytes of a usage of a tenant in a ring of buckets
+type series struct {
+	total   uint64
+	buckets []uint64
+	last    int64
+}
+
+func (s *series) add(bucket int64, bytes uint64) {
+	s.advance(bucket)
+	s.buckets[bucket%int64(len(s.buckets))] += bytes
+	s.total += bytes
+}
+
+// sum returns the bytes of the last n buckets up to bucket
+func (s *series) sum(bucket, n int64) uint64 {
+	s.advance(bucket)
+
+	var sum uint64
+	for i := int64(0); i < n; i++ {
+		sum += s.buckets[(bucket-i)%int64(len(s.buckets))]
+	}
+	return sum
+}
+
+// advance clears the buckets that were passed since the last one
+func (s *series) advance(bucket int64) {
+	if bucket <= s.last {
+		return
+	}
+
+	n := int64(len(s.buckets))
+	for b := max(s.last+1, bucket-n+1); b <= bucket; b++ {
+		s.buckets[b%n] = 0
+	}
+	s.last = bucket
+}
@@ -0,0 +1,106 @@
+package tenantusage
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewTracker(t *testing.T) {
+	_, err := NewTracker(Config{})
+	require.EqualError(t, err, "at least one window is required")
+
+	_, err = NewTracker(Config{Windows: []time.Duration{90 * time.Second}})
+	require.EqualError(t, err, "window 1m30s must be a multiple of 1m0s")
+
+	_, err = NewTracker(Config{Windows: []time.Duration{time.Hour, 24 * time.Hour}})
+	require.NoError(t, err)
+}
+
+func TestTracker(t *testing.T) {
+	tracker, err := NewTracker(Config{Windows: []time.Duration{time.Minute, time.Hour}})
+	require.NoError(t, err)
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	tracker.now = func() time.Time { return now }
+
+	tracker.Add("a", Ingested, 10)
+	tracker.Add("a", Queried, 100)
+	now = now.Add(30 * time.Minute)
+	tracker.Add("a", Ingested, 20)
+	tracker.Add("b", Ingested, 5)
+	tracker.SetStoredBytes(func() map[string]uint64 { return map[string]uint64{"c": 1000} })
+
+	require.Equal(t, []*TenantUsage{
+		{TenantID: "a", IngestedBytes: map[string]uint64{"1m": 20, "1h": 30}, QueriedBytes: map[string]uint64{"1m": 0, "1h": 100}},
+		{TenantID: "b", IngestedBytes: map[string]uint64{"1m": 5, "1h": 5}},
+		{TenantID: "c", StoredBytes: uint64Ptr(1000)},
+	}, tracker.Usage())
+
+	// the bytes leave the windows
+	now = now.Add(45 * time.Minute)
+	tracker.Add("b", Ingested, 1)
+	require.Equal(t, []*TenantUsage{
+		{TenantID: "a", IngestedBytes: map[string]uint64{"1m": 0, "1h": 20}, QueriedBytes: map[string]uint64{"1m": 0, "1h": 0}},
+		{TenantID: "b", IngestedBytes: map[string]uint64{"1m": 1, "1h": 6}},
+		{TenantID: "c", StoredBytes: uint64Ptr(1000)},
+	}, tracker.Usage())
+
+	reg := prometheus.NewPedanticRegistry()
+	require.NoError(t, reg.Register(tracker))
+	require.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(`
+# HELP tempo_tenant_usage_bytes_total Total number of bytes ingested or queried by the tenant.
+# TYPE tempo_tenant_usage_bytes_total counter
+tempo_tenant_usage_bytes_total{tenant="a",usage="ingested"} 30
+tempo_tenant_usage_bytes_total{tenant="b",usage="ingested"} 6
+# HELP tempo_tenant_usage_stored_bytes Size of the blocks of the tenant in the blocklist.
+# TYPE tempo_tenant_usage_stored_bytes gauge
+tempo_tenant_usage_stored_bytes{tenant="c"} 1000
+# HELP tempo_tenant_usage_window_bytes Number of bytes ingested or queried by the tenant within the window.
+# TYPE tempo_tenant_usage_window_bytes gauge
+tempo_tenant_usage_window_bytes{tenant="a",usage="ingested",window="1h"} 20
+tempo_tenant_usage_window_bytes{tenant="a",usage="ingested",window="1m"} 0
+tempo_tenant_usage_window_bytes{tenant="b",usage="ingested",window="1h"} 6
+tempo_tenant_usage_window_bytes{tenant="b",usage="ingested",window="1m"} 1
+`)))
+
+	// the queried bytes of a were removed once they left the longest window
+	require.Nil(t, tracker.Usage()[0].QueriedBytes)
+
+	// a nil tracker does nothing
+	var nilTracker *Tracker
+	nilTracker.Add("a", Ingested, 10)
+	nilTracker.SetStoredBytes(nil)
+}
+
+func TestTrackerHandler(t *testing.T) {
+	tracker, err := NewTracker(Config{Windows: []time.Duration{time.Hour, 24 * time.Hour}})
+	require.NoError(t, err)
+	tracker.Add("a", Ingested, 10)
+	tracker.Add("b", Queried, 20)
+
+	get := func(target string) string {
+		rec := httptest.NewRecorder()
+		tracker.Handler(rec, httptest.NewRequest(http.MethodGet, target, nil))
+		require.Equal(t, http.StatusOK, rec.Code)
+		return rec.Body.String()
+	}
+
+	require.JSONEq(t, `{"windows":["1h","1d"],"tenants":[
+		{"tenantID":"a","ingestedBytes":{"1h":10,"1d":10}},
+		{"tenantID":"b","queriedBytes":{"1h":20,"1d":20}}
+	]}`, get("/status/usage"))
+	require.JSONEq(t, `{"windows":["1h","1d"],"tenants":[
+		{"tenantID":"b","queriedBytes":{"1h":20,"1d":20}}
+	]}`, get("/status/usage?tenant=b"))
+}
+
+func uint64Ptr(v uint64) *uint64 {
+	return &v
+}
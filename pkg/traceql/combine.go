@@ -1,6 +1,7 @@
 package traceql
 
 import (
+	"slices"
 	"sort"
 	"strings"
 
@@ -90,6 +91,13 @@ func combineSearchResults(existing *tempopb.TraceSearchMetadata, incoming *tempo
 		existingStats.ErrorCount = max(existingStats.ErrorCount, incomingStats.ErrorCount)
 	}
 
+	// a trace may be found in more than one cluster in a federated query
+	for _, cluster := range incoming.Clusters {
+		if !slices.Contains(existing.Clusters, cluster) {
+			existing.Clusters = append(existing.Clusters, cluster)
+		}
+	}
+
 	// make a map of existing Spansets
 	existingSS := make(map[string]*tempopb.SpanSet)
 	for _, ss := range existing.SpanSets {
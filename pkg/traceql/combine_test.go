@@ -38,6 +38,24 @@ func TestCombineResults(t *testing.T) {
 				SpanSets:          []*tempopb.SpanSet{},
 			},
 		},
+		{
+			name: "combine clusters",
+			existing: &tempopb.TraceSearchMetadata{
+				TraceID:  "trace-1",
+				SpanSets: []*tempopb.SpanSet{},
+				Clusters: []string{"eu", "us"},
+			},
+			new: &tempopb.TraceSearchMetadata{
+				TraceID:  "trace-1",
+				SpanSets: []*tempopb.SpanSet{},
+				Clusters: []string{"us", "ap"},
+			},
+			expected: &tempopb.TraceSearchMetadata{
+				TraceID:  "trace-1",
+				SpanSets: []*tempopb.SpanSet{},
+				Clusters: []string{"eu", "us", "ap"},
+			},
+		},
 		{
 			name: "mixed copying in fields",
 			existing: &tempopb.TraceSearchMetadata{
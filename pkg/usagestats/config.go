@@ -9,6 +9,7 @@ import (
 
 type Config struct {
 	Enabled bool           `yaml:"reporting_enabled"`
+	URL     string         `yaml:"url"`
 	Leader  bool           `yaml:"-"`
 	Backoff backoff.Config `yaml:"backoff"`
 }
@@ -16,6 +17,7 @@ type Config struct {
 // RegisterFlags adds the flags required to config this to the given FlagSet
 func (cfg *Config) RegisterFlagsAndApplyDefaults(prefix string, f *flag.FlagSet) {
 	f.BoolVar(&cfg.Enabled, util.PrefixConfig(prefix, "enabled"), true, "Enable anonymous usage reporting.")
+	f.StringVar(&cfg.URL, util.PrefixConfig(prefix, "url"), usageStatsURL, "URL the anonymous usage reports are sent to.")
 	cfg.Backoff.RegisterFlagsWithPrefix(prefix, f)
 	cfg.Backoff.MaxRetries = 0
 }
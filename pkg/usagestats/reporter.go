@@ -56,6 +56,9 @@ func NewReporter(config Config, kvConfig kv.Config, reader backend.RawReader, wr
 	if !config.Enabled {
 		return nil, nil
 	}
+	if config.URL == "" {
+		config.URL = usageStatsURL
+	}
 	r := &Reporter{
 		logger:   logger,
 		reader:   reader,
@@ -279,7 +282,7 @@ func (rep *Reporter) reportUsage(ctx context.Context, interval time.Time) error
 	})
 	var errs multierror.MultiError
 	for backoff.Ongoing() {
-		if err := sendReport(ctx, rep.cluster, interval); err != nil {
+		if err := sendReport(ctx, rep.conf.URL, rep.cluster, interval); err != nil {
 			level.Info(rep.logger).Log("msg", "failed to send usage report", "retries", backoff.NumRetries(), "err", err)
 			errs.Add(err)
 			backoff.Wait()
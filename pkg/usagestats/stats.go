@@ -45,13 +45,13 @@ type Report struct {
 }
 
 // sendReport sends the report to the stats server
-func sendReport(ctx context.Context, seed *ClusterSeed, interval time.Time) error {
+func sendReport(ctx context.Context, url string, seed *ClusterSeed, interval time.Time) error {
 	report := buildReport(seed, interval)
 	out, err := jsoniter.MarshalIndent(report, "", " ")
 	if err != nil {
 		return err
 	}
-	req, err := http.NewRequest(http.MethodPost, usageStatsURL, bytes.NewBuffer(out))
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewBuffer(out))
 	if err != nil {
 		return err
 	}
@@ -161,6 +161,19 @@ func memstats() interface{} {
 	}
 }
 
+// ScaleBucket returns the order of magnitude of n so the scale of a deployment can be reported without its exact size,
+// e.g. "0", "1-10", "11-100", "101-1000"
+func ScaleBucket(n int) string {
+	if n <= 0 {
+		return "0"
+	}
+	lower, upper := 1, 10
+	for n > upper {
+		lower, upper = upper+1, upper*10
+	}
+	return fmt.Sprintf("%d-%d", lower, upper)
+}
+
 // NewFloat returns a new Float stats object.
 // If a Float stats object with the same name already exists it is returned.
 func NewFloat(name string) *expvar.Float {
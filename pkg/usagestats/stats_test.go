@@ -161,6 +161,15 @@ func TestWordCounter(t *testing.T) {
 	require.Equal(t, int64(2), w.Value())
 }
 
+func TestScaleBucket(t *testing.T) {
+	require.Equal(t, "0", ScaleBucket(0))
+	require.Equal(t, "1-10", ScaleBucket(1))
+	require.Equal(t, "1-10", ScaleBucket(10))
+	require.Equal(t, "11-100", ScaleBucket(11))
+	require.Equal(t, "101-1000", ScaleBucket(1000))
+	require.Equal(t, "10001-100000", ScaleBucket(12345))
+}
+
 func TestPanics(t *testing.T) {
 	require.Panics(t, func() {
 		NewStatistics("panicstats")
@@ -13,16 +13,23 @@ import (
 // Prefer accepting a non-global logger as an argument.
 var Logger = kitlog.NewNopLogger()
 
+var (
+	// baseLogger is the unfiltered logger. levelLogger applies the current level on top of it
+	baseLogger  kitlog.Logger = kitlog.NewNopLogger()
+	levelLogger               = &kitlog.SwapLogger{}
+)
+
 // InitLogger initialises the global gokit logger and overrides the
 // default logger for the server.
 func InitLogger(cfg *server.Config) {
-	logger := kitlog.NewLogfmtLogger(kitlog.NewSyncWriter(os.Stderr))
+	baseLogger = kitlog.NewLogfmtLogger(kitlog.NewSyncWriter(os.Stderr))
 	if cfg.LogFormat == "json" {
-		logger = kitlog.NewJSONLogger(kitlog.NewSyncWriter(os.Stderr))
+		baseLogger = kitlog.NewJSONLogger(kitlog.NewSyncWriter(os.Stderr))
 	}
 
-	// add support for level based logging
-	logger = level.NewFilter(logger, LevelFilter(cfg.LogLevel.String()))
+	// add support for level based logging. the level can be changed later with SetLevel
+	SetLevel(cfg.LogLevel.String())
+	var logger kitlog.Logger = levelLogger
 
 	// use UTC timestamps
 	logger = kitlog.With(logger, "ts", kitlog.DefaultTimestampUTC)
@@ -37,6 +44,11 @@ func InitLogger(cfg *server.Config) {
 	cfg.Log = kitlog.With(logger, "caller", kitlog.Caller(4))
 }
 
+// SetLevel changes the level of the global logger and the server logger created by InitLogger
+func SetLevel(l string) {
+	levelLogger.Swap(level.NewFilter(baseLogger, LevelFilter(l)))
+}
+
 // TODO: remove once weaveworks/common updates to go-kit/log
 // -> we can then revert to using Level.Gokit
 func LevelFilter(l string) level.Option {
@@ -0,0 +1,87 @@
+package tempodb
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/grafana/tempo/pkg/util"
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/encoding/common"
+)
+
+// AnnotationsKeyPath is the folder of the tenant that holds the annotations of its traces. One object is stored per
+// annotated trace.
+const AnnotationsKeyPath = "annotations"
+
+// Annotation is a note attached to a trace. i.e. "this is the offending request"
+type Annotation struct {
+	Text      string    `json:"text"`
+	Author    string    `json:"author,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type traceAnnotations struct {
+	Annotations []*Annotation `json:"annotations"`
+}
+
+func annotationsObjectName(traceID common.ID) string {
+	return util.TraceIDToHexString(traceID) + ".json"
+}
+
+// TraceAnnotations returns the annotations of the trace in the order they were added
+func (rw *readerWriter) TraceAnnotations(ctx context.Context, tenantID string, traceID common.ID) ([]*Annotation, error) {
+	annotations, err := rw.readTraceAnnotations(ctx, tenantID, traceID)
+	if err != nil {
+		return nil, err
+	}
+	return annotations.Annotations, nil
+}
+
+// AddTraceAnnotation appends the annotation to the annotations of the trace. Annotations added concurrently through
+// different instances can overwrite each other.
+func (rw *readerWriter) AddTraceAnnotation(ctx context.Context, tenantID string, traceID common.ID, a *Annotation) error {
+	rw.annotationsMtx.Lock()
+	defer rw.annotationsMtx.Unlock()
+
+	annotations, err := rw.readTraceAnnotations(ctx, tenantID, traceID)
+	if err != nil {
+		return err
+	}
+	annotations.Annotations = append(annotations.Annotations, a)
+
+	buff, err := json.Marshal(annotations)
+	if err != nil {
+		return fmt.Errorf("error marshalling trace annotations: %w", err)
+	}
+
+	return rw.rawW.Write(ctx, annotationsObjectName(traceID), backend.KeyPath{tenantID, AnnotationsKeyPath}, bytes.NewReader(buff), int64(len(buff)), nil)
+}
+
+func (rw *readerWriter) readTraceAnnotations(ctx context.Context, tenantID string, traceID common.ID) (*traceAnnotations, error) {
+	annotations := &traceAnnotations{}
+
+	reader, _, err := rw.rawR.Read(ctx, annotationsObjectName(traceID), backend.KeyPath{tenantID, AnnotationsKeyPath}, nil)
+	if errors.Is(err, backend.ErrDoesNotExist) {
+		return annotations, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading trace annotations: %w", err)
+	}
+	defer reader.Close()
+
+	buff, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("error reading trace annotations: %w", err)
+	}
+
+	err = json.Unmarshal(buff, annotations)
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshalling trace annotations: %w", err)
+	}
+	return annotations, nil
+}
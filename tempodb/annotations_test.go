@@ -0,0 +1,47 @@
+package tempodb
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/pkg/util/test"
+	"github.com/grafana/tempo/tempodb/backend"
+)
+
+func TestTraceAnnotations(t *testing.T) {
+	r, w, _, _ := testConfig(t, backend.EncGZIP, time.Minute)
+
+	ctx := context.Background()
+	traceID := test.ValidTraceID(nil)
+
+	annotations, err := r.TraceAnnotations(ctx, testTenantID, traceID)
+	require.NoError(t, err)
+	require.Empty(t, annotations)
+
+	first := &Annotation{Text: "first", Author: "alice", CreatedAt: time.Unix(1, 0).UTC()}
+	second := &Annotation{Text: "second", CreatedAt: time.Unix(2, 0).UTC()}
+	require.NoError(t, w.AddTraceAnnotation(ctx, testTenantID, traceID, first))
+	require.NoError(t, w.AddTraceAnnotation(ctx, testTenantID, traceID, second))
+
+	annotations, err = r.TraceAnnotations(ctx, testTenantID, traceID)
+	require.NoError(t, err)
+	require.Equal(t, []*Annotation{first, second}, annotations)
+
+	// annotations are per tenant and trace
+	annotations, err = r.TraceAnnotations(ctx, testTenantID2, traceID)
+	require.NoError(t, err)
+	require.Empty(t, annotations)
+
+	annotations, err = r.TraceAnnotations(ctx, testTenantID, test.ValidTraceID(nil))
+	require.NoError(t, err)
+	require.Empty(t, annotations)
+
+	// the annotations aren't listed as blocks
+	r.EnablePolling(ctx, &mockJobSharder{})
+	rw := r.(*readerWriter)
+	rw.pollBlocklist()
+	require.Empty(t, rw.blocklist.Metas(testTenantID))
+}
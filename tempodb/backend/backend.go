@@ -15,6 +15,7 @@ const (
 	GCS   = "gcs"
 	S3    = "s3"
 	Azure = "azure"
+	Swift = "swift"
 )
 
 var (
@@ -22,6 +23,9 @@ var (
 	ErrEmptyTenantID = fmt.Errorf("empty tenant id")
 	ErrEmptyBlockID  = fmt.Errorf("empty block id")
 	ErrBadSeedFile   = fmt.Errorf("bad seed file")
+	// ErrObjectLocked is returned when deleting objects of a bucket that doesn't allow it. They are removed by the
+	// lifecycle of the bucket.
+	ErrObjectLocked = fmt.Errorf("object is locked")
 
 	GlobalMaxBlockID = uuid.MustParse("ffffffff-ffff-ffff-ffff-ffffffffffff")
 )
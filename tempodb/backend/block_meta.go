@@ -2,9 +2,12 @@ package backend
 
 import (
 	"bytes"
+	"encoding/binary"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
+	"math/bits"
 	"time"
 
 	"github.com/cespare/xxhash/v2"
@@ -92,6 +95,8 @@ type CompactedBlockMeta struct {
 const (
 	DefaultReplicationFactor          = 0 // Replication factor for blocks from the ingester. This is the default value to indicate RF3.
 	MetricsGeneratorReplicationFactor = 1
+
+	durationHistogramBuckets = 32 // the last bucket starts at 2^30ms, about 12 days
 )
 
 // The BlockMeta data that is stored for each individual block.
@@ -134,6 +139,25 @@ type BlockMeta struct {
 	// ReplicationFactor is the number of times the data written in this block has been replicated.
 	// It's left unset if replication factor is 3. Default is 0 (RF3).
 	ReplicationFactor uint32 `json:"replicationFactor,omitempty"`
+	// Downsampled is set if the block was rewritten to keep only a reduced copy of its traces.
+	// Downsampled blocks are not compacted or downsampled again.
+	Downsampled bool `json:"downsampled,omitempty"`
+	// ColdTier is set if the block was moved to the cold tier of the backend. Blocks in the cold tier are not
+	// compacted.
+	ColdTier bool `json:"coldTier,omitempty"`
+	// Retention is the longest retention requested by a trace in this block with the tempo.retention attribute.
+	// Blocks are only compacted with blocks of the same retention. 0 uses the retention of the tenant.
+	Retention time.Duration `json:"retention,omitempty"`
+	// Fingerprint identifies the trace ids and the contents of the traces in this block. The blocks flushed by the
+	// ingesters that received the same replicated traces have the same fingerprint.
+	Fingerprint uint64 `json:"fingerprint,omitempty"`
+	// DurationHistogram counts the traces in this block by duration. Bucket 0 counts the traces shorter than 1ms and
+	// bucket i the traces between 2^(i-1)ms and 2^i ms. The last bucket also counts all longer traces. It is used to
+	// skip the block in searches with a min or max duration.
+	DurationHistogram []uint32 `json:"durationHistogram,omitempty"`
+	// DurationHistogramInvalid is set if the duration of a trace in this block is not known. The block is searched
+	// for any duration.
+	DurationHistogramInvalid bool `json:"durationHistogramInvalid,omitempty"`
 }
 
 // DedicatedColumn contains the configuration for a single attribute with the given name that should
@@ -225,6 +249,76 @@ func (b *BlockMeta) ObjectAdded(id []byte, start, end uint32) {
 	b.TotalObjects++
 }
 
+// FingerprintAdded adds a trace to the fingerprint of the block. contentHash is a hash of the content of the trace so
+// blocks with the same trace ids but different spans have different fingerprints.
+func (b *BlockMeta) FingerprintAdded(id []byte, contentHash uint64) {
+	d := xxhash.New()
+	_, _ = d.Write(id)
+	_, _ = d.Write(binary.BigEndian.AppendUint64(nil, contentHash))
+
+	// sum the hashes so the fingerprint doesn't depend on the order the traces were added
+	b.Fingerprint += d.Sum64()
+}
+
+// DurationAdded adds the duration of a trace to the duration histogram
+func (b *BlockMeta) DurationAdded(d time.Duration) {
+	if b.DurationHistogramInvalid {
+		return
+	}
+	i := durationHistogramBucket(d)
+	for len(b.DurationHistogram) <= i {
+		b.DurationHistogram = append(b.DurationHistogram, 0)
+	}
+	b.DurationHistogram[i]++
+}
+
+// DurationUnknown invalidates the duration histogram when the duration of a trace is not known
+func (b *BlockMeta) DurationUnknown() {
+	b.DurationHistogramInvalid = true
+	b.DurationHistogram = nil
+}
+
+// MayContainDuration returns false if the block doesn't have a trace with a duration between minDuration and
+// maxDuration. A maxDuration of 0 is unlimited. Blocks without a valid duration histogram may contain traces of any
+// duration.
+func (b *BlockMeta) MayContainDuration(minDuration, maxDuration time.Duration) bool {
+	if len(b.DurationHistogram) == 0 || b.DurationHistogramInvalid {
+		return true
+	}
+	if maxDuration == 0 {
+		maxDuration = math.MaxInt64
+	}
+
+	for i, count := range b.DurationHistogram {
+		if count == 0 {
+			continue
+		}
+		lower, upper := durationHistogramBucketBounds(i)
+		if lower <= maxDuration && upper > minDuration {
+			return true
+		}
+	}
+	return false
+}
+
+func durationHistogramBucket(d time.Duration) int {
+	if d < 0 {
+		return 0
+	}
+	return min(bits.Len64(uint64(d/time.Millisecond)), durationHistogramBuckets-1)
+}
+
+// durationHistogramBucketBounds returns the inclusive lower and exclusive upper bound of the bucket
+func durationHistogramBucketBounds(i int) (lower, upper time.Duration) {
+	if i > 0 {
+		lower = time.Duration(1<<(i-1)) * time.Millisecond
+	}
+	if i >= durationHistogramBuckets-1 {
+		return lower, math.MaxInt64
+	}
+	return lower, time.Duration(1<<i) * time.Millisecond
+}
+
 func (b *BlockMeta) DedicatedColumnsHash() uint64 {
 	return b.DedicatedColumns.Hash()
 }
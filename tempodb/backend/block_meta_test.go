@@ -99,6 +99,74 @@ func TestBlockMetaObjectAdded(t *testing.T) {
 	}
 }
 
+func TestBlockMetaFingerprint(t *testing.T) {
+	ids := [][]byte{{0x01}, {0x02}, {0x03}}
+
+	a := &BlockMeta{}
+	for _, id := range ids {
+		a.FingerprintAdded(id, 1)
+	}
+
+	// the order of the traces doesn't matter
+	b := &BlockMeta{}
+	for i := len(ids) - 1; i >= 0; i-- {
+		b.FingerprintAdded(ids[i], 1)
+	}
+	assert.NotZero(t, a.Fingerprint)
+	assert.Equal(t, a.Fingerprint, b.Fingerprint)
+
+	c := &BlockMeta{}
+	for _, id := range ids[:2] {
+		c.FingerprintAdded(id, 1)
+	}
+	assert.NotEqual(t, a.Fingerprint, c.Fingerprint)
+
+	// the same trace ids with different contents
+	d := &BlockMeta{}
+	for i, id := range ids {
+		d.FingerprintAdded(id, uint64(i))
+	}
+	assert.NotEqual(t, a.Fingerprint, d.Fingerprint)
+}
+
+func TestBlockMetaDurationHistogram(t *testing.T) {
+	b := &BlockMeta{}
+	assert.True(t, b.MayContainDuration(time.Hour, 0))
+
+	b.DurationAdded(500 * time.Microsecond)
+	b.DurationAdded(3 * time.Millisecond)
+	b.DurationAdded(3 * time.Millisecond)
+	b.DurationAdded(100 * time.Millisecond)
+	assert.Equal(t, []uint32{1, 0, 2, 0, 0, 0, 0, 1}, b.DurationHistogram)
+
+	tcs := []struct {
+		min, max time.Duration
+		expected bool
+	}{
+		{min: 0, max: 0, expected: true},
+		{min: 0, max: time.Millisecond, expected: true},
+		{min: 5 * time.Millisecond, max: 50 * time.Millisecond, expected: false},
+		{min: 5 * time.Millisecond, max: 64 * time.Millisecond, expected: true},
+		{min: 127 * time.Millisecond, max: 0, expected: true},
+		{min: 128 * time.Millisecond, max: 0, expected: false},
+		{min: time.Hour, max: 2 * time.Hour, expected: false},
+	}
+	for _, tc := range tcs {
+		assert.Equal(t, tc.expected, b.MayContainDuration(tc.min, tc.max), "min: %s max: %s", tc.min, tc.max)
+	}
+
+	// the last bucket counts all longer traces
+	b.DurationAdded(365 * 24 * time.Hour)
+	assert.Len(t, b.DurationHistogram, durationHistogramBuckets)
+	assert.True(t, b.MayContainDuration(100*24*time.Hour, 0))
+
+	// a trace without a duration invalidates the histogram
+	b.DurationUnknown()
+	b.DurationAdded(time.Millisecond)
+	assert.Nil(t, b.DurationHistogram)
+	assert.True(t, b.MayContainDuration(time.Hour, 2*time.Hour))
+}
+
 func TestBlockMetaParsing(t *testing.T) {
 	timeParse := func(s string) time.Time {
 		date, err := time.Parse(time.RFC3339Nano, s)
@@ -0,0 +1,68 @@
+package encryption
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+
+	"github.com/grafana/tempo/pkg/util"
+)
+
+const (
+	KeyProviderFile   = "file"
+	KeyProviderAWSKMS = "aws_kms"
+)
+
+type Config struct {
+	Enabled bool `yaml:"enabled"`
+	// ReadUnencryptedObjects reads the objects that are not encrypted, such as the blocks written before the encryption
+	// was enabled. Otherwise they fail to read.
+	ReadUnencryptedObjects bool `yaml:"read_unencrypted_objects"`
+	// KeyProvider wraps the data keys of the tenants: file or aws_kms
+	KeyProvider string       `yaml:"key_provider"`
+	File        FileConfig   `yaml:"file"`
+	AWSKMS      AWSKMSConfig `yaml:"aws_kms"`
+}
+
+// FileConfig wraps the data keys with a 32 byte master key read from a file. The file contains the key encoded in
+// hex.
+type FileConfig struct {
+	KeyFile string `yaml:"key_file"`
+}
+
+// AWSKMSConfig wraps the data keys with a key of AWS KMS. The credentials are read from the default credential chain.
+type AWSKMSConfig struct {
+	KeyID    string `yaml:"key_id"`
+	Region   string `yaml:"region"`
+	Endpoint string `yaml:"endpoint"`
+}
+
+func (cfg *Config) RegisterFlagsAndApplyDefaults(prefix string, f *flag.FlagSet) {
+	f.BoolVar(&cfg.Enabled, util.PrefixConfig(prefix, "encryption.enabled"), false, "Encrypt the objects of the blocks before they are written to the backend.")
+	f.BoolVar(&cfg.ReadUnencryptedObjects, util.PrefixConfig(prefix, "encryption.read-unencrypted-objects"), false, "Read the objects of the blocks that are not encrypted, such as the blocks written before the encryption was enabled.")
+	f.StringVar(&cfg.KeyProvider, util.PrefixConfig(prefix, "encryption.key-provider"), KeyProviderFile, "Provider of the keys that wrap the data keys of the tenants: file or aws_kms.")
+	f.StringVar(&cfg.File.KeyFile, util.PrefixConfig(prefix, "encryption.file.key-file"), "", "File with the hex encoded 32 byte master key.")
+	f.StringVar(&cfg.AWSKMS.KeyID, util.PrefixConfig(prefix, "encryption.aws-kms.key-id"), "", "ID, ARN or alias of the AWS KMS key.")
+	f.StringVar(&cfg.AWSKMS.Region, util.PrefixConfig(prefix, "encryption.aws-kms.region"), "", "Region of AWS KMS. Defaults to the region of the environment.")
+	f.StringVar(&cfg.AWSKMS.Endpoint, util.PrefixConfig(prefix, "encryption.aws-kms.endpoint"), "", "Endpoint of AWS KMS. Defaults to the public endpoint of the region.")
+}
+
+func (cfg *Config) Validate() error {
+	if !cfg.Enabled {
+		return nil
+	}
+
+	switch cfg.KeyProvider {
+	case KeyProviderFile:
+		if cfg.File.KeyFile == "" {
+			return errors.New("file.key_file is required")
+		}
+	case KeyProviderAWSKMS:
+		if cfg.AWSKMS.KeyID == "" {
+			return errors.New("aws_kms.key_id is required")
+		}
+	default:
+		return fmt.Errorf("unknown key_provider %s, must be %s or %s", cfg.KeyProvider, KeyProviderFile, KeyProviderAWSKMS)
+	}
+	return nil
+}
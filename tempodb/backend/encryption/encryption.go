@@ -0,0 +1,603 @@
+package encryption
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"sync"
+
+	"github.com/google/uuid"
+	lru "github.com/hashicorp/golang-lru/v2"
+
+	"github.com/grafana/tempo/tempodb/backend"
+)
+
+const (
+	dataKeySize = 32
+
+	headerVersion = 1
+	// headerPrefixSize is the size of the magic, the version and the length of the wrapped key
+	headerPrefixSize = 7
+
+	// chunkSize is the size of the plaintext of a chunk. The final chunk of an object is padded to it so the chunks
+	// of a range are found without knowing the size of the object.
+	chunkSize = 16 << 10
+	// chunkTrailerSize is the size of the final flag, the length of the plaintext and the mac that follow every chunk
+	chunkTrailerSize = 1 + 4 + macSize
+	sealedChunkSize  = chunkSize + chunkTrailerSize
+	macSize          = sha256.Size
+
+	maxCachedHeaders = 10_000
+	maxCachedKeys    = 1_000
+)
+
+var (
+	headerMagic = []byte("TENC")
+
+	errNotEncrypted = errors.New("object is not encrypted")
+)
+
+// header is written at the start of every encrypted object. It holds the wrapped data key of the tenant and the iv
+// of the object. The encrypted data follows the header in chunks, each sealed with a mac of the header, its index and
+// its encrypted data.
+type header struct {
+	plain   bool // the object was written without encryption
+	wrapped []byte
+	iv      []byte
+}
+
+func (h *header) size() int64 {
+	if h.plain {
+		return 0
+	}
+	return int64(headerPrefixSize + len(h.wrapped) + aes.BlockSize)
+}
+
+func (h *header) marshal() []byte {
+	buf := make([]byte, 0, h.size())
+	buf = append(buf, headerMagic...)
+	buf = append(buf, headerVersion)
+	buf = binary.BigEndian.AppendUint16(buf, uint16(len(h.wrapped)))
+	buf = append(buf, h.wrapped...)
+	return append(buf, h.iv...)
+}
+
+// parseHeaderPrefix returns the length of the wrapped key. ok is false if the object is not encrypted
+func parseHeaderPrefix(prefix []byte) (wrappedLen int, ok bool, err error) {
+	if len(prefix) < headerPrefixSize || !bytes.Equal(prefix[:len(headerMagic)], headerMagic) {
+		return 0, false, nil
+	}
+	if prefix[len(headerMagic)] != headerVersion {
+		return 0, false, fmt.Errorf("unsupported encryption header version %d", prefix[len(headerMagic)])
+	}
+	return int(binary.BigEndian.Uint16(prefix[len(headerMagic)+1:])), true, nil
+}
+
+// sealedSize returns the size of an encrypted object with size bytes of data
+func sealedSize(h *header, size int64) int64 {
+	if size < 0 {
+		return size
+	}
+	chunks := max((size+chunkSize-1)/chunkSize, 1)
+	return h.size() + chunks*sealedChunkSize
+}
+
+type dataKey struct {
+	key     []byte
+	wrapped []byte
+}
+
+// unwrappedKey is the key of the cache of unwrapped data keys. The tenant is part of the key because the key provider
+// only unwraps the data keys of the tenant that wrapped them.
+type unwrappedKey struct {
+	tenantID string
+	wrapped  string
+}
+
+type readerWriter struct {
+	keys            KeyProvider
+	readUnencrypted bool
+
+	dataKeysMtx sync.Mutex
+	dataKeys    map[string]*dataKey
+
+	headers   *lru.Cache[string, *header]
+	unwrapped *lru.Cache[unwrappedKey, []byte]
+
+	nextReader    backend.RawReader
+	nextWriter    backend.RawWriter
+	nextCompactor backend.Compactor
+}
+
+var (
+	_ backend.RawReader = (*readerWriter)(nil)
+	_ backend.RawWriter = (*readerWriter)(nil)
+	_ backend.Compactor = (*readerWriter)(nil)
+)
+
+// New wraps a backend to encrypt the objects of the blocks before they are written and decrypt them on read. The
+// objects are encrypted with AES-256 in CTR mode so ranges of them can be read, the data key of each tenant is
+// wrapped by the key provider and stored in the header of the objects. The metas of the blocks are not encrypted, they
+// are read by the backends and hold no trace data. Objects that are not encrypted fail to read unless
+// read_unencrypted_objects is set to read the blocks written before the encryption was enabled.
+//
+// The data of an object is sealed in chunks of 16KiB, each followed by an HMAC-SHA256 of the header, the index and the
+// encrypted data of the chunk. Ranges read the chunks they cover and check their macs, whole objects also check that
+// they end with the final chunk.
+func New(cfg *Config, nextReader backend.RawReader, nextWriter backend.RawWriter, nextCompactor backend.Compactor) (backend.RawReader, backend.RawWriter, backend.Compactor, error) {
+	keys, err := newKeyProvider(cfg)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return newReaderWriter(keys, cfg.ReadUnencryptedObjects, nextReader, nextWriter, nextCompactor)
+}
+
+func newReaderWriter(keys KeyProvider, readUnencrypted bool, nextReader backend.RawReader, nextWriter backend.RawWriter, nextCompactor backend.Compactor) (backend.RawReader, backend.RawWriter, backend.Compactor, error) {
+	headers, err := lru.New[string, *header](maxCachedHeaders)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	unwrapped, err := lru.New[unwrappedKey, []byte](maxCachedKeys)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	rw := &readerWriter{
+		keys:            keys,
+		readUnencrypted: readUnencrypted,
+		dataKeys:        map[string]*dataKey{},
+		headers:         headers,
+		unwrapped:       unwrapped,
+		nextReader:      nextReader,
+		nextWriter:      nextWriter,
+		nextCompactor:   nextCompactor,
+	}
+	return rw, rw, rw, nil
+}
+
+// List implements backend.RawReader
+func (r *readerWriter) List(ctx context.Context, keypath backend.KeyPath) ([]string, error) {
+	return r.nextReader.List(ctx, keypath)
+}
+
+// ListBlocks implements backend.RawReader
+func (r *readerWriter) ListBlocks(ctx context.Context, tenant string) ([]uuid.UUID, []uuid.UUID, error) {
+	return r.nextReader.ListBlocks(ctx, tenant)
+}
+
+// Find implements backend.RawReader
+func (r *readerWriter) Find(ctx context.Context, keypath backend.KeyPath, f backend.FindFunc) error {
+	return r.nextReader.Find(ctx, keypath, f)
+}
+
+// Read implements backend.RawReader
+func (r *readerWriter) Read(ctx context.Context, name string, keypath backend.KeyPath, cacheInfo *backend.CacheInfo) (io.ReadCloser, int64, error) {
+	rc, size, err := r.nextReader.Read(ctx, name, keypath, cacheInfo)
+	if err != nil || !encrypted(name, keypath) {
+		return rc, size, err
+	}
+
+	prefix := make([]byte, headerPrefixSize)
+	n, err := io.ReadFull(rc, prefix)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		_ = rc.Close()
+		return nil, 0, err
+	}
+
+	wrappedLen, ok, err := parseHeaderPrefix(prefix[:n])
+	if err == nil && !ok && !r.readUnencrypted {
+		err = errNotEncrypted
+	}
+	if err != nil {
+		_ = rc.Close()
+		return nil, 0, fmt.Errorf("error reading %s: %w", objectKey(name, keypath), err)
+	}
+	if !ok {
+		return &readCloser{Reader: io.MultiReader(bytes.NewReader(prefix[:n]), rc), Closer: rc}, size, nil
+	}
+
+	h := &header{wrapped: make([]byte, wrappedLen), iv: make([]byte, aes.BlockSize)}
+	if _, err := io.ReadFull(rc, h.wrapped); err != nil {
+		_ = rc.Close()
+		return nil, 0, fmt.Errorf("error reading encryption header: %w", err)
+	}
+	if _, err := io.ReadFull(rc, h.iv); err != nil {
+		_ = rc.Close()
+		return nil, 0, fmt.Errorf("error reading encryption header: %w", err)
+	}
+	r.headers.Add(objectKey(name, keypath), h)
+
+	key, err := r.key(ctx, keypath[0], h)
+	if err != nil {
+		_ = rc.Close()
+		return nil, 0, err
+	}
+	stream, err := newStream(key, h.iv, 0)
+	if err != nil {
+		_ = rc.Close()
+		return nil, 0, err
+	}
+
+	// the size of the final chunk is only known once it's read, so the size is rounded up to whole chunks
+	if size > 0 {
+		size = max(size-h.size(), 0) / sealedChunkSize * chunkSize
+	}
+	return &readCloser{Reader: newDecryptingReader(rc, stream, deriveMACKey(key), h.marshal()), Closer: rc}, size, nil
+}
+
+// ReadRange implements backend.RawReader
+func (r *readerWriter) ReadRange(ctx context.Context, name string, keypath backend.KeyPath, offset uint64, buffer []byte, cacheInfo *backend.CacheInfo) error {
+	if !encrypted(name, keypath) {
+		return r.nextReader.ReadRange(ctx, name, keypath, offset, buffer, cacheInfo)
+	}
+
+	h, err := r.header(ctx, name, keypath, cacheInfo)
+	if err != nil {
+		return err
+	}
+	if h.plain {
+		return r.nextReader.ReadRange(ctx, name, keypath, offset, buffer, cacheInfo)
+	}
+	if len(buffer) == 0 {
+		return nil
+	}
+
+	// read and open every chunk the range covers
+	first := offset / chunkSize
+	last := (offset + uint64(len(buffer)) - 1) / chunkSize
+	sealed := make([]byte, (last-first+1)*sealedChunkSize)
+	err = r.nextReader.ReadRange(ctx, name, keypath, uint64(h.size())+first*sealedChunkSize, sealed, cacheInfo)
+	if err != nil {
+		return err
+	}
+
+	key, err := r.key(ctx, keypath[0], h)
+	if err != nil {
+		return err
+	}
+	stream, err := newStream(key, h.iv, int64(first*chunkSize))
+	if err != nil {
+		return err
+	}
+	macKey, hdr := deriveMACKey(key), h.marshal()
+
+	out := buffer
+	skip := offset - first*chunkSize
+	for i := first; i <= last; i++ {
+		plain, final, err := openChunk(sealed[(i-first)*sealedChunkSize:(i-first+1)*sealedChunkSize], stream, macKey, hdr, i)
+		if err != nil {
+			return err
+		}
+		if skip < uint64(len(plain)) {
+			out = out[copy(out, plain[skip:]):]
+		}
+		skip = 0
+		if final {
+			break
+		}
+	}
+	if len(out) > 0 {
+		return fmt.Errorf("error reading %s: range %d-%d is past the end of the object: %w", objectKey(name, keypath), offset, offset+uint64(len(buffer)), io.ErrUnexpectedEOF)
+	}
+	return nil
+}
+
+// Shutdown implements backend.RawReader
+func (r *readerWriter) Shutdown() {
+	r.nextReader.Shutdown()
+}
+
+// Write implements backend.RawWriter
+func (r *readerWriter) Write(ctx context.Context, name string, keypath backend.KeyPath, data io.Reader, size int64, cacheInfo *backend.CacheInfo) error {
+	if !encrypted(name, keypath) {
+		return r.nextWriter.Write(ctx, name, keypath, data, size, cacheInfo)
+	}
+
+	h, key, err := r.newObject(ctx, keypath[0])
+	if err != nil {
+		return err
+	}
+
+	reader, err := newEncryptingReader(h, key, data)
+	if err != nil {
+		return err
+	}
+
+	err = r.nextWriter.Write(ctx, name, keypath, reader, sealedSize(h, size), cacheInfo)
+	if err != nil {
+		return err
+	}
+
+	r.headers.Add(objectKey(name, keypath), h)
+	return nil
+}
+
+// appendTracker continues the encryption of an object across appends. The data that doesn't fill a chunk is held
+// back until the next append, the final chunk is appended when the object is closed.
+type appendTracker struct {
+	tracker backend.AppendTracker
+	name    string
+	keypath backend.KeyPath
+	stream  cipher.Stream
+	macKey  []byte
+	header  []byte
+	index   uint64
+	pending []byte
+}
+
+// Append implements backend.RawWriter
+func (r *readerWriter) Append(ctx context.Context, name string, keypath backend.KeyPath, tracker backend.AppendTracker, buffer []byte) (backend.AppendTracker, error) {
+	if !encrypted(name, keypath) {
+		return r.nextWriter.Append(ctx, name, keypath, tracker, buffer)
+	}
+
+	var t *appendTracker
+	var out []byte
+	if tracker == nil {
+		h, key, err := r.newObject(ctx, keypath[0])
+		if err != nil {
+			return nil, err
+		}
+		stream, err := newStream(key, h.iv, 0)
+		if err != nil {
+			return nil, err
+		}
+		out = h.marshal()
+		t = &appendTracker{name: name, keypath: keypath, stream: stream, macKey: deriveMACKey(key), header: out}
+		r.headers.Add(objectKey(name, keypath), h)
+	} else {
+		t = tracker.(*appendTracker)
+	}
+
+	// a chunk is sealed once more data follows it, so it's not the final one
+	t.pending = append(t.pending, buffer...)
+	sealed := 0
+	for ; len(t.pending)-sealed > chunkSize; sealed += chunkSize {
+		out = sealChunk(out, t.stream, t.macKey, t.header, t.index, false, t.pending[sealed:sealed+chunkSize])
+		t.index++
+	}
+	t.pending = append(t.pending[:0], t.pending[sealed:]...)
+	if len(out) == 0 {
+		return t, nil
+	}
+
+	inner, err := r.nextWriter.Append(ctx, name, keypath, t.tracker, out)
+	if err != nil {
+		return nil, err
+	}
+	t.tracker = inner
+	return t, nil
+}
+
+// CloseAppend implements backend.RawWriter
+func (r *readerWriter) CloseAppend(ctx context.Context, tracker backend.AppendTracker) error {
+	if t, ok := tracker.(*appendTracker); ok {
+		out := sealChunk(nil, t.stream, t.macKey, t.header, t.index, true, t.pending)
+		inner, err := r.nextWriter.Append(ctx, t.name, t.keypath, t.tracker, out)
+		if err != nil {
+			return err
+		}
+		return r.nextWriter.CloseAppend(ctx, inner)
+	}
+	return r.nextWriter.CloseAppend(ctx, tracker)
+}
+
+// Delete implements backend.RawWriter
+func (r *readerWriter) Delete(ctx context.Context, name string, keypath backend.KeyPath, cacheInfo *backend.CacheInfo) error {
+	r.headers.Remove(objectKey(name, keypath))
+	return r.nextWriter.Delete(ctx, name, keypath, cacheInfo)
+}
+
+// MarkBlockCompacted implements backend.Compactor
+func (r *readerWriter) MarkBlockCompacted(blockID uuid.UUID, tenantID string) error {
+	return r.nextCompactor.MarkBlockCompacted(blockID, tenantID)
+}
+
+// ClearBlock implements backend.Compactor
+func (r *readerWriter) ClearBlock(blockID uuid.UUID, tenantID string) error {
+	return r.nextCompactor.ClearBlock(blockID, tenantID)
+}
+
+// CompactedBlockMeta implements backend.Compactor
+func (r *readerWriter) CompactedBlockMeta(blockID uuid.UUID, tenantID string) (*backend.CompactedBlockMeta, error) {
+	return r.nextCompactor.CompactedBlockMeta(blockID, tenantID)
+}
+
+// newObject returns the header and the data key of a new object of the tenant
+func (r *readerWriter) newObject(ctx context.Context, tenantID string) (*header, []byte, error) {
+	dk, err := r.dataKey(ctx, tenantID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	h := &header{wrapped: dk.wrapped, iv: make([]byte, aes.BlockSize)}
+	if _, err := rand.Read(h.iv); err != nil {
+		return nil, nil, err
+	}
+	return h, dk.key, nil
+}
+
+// dataKey returns the data key used to write the objects of the tenant. A data key is created per tenant when the
+// first object of the tenant is written by the process.
+func (r *readerWriter) dataKey(ctx context.Context, tenantID string) (*dataKey, error) {
+	r.dataKeysMtx.Lock()
+	defer r.dataKeysMtx.Unlock()
+
+	if dk, ok := r.dataKeys[tenantID]; ok {
+		return dk, nil
+	}
+
+	key := make([]byte, dataKeySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+
+	wrapped, err := r.keys.WrapKey(ctx, tenantID, key)
+	if err != nil {
+		return nil, fmt.Errorf("error wrapping data key of tenant %s: %w", tenantID, err)
+	}
+	if len(wrapped) > 1<<16-1 {
+		return nil, fmt.Errorf("wrapped data key of tenant %s is too long: %d bytes", tenantID, len(wrapped))
+	}
+
+	dk := &dataKey{key: key, wrapped: wrapped}
+	r.dataKeys[tenantID] = dk
+	r.unwrapped.Add(unwrappedKey{tenantID: tenantID, wrapped: string(wrapped)}, key)
+	return dk, nil
+}
+
+// header returns the encryption header of the object. The headers are cached so ranges of an object are read with
+// a single request.
+func (r *readerWriter) header(ctx context.Context, name string, keypath backend.KeyPath, cacheInfo *backend.CacheInfo) (*header, error) {
+	key := objectKey(name, keypath)
+	if h, ok := r.headers.Get(key); ok {
+		return h, nil
+	}
+
+	prefix := make([]byte, headerPrefixSize)
+	if err := r.nextReader.ReadRange(ctx, name, keypath, 0, prefix, cacheInfo); err != nil {
+		return nil, fmt.Errorf("error reading encryption header: %w", err)
+	}
+
+	h := &header{}
+	wrappedLen, ok, err := parseHeaderPrefix(prefix)
+	if err == nil && !ok && !r.readUnencrypted {
+		err = errNotEncrypted
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s: %w", key, err)
+	}
+	if !ok {
+		h.plain = true
+	} else {
+		buf := make([]byte, wrappedLen+aes.BlockSize)
+		if err := r.nextReader.ReadRange(ctx, name, keypath, headerPrefixSize, buf, cacheInfo); err != nil {
+			return nil, fmt.Errorf("error reading encryption header: %w", err)
+		}
+		h.wrapped, h.iv = buf[:wrappedLen], buf[wrappedLen:]
+	}
+
+	r.headers.Add(key, h)
+	return h, nil
+}
+
+// key returns the unwrapped data key of the object of the header
+func (r *readerWriter) key(ctx context.Context, tenantID string, h *header) ([]byte, error) {
+	cacheKey := unwrappedKey{tenantID: tenantID, wrapped: string(h.wrapped)}
+	key, ok := r.unwrapped.Get(cacheKey)
+	if !ok {
+		var err error
+		key, err = r.keys.UnwrapKey(ctx, tenantID, h.wrapped)
+		if err != nil {
+			return nil, fmt.Errorf("error unwrapping data key of tenant %s: %w", tenantID, err)
+		}
+		r.unwrapped.Add(cacheKey, key)
+	}
+	return key, nil
+}
+
+// encrypted returns true for the objects of the blocks except for their metas
+func encrypted(name string, keypath backend.KeyPath) bool {
+	if len(keypath) != 2 || name == backend.MetaName || name == backend.CompactedMetaName {
+		return false
+	}
+
+	_, err := uuid.Parse(keypath[1])
+	return err == nil
+}
+
+func objectKey(name string, keypath backend.KeyPath) string {
+	return path.Join(path.Join(keypath...), name)
+}
+
+// newStream returns an AES-CTR stream positioned at offset
+func newStream(key, iv []byte, offset int64) (cipher.Stream, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	// the counter is the iv incremented once per block
+	counter := make([]byte, aes.BlockSize)
+	copy(counter, iv)
+	carry := uint64(offset / aes.BlockSize)
+	for i := aes.BlockSize - 1; i >= 0 && carry > 0; i-- {
+		sum := uint64(counter[i]) + carry&0xff
+		counter[i] = byte(sum)
+		carry = carry>>8 + sum>>8
+	}
+
+	stream := cipher.NewCTR(block, counter)
+	if skip := offset % aes.BlockSize; skip > 0 {
+		discard := make([]byte, skip)
+		stream.XORKeyStream(discard, discard)
+	}
+	return stream, nil
+}
+
+// deriveMACKey returns the key of the macs of the chunks. It's derived from the data key so the data key is only used
+// for the encryption.
+func deriveMACKey(key []byte) []byte {
+	derive := hmac.New(sha256.New, key)
+	derive.Write([]byte("tempo encryption mac"))
+	return derive.Sum(nil)
+}
+
+// sealChunk encrypts the plaintext of a chunk padded to chunkSize and appends it with its trailer to out
+func sealChunk(out []byte, stream cipher.Stream, macKey, header []byte, index uint64, final bool, plain []byte) []byte {
+	start := len(out)
+	out = append(out, plain...)
+	out = append(out, make([]byte, chunkSize-len(plain))...)
+	stream.XORKeyStream(out[start:], out[start:])
+
+	var flag byte
+	if final {
+		flag = 1
+	}
+	out = append(out, flag)
+	out = binary.BigEndian.AppendUint32(out, uint32(len(plain)))
+	return append(out, chunkMAC(macKey, header, index, out[start+chunkSize:], out[start:start+chunkSize])...)
+}
+
+// openChunk checks the mac of a sealed chunk and decrypts it in place. It returns the plaintext without the padding.
+func openChunk(sealed []byte, stream cipher.Stream, macKey, header []byte, index uint64) (plain []byte, final bool, err error) {
+	data, trailer := sealed[:chunkSize], sealed[chunkSize:]
+	if !hmac.Equal(chunkMAC(macKey, header, index, trailer[:5], data), trailer[5:]) {
+		return nil, false, errAuthentication
+	}
+
+	final = trailer[0] == 1
+	length := binary.BigEndian.Uint32(trailer[1:5])
+	if trailer[0] > 1 || length > chunkSize || (!final && length != chunkSize) {
+		return nil, false, errAuthentication
+	}
+
+	stream.XORKeyStream(data, data)
+	return data[:length], final, nil
+}
+
+// chunkMAC returns the mac of a chunk. It covers the header so chunks can't be moved between objects, and the index so
+// they can't be reordered.
+func chunkMAC(macKey, header []byte, index uint64, flagAndLength, data []byte) []byte {
+	mac := hmac.New(sha256.New, macKey)
+	mac.Write(header)
+	mac.Write(binary.BigEndian.AppendUint64(nil, index))
+	mac.Write(flagAndLength)
+	mac.Write(data)
+	return mac.Sum(nil)
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
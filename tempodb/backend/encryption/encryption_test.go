@@ -0,0 +1,328 @@
+package encryption
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"io"
+	"os"
+	"path"
+	"slices"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/backend/local"
+)
+
+func TestEncryption(t *testing.T) {
+	dir := t.TempDir()
+	r, w, _ := newTestBackend(t, dir)
+	ctx := context.Background()
+
+	data := make([]byte, 3*chunkSize+1000)
+	_, err := rand.Read(data)
+	require.NoError(t, err)
+
+	keypath := backend.KeyPathForBlock(uuid.New(), "tenant")
+	require.NoError(t, w.Write(ctx, "data", keypath, bytes.NewReader(data), int64(len(data)), nil))
+
+	// appended objects are sealed in the same chunks
+	var tracker backend.AppendTracker
+	for i := 0; i < len(data); i += 3333 {
+		tracker, err = w.Append(ctx, "appended", keypath, tracker, data[i:min(i+3333, len(data))])
+		require.NoError(t, err)
+	}
+	require.NoError(t, w.CloseAppend(ctx, tracker))
+
+	// objects that fill their final chunk and empty objects
+	require.NoError(t, w.Write(ctx, "full", keypath, bytes.NewReader(data[:2*chunkSize]), 2*chunkSize, nil))
+	require.NoError(t, w.Write(ctx, "empty", keypath, bytes.NewReader(nil), 0, nil))
+
+	require.NoError(t, w.Write(ctx, backend.MetaName, keypath, bytes.NewReader([]byte("{}")), 2, nil))
+
+	stored, err := os.ReadFile(path.Join(dir, "tenant", keypath[1], "data"))
+	require.NoError(t, err)
+	require.False(t, bytes.Contains(stored, data[:32]))
+
+	meta, err := os.ReadFile(path.Join(dir, "tenant", keypath[1], backend.MetaName))
+	require.NoError(t, err)
+	require.Equal(t, []byte("{}"), meta)
+
+	for name, expected := range map[string][]byte{"data": data, "appended": data, "full": data[:2*chunkSize], "empty": {}} {
+		rc, size, err := r.Read(ctx, name, keypath, nil)
+		require.NoError(t, err)
+		// the size is rounded up to whole chunks
+		require.GreaterOrEqual(t, size, int64(len(expected)))
+		actual, err := io.ReadAll(rc)
+		require.NoError(t, err)
+		require.NoError(t, rc.Close())
+		require.Equal(t, expected, actual)
+
+		for _, offset := range []int{0, 1, 15, 16, 17, 500, chunkSize - 50, chunkSize, 2*chunkSize - 1, len(expected) - 100} {
+			if offset < 0 || offset >= len(expected) {
+				continue
+			}
+			for _, length := range []int{1, 100, 2*chunkSize + 10} {
+				buffer := make([]byte, min(length, len(expected)-offset))
+				require.NoError(t, r.ReadRange(ctx, name, keypath, uint64(offset), buffer, nil))
+				require.Equal(t, expected[offset:offset+len(buffer)], buffer)
+			}
+		}
+
+		// ranges past the end of the data fail
+		require.Error(t, r.ReadRange(ctx, name, keypath, uint64(len(expected)), make([]byte, 1), nil))
+	}
+}
+
+func TestEncryptionReadsPlainObjects(t *testing.T) {
+	dir := t.TempDir()
+	_, plainW, _, err := local.New(&local.Config{Path: dir})
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	data := []byte("written before the encryption was enabled")
+	keypath := backend.KeyPathForBlock(uuid.New(), "tenant")
+	require.NoError(t, plainW.Write(ctx, "data", keypath, bytes.NewReader(data), int64(len(data)), nil))
+
+	// objects that are not encrypted fail to read by default
+	r, _, _ := newTestBackend(t, dir)
+	_, _, err = r.Read(ctx, "data", keypath, nil)
+	require.ErrorIs(t, err, errNotEncrypted)
+	require.ErrorIs(t, r.ReadRange(ctx, "data", keypath, 8, make([]byte, 6), nil), errNotEncrypted)
+
+	r, _, _ = newTestBackend(t, dir, func(cfg *Config) { cfg.ReadUnencryptedObjects = true })
+	rc, _, err := r.Read(ctx, "data", keypath, nil)
+	require.NoError(t, err)
+	actual, err := io.ReadAll(rc)
+	require.NoError(t, err)
+	require.Equal(t, data, actual)
+
+	buffer := make([]byte, 6)
+	require.NoError(t, r.ReadRange(ctx, "data", keypath, 8, buffer, nil))
+	require.Equal(t, []byte("before"), buffer)
+}
+
+func TestEncryptingReaderSeek(t *testing.T) {
+	dir := t.TempDir()
+	r, w, _ := newTestBackend(t, dir)
+	rw := w.(*readerWriter)
+	ctx := context.Background()
+
+	data := []byte("the data is encrypted again after the reader is rewound")
+	h, key, err := rw.newObject(ctx, "tenant")
+	require.NoError(t, err)
+
+	reader, err := newEncryptingReader(h, key, bytes.NewReader(data))
+	require.NoError(t, err)
+	seeker, ok := reader.(io.Seeker)
+	require.True(t, ok)
+
+	first, err := io.ReadAll(reader)
+	require.NoError(t, err)
+
+	_, err = seeker.Seek(0, io.SeekStart)
+	require.NoError(t, err)
+	second, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	require.Equal(t, first, second)
+
+	// seeking into the object continues the same encryption and mac
+	_, err = seeker.Seek(int64(len(first)/2), io.SeekStart)
+	require.NoError(t, err)
+	pos, err := seeker.Seek(0, io.SeekCurrent)
+	require.NoError(t, err)
+	require.Equal(t, int64(len(first)/2), pos)
+	rest, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	require.Equal(t, first[len(first)/2:], rest)
+
+	// the rewound object is decrypted
+	keypath := backend.KeyPathForBlock(uuid.New(), "tenant")
+	_, err = seeker.Seek(0, io.SeekStart)
+	require.NoError(t, err)
+	require.NoError(t, rw.nextWriter.Write(ctx, "data", keypath, reader, int64(len(second)), nil))
+
+	rc, _, err := r.Read(ctx, "data", keypath, nil)
+	require.NoError(t, err)
+	actual, err := io.ReadAll(rc)
+	require.NoError(t, err)
+	require.Equal(t, data, actual)
+}
+
+func TestEncryptionAuthentication(t *testing.T) {
+	data := make([]byte, 2*chunkSize+1000)
+	_, err := rand.Read(data)
+	require.NoError(t, err)
+
+	// chunk returns the sealed chunk i of the stored object
+	chunk := func(stored []byte, i int) []byte {
+		start := len(stored) - 3*sealedChunkSize + i*sealedChunkSize
+		return stored[start : start+sealedChunkSize]
+	}
+
+	tcs := []struct {
+		name   string
+		modify func(stored []byte) []byte
+		// the chunks whose ranges fail to read
+		failedChunks []int
+	}{
+		{
+			name: "modified data",
+			modify: func(stored []byte) []byte {
+				chunk(stored, 1)[500] ^= 1
+				return stored
+			},
+			failedChunks: []int{1},
+		},
+		{
+			name: "modified mac",
+			modify: func(stored []byte) []byte {
+				stored[len(stored)-1] ^= 1
+				return stored
+			},
+			failedChunks: []int{2},
+		},
+		{
+			name: "modified length",
+			modify: func(stored []byte) []byte {
+				chunk(stored, 2)[chunkSize+4]++
+				return stored
+			},
+			failedChunks: []int{2},
+		},
+		{
+			name: "reordered",
+			modify: func(stored []byte) []byte {
+				first := bytes.Clone(chunk(stored, 0))
+				copy(chunk(stored, 0), chunk(stored, 1))
+				copy(chunk(stored, 1), first)
+				return stored
+			},
+			failedChunks: []int{0, 1},
+		},
+		{
+			name: "truncated",
+			modify: func(stored []byte) []byte {
+				return stored[:len(stored)-1]
+			},
+			failedChunks: []int{2},
+		},
+		{
+			name: "no final chunk",
+			modify: func(stored []byte) []byte {
+				return stored[:len(stored)-sealedChunkSize]
+			},
+			failedChunks: []int{2},
+		},
+		{
+			name: "appended",
+			modify: func(stored []byte) []byte {
+				return append(stored, chunk(stored, 1)...)
+			},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			r, w, _ := newTestBackend(t, dir)
+			ctx := context.Background()
+
+			keypath := backend.KeyPathForBlock(uuid.New(), "tenant")
+			require.NoError(t, w.Write(ctx, "data", keypath, bytes.NewReader(data), int64(len(data)), nil))
+
+			file := path.Join(dir, "tenant", keypath[1], "data")
+			stored, err := os.ReadFile(file)
+			require.NoError(t, err)
+			require.NoError(t, os.WriteFile(file, tc.modify(stored), 0o600))
+
+			rc, _, err := r.Read(ctx, "data", keypath, nil)
+			require.NoError(t, err)
+			_, err = io.ReadAll(rc)
+			require.ErrorIs(t, err, errAuthentication)
+			require.NoError(t, rc.Close())
+
+			// ranges are authenticated by the chunks they cover
+			for i := 0; i < 3; i++ {
+				buffer := make([]byte, 10)
+				err := r.ReadRange(ctx, "data", keypath, uint64(i*chunkSize), buffer, nil)
+				if slices.Contains(tc.failedChunks, i) {
+					require.Error(t, err)
+					continue
+				}
+				require.NoError(t, err)
+				require.Equal(t, data[i*chunkSize:i*chunkSize+10], buffer)
+			}
+		})
+	}
+}
+
+func TestEncryptionKeysAreCachedPerTenant(t *testing.T) {
+	dir := t.TempDir()
+	r, w, _ := newTestBackend(t, dir)
+	ctx := context.Background()
+
+	// the data key of tenant a is cached once it's written
+	keypath := backend.KeyPathForBlock(uuid.New(), "a")
+	require.NoError(t, w.Write(ctx, "data", keypath, bytes.NewReader([]byte("data of tenant a")), 16, nil))
+
+	// an object copied to tenant b can't be read with the cached key of tenant a
+	stored, err := os.ReadFile(path.Join(dir, "a", keypath[1], "data"))
+	require.NoError(t, err)
+	copied := backend.KeyPathForBlock(uuid.New(), "b")
+	require.NoError(t, os.MkdirAll(path.Join(dir, "b", copied[1]), 0o700))
+	require.NoError(t, os.WriteFile(path.Join(dir, "b", copied[1], "data"), stored, 0o600))
+
+	_, _, err = r.Read(ctx, "data", copied, nil)
+	require.ErrorContains(t, err, "error unwrapping data key of tenant b")
+	require.ErrorContains(t, r.ReadRange(ctx, "data", copied, 0, make([]byte, 4), nil), "error unwrapping data key of tenant b")
+}
+
+func TestFileKeyProviderBindsTenant(t *testing.T) {
+	p, err := newFileKeyProvider(&FileConfig{KeyFile: writeKeyFile(t)})
+	require.NoError(t, err)
+
+	wrapped, err := p.WrapKey(context.Background(), "a", []byte("key"))
+	require.NoError(t, err)
+
+	key, err := p.UnwrapKey(context.Background(), "a", wrapped)
+	require.NoError(t, err)
+	require.Equal(t, []byte("key"), key)
+
+	_, err = p.UnwrapKey(context.Background(), "b", wrapped)
+	require.Error(t, err)
+}
+
+func TestConfigValidate(t *testing.T) {
+	require.NoError(t, (&Config{}).Validate())
+	require.EqualError(t, (&Config{Enabled: true, KeyProvider: KeyProviderFile}).Validate(), "file.key_file is required")
+	require.EqualError(t, (&Config{Enabled: true, KeyProvider: KeyProviderAWSKMS}).Validate(), "aws_kms.key_id is required")
+	require.EqualError(t, (&Config{Enabled: true, KeyProvider: "vault"}).Validate(), "unknown key_provider vault, must be file or aws_kms")
+}
+
+func newTestBackend(t *testing.T, dir string, opts ...func(*Config)) (backend.RawReader, backend.RawWriter, backend.Compactor) {
+	nextR, nextW, nextC, err := local.New(&local.Config{Path: dir})
+	require.NoError(t, err)
+
+	cfg := &Config{Enabled: true, KeyProvider: KeyProviderFile, File: FileConfig{KeyFile: writeKeyFile(t)}}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	r, w, c, err := New(cfg, nextR, nextW, nextC)
+	require.NoError(t, err)
+	return r, w, c
+}
+
+func writeKeyFile(t *testing.T) string {
+	key := make([]byte, dataKeySize)
+	_, err := rand.Read(key)
+	require.NoError(t, err)
+
+	keyFile := path.Join(t.TempDir(), "key")
+	require.NoError(t, os.WriteFile(keyFile, []byte(hex.EncodeToString(key)+"\n"), 0o600))
+	return keyFile
+}
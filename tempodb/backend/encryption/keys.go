@@ -0,0 +1,139 @@
+package encryption
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/kms"
+)
+
+// KeyProvider wraps the data keys of the tenants with a master key. The tenant is bound to the wrapped key so it
+// can't be unwrapped for another tenant.
+type KeyProvider interface {
+	WrapKey(ctx context.Context, tenantID string, key []byte) ([]byte, error)
+	UnwrapKey(ctx context.Context, tenantID string, wrapped []byte) ([]byte, error)
+}
+
+func newKeyProvider(cfg *Config) (KeyProvider, error) {
+	switch cfg.KeyProvider {
+	case KeyProviderFile:
+		return newFileKeyProvider(&cfg.File)
+	case KeyProviderAWSKMS:
+		return newAWSKMSKeyProvider(&cfg.AWSKMS)
+	default:
+		return nil, fmt.Errorf("unknown key provider %s", cfg.KeyProvider)
+	}
+}
+
+// fileKeyProvider wraps the keys with AES-GCM
+type fileKeyProvider struct {
+	aead cipher.AEAD
+}
+
+func newFileKeyProvider(cfg *FileConfig) (*fileKeyProvider, error) {
+	data, err := os.ReadFile(cfg.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("error reading key file: %w", err)
+	}
+
+	key, err := hex.DecodeString(string(bytes.TrimSpace(data)))
+	if err != nil {
+		return nil, fmt.Errorf("error decoding key file: %w", err)
+	}
+	if len(key) != dataKeySize {
+		return nil, fmt.Errorf("key must be %d bytes, got %d", dataKeySize, len(key))
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &fileKeyProvider{aead: aead}, nil
+}
+
+// WrapKey implements KeyProvider
+func (p *fileKeyProvider) WrapKey(_ context.Context, tenantID string, key []byte) ([]byte, error) {
+	nonce := make([]byte, p.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return p.aead.Seal(nonce, nonce, key, []byte(tenantID)), nil
+}
+
+// UnwrapKey implements KeyProvider
+func (p *fileKeyProvider) UnwrapKey(_ context.Context, tenantID string, wrapped []byte) ([]byte, error) {
+	if len(wrapped) < p.aead.NonceSize() {
+		return nil, errors.New("wrapped key too short")
+	}
+	nonce, ciphertext := wrapped[:p.aead.NonceSize()], wrapped[p.aead.NonceSize():]
+	return p.aead.Open(nil, nonce, ciphertext, []byte(tenantID))
+}
+
+// awsKMSKeyProvider wraps the keys with a key of AWS KMS. The tenant is passed as encryption context.
+type awsKMSKeyProvider struct {
+	keyID  string
+	client *kms.KMS
+}
+
+func newAWSKMSKeyProvider(cfg *AWSKMSConfig) (*awsKMSKeyProvider, error) {
+	awsCfg := aws.NewConfig()
+	if cfg.Region != "" {
+		awsCfg = awsCfg.WithRegion(cfg.Region)
+	}
+	if cfg.Endpoint != "" {
+		awsCfg = awsCfg.WithEndpoint(cfg.Endpoint)
+	}
+
+	sess, err := session.NewSessionWithOptions(session.Options{
+		Config:            *awsCfg,
+		SharedConfigState: session.SharedConfigEnable,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &awsKMSKeyProvider{keyID: cfg.KeyID, client: kms.New(sess)}, nil
+}
+
+// WrapKey implements KeyProvider
+func (p *awsKMSKeyProvider) WrapKey(ctx context.Context, tenantID string, key []byte) ([]byte, error) {
+	out, err := p.client.EncryptWithContext(ctx, &kms.EncryptInput{
+		KeyId:             aws.String(p.keyID),
+		Plaintext:         key,
+		EncryptionContext: encryptionContext(tenantID),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out.CiphertextBlob, nil
+}
+
+// UnwrapKey implements KeyProvider
+func (p *awsKMSKeyProvider) UnwrapKey(ctx context.Context, tenantID string, wrapped []byte) ([]byte, error) {
+	out, err := p.client.DecryptWithContext(ctx, &kms.DecryptInput{
+		KeyId:             aws.String(p.keyID),
+		CiphertextBlob:    wrapped,
+		EncryptionContext: encryptionContext(tenantID),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out.Plaintext, nil
+}
+
+func encryptionContext(tenantID string) map[string]*string {
+	return map[string]*string{"tenant": aws.String(tenantID)}
+}
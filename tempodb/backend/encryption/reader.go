@@ -0,0 +1,223 @@
+package encryption
+
+import (
+	"crypto/cipher"
+	"errors"
+	"io"
+)
+
+var errAuthentication = errors.New("encrypted object failed authentication")
+
+// encryptingReader reads the header followed by the sealed chunks of the data. A chunk is read ahead of the one that
+// is sealed to know if the sealed chunk is the final one.
+type encryptingReader struct {
+	header []byte
+	data   io.Reader
+	key    []byte
+	iv     []byte
+	macKey []byte
+
+	stream   cipher.Stream
+	index    uint64
+	ahead    []byte // the plaintext of the next chunk
+	spare    []byte
+	aheadEOF bool // the data ended while reading ahead
+	started  bool
+	done     bool
+	out      []byte // sealed bytes that are not read yet
+	buf      []byte
+	pos      int64
+}
+
+// seekableEncryptingReader is an encryptingReader of data that can be rewound, so writes of the object can be retried
+type seekableEncryptingReader struct {
+	encryptingReader
+	seeker    io.Seeker
+	dataStart int64
+}
+
+func newEncryptingReader(h *header, key []byte, data io.Reader) (io.Reader, error) {
+	r := encryptingReader{
+		header: h.marshal(),
+		data:   data,
+		key:    key,
+		iv:     h.iv,
+		macKey: deriveMACKey(key),
+		ahead:  make([]byte, chunkSize),
+		spare:  make([]byte, chunkSize),
+	}
+	if err := r.reset(); err != nil {
+		return nil, err
+	}
+
+	seeker, ok := data.(io.Seeker)
+	if !ok {
+		return &r, nil
+	}
+
+	// data that can't report its position is read once
+	start, err := seeker.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return &r, nil
+	}
+
+	return &seekableEncryptingReader{encryptingReader: r, seeker: seeker, dataStart: start}, nil
+}
+
+// reset positions the reader at the start of the object
+func (r *encryptingReader) reset() error {
+	stream, err := newStream(r.key, r.iv, 0)
+	if err != nil {
+		return err
+	}
+
+	r.stream = stream
+	r.index = 0
+	r.started = false
+	r.done = false
+	r.out = append(r.buf[:0], r.header...)
+	r.pos = 0
+	return nil
+}
+
+func (r *encryptingReader) Read(p []byte) (int, error) {
+	for len(r.out) == 0 {
+		if r.done {
+			return 0, io.EOF
+		}
+		if err := r.seal(); err != nil {
+			return 0, err
+		}
+	}
+
+	n := copy(p, r.out)
+	r.out = r.out[n:]
+	r.pos += int64(n)
+	return n, nil
+}
+
+// seal seals the next chunk of the data
+func (r *encryptingReader) seal() error {
+	var err error
+	if !r.started {
+		r.ahead, r.aheadEOF, err = readChunk(r.data, r.ahead)
+		if err != nil {
+			return err
+		}
+		r.started = true
+	}
+
+	plain := r.ahead
+	final := r.aheadEOF
+	if !final {
+		r.ahead, r.aheadEOF, err = readChunk(r.data, r.spare)
+		if err != nil {
+			return err
+		}
+		r.spare = plain[:cap(plain)]
+		final = r.aheadEOF && len(r.ahead) == 0
+	}
+
+	r.buf = sealChunk(r.buf[:0], r.stream, r.macKey, r.header, r.index, final, plain)
+	r.out = r.buf
+	r.index++
+	r.done = final
+	return nil
+}
+
+// readChunk reads the plaintext of a chunk into buf. eof is true if the data ended.
+func readChunk(data io.Reader, buf []byte) (chunk []byte, eof bool, err error) {
+	n, err := io.ReadFull(data, buf[:chunkSize])
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return buf[:n], true, nil
+	}
+	return buf[:n], false, err
+}
+
+// Seek supports seeking from the start and the current position. The chunks are sealed in order, so the data is
+// read again up to the position.
+func (r *seekableEncryptingReader) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		if offset == 0 {
+			return r.pos, nil
+		}
+		offset += r.pos
+	default:
+		return 0, errors.New("unsupported whence")
+	}
+	if offset < 0 {
+		return 0, errors.New("negative position")
+	}
+
+	if _, err := r.seeker.Seek(r.dataStart, io.SeekStart); err != nil {
+		return 0, err
+	}
+	if err := r.reset(); err != nil {
+		return 0, err
+	}
+	if _, err := io.CopyN(io.Discard, &r.encryptingReader, offset); err != nil {
+		return 0, err
+	}
+	return offset, nil
+}
+
+// decryptingReader opens the sealed chunks of an object in order. The object must end with its final chunk.
+type decryptingReader struct {
+	r      io.Reader
+	stream cipher.Stream
+	macKey []byte
+	header []byte
+
+	index  uint64
+	sealed []byte
+	plain  []byte // the plaintext of the last opened chunk that is not read yet
+	final  bool
+	err    error
+}
+
+func newDecryptingReader(r io.Reader, stream cipher.Stream, macKey, header []byte) *decryptingReader {
+	return &decryptingReader{r: r, stream: stream, macKey: macKey, header: header, sealed: make([]byte, sealedChunkSize)}
+}
+
+func (r *decryptingReader) Read(p []byte) (int, error) {
+	for len(r.plain) == 0 {
+		if r.err != nil {
+			return 0, r.err
+		}
+		r.err = r.open()
+	}
+
+	n := copy(p, r.plain)
+	r.plain = r.plain[n:]
+	return n, nil
+}
+
+// open opens the next chunk. After the final chunk it checks that the object ends.
+func (r *decryptingReader) open() error {
+	if r.final {
+		var b [1]byte
+		n, err := io.ReadFull(r.r, b[:])
+		if n > 0 {
+			return errAuthentication
+		}
+		return err
+	}
+
+	if _, err := io.ReadFull(r.r, r.sealed); err != nil {
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return errAuthentication
+		}
+		return err
+	}
+
+	plain, final, err := openChunk(r.sealed, r.stream, r.macKey, r.header, r.index)
+	if err != nil {
+		return err
+	}
+	r.index++
+	r.plain = plain
+	r.final = final
+	return nil
+}
@@ -26,7 +26,9 @@ func (rw *readerWriter) MarkBlockCompacted(blockID uuid.UUID, tenantID string) e
 	)
 
 	ctx := context.TODO()
-	_, err := dst.CopierFrom(src).Run(ctx)
+	copier := dst.CopierFrom(src)
+	copier.DestinationKMSKeyName = rw.cfg.KMSKeyName
+	_, err := copier.Run(ctx)
 	if err != nil {
 		return err
 	}
@@ -18,8 +18,24 @@ type Config struct {
 	ObjectCacheControl    string            `yaml:"object_cache_control"`
 	ObjectMetadata        map[string]string `yaml:"object_metadata"`
 	ListBlocksConcurrency int               `yaml:"list_blocks_concurrency"`
+	// KMSKeyName is the Cloud KMS key that encrypts the written objects (CMEK). Empty uses the default encryption of
+	// the bucket.
+	KMSKeyName string `yaml:"kms_key_name"`
+	// AuthType selects the credentials. Empty or default uses the application default credentials.
+	AuthType        string `yaml:"auth_type"`
+	CredentialsFile string `yaml:"credentials_file"`
+	// RequesterPays bills the requests and the data transfer to RequesterPaysProject instead of the project of the
+	// bucket
+	RequesterPays        bool   `yaml:"requester_pays"`
+	RequesterPaysProject string `yaml:"requester_pays_project"`
 }
 
+const (
+	AuthTypeDefault          = "default"
+	AuthTypeCredentialsFile  = "credentials_file"
+	AuthTypeWorkloadIdentity = "workload_identity"
+)
+
 func (cfg *Config) RegisterFlagsAndApplyDefaults(prefix string, f *flag.FlagSet) {
 	f.StringVar(&cfg.BucketName, util.PrefixConfig(prefix, "gcs.bucket"), "", "gcs bucket to store traces in.")
 	f.StringVar(&cfg.Prefix, util.PrefixConfig(prefix, "gcs.prefix"), "", "gcs bucket prefix to store traces in.")
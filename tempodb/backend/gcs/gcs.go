@@ -20,6 +20,7 @@ import (
 	"cloud.google.com/go/storage"
 	"github.com/cristalhq/hedgedhttp"
 	opentracing "github.com/opentracing/opentracing-go"
+	"golang.org/x/oauth2/google"
 	"google.golang.org/api/iterator"
 	"google.golang.org/api/option"
 	google_http "google.golang.org/api/transport/http"
@@ -452,6 +453,10 @@ func (rw *readerWriter) writer(ctx context.Context, name string, conditions *sto
 		w.CacheControl = rw.cfg.ObjectCacheControl
 	}
 
+	if rw.cfg.KMSKeyName != "" {
+		w.KMSKeyName = rw.cfg.KMSKeyName
+	}
+
 	return w
 }
 
@@ -494,6 +499,10 @@ func (rw *readerWriter) readRange(ctx context.Context, name string, offset int64
 }
 
 func createBucket(ctx context.Context, cfg *Config, hedge bool) (*storage.BucketHandle, error) {
+	if cfg.RequesterPays && cfg.RequesterPaysProject == "" {
+		return nil, errors.New("requester_pays_project is required for requester_pays")
+	}
+
 	// start with default transport
 	customTransport := http.DefaultTransport.(*http.Transport).Clone()
 
@@ -504,6 +513,12 @@ func createBucket(ctx context.Context, cfg *Config, hedge bool) (*storage.Bucket
 	if cfg.Insecure {
 		transportOptions = append(transportOptions, option.WithoutAuthentication())
 		customTransport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	} else {
+		authOptions, err := createAuthOptions(cfg)
+		if err != nil {
+			return nil, err
+		}
+		transportOptions = append(transportOptions, authOptions...)
 	}
 	transport, err := google_http.NewTransport(ctx, customTransport, transportOptions...)
 	if err != nil {
@@ -540,7 +555,34 @@ func createBucket(ctx context.Context, cfg *Config, hedge bool) (*storage.Bucket
 	}
 
 	// Build bucket
-	return client.Bucket(cfg.BucketName), nil
+	bucket := client.Bucket(cfg.BucketName)
+	if cfg.RequesterPays {
+		// the project is sent as the userProject parameter of all requests of the bucket and its objects
+		bucket = bucket.UserProject(cfg.RequesterPaysProject)
+	}
+	return bucket, nil
+}
+
+// createAuthOptions returns the options to authenticate with the configured credentials
+func createAuthOptions(cfg *Config) ([]option.ClientOption, error) {
+	switch cfg.AuthType {
+	case "", AuthTypeDefault:
+		if cfg.CredentialsFile != "" {
+			return nil, fmt.Errorf("credentials_file requires auth_type %s", AuthTypeCredentialsFile)
+		}
+		return nil, nil
+	case AuthTypeCredentialsFile:
+		if cfg.CredentialsFile == "" {
+			return nil, fmt.Errorf("credentials_file is required for auth_type %s", AuthTypeCredentialsFile)
+		}
+		// service account keys and workload identity federation configs are both supported
+		return []option.ClientOption{option.WithCredentialsFile(cfg.CredentialsFile)}, nil
+	case AuthTypeWorkloadIdentity:
+		// the service account bound to the pod or the vm is served by the metadata server
+		return []option.ClientOption{option.WithTokenSource(google.ComputeTokenSource("", storage.ScopeReadWrite))}, nil
+	default:
+		return nil, fmt.Errorf("unsupported auth_type %s. valid values are %s, %s and %s", cfg.AuthType, AuthTypeDefault, AuthTypeCredentialsFile, AuthTypeWorkloadIdentity)
+	}
 }
 
 func readError(err error) error {
@@ -12,6 +12,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -178,6 +179,160 @@ func TestRetry_MarkBlockCompacted(t *testing.T) {
 	require.Equal(t, int32(2), atomic.LoadInt32(&count))
 }
 
+func TestCustomerManagedEncryptionKey(t *testing.T) {
+	const kmsKeyName = "projects/tempo/locations/global/keyRings/tempo/cryptoKeys/traces"
+
+	var (
+		mtx  sync.Mutex
+		keys = map[string]string{}
+	)
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mtx.Lock()
+		defer mtx.Unlock()
+
+		switch {
+		case strings.HasPrefix(r.URL.Path, "/upload/storage/v1/b/blerg"):
+			keys["upload"] = r.URL.Query().Get("kmsKeyName")
+			_, _ = w.Write([]byte(`{}`))
+		case strings.Contains(r.URL.Path, "/rewriteTo/"):
+			keys["rewrite"] = r.URL.Query().Get("destinationKmsKeyName")
+			_, _ = w.Write([]byte(`{"done": true}`))
+		default:
+			_, _ = w.Write([]byte(`{}`))
+		}
+	}))
+	server.StartTLS()
+	t.Cleanup(server.Close)
+
+	_, w, c, err := New(&Config{
+		BucketName: "blerg",
+		Insecure:   true,
+		Endpoint:   server.URL,
+		KMSKeyName: kmsKeyName,
+	})
+	require.NoError(t, err)
+
+	// objects and the metas of compacted blocks are encrypted with the key
+	require.NoError(t, w.Write(context.Background(), "object", []string{"test"}, bytes.NewReader([]byte("data")), 4, nil))
+	require.NoError(t, c.MarkBlockCompacted(uuid.New(), "tenant"))
+
+	mtx.Lock()
+	defer mtx.Unlock()
+	assert.Equal(t, map[string]string{"upload": kmsKeyName, "rewrite": kmsKeyName}, keys)
+}
+
+func TestRequesterPays(t *testing.T) {
+	var (
+		mtx      sync.Mutex
+		projects = map[string]string{}
+	)
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mtx.Lock()
+		defer mtx.Unlock()
+
+		project := r.URL.Query().Get("userProject")
+		switch {
+		case strings.HasPrefix(r.URL.Path, "/upload/storage/v1/b/blerg"):
+			projects["upload"] = project
+			_, _ = w.Write([]byte(`{}`))
+		case strings.Contains(r.URL.Path, "/rewriteTo/"):
+			projects["rewrite"] = project
+			_, _ = w.Write([]byte(`{"done": true}`))
+		case strings.HasSuffix(r.URL.Path, "/b/blerg/o"):
+			projects["list"] = project
+			_, _ = w.Write([]byte(`{}`))
+		case r.Method == http.MethodDelete:
+			projects["delete"] = project
+		default:
+			_, _ = w.Write([]byte(`{}`))
+		}
+	}))
+	server.StartTLS()
+	t.Cleanup(server.Close)
+
+	_, _, _, err := New(&Config{
+		BucketName:    "blerg",
+		Insecure:      true,
+		Endpoint:      server.URL,
+		RequesterPays: true,
+	})
+	require.EqualError(t, err, "creating bucket: requester_pays_project is required for requester_pays")
+
+	r, w, c, err := New(&Config{
+		BucketName:           "blerg",
+		Insecure:             true,
+		Endpoint:             server.URL,
+		RequesterPays:        true,
+		RequesterPaysProject: "billing",
+	})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	require.NoError(t, w.Write(ctx, "object", []string{"test"}, bytes.NewReader([]byte("data")), 4, nil))
+	_, err = r.List(ctx, []string{"test"})
+	require.NoError(t, err)
+	require.NoError(t, c.MarkBlockCompacted(uuid.New(), "tenant"))
+	require.NoError(t, w.Delete(ctx, "object", []string{"test"}, nil))
+
+	mtx.Lock()
+	defer mtx.Unlock()
+	assert.Equal(t, map[string]string{"upload": "billing", "rewrite": "billing", "list": "billing", "delete": "billing"}, projects)
+}
+
+func TestAuthOptions(t *testing.T) {
+	tests := []struct {
+		name            string
+		cfg             Config
+		expectedOptions int
+		expectedErr     string
+	}{
+		{
+			name: "application default credentials",
+		},
+		{
+			name: "default",
+			cfg:  Config{AuthType: AuthTypeDefault},
+		},
+		{
+			name:            "credentials file",
+			cfg:             Config{AuthType: AuthTypeCredentialsFile, CredentialsFile: "/var/secrets/gcs.json"},
+			expectedOptions: 1,
+		},
+		{
+			name:            "workload identity",
+			cfg:             Config{AuthType: AuthTypeWorkloadIdentity},
+			expectedOptions: 1,
+		},
+		{
+			name:        "credentials file without path",
+			cfg:         Config{AuthType: AuthTypeCredentialsFile},
+			expectedErr: "credentials_file is required for auth_type credentials_file",
+		},
+		{
+			name:        "path without credentials file",
+			cfg:         Config{CredentialsFile: "/var/secrets/gcs.json"},
+			expectedErr: "credentials_file requires auth_type credentials_file",
+		},
+		{
+			name:        "unsupported",
+			cfg:         Config{AuthType: "api_key"},
+			expectedErr: "unsupported auth_type api_key. valid values are default, credentials_file and workload_identity",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			options, err := createAuthOptions(&tc.cfg)
+			if tc.expectedErr != "" {
+				require.EqualError(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, options, tc.expectedOptions)
+		})
+	}
+}
+
 func fakeServer(t *testing.T, returnIn time.Duration, counter *int32) *httptest.Server {
 	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		time.Sleep(returnIn)
@@ -2,6 +2,7 @@ package local
 
 import (
 	"context"
+	"errors"
 	"io"
 	"io/fs"
 	"os"
@@ -136,6 +137,10 @@ func (rw *Backend) List(ctx context.Context, keypath backend.KeyPath) ([]string,
 
 	path := rw.rootPath(keypath)
 	folders, err := os.ReadDir(path)
+	// missing folders are empty like in the object stores
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -186,6 +191,10 @@ func (rw *Backend) ListBlocks(_ context.Context, tenant string) (metas []uuid.UU
 
 		return nil
 	})
+	// missing tenants have no blocks like in the object stores
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil, nil
+	}
 
 	return
 }
@@ -218,6 +227,9 @@ func (rw *Backend) Find(_ context.Context, keypath backend.KeyPath, f backend.Fi
 
 		return nil
 	})
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
 
 	return
 }
@@ -0,0 +1,224 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"golang.org/x/time/rate"
+
+	"github.com/grafana/tempo/pkg/util"
+	"github.com/grafana/tempo/tempodb/backend"
+)
+
+var metricQueueTime = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	Namespace: "tempodb",
+	Name:      "backend_metadata_queue_time_seconds",
+	Help:      "Time metadata operations waited for the backend rate limiter.",
+	Buckets:   prometheus.ExponentialBuckets(0.001, 4, 8),
+}, []string{"operation"})
+
+var metricUploadThrottledTime = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "tempodb",
+	Name:      "backend_upload_throttled_seconds_total",
+	Help:      "Total time writes and appends waited for the upload bandwidth limiter.",
+}, []string{"operation"})
+
+type Config struct {
+	// MetadataOpsPerSecond is the rate of the LIST and attribute requests sent to the backend by this process.
+	// 0 disables the limit
+	MetadataOpsPerSecond float64 `yaml:"metadata_ops_per_second"`
+	// MetadataBurst is the number of metadata requests that can be sent at once
+	MetadataBurst int `yaml:"metadata_burst"`
+	// UploadBytesPerSecond is the bandwidth of the writes and appends of objects to the backend by this process.
+	// 0 disables the limit
+	UploadBytesPerSecond int `yaml:"upload_bytes_per_second"`
+	// UploadBurstBytes is the number of bytes that can be uploaded at once
+	UploadBurstBytes int `yaml:"upload_burst_bytes"`
+}
+
+func (cfg *Config) RegisterFlagsAndApplyDefaults(prefix string, f *flag.FlagSet) {
+	f.Float64Var(&cfg.MetadataOpsPerSecond, util.PrefixConfig(prefix, "rate-limit.metadata-ops-per-second"), 0, "Rate of the LIST and attribute requests sent to the backend. 0 disables the limit.")
+	f.IntVar(&cfg.MetadataBurst, util.PrefixConfig(prefix, "rate-limit.metadata-burst"), 10, "Number of LIST and attribute requests that can be sent to the backend at once.")
+	f.IntVar(&cfg.UploadBytesPerSecond, util.PrefixConfig(prefix, "rate-limit.upload-bytes-per-second"), 0, "Bandwidth of the uploads of objects to the backend in bytes per second. 0 disables the limit.")
+	f.IntVar(&cfg.UploadBurstBytes, util.PrefixConfig(prefix, "rate-limit.upload-burst-bytes"), 1024*1024, "Number of bytes that can be uploaded to the backend at once.")
+}
+
+func (cfg *Config) Enabled() bool {
+	return cfg.MetadataOpsPerSecond > 0 || cfg.UploadBytesPerSecond > 0
+}
+
+func (cfg *Config) Validate() error {
+	if cfg.MetadataOpsPerSecond < 0 {
+		return errors.New("metadata_ops_per_second must not be negative")
+	}
+	if cfg.MetadataOpsPerSecond > 0 && cfg.MetadataBurst < 1 {
+		return errors.New("metadata_burst must be at least 1")
+	}
+	if cfg.UploadBytesPerSecond < 0 {
+		return errors.New("upload_bytes_per_second must not be negative")
+	}
+	if cfg.UploadBytesPerSecond > 0 && cfg.UploadBurstBytes < 1 {
+		return errors.New("upload_burst_bytes must be at least 1")
+	}
+	return nil
+}
+
+type readerWriter struct {
+	// limiters are nil if their limit is disabled
+	limiter       *rate.Limiter
+	uploadLimiter *rate.Limiter
+
+	nextReader    backend.RawReader
+	nextWriter    backend.RawWriter
+	nextCompactor backend.Compactor
+}
+
+var (
+	_ backend.RawReader = (*readerWriter)(nil)
+	_ backend.RawWriter = (*readerWriter)(nil)
+	_ backend.Compactor = (*readerWriter)(nil)
+)
+
+// New wraps a backend to limit the rate of the metadata operations: the listing of objects and blocks and the
+// reading of object attributes. These are mostly sent by the blocklist polling and are limited so they stay within
+// the request quota of the bucket and don't cause the writes to be throttled. The bandwidth of the writes and appends
+// of objects is limited separately so the uploads of the flushes and compactions don't saturate the network of the
+// node. Reads of objects are not limited.
+func New(cfg *Config, nextReader backend.RawReader, nextWriter backend.RawWriter, nextCompactor backend.Compactor) (backend.RawReader, backend.RawWriter, backend.Compactor) {
+	rw := &readerWriter{
+		nextReader:    nextReader,
+		nextWriter:    nextWriter,
+		nextCompactor: nextCompactor,
+	}
+	if cfg.MetadataOpsPerSecond > 0 {
+		rw.limiter = rate.NewLimiter(rate.Limit(cfg.MetadataOpsPerSecond), cfg.MetadataBurst)
+	}
+	if cfg.UploadBytesPerSecond > 0 {
+		rw.uploadLimiter = rate.NewLimiter(rate.Limit(cfg.UploadBytesPerSecond), cfg.UploadBurstBytes)
+	}
+	return rw, rw, rw
+}
+
+// List implements backend.RawReader
+func (r *readerWriter) List(ctx context.Context, keypath backend.KeyPath) ([]string, error) {
+	if err := r.wait(ctx, "List"); err != nil {
+		return nil, err
+	}
+	return r.nextReader.List(ctx, keypath)
+}
+
+// ListBlocks implements backend.RawReader
+func (r *readerWriter) ListBlocks(ctx context.Context, tenant string) ([]uuid.UUID, []uuid.UUID, error) {
+	if err := r.wait(ctx, "ListBlocks"); err != nil {
+		return nil, nil, err
+	}
+	return r.nextReader.ListBlocks(ctx, tenant)
+}
+
+// Find implements backend.RawReader
+func (r *readerWriter) Find(ctx context.Context, keypath backend.KeyPath, f backend.FindFunc) error {
+	if err := r.wait(ctx, "Find"); err != nil {
+		return err
+	}
+	return r.nextReader.Find(ctx, keypath, f)
+}
+
+// Read implements backend.RawReader
+func (r *readerWriter) Read(ctx context.Context, name string, keypath backend.KeyPath, cacheInfo *backend.CacheInfo) (io.ReadCloser, int64, error) {
+	return r.nextReader.Read(ctx, name, keypath, cacheInfo)
+}
+
+// ReadRange implements backend.RawReader
+func (r *readerWriter) ReadRange(ctx context.Context, name string, keypath backend.KeyPath, offset uint64, buffer []byte, cacheInfo *backend.CacheInfo) error {
+	return r.nextReader.ReadRange(ctx, name, keypath, offset, buffer, cacheInfo)
+}
+
+// Shutdown implements backend.RawReader
+func (r *readerWriter) Shutdown() {
+	r.nextReader.Shutdown()
+}
+
+// Write implements backend.RawWriter. The data is limited while it is read by the backend.
+func (r *readerWriter) Write(ctx context.Context, name string, keypath backend.KeyPath, data io.Reader, size int64, cacheInfo *backend.CacheInfo) error {
+	if r.uploadLimiter != nil {
+		data = newLimitedReader(ctx, data, func(ctx context.Context, n int) error {
+			return r.waitUpload(ctx, "Write", n)
+		})
+	}
+	return r.nextWriter.Write(ctx, name, keypath, data, size, cacheInfo)
+}
+
+// Append implements backend.RawWriter
+func (r *readerWriter) Append(ctx context.Context, name string, keypath backend.KeyPath, tracker backend.AppendTracker, buffer []byte) (backend.AppendTracker, error) {
+	if err := r.waitUpload(ctx, "Append", len(buffer)); err != nil {
+		return nil, err
+	}
+	return r.nextWriter.Append(ctx, name, keypath, tracker, buffer)
+}
+
+// CloseAppend implements backend.RawWriter
+func (r *readerWriter) CloseAppend(ctx context.Context, tracker backend.AppendTracker) error {
+	return r.nextWriter.CloseAppend(ctx, tracker)
+}
+
+// Delete implements backend.RawWriter
+func (r *readerWriter) Delete(ctx context.Context, name string, keypath backend.KeyPath, cacheInfo *backend.CacheInfo) error {
+	return r.nextWriter.Delete(ctx, name, keypath, cacheInfo)
+}
+
+// MarkBlockCompacted implements backend.Compactor
+func (r *readerWriter) MarkBlockCompacted(blockID uuid.UUID, tenantID string) error {
+	return r.nextCompactor.MarkBlockCompacted(blockID, tenantID)
+}
+
+// ClearBlock implements backend.Compactor
+func (r *readerWriter) ClearBlock(blockID uuid.UUID, tenantID string) error {
+	return r.nextCompactor.ClearBlock(blockID, tenantID)
+}
+
+// CompactedBlockMeta implements backend.Compactor. The compacted time is read from the attributes of the object by
+// most backends.
+func (r *readerWriter) CompactedBlockMeta(blockID uuid.UUID, tenantID string) (*backend.CompactedBlockMeta, error) {
+	if err := r.wait(context.TODO(), "CompactedBlockMeta"); err != nil {
+		return nil, err
+	}
+	return r.nextCompactor.CompactedBlockMeta(blockID, tenantID)
+}
+
+// wait blocks until the limiter allows the operation and records the time it waited
+func (r *readerWriter) wait(ctx context.Context, operation string) error {
+	if r.limiter == nil {
+		return nil
+	}
+
+	start := time.Now()
+	err := r.limiter.Wait(ctx)
+	metricQueueTime.WithLabelValues(operation).Observe(time.Since(start).Seconds())
+	return err
+}
+
+// waitUpload blocks until the upload limiter allows n bytes and records the time it waited. n is split into waits of
+// at most the burst
+func (r *readerWriter) waitUpload(ctx context.Context, operation string, n int) error {
+	if r.uploadLimiter == nil {
+		return nil
+	}
+
+	start := time.Now()
+	defer func() { metricUploadThrottledTime.WithLabelValues(operation).Add(time.Since(start).Seconds()) }()
+
+	for n > 0 {
+		chunk := min(n, r.uploadLimiter.Burst())
+		if err := r.uploadLimiter.WaitN(ctx, chunk); err != nil {
+			return err
+		}
+		n -= chunk
+	}
+	return nil
+}
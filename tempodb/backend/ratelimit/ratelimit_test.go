@@ -0,0 +1,129 @@
+package ratelimit
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/tempodb/backend"
+)
+
+func TestRateLimitMetadataOperations(t *testing.T) {
+	next := &backend.MockRawReader{L: []string{"object"}}
+	r, _, _ := New(&Config{MetadataOpsPerSecond: 20, MetadataBurst: 1}, next, &backend.MockRawWriter{}, &backend.MockCompactor{})
+
+	before := testutil.CollectAndCount(metricQueueTime)
+
+	// the first list uses the burst, the others wait for the limiter
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		objects, err := r.List(context.Background(), backend.KeyPath{"test"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"object"}, objects)
+	}
+	assert.GreaterOrEqual(t, time.Since(start), 90*time.Millisecond)
+	assert.Equal(t, before+1, testutil.CollectAndCount(metricQueueTime))
+
+	// reads are not limited
+	start = time.Now()
+	for i := 0; i < 3; i++ {
+		require.NoError(t, r.ReadRange(context.Background(), "object", backend.KeyPath{"test"}, 0, nil, nil))
+	}
+	assert.Less(t, time.Since(start), 40*time.Millisecond)
+}
+
+func TestRateLimitCanceled(t *testing.T) {
+	next := &backend.MockRawReader{}
+	r, _, _ := New(&Config{MetadataOpsPerSecond: 0.001, MetadataBurst: 1}, next, &backend.MockRawWriter{}, &backend.MockCompactor{})
+
+	_, _, err := r.ListBlocks(context.Background(), "tenant")
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, _, err = r.ListBlocks(ctx, "tenant")
+	require.Error(t, err)
+}
+
+func TestRateLimitUploads(t *testing.T) {
+	next := &captureWriter{}
+	_, w, _ := New(&Config{UploadBytesPerSecond: 1000, UploadBurstBytes: 100}, &backend.MockRawReader{}, next, &backend.MockCompactor{})
+
+	before := testutil.ToFloat64(metricUploadThrottledTime.WithLabelValues("Write"))
+
+	// the first 100 bytes use the burst, the others wait for the limiter
+	data := bytes.Repeat([]byte("a"), 200)
+	start := time.Now()
+	require.NoError(t, w.Write(context.Background(), "object", backend.KeyPath{"test"}, bytes.NewReader(data), int64(len(data)), nil))
+	assert.GreaterOrEqual(t, time.Since(start), 90*time.Millisecond)
+	assert.Equal(t, data, next.data)
+	assert.Greater(t, testutil.ToFloat64(metricUploadThrottledTime.WithLabelValues("Write")), before)
+
+	// appends larger than the burst wait in chunks of the burst
+	start = time.Now()
+	_, err := w.Append(context.Background(), "object", backend.KeyPath{"test"}, nil, data)
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, time.Since(start), 190*time.Millisecond)
+
+	// canceled uploads fail
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = w.Append(ctx, "object", backend.KeyPath{"test"}, nil, data)
+	require.Error(t, err)
+}
+
+type captureWriter struct {
+	backend.MockRawWriter
+	data []byte
+}
+
+func (w *captureWriter) Write(_ context.Context, _ string, _ backend.KeyPath, data io.Reader, _ int64, _ *backend.CacheInfo) error {
+	var err error
+	w.data, err = io.ReadAll(data)
+	return err
+}
+
+func TestRateLimitUploadsSeekable(t *testing.T) {
+	data := bytes.NewReader([]byte("data"))
+	r := newLimitedReader(context.Background(), data, func(context.Context, int) error { return nil })
+
+	seeker, ok := r.(io.Seeker)
+	require.True(t, ok)
+
+	_, err := io.ReadAll(r)
+	require.NoError(t, err)
+	_, err = seeker.Seek(0, io.SeekStart)
+	require.NoError(t, err)
+
+	actual, err := io.ReadAll(r)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("data"), actual)
+}
+
+func TestConfigDefaults(t *testing.T) {
+	cfg := Config{}
+	cfg.RegisterFlagsAndApplyDefaults("", flag.NewFlagSet("", flag.PanicOnError))
+
+	assert.False(t, cfg.Enabled())
+	require.NoError(t, cfg.Validate())
+
+	cfg.MetadataOpsPerSecond = 10
+	cfg.MetadataBurst = 0
+	require.EqualError(t, cfg.Validate(), "metadata_burst must be at least 1")
+
+	cfg = Config{}
+	cfg.RegisterFlagsAndApplyDefaults("", flag.NewFlagSet("", flag.PanicOnError))
+	cfg.UploadBytesPerSecond = 1024
+	assert.True(t, cfg.Enabled())
+	require.NoError(t, cfg.Validate())
+
+	cfg.UploadBurstBytes = 0
+	require.EqualError(t, cfg.Validate(), "upload_burst_bytes must be at least 1")
+}
@@ -0,0 +1,43 @@
+package ratelimit
+
+import (
+	"context"
+	"io"
+)
+
+// limitedReader waits for the limiter before it returns the data read
+type limitedReader struct {
+	ctx  context.Context
+	r    io.Reader
+	wait func(ctx context.Context, n int) error
+}
+
+// seekableLimitedReader is a limitedReader of data that can be rewound, so writes of the object can be retried
+type seekableLimitedReader struct {
+	limitedReader
+	seeker io.Seeker
+}
+
+func newLimitedReader(ctx context.Context, r io.Reader, wait func(ctx context.Context, n int) error) io.Reader {
+	lr := limitedReader{ctx: ctx, r: r, wait: wait}
+
+	if seeker, ok := r.(io.Seeker); ok {
+		return &seekableLimitedReader{limitedReader: lr, seeker: seeker}
+	}
+	return &lr
+}
+
+func (r *limitedReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	if n > 0 {
+		if waitErr := r.wait(r.ctx, n); waitErr != nil {
+			return 0, waitErr
+		}
+	}
+	return n, err
+}
+
+// Seek implements io.Seeker
+func (r *seekableLimitedReader) Seek(offset int64, whence int) (int64, error) {
+	return r.seeker.Seek(offset, whence)
+}
@@ -20,6 +20,10 @@ const (
 	TenantIndexName   = "index.json.gz"
 	// File name for the cluster seed file.
 	ClusterSeedFileName = "tempo_cluster_seed.json"
+	// Folder for the deletion marks of the tenants. It is not a tenant.
+	TenantDeletionsKeyPath = "tenant-deletions"
+	// Folder for the marks of the tenants that exceed their storage quota. It is not a tenant.
+	StorageQuotasKeyPath = "storage-quotas"
 )
 
 // KeyPath is an ordered set of strings that govern where data is read/written
@@ -121,10 +125,13 @@ func (w *writer) WriteTenantIndex(ctx context.Context, tenantID string, meta []*
 	if len(meta) == 0 && len(compactedMeta) == 0 {
 		// Skip returning an error when the object is already deleted.
 		err := w.w.Delete(ctx, TenantIndexName, []string{tenantID}, nil)
-		if err != nil && !errors.Is(err, ErrDoesNotExist) {
+		if err != nil && !errors.Is(err, ErrDoesNotExist) && !errors.Is(err, ErrObjectLocked) {
 			return err
 		}
-		return nil
+		// an index that can't be deleted is written empty
+		if !errors.Is(err, ErrObjectLocked) {
+			return nil
+		}
 	}
 
 	b := newTenantIndex(meta, compactedMeta)
@@ -185,7 +192,7 @@ func (r *reader) Tenants(ctx context.Context) ([]string, error) {
 	// this filter is added to fix a GCS usage stats issue that would result in ""
 	var filteredList []string
 	for _, tenant := range list {
-		if tenant != "" && tenant != ClusterSeedFileName {
+		if tenant != "" && tenant != ClusterSeedFileName && tenant != TenantDeletionsKeyPath && tenant != StorageQuotasKeyPath {
 			filteredList = append(filteredList, tenant)
 		}
 	}
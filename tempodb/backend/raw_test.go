@@ -65,6 +65,18 @@ func TestWriter(t *testing.T) {
 	w = NewWriter(m)
 	err = w.WriteTenantIndex(ctx, "test", nil, nil)
 	assert.NoError(t, err)
+
+	// When the tenant index is locked, it is written empty
+	m = &MockRawWriter{err: ErrObjectLocked}
+	w = NewWriter(m)
+	err = w.WriteTenantIndex(ctx, "test", nil, nil)
+	assert.NoError(t, err)
+
+	idx = &TenantIndex{}
+	err = idx.unmarshal(m.writeBuffer)
+	assert.NoError(t, err)
+	assert.Empty(t, idx.Meta)
+	assert.Empty(t, idx.CompactedMeta)
 }
 
 func TestReader(t *testing.T) {
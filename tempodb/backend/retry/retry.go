@@ -0,0 +1,272 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/google/uuid"
+	"github.com/grafana/dskit/backoff"
+	"github.com/minio/minio-go/v7"
+	"github.com/ncw/swift/v2"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"google.golang.org/api/googleapi"
+
+	"github.com/grafana/tempo/pkg/util"
+	"github.com/grafana/tempo/tempodb/backend"
+)
+
+var metricRetries = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "tempodb",
+	Name:      "backend_retries_total",
+	Help:      "Total number of backend operations retried after a transient error.",
+}, []string{"operation"})
+
+type Config struct {
+	// MaxAttempts is the number of times an operation is tried. 1 disables retries
+	MaxAttempts int           `yaml:"max_attempts"`
+	MinBackoff  time.Duration `yaml:"min_backoff"`
+	MaxBackoff  time.Duration `yaml:"max_backoff"`
+	// RetryableStatusCodes are the http status codes of the object store that are retried. Errors without a status
+	// code are retried if they are network errors
+	RetryableStatusCodes []int `yaml:"retryable_status_codes"`
+}
+
+func (cfg *Config) RegisterFlagsAndApplyDefaults(prefix string, f *flag.FlagSet) {
+	f.IntVar(&cfg.MaxAttempts, util.PrefixConfig(prefix, "retry.max-attempts"), 1, "Number of times a backend operation is tried. 1 disables retries.")
+	f.DurationVar(&cfg.MinBackoff, util.PrefixConfig(prefix, "retry.min-backoff"), 100*time.Millisecond, "Minimum backoff between retries of a backend operation.")
+	f.DurationVar(&cfg.MaxBackoff, util.PrefixConfig(prefix, "retry.max-backoff"), 3*time.Second, "Maximum backoff between retries of a backend operation.")
+	cfg.RetryableStatusCodes = []int{
+		http.StatusTooManyRequests,
+		http.StatusInternalServerError,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout,
+	}
+}
+
+func (cfg *Config) Enabled() bool {
+	return cfg.MaxAttempts > 1
+}
+
+func (cfg *Config) Validate() error {
+	if cfg.MaxAttempts < 0 {
+		return errors.New("max_attempts must not be negative")
+	}
+	if cfg.MinBackoff > cfg.MaxBackoff {
+		return fmt.Errorf("min_backoff %s must not be greater than max_backoff %s", cfg.MinBackoff, cfg.MaxBackoff)
+	}
+	return nil
+}
+
+type readerWriter struct {
+	cfg *Config
+
+	nextReader    backend.RawReader
+	nextWriter    backend.RawWriter
+	nextCompactor backend.Compactor
+}
+
+var (
+	_ backend.RawReader = (*readerWriter)(nil)
+	_ backend.RawWriter = (*readerWriter)(nil)
+	_ backend.Compactor = (*readerWriter)(nil)
+)
+
+// New wraps a backend to retry the operations that fail with a transient error. Appends are not retried because
+// the parts already uploaded are tracked by the backend.
+func New(cfg *Config, nextReader backend.RawReader, nextWriter backend.RawWriter, nextCompactor backend.Compactor) (backend.RawReader, backend.RawWriter, backend.Compactor) {
+	rw := &readerWriter{
+		cfg:           cfg,
+		nextReader:    nextReader,
+		nextWriter:    nextWriter,
+		nextCompactor: nextCompactor,
+	}
+	return rw, rw, rw
+}
+
+// List implements backend.RawReader
+func (r *readerWriter) List(ctx context.Context, keypath backend.KeyPath) (objects []string, err error) {
+	err = r.retry(ctx, "List", func() error {
+		objects, err = r.nextReader.List(ctx, keypath)
+		return err
+	})
+	return objects, err
+}
+
+// ListBlocks implements backend.RawReader
+func (r *readerWriter) ListBlocks(ctx context.Context, tenant string) (blockIDs []uuid.UUID, compactedBlockIDs []uuid.UUID, err error) {
+	err = r.retry(ctx, "ListBlocks", func() error {
+		blockIDs, compactedBlockIDs, err = r.nextReader.ListBlocks(ctx, tenant)
+		return err
+	})
+	return blockIDs, compactedBlockIDs, err
+}
+
+// Find implements backend.RawReader. Objects found by a failed attempt are passed to f again.
+func (r *readerWriter) Find(ctx context.Context, keypath backend.KeyPath, f backend.FindFunc) error {
+	return r.retry(ctx, "Find", func() error {
+		return r.nextReader.Find(ctx, keypath, f)
+	})
+}
+
+// Read implements backend.RawReader. Only opening the object is retried.
+func (r *readerWriter) Read(ctx context.Context, name string, keypath backend.KeyPath, cacheInfo *backend.CacheInfo) (rc io.ReadCloser, size int64, err error) {
+	err = r.retry(ctx, "Read", func() error {
+		rc, size, err = r.nextReader.Read(ctx, name, keypath, cacheInfo)
+		return err
+	})
+	return rc, size, err
+}
+
+// ReadRange implements backend.RawReader
+func (r *readerWriter) ReadRange(ctx context.Context, name string, keypath backend.KeyPath, offset uint64, buffer []byte, cacheInfo *backend.CacheInfo) error {
+	return r.retry(ctx, "ReadRange", func() error {
+		return r.nextReader.ReadRange(ctx, name, keypath, offset, buffer, cacheInfo)
+	})
+}
+
+// Shutdown implements backend.RawReader
+func (r *readerWriter) Shutdown() {
+	r.nextReader.Shutdown()
+}
+
+// Write implements backend.RawWriter. The data is rewound before each retry, data that can't be rewound is written
+// once.
+func (r *readerWriter) Write(ctx context.Context, name string, keypath backend.KeyPath, data io.Reader, size int64, cacheInfo *backend.CacheInfo) error {
+	seeker, ok := data.(io.Seeker)
+	if !ok {
+		return r.nextWriter.Write(ctx, name, keypath, data, size, cacheInfo)
+	}
+
+	start, err := seeker.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return r.nextWriter.Write(ctx, name, keypath, data, size, cacheInfo)
+	}
+
+	first := true
+	return r.retry(ctx, "Write", func() error {
+		if !first {
+			if _, err := seeker.Seek(start, io.SeekStart); err != nil {
+				return fmt.Errorf("rewinding data: %w", err)
+			}
+		}
+		first = false
+
+		return r.nextWriter.Write(ctx, name, keypath, data, size, cacheInfo)
+	})
+}
+
+// Append implements backend.RawWriter
+func (r *readerWriter) Append(ctx context.Context, name string, keypath backend.KeyPath, tracker backend.AppendTracker, buffer []byte) (backend.AppendTracker, error) {
+	return r.nextWriter.Append(ctx, name, keypath, tracker, buffer)
+}
+
+// CloseAppend implements backend.RawWriter
+func (r *readerWriter) CloseAppend(ctx context.Context, tracker backend.AppendTracker) error {
+	return r.nextWriter.CloseAppend(ctx, tracker)
+}
+
+// Delete implements backend.RawWriter
+func (r *readerWriter) Delete(ctx context.Context, name string, keypath backend.KeyPath, cacheInfo *backend.CacheInfo) error {
+	return r.retry(ctx, "Delete", func() error {
+		return r.nextWriter.Delete(ctx, name, keypath, cacheInfo)
+	})
+}
+
+// MarkBlockCompacted implements backend.Compactor
+func (r *readerWriter) MarkBlockCompacted(blockID uuid.UUID, tenantID string) error {
+	return r.retry(context.TODO(), "MarkBlockCompacted", func() error {
+		return r.nextCompactor.MarkBlockCompacted(blockID, tenantID)
+	})
+}
+
+// ClearBlock implements backend.Compactor
+func (r *readerWriter) ClearBlock(blockID uuid.UUID, tenantID string) error {
+	return r.retry(context.TODO(), "ClearBlock", func() error {
+		return r.nextCompactor.ClearBlock(blockID, tenantID)
+	})
+}
+
+// CompactedBlockMeta implements backend.Compactor
+func (r *readerWriter) CompactedBlockMeta(blockID uuid.UUID, tenantID string) (meta *backend.CompactedBlockMeta, err error) {
+	err = r.retry(context.TODO(), "CompactedBlockMeta", func() error {
+		meta, err = r.nextCompactor.CompactedBlockMeta(blockID, tenantID)
+		return err
+	})
+	return meta, err
+}
+
+// retry calls f until it succeeds, fails with an error that isn't transient or the attempts are exhausted
+func (r *readerWriter) retry(ctx context.Context, operation string, f func() error) error {
+	b := backoff.New(ctx, backoff.Config{
+		MinBackoff: r.cfg.MinBackoff,
+		MaxBackoff: r.cfg.MaxBackoff,
+		MaxRetries: r.cfg.MaxAttempts,
+	})
+
+	for {
+		err := f()
+		if err == nil || !r.retryable(err) {
+			return err
+		}
+
+		b.Wait()
+		if !b.Ongoing() {
+			return err
+		}
+		metricRetries.WithLabelValues(operation).Inc()
+	}
+}
+
+func (r *readerWriter) retryable(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	if code, ok := statusCode(err); ok {
+		return slices.Contains(r.cfg.RetryableStatusCodes, code)
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// statusCode returns the http status code of an error returned by the object store clients
+func statusCode(err error) (int, bool) {
+	var minioErr minio.ErrorResponse
+	if errors.As(err, &minioErr) && minioErr.StatusCode != 0 {
+		return minioErr.StatusCode, true
+	}
+
+	var gcsErr *googleapi.Error
+	if errors.As(err, &gcsErr) {
+		return gcsErr.Code, true
+	}
+
+	var azureErr *azcore.ResponseError
+	if errors.As(err, &azureErr) {
+		return azureErr.StatusCode, true
+	}
+
+	// the errors of the legacy azure client expose the response
+	var responseErr interface{ Response() *http.Response }
+	if errors.As(err, &responseErr) && responseErr.Response() != nil {
+		return responseErr.Response().StatusCode, true
+	}
+
+	var swiftErr *swift.Error
+	if errors.As(err, &swiftErr) && swiftErr.StatusCode != 0 {
+		return swiftErr.StatusCode, true
+	}
+
+	return 0, false
+}
@@ -0,0 +1,184 @@
+package retry
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/google/uuid"
+	"github.com/minio/minio-go/v7"
+	"github.com/ncw/swift/v2"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/api/googleapi"
+
+	"github.com/grafana/tempo/tempodb/backend"
+)
+
+func TestRetryableErrors(t *testing.T) {
+	tests := []struct {
+		name          string
+		err           error
+		expectedCalls int
+	}{
+		{
+			name:          "s3 503",
+			err:           minio.ErrorResponse{StatusCode: http.StatusServiceUnavailable},
+			expectedCalls: 2,
+		},
+		{
+			name:          "s3 404",
+			err:           minio.ErrorResponse{StatusCode: http.StatusNotFound},
+			expectedCalls: 1,
+		},
+		{
+			name:          "gcs 429",
+			err:           fmt.Errorf("wrapped: %w", &googleapi.Error{Code: http.StatusTooManyRequests}),
+			expectedCalls: 2,
+		},
+		{
+			name:          "gcs 412",
+			err:           &googleapi.Error{Code: http.StatusPreconditionFailed},
+			expectedCalls: 1,
+		},
+		{
+			name:          "azure 502",
+			err:           &azcore.ResponseError{StatusCode: http.StatusBadGateway},
+			expectedCalls: 2,
+		},
+		{
+			name:          "swift 500",
+			err:           &swift.Error{StatusCode: http.StatusInternalServerError},
+			expectedCalls: 2,
+		},
+		{
+			name:          "network error",
+			err:           &net.OpError{Op: "dial", Err: errors.New("connection refused")},
+			expectedCalls: 2,
+		},
+		{
+			name:          "does not exist",
+			err:           backend.ErrDoesNotExist,
+			expectedCalls: 1,
+		},
+		{
+			name:          "context canceled",
+			err:           context.Canceled,
+			expectedCalls: 1,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			next := &flakyBackend{err: tc.err, failures: 1}
+			r, _, _ := New(testConfig(3), next, next, next)
+
+			err := r.ReadRange(context.Background(), "object", backend.KeyPath{"test"}, 0, make([]byte, 1), nil)
+			if tc.expectedCalls == 1 {
+				require.ErrorIs(t, err, tc.err)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tc.expectedCalls, next.calls)
+		})
+	}
+}
+
+func TestRetryMaxAttempts(t *testing.T) {
+	next := &flakyBackend{err: &googleapi.Error{Code: http.StatusServiceUnavailable}, failures: 10}
+	_, _, c := New(testConfig(3), next, next, next)
+
+	before := testutil.ToFloat64(metricRetries.WithLabelValues("MarkBlockCompacted"))
+
+	err := c.MarkBlockCompacted(uuid.New(), "tenant")
+	require.ErrorIs(t, err, next.err)
+	assert.Equal(t, 3, next.calls)
+	assert.Equal(t, 2.0, testutil.ToFloat64(metricRetries.WithLabelValues("MarkBlockCompacted"))-before)
+}
+
+func TestRetryWriteRewindsData(t *testing.T) {
+	next := &flakyBackend{err: minio.ErrorResponse{StatusCode: http.StatusInternalServerError}, failures: 1}
+	_, w, _ := New(testConfig(3), next, next, next)
+
+	data := bytes.NewReader([]byte("data"))
+	require.NoError(t, w.Write(context.Background(), "object", backend.KeyPath{"test"}, data, 4, nil))
+	assert.Equal(t, 2, next.calls)
+	assert.Equal(t, []byte("data"), next.written)
+
+	// readers that can't be rewound are written once
+	next = &flakyBackend{err: minio.ErrorResponse{StatusCode: http.StatusInternalServerError}, failures: 1}
+	_, w, _ = New(testConfig(3), next, next, next)
+
+	err := w.Write(context.Background(), "object", backend.KeyPath{"test"}, io.LimitReader(bytes.NewReader([]byte("data")), 4), 4, nil)
+	require.Error(t, err)
+	assert.Equal(t, 1, next.calls)
+}
+
+func TestConfigDefaults(t *testing.T) {
+	cfg := Config{}
+	cfg.RegisterFlagsAndApplyDefaults("", flag.NewFlagSet("", flag.PanicOnError))
+
+	assert.False(t, cfg.Enabled())
+	require.NoError(t, cfg.Validate())
+
+	cfg.MinBackoff = time.Minute
+	require.EqualError(t, cfg.Validate(), "min_backoff 1m0s must not be greater than max_backoff 3s")
+}
+
+func testConfig(maxAttempts int) *Config {
+	cfg := &Config{}
+	cfg.RegisterFlagsAndApplyDefaults("", flag.NewFlagSet("", flag.PanicOnError))
+	cfg.MaxAttempts = maxAttempts
+	cfg.MinBackoff = time.Millisecond
+	cfg.MaxBackoff = time.Millisecond
+	return cfg
+}
+
+// flakyBackend fails the first calls with err
+type flakyBackend struct {
+	backend.MockRawReader
+	backend.MockRawWriter
+	backend.MockCompactor
+
+	err      error
+	failures int
+	calls    int
+	written  []byte
+}
+
+func (f *flakyBackend) fail() error {
+	f.calls++
+	if f.calls <= f.failures {
+		return f.err
+	}
+	return nil
+}
+
+func (f *flakyBackend) ReadRange(context.Context, string, backend.KeyPath, uint64, []byte, *backend.CacheInfo) error {
+	return f.fail()
+}
+
+func (f *flakyBackend) Write(_ context.Context, _ string, _ backend.KeyPath, data io.Reader, _ int64, _ *backend.CacheInfo) error {
+	b, err := io.ReadAll(data)
+	if err != nil {
+		return err
+	}
+	if err := f.fail(); err != nil {
+		return err
+	}
+	f.written = b
+	return nil
+}
+
+func (f *flakyBackend) MarkBlockCompacted(uuid.UUID, string) error {
+	return f.fail()
+}
@@ -0,0 +1,172 @@
+package routing
+
+import (
+	"context"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/grafana/tempo/tempodb/backend"
+)
+
+// Backend is a backend that stores the data of a tenant
+type Backend struct {
+	Reader    backend.RawReader
+	Writer    backend.RawWriter
+	Compactor backend.Compactor
+}
+
+type readerWriter struct {
+	next    Backend
+	tenants map[string]Backend
+}
+
+var (
+	_ backend.RawReader = (*readerWriter)(nil)
+	_ backend.RawWriter = (*readerWriter)(nil)
+	_ backend.Compactor = (*readerWriter)(nil)
+)
+
+// New returns a backend that sends the operations on the data of the tenants in tenants to their own backend and the
+// operations of all other tenants to next. The tenants are routed by the first path segment of the keypath, so a
+// keypath of {"<tenant>/<block>/"} is routed like {"<tenant>", "<block>"}. Listing the root of the backend returns the
+// tenants of next and the routed tenants that have data in their backend.
+func New(next Backend, tenants map[string]Backend) (backend.RawReader, backend.RawWriter, backend.Compactor) {
+	rw := &readerWriter{
+		next:    next,
+		tenants: tenants,
+	}
+	return rw, rw, rw
+}
+
+// List implements backend.RawReader
+func (r *readerWriter) List(ctx context.Context, keypath backend.KeyPath) ([]string, error) {
+	if len(keypath) > 0 {
+		return r.backendForKeypath(keypath).Reader.List(ctx, keypath)
+	}
+
+	objects, err := r.next.Reader.List(ctx, keypath)
+	if err != nil {
+		return nil, err
+	}
+
+	// the data of routed tenants left in the default backend is not read
+	objects = slices.DeleteFunc(objects, func(o string) bool {
+		_, ok := r.tenants[o]
+		return ok
+	})
+
+	for tenant, b := range r.tenants {
+		tenantObjects, err := b.Reader.List(ctx, keypath)
+		if err != nil {
+			return nil, err
+		}
+		if slices.Contains(tenantObjects, tenant) {
+			objects = append(objects, tenant)
+		}
+	}
+
+	return objects, nil
+}
+
+// ListBlocks implements backend.RawReader
+func (r *readerWriter) ListBlocks(ctx context.Context, tenant string) ([]uuid.UUID, []uuid.UUID, error) {
+	return r.backendFor(tenant).Reader.ListBlocks(ctx, tenant)
+}
+
+// Find implements backend.RawReader
+func (r *readerWriter) Find(ctx context.Context, keypath backend.KeyPath, f backend.FindFunc) error {
+	return r.backendForKeypath(keypath).Reader.Find(ctx, keypath, f)
+}
+
+// Read implements backend.RawReader
+func (r *readerWriter) Read(ctx context.Context, name string, keypath backend.KeyPath, cacheInfo *backend.CacheInfo) (io.ReadCloser, int64, error) {
+	return r.backendForKeypath(keypath).Reader.Read(ctx, name, keypath, cacheInfo)
+}
+
+// ReadRange implements backend.RawReader
+func (r *readerWriter) ReadRange(ctx context.Context, name string, keypath backend.KeyPath, offset uint64, buffer []byte, cacheInfo *backend.CacheInfo) error {
+	return r.backendForKeypath(keypath).Reader.ReadRange(ctx, name, keypath, offset, buffer, cacheInfo)
+}
+
+// Shutdown implements backend.RawReader
+func (r *readerWriter) Shutdown() {
+	r.next.Reader.Shutdown()
+	for _, b := range r.tenants {
+		b.Reader.Shutdown()
+	}
+}
+
+// Write implements backend.RawWriter
+func (r *readerWriter) Write(ctx context.Context, name string, keypath backend.KeyPath, data io.Reader, size int64, cacheInfo *backend.CacheInfo) error {
+	return r.backendForKeypath(keypath).Writer.Write(ctx, name, keypath, data, size, cacheInfo)
+}
+
+// appendTracker keeps the backend of an append to close it in the same backend
+type appendTracker struct {
+	writer  backend.RawWriter
+	tracker backend.AppendTracker
+}
+
+// Append implements backend.RawWriter
+func (r *readerWriter) Append(ctx context.Context, name string, keypath backend.KeyPath, tracker backend.AppendTracker, buffer []byte) (backend.AppendTracker, error) {
+	var inner backend.AppendTracker
+	if tracker != nil {
+		inner = tracker.(*appendTracker).tracker
+	}
+
+	w := r.backendForKeypath(keypath).Writer
+	inner, err := w.Append(ctx, name, keypath, inner, buffer)
+	if err != nil {
+		return nil, err
+	}
+
+	return &appendTracker{writer: w, tracker: inner}, nil
+}
+
+// CloseAppend implements backend.RawWriter
+func (r *readerWriter) CloseAppend(ctx context.Context, tracker backend.AppendTracker) error {
+	if tracker == nil {
+		return nil
+	}
+
+	t := tracker.(*appendTracker)
+	return t.writer.CloseAppend(ctx, t.tracker)
+}
+
+// Delete implements backend.RawWriter
+func (r *readerWriter) Delete(ctx context.Context, name string, keypath backend.KeyPath, cacheInfo *backend.CacheInfo) error {
+	return r.backendForKeypath(keypath).Writer.Delete(ctx, name, keypath, cacheInfo)
+}
+
+// MarkBlockCompacted implements backend.Compactor
+func (r *readerWriter) MarkBlockCompacted(blockID uuid.UUID, tenantID string) error {
+	return r.backendFor(tenantID).Compactor.MarkBlockCompacted(blockID, tenantID)
+}
+
+// ClearBlock implements backend.Compactor
+func (r *readerWriter) ClearBlock(blockID uuid.UUID, tenantID string) error {
+	return r.backendFor(tenantID).Compactor.ClearBlock(blockID, tenantID)
+}
+
+// CompactedBlockMeta implements backend.Compactor
+func (r *readerWriter) CompactedBlockMeta(blockID uuid.UUID, tenantID string) (*backend.CompactedBlockMeta, error) {
+	return r.backendFor(tenantID).Compactor.CompactedBlockMeta(blockID, tenantID)
+}
+
+func (r *readerWriter) backendForKeypath(keypath backend.KeyPath) Backend {
+	if len(keypath) == 0 {
+		return r.next
+	}
+	tenant, _, _ := strings.Cut(keypath[0], "/")
+	return r.backendFor(tenant)
+}
+
+func (r *readerWriter) backendFor(tenant string) Backend {
+	if b, ok := r.tenants[tenant]; ok {
+		return b
+	}
+	return r.next
+}
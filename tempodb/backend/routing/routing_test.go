@@ -0,0 +1,67 @@
+package routing
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/backend/local"
+)
+
+func TestRouting(t *testing.T) {
+	defaultBackend := newLocalBackend(t)
+	routedBackend := newLocalBackend(t)
+
+	r, w, c := New(defaultBackend, map[string]Backend{"routed": routedBackend})
+	ctx := context.Background()
+
+	for _, tenant := range []string{"default", "routed"} {
+		keypath := backend.KeyPathForBlock(uuid.New(), tenant)
+		require.NoError(t, w.Write(ctx, backend.MetaName, keypath, bytes.NewReader([]byte("{}")), 2, nil))
+
+		tracker, err := w.Append(ctx, "data", keypath, nil, []byte("a"))
+		require.NoError(t, err)
+		tracker, err = w.Append(ctx, "data", keypath, tracker, []byte("b"))
+		require.NoError(t, err)
+		require.NoError(t, w.CloseAppend(ctx, tracker))
+
+		rc, _, err := r.Read(ctx, "data", keypath, nil)
+		require.NoError(t, err)
+		data, err := io.ReadAll(rc)
+		require.NoError(t, err)
+		require.NoError(t, rc.Close())
+		require.Equal(t, []byte("ab"), data)
+	}
+
+	// the routed tenant is only stored in its backend
+	defaultTenants, err := defaultBackend.Reader.List(ctx, nil)
+	require.NoError(t, err)
+	require.Equal(t, []string{"default"}, defaultTenants)
+
+	routedTenants, err := routedBackend.Reader.List(ctx, nil)
+	require.NoError(t, err)
+	require.Equal(t, []string{"routed"}, routedTenants)
+
+	tenants, err := r.List(ctx, nil)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"default", "routed"}, tenants)
+
+	blockIDs, _, err := r.ListBlocks(ctx, "routed")
+	require.NoError(t, err)
+	require.Len(t, blockIDs, 1)
+
+	require.NoError(t, c.MarkBlockCompacted(blockIDs[0], "routed"))
+	_, err = routedBackend.Compactor.CompactedBlockMeta(blockIDs[0], "routed")
+	require.NoError(t, err)
+}
+
+func newLocalBackend(t *testing.T) Backend {
+	r, w, c, err := local.New(&local.Config{Path: t.TempDir()})
+	require.NoError(t, err)
+	return Backend{Reader: r, Writer: w, Compactor: c}
+}
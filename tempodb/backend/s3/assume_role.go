@@ -0,0 +1,115 @@
+package s3
+
+import (
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	awscredentials "github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+const defaultAssumeRoleSessionName = "tempo"
+
+// AssumeRoleConfig configures an IAM role that is assumed with the credentials found by the credential chain, like
+// the web identity of IRSA, the instance profile or static keys. The credentials of the role are refreshed before
+// they expire.
+type AssumeRoleConfig struct {
+	RoleARN     string        `yaml:"role_arn"`
+	ExternalID  string        `yaml:"external_id"`
+	SessionName string        `yaml:"session_name"`
+	Duration    time.Duration `yaml:"duration"`
+	// Endpoint overrides the STS endpoint, for example to use a VPC endpoint
+	Endpoint string `yaml:"sts_endpoint"`
+}
+
+// assumeRoleProvider is a credentials.Provider that assumes a role with AWS STS
+type assumeRoleProvider struct {
+	creds *awscredentials.Credentials
+}
+
+var _ credentials.Provider = (*assumeRoleProvider)(nil)
+
+func newAssumeRoleProvider(cfg *AssumeRoleConfig, region string, base *credentials.Credentials) (*assumeRoleProvider, error) {
+	awsCfg := aws.NewConfig().
+		WithCredentials(awscredentials.NewCredentials(&chainProvider{creds: base})).
+		WithRegion(region)
+	if cfg.Endpoint != "" {
+		awsCfg = awsCfg.WithEndpoint(cfg.Endpoint)
+	}
+
+	sess, err := session.NewSession(awsCfg)
+	if err != nil {
+		return nil, err
+	}
+
+	sessionName := cfg.SessionName
+	if sessionName == "" {
+		sessionName = defaultAssumeRoleSessionName
+	}
+
+	p := &stscreds.AssumeRoleProvider{
+		Client:          sts.New(sess),
+		RoleARN:         cfg.RoleARN,
+		RoleSessionName: sessionName,
+		Duration:        cfg.Duration,
+		ExpiryWindow:    time.Minute,
+	}
+	if p.Duration == 0 {
+		p.Duration = stscreds.DefaultDuration
+	}
+	if cfg.ExternalID != "" {
+		p.ExternalID = aws.String(cfg.ExternalID)
+	}
+
+	return &assumeRoleProvider{creds: awscredentials.NewCredentials(p)}, nil
+}
+
+// Retrieve implements credentials.Provider
+func (p *assumeRoleProvider) Retrieve() (credentials.Value, error) {
+	v, err := p.creds.Get()
+	if err != nil {
+		return credentials.Value{}, err
+	}
+
+	return credentials.Value{
+		AccessKeyID:     v.AccessKeyID,
+		SecretAccessKey: v.SecretAccessKey,
+		SessionToken:    v.SessionToken,
+		SignerType:      credentials.SignatureV4,
+	}, nil
+}
+
+// IsExpired implements credentials.Provider
+func (p *assumeRoleProvider) IsExpired() bool {
+	return p.creds.IsExpired()
+}
+
+// chainProvider passes the credentials of the credential chain to the sts client
+type chainProvider struct {
+	creds *credentials.Credentials
+}
+
+var _ awscredentials.Provider = (*chainProvider)(nil)
+
+// Retrieve implements awscredentials.Provider
+func (p *chainProvider) Retrieve() (awscredentials.Value, error) {
+	v, err := p.creds.Get()
+	if err != nil {
+		return awscredentials.Value{}, err
+	}
+
+	return awscredentials.Value{
+		AccessKeyID:     v.AccessKeyID,
+		SecretAccessKey: v.SecretAccessKey,
+		SessionToken:    v.SessionToken,
+		ProviderName:    "tempo",
+	}, nil
+}
+
+// IsExpired implements awscredentials.Provider
+func (p *chainProvider) IsExpired() bool {
+	return p.creds.IsExpired()
+}
@@ -20,7 +20,7 @@ func (rw *readerWriter) MarkBlockCompacted(blockID uuid.UUID, tenantID string) e
 		return backend.ErrEmptyBlockID
 	}
 
-	putObjectOptions := getPutObjectOptions(rw)
+	putObjectOptions := getPutObjectOptions(rw, tenantID)
 
 	metaFileName := backend.MetaFileName(blockID, tenantID, rw.cfg.Prefix)
 	// copy meta.json to meta.compacted.json
@@ -30,7 +30,8 @@ func (rw *readerWriter) MarkBlockCompacted(blockID uuid.UUID, tenantID string) e
 		metaFileName,
 		rw.cfg.Bucket,
 		backend.CompactedMetaFileName(blockID, tenantID, rw.cfg.Prefix),
-		nil,
+		// the copy doesn't take the encryption from the put options
+		rw.sseHeaders(tenantID),
 		minio.CopySrcOptions{},
 		putObjectOptions,
 	)
@@ -38,6 +39,11 @@ func (rw *readerWriter) MarkBlockCompacted(blockID uuid.UUID, tenantID string) e
 		return fmt.Errorf("error copying obj meta to compacted obj meta: %w", err)
 	}
 
+	// with object lock meta.json is kept. a block with both metas is compacted
+	if rw.cfg.ObjectLock {
+		return nil
+	}
+
 	// delete meta.json
 	return rw.core.RemoveObject(context.TODO(), rw.cfg.Bucket, metaFileName, minio.RemoveObjectOptions{})
 }
@@ -51,6 +57,9 @@ func (rw *readerWriter) ClearBlock(blockID uuid.UUID, tenantID string) error {
 	}
 
 	path := backend.RootPath(blockID, tenantID, rw.cfg.Prefix) + "/"
+	if rw.cfg.ObjectLock {
+		return fmt.Errorf("not deleting block %s: %w", path, backend.ErrObjectLocked)
+	}
 	level.Debug(rw.logger).Log("msg", "deleting block", "block path", path)
 
 	// ListObjects(bucket, prefix, marker, delimiter string, maxKeys int)
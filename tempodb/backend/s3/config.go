@@ -1,11 +1,15 @@
 package s3
 
 import (
+	"errors"
 	"flag"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/grafana/dskit/crypto/tls"
 	"github.com/grafana/dskit/flagext"
+	"github.com/minio/minio-go/v7"
 
 	"github.com/grafana/tempo/pkg/util"
 )
@@ -35,6 +39,34 @@ type Config struct {
 	// See https://github.com/grafana/tempo/pull/3006 for more details
 	NativeAWSAuthEnabled  bool `yaml:"native_aws_auth_enabled"`
 	ListBlocksConcurrency int  `yaml:"list_blocks_concurrency"`
+	// PartUploadConcurrency is the number of parts of a multipart upload that are uploaded in parallel
+	PartUploadConcurrency uint `yaml:"part_upload_concurrency"`
+	// ObjectLock writes to buckets with S3 Object Lock or other immutability policies. Tempo never deletes
+	// objects and marks blocks compacted by adding a meta.compacted.json next to meta.json. Objects must be
+	// expired by a lifecycle rule of the bucket.
+	ObjectLock bool `yaml:"object_lock"`
+	// SSE configures the server-side encryption of the objects written by Tempo
+	SSE SSEConfig `yaml:"sse"`
+	// AssumeRole configures an IAM role to assume with the credentials of the chain
+	AssumeRole AssumeRoleConfig `yaml:"assume_role"`
+	// RequesterPays charges the requests and the data transfer to the account of the credentials instead of the owner
+	// of the bucket
+	RequesterPays bool `yaml:"requester_pays"`
+}
+
+const (
+	SSETypeS3  = "SSE-S3"
+	SSETypeKMS = "SSE-KMS"
+)
+
+// SSEConfig configures server-side encryption with keys managed by s3 (SSE-S3) or by AWS KMS (SSE-KMS). Objects are
+// decrypted transparently on read so only writes carry the encryption headers.
+type SSEConfig struct {
+	Type                 string `yaml:"type"`
+	KMSKeyID             string `yaml:"kms_key_id"`
+	KMSEncryptionContext string `yaml:"kms_encryption_context"`
+	// TenantKMSKeyIDs overrides the KMS key of the objects of a tenant
+	TenantKMSKeyIDs map[string]string `yaml:"tenant_kms_key_ids"`
 }
 
 func (cfg *Config) RegisterFlagsAndApplyDefaults(prefix string, f *flag.FlagSet) {
@@ -46,9 +78,46 @@ func (cfg *Config) RegisterFlagsAndApplyDefaults(prefix string, f *flag.FlagSet)
 	f.Var(&cfg.SecretKey, util.PrefixConfig(prefix, "s3.secret_key"), "s3 secret key.")
 	f.Var(&cfg.SessionToken, util.PrefixConfig(prefix, "s3.session_token"), "s3 session token.")
 	f.IntVar(&cfg.ListBlocksConcurrency, util.PrefixConfig(prefix, "s3.list_blocks_concurrency"), 3, "number of concurrent list calls to make to backend")
+	f.UintVar(&cfg.PartUploadConcurrency, util.PrefixConfig(prefix, "s3.part_upload_concurrency"), 4, "number of parts of a multipart upload to upload in parallel")
 	cfg.HedgeRequestsUpTo = 2
 }
 
+// Validate checks the settings needed to reach the bucket. S3-compatible stores like MinIO or Ceph RGW are usually
+// reached with a custom endpoint and path-style addressing, the region is optional.
+func (cfg *Config) Validate() error {
+	if cfg.Bucket == "" {
+		return errors.New("bucket is required")
+	}
+	if cfg.Endpoint == "" {
+		return errors.New("endpoint is required")
+	}
+	if strings.Contains(cfg.Endpoint, "://") {
+		return fmt.Errorf("endpoint %s must not contain a scheme, set insecure to true to use http", cfg.Endpoint)
+	}
+	if strings.Contains(cfg.Endpoint, "/") {
+		return fmt.Errorf("endpoint %s must be a host with an optional port, use prefix to store blocks under a path", cfg.Endpoint)
+	}
+
+	lookup := minio.BucketLookupType(cfg.BucketLookupType)
+	if lookup != minio.BucketLookupAuto && lookup != minio.BucketLookupDNS && lookup != minio.BucketLookupPath {
+		return fmt.Errorf("invalid bucket_lookup_type %d, valid values are 0 (auto), 1 (virtual-hosted-style) and 2 (path-style)", cfg.BucketLookupType)
+	}
+	if cfg.ForcePathStyle && lookup == minio.BucketLookupDNS {
+		return errors.New("forcepathstyle can't be used with bucket_lookup_type 1 (virtual-hosted-style)")
+	}
+	if cfg.AssumeRole.RoleARN == "" && cfg.AssumeRole.ExternalID != "" {
+		return errors.New("assume_role.external_id requires assume_role.role_arn")
+	}
+	if cfg.RequesterPays && cfg.SignatureV2 {
+		return errors.New("requester_pays can't be used with signature_v2")
+	}
+	if cfg.RequesterPays && cfg.Insecure {
+		return errors.New("requester_pays can't be used with insecure")
+	}
+
+	return nil
+}
+
 func (cfg *Config) PathMatches(other *Config) bool {
 	// S3 bucket names are globally unique
 	return cfg.Bucket == other.Bucket && cfg.Prefix == other.Prefix
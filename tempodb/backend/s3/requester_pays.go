@@ -0,0 +1,78 @@
+package s3
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/minio/minio-go/v7/pkg/signer"
+)
+
+const (
+	requesterPaysHeader = "X-Amz-Request-Payer"
+	requesterPaysValue  = "requester"
+
+	signV4Algorithm   = "AWS4-HMAC-SHA256"
+	streamingPayload  = "STREAMING-AWS4-HMAC-SHA256-PAYLOAD"
+	credentialPrefix  = "Credential="
+	contentSHA256Name = "X-Amz-Content-Sha256"
+)
+
+// requesterPaysTransport charges the requests to the bucket of the requester. s3 rejects x-amz-* headers that are
+// not signed and minio signs the requests before they reach the transport, so the requests are signed again with the
+// header.
+type requesterPaysTransport struct {
+	next  http.RoundTripper
+	creds *credentials.Credentials
+}
+
+func newRequesterPaysTransport(next http.RoundTripper, creds *credentials.Credentials) http.RoundTripper {
+	return &requesterPaysTransport{
+		next:  next,
+		creds: creds,
+	}
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *requesterPaysTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set(requesterPaysHeader, requesterPaysValue)
+
+	auth := req.Header.Get("Authorization")
+	if !strings.HasPrefix(auth, signV4Algorithm) {
+		// anonymous requests are not signed
+		return t.next.RoundTrip(req)
+	}
+	if req.Header.Get(contentSHA256Name) == streamingPayload {
+		return nil, errors.New("requester_pays can't be used with streaming signatures, disable insecure")
+	}
+
+	region, err := signingRegion(auth)
+	if err != nil {
+		return nil, err
+	}
+	creds, err := t.creds.Get()
+	if err != nil {
+		return nil, err
+	}
+
+	req = signer.SignV4(*req, creds.AccessKeyID, creds.SecretAccessKey, creds.SessionToken, region)
+	return t.next.RoundTrip(req)
+}
+
+// signingRegion returns the region of the credential scope of a V4 authorization header:
+// AWS4-HMAC-SHA256 Credential=<key>/<date>/<region>/<service>/aws4_request, SignedHeaders=..., Signature=...
+func signingRegion(auth string) (string, error) {
+	i := strings.Index(auth, credentialPrefix)
+	if i < 0 {
+		return "", errors.New("authorization header has no credential")
+	}
+	credential, _, _ := strings.Cut(auth[i+len(credentialPrefix):], ",")
+
+	scope := strings.Split(credential, "/")
+	if len(scope) != 5 {
+		return "", errors.New("authorization header has an invalid credential scope")
+	}
+	return scope[2], nil
+}
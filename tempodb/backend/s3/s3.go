@@ -3,6 +3,9 @@ package s3
 import (
 	"bytes"
 	"context"
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -22,6 +25,7 @@ import (
 	"github.com/go-kit/log/level"
 	minio "github.com/minio/minio-go/v7"
 	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/minio/minio-go/v7/pkg/encrypt"
 	"github.com/opentracing/opentracing-go"
 
 	"github.com/grafana/tempo/pkg/blockboundary"
@@ -36,6 +40,8 @@ type readerWriter struct {
 	cfg        *Config
 	core       *minio.Core
 	hedgedCore *minio.Core
+	sse        encrypt.ServerSide
+	tenantSSE  map[string]encrypt.ServerSide
 }
 
 var (
@@ -98,8 +104,22 @@ func internalNew(cfg *Config, confirm bool) (*readerWriter, error) {
 		return nil, fmt.Errorf("config is nil")
 	}
 
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid s3 config: %w", err)
+	}
+
 	l := log.Logger
 
+	sse, err := createServerSide(cfg.SSE)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sse config: %w", err)
+	}
+
+	tenantSSE, err := createTenantServerSide(cfg.SSE)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sse config: %w", err)
+	}
+
 	core, err := createCore(cfg, false)
 	if err != nil {
 		return nil, fmt.Errorf("unexpected error creating core: %w", err)
@@ -123,16 +143,23 @@ func internalNew(cfg *Config, confirm bool) (*readerWriter, error) {
 		cfg:        cfg,
 		core:       core,
 		hedgedCore: hedgedCore,
+		sse:        sse,
+		tenantSSE:  tenantSSE,
 	}
 	return rw, nil
 }
 
-func getPutObjectOptions(rw *readerWriter) minio.PutObjectOptions {
+// getPutObjectOptions returns the options to write an object of the tenant
+func getPutObjectOptions(rw *readerWriter, tenantID string) minio.PutObjectOptions {
 	return minio.PutObjectOptions{
 		PartSize:     rw.cfg.PartSize,
+		NumThreads:   rw.cfg.PartUploadConcurrency,
 		UserTags:     rw.cfg.Tags,
 		StorageClass: rw.cfg.StorageClass,
 		UserMetadata: rw.cfg.Metadata,
+		// s3 requires a checksum for uploads to buckets with object lock
+		SendContentMd5:       rw.cfg.ObjectLock,
+		ServerSideEncryption: rw.serverSide(tenantID),
 	}
 }
 
@@ -143,11 +170,11 @@ func (rw *readerWriter) Write(ctx context.Context, name string, keypath backend.
 
 	span.SetTag("object", name)
 
+	putObjectOptions := getPutObjectOptions(rw, tenantOf(keypath))
+
 	keypath = backend.KeyPathWithPrefix(keypath, rw.cfg.Prefix)
 	objName := backend.ObjectFileName(keypath, name)
 
-	putObjectOptions := getPutObjectOptions(rw)
-
 	info, err := rw.core.Client.PutObject(
 		derivedCtx,
 		rw.cfg.Bucket,
@@ -173,10 +200,10 @@ func (rw *readerWriter) Append(ctx context.Context, name string, keypath backend
 	defer span.Finish()
 
 	var a appendTracker
+	options := getPutObjectOptions(rw, tenantOf(keypath))
+
 	keypath = backend.KeyPathWithPrefix(keypath, rw.cfg.Prefix)
 	objectName := backend.ObjectFileName(keypath, name)
-
-	options := getPutObjectOptions(rw)
 	if tracker != nil {
 		a = tracker.(appendTracker)
 	} else {
@@ -195,6 +222,12 @@ func (rw *readerWriter) Append(ctx context.Context, name string, keypath backend
 
 	level.Debug(rw.logger).Log("msg", "appending object to s3", "objectName", objectName)
 
+	var partOptions minio.PutObjectPartOptions
+	if rw.cfg.ObjectLock {
+		sum := md5.Sum(buffer)
+		partOptions.Md5Base64 = base64.StdEncoding.EncodeToString(sum[:])
+	}
+
 	a.partNum++
 	objPart, err := rw.core.PutObjectPart(
 		ctx,
@@ -204,7 +237,7 @@ func (rw *readerWriter) Append(ctx context.Context, name string, keypath backend
 		a.partNum,
 		bytes.NewReader(buffer),
 		int64(len(buffer)),
-		minio.PutObjectPartOptions{},
+		partOptions,
 	)
 	if err != nil {
 		return a, fmt.Errorf("error in multipart upload: %w", err)
@@ -246,6 +279,9 @@ func (rw *readerWriter) CloseAppend(ctx context.Context, tracker backend.AppendT
 
 func (rw *readerWriter) Delete(ctx context.Context, name string, keypath backend.KeyPath, _ *backend.CacheInfo) error {
 	filename := backend.ObjectFileName(keypath, name)
+	if rw.cfg.ObjectLock {
+		return fmt.Errorf("not deleting object %s: %w", filename, backend.ErrObjectLocked)
+	}
 	return rw.core.RemoveObject(ctx, rw.cfg.Bucket, filename, minio.RemoveObjectOptions{})
 }
 
@@ -386,11 +422,36 @@ func (rw *readerWriter) ListBlocks(
 		return nil, nil, errors.Join(errs...)
 	}
 
+	// with object lock meta.json is not removed when a block is compacted
+	if rw.cfg.ObjectLock {
+		blockIDs = removeCompacted(blockIDs, compactedBlockIDs)
+	}
+
 	level.Debug(rw.logger).Log("msg", "listing blocks complete", "blockIDs", len(blockIDs), "compactedBlockIDs", len(compactedBlockIDs))
 
 	return blockIDs, compactedBlockIDs, nil
 }
 
+// removeCompacted removes the blocks that are also compacted from blockIDs
+func removeCompacted(blockIDs, compactedBlockIDs []uuid.UUID) []uuid.UUID {
+	if len(compactedBlockIDs) == 0 {
+		return blockIDs
+	}
+
+	compacted := make(map[uuid.UUID]struct{}, len(compactedBlockIDs))
+	for _, id := range compactedBlockIDs {
+		compacted[id] = struct{}{}
+	}
+
+	live := blockIDs[:0]
+	for _, id := range blockIDs {
+		if _, ok := compacted[id]; !ok {
+			live = append(live, id)
+		}
+	}
+	return live
+}
+
 // Find implements backend.Reader
 func (rw *readerWriter) Find(ctx context.Context, keypath backend.KeyPath, f backend.FindFunc) (err error) {
 	keypath = backend.KeyPathWithPrefix(keypath, rw.cfg.Prefix)
@@ -607,6 +668,19 @@ func fetchCreds(cfg *Config) (*credentials.Credentials, error) {
 
 	creds := credentials.NewChainCredentials(chain)
 
+	if cfg.AssumeRole.RoleARN != "" {
+		region := cfg.Region
+		if region == "" {
+			region = "us-east-1"
+		}
+
+		p, err := newAssumeRoleProvider(&cfg.AssumeRole, region, creds)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create assume role credentials: %w", err)
+		}
+		creds = credentials.New(p)
+	}
+
 	// error early if we cannot obtain credentials
 	if _, err := creds.Get(); err != nil {
 		return nil, fmt.Errorf("failed to get credentials: %w", err)
@@ -635,8 +709,13 @@ func createCore(cfg *Config, hedge bool) (*minio.Core, error) {
 		customTransport.TLSClientConfig = tlsConfig
 	}
 
+	var transport http.RoundTripper = customTransport
+	if cfg.RequesterPays {
+		transport = newRequesterPaysTransport(transport, creds)
+	}
+
 	// add instrumentation
-	transport := instrumentation.NewTransport(customTransport)
+	transport = instrumentation.NewTransport(transport)
 	var stats *hedgedhttp.Stats
 
 	if hedge && cfg.HedgeRequestsAt != 0 {
@@ -663,6 +742,92 @@ func createCore(cfg *Config, hedge bool) (*minio.Core, error) {
 	return minio.NewCore(cfg.Endpoint, opts)
 }
 
+// serverSide returns the server-side encryption of the objects of the tenant
+func (rw *readerWriter) serverSide(tenantID string) encrypt.ServerSide {
+	if sse, ok := rw.tenantSSE[tenantID]; ok {
+		return sse
+	}
+	return rw.sse
+}
+
+// sseHeaders returns the server-side encryption as headers for the requests that don't take it as an option
+func (rw *readerWriter) sseHeaders(tenantID string) map[string]string {
+	sse := rw.serverSide(tenantID)
+	if sse == nil {
+		return nil
+	}
+
+	h := http.Header{}
+	sse.Marshal(h)
+	headers := make(map[string]string, len(h))
+	for k := range h {
+		headers[k] = h.Get(k)
+	}
+	return headers
+}
+
+// createServerSide returns the server-side encryption of the written objects or nil if it's disabled
+func createServerSide(cfg SSEConfig) (encrypt.ServerSide, error) {
+	switch cfg.Type {
+	case "":
+		return nil, nil
+	case SSETypeS3:
+		return encrypt.NewSSE(), nil
+	case SSETypeKMS:
+		if cfg.KMSKeyID == "" {
+			return nil, errors.New("kms_key_id is required for SSE-KMS")
+		}
+		return createSSEKMS(cfg, cfg.KMSKeyID)
+	default:
+		return nil, fmt.Errorf("unsupported type %s. valid values are %s and %s", cfg.Type, SSETypeS3, SSETypeKMS)
+	}
+}
+
+// createTenantServerSide returns the server-side encryption of the tenants with their own KMS key
+func createTenantServerSide(cfg SSEConfig) (map[string]encrypt.ServerSide, error) {
+	if len(cfg.TenantKMSKeyIDs) == 0 {
+		return nil, nil
+	}
+	if cfg.Type != SSETypeKMS {
+		return nil, fmt.Errorf("tenant_kms_key_ids requires type %s", SSETypeKMS)
+	}
+
+	tenantSSE := make(map[string]encrypt.ServerSide, len(cfg.TenantKMSKeyIDs))
+	for tenantID, keyID := range cfg.TenantKMSKeyIDs {
+		if keyID == "" {
+			return nil, fmt.Errorf("kms key id of tenant %s is empty", tenantID)
+		}
+
+		sse, err := createSSEKMS(cfg, keyID)
+		if err != nil {
+			return nil, err
+		}
+		tenantSSE[tenantID] = sse
+	}
+	return tenantSSE, nil
+}
+
+func createSSEKMS(cfg SSEConfig, keyID string) (encrypt.ServerSide, error) {
+	var encryptionContext map[string]string
+	if cfg.KMSEncryptionContext != "" {
+		if err := json.Unmarshal([]byte(cfg.KMSEncryptionContext), &encryptionContext); err != nil {
+			return nil, fmt.Errorf("kms_encryption_context must be a JSON object of strings: %w", err)
+		}
+	}
+	if encryptionContext == nil {
+		return encrypt.NewSSEKMS(keyID, nil)
+	}
+	return encrypt.NewSSEKMS(keyID, encryptionContext)
+}
+
+// tenantOf returns the tenant of an object from its key path. Objects of a tenant are stored under the tenant id.
+func tenantOf(keypath backend.KeyPath) string {
+	if len(keypath) == 0 {
+		return ""
+	}
+	return keypath[0]
+}
+
 func readError(err error) error {
 	if err != nil && minio.ToErrorResponse(err).Code == s3.ErrCodeNoSuchKey {
 		return backend.ErrDoesNotExist
@@ -14,6 +14,8 @@ import (
 	"os"
 	"path/filepath"
 	"strconv"
+	"strings"
+	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -286,6 +288,174 @@ func TestNilConfig(t *testing.T) {
 	require.Error(t, err)
 }
 
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		cfg    Config
+		expErr string
+	}{
+		{
+			name: "valid",
+			cfg:  Config{Bucket: "tempo", Endpoint: "minio:9000", ForcePathStyle: true},
+		},
+		{
+			name:   "missing bucket",
+			cfg:    Config{Endpoint: "minio:9000"},
+			expErr: "bucket is required",
+		},
+		{
+			name:   "missing endpoint",
+			cfg:    Config{Bucket: "tempo"},
+			expErr: "endpoint is required",
+		},
+		{
+			name:   "endpoint with scheme",
+			cfg:    Config{Bucket: "tempo", Endpoint: "http://minio:9000"},
+			expErr: "endpoint http://minio:9000 must not contain a scheme, set insecure to true to use http",
+		},
+		{
+			name:   "endpoint with path",
+			cfg:    Config{Bucket: "tempo", Endpoint: "minio:9000/tempo"},
+			expErr: "endpoint minio:9000/tempo must be a host with an optional port, use prefix to store blocks under a path",
+		},
+		{
+			name:   "invalid bucket lookup type",
+			cfg:    Config{Bucket: "tempo", Endpoint: "minio:9000", BucketLookupType: 3},
+			expErr: "invalid bucket_lookup_type 3, valid values are 0 (auto), 1 (virtual-hosted-style) and 2 (path-style)",
+		},
+		{
+			name:   "path-style with virtual-hosted-style lookup",
+			cfg:    Config{Bucket: "tempo", Endpoint: "minio:9000", ForcePathStyle: true, BucketLookupType: int(minio.BucketLookupDNS)},
+			expErr: "forcepathstyle can't be used with bucket_lookup_type 1 (virtual-hosted-style)",
+		},
+		{
+			name:   "external id without role",
+			cfg:    Config{Bucket: "tempo", Endpoint: "minio:9000", AssumeRole: AssumeRoleConfig{ExternalID: "id"}},
+			expErr: "assume_role.external_id requires assume_role.role_arn",
+		},
+		{
+			name:   "requester pays with signature v2",
+			cfg:    Config{Bucket: "tempo", Endpoint: "minio:9000", RequesterPays: true, SignatureV2: true},
+			expErr: "requester_pays can't be used with signature_v2",
+		},
+		{
+			name:   "requester pays with insecure",
+			cfg:    Config{Bucket: "tempo", Endpoint: "minio:9000", RequesterPays: true, Insecure: true},
+			expErr: "requester_pays can't be used with insecure",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.cfg.Validate()
+			if tc.expErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.EqualError(t, err, tc.expErr)
+		})
+	}
+}
+
+func TestAssumeRole(t *testing.T) {
+	var form url.Values
+	calls := 0
+	server := testServer(t, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		require.NoError(t, r.ParseForm())
+		form = r.PostForm
+		assert.Contains(t, r.Header.Get("Authorization"), defaultAccessKey)
+
+		_, _ = w.Write([]byte(`<AssumeRoleResponse xmlns="https://sts.amazonaws.com/doc/2011-06-15/">
+  <AssumeRoleResult>
+    <Credentials>
+      <AccessKeyId>role-access-key</AccessKeyId>
+      <SecretAccessKey>role-secret-key</SecretAccessKey>
+      <SessionToken>role-session-token</SessionToken>
+      <Expiration>` + time.Now().Add(time.Hour).UTC().Format(time.RFC3339) + `</Expiration>
+    </Credentials>
+  </AssumeRoleResult>
+</AssumeRoleResponse>`))
+	})
+
+	creds, err := fetchCreds(&Config{
+		AccessKey: defaultAccessKey,
+		SecretKey: flagext.SecretWithValue(defaultSecretKey),
+		AssumeRole: AssumeRoleConfig{
+			RoleARN:    "arn:aws:iam::123456789012:role/tempo",
+			ExternalID: "external-id",
+			Endpoint:   server.URL,
+		},
+	})
+	require.NoError(t, err)
+
+	v, err := creds.Get()
+	require.NoError(t, err)
+	assert.Equal(t, credentials.Value{
+		AccessKeyID:     "role-access-key",
+		SecretAccessKey: "role-secret-key",
+		SessionToken:    "role-session-token",
+		SignerType:      credentials.SignatureV4,
+	}, v)
+
+	assert.Equal(t, "AssumeRole", form.Get("Action"))
+	assert.Equal(t, "arn:aws:iam::123456789012:role/tempo", form.Get("RoleArn"))
+	assert.Equal(t, "external-id", form.Get("ExternalId"))
+	assert.Equal(t, defaultAssumeRoleSessionName, form.Get("RoleSessionName"))
+
+	// the credentials are cached until they expire
+	_, err = creds.Get()
+	require.NoError(t, err)
+	assert.Equal(t, 1, calls)
+}
+
+func TestS3Compatible(t *testing.T) {
+	var (
+		mtx   sync.Mutex
+		paths []string
+	)
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mtx.Lock()
+		paths = append(paths, r.URL.Path)
+		mtx.Unlock()
+
+		switch {
+		case r.URL.Query().Has("location"):
+			// region-less stores don't report a location
+			_, _ = w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+		<LocationConstraint></LocationConstraint>`))
+		case r.Method == getMethod:
+			_, _ = w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+		<ListBucketResult>
+		</ListBucketResult>`))
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	cfg := &Config{
+		AccessKey:      "test",
+		SecretKey:      flagext.SecretWithValue("test"),
+		Bucket:         "blerg",
+		Endpoint:       server.URL[8:], // [8:] -> strip https://
+		ForcePathStyle: true,
+	}
+	cfg.InsecureSkipVerify = true
+
+	_, w, _, err := New(cfg)
+	require.NoError(t, err)
+
+	err = w.Write(context.Background(), "object", backend.KeyPath{"test"}, bytes.NewReader([]byte("data")), 4, nil)
+	require.NoError(t, err)
+
+	mtx.Lock()
+	defer mtx.Unlock()
+	require.NotEmpty(t, paths)
+	for _, p := range paths {
+		assert.Truef(t, strings.HasPrefix(p, "/blerg"), "expected a path-style request, got %s", p)
+	}
+	assert.Contains(t, paths, "/blerg/test/object")
+}
+
 func fakeServer(t *testing.T, returnIn time.Duration, counter *int32) *httptest.Server {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
 		time.Sleep(returnIn)
@@ -569,6 +739,243 @@ func TestListBlocksWithPrefix(t *testing.T) {
 	}
 }
 
+func TestObjectLock(t *testing.T) {
+	var (
+		mtx     sync.Mutex
+		deletes int
+		md5s    int
+	)
+
+	server := testServer(t, func(w http.ResponseWriter, r *http.Request) {
+		mtx.Lock()
+		defer mtx.Unlock()
+
+		switch r.Method {
+		case http.MethodDelete:
+			deletes++
+		case putMethod:
+			if r.Header.Get("X-Amz-Copy-Source") != "" {
+				_, _ = w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?><CopyObjectResult></CopyObjectResult>`))
+				return
+			}
+			if r.Header.Get("Content-Md5") != "" {
+				md5s++
+			}
+		case getMethod:
+			_, _ = w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+			<ListBucketResult>
+				<Name>blerg</Name>
+				<IsTruncated>false</IsTruncated>
+				<Contents>
+					<Key>single-tenant/00000000-0000-0000-0000-000000000000/meta.json</Key>
+				</Contents>
+				<Contents>
+					<Key>single-tenant/00000000-0000-0000-0000-000000000001/meta.compacted.json</Key>
+				</Contents>
+				<Contents>
+					<Key>single-tenant/00000000-0000-0000-0000-000000000001/meta.json</Key>
+				</Contents>
+			</ListBucketResult>`))
+		}
+	})
+
+	r, w, c, err := NewNoConfirm(&Config{
+		Region:                "blerg",
+		AccessKey:             "test",
+		SecretKey:             flagext.SecretWithValue("test"),
+		Bucket:                "blerg",
+		Insecure:              true,
+		Endpoint:              server.URL[7:],
+		ListBlocksConcurrency: 1,
+		ObjectLock:            true,
+	})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	// a block with both metas is compacted
+	blockIDs, compactedBlockIDs, err := r.ListBlocks(ctx, "single-tenant")
+	require.NoError(t, err)
+	require.Equal(t, []uuid.UUID{uuid.MustParse("00000000-0000-0000-0000-000000000000")}, blockIDs)
+	require.Equal(t, []uuid.UUID{uuid.MustParse("00000000-0000-0000-0000-000000000001")}, compactedBlockIDs)
+
+	// uploads carry a checksum
+	require.NoError(t, w.Write(ctx, "object", backend.KeyPath{"test"}, bytes.NewReader([]byte("data")), 4, nil))
+	require.Equal(t, 1, md5s)
+
+	// nothing is ever deleted
+	blockID := uuid.MustParse("00000000-0000-0000-0000-000000000002")
+	require.NoError(t, c.MarkBlockCompacted(blockID, "single-tenant"))
+	require.ErrorIs(t, c.ClearBlock(blockID, "single-tenant"), backend.ErrObjectLocked)
+	require.ErrorIs(t, w.Delete(ctx, "object", backend.KeyPath{"test"}, nil), backend.ErrObjectLocked)
+	require.Equal(t, 0, deletes)
+}
+
+func TestServerSideEncryption(t *testing.T) {
+	tests := []struct {
+		name            string
+		sse             SSEConfig
+		expectedHeaders map[string]string
+		expectedErr     string
+	}{
+		{
+			name: "disabled",
+			expectedHeaders: map[string]string{
+				"X-Amz-Server-Side-Encryption": "",
+			},
+		},
+		{
+			name: "SSE-S3",
+			sse:  SSEConfig{Type: SSETypeS3},
+			expectedHeaders: map[string]string{
+				"X-Amz-Server-Side-Encryption": "AES256",
+			},
+		},
+		{
+			name: "SSE-KMS",
+			sse:  SSEConfig{Type: SSETypeKMS, KMSKeyID: "key", KMSEncryptionContext: `{"tenant":"blerg"}`},
+			expectedHeaders: map[string]string{
+				"X-Amz-Server-Side-Encryption":                "aws:kms",
+				"X-Amz-Server-Side-Encryption-Aws-Kms-Key-Id": "key",
+				"X-Amz-Server-Side-Encryption-Context":        base64.StdEncoding.EncodeToString([]byte(`{"tenant":"blerg"}`)),
+			},
+		},
+		{
+			name:        "SSE-KMS without key",
+			sse:         SSEConfig{Type: SSETypeKMS},
+			expectedErr: "invalid sse config: kms_key_id is required for SSE-KMS",
+		},
+		{
+			name:        "SSE-KMS with invalid context",
+			sse:         SSEConfig{Type: SSETypeKMS, KMSKeyID: "key", KMSEncryptionContext: "tenant=blerg"},
+			expectedErr: "invalid sse config: kms_encryption_context must be a JSON object of strings",
+		},
+		{
+			name:        "tenant keys without SSE-KMS",
+			sse:         SSEConfig{Type: SSETypeS3, TenantKMSKeyIDs: map[string]string{"single-tenant": "tenant-key"}},
+			expectedErr: "invalid sse config: tenant_kms_key_ids requires type SSE-KMS",
+		},
+		{
+			name:        "tenant with empty key",
+			sse:         SSEConfig{Type: SSETypeKMS, KMSKeyID: "key", TenantKMSKeyIDs: map[string]string{"single-tenant": ""}},
+			expectedErr: "invalid sse config: kms key id of tenant single-tenant is empty",
+		},
+		{
+			name:        "unsupported",
+			sse:         SSEConfig{Type: "SSE-C"},
+			expectedErr: "invalid sse config: unsupported type SSE-C. valid values are SSE-S3 and SSE-KMS",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				mtx     sync.Mutex
+				headers []http.Header
+			)
+			server := testServer(t, func(w http.ResponseWriter, r *http.Request) {
+				if r.Method == http.MethodDelete {
+					w.WriteHeader(http.StatusNoContent)
+					return
+				}
+				if r.Method != putMethod {
+					return
+				}
+
+				mtx.Lock()
+				headers = append(headers, r.Header.Clone())
+				mtx.Unlock()
+
+				if r.Header.Get("X-Amz-Copy-Source") != "" {
+					_, _ = w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?><CopyObjectResult></CopyObjectResult>`))
+				}
+			})
+
+			_, w, c, err := NewNoConfirm(&Config{
+				Region:    "blerg",
+				AccessKey: "test",
+				SecretKey: flagext.SecretWithValue("test"),
+				Bucket:    "blerg",
+				Insecure:  true,
+				Endpoint:  server.URL[7:],
+				SSE:       tc.sse,
+			})
+			if tc.expectedErr != "" {
+				require.ErrorContains(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+
+			// objects and the metas of compacted blocks are encrypted
+			require.NoError(t, w.Write(context.Background(), "object", backend.KeyPath{"test"}, bytes.NewReader([]byte("data")), 4, nil))
+			require.NoError(t, c.MarkBlockCompacted(uuid.New(), "single-tenant"))
+
+			mtx.Lock()
+			defer mtx.Unlock()
+			require.Len(t, headers, 2)
+			for _, h := range headers {
+				for k, v := range tc.expectedHeaders {
+					require.Equal(t, v, h.Get(k), k)
+				}
+			}
+		})
+	}
+}
+
+func TestTenantServerSideEncryption(t *testing.T) {
+	var (
+		mtx  sync.Mutex
+		keys = map[string]string{}
+	)
+	server := testServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodDelete {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		if r.Method != putMethod {
+			return
+		}
+
+		mtx.Lock()
+		keys[r.URL.Path] = r.Header.Get("X-Amz-Server-Side-Encryption-Aws-Kms-Key-Id")
+		mtx.Unlock()
+
+		if r.Header.Get("X-Amz-Copy-Source") != "" {
+			_, _ = w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?><CopyObjectResult></CopyObjectResult>`))
+		}
+	})
+
+	_, w, c, err := NewNoConfirm(&Config{
+		Region:    "blerg",
+		AccessKey: "test",
+		SecretKey: flagext.SecretWithValue("test"),
+		Bucket:    "blerg",
+		Prefix:    "traces",
+		Insecure:  true,
+		Endpoint:  server.URL[7:],
+		SSE: SSEConfig{
+			Type:            SSETypeKMS,
+			KMSKeyID:        "key",
+			TenantKMSKeyIDs: map[string]string{"tenant-a": "tenant-a-key"},
+		},
+	})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	blockID := uuid.New()
+	require.NoError(t, w.Write(ctx, "object", backend.KeyPath{"tenant-a", blockID.String()}, bytes.NewReader([]byte("data")), 4, nil))
+	require.NoError(t, w.Write(ctx, "object", backend.KeyPath{"tenant-b", blockID.String()}, bytes.NewReader([]byte("data")), 4, nil))
+	require.NoError(t, c.MarkBlockCompacted(blockID, "tenant-a"))
+
+	mtx.Lock()
+	defer mtx.Unlock()
+	require.Equal(t, map[string]string{
+		"/blerg/traces/tenant-a/" + blockID.String() + "/object":                       "tenant-a-key",
+		"/blerg/traces/tenant-b/" + blockID.String() + "/object":                       "key",
+		"/blerg/traces/tenant-a/" + blockID.String() + "/" + backend.CompactedMetaName: "tenant-a-key",
+	}, keys)
+}
+
 func TestObjectStorageClass(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -604,6 +1011,83 @@ func TestObjectStorageClass(t *testing.T) {
 	}
 }
 
+func TestPutObjectOptions(t *testing.T) {
+	rw := &readerWriter{cfg: &Config{
+		PartSize:              32 * 1024 * 1024,
+		PartUploadConcurrency: 8,
+		StorageClass:          "STANDARD",
+	}}
+
+	opts := getPutObjectOptions(rw, "tenant")
+	assert.Equal(t, uint64(32*1024*1024), opts.PartSize)
+	assert.Equal(t, uint(8), opts.NumThreads)
+	assert.Equal(t, "STANDARD", opts.StorageClass)
+}
+
+func TestRequesterPays(t *testing.T) {
+	for _, requesterPays := range []bool{false, true} {
+		t.Run(fmt.Sprintf("requester pays %t", requesterPays), func(t *testing.T) {
+			var (
+				mtx     sync.Mutex
+				headers []http.Header
+			)
+			server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				mtx.Lock()
+				headers = append(headers, r.Header.Clone())
+				mtx.Unlock()
+
+				switch {
+				case r.Method == http.MethodDelete:
+					w.WriteHeader(http.StatusNoContent)
+				case r.Header.Get("X-Amz-Copy-Source") != "":
+					_, _ = w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?><CopyObjectResult></CopyObjectResult>`))
+				case r.URL.Query().Has("list-type"):
+					_, _ = w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?><ListBucketResult></ListBucketResult>`))
+				case r.Method == getMethod:
+					w.Header().Set("Last-Modified", time.Now().UTC().Format(http.TimeFormat))
+					_, _ = w.Write([]byte("data"))
+				}
+			}))
+			t.Cleanup(server.Close)
+
+			cfg := &Config{
+				Region:        "blerg",
+				AccessKey:     "test",
+				SecretKey:     flagext.SecretWithValue("test"),
+				Bucket:        "blerg",
+				Endpoint:      server.URL[8:],
+				RequesterPays: requesterPays,
+			}
+			cfg.InsecureSkipVerify = true
+			r, w, c, err := NewNoConfirm(cfg)
+			require.NoError(t, err)
+
+			ctx := context.Background()
+			require.NoError(t, w.Write(ctx, "object", backend.KeyPath{"test"}, bytes.NewReader([]byte("data")), 4, nil))
+			_, _, err = r.Read(ctx, "object", backend.KeyPath{"test"}, nil)
+			require.NoError(t, err)
+			_, _, err = r.ListBlocks(ctx, "single-tenant")
+			require.NoError(t, err)
+			require.NoError(t, c.MarkBlockCompacted(uuid.New(), "single-tenant"))
+			require.NoError(t, w.Delete(ctx, "object", backend.KeyPath{"test"}, nil))
+
+			mtx.Lock()
+			defer mtx.Unlock()
+			require.GreaterOrEqual(t, len(headers), 5)
+			for _, h := range headers {
+				if !requesterPays {
+					require.Empty(t, h.Get("X-Amz-Request-Payer"))
+					continue
+				}
+				// the header must be signed
+				require.Equal(t, "requester", h.Get("X-Amz-Request-Payer"))
+				require.Contains(t, h.Get("Authorization"), "x-amz-request-payer")
+				require.Contains(t, h.Get("Authorization"), "Credential=test/")
+			}
+		})
+	}
+}
+
 func testServer(t *testing.T, httpHandler http.HandlerFunc) *httptest.Server {
 	t.Helper()
 	assert.NotNil(t, httpHandler)
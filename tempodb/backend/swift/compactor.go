@@ -0,0 +1,90 @@
+package swift
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/ncw/swift/v2"
+
+	"github.com/grafana/tempo/tempodb/backend"
+)
+
+func (rw *readerWriter) MarkBlockCompacted(blockID uuid.UUID, tenantID string) error {
+	if len(tenantID) == 0 {
+		return backend.ErrEmptyTenantID
+	}
+	if blockID == uuid.Nil {
+		return backend.ErrEmptyBlockID
+	}
+
+	ctx := context.TODO()
+	metaFilename := backend.MetaFileName(blockID, tenantID, rw.cfg.Prefix)
+	compactedMetaFilename := backend.CompactedMetaFileName(blockID, tenantID, rw.cfg.Prefix)
+
+	// copy meta.json to meta.compacted.json
+	_, err := rw.conn.ObjectCopy(ctx, rw.cfg.ContainerName, metaFilename, rw.cfg.ContainerName, compactedMetaFilename, nil)
+	if err != nil {
+		return fmt.Errorf("error copying obj meta to compacted obj meta: %w", err)
+	}
+
+	// delete meta.json
+	return readError(rw.conn.ObjectDelete(ctx, rw.cfg.ContainerName, metaFilename))
+}
+
+func (rw *readerWriter) ClearBlock(blockID uuid.UUID, tenantID string) error {
+	if len(tenantID) == 0 {
+		return backend.ErrEmptyTenantID
+	}
+	if blockID == uuid.Nil {
+		return backend.ErrEmptyBlockID
+	}
+
+	// the segments of large objects are stored under the block and removed with it
+	ctx := context.TODO()
+	names, err := rw.conn.ObjectNamesAll(ctx, rw.cfg.ContainerName, &swift.ObjectsOpts{
+		Prefix: backend.RootPath(blockID, tenantID, rw.cfg.Prefix) + "/",
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		if err := rw.conn.ObjectDelete(ctx, rw.cfg.ContainerName, name); err != nil && !errors.Is(err, swift.ObjectNotFound) {
+			return err
+		}
+	}
+	return nil
+}
+
+func (rw *readerWriter) CompactedBlockMeta(blockID uuid.UUID, tenantID string) (*backend.CompactedBlockMeta, error) {
+	if len(tenantID) == 0 {
+		return nil, backend.ErrEmptyTenantID
+	}
+	if blockID == uuid.Nil {
+		return nil, backend.ErrEmptyBlockID
+	}
+
+	name := backend.CompactedMetaFileName(blockID, tenantID, rw.cfg.Prefix)
+
+	bytes, headers, err := rw.readAll(context.Background(), name)
+	if err != nil {
+		return nil, readError(err)
+	}
+
+	out := &backend.CompactedBlockMeta{}
+	err = json.Unmarshal(bytes, out)
+	if err != nil {
+		return nil, err
+	}
+
+	out.CompactedTime, err = http.ParseTime(headers["Last-Modified"])
+	if err != nil {
+		return nil, fmt.Errorf("error parsing last modified of %s: %w", name, err)
+	}
+
+	return out, nil
+}
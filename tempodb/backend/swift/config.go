@@ -0,0 +1,54 @@
+package swift
+
+import (
+	"flag"
+	"time"
+
+	"github.com/grafana/dskit/flagext"
+
+	"github.com/grafana/tempo/pkg/util"
+)
+
+type Config struct {
+	// AuthVersion is the keystone version. 0 detects it from the auth url
+	AuthVersion       int            `yaml:"auth_version"`
+	AuthURL           string         `yaml:"auth_url"`
+	Internal          bool           `yaml:"internal"`
+	Username          string         `yaml:"username"`
+	UserDomainName    string         `yaml:"user_domain_name"`
+	UserDomainID      string         `yaml:"user_domain_id"`
+	UserID            string         `yaml:"user_id"`
+	Password          flagext.Secret `yaml:"password"`
+	DomainID          string         `yaml:"domain_id"`
+	DomainName        string         `yaml:"domain_name"`
+	ProjectID         string         `yaml:"project_id"`
+	ProjectName       string         `yaml:"project_name"`
+	ProjectDomainID   string         `yaml:"project_domain_id"`
+	ProjectDomainName string         `yaml:"project_domain_name"`
+	RegionName        string         `yaml:"region_name"`
+	ContainerName     string         `yaml:"container_name"`
+	Prefix            string         `yaml:"prefix"`
+	MaxRetries        int            `yaml:"max_retries"`
+	ConnectTimeout    time.Duration  `yaml:"connect_timeout"`
+	RequestTimeout    time.Duration  `yaml:"request_timeout"`
+	// SegmentSizeBytes is the size of the segments of static large objects. Objects larger than a segment, and all
+	// objects written in parts, are uploaded as static large objects
+	SegmentSizeBytes int64 `yaml:"segment_size_bytes"`
+}
+
+func (cfg *Config) RegisterFlagsAndApplyDefaults(prefix string, f *flag.FlagSet) {
+	f.StringVar(&cfg.AuthURL, util.PrefixConfig(prefix, "swift.auth_url"), "", "swift keystone auth url.")
+	f.StringVar(&cfg.ContainerName, util.PrefixConfig(prefix, "swift.container_name"), "", "swift container to store traces in.")
+	f.StringVar(&cfg.Prefix, util.PrefixConfig(prefix, "swift.prefix"), "", "swift container prefix to store traces in.")
+	f.StringVar(&cfg.Username, util.PrefixConfig(prefix, "swift.username"), "", "swift username.")
+	f.Var(&cfg.Password, util.PrefixConfig(prefix, "swift.password"), "swift password or api key.")
+	cfg.MaxRetries = 3
+	cfg.ConnectTimeout = 10 * time.Second
+	cfg.RequestTimeout = 5 * time.Minute
+	cfg.SegmentSizeBytes = 100 * 1024 * 1024
+}
+
+func (cfg *Config) PathMatches(other *Config) bool {
+	return cfg.AuthURL == other.AuthURL && cfg.ProjectID == other.ProjectID && cfg.ProjectName == other.ProjectName &&
+		cfg.ContainerName == other.ContainerName && cfg.Prefix == other.Prefix
+}
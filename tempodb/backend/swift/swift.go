@@ -0,0 +1,382 @@
+package swift
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/ncw/swift/v2"
+	"github.com/opentracing/opentracing-go"
+
+	tempo_io "github.com/grafana/tempo/pkg/io"
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/backend/instrumentation"
+)
+
+// segmentsSuffix is appended to the name of a static large object to get the prefix of its segments. The segments are
+// stored next to the object so they are removed with the block.
+const segmentsSuffix = ".segments"
+
+type readerWriter struct {
+	cfg  *Config
+	conn *swift.Connection
+}
+
+var (
+	_ backend.RawReader = (*readerWriter)(nil)
+	_ backend.RawWriter = (*readerWriter)(nil)
+	_ backend.Compactor = (*readerWriter)(nil)
+)
+
+// appendTracker is the static large object of an append job
+type appendTracker struct {
+	file swift.LargeObjectFile
+}
+
+// NewNoConfirm gets the Swift backend without testing it
+func NewNoConfirm(cfg *Config) (backend.RawReader, backend.RawWriter, backend.Compactor, error) {
+	rw, err := internalNew(cfg, false)
+	return rw, rw, rw, err
+}
+
+// New gets the Swift backend
+func New(cfg *Config) (backend.RawReader, backend.RawWriter, backend.Compactor, error) {
+	rw, err := internalNew(cfg, true)
+	return rw, rw, rw, err
+}
+
+// NewVersionedReaderWriter gets the Swift backend for versioned objects. Swift has no conditional writes so the
+// versions are not checked.
+func NewVersionedReaderWriter(cfg *Config) (backend.VersionedReaderWriter, error) {
+	rw, err := internalNew(cfg, true)
+	if err != nil {
+		return nil, err
+	}
+	return backend.NewFakeVersionedReaderWriter(rw, rw), nil
+}
+
+func internalNew(cfg *Config, confirm bool) (*readerWriter, error) {
+	if cfg == nil {
+		return nil, fmt.Errorf("config is nil")
+	}
+
+	conn := createConnection(cfg)
+
+	// authenticate and check the container exists
+	if confirm {
+		ctx := context.Background()
+		if err := conn.Authenticate(ctx); err != nil {
+			return nil, fmt.Errorf("failed to authenticate: %w", err)
+		}
+		if _, _, err := conn.Container(ctx, cfg.ContainerName); err != nil {
+			return nil, fmt.Errorf("failed to get container %s: %w", cfg.ContainerName, err)
+		}
+	}
+
+	return &readerWriter{
+		cfg:  cfg,
+		conn: conn,
+	}, nil
+}
+
+// Write implements backend.Writer
+func (rw *readerWriter) Write(ctx context.Context, name string, keypath backend.KeyPath, data io.Reader, size int64, _ *backend.CacheInfo) error {
+	keypath = backend.KeyPathWithPrefix(keypath, rw.cfg.Prefix)
+	span, derivedCtx := opentracing.StartSpanFromContext(ctx, "swift.Write")
+	defer span.Finish()
+
+	span.SetTag("object", name)
+
+	objectName := backend.ObjectFileName(keypath, name)
+
+	// objects that don't fit in a segment are uploaded as static large objects
+	if size > rw.cfg.SegmentSizeBytes {
+		f, err := rw.largeObject(derivedCtx, objectName)
+		if err != nil {
+			span.SetTag("error", true)
+			return err
+		}
+		if _, err = io.Copy(f, data); err != nil {
+			_ = f.CloseWithContext(derivedCtx)
+			span.SetTag("error", true)
+			return fmt.Errorf("failed to write: %w", err)
+		}
+		return f.CloseWithContext(derivedCtx)
+	}
+
+	_, err := rw.conn.ObjectPut(derivedCtx, rw.cfg.ContainerName, objectName, data, false, "", "", nil)
+	if err != nil {
+		span.SetTag("error", true)
+		return fmt.Errorf("error writing object to swift backend, object %s: %w", objectName, err)
+	}
+	return nil
+}
+
+// Append implements backend.Writer
+func (rw *readerWriter) Append(ctx context.Context, name string, keypath backend.KeyPath, tracker backend.AppendTracker, buffer []byte) (backend.AppendTracker, error) {
+	keypath = backend.KeyPathWithPrefix(keypath, rw.cfg.Prefix)
+	span, ctx := opentracing.StartSpanFromContext(ctx, "swift.Append", opentracing.Tags{
+		"len": len(buffer),
+	})
+	defer span.Finish()
+
+	var a appendTracker
+	if tracker != nil {
+		a = tracker.(appendTracker)
+	} else {
+		f, err := rw.largeObject(ctx, backend.ObjectFileName(keypath, name))
+		if err != nil {
+			return nil, err
+		}
+		a.file = f
+	}
+
+	if _, err := a.file.WriteWithContext(ctx, buffer); err != nil {
+		return a, fmt.Errorf("error in large object upload: %w", err)
+	}
+	return a, nil
+}
+
+// CloseAppend implements backend.Writer
+func (rw *readerWriter) CloseAppend(ctx context.Context, tracker backend.AppendTracker) error {
+	if tracker == nil {
+		return nil
+	}
+
+	a := tracker.(appendTracker)
+	return a.file.CloseWithContext(ctx)
+}
+
+// Delete implements backend.Writer
+func (rw *readerWriter) Delete(ctx context.Context, name string, keypath backend.KeyPath, _ *backend.CacheInfo) error {
+	keypath = backend.KeyPathWithPrefix(keypath, rw.cfg.Prefix)
+	return readError(rw.conn.ObjectDelete(ctx, rw.cfg.ContainerName, backend.ObjectFileName(keypath, name)))
+}
+
+// List implements backend.Reader
+func (rw *readerWriter) List(ctx context.Context, keypath backend.KeyPath) ([]string, error) {
+	keypath = backend.KeyPathWithPrefix(keypath, rw.cfg.Prefix)
+	prefix := path.Join(keypath...)
+	if len(prefix) > 0 {
+		prefix += "/"
+	}
+
+	objects, err := rw.conn.ObjectsAll(ctx, rw.cfg.ContainerName, &swift.ObjectsOpts{
+		Prefix:    prefix,
+		Delimiter: '/',
+	})
+	if err != nil {
+		return nil, fmt.Errorf("listing objects: %w", err)
+	}
+
+	var names []string
+	for _, o := range objects {
+		if !o.PseudoDirectory {
+			continue
+		}
+		names = append(names, strings.TrimSuffix(strings.TrimPrefix(o.Name, prefix), "/"))
+	}
+	return names, nil
+}
+
+// ListBlocks implements backend.Reader
+func (rw *readerWriter) ListBlocks(ctx context.Context, tenant string) ([]uuid.UUID, []uuid.UUID, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "readerWriter.ListBlocks")
+	defer span.Finish()
+
+	keypath := backend.KeyPathWithPrefix(backend.KeyPath{tenant}, rw.cfg.Prefix)
+	prefix := path.Join(keypath...)
+	if len(prefix) > 0 {
+		prefix += "/"
+	}
+
+	var (
+		blockIDs          = make([]uuid.UUID, 0, 1000)
+		compactedBlockIDs = make([]uuid.UUID, 0, 1000)
+	)
+	err := rw.conn.ObjectsWalk(ctx, rw.cfg.ContainerName, &swift.ObjectsOpts{Prefix: prefix}, func(ctx context.Context, opts *swift.ObjectsOpts) (interface{}, error) {
+		names, err := rw.conn.ObjectNames(ctx, rw.cfg.ContainerName, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, name := range names {
+			// ie: <blockID>/meta.json
+			parts := strings.Split(strings.TrimPrefix(name, prefix), "/")
+			if len(parts) != 2 {
+				continue
+			}
+
+			id, err := uuid.Parse(parts[0])
+			if err != nil {
+				continue
+			}
+
+			switch parts[1] {
+			case backend.MetaName:
+				blockIDs = append(blockIDs, id)
+			case backend.CompactedMetaName:
+				compactedBlockIDs = append(compactedBlockIDs, id)
+			}
+		}
+		return names, nil
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("iterating blocks: %w", err)
+	}
+
+	return blockIDs, compactedBlockIDs, nil
+}
+
+// Find implements backend.Reader
+func (rw *readerWriter) Find(ctx context.Context, keypath backend.KeyPath, f backend.FindFunc) error {
+	keypath = backend.KeyPathWithPrefix(keypath, rw.cfg.Prefix)
+	prefix := path.Join(keypath...)
+	if len(prefix) > 0 {
+		prefix += "/"
+	}
+
+	objects, err := rw.conn.ObjectsAll(ctx, rw.cfg.ContainerName, &swift.ObjectsOpts{Prefix: prefix})
+	if err != nil {
+		return fmt.Errorf("iterating objects: %w", err)
+	}
+
+	for _, o := range objects {
+		f(backend.FindMatch{
+			Key:      o.Name,
+			Modified: o.LastModified,
+		})
+	}
+	return nil
+}
+
+// Read implements backend.Reader
+func (rw *readerWriter) Read(ctx context.Context, name string, keypath backend.KeyPath, _ *backend.CacheInfo) (io.ReadCloser, int64, error) {
+	keypath = backend.KeyPathWithPrefix(keypath, rw.cfg.Prefix)
+	span, derivedCtx := opentracing.StartSpanFromContext(ctx, "swift.Read")
+	defer span.Finish()
+
+	span.SetTag("object", name)
+
+	b, _, err := rw.readAll(derivedCtx, backend.ObjectFileName(keypath, name))
+	if err != nil {
+		span.SetTag("error", true)
+	}
+	return io.NopCloser(bytes.NewReader(b)), int64(len(b)), readError(err)
+}
+
+// ReadRange implements backend.Reader
+func (rw *readerWriter) ReadRange(ctx context.Context, name string, keypath backend.KeyPath, offset uint64, buffer []byte, _ *backend.CacheInfo) error {
+	keypath = backend.KeyPathWithPrefix(keypath, rw.cfg.Prefix)
+	span, derivedCtx := opentracing.StartSpanFromContext(ctx, "swift.ReadRange", opentracing.Tags{
+		"len":    len(buffer),
+		"offset": offset,
+	})
+	defer span.Finish()
+
+	if len(buffer) == 0 {
+		return nil
+	}
+
+	f, _, err := rw.conn.ObjectOpen(derivedCtx, rw.cfg.ContainerName, backend.ObjectFileName(keypath, name), false, swift.Headers{
+		"Range": fmt.Sprintf("bytes=%d-%d", offset, offset+uint64(len(buffer))-1),
+	})
+	if err != nil {
+		span.SetTag("error", true)
+		return readError(err)
+	}
+	defer f.Close()
+
+	_, err = io.ReadFull(f, buffer)
+	if err != nil {
+		span.SetTag("error", true)
+	}
+	return readError(err)
+}
+
+// Shutdown implements backend.Reader
+func (rw *readerWriter) Shutdown() {
+}
+
+// largeObject creates a static large object. The segments are stored next to the object.
+func (rw *readerWriter) largeObject(ctx context.Context, objectName string) (swift.LargeObjectFile, error) {
+	f, err := rw.conn.StaticLargeObjectCreate(ctx, &swift.LargeObjectOpts{
+		Container:        rw.cfg.ContainerName,
+		ObjectName:       objectName,
+		ChunkSize:        rw.cfg.SegmentSizeBytes,
+		SegmentContainer: rw.cfg.ContainerName,
+		SegmentPrefix:    objectName + segmentsSuffix,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error creating large object %s: %w", objectName, err)
+	}
+	return f, nil
+}
+
+func (rw *readerWriter) readAll(ctx context.Context, name string) ([]byte, swift.Headers, error) {
+	f, headers, err := rw.conn.ObjectOpen(ctx, rw.cfg.ContainerName, name, false, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer f.Close()
+
+	length, err := f.Length(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	buf, err := tempo_io.ReadAllWithEstimate(f, length)
+	if err != nil {
+		return nil, nil, err
+	}
+	return buf, headers, nil
+}
+
+func createConnection(cfg *Config) *swift.Connection {
+	// add instrumentation
+	customTransport := http.DefaultTransport.(*http.Transport).Clone()
+	customTransport.MaxIdleConnsPerHost = 100
+
+	conn := &swift.Connection{
+		AuthVersion:    cfg.AuthVersion,
+		AuthUrl:        cfg.AuthURL,
+		Internal:       cfg.Internal,
+		ApiKey:         cfg.Password.String(),
+		UserName:       cfg.Username,
+		UserId:         cfg.UserID,
+		Retries:        cfg.MaxRetries,
+		ConnectTimeout: cfg.ConnectTimeout,
+		Timeout:        cfg.RequestTimeout,
+		TenantId:       cfg.ProjectID,
+		Tenant:         cfg.ProjectName,
+		TenantDomain:   cfg.ProjectDomainName,
+		TenantDomainId: cfg.ProjectDomainID,
+		Domain:         cfg.DomainName,
+		DomainId:       cfg.DomainID,
+		Region:         cfg.RegionName,
+		Transport:      instrumentation.NewTransport(customTransport),
+	}
+
+	// the domain of the user takes precedence over the domain of the project
+	if cfg.UserDomainID != "" {
+		conn.DomainId = cfg.UserDomainID
+	}
+	if cfg.UserDomainName != "" {
+		conn.Domain = cfg.UserDomainName
+	}
+
+	return conn
+}
+
+func readError(err error) error {
+	if errors.Is(err, swift.ObjectNotFound) {
+		return backend.ErrDoesNotExist
+	}
+	return err
+}
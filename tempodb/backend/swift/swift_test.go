@@ -0,0 +1,170 @@
+package swift
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/grafana/dskit/flagext"
+	"github.com/ncw/swift/v2"
+	"github.com/ncw/swift/v2/swifttest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/tempodb/backend"
+)
+
+const testContainer = "tempo"
+
+func TestReadWrite(t *testing.T) {
+	rw := newTestReaderWriter(t, 1024)
+	ctx := context.Background()
+
+	keypath := backend.KeyPath{"tenant", uuid.New().String()}
+	data := []byte("some data to store")
+
+	err := rw.Write(ctx, "object", keypath, bytes.NewReader(data), int64(len(data)), nil)
+	require.NoError(t, err)
+
+	r, size, err := rw.Read(ctx, "object", keypath, nil)
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(data)), size)
+	actual, err := io.ReadAll(r)
+	require.NoError(t, err)
+	assert.Equal(t, data, actual)
+
+	buffer := make([]byte, 4)
+	err = rw.ReadRange(ctx, "object", keypath, 5, buffer, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("data"), buffer)
+
+	_, _, err = rw.Read(ctx, "missing", keypath, nil)
+	assert.ErrorIs(t, err, backend.ErrDoesNotExist)
+
+	err = rw.Delete(ctx, "object", keypath, nil)
+	require.NoError(t, err)
+	_, _, err = rw.Read(ctx, "object", keypath, nil)
+	assert.ErrorIs(t, err, backend.ErrDoesNotExist)
+}
+
+func TestLargeObjects(t *testing.T) {
+	rw := newTestReaderWriter(t, 8)
+	ctx := context.Background()
+
+	keypath := backend.KeyPath{"tenant", uuid.New().String()}
+
+	// write exceeding the segment size
+	data := []byte("a large object made of segments")
+	err := rw.Write(ctx, "written", keypath, bytes.NewReader(data), int64(len(data)), nil)
+	require.NoError(t, err)
+
+	r, _, err := rw.Read(ctx, "written", keypath, nil)
+	require.NoError(t, err)
+	actual, err := io.ReadAll(r)
+	require.NoError(t, err)
+	assert.Equal(t, data, actual)
+
+	// append in several calls
+	var tracker backend.AppendTracker
+	for _, b := range [][]byte{[]byte("0123456789"), []byte("abc"), []byte("defghijklmn")} {
+		tracker, err = rw.Append(ctx, "appended", keypath, tracker, b)
+		require.NoError(t, err)
+	}
+	require.NoError(t, rw.CloseAppend(ctx, tracker))
+
+	r, _, err = rw.Read(ctx, "appended", keypath, nil)
+	require.NoError(t, err)
+	actual, err = io.ReadAll(r)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("0123456789abcdefghijklmn"), actual)
+}
+
+func TestListBlocks(t *testing.T) {
+	rw := newTestReaderWriter(t, 1024)
+	ctx := context.Background()
+
+	blockID := uuid.New()
+	compactedBlockID := uuid.New()
+	for _, o := range []struct {
+		id   uuid.UUID
+		name string
+	}{
+		{blockID, backend.MetaName},
+		{blockID, "data"},
+		{compactedBlockID, backend.CompactedMetaName},
+	} {
+		err := rw.Write(ctx, o.name, backend.KeyPathForBlock(o.id, "tenant"), bytes.NewReader([]byte("{}")), 2, nil)
+		require.NoError(t, err)
+	}
+
+	tenants, err := rw.List(ctx, backend.KeyPath{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"tenant"}, tenants)
+
+	blocks, compacted, err := rw.ListBlocks(ctx, "tenant")
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{blockID}, blocks)
+	assert.Equal(t, []uuid.UUID{compactedBlockID}, compacted)
+}
+
+func TestCompactor(t *testing.T) {
+	rw := newTestReaderWriter(t, 8)
+	ctx := context.Background()
+
+	blockID := uuid.New()
+	keypath := backend.KeyPathForBlock(blockID, "tenant")
+
+	meta := []byte(`{"format":"vParquet3"}`)
+	require.NoError(t, rw.Write(ctx, backend.MetaName, keypath, bytes.NewReader(meta), int64(len(meta)), nil))
+	data := []byte("data stored as a large object")
+	require.NoError(t, rw.Write(ctx, "data", keypath, bytes.NewReader(data), int64(len(data)), nil))
+
+	_, err := rw.CompactedBlockMeta(blockID, "tenant")
+	assert.ErrorIs(t, err, backend.ErrDoesNotExist)
+
+	require.NoError(t, rw.MarkBlockCompacted(blockID, "tenant"))
+
+	compactedMeta, err := rw.CompactedBlockMeta(blockID, "tenant")
+	require.NoError(t, err)
+	assert.Equal(t, "vParquet3", compactedMeta.Version)
+	assert.WithinDuration(t, time.Now(), compactedMeta.CompactedTime, time.Minute)
+
+	blocks, compacted, err := rw.ListBlocks(ctx, "tenant")
+	require.NoError(t, err)
+	assert.Empty(t, blocks)
+	assert.Equal(t, []uuid.UUID{blockID}, compacted)
+
+	require.NoError(t, rw.ClearBlock(blockID, "tenant"))
+
+	names, err := rw.conn.ObjectNamesAll(ctx, testContainer, nil)
+	require.NoError(t, err)
+	assert.Empty(t, names)
+}
+
+func newTestReaderWriter(t *testing.T, segmentSize int64) *readerWriter {
+	server, err := swifttest.NewSwiftServer("localhost")
+	require.NoError(t, err)
+	t.Cleanup(server.Close)
+
+	cfg := &Config{
+		AuthVersion:      1,
+		AuthURL:          server.AuthURL,
+		Username:         swifttest.TEST_ACCOUNT,
+		Password:         flagext.SecretWithValue(swifttest.TEST_ACCOUNT),
+		ContainerName:    testContainer,
+		MaxRetries:       1,
+		ConnectTimeout:   time.Second,
+		RequestTimeout:   time.Minute,
+		SegmentSizeBytes: segmentSize,
+	}
+
+	conn := createConnection(cfg)
+	require.NoError(t, conn.ContainerCreate(context.Background(), testContainer, swift.Headers{}))
+
+	rw, err := internalNew(cfg, true)
+	require.NoError(t, err)
+	return rw
+}
@@ -0,0 +1,334 @@
+package tiering
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"slices"
+	"sync"
+
+	"github.com/google/uuid"
+
+	"github.com/grafana/tempo/tempodb/backend"
+)
+
+// Backend is the backend of a tier
+type Backend struct {
+	Reader    backend.RawReader
+	Writer    backend.RawWriter
+	Compactor backend.Compactor
+}
+
+// Mover moves blocks from the hot tier to the cold tier
+type Mover interface {
+	// CopyToColdTier copies the block to a new block in the cold tier and returns the meta of the new block. The
+	// block is left unchanged in the hot tier so it can be marked compacted and removed by the retention.
+	CopyToColdTier(ctx context.Context, meta *backend.BlockMeta) (*backend.BlockMeta, error)
+}
+
+type readerWriter struct {
+	hot  Backend
+	cold Backend
+
+	// blocks of the tenants known to be in the cold tier. their objects are read from the cold tier first
+	coldBlocksMtx sync.RWMutex
+	coldBlocks    map[string]map[uuid.UUID]struct{}
+}
+
+var (
+	_ backend.RawReader = (*readerWriter)(nil)
+	_ backend.RawWriter = (*readerWriter)(nil)
+	_ backend.Compactor = (*readerWriter)(nil)
+	_ Mover             = (*readerWriter)(nil)
+)
+
+// New returns a backend that writes to the hot tier and reads the blocks from both tiers. Objects of blocks are read
+// from the tier the block is known to be in and from the other tier if they don't exist there, so blocks can be read
+// while they are moved. All objects that are not part of a block are only stored in the hot tier.
+func New(hot, cold Backend) (backend.RawReader, backend.RawWriter, backend.Compactor, Mover) {
+	rw := &readerWriter{
+		hot:        hot,
+		cold:       cold,
+		coldBlocks: map[string]map[uuid.UUID]struct{}{},
+	}
+	return rw, rw, rw, rw
+}
+
+// List implements backend.RawReader
+func (r *readerWriter) List(ctx context.Context, keypath backend.KeyPath) ([]string, error) {
+	objects, err := r.hot.Reader.List(ctx, keypath)
+	if err != nil {
+		return nil, err
+	}
+
+	coldObjects, err := r.cold.Reader.List(ctx, keypath)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, o := range coldObjects {
+		if !slices.Contains(objects, o) {
+			objects = append(objects, o)
+		}
+	}
+	return objects, nil
+}
+
+// ListBlocks implements backend.RawReader
+func (r *readerWriter) ListBlocks(ctx context.Context, tenant string) ([]uuid.UUID, []uuid.UUID, error) {
+	blockIDs, compactedBlockIDs, err := r.hot.Reader.ListBlocks(ctx, tenant)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	coldBlockIDs, coldCompactedBlockIDs, err := r.cold.Reader.ListBlocks(ctx, tenant)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// blocks that are being moved are in both tiers
+	coldBlocks := make(map[uuid.UUID]struct{}, len(coldBlockIDs))
+	for _, id := range coldBlockIDs {
+		coldBlocks[id] = struct{}{}
+		if !slices.Contains(blockIDs, id) {
+			blockIDs = append(blockIDs, id)
+		}
+	}
+	for _, id := range coldCompactedBlockIDs {
+		if !slices.Contains(compactedBlockIDs, id) {
+			compactedBlockIDs = append(compactedBlockIDs, id)
+		}
+	}
+
+	r.coldBlocksMtx.Lock()
+	r.coldBlocks[tenant] = coldBlocks
+	r.coldBlocksMtx.Unlock()
+
+	return blockIDs, compactedBlockIDs, nil
+}
+
+// Find implements backend.RawReader
+func (r *readerWriter) Find(ctx context.Context, keypath backend.KeyPath, f backend.FindFunc) error {
+	if err := r.hot.Reader.Find(ctx, keypath, f); err != nil {
+		return err
+	}
+	return r.cold.Reader.Find(ctx, keypath, f)
+}
+
+// Read implements backend.RawReader
+func (r *readerWriter) Read(ctx context.Context, name string, keypath backend.KeyPath, cacheInfo *backend.CacheInfo) (io.ReadCloser, int64, error) {
+	tenantID, blockID, ok := blockOf(keypath)
+	if !ok {
+		return r.hot.Reader.Read(ctx, name, keypath, cacheInfo)
+	}
+
+	first, second, cold := r.tiers(tenantID, blockID)
+	rc, size, err := first.Reader.Read(ctx, name, keypath, cacheInfo)
+	if errors.Is(err, backend.ErrDoesNotExist) {
+		rc, size, err = second.Reader.Read(ctx, name, keypath, cacheInfo)
+		if err == nil {
+			r.located(tenantID, blockID, !cold)
+		}
+	}
+	return rc, size, err
+}
+
+// ReadRange implements backend.RawReader
+func (r *readerWriter) ReadRange(ctx context.Context, name string, keypath backend.KeyPath, offset uint64, buffer []byte, cacheInfo *backend.CacheInfo) error {
+	tenantID, blockID, ok := blockOf(keypath)
+	if !ok {
+		return r.hot.Reader.ReadRange(ctx, name, keypath, offset, buffer, cacheInfo)
+	}
+
+	first, second, cold := r.tiers(tenantID, blockID)
+	err := first.Reader.ReadRange(ctx, name, keypath, offset, buffer, cacheInfo)
+	if errors.Is(err, backend.ErrDoesNotExist) {
+		err = second.Reader.ReadRange(ctx, name, keypath, offset, buffer, cacheInfo)
+		if err == nil {
+			r.located(tenantID, blockID, !cold)
+		}
+	}
+	return err
+}
+
+// Shutdown implements backend.RawReader
+func (r *readerWriter) Shutdown() {
+	r.hot.Reader.Shutdown()
+	r.cold.Reader.Shutdown()
+}
+
+// Write implements backend.RawWriter
+func (r *readerWriter) Write(ctx context.Context, name string, keypath backend.KeyPath, data io.Reader, size int64, cacheInfo *backend.CacheInfo) error {
+	return r.hot.Writer.Write(ctx, name, keypath, data, size, cacheInfo)
+}
+
+// Append implements backend.RawWriter
+func (r *readerWriter) Append(ctx context.Context, name string, keypath backend.KeyPath, tracker backend.AppendTracker, buffer []byte) (backend.AppendTracker, error) {
+	return r.hot.Writer.Append(ctx, name, keypath, tracker, buffer)
+}
+
+// CloseAppend implements backend.RawWriter
+func (r *readerWriter) CloseAppend(ctx context.Context, tracker backend.AppendTracker) error {
+	return r.hot.Writer.CloseAppend(ctx, tracker)
+}
+
+// Delete implements backend.RawWriter
+func (r *readerWriter) Delete(ctx context.Context, name string, keypath backend.KeyPath, cacheInfo *backend.CacheInfo) error {
+	tenantID, blockID, ok := blockOf(keypath)
+	if !ok {
+		return r.hot.Writer.Delete(ctx, name, keypath, cacheInfo)
+	}
+
+	first, _, _ := r.tiers(tenantID, blockID)
+	return first.Writer.Delete(ctx, name, keypath, cacheInfo)
+}
+
+// MarkBlockCompacted implements backend.Compactor
+func (r *readerWriter) MarkBlockCompacted(blockID uuid.UUID, tenantID string) error {
+	b, err := r.locate(context.Background(), blockID, tenantID)
+	if err != nil {
+		return err
+	}
+	return b.Compactor.MarkBlockCompacted(blockID, tenantID)
+}
+
+// ClearBlock implements backend.Compactor
+func (r *readerWriter) ClearBlock(blockID uuid.UUID, tenantID string) error {
+	if err := r.hot.Compactor.ClearBlock(blockID, tenantID); err != nil {
+		return err
+	}
+	if err := r.cold.Compactor.ClearBlock(blockID, tenantID); err != nil {
+		return err
+	}
+
+	r.coldBlocksMtx.Lock()
+	delete(r.coldBlocks[tenantID], blockID)
+	r.coldBlocksMtx.Unlock()
+	return nil
+}
+
+// CompactedBlockMeta implements backend.Compactor
+func (r *readerWriter) CompactedBlockMeta(blockID uuid.UUID, tenantID string) (*backend.CompactedBlockMeta, error) {
+	first, second, _ := r.tiers(tenantID, blockID)
+	meta, err := first.Compactor.CompactedBlockMeta(blockID, tenantID)
+	if errors.Is(err, backend.ErrDoesNotExist) {
+		return second.Compactor.CompactedBlockMeta(blockID, tenantID)
+	}
+	return meta, err
+}
+
+// CopyToColdTier implements Mover. The objects of the block are copied before the meta of the new block so the new
+// block is complete once it is listed.
+func (r *readerWriter) CopyToColdTier(ctx context.Context, meta *backend.BlockMeta) (*backend.BlockMeta, error) {
+	keypath := backend.KeyPathForBlock(meta.BlockID, meta.TenantID)
+
+	var names []string
+	err := r.hot.Reader.Find(ctx, keypath, func(m backend.FindMatch) {
+		name := path.Base(m.Key)
+		if name != backend.MetaName && name != backend.CompactedMetaName {
+			names = append(names, name)
+		}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error finding objects of block: %w", err)
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("no objects found for block %s: %w", meta.BlockID, backend.ErrDoesNotExist)
+	}
+
+	newMeta := *meta
+	newMeta.BlockID = uuid.New()
+	newMeta.ColdTier = true
+	newKeypath := backend.KeyPathForBlock(newMeta.BlockID, newMeta.TenantID)
+
+	for _, name := range names {
+		if err := r.copy(ctx, name, keypath, newKeypath); err != nil {
+			return nil, fmt.Errorf("error copying object %s: %w", name, err)
+		}
+	}
+
+	buff, err := json.Marshal(&newMeta)
+	if err != nil {
+		return nil, err
+	}
+	err = r.cold.Writer.Write(ctx, backend.MetaName, newKeypath, bytes.NewReader(buff), int64(len(buff)), nil)
+	if err != nil {
+		return nil, fmt.Errorf("error writing meta of block in the cold tier: %w", err)
+	}
+	r.located(newMeta.TenantID, newMeta.BlockID, true)
+
+	return &newMeta, nil
+}
+
+func (r *readerWriter) copy(ctx context.Context, name string, from, to backend.KeyPath) error {
+	rc, size, err := r.hot.Reader.Read(ctx, name, from, nil)
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	return r.cold.Writer.Write(ctx, name, to, rc, size, nil)
+}
+
+// locate returns the tier that holds the meta of the block
+func (r *readerWriter) locate(ctx context.Context, blockID uuid.UUID, tenantID string) (Backend, error) {
+	first, second, _ := r.tiers(tenantID, blockID)
+
+	rc, _, err := first.Reader.Read(ctx, backend.MetaName, backend.KeyPathForBlock(blockID, tenantID), nil)
+	if errors.Is(err, backend.ErrDoesNotExist) {
+		return second, nil
+	}
+	if err != nil {
+		return Backend{}, err
+	}
+	_ = rc.Close()
+	return first, nil
+}
+
+// tiers returns the tier the block is known to be in followed by the other tier, and whether the block is known to
+// be in the cold tier
+func (r *readerWriter) tiers(tenantID string, blockID uuid.UUID) (Backend, Backend, bool) {
+	r.coldBlocksMtx.RLock()
+	_, cold := r.coldBlocks[tenantID][blockID]
+	r.coldBlocksMtx.RUnlock()
+
+	if cold {
+		return r.cold, r.hot, true
+	}
+	return r.hot, r.cold, false
+}
+
+// located records the tier the block was found in
+func (r *readerWriter) located(tenantID string, blockID uuid.UUID, cold bool) {
+	r.coldBlocksMtx.Lock()
+	defer r.coldBlocksMtx.Unlock()
+
+	if !cold {
+		delete(r.coldBlocks[tenantID], blockID)
+		return
+	}
+
+	blocks, ok := r.coldBlocks[tenantID]
+	if !ok {
+		blocks = map[uuid.UUID]struct{}{}
+		r.coldBlocks[tenantID] = blocks
+	}
+	blocks[blockID] = struct{}{}
+}
+
+// blockOf returns the tenant and the block of the objects under keypath
+func blockOf(keypath backend.KeyPath) (string, uuid.UUID, bool) {
+	if len(keypath) != 2 {
+		return "", uuid.UUID{}, false
+	}
+
+	blockID, err := uuid.Parse(keypath[1])
+	if err != nil {
+		return "", uuid.UUID{}, false
+	}
+	return keypath[0], blockID, true
+}
@@ -0,0 +1,98 @@
+package tiering
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/backend/local"
+)
+
+func TestTiering(t *testing.T) {
+	hot := newLocalBackend(t)
+	cold := newLocalBackend(t)
+
+	r, w, c, mover := New(hot, cold)
+	ctx := context.Background()
+
+	meta := backend.NewBlockMeta("tenant", uuid.New(), "vParquet4", backend.EncNone, "")
+	keypath := backend.KeyPathForBlock(meta.BlockID, meta.TenantID)
+	writeMeta(t, w, meta)
+	require.NoError(t, w.Write(ctx, "data", keypath, bytes.NewReader([]byte("data")), 4, nil))
+	require.NoError(t, w.Write(ctx, "index.json.gz", backend.KeyPath{"tenant"}, bytes.NewReader([]byte("index")), 5, nil))
+
+	newMeta, err := mover.CopyToColdTier(ctx, meta)
+	require.NoError(t, err)
+	require.NotEqual(t, meta.BlockID, newMeta.BlockID)
+	require.True(t, newMeta.ColdTier)
+
+	// the new block is only written to the cold tier
+	hotBlocks, _, err := hot.Reader.ListBlocks(ctx, "tenant")
+	require.NoError(t, err)
+	require.Equal(t, []uuid.UUID{meta.BlockID}, hotBlocks)
+	coldBlocks, _, err := cold.Reader.ListBlocks(ctx, "tenant")
+	require.NoError(t, err)
+	require.Equal(t, []uuid.UUID{newMeta.BlockID}, coldBlocks)
+
+	blocks, _, err := r.ListBlocks(ctx, "tenant")
+	require.NoError(t, err)
+	require.ElementsMatch(t, []uuid.UUID{meta.BlockID, newMeta.BlockID}, blocks)
+
+	// objects of blocks are read from both tiers
+	for _, id := range []uuid.UUID{meta.BlockID, newMeta.BlockID} {
+		rc, _, err := r.Read(ctx, "data", backend.KeyPathForBlock(id, "tenant"), nil)
+		require.NoError(t, err)
+		data, err := io.ReadAll(rc)
+		require.NoError(t, err)
+		require.NoError(t, rc.Close())
+		require.Equal(t, []byte("data"), data)
+
+		buffer := make([]byte, 2)
+		require.NoError(t, r.ReadRange(ctx, "data", backend.KeyPathForBlock(id, "tenant"), 2, buffer, nil))
+		require.Equal(t, []byte("ta"), buffer)
+	}
+
+	// other objects are only read from the hot tier
+	_, _, err = cold.Reader.Read(ctx, "index.json.gz", backend.KeyPath{"tenant"}, nil)
+	require.ErrorIs(t, err, backend.ErrDoesNotExist)
+	rc, _, err := r.Read(ctx, "index.json.gz", backend.KeyPath{"tenant"}, nil)
+	require.NoError(t, err)
+	require.NoError(t, rc.Close())
+
+	// blocks are compacted and cleared in their tier
+	require.NoError(t, c.MarkBlockCompacted(meta.BlockID, "tenant"))
+	require.NoError(t, c.MarkBlockCompacted(newMeta.BlockID, "tenant"))
+	_, err = hot.Compactor.CompactedBlockMeta(meta.BlockID, "tenant")
+	require.NoError(t, err)
+	_, err = c.CompactedBlockMeta(newMeta.BlockID, "tenant")
+	require.NoError(t, err)
+
+	require.NoError(t, c.ClearBlock(newMeta.BlockID, "tenant"))
+	_, _, err = r.Read(ctx, "data", backend.KeyPathForBlock(newMeta.BlockID, "tenant"), nil)
+	require.ErrorIs(t, err, backend.ErrDoesNotExist)
+}
+
+func TestCopyToColdTierMissingBlock(t *testing.T) {
+	_, _, _, mover := New(newLocalBackend(t), newLocalBackend(t))
+
+	_, err := mover.CopyToColdTier(context.Background(), backend.NewBlockMeta("tenant", uuid.New(), "vParquet4", backend.EncNone, ""))
+	require.ErrorIs(t, err, backend.ErrDoesNotExist)
+}
+
+func writeMeta(t *testing.T, w backend.RawWriter, meta *backend.BlockMeta) {
+	buff, err := json.Marshal(meta)
+	require.NoError(t, err)
+	require.NoError(t, w.Write(context.Background(), backend.MetaName, backend.KeyPathForBlock(meta.BlockID, meta.TenantID), bytes.NewReader(buff), int64(len(buff)), nil))
+}
+
+func newLocalBackend(t *testing.T) Backend {
+	r, w, c, err := local.New(&local.Config{Path: t.TempDir()})
+	require.NoError(t, err)
+	return Backend{Reader: r, Writer: w, Compactor: c}
+}
@@ -0,0 +1,67 @@
+package tempodb
+
+import (
+	"context"
+
+	"github.com/grafana/tempo/tempodb/backend"
+)
+
+// Blocks are claimed by the compactions and the rewrites of this compactor, like migrations, downsampling, moves to
+// the cold tier and removals of tombstoned traces, while they work on them. A claimed block is not selected by
+// another compaction or rewrite, so a block is never replaced by two new blocks.
+
+// claimBlocks claims all the blocks or none. It returns false if one of the blocks is already claimed.
+func (rw *readerWriter) claimBlocks(metas []*backend.BlockMeta) bool {
+	rw.claimedBlocksMtx.Lock()
+	defer rw.claimedBlocksMtx.Unlock()
+
+	for _, m := range metas {
+		if _, ok := rw.claimedBlocks[m.BlockID]; ok {
+			return false
+		}
+	}
+	for _, m := range metas {
+		rw.claimedBlocks[m.BlockID] = struct{}{}
+	}
+	return true
+}
+
+func (rw *readerWriter) releaseBlocks(metas []*backend.BlockMeta) {
+	rw.claimedBlocksMtx.Lock()
+	defer rw.claimedBlocksMtx.Unlock()
+
+	for _, m := range metas {
+		delete(rw.claimedBlocks, m.BlockID)
+	}
+}
+
+// claimBlockForRewrite claims the block if it is still live. A compaction that released the block since the
+// blocklist was polled may have replaced it already.
+func (rw *readerWriter) claimBlockForRewrite(ctx context.Context, tenantID string, meta *backend.BlockMeta) bool {
+	metas := []*backend.BlockMeta{meta}
+	if !rw.claimBlocks(metas) {
+		return false
+	}
+	if _, err := rw.r.BlockMeta(ctx, meta.BlockID, tenantID); err != nil {
+		rw.releaseBlocks(metas)
+		return false
+	}
+	return true
+}
+
+func (rw *readerWriter) withoutClaimedBlocks(metas []*backend.BlockMeta) []*backend.BlockMeta {
+	rw.claimedBlocksMtx.Lock()
+	defer rw.claimedBlocksMtx.Unlock()
+
+	if len(rw.claimedBlocks) == 0 {
+		return metas
+	}
+
+	filtered := make([]*backend.BlockMeta, 0, len(metas))
+	for _, m := range metas {
+		if _, ok := rw.claimedBlocks[m.BlockID]; !ok {
+			filtered = append(filtered, m)
+		}
+	}
+	return filtered
+}
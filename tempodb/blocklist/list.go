@@ -186,4 +186,10 @@ func (l *List) updateInternal(tenantID string, add []*backend.BlockMeta, remove
 	}
 
 	l.compactedMetas[tenantID] = newCompactedBlocklist
+
+	// tenants without blocks are dropped instead of kept with empty lists until the next poll
+	if len(newblocklist) == 0 && len(newCompactedBlocklist) == 0 {
+		delete(l.metas, tenantID)
+		delete(l.compactedMetas, tenantID)
+	}
 }
@@ -653,3 +653,26 @@ func TestUpdatesSaved(t *testing.T) {
 		assert.Equal(t, tc.expectedCompacted, actualCompacted)
 	}
 }
+
+func TestUpdateDropsEmptyTenants(t *testing.T) {
+	one := uuid.MustParse("00000000-0000-0000-0000-000000000001")
+	two := uuid.MustParse("00000000-0000-0000-0000-000000000002")
+
+	l := New()
+	l.ApplyPollResults(PerTenant{
+		testTenantID: []*backend.BlockMeta{{BlockID: one}},
+		"other":      []*backend.BlockMeta{{BlockID: two}},
+	}, PerTenantCompacted{})
+
+	// the tenant is kept while it has compacted blocks
+	l.Update(testTenantID, nil, []*backend.BlockMeta{{BlockID: one}}, []*backend.CompactedBlockMeta{{BlockMeta: backend.BlockMeta{BlockID: one}}}, nil)
+	tenants := l.Tenants()
+	sort.Strings(tenants)
+	assert.Equal(t, []string{"other", testTenantID}, tenants)
+
+	// and dropped once it has no blocks left
+	l.Update(testTenantID, nil, nil, nil, []*backend.CompactedBlockMeta{{BlockMeta: backend.BlockMeta{BlockID: one}}})
+	assert.Equal(t, []string{"other"}, l.Tenants())
+	assert.Empty(t, l.Metas(testTenantID))
+	assert.Empty(t, l.CompactedMetas(testTenantID))
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"math/rand"
 	"path"
+	"slices"
 	"sort"
 	"strconv"
 	"sync"
@@ -29,6 +30,8 @@ const (
 	blockStatusCompactedLabel = "compacted"
 )
 
+var errStaleTenantIndex = errors.New("stale tenant index")
+
 var (
 	metricBackendObjects = promauto.NewGaugeVec(prometheus.GaugeOpts{
 		Namespace: "tempodb",
@@ -79,6 +82,7 @@ type PollerConfig struct {
 	PollFallback               bool
 	TenantIndexBuilders        int
 	StaleTenantIndex           time.Duration
+	StaleTenantIndexTolerance  time.Duration
 	PollJitterMs               int
 	TolerateConsecutiveErrors  int
 	EmptyTenantDeletionAge     time.Duration
@@ -179,15 +183,29 @@ func (p *Poller) Do(previous *List) (PerTenant, PerTenantCompacted, error) {
 			metricBackendBytes.WithLabelValues(tenantID, blockStatusCompactedLabel).Set(float64(backendMetaMetrics.compactedBlockMetaTotalBytes))
 			continue
 		}
-		metricBlocklistLength.DeleteLabelValues(tenantID)
-		metricBackendObjects.DeleteLabelValues(tenantID)
-		metricBackendObjects.DeleteLabelValues(tenantID)
-		metricBackendBytes.DeleteLabelValues(tenantID)
+		deleteTenantMetrics(tenantID)
+	}
+
+	// tenants that are no longer in the backend are dropped from the blocklist
+	if previous != nil {
+		for _, tenantID := range previous.Tenants() {
+			if !slices.Contains(tenants, tenantID) {
+				deleteTenantMetrics(tenantID)
+			}
+		}
 	}
 
 	return blocklist, compactedBlocklist, nil
 }
 
+func deleteTenantMetrics(tenantID string) {
+	metricBlocklistLength.DeleteLabelValues(tenantID)
+	metricBackendObjects.DeletePartialMatch(prometheus.Labels{"tenant": tenantID})
+	metricBackendBytes.DeletePartialMatch(prometheus.Labels{"tenant": tenantID})
+	metricTenantIndexAgeSeconds.DeleteLabelValues(tenantID)
+	metricTenantIndexBuilder.DeleteLabelValues(tenantID)
+}
+
 func (p *Poller) pollTenantAndCreateIndex(
 	ctx context.Context,
 	tenantID string,
@@ -196,6 +214,9 @@ func (p *Poller) pollTenantAndCreateIndex(
 	span, derivedCtx := opentracing.StartSpanFromContext(ctx, "Poller.pollTenantAndCreateIndex", opentracing.Tag{Key: "tenant", Value: tenantID})
 	defer span.Finish()
 
+	// a stale tenant index within the tolerance is used if the blocks can't be pulled any other way
+	var tolerated *backend.TenantIndex
+
 	// are we a tenant index builder?
 	builder := p.tenantIndexBuilder(tenantID)
 	span.SetTag("tenant_index_builder", builder)
@@ -204,6 +225,9 @@ func (p *Poller) pollTenantAndCreateIndex(
 
 		i, err := p.reader.TenantIndex(derivedCtx, tenantID)
 		err = p.tenantIndexPollError(i, err)
+		if errors.Is(err, errStaleTenantIndex) && p.staleTenantIndexTolerated(i) {
+			tolerated = i
+		}
 		if err == nil {
 			// success! return the retrieved index
 			metricTenantIndexAgeSeconds.WithLabelValues(tenantID).Set(float64(time.Since(i.CreatedAt) / time.Second))
@@ -221,6 +245,9 @@ func (p *Poller) pollTenantAndCreateIndex(
 
 		// there was an error, return the error if we're not supposed to fallback to polling
 		if !p.cfg.PollFallback {
+			if tolerated != nil {
+				return p.useStaleTenantIndex(tenantID, tolerated, err)
+			}
 			return nil, nil, fmt.Errorf("failed to pull tenant index and no fallback configured: %w", err)
 		}
 
@@ -234,6 +261,9 @@ func (p *Poller) pollTenantAndCreateIndex(
 	metricTenantIndexBuilder.WithLabelValues(tenantID).Set(1)
 	blocklist, compactedBlocklist, err := p.pollTenantBlocks(derivedCtx, tenantID, previous)
 	if err != nil {
+		if tolerated != nil {
+			return p.useStaleTenantIndex(tenantID, tolerated, err)
+		}
 		return nil, nil, fmt.Errorf("failed to poll tenant blocks: %w", err)
 	}
 
@@ -461,12 +491,24 @@ func (p *Poller) tenantIndexPollError(idx *backend.TenantIndex, err error) error
 	}
 
 	if p.cfg.StaleTenantIndex != 0 && time.Since(idx.CreatedAt) > p.cfg.StaleTenantIndex {
-		return fmt.Errorf("tenant index created at %s is stale", idx.CreatedAt)
+		return fmt.Errorf("tenant index created at %s: %w", idx.CreatedAt, errStaleTenantIndex)
 	}
 
 	return nil
 }
 
+// staleTenantIndexTolerated returns true if the stale tenant index is younger than the stale tenant index age plus
+// the tolerance
+func (p *Poller) staleTenantIndexTolerated(idx *backend.TenantIndex) bool {
+	return p.cfg.StaleTenantIndexTolerance > 0 && time.Since(idx.CreatedAt) <= p.cfg.StaleTenantIndex+p.cfg.StaleTenantIndexTolerance
+}
+
+func (p *Poller) useStaleTenantIndex(tenantID string, idx *backend.TenantIndex, err error) ([]*backend.BlockMeta, []*backend.CompactedBlockMeta, error) {
+	level.Warn(p.logger).Log("msg", "using stale tenant index within tolerance", "tenant", tenantID, "createdAt", idx.CreatedAt, "err", err)
+	metricTenantIndexAgeSeconds.WithLabelValues(tenantID).Set(float64(time.Since(idx.CreatedAt) / time.Second))
+	return idx.Meta, idx.CompactedMeta, nil
+}
+
 // deleteTenant will delete all of a tenant's objects if there is not a tenant index present.
 func (p *Poller) deleteTenant(ctx context.Context, tenantID string) error {
 	// If we have not enabled empty tenant deletion, do nothing.
@@ -195,6 +195,9 @@ func TestTenantIndexFallback(t *testing.T) {
 		expectsError              bool
 		expectsTenantIndexWritten bool
 		staleTenantIndex          time.Duration
+		staleTenantIndexTolerance time.Duration
+		errorOnPoll               bool
+		expectsTenantIndexUsed    bool
 	}{
 		{
 			name:                      "builder writes index",
@@ -238,6 +241,36 @@ func TestTenantIndexFallback(t *testing.T) {
 			expectsTenantIndexWritten: true,
 			staleTenantIndex:          time.Second,
 		},
+		{
+			name:                      "reader uses stale index within tolerance if no fallback",
+			isTenantIndexBuilder:      false,
+			pollFallback:              false,
+			expectsError:              false,
+			expectsTenantIndexWritten: false,
+			staleTenantIndex:          time.Second,
+			staleTenantIndexTolerance: 10 * time.Minute,
+			expectsTenantIndexUsed:    true,
+		},
+		{
+			name:                      "reader does not use stale index beyond tolerance",
+			isTenantIndexBuilder:      false,
+			pollFallback:              false,
+			expectsError:              true,
+			expectsTenantIndexWritten: false,
+			staleTenantIndex:          time.Second,
+			staleTenantIndexTolerance: time.Minute,
+		},
+		{
+			name:                      "reader uses stale index within tolerance if fallback fails",
+			isTenantIndexBuilder:      false,
+			pollFallback:              true,
+			errorOnPoll:               true,
+			expectsError:              false,
+			expectsTenantIndexWritten: false,
+			staleTenantIndex:          time.Second,
+			staleTenantIndexTolerance: 10 * time.Minute,
+			expectsTenantIndexUsed:    true,
+		},
 	}
 
 	for _, tc := range tests {
@@ -245,7 +278,7 @@ func TestTenantIndexFallback(t *testing.T) {
 			c := &backend.MockCompactor{}
 			r := newMockReader(PerTenant{
 				"test": []*backend.BlockMeta{},
-			}, nil, false)
+			}, nil, tc.errorOnPoll)
 			w := &backend.MockWriter{}
 			b := newBlocklist(PerTenant{}, PerTenantCompacted{})
 
@@ -257,21 +290,26 @@ func TestTenantIndexFallback(t *testing.T) {
 					CreatedAt: time.Now().
 						Add(-5 * time.Minute),
 					// always make the tenant index 5 minutes old so the above tests can use that for fallback testing
+					Meta: []*backend.BlockMeta{{BlockID: uuid.MustParse("00000000-0000-0000-0000-000000000001")}},
 				}, nil
 			}
 
 			poller := NewPoller(&PollerConfig{
-				PollConcurrency:        testPollConcurrency,
-				PollFallback:           tc.pollFallback,
-				TenantIndexBuilders:    testBuilders,
-				StaleTenantIndex:       tc.staleTenantIndex,
-				EmptyTenantDeletionAge: testEmptyTenantIndexAge,
+				PollConcurrency:           testPollConcurrency,
+				PollFallback:              tc.pollFallback,
+				TenantIndexBuilders:       testBuilders,
+				StaleTenantIndex:          tc.staleTenantIndex,
+				StaleTenantIndexTolerance: tc.staleTenantIndexTolerance,
+				EmptyTenantDeletionAge:    testEmptyTenantIndexAge,
 			}, &mockJobSharder{
 				owns: tc.isTenantIndexBuilder,
 			}, r, c, w, log.NewNopLogger())
-			_, _, err := poller.Do(b)
+			list, _, err := poller.Do(b)
 
 			assert.Equal(t, tc.expectsError, err != nil)
+			if tc.expectsTenantIndexUsed {
+				assert.Len(t, list["test"], 1)
+			}
 			assert.Equal(t, tc.expectsTenantIndexWritten, w.IndexCompactedMeta != nil)
 			assert.Equal(t, tc.expectsTenantIndexWritten, w.IndexMeta != nil)
 		})
@@ -0,0 +1,147 @@
+package tempodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/backend/tiering"
+)
+
+var (
+	metricColdTierMovedBlocks = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "tempodb",
+		Name:      "cold_tier_moved_blocks_total",
+		Help:      "Total number of blocks moved to the cold tier.",
+	})
+	metricColdTierMovedBytes = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "tempodb",
+		Name:      "cold_tier_moved_bytes_total",
+		Help:      "Total number of bytes of the blocks moved to the cold tier.",
+	})
+	metricColdTierMoveErrors = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "tempodb",
+		Name:      "cold_tier_move_errors_total",
+		Help:      "Total number of times an error occurred while moving blocks to the cold tier.",
+	})
+)
+
+// ColdTierConfig stores the blocks moved by the compactors in another bucket or prefix of the configured backend. The
+// blocks are read from both tiers.
+type ColdTierConfig struct {
+	// Bucket replaces the bucket of s3, the bucket name of gcs, the container name of azure and swift and the path of
+	// the local backend. Empty keeps the configured bucket
+	Bucket string `yaml:"bucket"`
+	// Prefix replaces the prefix of the object store backends. Empty keeps the configured prefix
+	Prefix string `yaml:"prefix"`
+	// StorageClass replaces the storage class of the s3 backend
+	StorageClass string `yaml:"storage_class"`
+}
+
+func (cfg ColdTierConfig) Enabled() bool {
+	return cfg.Bucket != "" || cfg.Prefix != ""
+}
+
+func (cfg ColdTierConfig) validate(backendName string) error {
+	if !cfg.Enabled() {
+		if cfg.StorageClass != "" {
+			return errors.New("bucket or prefix must be set")
+		}
+		return nil
+	}
+	if backendName == backend.Local && cfg.Prefix != "" {
+		return errors.New("prefix can't be set for the local backend")
+	}
+	if backendName != backend.S3 && cfg.StorageClass != "" {
+		return errors.New("storage_class can only be set for the s3 backend")
+	}
+	return nil
+}
+
+// ColdTierPolicyConfig controls the moving of blocks to the cold tier by the compactors
+type ColdTierPolicyConfig struct {
+	// After is the block age after which blocks are moved to the cold tier. 0 disables moving blocks
+	After time.Duration `yaml:"after"`
+	// MaxBlocksPerCycle is the maximum number of blocks moved per tenant in a retention cycle. 0 is unlimited
+	MaxBlocksPerCycle int `yaml:"max_blocks_per_cycle"`
+}
+
+// newColdTier wraps the backend in a backend that also reads the blocks from the cold tier
+func newColdTier(cfg *Config, r backend.RawReader, w backend.RawWriter, c backend.Compactor) (backend.RawReader, backend.RawWriter, backend.Compactor, tiering.Mover, error) {
+	coldCfg := routeConfig(cfg, TenantRoute{Bucket: cfg.ColdTier.Bucket, Prefix: cfg.ColdTier.Prefix})
+	if cfg.ColdTier.StorageClass != "" {
+		s3Cfg := *coldCfg.S3
+		s3Cfg.StorageClass = cfg.ColdTier.StorageClass
+		coldCfg.S3 = &s3Cfg
+	}
+
+	coldR, coldW, coldC, err := newBackend(coldCfg)
+	if err != nil {
+		return nil, nil, nil, nil, fmt.Errorf("error creating backend of the cold tier: %w", err)
+	}
+
+	r, w, c, mover := tiering.New(tiering.Backend{Reader: r, Writer: w, Compactor: c}, tiering.Backend{Reader: coldR, Writer: coldW, Compactor: coldC})
+	return r, w, c, mover, nil
+}
+
+// moveTenantToColdTier moves the owned blocks of the tenant that are older than the configured age to the cold tier.
+// The blocks are copied to new blocks in the cold tier and marked compacted, so the queriers switch to the new blocks
+// before the retention removes the originals from the hot tier. The blocks of routed tenants are not moved.
+func (rw *readerWriter) moveTenantToColdTier(ctx context.Context, tenantID string) {
+	cfg := rw.compactorCfg.ColdTier
+	if cfg.After <= 0 || rw.coldTier == nil || rw.cfg.routed(tenantID) {
+		return
+	}
+
+	cutoff := time.Now().Add(-cfg.After)
+	moved := 0
+
+	for _, b := range rw.blocklist.Metas(tenantID) {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+
+		if cfg.MaxBlocksPerCycle > 0 && moved >= cfg.MaxBlocksPerCycle {
+			return
+		}
+
+		if b.ColdTier || !b.EndTime.Before(cutoff) || rw.isQuarantined(ctx, tenantID, b.BlockID) || !rw.compactorSharder.Owns(b.BlockID.String()) {
+			continue
+		}
+
+		if !rw.claimBlockForRewrite(ctx, tenantID, b) {
+			continue
+		}
+
+		start := time.Now()
+		newMeta, err := rw.coldTier.CopyToColdTier(ctx, b)
+		if err == nil {
+			err = markCompacted(rw, tenantID, []*backend.BlockMeta{b}, []*backend.BlockMeta{newMeta})
+		}
+		rw.releaseBlocks([]*backend.BlockMeta{b})
+		// the block was compacted since the last poll
+		if errors.Is(err, backend.ErrDoesNotExist) {
+			continue
+		}
+		if err != nil {
+			level.Error(rw.logger).Log("msg", "failed to move block to the cold tier", "blockID", b.BlockID, "tenantID", tenantID, "err", err)
+			metricColdTierMoveErrors.Inc()
+			continue
+		}
+
+		moved++
+		metricColdTierMovedBlocks.Inc()
+		metricColdTierMovedBytes.Add(float64(b.Size))
+
+		level.Info(rw.logger).Log("msg", "moved block to the cold tier", "blockID", b.BlockID, "newBlockID", newMeta.BlockID, "tenantID", tenantID,
+			"size", b.Size, "elapsed", time.Since(start))
+	}
+}
@@ -0,0 +1,171 @@
+package tempodb
+
+import (
+	"context"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/pkg/util/test"
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/encoding/common"
+)
+
+func TestColdTierConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		backend string
+		cfg     ColdTierConfig
+		err     string
+	}{
+		{name: "disabled", backend: backend.S3},
+		{name: "valid", backend: backend.S3, cfg: ColdTierConfig{Bucket: "cold", StorageClass: "GLACIER_IR"}},
+		{name: "storage class only", backend: backend.S3, cfg: ColdTierConfig{StorageClass: "GLACIER_IR"}, err: "bucket or prefix must be set"},
+		{name: "local prefix", backend: backend.Local, cfg: ColdTierConfig{Prefix: "cold"}, err: "prefix can't be set for the local backend"},
+		{name: "storage class of gcs", backend: backend.GCS, cfg: ColdTierConfig{Bucket: "cold", StorageClass: "ARCHIVE"}, err: "storage_class can only be set for the s3 backend"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.cfg.validate(tc.backend)
+			if tc.err == "" {
+				require.NoError(t, err)
+			} else {
+				require.EqualError(t, err, tc.err)
+			}
+		})
+	}
+}
+
+func TestColdTier(t *testing.T) {
+	coldPath := path.Join(t.TempDir(), "cold")
+	r, w, c, tempDir := testConfig(t, backend.EncNone, 0, func(cfg *Config) {
+		cfg.ColdTier = ColdTierConfig{Bucket: coldPath}
+	})
+	ctx := context.Background()
+
+	err := c.EnableCompaction(ctx, &CompactorConfig{
+		ChunkSizeBytes:          10,
+		MaxCompactionRange:      time.Hour,
+		BlockRetention:          24 * time.Hour,
+		CompactedBlockRetention: time.Hour,
+		ColdTier:                ColdTierPolicyConfig{After: time.Hour},
+	}, &mockSharder{}, &mockOverrides{})
+	require.NoError(t, err)
+
+	r.EnablePolling(ctx, &mockJobSharder{})
+	rw := r.(*readerWriter)
+
+	oldID := test.ValidTraceID(nil)
+	writeTestBlock(t, w, testTenantID, oldID, uint32(time.Now().Add(-2*time.Hour).Unix()))
+	newID := test.ValidTraceID(nil)
+	writeTestBlock(t, w, testTenantID, newID, uint32(time.Now().Unix()))
+
+	rw.pollBlocklist()
+	require.Len(t, rw.blocklist.Metas(testTenantID), 2)
+
+	rw.moveTenantToColdTier(ctx, testTenantID)
+
+	// the old block is copied to a new block in the cold tier and marked compacted
+	metas := rw.blocklist.Metas(testTenantID)
+	require.Len(t, metas, 2)
+	compacted := rw.blocklist.CompactedMetas(testTenantID)
+	require.Len(t, compacted, 1)
+	require.True(t, compacted[0].EndTime.Before(time.Now().Add(-time.Hour)))
+
+	var coldBlock *backend.BlockMeta
+	for _, m := range metas {
+		_, hotErr := os.Stat(path.Join(tempDir, "traces", testTenantID, m.BlockID.String()))
+		_, coldErr := os.Stat(path.Join(coldPath, testTenantID, m.BlockID.String()))
+		if m.ColdTier {
+			coldBlock = m
+			require.True(t, os.IsNotExist(hotErr))
+			require.NoError(t, coldErr)
+		} else {
+			require.NoError(t, hotErr)
+			require.True(t, os.IsNotExist(coldErr))
+		}
+	}
+	require.NotNil(t, coldBlock)
+	require.Equal(t, compacted[0].StartTime, coldBlock.StartTime)
+	require.Equal(t, compacted[0].TotalObjects, coldBlock.TotalObjects)
+
+	find := func(id common.ID) {
+		traces, failedBlocks, err := r.Find(ctx, testTenantID, id, BlockIDMin, BlockIDMax, 0, 0, common.DefaultSearchOptions())
+		require.NoError(t, err)
+		require.Empty(t, failedBlocks)
+		require.Len(t, traces, 1)
+	}
+
+	// the retention removes the original block from the hot tier and the new block is read from the cold tier
+	require.NoError(t, rw.c.ClearBlock(compacted[0].BlockID, testTenantID))
+	// the updates of the blocklist are kept for one poll
+	rw.pollBlocklist()
+	rw.pollBlocklist()
+	metas = rw.blocklist.Metas(testTenantID)
+	require.Len(t, metas, 2)
+	require.Len(t, withoutColdBlocks(metas), 1)
+	find(oldID)
+	find(newID)
+
+	// blocks in the cold tier are not moved again
+	rw.moveTenantToColdTier(ctx, testTenantID)
+	require.ElementsMatch(t, metas, rw.blocklist.Metas(testTenantID))
+
+	// blocks in the cold tier are compacted and cleared in the cold tier
+	require.NoError(t, rw.c.MarkBlockCompacted(coldBlock.BlockID, testTenantID))
+	_, err = os.Stat(path.Join(coldPath, testTenantID, coldBlock.BlockID.String(), backend.CompactedMetaName))
+	require.NoError(t, err)
+	require.NoError(t, rw.c.ClearBlock(coldBlock.BlockID, testTenantID))
+	_, err = os.Stat(path.Join(coldPath, testTenantID, coldBlock.BlockID.String()))
+	require.True(t, os.IsNotExist(err))
+}
+
+func TestColdTierRequiresStorage(t *testing.T) {
+	_, _, c, _ := testConfig(t, backend.EncNone, 0)
+
+	err := c.EnableCompaction(context.Background(), &CompactorConfig{
+		MaxCompactionRange: time.Hour,
+		ColdTier:           ColdTierPolicyConfig{After: time.Hour},
+	}, &mockSharder{}, &mockOverrides{})
+	require.EqualError(t, err, "moving blocks to the cold tier requires the cold tier of the storage")
+}
+
+func TestColdTierSkipsRoutedTenants(t *testing.T) {
+	coldPath := path.Join(t.TempDir(), "cold")
+	routedPath := path.Join(t.TempDir(), "routed")
+	r, w, c, _ := testConfig(t, backend.EncNone, 0, func(cfg *Config) {
+		cfg.ColdTier = ColdTierConfig{Bucket: coldPath}
+		cfg.TenantRoutes = []TenantRoute{{Tenant: "routed", Bucket: routedPath}}
+	})
+	ctx := context.Background()
+
+	err := c.EnableCompaction(ctx, &CompactorConfig{
+		ChunkSizeBytes:          10,
+		MaxCompactionRange:      time.Hour,
+		BlockRetention:          24 * time.Hour,
+		CompactedBlockRetention: time.Hour,
+		ColdTier:                ColdTierPolicyConfig{After: time.Hour},
+	}, &mockSharder{}, &mockOverrides{})
+	require.NoError(t, err)
+
+	r.EnablePolling(ctx, &mockJobSharder{})
+	rw := r.(*readerWriter)
+
+	writeTestBlock(t, w, "routed", test.ValidTraceID(nil), uint32(time.Now().Add(-2*time.Hour).Unix()))
+	rw.pollBlocklist()
+	metas := rw.blocklist.Metas("routed")
+	require.Len(t, metas, 1)
+
+	// the block stays in the bucket of the tenant
+	rw.moveTenantToColdTier(ctx, "routed")
+	require.Equal(t, metas, rw.blocklist.Metas("routed"))
+	require.Empty(t, rw.blocklist.CompactedMetas("routed"))
+	_, err = os.Stat(path.Join(routedPath, "routed", metas[0].BlockID.String()))
+	require.NoError(t, err)
+	_, err = os.Stat(path.Join(coldPath, "routed"))
+	require.True(t, os.IsNotExist(err))
+}
@@ -30,9 +30,14 @@ type timeWindowBlockSelector struct {
 	MinInputBlocks       int
 	MaxInputBlocks       int
 	MaxCompactionRange   time.Duration // Size of the time window - say 6 hours
+	MaxTimeRange         time.Duration // maximum time range of a compacted block. 0 is unlimited
 	MaxCompactionObjects int           // maximum size of compacted objects
 	MaxBlockBytes        uint64        // maximum block size, estimate
 
+	// limits of the compactions outside the active window. 0 uses the limit of the active window
+	OldWindowMaxInputBlocks int
+	OldWindowMaxTimeRange   time.Duration
+
 	entries []timeWindowBlockEntry
 }
 
@@ -41,17 +46,22 @@ type timeWindowBlockEntry struct {
 	group string // Blocks in the same group will be compacted together. Sort order also determines group priority.
 	order string // Individual block priority within the group.
 	hash  string // hash string used for sharding ownership, preserves backwards compatibility
+	old   bool   // outside the active window
 }
 
 var _ (CompactionBlockSelector) = (*timeWindowBlockSelector)(nil)
 
-func newTimeWindowBlockSelector(blocklist []*backend.BlockMeta, maxCompactionRange time.Duration, maxCompactionObjects int, maxBlockBytes uint64, minInputBlocks, maxInputBlocks int) CompactionBlockSelector {
+func newTimeWindowBlockSelector(blocklist []*backend.BlockMeta, maxCompactionRange, maxTimeRange time.Duration, maxCompactionObjects int, maxBlockBytes uint64, minInputBlocks, maxInputBlocks, oldWindowMaxInputBlocks int, oldWindowMaxTimeRange time.Duration) CompactionBlockSelector {
 	twbs := &timeWindowBlockSelector{
 		MinInputBlocks:       minInputBlocks,
 		MaxInputBlocks:       maxInputBlocks,
 		MaxCompactionRange:   maxCompactionRange,
+		MaxTimeRange:         maxTimeRange,
 		MaxCompactionObjects: maxCompactionObjects,
 		MaxBlockBytes:        maxBlockBytes,
+
+		OldWindowMaxInputBlocks: oldWindowMaxInputBlocks,
+		OldWindowMaxTimeRange:   oldWindowMaxTimeRange,
 	}
 
 	now := time.Now()
@@ -83,7 +93,8 @@ func newTimeWindowBlockSelector(blocklist []*backend.BlockMeta, maxCompactionRan
 			// Within group choose smallest blocks first.
 			// update after parquet: we want to make sure blocks of the same version end up together
 			// update afert vParquet3: we want to make sure blocks of the same dedicated columns end up together
-			entry.order = fmt.Sprintf("%016X-%v-%016X", entry.meta.TotalObjects, entry.meta.Version, entry.meta.DedicatedColumnsHash())
+			// blocks of the same retention class end up together as well
+			entry.order = fmt.Sprintf("%016X-%v-%016X-%016X", entry.meta.TotalObjects, entry.meta.Version, entry.meta.DedicatedColumnsHash(), int64(entry.meta.Retention))
 
 			entry.hash = fmt.Sprintf("%v-%v-%v-%v", b.TenantID, b.CompactionLevel, w, b.ReplicationFactor)
 		} else {
@@ -94,9 +105,11 @@ func newTimeWindowBlockSelector(blocklist []*backend.BlockMeta, maxCompactionRan
 			// Within group chose lowest compaction lvl and smallest blocks first.
 			// update after parquet: we want to make sure blocks of the same version end up together
 			// update afert vParquet3: we want to make sure blocks of the same dedicated columns end up together
-			entry.order = fmt.Sprintf("%v-%016X-%v-%016X", b.CompactionLevel, entry.meta.TotalObjects, entry.meta.Version, entry.meta.DedicatedColumnsHash())
+			// blocks of the same retention class end up together as well
+			entry.order = fmt.Sprintf("%v-%016X-%v-%016X-%016X", b.CompactionLevel, entry.meta.TotalObjects, entry.meta.Version, entry.meta.DedicatedColumnsHash(), int64(entry.meta.Retention))
 
 			entry.hash = fmt.Sprintf("%v-%v-%v", b.TenantID, w, b.ReplicationFactor)
+			entry.old = true
 		}
 
 		twbs.entries = append(twbs.entries, entry)
@@ -124,15 +137,18 @@ func (twbs *timeWindowBlockSelector) BlocksToCompact() ([]*backend.BlockMeta, st
 		// Gather contiguous blocks while staying within limits
 		i := 0
 		for ; i < len(twbs.entries); i++ {
+			maxInputBlocks, maxTimeRange := twbs.limits(twbs.entries[i])
 			for j := i + 1; j < len(twbs.entries); j++ {
 				stripe := twbs.entries[i : j+1]
 				if twbs.entries[i].group == twbs.entries[j].group &&
 					twbs.entries[i].meta.DataEncoding == twbs.entries[j].meta.DataEncoding &&
 					twbs.entries[i].meta.Version == twbs.entries[j].meta.Version && // update after parquet: only compact blocks of the same version
 					twbs.entries[i].meta.DedicatedColumnsHash() == twbs.entries[j].meta.DedicatedColumnsHash() && // update after vParquet3: only compact blocks of the same dedicated columns
-					len(stripe) <= twbs.MaxInputBlocks &&
+					twbs.entries[i].meta.Retention == twbs.entries[j].meta.Retention && // only compact blocks of the same retention class
+					len(stripe) <= maxInputBlocks &&
 					totalObjects(stripe) <= twbs.MaxCompactionObjects &&
-					totalSize(stripe) <= twbs.MaxBlockBytes {
+					totalSize(stripe) <= twbs.MaxBlockBytes &&
+					(maxTimeRange == 0 || timeRange(stripe) <= maxTimeRange) { // keep block time ranges tight so blocks can be pruned by time
 					chosen = stripe
 				} else {
 					break
@@ -161,6 +177,21 @@ func (twbs *timeWindowBlockSelector) BlocksToCompact() ([]*backend.BlockMeta, st
 	return nil, ""
 }
 
+// limits returns the maximum number of input blocks and the maximum time range of a compaction starting at the entry.
+// Windows outside the active window no longer receive new blocks and can be consolidated into larger blocks.
+func (twbs *timeWindowBlockSelector) limits(e timeWindowBlockEntry) (int, time.Duration) {
+	maxInputBlocks, maxTimeRange := twbs.MaxInputBlocks, twbs.MaxTimeRange
+	if e.old {
+		if twbs.OldWindowMaxInputBlocks > 0 {
+			maxInputBlocks = twbs.OldWindowMaxInputBlocks
+		}
+		if twbs.OldWindowMaxTimeRange > 0 {
+			maxTimeRange = twbs.OldWindowMaxTimeRange
+		}
+	}
+	return maxInputBlocks, maxTimeRange
+}
+
 func totalObjects(entries []timeWindowBlockEntry) int {
 	totalObjects := 0
 	for _, b := range entries {
@@ -177,6 +208,21 @@ func totalSize(entries []timeWindowBlockEntry) uint64 {
 	return sz
 }
 
+// timeRange returns the time range covered by the blocks. It is the time range of the compacted block
+func timeRange(entries []timeWindowBlockEntry) time.Duration {
+	start := entries[0].meta.StartTime
+	end := entries[0].meta.EndTime
+	for _, b := range entries[1:] {
+		if b.meta.StartTime.Before(start) {
+			start = b.meta.StartTime
+		}
+		if b.meta.EndTime.After(end) {
+			end = b.meta.EndTime
+		}
+	}
+	return end.Sub(start)
+}
+
 func (twbs *timeWindowBlockSelector) windowForBlock(meta *backend.BlockMeta) int64 {
 	return twbs.windowForTime(meta.EndTime)
 }
@@ -15,12 +15,29 @@ func TestTimeWindowBlockSelectorBlocksToCompact(t *testing.T) {
 	timeWindow := 12 * time.Hour
 	tenantID := ""
 
+	// blocks of an old window that cover more than an hour each
+	oldWindowEnd := now.Add(-2 * activeWindowDuration)
+	var oldWindowBlocks []*backend.BlockMeta
+	for i := 0; i < 5; i++ {
+		oldWindowBlocks = append(oldWindowBlocks, &backend.BlockMeta{
+			BlockID:      uuid.MustParse(fmt.Sprintf("00000000-0000-0000-0000-00000000000%d", i)),
+			TotalObjects: i,
+			StartTime:    oldWindowEnd.Add(-2 * time.Hour),
+			EndTime:      oldWindowEnd,
+		})
+	}
+
 	tests := []struct {
 		name           string
 		blocklist      []*backend.BlockMeta
-		minInputBlocks int    // optional, defaults to global const
-		maxInputBlocks int    // optional, defaults to global const
-		maxBlockBytes  uint64 // optional, defaults to ???
+		minInputBlocks int           // optional, defaults to global const
+		maxInputBlocks int           // optional, defaults to global const
+		maxBlockBytes  uint64        // optional, defaults to ???
+		maxTimeRange   time.Duration // optional, defaults to unlimited
+
+		oldWindowMaxInputBlocks int           // optional, defaults to maxInputBlocks
+		oldWindowMaxTimeRange   time.Duration // optional, defaults to maxTimeRange
+
 		expected       []*backend.BlockMeta
 		expectedHash   string
 		expectedSecond []*backend.BlockMeta
@@ -765,6 +782,53 @@ func TestTimeWindowBlockSelectorBlocksToCompact(t *testing.T) {
 			},
 			expectedHash2: fmt.Sprintf("%v-%v-%v-%v", tenantID, 0, now.Unix(), 0),
 		},
+		{
+			name: "blocks with different retention are not selected together",
+			blocklist: []*backend.BlockMeta{
+				{
+					BlockID:   uuid.MustParse("00000000-0000-0000-0000-000000000001"),
+					EndTime:   now,
+					Retention: 30 * 24 * time.Hour,
+				},
+				{
+					BlockID: uuid.MustParse("00000000-0000-0000-0000-000000000002"),
+					EndTime: now,
+				},
+				{
+					BlockID:   uuid.MustParse("00000000-0000-0000-0000-000000000003"),
+					EndTime:   now,
+					Retention: 30 * 24 * time.Hour,
+				},
+				{
+					BlockID: uuid.MustParse("00000000-0000-0000-0000-000000000004"),
+					EndTime: now,
+				},
+			},
+			expected: []*backend.BlockMeta{
+				{
+					BlockID: uuid.MustParse("00000000-0000-0000-0000-000000000002"),
+					EndTime: now,
+				},
+				{
+					BlockID: uuid.MustParse("00000000-0000-0000-0000-000000000004"),
+					EndTime: now,
+				},
+			},
+			expectedHash: fmt.Sprintf("%v-%v-%v-%v", tenantID, 0, now.Unix(), 0),
+			expectedSecond: []*backend.BlockMeta{
+				{
+					BlockID:   uuid.MustParse("00000000-0000-0000-0000-000000000001"),
+					EndTime:   now,
+					Retention: 30 * 24 * time.Hour,
+				},
+				{
+					BlockID:   uuid.MustParse("00000000-0000-0000-0000-000000000003"),
+					EndTime:   now,
+					Retention: 30 * 24 * time.Hour,
+				},
+			},
+			expectedHash2: fmt.Sprintf("%v-%v-%v-%v", tenantID, 0, now.Unix(), 0),
+		},
 		{
 			name: "blocks are grouped by replication factor",
 			blocklist: []*backend.BlockMeta{
@@ -816,6 +880,85 @@ func TestTimeWindowBlockSelectorBlocksToCompact(t *testing.T) {
 			},
 			expectedHash2: fmt.Sprintf("%v-%v-%v-%v", tenantID, 0, now.Unix(), 3),
 		},
+		{
+			name:         "max time range",
+			maxTimeRange: time.Hour,
+			blocklist: []*backend.BlockMeta{
+				{
+					BlockID:   uuid.MustParse("00000000-0000-0000-0000-000000000000"),
+					StartTime: now.Add(-30 * time.Minute),
+					EndTime:   now,
+				},
+				{
+					BlockID:      uuid.MustParse("00000000-0000-0000-0000-000000000001"),
+					TotalObjects: 1,
+					StartTime:    now.Add(-2 * time.Hour),
+					EndTime:      now,
+				},
+				{
+					BlockID:      uuid.MustParse("00000000-0000-0000-0000-000000000002"),
+					TotalObjects: 2,
+					StartTime:    now.Add(-10 * time.Minute),
+					EndTime:      now,
+				},
+				{
+					BlockID:      uuid.MustParse("00000000-0000-0000-0000-000000000003"),
+					TotalObjects: 3,
+					StartTime:    now.Add(-20 * time.Minute),
+					EndTime:      now,
+				},
+			},
+			// the block that covers 2 hours is never compacted with the others
+			expected: []*backend.BlockMeta{
+				{
+					BlockID:      uuid.MustParse("00000000-0000-0000-0000-000000000002"),
+					TotalObjects: 2,
+					StartTime:    now.Add(-10 * time.Minute),
+					EndTime:      now,
+				},
+				{
+					BlockID:      uuid.MustParse("00000000-0000-0000-0000-000000000003"),
+					TotalObjects: 3,
+					StartTime:    now.Add(-20 * time.Minute),
+					EndTime:      now,
+				},
+			},
+			expectedHash: fmt.Sprintf("%v-%v-%v-%v", tenantID, 0, now.Unix(), 0),
+		},
+		{
+			name:         "old window uses the active window limits",
+			maxTimeRange: time.Hour,
+			blocklist:    oldWindowBlocks,
+			expected:     nil,
+		},
+		{
+			name:                    "old window limits",
+			maxTimeRange:            time.Hour,
+			oldWindowMaxInputBlocks: 5,
+			oldWindowMaxTimeRange:   3 * time.Hour,
+			blocklist:               oldWindowBlocks,
+			expected:                oldWindowBlocks,
+			expectedHash:            fmt.Sprintf("%v-%v-%v", tenantID, oldWindowEnd.Unix(), 0),
+		},
+		{
+			name:                  "old window limits don't apply to the active window",
+			maxTimeRange:          time.Hour,
+			oldWindowMaxTimeRange: 3 * time.Hour,
+			blocklist: []*backend.BlockMeta{
+				{
+					BlockID:   uuid.MustParse("00000000-0000-0000-0000-000000000000"),
+					StartTime: now.Add(-2 * time.Hour),
+					EndTime:   now,
+				},
+				{
+					BlockID:      uuid.MustParse("00000000-0000-0000-0000-000000000001"),
+					TotalObjects: 1,
+					StartTime:    now.Add(-2 * time.Hour),
+					EndTime:      now,
+				},
+			},
+			expected: nil,
+		},
 	}
 
 	for _, tt := range tests {
@@ -835,7 +978,7 @@ func TestTimeWindowBlockSelectorBlocksToCompact(t *testing.T) {
 				maxSize = tt.maxBlockBytes
 			}
 
-			selector := newTimeWindowBlockSelector(tt.blocklist, time.Second, 100, maxSize, min, max)
+			selector := newTimeWindowBlockSelector(tt.blocklist, time.Second, tt.maxTimeRange, 100, maxSize, min, max, tt.oldWindowMaxInputBlocks, tt.oldWindowMaxTimeRange)
 
 			actual, hash := selector.BlocksToCompact()
 			assert.Equal(t, tt.expected, actual)
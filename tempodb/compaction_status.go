@@ -0,0 +1,109 @@
+package tempodb
+
+import (
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/grafana/tempo/tempodb/backend"
+)
+
+// CompactionJob is a compaction queued or running on this compactor. Jobs are queued while all compaction slots are
+// in use.
+type CompactionJob struct {
+	TenantID  string     `json:"tenantID"`
+	Level     uint8      `json:"level"`
+	Blocks    int        `json:"blocks"`
+	Bytes     uint64     `json:"bytes"`
+	QueuedAt  time.Time  `json:"queuedAt"`
+	StartedAt *time.Time `json:"startedAt,omitempty"`
+}
+
+// TenantCompactionStatus is the backlog of a tenant measured at the end of its last compaction cycle on this compactor
+type TenantCompactionStatus struct {
+	TenantID                 string        `json:"tenantID"`
+	OutstandingBlocks        int           `json:"outstandingBlocks"`
+	OutstandingBlocksByLevel map[uint8]int `json:"outstandingBlocksByLevel"`
+	MeasuredAt               time.Time     `json:"measuredAt"`
+}
+
+// CompactionStatus is the job queue and the backlog of the tenants of this compactor
+type CompactionStatus struct {
+	Jobs    []*CompactionJob          `json:"jobs"`
+	Tenants []*TenantCompactionStatus `json:"tenants"`
+}
+
+// CompactionStatus returns the jobs in the order they were queued and the tenants sorted by ID
+func (rw *readerWriter) CompactionStatus() *CompactionStatus {
+	rw.compactionStatusMtx.Lock()
+	defer rw.compactionStatusMtx.Unlock()
+
+	status := &CompactionStatus{
+		Jobs:    make([]*CompactionJob, 0, len(rw.compactionJobs)),
+		Tenants: make([]*TenantCompactionStatus, 0, len(rw.compactionTenants)),
+	}
+	for job := range rw.compactionJobs {
+		j := *job
+		status.Jobs = append(status.Jobs, &j)
+	}
+	for _, tenant := range rw.compactionTenants {
+		t := *tenant
+		status.Tenants = append(status.Tenants, &t)
+	}
+
+	sort.Slice(status.Jobs, func(i, j int) bool { return status.Jobs[i].QueuedAt.Before(status.Jobs[j].QueuedAt) })
+	sort.Slice(status.Tenants, func(i, j int) bool { return status.Tenants[i].TenantID < status.Tenants[j].TenantID })
+	return status
+}
+
+func (rw *readerWriter) queueCompactionJob(tenantID string, blockMetas []*backend.BlockMeta) *CompactionJob {
+	job := &CompactionJob{
+		TenantID: tenantID,
+		Level:    compactionLevelForBlocks(blockMetas),
+		Blocks:   len(blockMetas),
+		QueuedAt: time.Now(),
+	}
+	for _, m := range blockMetas {
+		job.Bytes += m.Size
+	}
+
+	rw.compactionStatusMtx.Lock()
+	defer rw.compactionStatusMtx.Unlock()
+
+	rw.compactionJobs[job] = struct{}{}
+	return job
+}
+
+func (rw *readerWriter) startCompactionJob(job *CompactionJob) {
+	rw.compactionStatusMtx.Lock()
+	defer rw.compactionStatusMtx.Unlock()
+
+	now := time.Now()
+	job.StartedAt = &now
+}
+
+func (rw *readerWriter) finishCompactionJob(job *CompactionJob) {
+	rw.compactionStatusMtx.Lock()
+	defer rw.compactionStatusMtx.Unlock()
+
+	delete(rw.compactionJobs, job)
+}
+
+func (rw *readerWriter) setTenantCompactionStatus(status *TenantCompactionStatus) {
+	rw.compactionStatusMtx.Lock()
+	defer rw.compactionStatusMtx.Unlock()
+
+	rw.compactionTenants[status.TenantID] = status
+}
+
+// pruneTenantCompactionStatus removes the status of the tenants that are no longer in the blocklist
+func (rw *readerWriter) pruneTenantCompactionStatus(tenants []string) {
+	rw.compactionStatusMtx.Lock()
+	defer rw.compactionStatusMtx.Unlock()
+
+	for tenantID := range rw.compactionTenants {
+		if _, found := slices.BinarySearch(tenants, tenantID); !found {
+			delete(rw.compactionTenants, tenantID)
+		}
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"sort"
 	"strconv"
+	"sync"
 	"time"
 
 	"github.com/go-kit/log/level"
@@ -13,6 +14,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
 
+	"github.com/grafana/tempo/pkg/boundedwaitgroup"
 	"github.com/grafana/tempo/pkg/dataquality"
 	"github.com/grafana/tempo/pkg/util/tracing"
 	"github.com/grafana/tempo/tempodb/backend"
@@ -24,7 +26,20 @@ const (
 	inputBlocks  = 2
 	outputBlocks = 1
 
-	DefaultCompactionCycle = 30 * time.Second
+	DefaultCompactionCycle       = 30 * time.Second
+	DefaultCompactionConcurrency = 1
+
+	// strategies to pick the tenants compacted in a cycle
+	TenantSchedulingRoundRobin   = "round_robin"
+	TenantSchedulingLargestFirst = "largest_first"
+
+	// reasons of compaction errors
+	reasonCompactionReadMeta       = "read_meta"
+	reasonCompactionEncoding       = "encoding"
+	reasonCompactionCompact        = "compact"
+	reasonCompactionMarkCompacted  = "mark_compacted"
+	reasonCompactionReadTombstones = "read_tombstones"
+	reasonCompactionUnknown        = "unknown"
 
 	DefaultChunkSizeBytes            = 5 * 1024 * 1024  // 5 MiB
 	DefaultFlushSizeBytes     uint32 = 20 * 1024 * 1024 // 20 MiB
@@ -47,21 +62,47 @@ var (
 		Name:      "compaction_bytes_written_total",
 		Help:      "Total number of bytes written to backend during compaction.",
 	}, []string{"level"})
-	metricCompactionErrors = promauto.NewCounter(prometheus.CounterOpts{
+	metricCompactionBytesCompacted = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "tempodb",
+		Name:      "compaction_bytes_compacted_total",
+		Help:      "Total size of the blocks compacted.",
+	}, []string{"level"})
+	metricCompactionDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "tempodb",
+		Name:      "compaction_duration_seconds",
+		Help:      "Time taken by successful compactions.",
+		Buckets:   prometheus.ExponentialBuckets(1, 2, 12),
+	}, []string{"level"})
+	metricCompactionErrors = promauto.NewCounterVec(prometheus.CounterOpts{
 		Namespace: "tempodb",
 		Name:      "compaction_errors_total",
-		Help:      "Total number of errors occurring during compaction.",
-	})
+		Help:      "Total number of errors occurring during compaction by reason.",
+	}, []string{"reason"})
 	metricCompactionObjectsCombined = promauto.NewCounterVec(prometheus.CounterOpts{
 		Namespace: "tempodb",
 		Name:      "compaction_objects_combined_total",
 		Help:      "Total number of objects combined during compaction.",
 	}, []string{"level"})
+	metricCompactionDuplicateBlocks = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "tempodb",
+		Name:      "compaction_duplicate_blocks_discarded_total",
+		Help:      "Total number of blocks discarded because another block contains the same traces.",
+	})
 	metricCompactionOutstandingBlocks = promauto.NewGaugeVec(prometheus.GaugeOpts{
 		Namespace: "tempodb",
 		Name:      "compaction_outstanding_blocks",
 		Help:      "Number of blocks remaining to be compacted before next maintenance cycle",
 	}, []string{"tenant"})
+	metricCompactionOutstandingBlocksByLevel = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "tempodb",
+		Name:      "compaction_outstanding_blocks_by_level",
+		Help:      "Number of blocks remaining to be compacted before next maintenance cycle by compaction level.",
+	}, []string{"tenant", "level"})
+	metricCompactionsRunning = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "tempodb",
+		Name:      "compactions_running",
+		Help:      "Number of compactions running per tenant.",
+	}, []string{"tenant"})
 )
 
 func (rw *readerWriter) compactionLoop(ctx context.Context) {
@@ -88,7 +129,9 @@ func (rw *readerWriter) compactionLoop(ctx context.Context) {
 	}
 }
 
-// doCompaction runs a compaction cycle every 30s
+// doCompaction runs a compaction cycle every 30s. Up to CompactionConcurrency compactions run at the same time across
+// as many tenants. The tenants take turns to start their compactions so a tenant with a large backlog doesn't hold up
+// the others.
 func (rw *readerWriter) doCompaction(ctx context.Context) {
 	// List of all tenants in the block list
 	// The block list is updated by constant polling the storage for tenant indexes and/or tenant blocks (and building the index)
@@ -97,38 +140,189 @@ func (rw *readerWriter) doCompaction(ctx context.Context) {
 		return
 	}
 
+	concurrency := rw.compactorCfg.CompactionConcurrency
+	if concurrency <= 0 {
+		concurrency = DefaultCompactionConcurrency
+	}
+
 	// Iterate through tenants each cycle
 	// Sort tenants for stability (since original map does not guarantee order)
 	sort.Slice(tenants, func(i, j int) bool { return tenants[i] < tenants[j] })
-	rw.compactorTenantOffset = (rw.compactorTenantOffset + 1) % uint(len(tenants))
+	rw.pruneTenantCompactionStatus(tenants)
 
-	// Select the next tenant to run compaction for
-	tenantID := tenants[rw.compactorTenantOffset]
-	// Get the meta file of all non-compacted blocks for the given tenant
-	blocklist := rw.blocklist.Metas(tenantID)
+	tenants, waiting := rw.tenantsToCompact(tenants)
+	if len(tenants) == 0 {
+		return
+	}
 
-	window := rw.compactorOverrides.MaxCompactionRangeForTenant(tenantID)
-	if window == 0 {
-		window = rw.compactorCfg.MaxCompactionRange
+	// Select the next tenants to run compaction for
+	selected := rw.selectTenants(tenants, concurrency)
+	rw.updateTenantWaits(tenants, waiting, selected)
+
+	// the goroutines waiting for a free slot are served in order so the tenants alternate
+	var (
+		wg   sync.WaitGroup
+		jobs = boundedwaitgroup.New(uint(concurrency))
+	)
+	for _, tenantID := range selected {
+		wg.Add(1)
+		go func(tenantID string) {
+			defer wg.Done()
+			rw.compactTenant(ctx, tenantID, &jobs)
+		}(tenantID)
 	}
+	wg.Wait()
+	jobs.Wait()
+}
 
-	// Select which blocks to compact.
-	//
-	// Blocks are firstly divided by the active compaction window (default: most recent 24h)
-	//  1. If blocks are inside the active window, they're grouped by compaction level (how many times they've been compacted).
-	//   Favoring lower compaction levels, and compacting blocks only from the same tenant.
-	//  2. If blocks are outside the active window, they're grouped only by windows, ignoring compaction level.
-	//   It picks more recent windows first, and compacting blocks only from the same tenant.
-	blockSelector := newTimeWindowBlockSelector(blocklist,
-		window,
-		rw.compactorCfg.MaxCompactionObjects,
-		rw.compactorCfg.MaxBlockBytes,
-		defaultMinInputBlocks,
-		defaultMaxInputBlocks)
+// tenantsToCompact returns the tenants that take turns this cycle and the tenants that wait for them. Tenants with
+// compaction disabled are skipped. If the tenants have different priorities only the ones with the highest priority
+// that have blocks to compact take turns, and the tenants with blocks to compact and a lower priority wait. A tenant
+// gains one priority for every cycle it waits so tenants with a low priority aren't starved.
+func (rw *readerWriter) tenantsToCompact(tenants []string) ([]string, []string) {
+	byPriority := map[int][]string{}
+	for _, tenantID := range tenants {
+		if rw.compactorOverrides.CompactionDisabledForTenant(tenantID) {
+			continue
+		}
+		priority := rw.compactorOverrides.CompactionPriorityForTenant(tenantID) + rw.compactorTenantWaits[tenantID]
+		byPriority[priority] = append(byPriority[priority], tenantID)
+	}
+
+	if len(byPriority) <= 1 {
+		for _, tenants := range byPriority {
+			return tenants, nil
+		}
+		return nil, nil
+	}
+
+	priorities := make([]int, 0, len(byPriority))
+	for priority := range byPriority {
+		priorities = append(priorities, priority)
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(priorities)))
+
+	var candidates, waiting []string
+	for _, priority := range priorities {
+		var withBlocks []string
+		for _, tenantID := range byPriority[priority] {
+			if rw.hasBlocksToCompact(tenantID) {
+				withBlocks = append(withBlocks, tenantID)
+			}
+		}
+		if len(candidates) == 0 {
+			candidates = withBlocks
+		} else {
+			waiting = append(waiting, withBlocks...)
+		}
+	}
+	return candidates, waiting
+}
+
+// updateTenantWaits counts the cycles the tenants waited for their turn. The tenants selected this cycle start over.
+func (rw *readerWriter) updateTenantWaits(candidates, waiting, selected []string) {
+	waits := make(map[string]int, len(candidates)+len(waiting))
+	for _, tenantID := range candidates {
+		if n, ok := rw.compactorTenantWaits[tenantID]; ok {
+			waits[tenantID] = n
+		}
+	}
+	for _, tenantID := range waiting {
+		waits[tenantID] = rw.compactorTenantWaits[tenantID] + 1
+	}
+	for _, tenantID := range selected {
+		delete(waits, tenantID)
+	}
+	rw.compactorTenantWaits = waits
+}
+
+// selectTenants returns up to concurrency tenants to compact this cycle. Round robin moves on to the next tenants
+// every cycle. Largest first picks the tenants with the most blocks to compact, then the most bytes to compact, so the
+// blocklists of large tenants don't grow while the compactor takes turns with small ones. Tenants without blocks to
+// compact are skipped.
+func (rw *readerWriter) selectTenants(tenants []string, concurrency int) []string {
+	if rw.compactorCfg.TenantSchedulingStrategy == TenantSchedulingLargestFirst {
+		type tenantSize struct {
+			tenantID string
+			blocks   int
+			bytes    uint64
+		}
+		sizes := make([]tenantSize, 0, len(tenants))
+		for _, tenantID := range tenants {
+			blocks, bytes := rw.blocksToCompact(tenantID)
+			if blocks == 0 {
+				continue
+			}
+			sizes = append(sizes, tenantSize{tenantID: tenantID, blocks: blocks, bytes: bytes})
+		}
+		// the tenants are sorted by ID so ties are broken by ID
+		sort.SliceStable(sizes, func(i, j int) bool {
+			if sizes[i].blocks != sizes[j].blocks {
+				return sizes[i].blocks > sizes[j].blocks
+			}
+			return sizes[i].bytes > sizes[j].bytes
+		})
+
+		selected := make([]string, 0, min(concurrency, len(sizes)))
+		for i := 0; i < cap(selected); i++ {
+			selected = append(selected, sizes[i].tenantID)
+		}
+		return selected
+	}
+
+	selected := make([]string, 0, min(concurrency, len(tenants)))
+	for i := 0; i < cap(selected); i++ {
+		rw.compactorTenantOffset = (rw.compactorTenantOffset + 1) % uint(len(tenants))
+		selected = append(selected, tenants[rw.compactorTenantOffset])
+	}
+	return selected
+}
+
+// blocksToCompact returns the number and the size of the blocks the block selector picks for compaction that this
+// compactor owns
+func (rw *readerWriter) blocksToCompact(tenantID string) (int, uint64) {
+	var (
+		blocks int
+		bytes  uint64
+	)
+	blockSelector := rw.blockSelector(tenantID, false)
+	for {
+		toBeCompacted, hashString := blockSelector.BlocksToCompact()
+		if len(toBeCompacted) == 0 {
+			return blocks, bytes
+		}
+		if !rw.compactorSharder.Owns(hashString) {
+			continue
+		}
+		for _, m := range toBeCompacted {
+			blocks++
+			bytes += m.Size
+		}
+	}
+}
+
+// hasBlocksToCompact returns true if the tenant has blocks to compact that this compactor owns
+func (rw *readerWriter) hasBlocksToCompact(tenantID string) bool {
+	blockSelector := rw.blockSelector(tenantID, false)
+	for {
+		toBeCompacted, hashString := blockSelector.BlocksToCompact()
+		if len(toBeCompacted) == 0 {
+			return false
+		}
+		if rw.compactorSharder.Owns(hashString) {
+			return true
+		}
+	}
+}
+
+// compactTenant starts the compactions of the tenant in jobs until there are no more blocks to compact or the tenant
+// used up its maintenance cycle
+func (rw *readerWriter) compactTenant(ctx context.Context, tenantID string, jobs *boundedwaitgroup.BoundedWaitGroup) {
+	blockSelector := rw.blockSelector(tenantID, true)
 
 	start := time.Now()
 
-	level.Debug(rw.logger).Log("msg", "starting compaction cycle", "tenantID", tenantID, "offset", rw.compactorTenantOffset)
+	level.Debug(rw.logger).Log("msg", "starting compaction cycle", "tenantID", tenantID)
 	for {
 		select {
 		case <-ctx.Done():
@@ -137,7 +331,7 @@ func (rw *readerWriter) doCompaction(ctx context.Context) {
 			// Pick up to defaultMaxInputBlocks (4) blocks to compact into a single one
 			toBeCompacted, hashString := blockSelector.BlocksToCompact()
 			if len(toBeCompacted) == 0 {
-				measureOutstandingBlocks(tenantID, blockSelector, rw.compactorSharder.Owns)
+				rw.measureOutstandingBlocks(tenantID, blockSelector)
 
 				level.Debug(rw.logger).Log("msg", "compaction cycle complete. No more blocks to compact", "tenantID", tenantID)
 				return
@@ -146,20 +340,35 @@ func (rw *readerWriter) doCompaction(ctx context.Context) {
 				// continue on this tenant until we find something we own
 				continue
 			}
-			level.Info(rw.logger).Log("msg", "Compacting hash", "hashString", hashString)
-			// Compact selected blocks into a larger one
-			err := rw.compact(ctx, toBeCompacted, tenantID)
-
-			if errors.Is(err, backend.ErrDoesNotExist) {
-				level.Warn(rw.logger).Log("msg", "unable to find meta during compaction.  trying again on this block list", "err", err)
-			} else if err != nil {
-				level.Error(rw.logger).Log("msg", "error during compaction cycle", "err", err)
-				metricCompactionErrors.Inc()
+			// blocks claimed by a rewrite since the blocks were selected are left for the next cycle
+			if !rw.claimBlocks(toBeCompacted) {
+				continue
 			}
+			level.Info(rw.logger).Log("msg", "Compacting hash", "hashString", hashString)
+			// Compact selected blocks into a larger one. The selected blocks don't overlap with the blocks of the
+			// compactions still running
+			job := rw.queueCompactionJob(tenantID, toBeCompacted)
+			jobs.Add(1)
+			rw.startCompactionJob(job)
+			metricCompactionsRunning.WithLabelValues(tenantID).Inc()
+			go func(toBeCompacted []*backend.BlockMeta) {
+				defer jobs.Done()
+				defer rw.releaseBlocks(toBeCompacted)
+				defer rw.finishCompactionJob(job)
+				defer metricCompactionsRunning.WithLabelValues(tenantID).Dec()
+
+				err := rw.compact(ctx, toBeCompacted, tenantID)
+				if errors.Is(err, backend.ErrDoesNotExist) {
+					level.Warn(rw.logger).Log("msg", "unable to find meta during compaction.  trying again on this block list", "err", err)
+				} else if err != nil {
+					level.Error(rw.logger).Log("msg", "error during compaction cycle", "err", err)
+					metricCompactionErrors.WithLabelValues(compactionErrorReason(err)).Inc()
+				}
+			}(toBeCompacted)
 
 			// after a maintenance cycle bail out
 			if start.Add(rw.compactorCfg.MaxTimePerTenant).Before(time.Now()) {
-				measureOutstandingBlocks(tenantID, blockSelector, rw.compactorSharder.Owns)
+				rw.measureOutstandingBlocks(tenantID, blockSelector)
 
 				level.Info(rw.logger).Log("msg", "compacted blocks for a maintenance cycle, bailing out", "tenantID", tenantID)
 				return
@@ -168,6 +377,47 @@ func (rw *readerWriter) doCompaction(ctx context.Context) {
 	}
 }
 
+// blockSelector returns the selector of the blocks of the tenant to compact. Duplicate blocks are left out of the
+// selection and are only marked compacted if discardDuplicates is set, so the tenants can be probed for blocks to
+// compact without changing their blocks.
+func (rw *readerWriter) blockSelector(tenantID string, discardDuplicates bool) CompactionBlockSelector {
+	// Get the meta file of all non-compacted blocks for the given tenant. Downsampled blocks are not compacted
+	// again so the reduced copies of their traces are never combined with complete traces. Blocks in the cold tier
+	// are not compacted so they aren't written back to the hot tier. Blocks claimed by a rewrite are left out.
+	blocklist := rw.withoutClaimedBlocks(rw.withoutQuarantinedBlocks(context.Background(), tenantID, withoutColdBlocks(withoutDownsampledBlocks(rw.blocklist.Metas(tenantID)))))
+	if rw.compactorCfg.DiscardDuplicateBlocks {
+		if discardDuplicates {
+			blocklist = rw.discardDuplicateBlocks(tenantID, blocklist)
+		} else {
+			blocklist, _ = rw.duplicateBlocks(tenantID, blocklist)
+		}
+	}
+
+	window := rw.compactorOverrides.MaxCompactionRangeForTenant(tenantID)
+	if window == 0 {
+		window = rw.compactorCfg.MaxCompactionRange
+	}
+
+	// Select which blocks to compact.
+	//
+	// Blocks are firstly divided by the active compaction window (default: most recent 24h)
+	//  1. If blocks are inside the active window, they're grouped by compaction level (how many times they've been compacted).
+	//   Favoring lower compaction levels, and compacting blocks only from the same tenant.
+	//  2. If blocks are outside the active window, they're grouped only by windows, ignoring compaction level.
+	//   It picks more recent windows first, and compacting blocks only from the same tenant.
+	// Blocks are only compacted together if the compacted block doesn't cover more than the max time range.
+	// Windows outside the active window can use larger limits to consolidate them into fewer blocks.
+	return newTimeWindowBlockSelector(blocklist,
+		window,
+		rw.compactorCfg.MaxTimeRange,
+		rw.compactorCfg.MaxCompactionObjects,
+		rw.compactorCfg.MaxBlockBytes,
+		defaultMinInputBlocks,
+		defaultMaxInputBlocks,
+		rw.compactorCfg.OldWindowMaxInputBlocks,
+		rw.compactorCfg.OldWindowMaxTimeRange)
+}
+
 func (rw *readerWriter) compact(ctx context.Context, blockMetas []*backend.BlockMeta, tenantID string) error {
 	level.Debug(rw.logger).Log("msg", "beginning compaction", "num blocks compacting", len(blockMetas))
 
@@ -195,13 +445,13 @@ func (rw *readerWriter) compact(ctx context.Context, blockMetas []*backend.Block
 		// Make sure block still exists
 		_, err = rw.r.BlockMeta(ctx, blockMeta.BlockID, tenantID)
 		if err != nil {
-			return err
+			return &compactionError{reason: reasonCompactionReadMeta, err: err}
 		}
 	}
 
 	enc, err := encoding.FromVersion(blockMetas[0].Version)
 	if err != nil {
-		return err
+		return &compactionError{reason: reasonCompactionEncoding, err: err}
 	}
 
 	compactionLevel := compactionLevelForBlocks(blockMetas)
@@ -214,7 +464,7 @@ func (rw *readerWriter) compact(ctx context.Context, blockMetas []*backend.Block
 	}
 
 	opts := common.CompactionOptions{
-		BlockConfig:        *rw.cfg.Block,
+		BlockConfig:        rw.compactorBlockConfig(),
 		ChunkSizeBytes:     rw.compactorCfg.ChunkSizeBytes,
 		FlushSizeBytes:     rw.compactorCfg.FlushSizeBytes,
 		IteratorBufferSize: rw.compactorCfg.IteratorBufferSize,
@@ -238,20 +488,35 @@ func (rw *readerWriter) compact(ctx context.Context, blockMetas []*backend.Block
 		},
 	}
 
+	// tombstoned traces are dropped so compactions don't carry them into new blocks
+	opts.DropObject, err = rw.tombstonedTracesFilter(ctx, tenantID)
+	if err != nil {
+		return &compactionError{reason: reasonCompactionReadTombstones, err: err}
+	}
+
 	compactor := enc.NewCompactor(opts)
 
 	// Compact selected blocks into a larger one
 	newCompactedBlocks, err := compactor.Compact(ctx, rw.logger, rw.r, rw.w, blockMetas)
 	if err != nil {
-		return err
+		if common.IsCorrupted(err) {
+			rw.quarantineCorruptedBlocks(ctx, blockMetas)
+		}
+		return &compactionError{reason: reasonCompactionCompact, err: err}
 	}
 
 	// mark old blocks compacted, so they don't show up in polling
 	if err := markCompacted(rw, tenantID, blockMetas, newCompactedBlocks); err != nil {
-		return err
+		return &compactionError{reason: reasonCompactionMarkCompacted, err: err}
 	}
 
+	var bytesCompacted uint64
+	for _, meta := range blockMetas {
+		bytesCompacted += meta.Size
+	}
 	metricCompactionBlocks.WithLabelValues(compactionLevelLabel).Add(float64(len(blockMetas)))
+	metricCompactionBytesCompacted.WithLabelValues(compactionLevelLabel).Add(float64(bytesCompacted))
+	metricCompactionDuration.WithLabelValues(compactionLevelLabel).Observe(time.Since(startTime).Seconds())
 
 	logArgs := []interface{}{
 		"msg",
@@ -267,6 +532,28 @@ func (rw *readerWriter) compact(ctx context.Context, blockMetas []*backend.Block
 	return nil
 }
 
+// compactionError is an error of a compaction with the reason it is counted under
+type compactionError struct {
+	reason string
+	err    error
+}
+
+func (e *compactionError) Error() string {
+	return e.err.Error()
+}
+
+func (e *compactionError) Unwrap() error {
+	return e.err
+}
+
+func compactionErrorReason(err error) string {
+	var cErr *compactionError
+	if errors.As(err, &cErr) {
+		return cErr.reason
+	}
+	return reasonCompactionUnknown
+}
+
 func markCompacted(rw *readerWriter, tenantID string, oldBlocks, newBlocks []*backend.BlockMeta) error {
 	// Check if we have any errors, but continue marking the blocks as compacted
 	var errCount int
@@ -275,7 +562,7 @@ func markCompacted(rw *readerWriter, tenantID string, oldBlocks, newBlocks []*ba
 		if err := rw.c.MarkBlockCompacted(meta.BlockID, tenantID); err != nil {
 			errCount++
 			level.Error(rw.logger).Log("msg", "unable to mark block compacted", "blockID", meta.BlockID, "tenantID", tenantID, "err", err)
-			metricCompactionErrors.Inc()
+			metricCompactionErrors.WithLabelValues(reasonCompactionMarkCompacted).Inc()
 		}
 	}
 
@@ -289,7 +576,7 @@ func markCompacted(rw *readerWriter, tenantID string, oldBlocks, newBlocks []*ba
 	}
 
 	// Update blocklist in memory
-	rw.blocklist.Update(tenantID, newBlocks, oldBlocks, newCompactions, nil)
+	rw.updateBlocklist(tenantID, newBlocks, oldBlocks, newCompactions, nil)
 
 	if errCount > 0 {
 		return fmt.Errorf("unable to mark %d blocks compacted", errCount)
@@ -298,21 +585,130 @@ func markCompacted(rw *readerWriter, tenantID string, oldBlocks, newBlocks []*ba
 	return nil
 }
 
-func measureOutstandingBlocks(tenantID string, blockSelector CompactionBlockSelector, owned func(hash string) bool) {
+func (rw *readerWriter) measureOutstandingBlocks(tenantID string, blockSelector CompactionBlockSelector) {
 	// count number of per-tenant outstanding blocks before next maintenance cycle
-	var totalOutstandingBlocks int
+	var (
+		totalOutstandingBlocks int
+		byLevel                = map[uint8]int{}
+	)
 	for {
 		leftToBeCompacted, hashString := blockSelector.BlocksToCompact()
 		if len(leftToBeCompacted) == 0 {
 			break
 		}
-		if !owned(hashString) {
+		if !rw.compactorSharder.Owns(hashString) {
 			// continue on this tenant until we find something we own
 			continue
 		}
 		totalOutstandingBlocks += len(leftToBeCompacted)
+		for _, b := range leftToBeCompacted {
+			byLevel[b.CompactionLevel]++
+		}
 	}
 	metricCompactionOutstandingBlocks.WithLabelValues(tenantID).Set(float64(totalOutstandingBlocks))
+
+	// levels without outstanding blocks are removed instead of reported as 0
+	metricCompactionOutstandingBlocksByLevel.DeletePartialMatch(prometheus.Labels{"tenant": tenantID})
+	for l, count := range byLevel {
+		metricCompactionOutstandingBlocksByLevel.WithLabelValues(tenantID, strconv.Itoa(int(l))).Set(float64(count))
+	}
+
+	rw.setTenantCompactionStatus(&TenantCompactionStatus{
+		TenantID:                 tenantID,
+		OutstandingBlocks:        totalOutstandingBlocks,
+		OutstandingBlocksByLevel: byLevel,
+		MeasuredAt:               time.Now(),
+	})
+}
+
+// discardDuplicateBlocks marks compacted the blocks that contain the same traces as another block. The ingesters
+// that receive the same replicated traces can flush identical blocks. Only one of them is kept and compacted instead
+// of combining every copy trace by trace. It returns the blocklist without the duplicates.
+func (rw *readerWriter) discardDuplicateBlocks(tenantID string, blocklist []*backend.BlockMeta) []*backend.BlockMeta {
+	filtered, duplicates := rw.duplicateBlocks(tenantID, blocklist)
+	if len(duplicates) == 0 {
+		return filtered
+	}
+
+	for _, b := range duplicates {
+		level.Info(rw.logger).Log("msg", "discarding duplicate block", "blockID", b.BlockID, "tenantID", tenantID, "fingerprint", b.Fingerprint)
+	}
+	if err := markCompacted(rw, tenantID, duplicates, nil); err != nil {
+		level.Error(rw.logger).Log("msg", "failed to discard duplicate blocks", "tenantID", tenantID, "err", err)
+	}
+	metricCompactionDuplicateBlocks.Add(float64(len(duplicates)))
+
+	return filtered
+}
+
+// duplicateBlocks returns the blocklist without the duplicate blocks and the duplicates this compactor owns
+func (rw *readerWriter) duplicateBlocks(tenantID string, blocklist []*backend.BlockMeta) ([]*backend.BlockMeta, []*backend.BlockMeta) {
+	type blockKey struct {
+		fingerprint      uint64
+		totalObjects     int
+		minID, maxID     string
+		version          string
+		dedicatedColumns uint64
+	}
+
+	// sort by block id so every compactor keeps the same block
+	sorted := make([]*backend.BlockMeta, len(blocklist))
+	copy(sorted, blocklist)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].BlockID.String() < sorted[j].BlockID.String() })
+
+	var (
+		kept       = map[blockKey]struct{}{}
+		filtered   = make([]*backend.BlockMeta, 0, len(sorted))
+		duplicates []*backend.BlockMeta
+	)
+	for _, b := range sorted {
+		// only blocks flushed by the ingesters are replicated
+		if b.CompactionLevel > 0 || b.Fingerprint == 0 || b.ReplicationFactor != backend.DefaultReplicationFactor {
+			filtered = append(filtered, b)
+			continue
+		}
+
+		k := blockKey{
+			fingerprint:      b.Fingerprint,
+			totalObjects:     b.TotalObjects,
+			minID:            string(b.MinID),
+			maxID:            string(b.MaxID),
+			version:          b.Version,
+			dedicatedColumns: b.DedicatedColumnsHash(),
+		}
+		if _, ok := kept[k]; !ok {
+			kept[k] = struct{}{}
+			filtered = append(filtered, b)
+			continue
+		}
+
+		// duplicates are left out of this cycle even if another compactor discards them
+		if rw.compactorSharder.Owns(fmt.Sprintf("%v-%v", tenantID, b.Fingerprint)) {
+			duplicates = append(duplicates, b)
+		}
+	}
+
+	return filtered, duplicates
+}
+
+func withoutDownsampledBlocks(blockMetas []*backend.BlockMeta) []*backend.BlockMeta {
+	filtered := make([]*backend.BlockMeta, 0, len(blockMetas))
+	for _, m := range blockMetas {
+		if !m.Downsampled {
+			filtered = append(filtered, m)
+		}
+	}
+	return filtered
+}
+
+func withoutColdBlocks(blockMetas []*backend.BlockMeta) []*backend.BlockMeta {
+	filtered := make([]*backend.BlockMeta, 0, len(blockMetas))
+	for _, m := range blockMetas {
+		if !m.ColdTier {
+			filtered = append(filtered, m)
+		}
+	}
+	return filtered
 }
 
 func compactionLevelForBlocks(blockMetas []*backend.BlockMeta) uint8 {
@@ -327,6 +723,15 @@ func compactionLevelForBlocks(blockMetas []*backend.BlockMeta) uint8 {
 	return level
 }
 
+// compactorBlockConfig returns the config of the blocks written by the compactor
+func (rw *readerWriter) compactorBlockConfig() common.BlockConfig {
+	cfg := *rw.cfg.Block
+	if rw.compactorCfg != nil && rw.compactorCfg.ParquetCompression != "" {
+		cfg.ParquetCompression = rw.compactorCfg.ParquetCompression
+	}
+	return cfg
+}
+
 type instrumentedObjectCombiner struct {
 	tenant               string
 	compactionLevelLabel string
@@ -49,6 +49,11 @@ type mockOverrides struct {
 	blockRetention      time.Duration
 	maxBytesPerTrace    int
 	maxCompactionWindow time.Duration
+	compactionDisabled  map[string]bool
+	compactionPriority  map[string]int
+	maxStoredBytes      int
+	maxStoredBlocks     int
+	storageQuotaReject  bool
 }
 
 func (m *mockOverrides) BlockRetentionForTenant(_ string) time.Duration {
@@ -63,6 +68,26 @@ func (m *mockOverrides) MaxCompactionRangeForTenant(_ string) time.Duration {
 	return m.maxCompactionWindow
 }
 
+func (m *mockOverrides) CompactionDisabledForTenant(tenantID string) bool {
+	return m.compactionDisabled[tenantID]
+}
+
+func (m *mockOverrides) CompactionPriorityForTenant(tenantID string) int {
+	return m.compactionPriority[tenantID]
+}
+
+func (m *mockOverrides) MaxStoredBytesForTenant(_ string) int {
+	return m.maxStoredBytes
+}
+
+func (m *mockOverrides) MaxStoredBlocksForTenant(_ string) int {
+	return m.maxStoredBlocks
+}
+
+func (m *mockOverrides) StorageQuotaRejectsIngestForTenant(_ string) bool {
+	return m.storageQuotaReject
+}
+
 func TestCompactionRoundtrip(t *testing.T) {
 	for _, enc := range encoding.AllEncodings() {
 		version := enc.Version()
@@ -155,7 +180,7 @@ func testCompactionRoundtrip(t *testing.T, targetBlockVersion string) {
 	rw.pollBlocklist()
 
 	blocklist := rw.blocklist.Metas(testTenantID)
-	blockSelector := newTimeWindowBlockSelector(blocklist, rw.compactorCfg.MaxCompactionRange, 10000, 1024*1024*1024, defaultMinInputBlocks, 2)
+	blockSelector := newTimeWindowBlockSelector(blocklist, rw.compactorCfg.MaxCompactionRange, 0, 10000, 1024*1024*1024, defaultMinInputBlocks, 2, 0, 0)
 
 	expectedCompactions := len(blocklist) / inputBlocks
 	compactions := 0
@@ -323,7 +348,7 @@ func testSameIDCompaction(t *testing.T, targetBlockVersion string) {
 
 	var blocks []*backend.BlockMeta
 	list := rw.blocklist.Metas(testTenantID)
-	blockSelector := newTimeWindowBlockSelector(list, rw.compactorCfg.MaxCompactionRange, 10000, 1024*1024*1024, defaultMinInputBlocks, blockCount)
+	blockSelector := newTimeWindowBlockSelector(list, rw.compactorCfg.MaxCompactionRange, 0, 10000, 1024*1024*1024, defaultMinInputBlocks, blockCount, 0, 0)
 	blocks, _ = blockSelector.BlocksToCompact()
 	require.Len(t, blocks, blockCount)
 
@@ -438,6 +463,79 @@ func TestCompactionUpdatesBlocklist(t *testing.T) {
 	}
 }
 
+func TestCompactionDiscardsDuplicateBlocks(t *testing.T) {
+	tempDir := t.TempDir()
+
+	r, w, c, err := New(&Config{
+		Backend: backend.Local,
+		Pool: &pool.Config{
+			MaxWorkers: 10,
+			QueueDepth: 100,
+		},
+		Local: &local.Config{
+			Path: path.Join(tempDir, "traces"),
+		},
+		Block: &common.BlockConfig{
+			IndexDownsampleBytes: 11,
+			BloomFP:              .01,
+			BloomShardSizeBytes:  100_000,
+			Version:              encoding.DefaultEncoding().Version(),
+			Encoding:             backend.EncNone,
+			IndexPageSizeBytes:   1000,
+		},
+		WAL: &wal.Config{
+			Filepath: path.Join(tempDir, "wal"),
+		},
+		BlocklistPoll: 0,
+	}, nil, log.NewNopLogger())
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	err = c.EnableCompaction(ctx, &CompactorConfig{
+		ChunkSizeBytes:          10,
+		MaxCompactionRange:      24 * time.Hour,
+		BlockRetention:          0,
+		CompactedBlockRetention: 0,
+		DiscardDuplicateBlocks:  true,
+	}, &mockSharder{}, &mockOverrides{})
+	require.NoError(t, err)
+
+	r.EnablePolling(ctx, &mockJobSharder{})
+
+	// 3 copies of a block, a block with the same trace ids but different spans and a unique block
+	now := uint32(time.Now().Unix())
+	data := make([]testData, 10)
+	for j := range data {
+		id := makeTraceID(0, j)
+		data[j] = testData{id: id, t: test.MakeTrace(1, id), start: now, end: now}
+	}
+	for i := 0; i < 3; i++ {
+		cutTestBlockWithTraces(t, w, testTenantID, data)
+	}
+	cutTestBlocks(t, w, testTenantID, 1, 10)
+	cutTestBlocks(t, w, testTenantID, 2, 10)
+
+	rw := r.(*readerWriter)
+	rw.pollBlocklist()
+	require.Len(t, rw.blocklist.Metas(testTenantID), 6)
+
+	blocklist := rw.discardDuplicateBlocks(testTenantID, rw.blocklist.Metas(testTenantID))
+	require.Len(t, blocklist, 4)
+
+	// only the copies are marked compacted
+	require.Len(t, rw.blocklist.Metas(testTenantID), 4)
+	require.Len(t, rw.blocklist.CompactedMetas(testTenantID), 2)
+
+	// every trace is still found
+	for i := 0; i < 2; i++ {
+		for j := 0; j < 10; j++ {
+			trace, _, err := rw.Find(ctx, testTenantID, makeTraceID(i, j), BlockIDMin, BlockIDMax, 0, 0, common.DefaultSearchOptions())
+			require.NoError(t, err)
+			require.NotEmpty(t, trace)
+		}
+	}
+}
+
 func TestCompactionMetrics(t *testing.T) {
 	tempDir := t.TempDir()
 
@@ -574,6 +672,278 @@ func TestCompactionIteratesThroughTenants(t *testing.T) {
 	assert.Equal(t, 1, len(rw.blocklist.Metas(testTenantID2)))
 }
 
+func TestCompactionConcurrency(t *testing.T) {
+	tempDir := t.TempDir()
+
+	r, w, c, err := New(&Config{
+		Backend: backend.Local,
+		Pool: &pool.Config{
+			MaxWorkers: 10,
+			QueueDepth: 100,
+		},
+		Local: &local.Config{
+			Path: path.Join(tempDir, "traces"),
+		},
+		Block: &common.BlockConfig{
+			IndexDownsampleBytes: 11,
+			BloomFP:              .01,
+			BloomShardSizeBytes:  100_000,
+			Version:              encoding.DefaultEncoding().Version(),
+			Encoding:             backend.EncLZ4_64k,
+			IndexPageSizeBytes:   1000,
+		},
+		WAL: &wal.Config{
+			Filepath: path.Join(tempDir, "wal"),
+		},
+		BlocklistPoll: 0,
+	}, nil, log.NewNopLogger())
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	err = c.EnableCompaction(ctx, &CompactorConfig{
+		ChunkSizeBytes:          10,
+		MaxCompactionRange:      24 * time.Hour,
+		MaxCompactionObjects:    1000,
+		MaxBlockBytes:           1024 * 1024 * 1024,
+		MaxTimePerTenant:        time.Minute,
+		CompactionConcurrency:   2,
+		BlockRetention:          0,
+		CompactedBlockRetention: 0,
+	}, &mockSharder{}, &mockOverrides{})
+	require.NoError(t, err)
+
+	r.EnablePolling(ctx, &mockJobSharder{})
+
+	cutTestBlocks(t, w, testTenantID, 2, 2)
+	cutTestBlocks(t, w, testTenantID2, 2, 2)
+
+	rw := r.(*readerWriter)
+	rw.pollBlocklist()
+
+	require.Len(t, rw.blocklist.Metas(testTenantID), 2)
+	require.Len(t, rw.blocklist.Metas(testTenantID2), 2)
+
+	// both tenants are compacted in the same cycle
+	rw.doCompaction(ctx)
+	require.Len(t, rw.blocklist.Metas(testTenantID), 1)
+	require.Len(t, rw.blocklist.Metas(testTenantID2), 1)
+	require.Len(t, rw.blocklist.CompactedMetas(testTenantID), 2)
+	require.Len(t, rw.blocklist.CompactedMetas(testTenantID2), 2)
+
+	running, err := test.GetGaugeVecValue(metricCompactionsRunning, testTenantID)
+	require.NoError(t, err)
+	require.Equal(t, float64(0), running)
+
+	// the jobs are finished and the backlog of both tenants is measured
+	status := rw.CompactionStatus()
+	require.Empty(t, status.Jobs)
+	require.Len(t, status.Tenants, 2)
+	require.Equal(t, testTenantID, status.Tenants[0].TenantID)
+	require.Equal(t, testTenantID2, status.Tenants[1].TenantID)
+	for _, tenant := range status.Tenants {
+		require.Zero(t, tenant.OutstandingBlocks)
+		require.Empty(t, tenant.OutstandingBlocksByLevel)
+	}
+}
+
+func TestCompactionTenantOverrides(t *testing.T) {
+	tempDir := t.TempDir()
+
+	r, w, c, err := New(&Config{
+		Backend: backend.Local,
+		Pool: &pool.Config{
+			MaxWorkers: 10,
+			QueueDepth: 100,
+		},
+		Local: &local.Config{
+			Path: path.Join(tempDir, "traces"),
+		},
+		Block: &common.BlockConfig{
+			IndexDownsampleBytes: 11,
+			BloomFP:              .01,
+			BloomShardSizeBytes:  100_000,
+			Version:              encoding.DefaultEncoding().Version(),
+			Encoding:             backend.EncLZ4_64k,
+			IndexPageSizeBytes:   1000,
+		},
+		WAL: &wal.Config{
+			Filepath: path.Join(tempDir, "wal"),
+		},
+		BlocklistPoll: 0,
+	}, nil, log.NewNopLogger())
+	require.NoError(t, err)
+
+	overrides := &mockOverrides{
+		compactionDisabled: map[string]bool{testTenantID: true},
+		compactionPriority: map[string]int{},
+	}
+
+	ctx := context.Background()
+	err = c.EnableCompaction(ctx, &CompactorConfig{
+		ChunkSizeBytes:          10,
+		MaxCompactionRange:      24 * time.Hour,
+		MaxCompactionObjects:    1000,
+		MaxBlockBytes:           1024 * 1024 * 1024,
+		MaxTimePerTenant:        time.Minute,
+		BlockRetention:          0,
+		CompactedBlockRetention: 0,
+	}, &mockSharder{}, overrides)
+	require.NoError(t, err)
+
+	r.EnablePolling(ctx, &mockJobSharder{})
+
+	cutTestBlocks(t, w, testTenantID, 2, 2)
+	cutTestBlocks(t, w, testTenantID2, 2, 2)
+
+	rw := r.(*readerWriter)
+	rw.pollBlocklist()
+
+	// the tenant with compaction disabled is skipped
+	rw.doCompaction(ctx)
+	rw.doCompaction(ctx)
+	require.Len(t, rw.blocklist.Metas(testTenantID), 2)
+	require.Len(t, rw.blocklist.Metas(testTenantID2), 1)
+
+	cutTestBlocks(t, w, testTenantID2, 2, 2)
+	rw.pollBlocklist()
+	require.Len(t, rw.blocklist.Metas(testTenantID2), 3)
+
+	// the tenant with the lower priority waits while the other tenant has blocks to compact
+	overrides.compactionDisabled = nil
+	overrides.compactionPriority[testTenantID] = -1
+	rw.doCompaction(ctx)
+	require.Len(t, rw.blocklist.Metas(testTenantID), 2)
+	require.Len(t, rw.blocklist.Metas(testTenantID2), 2)
+
+	rw.doCompaction(ctx)
+	require.Len(t, rw.blocklist.Metas(testTenantID), 2)
+	require.Len(t, rw.blocklist.Metas(testTenantID2), 1)
+
+	rw.doCompaction(ctx)
+	require.Len(t, rw.blocklist.Metas(testTenantID), 1)
+	require.Len(t, rw.blocklist.Metas(testTenantID2), 1)
+
+	// the tenant with the lower priority gains a priority for every cycle it waits, so it isn't starved by a tenant
+	// that always has blocks to compact
+	overrides.compactionPriority[testTenantID] = -2
+	cutTestBlocks(t, w, testTenantID, 2, 2)
+	for i := 0; i < 2; i++ {
+		cutTestBlocks(t, w, testTenantID2, 2, 2)
+		rw.pollBlocklist()
+		rw.doCompaction(ctx)
+		require.Len(t, rw.blocklist.Metas(testTenantID), 3)
+	}
+	require.Equal(t, 2, rw.compactorTenantWaits[testTenantID])
+
+	// once the priorities are equal the tenants take turns
+	for i := 0; i < 2 && len(rw.blocklist.Metas(testTenantID)) == 3; i++ {
+		cutTestBlocks(t, w, testTenantID2, 2, 2)
+		rw.pollBlocklist()
+		rw.doCompaction(ctx)
+	}
+	require.Less(t, len(rw.blocklist.Metas(testTenantID)), 3)
+	require.NotContains(t, rw.compactorTenantWaits, testTenantID)
+}
+
+func TestHasBlocksToCompactKeepsDuplicateBlocks(t *testing.T) {
+	r, w, c, _ := testConfig(t, backend.EncNone, 0)
+
+	ctx := context.Background()
+	err := c.EnableCompaction(ctx, &CompactorConfig{
+		ChunkSizeBytes:         10,
+		MaxCompactionRange:     24 * time.Hour,
+		MaxCompactionObjects:   1000,
+		MaxBlockBytes:          1024 * 1024 * 1024,
+		DiscardDuplicateBlocks: true,
+	}, &mockSharder{}, &mockOverrides{})
+	require.NoError(t, err)
+
+	r.EnablePolling(ctx, &mockJobSharder{})
+
+	now := uint32(time.Now().Unix())
+	id := makeTraceID(0, 0)
+	data := []testData{{id: id, t: test.MakeTrace(1, id), start: now, end: now}}
+	for i := 0; i < 2; i++ {
+		cutTestBlockWithTraces(t, w, testTenantID, data)
+	}
+	cutTestBlocks(t, w, testTenantID, 2, 2)
+
+	rw := r.(*readerWriter)
+	rw.pollBlocklist()
+
+	// probing the tenant doesn't discard the duplicate block
+	require.True(t, rw.hasBlocksToCompact(testTenantID))
+	blocks, _ := rw.blocksToCompact(testTenantID)
+	require.Equal(t, 3, blocks)
+	require.Len(t, rw.blocklist.Metas(testTenantID), 4)
+	require.Empty(t, rw.blocklist.CompactedMetas(testTenantID))
+}
+
+func TestCompactionLargestFirst(t *testing.T) {
+	tempDir := t.TempDir()
+
+	r, w, c, err := New(&Config{
+		Backend: backend.Local,
+		Pool: &pool.Config{
+			MaxWorkers: 10,
+			QueueDepth: 100,
+		},
+		Local: &local.Config{
+			Path: path.Join(tempDir, "traces"),
+		},
+		Block: &common.BlockConfig{
+			IndexDownsampleBytes: 11,
+			BloomFP:              .01,
+			BloomShardSizeBytes:  100_000,
+			Version:              encoding.DefaultEncoding().Version(),
+			Encoding:             backend.EncLZ4_64k,
+			IndexPageSizeBytes:   1000,
+		},
+		WAL: &wal.Config{
+			Filepath: path.Join(tempDir, "wal"),
+		},
+		BlocklistPoll: 0,
+	}, nil, log.NewNopLogger())
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	err = c.EnableCompaction(ctx, &CompactorConfig{
+		ChunkSizeBytes:           10,
+		MaxCompactionRange:       24 * time.Hour,
+		MaxCompactionObjects:     1000,
+		MaxBlockBytes:            1024 * 1024 * 1024,
+		MaxTimePerTenant:         time.Minute,
+		BlockRetention:           0,
+		CompactedBlockRetention:  0,
+		TenantSchedulingStrategy: TenantSchedulingLargestFirst,
+	}, &mockSharder{}, &mockOverrides{})
+	require.NoError(t, err)
+
+	r.EnablePolling(ctx, &mockJobSharder{})
+
+	cutTestBlocks(t, w, testTenantID, 2, 2)
+	cutTestBlocks(t, w, testTenantID2, 4, 2)
+	cutTestBlocks(t, w, "downsampled", 6, 2)
+
+	rw := r.(*readerWriter)
+	rw.pollBlocklist()
+
+	// downsampled blocks are not compacted so the tenant with the most blocks has none to compact
+	for _, m := range rw.blocklist.Metas("downsampled") {
+		m.Downsampled = true
+	}
+
+	// the tenant with the most blocks to compact is compacted first
+	rw.doCompaction(ctx)
+	require.Len(t, rw.blocklist.Metas(testTenantID), 2)
+	require.Less(t, len(rw.blocklist.Metas(testTenantID2)), 2)
+
+	// and keeps being picked while it has more blocks
+	rw.doCompaction(ctx)
+	require.Len(t, rw.blocklist.Metas(testTenantID), 1)
+	require.Len(t, rw.blocklist.Metas("downsampled"), 6)
+}
+
 func TestCompactionHonorsBlockStartEndTimes(t *testing.T) {
 	for _, enc := range encoding.AllEncodings() {
 		version := enc.Version()
@@ -10,11 +10,16 @@ import (
 	"github.com/grafana/tempo/modules/cache/redis"
 
 	"github.com/grafana/tempo/pkg/cache"
+	"github.com/grafana/tempo/pkg/model/trace"
 	azure "github.com/grafana/tempo/tempodb/backend/azure/config"
 	backend_cache "github.com/grafana/tempo/tempodb/backend/cache"
+	"github.com/grafana/tempo/tempodb/backend/encryption"
 	"github.com/grafana/tempo/tempodb/backend/gcs"
 	"github.com/grafana/tempo/tempodb/backend/local"
+	"github.com/grafana/tempo/tempodb/backend/ratelimit"
+	"github.com/grafana/tempo/tempodb/backend/retry"
 	"github.com/grafana/tempo/tempodb/backend/s3"
+	"github.com/grafana/tempo/tempodb/backend/swift"
 	"github.com/grafana/tempo/tempodb/encoding"
 	"github.com/grafana/tempo/tempodb/encoding/common"
 	"github.com/grafana/tempo/tempodb/pool"
@@ -50,7 +55,9 @@ type Config struct {
 	BlocklistPollFallback                  bool          `yaml:"blocklist_poll_fallback"`
 	BlocklistPollTenantIndexBuilders       int           `yaml:"blocklist_poll_tenant_index_builders"`
 	BlocklistPollStaleTenantIndex          time.Duration `yaml:"blocklist_poll_stale_tenant_index"`
+	BlocklistPollStaleTenantIndexTolerance time.Duration `yaml:"blocklist_poll_stale_tenant_index_tolerance"`
 	BlocklistPollJitterMs                  int           `yaml:"blocklist_poll_jitter_ms"`
+	BlocklistPollCycleJitter               time.Duration `yaml:"blocklist_poll_cycle_jitter"`
 	BlocklistPollTolerateConsecutiveErrors int           `yaml:"blocklist_poll_tolerate_consecutive_errors"`
 
 	EmptyTenantDeletionEnabled bool          `yaml:"empty_tenant_deletion_enabled"`
@@ -62,6 +69,18 @@ type Config struct {
 	GCS     *gcs.Config   `yaml:"gcs"`
 	S3      *s3.Config    `yaml:"s3"`
 	Azure   *azure.Config `yaml:"azure"`
+	Swift   *swift.Config `yaml:"swift"`
+
+	// retries of the backend operations that fail with a transient error
+	Retry retry.Config `yaml:"retry"`
+	// limits of the metadata operations sent to the backend
+	RateLimit ratelimit.Config `yaml:"rate_limit"`
+	// tenants stored in their own bucket or prefix
+	TenantRoutes []TenantRoute `yaml:"tenant_routes"`
+	// client-side encryption of the blocks
+	Encryption encryption.Config `yaml:"encryption"`
+	// bucket or prefix of the blocks moved to the cold tier by the compactors
+	ColdTier ColdTierConfig `yaml:"cold_tier"`
 
 	// legacy cache config. this is loaded by tempodb and added to the cache
 	// provider on construction
@@ -120,17 +139,43 @@ func (c SearchConfig) ApplyToOptions(o *common.SearchOptions) {
 
 // CompactorConfig contains compaction configuration options
 type CompactorConfig struct {
-	ChunkSizeBytes          uint32        `yaml:"v2_in_buffer_bytes"`
-	FlushSizeBytes          uint32        `yaml:"v2_out_buffer_bytes"`
-	IteratorBufferSize      int           `yaml:"v2_prefetch_traces_count"`
-	MaxCompactionRange      time.Duration `yaml:"compaction_window"`
-	MaxCompactionObjects    int           `yaml:"max_compaction_objects"`
-	MaxBlockBytes           uint64        `yaml:"max_block_bytes"`
-	BlockRetention          time.Duration `yaml:"block_retention"`
-	CompactedBlockRetention time.Duration `yaml:"compacted_block_retention"`
-	RetentionConcurrency    uint          `yaml:"retention_concurrency"`
-	MaxTimePerTenant        time.Duration `yaml:"max_time_per_tenant"`
-	CompactionCycle         time.Duration `yaml:"compaction_cycle"`
+	ChunkSizeBytes             uint32               `yaml:"v2_in_buffer_bytes"`
+	FlushSizeBytes             uint32               `yaml:"v2_out_buffer_bytes"`
+	IteratorBufferSize         int                  `yaml:"v2_prefetch_traces_count"`
+	MaxCompactionRange         time.Duration        `yaml:"compaction_window"`
+	MaxTimeRange               time.Duration        `yaml:"max_time_range"`
+	OldWindowMaxInputBlocks    int                  `yaml:"old_window_max_input_blocks"`
+	OldWindowMaxTimeRange      time.Duration        `yaml:"old_window_max_time_range"`
+	MaxCompactionObjects       int                  `yaml:"max_compaction_objects"`
+	MaxBlockBytes              uint64               `yaml:"max_block_bytes"`
+	BlockRetention             time.Duration        `yaml:"block_retention"`
+	CompactedBlockRetention    time.Duration        `yaml:"compacted_block_retention"`
+	RetentionConcurrency       uint                 `yaml:"retention_concurrency"`
+	RetentionDryRun            bool                 `yaml:"retention_dry_run"`
+	RetentionMaxBlocksPerCycle int                  `yaml:"retention_max_blocks_per_cycle"`
+	MaxTraceRetention          time.Duration        `yaml:"max_trace_retention"`
+	DiscardDuplicateBlocks     bool                 `yaml:"discard_duplicate_blocks"`
+	MaxTimePerTenant           time.Duration        `yaml:"max_time_per_tenant"`
+	CompactionCycle            time.Duration        `yaml:"compaction_cycle"`
+	CompactionConcurrency      int                  `yaml:"compaction_concurrency"`
+	TenantSchedulingStrategy   string               `yaml:"tenant_scheduling_strategy"`
+	Downsample                 DownsampleConfig     `yaml:"downsample"`
+	Migrate                    MigrateConfig        `yaml:"migrate"`
+	ColdTier                   ColdTierPolicyConfig `yaml:"cold_tier"`
+	// ParquetCompression overrides the compression of the blocks written by the compactor. Empty uses the
+	// compression of the block config
+	ParquetCompression string `yaml:"parquet_compression"`
+}
+
+// DownsampleConfig controls the rewriting of blocks older than After into blocks that keep only a reduced copy of
+// their traces. Error traces and trace skeletons retain most of their forensic value at a fraction of the storage.
+type DownsampleConfig struct {
+	// After is the block age after which blocks are downsampled. 0 disables downsampling
+	After time.Duration `yaml:"after"`
+	// ErrorsOnly drops all traces that don't contain a span with an error status
+	ErrorsOnly bool `yaml:"errors_only"`
+	// Strip is a comma separated list of the parts of the kept traces to remove: attributes, events and links
+	Strip string `yaml:"strip"`
 }
 
 func (compactorConfig CompactorConfig) validate() error {
@@ -138,6 +183,28 @@ func (compactorConfig CompactorConfig) validate() error {
 		return errors.New("Compaction window can't be 0")
 	}
 
+	switch compactorConfig.TenantSchedulingStrategy {
+	case "", TenantSchedulingRoundRobin, TenantSchedulingLargestFirst:
+	default:
+		return fmt.Errorf("invalid tenant scheduling strategy %s. valid values are %s and %s", compactorConfig.TenantSchedulingStrategy, TenantSchedulingRoundRobin, TenantSchedulingLargestFirst)
+	}
+
+	if compactorConfig.ParquetCompression != "" {
+		if _, err := common.ParquetCompression(compactorConfig.ParquetCompression); err != nil {
+			return fmt.Errorf("invalid compactor parquet compression: %w", err)
+		}
+	}
+
+	if compactorConfig.Downsample.After > 0 {
+		strip, ok := trace.ParseStripOptions(compactorConfig.Downsample.Strip)
+		if !ok {
+			return fmt.Errorf("invalid downsample strip %s. valid values are %s, %s and %s", compactorConfig.Downsample.Strip, trace.StripAttributes, trace.StripEvents, trace.StripLinks)
+		}
+		if !compactorConfig.Downsample.ErrorsOnly && strip.IsEmpty() {
+			return errors.New("downsample requires errors_only or strip to be set")
+		}
+	}
+
 	return nil
 }
 
@@ -174,5 +241,30 @@ func validateConfig(cfg *Config) error {
 		return fmt.Errorf("block version validation failed: %w", err)
 	}
 
+	err = cfg.Retry.Validate()
+	if err != nil {
+		return fmt.Errorf("retry config validation failed: %w", err)
+	}
+
+	err = cfg.RateLimit.Validate()
+	if err != nil {
+		return fmt.Errorf("rate limit config validation failed: %w", err)
+	}
+
+	err = validateTenantRoutes(cfg.Backend, cfg.TenantRoutes)
+	if err != nil {
+		return fmt.Errorf("tenant routes validation failed: %w", err)
+	}
+
+	err = cfg.Encryption.Validate()
+	if err != nil {
+		return fmt.Errorf("encryption config validation failed: %w", err)
+	}
+
+	err = cfg.ColdTier.validate(cfg.Backend)
+	if err != nil {
+		return fmt.Errorf("cold tier config validation failed: %w", err)
+	}
+
 	return nil
 }
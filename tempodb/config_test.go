@@ -3,6 +3,7 @@ package tempodb
 import (
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/grafana/tempo/tempodb/encoding/common"
 	"github.com/grafana/tempo/tempodb/wal"
@@ -186,4 +187,18 @@ func TestValidateCompactorConfig(t *testing.T) {
 	actual := compactorConfig.validate()
 
 	require.Equal(t, expected, actual)
+
+	compactorConfig.MaxCompactionRange = time.Hour
+	compactorConfig.TenantSchedulingStrategy = TenantSchedulingLargestFirst
+	require.NoError(t, compactorConfig.validate())
+
+	compactorConfig.TenantSchedulingStrategy = "smallest_first"
+	require.EqualError(t, compactorConfig.validate(), "invalid tenant scheduling strategy smallest_first. valid values are round_robin and largest_first")
+
+	compactorConfig.TenantSchedulingStrategy = TenantSchedulingRoundRobin
+	compactorConfig.ParquetCompression = "zstd"
+	require.NoError(t, compactorConfig.validate())
+
+	compactorConfig.ParquetCompression = "s2"
+	require.EqualError(t, compactorConfig.validate(), "invalid compactor parquet compression: unsupported parquet compression s2, supported: [none snappy gzip lz4 zstd]")
 }
@@ -0,0 +1,236 @@
+package tempodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-kit/log/level"
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/grafana/tempo/pkg/model/trace"
+	"github.com/grafana/tempo/pkg/tempopb"
+	v1_trace "github.com/grafana/tempo/pkg/tempopb/trace/v1"
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/encoding"
+	"github.com/grafana/tempo/tempodb/encoding/common"
+)
+
+var (
+	metricDownsampledBlocks = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "tempodb",
+		Name:      "downsample_blocks_total",
+		Help:      "Total number of blocks rewritten by downsampling.",
+	})
+	metricDownsampleTraces = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "tempodb",
+		Name:      "downsample_traces_total",
+		Help:      "Total number of traces kept or dropped by downsampling.",
+	}, []string{"result"})
+	metricDownsampleErrors = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "tempodb",
+		Name:      "downsample_errors_total",
+		Help:      "Total number of times an error occurred while downsampling blocks.",
+	})
+)
+
+var errRewriteUnsupported = errors.New("block version does not support rewriting")
+
+// downsampleTenant rewrites the owned blocks of the tenant that are older than the downsample age. the new
+// block keeps only the traces and the parts of the traces selected by the downsample config and replaces the
+// original block which is marked compacted.
+func (rw *readerWriter) downsampleTenant(ctx context.Context, tenantID string) {
+	cfg := rw.compactorCfg.Downsample
+	if cfg.After <= 0 {
+		return
+	}
+
+	strip, _ := trace.ParseStripOptions(cfg.Strip) // validated in CompactorConfig.validate()
+	cutoff := time.Now().Add(-cfg.After)
+
+	for _, b := range rw.blocklist.Metas(tenantID) {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+
+		if b.Downsampled || !b.EndTime.Before(cutoff) || !rw.compactorSharder.Owns(b.BlockID.String()) {
+			continue
+		}
+
+		if !rw.claimBlockForRewrite(ctx, tenantID, b) {
+			continue
+		}
+		err := rw.downsampleBlock(ctx, tenantID, b, cfg.ErrorsOnly, strip)
+		rw.releaseBlocks([]*backend.BlockMeta{b})
+		if errors.Is(err, errRewriteUnsupported) {
+			level.Debug(rw.logger).Log("msg", "skipping downsampling of block", "blockID", b.BlockID, "tenantID", tenantID, "version", b.Version)
+			continue
+		}
+		if err != nil {
+			level.Error(rw.logger).Log("msg", "failed to downsample block", "blockID", b.BlockID, "tenantID", tenantID, "err", err)
+			metricDownsampleErrors.Inc()
+		}
+	}
+}
+
+func (rw *readerWriter) downsampleBlock(ctx context.Context, tenantID string, meta *backend.BlockMeta, errorsOnly bool, strip trace.StripOptions) error {
+	start := time.Now()
+
+	kept, dropped, err := rw.rewriteBlock(ctx, tenantID, meta, true, func(_ common.ID, tr *tempopb.Trace) bool {
+		if errorsOnly && !hasErrorSpan(tr) {
+			return false
+		}
+		trace.Strip(tr, strip)
+		return true
+	})
+	if err != nil {
+		return err
+	}
+
+	metricDownsampledBlocks.Inc()
+	metricDownsampleTraces.WithLabelValues("kept").Add(float64(kept))
+	metricDownsampleTraces.WithLabelValues("dropped").Add(float64(dropped))
+
+	level.Info(rw.logger).Log("msg", "downsampled block", "blockID", meta.BlockID, "tenantID", tenantID, "kept", kept, "dropped", dropped, "elapsed", time.Since(start))
+	return nil
+}
+
+// rewriteBlock replaces the block with a new block that holds only the traces kept by rewrite. rewrite can modify the
+// traces it keeps. the original block is marked compacted. if no trace is kept no new block is created.
+func (rw *readerWriter) rewriteBlock(ctx context.Context, tenantID string, meta *backend.BlockMeta, downsampled bool, rewrite func(common.ID, *tempopb.Trace) bool) (kept, dropped int, err error) {
+	enc, err := encoding.FromVersion(meta.Version)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	block, err := enc.OpenBlock(meta, rw.r)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	iterable, ok := block.(common.IterableBlock)
+	if !ok {
+		return 0, 0, errRewriteUnsupported
+	}
+
+	iter, err := iterable.Iterator(ctx)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	rewritten := &rewriteIterator{
+		iter:    iter,
+		rewrite: rewrite,
+	}
+	defer rewritten.Close()
+
+	// look for the first trace to keep before creating a block. if there is none the original block is
+	// only marked compacted
+	if err := rewritten.peek(ctx); err != nil {
+		return 0, 0, fmt.Errorf("error iterating block: %w", err)
+	}
+
+	var newBlocks []*backend.BlockMeta
+	if rewritten.peeked != nil {
+		newMeta := &backend.BlockMeta{
+			BlockID:           uuid.New(),
+			TenantID:          tenantID,
+			StartTime:         meta.StartTime,
+			EndTime:           meta.EndTime,
+			TotalObjects:      meta.TotalObjects, // estimate for the bloom filter
+			CompactionLevel:   meta.CompactionLevel,
+			DedicatedColumns:  meta.DedicatedColumns,
+			ReplicationFactor: meta.ReplicationFactor,
+			Downsampled:       meta.Downsampled || downsampled,
+			Retention:         meta.Retention,
+		}
+
+		blockCfg := rw.compactorBlockConfig()
+		blockCfg.Version = meta.Version
+
+		newMeta, err = enc.CreateBlock(ctx, &blockCfg, newMeta, rewritten, rw.r, rw.w)
+		if err != nil {
+			return 0, 0, fmt.Errorf("error creating rewritten block: %w", err)
+		}
+		newBlocks = append(newBlocks, newMeta)
+	}
+
+	if err := markCompacted(rw, tenantID, []*backend.BlockMeta{meta}, newBlocks); err != nil {
+		return 0, 0, err
+	}
+
+	return rewritten.kept, rewritten.dropped, nil
+}
+
+// rewriteIterator wraps an iterator and returns only the traces kept by the rewrite func.
+type rewriteIterator struct {
+	iter    common.Iterator
+	rewrite func(common.ID, *tempopb.Trace) bool
+
+	peekedID common.ID
+	peeked   *tempopb.Trace
+
+	kept    int
+	dropped int
+}
+
+var _ common.Iterator = (*rewriteIterator)(nil)
+
+func (i *rewriteIterator) peek(ctx context.Context) error {
+	id, tr, err := i.next(ctx)
+	if err != nil {
+		return err
+	}
+
+	i.peekedID, i.peeked = id, tr
+	return nil
+}
+
+func (i *rewriteIterator) Next(ctx context.Context) (common.ID, *tempopb.Trace, error) {
+	if i.peeked != nil {
+		id, tr := i.peekedID, i.peeked
+		i.peekedID, i.peeked = nil, nil
+		return id, tr, nil
+	}
+
+	return i.next(ctx)
+}
+
+func (i *rewriteIterator) next(ctx context.Context) (common.ID, *tempopb.Trace, error) {
+	for {
+		id, tr, err := i.iter.Next(ctx)
+		if err != nil || tr == nil {
+			return nil, nil, err
+		}
+
+		if !i.rewrite(id, tr) {
+			i.dropped++
+			continue
+		}
+
+		i.kept++
+		return id, tr, nil
+	}
+}
+
+func (i *rewriteIterator) Close() {
+	i.iter.Close()
+}
+
+func hasErrorSpan(tr *tempopb.Trace) bool {
+	for _, b := range tr.Batches {
+		for _, ss := range b.ScopeSpans {
+			for _, s := range ss.Spans {
+				if s.Status != nil && s.Status.Code == v1_trace.Status_STATUS_CODE_ERROR {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,144 @@
+package tempodb
+
+import (
+	"context"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	v1_trace "github.com/grafana/tempo/pkg/tempopb/trace/v1"
+	"github.com/grafana/tempo/pkg/util/test"
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/backend/local"
+	"github.com/grafana/tempo/tempodb/encoding"
+	"github.com/grafana/tempo/tempodb/encoding/common"
+	"github.com/grafana/tempo/tempodb/wal"
+)
+
+func TestDownsample(t *testing.T) {
+	tempDir := t.TempDir()
+
+	r, w, c, err := New(&Config{
+		Backend: backend.Local,
+		Local: &local.Config{
+			Path: path.Join(tempDir, "traces"),
+		},
+		Block: &common.BlockConfig{
+			IndexDownsampleBytes: 17,
+			BloomFP:              0.01,
+			BloomShardSizeBytes:  100_000,
+			Version:              encoding.DefaultEncoding().Version(),
+			Encoding:             backend.EncLZ4_256k,
+			IndexPageSizeBytes:   1000,
+			RowGroupSizeBytes:    30_000_000,
+		},
+		WAL: &wal.Config{
+			Filepath: path.Join(tempDir, "wal"),
+		},
+		BlocklistPoll: 0,
+	}, nil, log.NewNopLogger())
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	err = c.EnableCompaction(ctx, &CompactorConfig{
+		ChunkSizeBytes:          10,
+		MaxCompactionRange:      time.Hour,
+		BlockRetention:          24 * time.Hour,
+		CompactedBlockRetention: time.Hour,
+		Downsample: DownsampleConfig{
+			After:      time.Minute,
+			ErrorsOnly: true,
+			Strip:      "attributes,events,links",
+		},
+	}, &mockSharder{}, &mockOverrides{})
+	require.NoError(t, err)
+
+	r.EnablePolling(ctx, &mockJobSharder{})
+	rw := r.(*readerWriter)
+
+	errorID := test.ValidTraceID(nil)
+	errorTrace := test.MakeTrace(2, errorID)
+	errorTrace.Batches[0].ScopeSpans[0].Spans[0].Status.Code = v1_trace.Status_STATUS_CODE_ERROR
+
+	okID := test.ValidTraceID(nil)
+	okTrace := test.MakeTrace(2, okID)
+
+	old := uint32(time.Now().Add(-time.Hour).Unix())
+	original := cutTestBlockWithTraces(t, w, testTenantID, []testData{
+		{id: errorID, t: errorTrace, start: old, end: old},
+		{id: okID, t: okTrace, start: old, end: old},
+	})
+
+	checkBlocklists(t, original.BlockMeta().BlockID, 1, 0, rw)
+
+	// the block is replaced with a downsampled block
+	rw.doMaintenance(ctx, rw.downsampleTenant)
+
+	metas := rw.blocklist.Metas(testTenantID)
+	require.Len(t, metas, 1)
+	downsampled := metas[0]
+	require.True(t, downsampled.Downsampled)
+	require.NotEqual(t, original.BlockMeta().BlockID, downsampled.BlockID)
+	require.Equal(t, 1, downsampled.TotalObjects)
+
+	compacted := rw.blocklist.CompactedMetas(testTenantID)
+	require.Len(t, compacted, 1)
+	require.Equal(t, original.BlockMeta().BlockID, compacted[0].BlockID)
+
+	// the downsampled flag survives polling
+	checkBlocklists(t, uuid.Nil, 1, 1, rw)
+	require.Equal(t, downsampled.BlockID, rw.blocklist.Metas(testTenantID)[0].BlockID)
+	require.True(t, rw.blocklist.Metas(testTenantID)[0].Downsampled)
+
+	// only the error trace is kept and it is stripped
+	block, err := encoding.OpenBlock(downsampled, rw.r)
+	require.NoError(t, err)
+
+	tr, err := block.FindTraceByID(ctx, okID, common.DefaultSearchOptions())
+	require.NoError(t, err)
+	require.Nil(t, tr)
+
+	tr, err = block.FindTraceByID(ctx, errorID, common.DefaultSearchOptions())
+	require.NoError(t, err)
+	require.NotNil(t, tr)
+	require.True(t, hasErrorSpan(tr))
+	for _, b := range tr.Batches {
+		for _, ss := range b.ScopeSpans {
+			for _, s := range ss.Spans {
+				require.Empty(t, s.Attributes)
+				require.Empty(t, s.Events)
+				require.Empty(t, s.Links)
+			}
+		}
+	}
+
+	// downsampled blocks are not downsampled again
+	rw.doMaintenance(ctx, rw.downsampleTenant)
+	checkBlocklists(t, uuid.Nil, 1, 1, rw)
+	require.Equal(t, downsampled.BlockID, rw.blocklist.Metas(testTenantID)[0].BlockID)
+}
+
+func TestDownsampleConfigValidate(t *testing.T) {
+	cfg := CompactorConfig{
+		MaxCompactionRange: time.Hour,
+		Downsample:         DownsampleConfig{After: time.Hour},
+	}
+	require.Error(t, cfg.validate())
+
+	cfg.Downsample.Strip = "foo"
+	require.Error(t, cfg.validate())
+
+	cfg.Downsample.Strip = "attributes"
+	require.NoError(t, cfg.validate())
+
+	cfg.Downsample = DownsampleConfig{After: time.Hour, ErrorsOnly: true}
+	require.NoError(t, cfg.validate())
+
+	// disabled
+	cfg.Downsample = DownsampleConfig{Strip: "foo"}
+	require.NoError(t, cfg.validate())
+}
@@ -3,10 +3,20 @@ package common
 import (
 	"errors"
 	"strconv"
+
+	"github.com/parquet-go/parquet-go"
 )
 
 var ErrUnsupported = errors.New("unsupported")
 
+// ErrCorrupted is returned when the checksum of data read from a block doesn't match the checksum written with it
+var ErrCorrupted = errors.New("corrupted block")
+
+// IsCorrupted returns true if err was caused by a checksum mismatch when reading a block
+func IsCorrupted(err error) bool {
+	return errors.Is(err, ErrCorrupted) || errors.Is(err, parquet.ErrCorrupted)
+}
+
 const (
 	// NameObjects names the backend data object
 	NameObjects = "data"
@@ -29,7 +29,8 @@ type BlockConfig struct {
 	Encoding             backend.Encoding `yaml:"v2_encoding"`
 
 	// parquet fields
-	RowGroupSizeBytes int `yaml:"parquet_row_group_size_bytes"`
+	RowGroupSizeBytes  int    `yaml:"parquet_row_group_size_bytes"`
+	ParquetCompression string `yaml:"parquet_compression"`
 
 	// vParquet3 fields
 	DedicatedColumns backend.DedicatedColumns `yaml:"parquet_dedicated_columns"`
@@ -45,6 +46,7 @@ func (cfg *BlockConfig) RegisterFlagsAndApplyDefaults(prefix string, f *flag.Fla
 	cfg.SearchEncoding = backend.EncSnappy
 	cfg.SearchPageSizeBytes = 1024 * 1024 // 1 MB
 	cfg.RowGroupSizeBytes = 100_000_000   // 100 MB
+	cfg.ParquetCompression = backend.EncSnappy.String()
 }
 
 // ValidateConfig returns true if the config is valid
@@ -65,5 +67,9 @@ func ValidateConfig(b *BlockConfig) error {
 		return fmt.Errorf("positive value required for bloom-filter shard size")
 	}
 
+	if _, err := ParquetCompression(b.ParquetCompression); err != nil {
+		return err
+	}
+
 	return b.DedicatedColumns.Validate()
 }
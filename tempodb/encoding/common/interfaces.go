@@ -38,6 +38,7 @@ type SearchOptions struct {
 	ReadBufferCount        int
 	ReadBufferSize         int
 	BlockReplicationFactor int // Only blocks with this replication factor will be searched. Set to 1 to search generator blocks (RF=1).
+	MaxConcurrentBlocks    int // Max number of blocks searched in parallel by a single trace by id lookup. 0 is unlimited.
 }
 
 // DefaultSearchOptions is used in a lot of places such as local ingester searches. It is important
@@ -78,6 +79,9 @@ type CompactionOptions struct {
 	BytesWritten      func(compactionLevel, bytes int)
 	SpansDiscarded    func(traceID string, rootSpanName string, rootServiceName string, spans int)
 	DisconnectedTrace func()
+
+	// DropObject returns true for the objects that are left out of the compacted blocks
+	DropObject func(id ID) bool
 }
 
 type Iterator interface {
@@ -92,6 +96,13 @@ type BackendBlock interface {
 	BlockMeta() *backend.BlockMeta
 }
 
+// IterableBlock is a BackendBlock that can iterate over all of its traces.
+type IterableBlock interface {
+	BackendBlock
+
+	Iterator(ctx context.Context) (Iterator, error)
+}
+
 type WALBlock interface {
 	BackendBlock
 
@@ -0,0 +1,136 @@
+package common
+
+import (
+	"encoding/binary"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/cespare/xxhash/v2"
+	"github.com/parquet-go/parquet-go"
+	"github.com/parquet-go/parquet-go/compress"
+
+	"github.com/grafana/tempo/tempodb/backend"
+)
+
+// SupportedParquetCompressions are the encodings that parquet blocks can be compressed with. Parquet has no codec for
+// the framed lz4 of the v2 encodings, so lz4 (EncLZ4_4M) compresses the columns with the LZ4_RAW codec. The encoding in
+// the block meta only names the compression; readers use the codec stored in the parquet file.
+var SupportedParquetCompressions = []backend.Encoding{
+	backend.EncNone,
+	backend.EncSnappy,
+	backend.EncGZIP,
+	backend.EncLZ4_4M,
+	backend.EncZstd,
+}
+
+// ParquetCompression returns the encoding parquet blocks are compressed with. An empty compression is the snappy
+// compression defined by the block schemas.
+func ParquetCompression(compression string) (backend.Encoding, error) {
+	if compression == "" {
+		return backend.EncSnappy, nil
+	}
+
+	enc, err := backend.ParseEncoding(compression)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, e := range SupportedParquetCompressions {
+		if e == enc {
+			return enc, nil
+		}
+	}
+	return 0, fmt.Errorf("unsupported parquet compression %s, supported: %v", compression, SupportedParquetCompressions)
+}
+
+// parquetCodec returns the parquet codec of a supported compression. See SupportedParquetCompressions for lz4.
+func parquetCodec(enc backend.Encoding) compress.Codec {
+	switch enc {
+	case backend.EncNone:
+		return &parquet.Uncompressed
+	case backend.EncGZIP:
+		return &parquet.Gzip
+	case backend.EncLZ4_4M:
+		return &parquet.Lz4Raw
+	case backend.EncZstd:
+		return &parquet.Zstd
+	default:
+		return &parquet.Snappy
+	}
+}
+
+type schemaKey struct {
+	schema *parquet.Schema
+	enc    backend.Encoding
+}
+
+var compressedSchemas sync.Map // schemaKey -> *parquet.Schema
+
+// ParquetSchemaWithCompression returns the schema with all its columns compressed with the given encoding. The
+// column order of the schema is preserved so blocks remain readable regardless of their compression.
+func ParquetSchemaWithCompression(schema *parquet.Schema, enc backend.Encoding) *parquet.Schema {
+	if enc == backend.EncSnappy {
+		return schema
+	}
+
+	key := schemaKey{schema: schema, enc: enc}
+	if s, ok := compressedSchemas.Load(key); ok {
+		return s.(*parquet.Schema)
+	}
+
+	s := parquet.NewSchema(schema.Name(), withCompression(schema, parquetCodec(enc)))
+	compressedSchemas.Store(key, s)
+	return s
+}
+
+func withCompression(node parquet.Node, codec compress.Codec) parquet.Node {
+	if node.Leaf() {
+		return parquet.Compressed(node, codec)
+	}
+
+	fields := node.Fields()
+	compressed := make([]parquet.Field, 0, len(fields))
+	for _, f := range fields {
+		compressed = append(compressed, &compressedField{
+			Node:  withCompression(f, codec),
+			field: f,
+		})
+	}
+	return &compressedGroup{Node: node, fields: compressed}
+}
+
+// compressedGroup is a group node whose fields are compressed with a different codec
+type compressedGroup struct {
+	parquet.Node
+	fields []parquet.Field
+}
+
+func (g *compressedGroup) Fields() []parquet.Field { return g.fields }
+
+// compressedField is a field of a compressedGroup
+type compressedField struct {
+	parquet.Node
+	field parquet.Field
+}
+
+func (f *compressedField) Name() string { return f.field.Name() }
+
+func (f *compressedField) Value(base reflect.Value) reflect.Value { return f.field.Value(base) }
+
+// RowHash returns a hash of the values of a parquet row. Rows with the same values have the same hash.
+func RowHash(row parquet.Row) uint64 {
+	d := xxhash.New()
+	var buf []byte
+	for _, v := range row {
+		buf = binary.BigEndian.AppendUint32(buf[:0], uint32(v.Column()))
+		buf = append(buf, byte(v.Kind()), byte(v.RepetitionLevel()), byte(v.DefinitionLevel()))
+		if !v.IsNull() {
+			b := v.Bytes()
+			buf = binary.BigEndian.AppendUint32(buf, uint32(len(b)))
+			buf = append(buf, b...)
+		}
+		_, _ = d.Write(buf)
+	}
+	return d.Sum64()
+}
@@ -0,0 +1,47 @@
+package common
+
+import (
+	"time"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/tempo/pkg/tempopb"
+	v1_common "github.com/grafana/tempo/pkg/tempopb/common/v1"
+)
+
+// RetentionAttribute is the reserved resource or span attribute a trace uses to request a longer retention. The value
+// is a duration like 30d or 72h.
+const RetentionAttribute = "tempo.retention"
+
+// TraceRetention returns the longest retention requested by the resources or spans of the trace with the
+// RetentionAttribute. It returns 0 if no retention is requested. Invalid values are ignored.
+func TraceRetention(tr *tempopb.Trace) time.Duration {
+	var retention time.Duration
+
+	for _, rs := range tr.Batches {
+		if rs.Resource != nil {
+			retention = max(retention, retentionFromAttributes(rs.Resource.Attributes))
+		}
+		for _, ss := range rs.ScopeSpans {
+			for _, s := range ss.Spans {
+				retention = max(retention, retentionFromAttributes(s.Attributes))
+			}
+		}
+	}
+
+	return retention
+}
+
+func retentionFromAttributes(attrs []*v1_common.KeyValue) time.Duration {
+	for _, a := range attrs {
+		if a.Key != RetentionAttribute || a.Value == nil {
+			continue
+		}
+		d, err := model.ParseDuration(a.Value.GetStringValue())
+		if err != nil {
+			return 0
+		}
+		return time.Duration(d)
+	}
+	return 0
+}
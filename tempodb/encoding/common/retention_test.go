@@ -0,0 +1,71 @@
+package common
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/pkg/tempopb"
+	v1_common "github.com/grafana/tempo/pkg/tempopb/common/v1"
+	v1_resource "github.com/grafana/tempo/pkg/tempopb/resource/v1"
+	v1_trace "github.com/grafana/tempo/pkg/tempopb/trace/v1"
+)
+
+func TestTraceRetention(t *testing.T) {
+	attr := func(v string) []*v1_common.KeyValue {
+		return []*v1_common.KeyValue{
+			{Key: "foo", Value: &v1_common.AnyValue{Value: &v1_common.AnyValue_StringValue{StringValue: "bar"}}},
+			{Key: RetentionAttribute, Value: &v1_common.AnyValue{Value: &v1_common.AnyValue_StringValue{StringValue: v}}},
+		}
+	}
+
+	tcs := []struct {
+		name     string
+		resource []*v1_common.KeyValue
+		span     []*v1_common.KeyValue
+		expected time.Duration
+	}{
+		{
+			name:     "none",
+			expected: 0,
+		},
+		{
+			name:     "resource",
+			resource: attr("30d"),
+			expected: 30 * 24 * time.Hour,
+		},
+		{
+			name:     "span",
+			span:     attr("72h"),
+			expected: 72 * time.Hour,
+		},
+		{
+			name:     "longest wins",
+			resource: attr("72h"),
+			span:     attr("1w"),
+			expected: 7 * 24 * time.Hour,
+		},
+		{
+			name:     "invalid",
+			span:     attr("forever"),
+			expected: 0,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			tr := &tempopb.Trace{
+				Batches: []*v1_trace.ResourceSpans{
+					{
+						Resource: &v1_resource.Resource{Attributes: tc.resource},
+						ScopeSpans: []*v1_trace.ScopeSpans{
+							{Spans: []*v1_trace.Span{{Attributes: tc.span}, {}}},
+						},
+					},
+				},
+			}
+			require.Equal(t, tc.expected, TraceRetention(tr))
+		})
+	}
+}
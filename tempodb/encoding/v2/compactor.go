@@ -87,6 +87,10 @@ func (c *Compactor) Compact(ctx context.Context, l log.Logger, r backend.Reader,
 			return nil, fmt.Errorf("error iterating input blocks: %w", err)
 		}
 
+		if c.opts.DropObject != nil && c.opts.DropObject(id) {
+			continue
+		}
+
 		// make a new block if necessary
 		if currentBlock == nil {
 			currentBlock, err = NewStreamingBlock(&c.opts.BlockConfig, uuid.New(), tenantID, inputs, recordsPerBlock)
@@ -133,7 +133,7 @@ func (r *indexReader) getPage(ctx context.Context, pageIdx int) (*page, error) {
 	h := xxhash.New()
 	_, _ = h.Write(page.data)
 	if page.header.(*indexHeader).checksum != h.Sum64() {
-		return nil, fmt.Errorf("mismatched checksum: %d", pageIdx)
+		return nil, fmt.Errorf("mismatched checksum: %d: %w", pageIdx, common.ErrCorrupted)
 	}
 
 	r.pageCache[pageIdx] = page
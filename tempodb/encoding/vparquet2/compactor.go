@@ -143,6 +143,11 @@ func (c *Compactor) Compact(ctx context.Context, l log.Logger, r backend.Reader,
 			return nil, fmt.Errorf("error iterating input blocks: %w", err)
 		}
 
+		if c.opts.DropObject != nil && c.opts.DropObject(lowestID) {
+			pool.Put(lowestObject)
+			continue
+		}
+
 		// make a new block if necessary
 		if currentBlock == nil {
 			// Start with a copy and then customize
@@ -107,7 +107,10 @@ type streamingBlock struct {
 }
 
 func newStreamingBlock(ctx context.Context, cfg *common.BlockConfig, meta *backend.BlockMeta, r backend.Reader, to backend.Writer, createBufferedWriter func(w io.Writer) tempo_io.BufferedWriteFlusher) *streamingBlock {
-	newMeta := backend.NewBlockMeta(meta.TenantID, meta.BlockID, VersionString, backend.EncNone, "")
+	// the compression is validated with the block config
+	compression, _ := common.ParquetCompression(cfg.ParquetCompression)
+
+	newMeta := backend.NewBlockMeta(meta.TenantID, meta.BlockID, VersionString, compression, "")
 	newMeta.StartTime = meta.StartTime
 	newMeta.EndTime = meta.EndTime
 
@@ -117,7 +120,7 @@ func newStreamingBlock(ctx context.Context, cfg *common.BlockConfig, meta *backe
 
 	w := &backendWriter{ctx, to, DataFileName, meta.BlockID, meta.TenantID, nil}
 	bw := createBufferedWriter(w)
-	pw := parquet.NewGenericWriter[*Trace](bw)
+	pw := parquet.NewGenericWriter[*Trace](bw, common.ParquetSchemaWithCompression(parquet.SchemaOf(&Trace{}), compression))
 
 	return &streamingBlock{
 		ctx:   ctx,
@@ -157,6 +160,7 @@ func (b *streamingBlock) AddRaw(id []byte, row parquet.Row, start, end uint32) e
 	b.index.Add(id)
 	b.bloom.Add(id)
 	b.meta.ObjectAdded(id, start, end)
+	b.meta.FingerprintAdded(id, common.RowHash(row))
 	b.currentBufferedTraces++
 	b.currentBufferedBytes += estimateMarshalledSizeFromParquetRow(row)
 
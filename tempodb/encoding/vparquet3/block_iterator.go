@@ -10,6 +10,8 @@ import (
 
 	tempo_io "github.com/grafana/tempo/pkg/io"
 	"github.com/grafana/tempo/pkg/parquetquery"
+	"github.com/grafana/tempo/pkg/tempopb"
+	"github.com/grafana/tempo/tempodb/backend"
 	"github.com/grafana/tempo/tempodb/encoding/common"
 )
 
@@ -49,6 +51,50 @@ func (b *backendBlock) rawIter(ctx context.Context, pool *rowPool) (*rawIterator
 	return &rawIterator{b.meta.BlockID.String(), r, traceIDIndex, pool}, nil
 }
 
+var _ common.IterableBlock = (*backendBlock)(nil)
+
+// Iterator returns an iterator over all traces in the block in the order they are stored.
+func (b *backendBlock) Iterator(ctx context.Context) (common.Iterator, error) {
+	iter, err := b.rawIter(ctx, newRowPool(0))
+	if err != nil {
+		return nil, err
+	}
+
+	return &traceIterator{
+		meta:   b.meta,
+		iter:   iter,
+		schema: parquet.SchemaOf(new(Trace)),
+	}, nil
+}
+
+// traceIterator implements common.Iterator over the rows of a rawIterator
+type traceIterator struct {
+	meta   *backend.BlockMeta
+	iter   *rawIterator
+	schema *parquet.Schema
+}
+
+var _ common.Iterator = (*traceIterator)(nil)
+
+func (i *traceIterator) Next(ctx context.Context) (common.ID, *tempopb.Trace, error) {
+	id, row, err := i.iter.Next(ctx)
+	if err != nil || row == nil {
+		return nil, nil, err
+	}
+
+	t := &Trace{}
+	err = i.schema.Reconstruct(t, row)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return id, ParquetTraceToTempopbTrace(i.meta, t), nil
+}
+
+func (i *traceIterator) Close() {
+	i.iter.Close()
+}
+
 type rawIterator struct {
 	blockID      string
 	r            *parquet.Reader //nolint:all //deprecated
@@ -146,6 +146,11 @@ func (c *Compactor) Compact(ctx context.Context, l log.Logger, r backend.Reader,
 			return nil, fmt.Errorf("error iterating input blocks: %w", err)
 		}
 
+		if c.opts.DropObject != nil && c.opts.DropObject(lowestID) {
+			pool.Put(lowestObject)
+			continue
+		}
+
 		// make a new block if necessary
 		if currentBlock == nil {
 			// Start with a copy and then customize
@@ -156,6 +161,7 @@ func (c *Compactor) Compact(ctx context.Context, l log.Logger, r backend.Reader,
 				TotalObjects:      recordsPerBlock, // Just an estimate
 				ReplicationFactor: inputs[0].ReplicationFactor,
 				DedicatedColumns:  inputs[0].DedicatedColumns,
+				Retention:         inputs[0].Retention,
 			}
 
 			currentBlock = newStreamingBlock(ctx, &c.opts.BlockConfig, newMeta, r, w, tempo_io.NewBufferedWriter)
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"time"
 
 	"github.com/google/uuid"
 	tempo_io "github.com/grafana/tempo/pkg/io"
@@ -107,10 +108,16 @@ type streamingBlock struct {
 }
 
 func newStreamingBlock(ctx context.Context, cfg *common.BlockConfig, meta *backend.BlockMeta, r backend.Reader, to backend.Writer, createBufferedWriter func(w io.Writer) tempo_io.BufferedWriteFlusher) *streamingBlock {
-	newMeta := backend.NewBlockMetaWithDedicatedColumns(meta.TenantID, meta.BlockID, VersionString, backend.EncNone, "", meta.DedicatedColumns)
+	// the compression is validated with the block config
+	compression, _ := common.ParquetCompression(cfg.ParquetCompression)
+
+	newMeta := backend.NewBlockMetaWithDedicatedColumns(meta.TenantID, meta.BlockID, VersionString, compression, "", meta.DedicatedColumns)
 	newMeta.StartTime = meta.StartTime
 	newMeta.EndTime = meta.EndTime
 	newMeta.ReplicationFactor = meta.ReplicationFactor
+	newMeta.CompactionLevel = meta.CompactionLevel
+	newMeta.Downsampled = meta.Downsampled
+	newMeta.Retention = meta.Retention
 
 	// TotalObjects is used here an an estimated count for the bloom filter.
 	// The real number of objects is tracked below.
@@ -118,7 +125,7 @@ func newStreamingBlock(ctx context.Context, cfg *common.BlockConfig, meta *backe
 
 	w := &backendWriter{ctx, to, DataFileName, meta.BlockID, meta.TenantID, nil}
 	bw := createBufferedWriter(w)
-	pw := parquet.NewGenericWriter[*Trace](bw)
+	pw := parquet.NewGenericWriter[*Trace](bw, common.ParquetSchemaWithCompression(parquetSchema, compression))
 
 	return &streamingBlock{
 		ctx:   ctx,
@@ -143,6 +150,7 @@ func (b *streamingBlock) Add(tr *Trace, start, end uint32) error {
 	b.index.Add(id)
 	b.bloom.Add(id)
 	b.meta.ObjectAdded(id, start, end)
+	b.meta.DurationAdded(time.Duration(tr.DurationNano))
 	b.currentBufferedTraces++
 	b.currentBufferedBytes += estimateMarshalledSizeFromTrace(tr)
 
@@ -158,12 +166,33 @@ func (b *streamingBlock) AddRaw(id []byte, row parquet.Row, start, end uint32) e
 	b.index.Add(id)
 	b.bloom.Add(id)
 	b.meta.ObjectAdded(id, start, end)
+	b.meta.FingerprintAdded(id, common.RowHash(row))
+	if d, ok := traceDurationFromRow(row); ok {
+		b.meta.DurationAdded(d)
+	} else {
+		b.meta.DurationUnknown()
+	}
 	b.currentBufferedTraces++
 	b.currentBufferedBytes += estimateMarshalledSizeFromParquetRow(row)
 
 	return nil
 }
 
+var durationNanoColumn, _ = parquetSchema.Lookup(columnPathDurationNanos)
+
+// traceDurationFromRow returns the duration of the trace in the row
+func traceDurationFromRow(row parquet.Row) (time.Duration, bool) {
+	for _, v := range row {
+		if v.Column() == durationNanoColumn.ColumnIndex {
+			return time.Duration(v.Uint64()), true
+		}
+		if v.Column() > durationNanoColumn.ColumnIndex {
+			break
+		}
+	}
+	return 0, false
+}
+
 func (b *streamingBlock) EstimatedBufferedBytes() int {
 	return b.currentBufferedBytes
 }
@@ -2,6 +2,7 @@ package vparquet4
 
 import (
 	"context"
+	"errors"
 	"io"
 	"testing"
 	"time"
@@ -12,6 +13,8 @@ import (
 	"github.com/grafana/tempo/tempodb/backend"
 	"github.com/grafana/tempo/tempodb/backend/local"
 	"github.com/grafana/tempo/tempodb/encoding/common"
+	"github.com/parquet-go/parquet-go"
+	"github.com/parquet-go/parquet-go/format"
 	"github.com/stretchr/testify/require"
 )
 
@@ -48,6 +51,117 @@ func TestCreateBlockHonorsTraceStartEndTimesFromWalMeta(t *testing.T) {
 	require.Equal(t, 305, int(outMeta.EndTime.Unix()))
 }
 
+func TestCreateBlockDurationHistogram(t *testing.T) {
+	ctx := context.Background()
+
+	rawR, rawW, _, err := local.New(&local.Config{
+		Path: t.TempDir(),
+	})
+	require.NoError(t, err)
+
+	r := backend.NewReader(rawR)
+	w := backend.NewWriter(rawW)
+
+	iter := newTestIterator()
+	for _, d := range []time.Duration{500 * time.Microsecond, 3 * time.Millisecond, time.Second} {
+		tr := test.MakeTrace(2, nil)
+		for _, b := range tr.Batches {
+			for _, ss := range b.ScopeSpans {
+				for _, s := range ss.Spans {
+					s.StartTimeUnixNano = uint64(time.Second)
+					s.EndTimeUnixNano = uint64(time.Second + d)
+				}
+			}
+		}
+		iter.Add(tr, 0, 0)
+	}
+
+	cfg := &common.BlockConfig{
+		BloomFP:             0.01,
+		BloomShardSizeBytes: 100 * 1024,
+	}
+
+	meta := backend.NewBlockMeta("fake", uuid.New(), VersionString, backend.EncNone, "")
+	meta.TotalObjects = 1
+
+	outMeta, err := CreateBlock(ctx, cfg, meta, iter, r, w)
+	require.NoError(t, err)
+
+	expected := &backend.BlockMeta{}
+	expected.DurationAdded(500 * time.Microsecond)
+	expected.DurationAdded(3 * time.Millisecond)
+	expected.DurationAdded(time.Second)
+	require.Equal(t, expected.DurationHistogram, outMeta.DurationHistogram)
+	require.False(t, outMeta.MayContainDuration(5*time.Millisecond, 500*time.Millisecond))
+	require.True(t, outMeta.MayContainDuration(500*time.Millisecond, 0))
+}
+
+func TestCreateBlockCompression(t *testing.T) {
+	tcs := []struct {
+		compression string
+		expected    backend.Encoding
+		codec       format.CompressionCodec
+	}{
+		{compression: "", expected: backend.EncSnappy, codec: format.Snappy},
+		{compression: "none", expected: backend.EncNone, codec: format.Uncompressed},
+		{compression: "gzip", expected: backend.EncGZIP, codec: format.Gzip},
+		{compression: "lz4", expected: backend.EncLZ4_4M, codec: format.Lz4Raw},
+		{compression: "zstd", expected: backend.EncZstd, codec: format.Zstd},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.expected.String(), func(t *testing.T) {
+			ctx := context.Background()
+
+			rawR, rawW, _, err := local.New(&local.Config{
+				Path: t.TempDir(),
+			})
+			require.NoError(t, err)
+
+			r := backend.NewReader(rawR)
+			w := backend.NewWriter(rawW)
+
+			iter := newTestIterator()
+			for i := 0; i < 3; i++ {
+				iter.Add(test.MakeTrace(5, nil), 0, 0)
+			}
+
+			cfg := &common.BlockConfig{
+				BloomFP:             0.01,
+				BloomShardSizeBytes: 100 * 1024,
+				ParquetCompression:  tc.compression,
+			}
+
+			meta := backend.NewBlockMeta("fake", uuid.New(), VersionString, backend.EncNone, "")
+			meta.TotalObjects = 3
+
+			outMeta, err := CreateBlock(ctx, cfg, meta, iter, r, w)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, outMeta.Encoding)
+
+			pf, _, err := newBackendBlock(outMeta, r).openForSearch(ctx, common.DefaultSearchOptions())
+			require.NoError(t, err)
+			require.Equal(t, int64(3), pf.NumRows())
+			for _, rg := range pf.Metadata().RowGroups {
+				for _, c := range rg.Columns {
+					if tc.expected == backend.EncSnappy && c.MetaData.Codec == format.Uncompressed {
+						continue // the schema leaves some columns uncompressed
+					}
+					require.Equal(t, tc.codec, c.MetaData.Codec, c.MetaData.PathInSchema)
+				}
+			}
+
+			tr := make([]*Trace, 3)
+			n, err := parquet.NewGenericReader[*Trace](pf).Read(tr)
+			if !errors.Is(err, io.EOF) {
+				require.NoError(t, err)
+			}
+			require.Equal(t, 3, n)
+			require.Len(t, tr[0].ResourceSpans, 5)
+		})
+	}
+}
+
 // func TestEstimateTraceSize(t *testing.T) {
 // 	f := "<put data.parquet file here>"
 // 	file, err := os.OpenFile(f, os.O_RDONLY, 0644)
@@ -0,0 +1,125 @@
+package vparquet4
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/parquet-go/parquet-go"
+
+	"github.com/grafana/tempo/pkg/cache"
+	pq "github.com/grafana/tempo/pkg/parquetquery"
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/encoding/common"
+)
+
+// RebuildIndex rebuilds the index of the block from the trace IDs of its data object and writes it to the backend.
+// It restores a block whose index was lost or corrupted.
+func RebuildIndex(ctx context.Context, meta *backend.BlockMeta, r backend.Reader, w backend.Writer) error {
+	idx := &index{}
+	err := forEachTraceID(ctx, meta, r, func(id common.ID) {
+		idx.Add(id)
+	}, idx.Flush)
+	if err != nil {
+		return err
+	}
+
+	b, err := idx.Marshal()
+	if err != nil {
+		return err
+	}
+	return w.Write(ctx, common.NameIndex, meta.BlockID, meta.TenantID, b, &backend.CacheInfo{
+		Meta: meta,
+		Role: cache.RoleTraceIDIdx,
+	})
+}
+
+// RebuildBloom rebuilds the bloom filter of the block from the trace IDs of its data object and writes it to the
+// backend. The bloom filter must be empty and have the number of shards of the meta.
+func RebuildBloom(ctx context.Context, meta *backend.BlockMeta, bloom *common.ShardedBloomFilter, r backend.Reader, w backend.Writer) error {
+	if bloom.GetShardCount() != int(meta.BloomShardCount) {
+		return fmt.Errorf("bloom filter has %d shards but the block has %d shards", bloom.GetShardCount(), meta.BloomShardCount)
+	}
+
+	err := forEachTraceID(ctx, meta, r, func(id common.ID) {
+		bloom.Add(id)
+	}, func() {})
+	if err != nil {
+		return err
+	}
+
+	blooms, err := bloom.Marshal()
+	if err != nil {
+		return err
+	}
+
+	cacheInfo := &backend.CacheInfo{
+		Meta: meta,
+		Role: cache.RoleBloom,
+	}
+	for i, b := range blooms {
+		err := w.Write(ctx, common.BloomName(i), meta.BlockID, meta.TenantID, b, cacheInfo)
+		if err != nil {
+			return fmt.Errorf("unexpected error writing bloom-%d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// forEachTraceID calls fn with every trace ID of the data object of the block in order, and rowGroupDone at the end
+// of each row group
+func forEachTraceID(ctx context.Context, meta *backend.BlockMeta, r backend.Reader, fn func(id common.ID), rowGroupDone func()) error {
+	pf, _, err := newBackendBlock(meta, r).openForSearch(ctx, common.DefaultSearchOptions())
+	if err != nil {
+		return fmt.Errorf("unexpected error opening parquet file: %w", err)
+	}
+
+	colIndex, _ := pq.GetColumnIndexByPath(pf, TraceIDColumnName)
+	if colIndex == -1 {
+		return fmt.Errorf("unable to get index for column: %s", TraceIDColumnName)
+	}
+
+	buf := make([]parquet.Value, 1000)
+	for _, rg := range pf.RowGroups() {
+		err := forEachValue(rg.ColumnChunks()[colIndex].Pages(), buf, func(v parquet.Value) {
+			// Clone ensures that the byte array is disconnected from the underlying i/o buffers.
+			fn(v.Clone().ByteArray())
+		})
+		if err != nil {
+			return fmt.Errorf("error reading trace IDs: %w", err)
+		}
+		rowGroupDone()
+	}
+	return nil
+}
+
+func forEachValue(pages parquet.Pages, buf []parquet.Value, fn func(v parquet.Value)) error {
+	defer pages.Close()
+
+	for {
+		page, err := pages.ReadPage()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		values := page.Values()
+		for {
+			n, err := values.ReadValues(buf)
+			for _, v := range buf[:n] {
+				fn(v)
+			}
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				parquet.Release(page)
+				return err
+			}
+		}
+		parquet.Release(page)
+	}
+}
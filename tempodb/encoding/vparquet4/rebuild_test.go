@@ -0,0 +1,84 @@
+package vparquet4
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	tempo_io "github.com/grafana/tempo/pkg/io"
+	"github.com/grafana/tempo/pkg/util/test"
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/backend/local"
+	"github.com/grafana/tempo/tempodb/encoding/common"
+)
+
+func TestRebuildIndexAndBloom(t *testing.T) {
+	dir := t.TempDir()
+	rawR, rawW, _, err := local.New(&local.Config{
+		Path: dir,
+	})
+	require.NoError(t, err)
+
+	r := backend.NewReader(rawR)
+	w := backend.NewWriter(rawW)
+	ctx := context.Background()
+
+	cfg := &common.BlockConfig{
+		BloomFP:             0.01,
+		BloomShardSizeBytes: 16,
+	}
+
+	var traces []*Trace
+	for i := 0; i < 50; i++ {
+		traces = append(traces, &Trace{TraceID: test.ValidTraceID(nil)})
+	}
+	sort.Slice(traces, func(i, j int) bool {
+		return bytes.Compare(traces[i].TraceID, traces[j].TraceID) == -1
+	})
+
+	meta := backend.NewBlockMeta("fake", uuid.New(), VersionString, backend.EncNone, "")
+	meta.TotalObjects = len(traces)
+	s := newStreamingBlock(ctx, cfg, meta, r, w, tempo_io.NewBufferedWriter)
+	for _, tr := range traces {
+		require.NoError(t, s.Add(tr, 0, 0))
+		if s.CurrentBufferedObjects() >= 7 {
+			_, err = s.Flush()
+			require.NoError(t, err)
+		}
+	}
+	_, err = s.Complete()
+	require.NoError(t, err)
+	require.Greater(t, int(s.meta.BloomShardCount), 1)
+
+	names := []string{common.NameIndex}
+	for i := 0; i < int(s.meta.BloomShardCount); i++ {
+		names = append(names, common.BloomName(i))
+	}
+
+	// lose the index and the bloom
+	want := map[string][]byte{}
+	for _, name := range names {
+		want[name], err = r.Read(ctx, name, s.meta.BlockID, s.meta.TenantID, nil)
+		require.NoError(t, err)
+		require.NoError(t, os.Remove(filepath.Join(dir, s.meta.TenantID, s.meta.BlockID.String(), name)))
+	}
+
+	require.NoError(t, RebuildIndex(ctx, s.meta, r, w))
+
+	// the bloom must have the shards of the meta
+	err = RebuildBloom(ctx, s.meta, common.NewBloom(cfg.BloomFP, uint(cfg.BloomShardSizeBytes)*10, uint(len(traces))), r, w)
+	require.EqualError(t, err, "bloom filter has 1 shards but the block has 4 shards")
+	require.NoError(t, RebuildBloom(ctx, s.meta, common.NewBloom(cfg.BloomFP, uint(cfg.BloomShardSizeBytes), uint(len(traces))), r, w))
+
+	for _, name := range names {
+		got, err := r.Read(ctx, name, s.meta.BlockID, s.meta.TenantID, nil)
+		require.NoError(t, err)
+		require.Equal(t, want[name], got, name)
+	}
+}
@@ -157,6 +157,7 @@ func createWALBlock(meta *backend.BlockMeta, filepath, dataEncoding string, inge
 			TenantID:          meta.TenantID,
 			DedicatedColumns:  meta.DedicatedColumns,
 			ReplicationFactor: meta.ReplicationFactor,
+			Retention:         meta.Retention,
 		},
 		path:           filepath,
 		ids:            common.NewIDMap[int64](),
@@ -0,0 +1,180 @@
+package tempodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-kit/log/level"
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/encoding"
+	"github.com/grafana/tempo/tempodb/encoding/common"
+)
+
+var (
+	metricMigratedBlocks = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "tempodb",
+		Name:      "migrate_blocks_total",
+		Help:      "Total number of blocks rewritten into the configured block version.",
+	})
+	metricMigrateErrors = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "tempodb",
+		Name:      "migrate_errors_total",
+		Help:      "Total number of times an error occurred while migrating blocks.",
+	})
+)
+
+// MigrateConfig controls the rewriting of blocks that are not in the version of the block config. The new blocks are
+// written like compacted blocks. This upgrades the existing blocks after the block version is changed instead of
+// waiting for them to be compacted or to expire.
+type MigrateConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// MaxBlocksPerCycle is the maximum number of blocks migrated per tenant in a retention cycle. 0 is unlimited
+	MaxBlocksPerCycle int `yaml:"max_blocks_per_cycle"`
+}
+
+// migrateTenant rewrites the owned blocks of the tenant that are not in the configured block version. the original
+// blocks are marked compacted once the new block is verified.
+func (rw *readerWriter) migrateTenant(ctx context.Context, tenantID string) {
+	cfg := rw.compactorCfg.Migrate
+	if !cfg.Enabled {
+		return
+	}
+
+	blockCfg := rw.compactorBlockConfig()
+	migrated := 0
+
+	for _, b := range rw.blocklist.Metas(tenantID) {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+
+		if cfg.MaxBlocksPerCycle > 0 && migrated >= cfg.MaxBlocksPerCycle {
+			return
+		}
+
+		if b.Version == blockCfg.Version || rw.isQuarantined(ctx, tenantID, b.BlockID) || !rw.compactorSharder.Owns(b.BlockID.String()) {
+			continue
+		}
+
+		if !rw.claimBlockForRewrite(ctx, tenantID, b) {
+			continue
+		}
+
+		start := time.Now()
+		newMeta, err := MigrateBlock(ctx, b, &blockCfg, tenantID, rw.r, rw.r, rw.w, rw.c)
+		if err == nil {
+			err = markCompacted(rw, tenantID, []*backend.BlockMeta{b}, []*backend.BlockMeta{newMeta})
+		}
+		rw.releaseBlocks([]*backend.BlockMeta{b})
+		if errors.Is(err, errRewriteUnsupported) {
+			level.Debug(rw.logger).Log("msg", "skipping migration of block", "blockID", b.BlockID, "tenantID", tenantID, "version", b.Version)
+			continue
+		}
+		if err != nil {
+			rw.quarantineIfCorrupted(ctx, b, err)
+			level.Error(rw.logger).Log("msg", "failed to migrate block", "blockID", b.BlockID, "tenantID", tenantID, "err", err)
+			metricMigrateErrors.Inc()
+			continue
+		}
+
+		migrated++
+		metricMigratedBlocks.Inc()
+		level.Info(rw.logger).Log("msg", "migrated block", "blockID", b.BlockID, "newBlockID", newMeta.BlockID, "tenantID", tenantID,
+			"fromVersion", b.Version, "toVersion", newMeta.Version, "elapsed", time.Since(start))
+	}
+}
+
+// MigrateBlock rewrites the block read from r into a new block of tenantID created with cfg and written to w. The new
+// block keeps the time range, compaction level, dedicated columns and retention of the original block. It is verified
+// before it is returned: it must contain as many traces as the original block and its first and last trace must be
+// found. A new block that fails the verification is marked compacted with c. The original block is left unchanged.
+func MigrateBlock(ctx context.Context, meta *backend.BlockMeta, cfg *common.BlockConfig, tenantID string, r backend.Reader, toR backend.Reader, w backend.Writer, c backend.Compactor) (*backend.BlockMeta, error) {
+	enc, err := encoding.FromVersion(meta.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	toEnc, err := encoding.FromVersion(cfg.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := enc.OpenBlock(meta, r)
+	if err != nil {
+		return nil, err
+	}
+
+	iterable, ok := block.(common.IterableBlock)
+	if !ok {
+		return nil, errRewriteUnsupported
+	}
+
+	iter, err := iterable.Iterator(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer iter.Close()
+
+	newMeta := &backend.BlockMeta{
+		BlockID:           uuid.New(),
+		TenantID:          tenantID,
+		StartTime:         meta.StartTime,
+		EndTime:           meta.EndTime,
+		TotalObjects:      meta.TotalObjects, // estimate for the bloom filter
+		CompactionLevel:   meta.CompactionLevel,
+		DedicatedColumns:  meta.DedicatedColumns,
+		ReplicationFactor: meta.ReplicationFactor,
+		Downsampled:       meta.Downsampled,
+		Retention:         meta.Retention,
+	}
+
+	newMeta, err = toEnc.CreateBlock(ctx, cfg, newMeta, iter, toR, w)
+	if err != nil {
+		return nil, fmt.Errorf("error creating migrated block: %w", err)
+	}
+
+	if err := verifyMigratedBlock(ctx, newMeta, meta.TotalObjects, toEnc, toR); err != nil {
+		if clearErr := c.MarkBlockCompacted(newMeta.BlockID, tenantID); clearErr != nil {
+			err = errors.Join(err, fmt.Errorf("error marking migrated block %s compacted: %w", newMeta.BlockID, clearErr))
+		}
+		return nil, fmt.Errorf("error verifying migrated block: %w", err)
+	}
+
+	return newMeta, nil
+}
+
+func verifyMigratedBlock(ctx context.Context, meta *backend.BlockMeta, traces int, enc encoding.VersionedEncoding, r backend.Reader) error {
+	if meta.TotalObjects != traces {
+		return fmt.Errorf("block contains %d traces, expected %d", meta.TotalObjects, traces)
+	}
+
+	block, err := enc.OpenBlock(meta, r)
+	if err != nil {
+		return err
+	}
+
+	for _, id := range []common.ID{meta.MinID, meta.MaxID} {
+		if len(id) == 0 {
+			continue
+		}
+
+		tr, err := block.FindTraceByID(ctx, id, common.DefaultSearchOptions())
+		if err != nil {
+			return fmt.Errorf("error finding trace %x: %w", id, err)
+		}
+		if tr == nil {
+			return fmt.Errorf("trace %x not found", id)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,178 @@
+package tempodb
+
+import (
+	"context"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/pkg/util/test"
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/backend/local"
+	"github.com/grafana/tempo/tempodb/encoding"
+	"github.com/grafana/tempo/tempodb/encoding/common"
+	"github.com/grafana/tempo/tempodb/encoding/vparquet3"
+	"github.com/grafana/tempo/tempodb/encoding/vparquet4"
+	"github.com/grafana/tempo/tempodb/wal"
+)
+
+func TestMigrate(t *testing.T) {
+	tempDir := t.TempDir()
+
+	r, w, c, err := New(&Config{
+		Backend: backend.Local,
+		Local: &local.Config{
+			Path: path.Join(tempDir, "traces"),
+		},
+		Block: &common.BlockConfig{
+			IndexDownsampleBytes: 17,
+			BloomFP:              0.01,
+			BloomShardSizeBytes:  100_000,
+			Version:              vparquet3.VersionString,
+			Encoding:             backend.EncLZ4_256k,
+			IndexPageSizeBytes:   1000,
+			RowGroupSizeBytes:    30_000_000,
+		},
+		WAL: &wal.Config{
+			Filepath: path.Join(tempDir, "wal"),
+		},
+		BlocklistPoll: 0,
+	}, nil, log.NewNopLogger())
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	err = c.EnableCompaction(ctx, &CompactorConfig{
+		ChunkSizeBytes:          10,
+		MaxCompactionRange:      time.Hour,
+		BlockRetention:          24 * time.Hour,
+		CompactedBlockRetention: time.Hour,
+		ParquetCompression:      backend.EncZstd.String(),
+		Migrate: MigrateConfig{
+			Enabled:           true,
+			MaxBlocksPerCycle: 1,
+		},
+	}, &mockSharder{}, &mockOverrides{})
+	require.NoError(t, err)
+
+	r.EnablePolling(ctx, &mockJobSharder{})
+	rw := r.(*readerWriter)
+
+	now := uint32(time.Now().Unix())
+	var ids []common.ID
+	var originals []*backend.BlockMeta
+	for i := 0; i < 2; i++ {
+		data := make([]testData, 0, 3)
+		for j := 0; j < 3; j++ {
+			id := test.ValidTraceID(nil)
+			ids = append(ids, id)
+			data = append(data, testData{id: id, t: test.MakeTrace(2, id), start: now, end: now})
+		}
+		originals = append(originals, cutTestBlockWithTraces(t, w, testTenantID, data).BlockMeta())
+	}
+	checkBlocklists(t, uuid.Nil, 2, 0, rw)
+
+	// blocks in the configured version are not migrated
+	rw.doMaintenance(ctx, rw.migrateTenant)
+	checkBlocklists(t, uuid.Nil, 2, 0, rw)
+
+	// one block is migrated per cycle
+	rw.cfg.Block.Version = vparquet4.VersionString
+	rw.doMaintenance(ctx, rw.migrateTenant)
+	checkBlocklists(t, uuid.Nil, 2, 1, rw)
+
+	rw.doMaintenance(ctx, rw.migrateTenant)
+	checkBlocklists(t, uuid.Nil, 2, 2, rw)
+
+	metas := rw.blocklist.Metas(testTenantID)
+	for _, m := range metas {
+		require.Equal(t, vparquet4.VersionString, m.Version)
+		require.Equal(t, backend.EncZstd, m.Encoding)
+		require.Equal(t, 3, m.TotalObjects)
+		require.NotContains(t, []uuid.UUID{originals[0].BlockID, originals[1].BlockID}, m.BlockID)
+	}
+
+	for _, id := range ids {
+		found := false
+		for _, m := range metas {
+			block, err := encoding.OpenBlock(m, rw.r)
+			require.NoError(t, err)
+
+			tr, err := block.FindTraceByID(ctx, id, common.DefaultSearchOptions())
+			require.NoError(t, err)
+			found = found || tr != nil
+		}
+		require.True(t, found, "trace %x not found", id)
+	}
+}
+
+func TestMigrateSkipsClaimedBlocks(t *testing.T) {
+	tempDir := t.TempDir()
+
+	r, w, c, err := New(&Config{
+		Backend: backend.Local,
+		Local: &local.Config{
+			Path: path.Join(tempDir, "traces"),
+		},
+		Block: &common.BlockConfig{
+			IndexDownsampleBytes: 17,
+			BloomFP:              0.01,
+			BloomShardSizeBytes:  100_000,
+			Version:              vparquet3.VersionString,
+			Encoding:             backend.EncLZ4_256k,
+			IndexPageSizeBytes:   1000,
+			RowGroupSizeBytes:    30_000_000,
+		},
+		WAL: &wal.Config{
+			Filepath: path.Join(tempDir, "wal"),
+		},
+		BlocklistPoll: 0,
+	}, nil, log.NewNopLogger())
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	err = c.EnableCompaction(ctx, &CompactorConfig{
+		ChunkSizeBytes:          10,
+		MaxCompactionRange:      time.Hour,
+		MaxCompactionObjects:    1000,
+		MaxBlockBytes:           1024 * 1024 * 1024,
+		BlockRetention:          24 * time.Hour,
+		CompactedBlockRetention: time.Hour,
+		Migrate:                 MigrateConfig{Enabled: true},
+	}, &mockSharder{}, &mockOverrides{})
+	require.NoError(t, err)
+
+	r.EnablePolling(ctx, &mockJobSharder{})
+	rw := r.(*readerWriter)
+
+	now := uint32(time.Now().Unix())
+	var metas []*backend.BlockMeta
+	for i := 0; i < 2; i++ {
+		id := test.ValidTraceID(nil)
+		metas = append(metas, cutTestBlockWithTraces(t, w, testTenantID, []testData{{id: id, t: test.MakeTrace(2, id), start: now, end: now}}).BlockMeta())
+	}
+	checkBlocklists(t, uuid.Nil, 2, 0, rw)
+
+	// the blocks claimed by a compaction are not selected again or migrated
+	require.True(t, rw.claimBlocks(metas))
+	require.False(t, rw.claimBlocks(metas[:1]))
+	toBeCompacted, _ := rw.blockSelector(testTenantID, false).BlocksToCompact()
+	require.Empty(t, toBeCompacted)
+
+	rw.cfg.Block.Version = vparquet4.VersionString
+	rw.doMaintenance(ctx, rw.migrateTenant)
+	checkBlocklists(t, uuid.Nil, 2, 0, rw)
+
+	// once the compaction is done the blocks are migrated
+	rw.releaseBlocks(metas)
+	rw.doMaintenance(ctx, rw.migrateTenant)
+	checkBlocklists(t, uuid.Nil, 2, 2, rw)
+	require.Empty(t, rw.claimedBlocks)
+
+	// blocks compacted since the blocklist was polled are not claimed for a rewrite
+	require.False(t, rw.claimBlockForRewrite(ctx, testTenantID, metas[0]))
+	require.Empty(t, rw.claimedBlocks)
+}
@@ -44,6 +44,7 @@ type job struct {
 	wg        *sync.WaitGroup
 	resultsCh chan result
 	stop      *atomic.Bool
+	sem       chan struct{} // released when the job is done if the number of concurrent jobs is limited
 }
 
 type Pool struct {
@@ -79,14 +80,27 @@ func NewPool(cfg *Config) *Pool {
 }
 
 func (p *Pool) RunJobs(ctx context.Context, payloads []interface{}, fn JobFunc) ([]interface{}, []error, error) {
+	return p.RunJobsWithConcurrency(ctx, payloads, 0, fn)
+}
+
+// RunJobsWithConcurrency runs the jobs like RunJobs, but at most concurrency of them are queued or running at the
+// same time. This keeps a single call with many jobs from filling the queue and starving other calls. 0 is unlimited.
+func (p *Pool) RunJobsWithConcurrency(ctx context.Context, payloads []interface{}, concurrency int, fn JobFunc) ([]interface{}, []error, error) {
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
 	totalJobs := len(payloads)
+	queuedJobs := totalJobs
+
+	var sem chan struct{}
+	if concurrency > 0 && concurrency < totalJobs {
+		queuedJobs = concurrency
+		sem = make(chan struct{}, concurrency)
+	}
 
 	// sanity check before we even attempt to start adding jobs
-	if int(p.size.Load())+totalJobs > p.cfg.QueueDepth {
-		return nil, nil, fmt.Errorf("queue doesn't have room for %d jobs", len(payloads))
+	if int(p.size.Load())+queuedJobs > p.cfg.QueueDepth {
+		return nil, nil, fmt.Errorf("queue doesn't have room for %d jobs", queuedJobs)
 	}
 
 	resultsCh := make(chan result, totalJobs) // way for jobs to send back results
@@ -94,7 +108,17 @@ func (p *Pool) RunJobs(ctx context.Context, payloads []interface{}, fn JobFunc)
 	wg := &sync.WaitGroup{}                   // way to wait for all jobs to complete
 
 	// add each job one at a time.  even though we checked length above these might still fail
+addJobs:
 	for _, payload := range payloads {
+		// wait for a running job to finish if the concurrency is limited
+		if sem != nil {
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				break addJobs
+			}
+		}
+
 		wg.Add(1)
 		j := &job{
 			ctx:       ctx,
@@ -103,6 +127,7 @@ func (p *Pool) RunJobs(ctx context.Context, payloads []interface{}, fn JobFunc)
 			wg:        wg,
 			resultsCh: resultsCh,
 			stop:      stop,
+			sem:       sem,
 		}
 
 		select {
@@ -172,6 +197,9 @@ func (p *Pool) reportQueueLength() {
 
 func runJob(job *job) {
 	defer job.wg.Done()
+	if job.sem != nil {
+		defer func() { <-job.sem }()
+	}
 
 	// bail in case we have been asked to stop
 	if job.ctx.Err() != nil {
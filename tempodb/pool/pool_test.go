@@ -181,6 +181,46 @@ func TestTooManyJobs(t *testing.T) {
 	goleak.VerifyNone(t, prePoolOpts)
 }
 
+func TestConcurrencyLimit(t *testing.T) {
+	prePoolOpts := goleak.IgnoreCurrent()
+
+	p := NewPool(&Config{
+		MaxWorkers: 10,
+		QueueDepth: 3,
+	})
+	opts := goleak.IgnoreCurrent()
+
+	var (
+		mtx           sync.Mutex
+		running, peak int
+	)
+	fn := func(ctx context.Context, payload interface{}) (interface{}, error) {
+		mtx.Lock()
+		running++
+		peak = max(peak, running)
+		mtx.Unlock()
+
+		time.Sleep(10 * time.Millisecond)
+
+		mtx.Lock()
+		running--
+		mtx.Unlock()
+		return payload, nil
+	}
+	payloads := []interface{}{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+
+	// more jobs than the queue depth can run if the concurrency is limited
+	msg, funcErrs, err := p.RunJobsWithConcurrency(context.Background(), payloads, 2, fn)
+	require.NoError(t, err)
+	require.Nil(t, funcErrs)
+	require.ElementsMatch(t, payloads, msg)
+	require.LessOrEqual(t, peak, 2)
+	goleak.VerifyNone(t, opts)
+
+	p.Shutdown()
+	goleak.VerifyNone(t, prePoolOpts)
+}
+
 func TestOneWorker(t *testing.T) {
 	prePoolOpts := goleak.IgnoreCurrent()
 
@@ -0,0 +1,299 @@
+package tempodb
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-kit/log/level"
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"golang.org/x/exp/maps"
+
+	"github.com/grafana/tempo/pkg/traceql"
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/encoding"
+	"github.com/grafana/tempo/tempodb/encoding/common"
+)
+
+var metricBlockCorruption = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "tempo",
+	Name:      "block_corruption_total",
+	Help:      "Total number of blocks quarantined because a checksum mismatch was found when reading them.",
+}, []string{"tenant"})
+
+// ErrBlockQuarantined is returned by the reads of a quarantined block
+var ErrBlockQuarantined = errors.New("block is quarantined")
+
+const (
+	// quarantineDir holds an object for every quarantined block of the tenant
+	quarantineDir = "quarantine"
+
+	// how long the quarantined blocks read by the query path and the compactions are cached
+	quarantineCacheTTL = time.Minute
+)
+
+// Blocks are quarantined when a page of the block doesn't match the checksum written with it. Quarantined blocks are
+// skipped by trace lookups, searches and compactions, so the corrupted data is neither returned nor copied into
+// compacted blocks. Every quarantined block has an object under <tenant>/quarantine/ so the quarantine is shared by
+// all instances and kept across restarts. Releasing a block removes its object and the block is read again.
+
+// QuarantinedBlock records a block quarantined because of corrupted data
+type QuarantinedBlock struct {
+	BlockID       uuid.UUID `json:"blockID"`
+	QuarantinedAt time.Time `json:"quarantinedAt"`
+	Reason        string    `json:"reason"`
+}
+
+type cachedQuarantine struct {
+	blocks  map[uuid.UUID]*QuarantinedBlock
+	expires time.Time
+}
+
+// QuarantinedBlocks returns the quarantined blocks of the tenant
+func (rw *readerWriter) QuarantinedBlocks(ctx context.Context, tenantID string) ([]*QuarantinedBlock, error) {
+	blocks, err := rw.readQuarantinedBlocks(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	quarantined := maps.Values(blocks)
+	sort.Slice(quarantined, func(i, j int) bool {
+		return quarantined[i].QuarantinedAt.Before(quarantined[j].QuarantinedAt)
+	})
+	return quarantined, nil
+}
+
+// ReleaseQuarantinedBlock removes the block from the quarantine so it's read again. It's quarantined again if its
+// data is still corrupted. Other instances read the block again after up to a minute.
+func (rw *readerWriter) ReleaseQuarantinedBlock(ctx context.Context, tenantID string, blockID uuid.UUID) error {
+	name, keypath := blockID.String()+".json", backend.KeyPath{tenantID, quarantineDir}
+
+	// the deletes of missing objects don't fail in all backends
+	reader, _, err := rw.rawR.Read(ctx, name, keypath, nil)
+	if errors.Is(err, backend.ErrDoesNotExist) {
+		return fmt.Errorf("block %s is not quarantined: %w", blockID, err)
+	}
+	if err != nil {
+		return fmt.Errorf("error reading quarantined block %s: %w", blockID, err)
+	}
+	reader.Close()
+
+	if err := rw.rawW.Delete(ctx, name, keypath, nil); err != nil {
+		return fmt.Errorf("error releasing quarantined block %s: %w", blockID, err)
+	}
+
+	rw.quarantineMtx.Lock()
+	if cached, ok := rw.quarantineCache[tenantID]; ok {
+		blocks := maps.Clone(cached.blocks)
+		delete(blocks, blockID)
+		rw.quarantineCache[tenantID] = &cachedQuarantine{blocks: blocks, expires: cached.expires}
+	}
+	rw.quarantineMtx.Unlock()
+
+	level.Info(rw.logger).Log("msg", "released quarantined block", "blockID", blockID, "tenantID", tenantID)
+	return nil
+}
+
+// forgetQuarantinedBlock removes the quarantine of a deleted block
+func (rw *readerWriter) forgetQuarantinedBlock(ctx context.Context, tenantID string, blockID uuid.UUID) {
+	if !rw.isQuarantined(ctx, tenantID, blockID) {
+		return
+	}
+
+	if err := rw.ReleaseQuarantinedBlock(ctx, tenantID, blockID); err != nil && !errors.Is(err, backend.ErrDoesNotExist) {
+		level.Error(rw.logger).Log("msg", "failed to remove the quarantine of deleted block", "blockID", blockID, "tenantID", tenantID, "err", err)
+	}
+}
+
+// quarantineIfCorrupted quarantines the block if err was caused by corrupted data and returns true if it was
+func (rw *readerWriter) quarantineIfCorrupted(ctx context.Context, meta *backend.BlockMeta, err error) bool {
+	if !common.IsCorrupted(err) {
+		return false
+	}
+	if rw.isQuarantined(ctx, meta.TenantID, meta.BlockID) {
+		return true
+	}
+
+	q := &QuarantinedBlock{BlockID: meta.BlockID, QuarantinedAt: time.Now().UTC(), Reason: err.Error()}
+	level.Error(rw.logger).Log("msg", "quarantining corrupted block", "blockID", meta.BlockID, "tenantID", meta.TenantID, "err", err)
+	metricBlockCorruption.WithLabelValues(meta.TenantID).Inc()
+
+	// the block is skipped by this instance even if the quarantine can't be written
+	rw.quarantineMtx.Lock()
+	cached, ok := rw.quarantineCache[meta.TenantID]
+	if !ok {
+		cached = &cachedQuarantine{expires: time.Now().Add(quarantineCacheTTL)}
+	}
+	blocks := maps.Clone(cached.blocks)
+	if blocks == nil {
+		blocks = map[uuid.UUID]*QuarantinedBlock{}
+	}
+	blocks[meta.BlockID] = q
+	rw.quarantineCache[meta.TenantID] = &cachedQuarantine{blocks: blocks, expires: cached.expires}
+	rw.quarantineMtx.Unlock()
+
+	// the request that found the corruption may be cancelled
+	if err := rw.writeQuarantinedBlock(context.WithoutCancel(ctx), meta.TenantID, q); err != nil {
+		level.Error(rw.logger).Log("msg", "failed to write quarantined block", "blockID", meta.BlockID, "tenantID", meta.TenantID, "err", err)
+	}
+	return true
+}
+
+func (rw *readerWriter) isQuarantined(ctx context.Context, tenantID string, blockID uuid.UUID) bool {
+	_, ok := rw.cachedQuarantinedBlocks(ctx, tenantID)[blockID]
+	return ok
+}
+
+func (rw *readerWriter) withoutQuarantinedBlocks(ctx context.Context, tenantID string, blockMetas []*backend.BlockMeta) []*backend.BlockMeta {
+	quarantined := rw.cachedQuarantinedBlocks(ctx, tenantID)
+	if len(quarantined) == 0 {
+		return blockMetas
+	}
+
+	filtered := make([]*backend.BlockMeta, 0, len(blockMetas))
+	for _, b := range blockMetas {
+		if _, ok := quarantined[b.BlockID]; !ok {
+			filtered = append(filtered, b)
+		}
+	}
+	return filtered
+}
+
+// cachedQuarantinedBlocks returns the cached quarantined blocks of the tenant and reads them if they are missing or
+// expired. If they can't be read the blocks are not skipped, corrupted blocks fail the reads instead.
+func (rw *readerWriter) cachedQuarantinedBlocks(ctx context.Context, tenantID string) map[uuid.UUID]*QuarantinedBlock {
+	rw.quarantineMtx.Lock()
+	cached, ok := rw.quarantineCache[tenantID]
+	rw.quarantineMtx.Unlock()
+
+	if ok && time.Now().Before(cached.expires) {
+		return cached.blocks
+	}
+
+	v, _, _ := rw.quarantineReads.Do(tenantID, func() (interface{}, error) {
+		blocks, err := rw.readQuarantinedBlocks(ctx, tenantID)
+		if err != nil {
+			level.Warn(rw.logger).Log("msg", "failed to read quarantined blocks", "tenantID", tenantID, "err", err)
+			if ok {
+				blocks = cached.blocks
+			}
+		}
+
+		rw.quarantineMtx.Lock()
+		defer rw.quarantineMtx.Unlock()
+		rw.quarantineCache[tenantID] = &cachedQuarantine{blocks: blocks, expires: time.Now().Add(quarantineCacheTTL)}
+		return blocks, nil
+	})
+	return v.(map[uuid.UUID]*QuarantinedBlock)
+}
+
+func (rw *readerWriter) readQuarantinedBlocks(ctx context.Context, tenantID string) (map[uuid.UUID]*QuarantinedBlock, error) {
+	keypath := backend.KeyPath{tenantID, quarantineDir}
+
+	var names []string
+	err := rw.rawR.Find(ctx, keypath, func(m backend.FindMatch) {
+		if name := path.Base(m.Key); strings.HasSuffix(name, ".json") {
+			names = append(names, name)
+		}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error listing quarantined blocks: %w", err)
+	}
+
+	blocks := make(map[uuid.UUID]*QuarantinedBlock, len(names))
+	for _, name := range names {
+		reader, _, err := rw.rawR.Read(ctx, name, keypath, nil)
+		if errors.Is(err, backend.ErrDoesNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error reading quarantined block %s: %w", name, err)
+		}
+
+		buff, err := io.ReadAll(reader)
+		reader.Close()
+		if err != nil {
+			return nil, fmt.Errorf("error reading quarantined block %s: %w", name, err)
+		}
+
+		q := &QuarantinedBlock{}
+		if err := json.Unmarshal(buff, q); err != nil {
+			return nil, fmt.Errorf("error unmarshalling quarantined block %s: %w", name, err)
+		}
+		blocks[q.BlockID] = q
+	}
+	return blocks, nil
+}
+
+func (rw *readerWriter) writeQuarantinedBlock(ctx context.Context, tenantID string, q *QuarantinedBlock) error {
+	buff, err := json.Marshal(q)
+	if err != nil {
+		return fmt.Errorf("error marshalling quarantined block: %w", err)
+	}
+
+	return rw.rawW.Write(ctx, q.BlockID.String()+".json", backend.KeyPath{tenantID, quarantineDir}, bytes.NewReader(buff), int64(len(buff)), nil)
+}
+
+// quarantineCorruptedBlocks reads the blocks of a compaction that failed on corrupted data one at a time to find
+// and quarantine the corrupted ones
+func (rw *readerWriter) quarantineCorruptedBlocks(ctx context.Context, blockMetas []*backend.BlockMeta) {
+	for _, meta := range blockMetas {
+		err := readBlock(ctx, meta, rw.r)
+		if err != nil && !rw.quarantineIfCorrupted(ctx, meta, err) {
+			level.Warn(rw.logger).Log("msg", "unable to check block for corruption", "blockID", meta.BlockID, "tenantID", meta.TenantID, "err", err)
+		}
+	}
+}
+
+// readBlock iterates all traces of the block
+func readBlock(ctx context.Context, meta *backend.BlockMeta, r backend.Reader) error {
+	block, err := encoding.OpenBlock(meta, r)
+	if err != nil {
+		return err
+	}
+
+	iterable, ok := block.(common.IterableBlock)
+	if !ok {
+		return common.ErrUnsupported
+	}
+
+	iter, err := iterable.Iterator(ctx)
+	if err != nil {
+		return err
+	}
+	defer iter.Close()
+
+	for {
+		_, tr, err := iter.Next(ctx)
+		if errors.Is(err, io.EOF) || (err == nil && tr == nil) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// quarantiningSpansetIterator quarantines the block when the spansets can't be read because of corrupted data
+type quarantiningSpansetIterator struct {
+	traceql.SpansetIterator
+	rw   *readerWriter
+	meta *backend.BlockMeta
+}
+
+func (i *quarantiningSpansetIterator) Next(ctx context.Context) (*traceql.Spanset, error) {
+	ss, err := i.SpansetIterator.Next(ctx)
+	if err != nil {
+		i.rw.quarantineIfCorrupted(ctx, i.meta, err)
+	}
+	return ss, err
+}
@@ -0,0 +1,152 @@
+package tempodb
+
+import (
+	"context"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/parquet-go/parquet-go"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/pkg/tempopb"
+	"github.com/grafana/tempo/pkg/util/test"
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/encoding/common"
+)
+
+func TestQuarantineCorruptedBlock(t *testing.T) {
+	r, w, c, tempDir := testConfig(t, backend.EncNone, 0)
+
+	err := c.EnableCompaction(context.Background(), &CompactorConfig{
+		ChunkSizeBytes:          10,
+		MaxCompactionRange:      time.Hour,
+		BlockRetention:          time.Hour,
+		CompactedBlockRetention: time.Hour,
+	}, &mockSharder{}, &mockOverrides{})
+	require.NoError(t, err)
+
+	r.EnablePolling(context.Background(), &mockJobSharder{})
+	rw := r.(*readerWriter)
+
+	now := uint32(time.Now().Unix())
+	id := test.ValidTraceID(nil)
+	corrupted := cutTestBlockWithTraces(t, w, testTenantID, []testData{{id: id, t: test.MakeTrace(2, id), start: now, end: now}}).BlockMeta()
+	cutTestBlockWithTraces(t, w, testTenantID, []testData{{id: id, t: test.MakeTrace(2, id), start: now, end: now}})
+	checkBlocklists(t, uuid.Nil, 2, 0, rw)
+
+	corruptTraceIDPages(t, path.Join(tempDir, "traces", testTenantID, corrupted.BlockID.String(), "data.parquet"))
+
+	before := testutil.ToFloat64(metricBlockCorruption.WithLabelValues(testTenantID))
+
+	// the corrupted block fails the lookup and is quarantined
+	traces, failedBlocks, err := rw.Find(context.Background(), testTenantID, id, BlockIDMin, BlockIDMax, 0, 0, common.DefaultSearchOptions())
+	require.NoError(t, err)
+	require.Len(t, failedBlocks, 1)
+	require.True(t, common.IsCorrupted(failedBlocks[0]))
+	require.Len(t, traces, 1)
+	require.True(t, rw.isQuarantined(context.Background(), testTenantID, corrupted.BlockID))
+	require.Equal(t, before+1, testutil.ToFloat64(metricBlockCorruption.WithLabelValues(testTenantID)))
+
+	// quarantined blocks are skipped and reported
+	traces, failedBlocks, err = rw.Find(context.Background(), testTenantID, id, BlockIDMin, BlockIDMax, 0, 0, common.DefaultSearchOptions())
+	require.NoError(t, err)
+	require.Len(t, failedBlocks, 1)
+	require.ErrorIs(t, failedBlocks[0], ErrBlockQuarantined)
+	require.Len(t, traces, 1)
+
+	_, err = rw.Search(context.Background(), corrupted, &tempopb.SearchRequest{}, common.DefaultSearchOptions())
+	require.ErrorIs(t, err, ErrBlockQuarantined)
+
+	toBeCompacted, _ := rw.blockSelector(testTenantID, false).BlocksToCompact()
+	require.Empty(t, toBeCompacted)
+	require.Equal(t, before+1, testutil.ToFloat64(metricBlockCorruption.WithLabelValues(testTenantID)))
+
+	// the quarantine is kept in the backend
+	rw.quarantineCache = map[string]*cachedQuarantine{}
+	require.True(t, rw.isQuarantined(context.Background(), testTenantID, corrupted.BlockID))
+
+	quarantined, err := rw.QuarantinedBlocks(context.Background(), testTenantID)
+	require.NoError(t, err)
+	require.Len(t, quarantined, 1)
+	require.Equal(t, corrupted.BlockID, quarantined[0].BlockID)
+	require.NotEmpty(t, quarantined[0].Reason)
+
+	// released blocks are read again and quarantined again while they are corrupted
+	require.NoError(t, rw.ReleaseQuarantinedBlock(context.Background(), testTenantID, corrupted.BlockID))
+	require.False(t, rw.isQuarantined(context.Background(), testTenantID, corrupted.BlockID))
+	require.ErrorIs(t, rw.ReleaseQuarantinedBlock(context.Background(), testTenantID, corrupted.BlockID), backend.ErrDoesNotExist)
+
+	_, failedBlocks, err = rw.Find(context.Background(), testTenantID, id, BlockIDMin, BlockIDMax, 0, 0, common.DefaultSearchOptions())
+	require.NoError(t, err)
+	require.Len(t, failedBlocks, 1)
+	require.True(t, common.IsCorrupted(failedBlocks[0]))
+	require.True(t, rw.isQuarantined(context.Background(), testTenantID, corrupted.BlockID))
+}
+
+func TestQuarantineCorruptedBlocksOfCompaction(t *testing.T) {
+	r, w, c, tempDir := testConfig(t, backend.EncNone, 0)
+
+	err := c.EnableCompaction(context.Background(), &CompactorConfig{
+		ChunkSizeBytes:          10,
+		MaxCompactionRange:      time.Hour,
+		BlockRetention:          time.Hour,
+		CompactedBlockRetention: time.Hour,
+	}, &mockSharder{}, &mockOverrides{})
+	require.NoError(t, err)
+
+	r.EnablePolling(context.Background(), &mockJobSharder{})
+	rw := r.(*readerWriter)
+
+	now := uint32(time.Now().Unix())
+	var metas []*backend.BlockMeta
+	for i := 0; i < 2; i++ {
+		id := test.ValidTraceID(nil)
+		metas = append(metas, cutTestBlockWithTraces(t, w, testTenantID, []testData{{id: id, t: test.MakeTrace(2, id), start: now, end: now}}).BlockMeta())
+	}
+	checkBlocklists(t, uuid.Nil, 2, 0, rw)
+
+	corruptTraceIDPages(t, path.Join(tempDir, "traces", testTenantID, metas[1].BlockID.String(), "data.parquet"))
+
+	// only the corrupted block is quarantined and the blocks are not compacted
+	err = rw.compact(context.Background(), metas, testTenantID)
+	require.True(t, common.IsCorrupted(err))
+	require.False(t, rw.isQuarantined(context.Background(), testTenantID, metas[0].BlockID))
+	require.True(t, rw.isQuarantined(context.Background(), testTenantID, metas[1].BlockID))
+	checkBlocklists(t, uuid.Nil, 2, 0, rw)
+}
+
+// corruptTraceIDPages flips the last byte of the pages of the trace ID column so they don't match their checksum
+func corruptTraceIDPages(t *testing.T, filename string) {
+	f, err := os.OpenFile(filename, os.O_RDWR, 0)
+	require.NoError(t, err)
+	defer f.Close()
+
+	info, err := f.Stat()
+	require.NoError(t, err)
+
+	pf, err := parquet.OpenFile(f, info.Size())
+	require.NoError(t, err)
+
+	col, ok := pf.Schema().Lookup("TraceID")
+	require.True(t, ok)
+
+	for _, rg := range pf.RowGroups() {
+		offsets, err := rg.ColumnChunks()[col.ColumnIndex].OffsetIndex()
+		require.NoError(t, err)
+
+		for i := 0; i < offsets.NumPages(); i++ {
+			pos := offsets.Offset(i) + offsets.CompressedPageSize(i) - 1
+
+			b := make([]byte, 1)
+			_, err = f.ReadAt(b, pos)
+			require.NoError(t, err)
+			b[0] ^= 0xff
+			_, err = f.WriteAt(b, pos)
+			require.NoError(t, err)
+		}
+	}
+}
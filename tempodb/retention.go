@@ -2,6 +2,7 @@ package tempodb
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"github.com/go-kit/log/level"
@@ -13,6 +14,7 @@ import (
 // retentionLoop watches a timer to clean up blocks that are past retention.
 func (rw *readerWriter) retentionLoop(ctx context.Context) {
 	ticker := time.NewTicker(rw.cfg.BlocklistPoll)
+	defer ticker.Stop()
 	for {
 		select {
 		case <-ctx.Done():
@@ -32,9 +34,40 @@ func (rw *readerWriter) retentionLoop(ctx context.Context) {
 func (rw *readerWriter) doRetention(ctx context.Context) {
 	tenants := rw.blocklist.Tenants()
 
+	// tenants marked for deletion are deleted instead. they are listed every cycle so the compactors pick up the
+	// marks without a restart
+	deletions, err := rw.TenantDeletions(ctx)
+	if err != nil {
+		level.Error(rw.logger).Log("msg", "failed to list tenant deletions", "err", err)
+		metricRetentionErrors.Inc()
+	}
+	deleting := make(map[string]struct{}, len(deletions))
+	for _, d := range deletions {
+		deleting[d.TenantID] = struct{}{}
+	}
+	// the tenants deleted are skipped by the other maintenance tasks. if the deletions can't be listed the tenants
+	// of the last cycle are kept
+	if err == nil {
+		rw.deletingTenantsMtx.Lock()
+		rw.deletingTenants = deleting
+		rw.deletingTenantsMtx.Unlock()
+	}
+
 	bg := boundedwaitgroup.New(rw.compactorCfg.RetentionConcurrency)
 
+	for _, d := range deletions {
+		bg.Add(1)
+		go func(d *TenantDeletion) {
+			defer bg.Done()
+			rw.deleteTenant(ctx, d)
+		}(d)
+	}
+
 	for _, tenantID := range tenants {
+		if _, ok := deleting[tenantID]; ok {
+			continue
+		}
+
 		bg.Add(1)
 		go func(t string) {
 			defer bg.Done()
@@ -45,6 +78,41 @@ func (rw *readerWriter) doRetention(ctx context.Context) {
 	bg.Wait()
 }
 
+// maintenanceLoop runs a maintenance task of the tenants every polling cycle
+func (rw *readerWriter) maintenanceLoop(ctx context.Context, task func(ctx context.Context, tenantID string)) {
+	ticker := time.NewTicker(rw.cfg.BlocklistPoll)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			rw.doMaintenance(ctx, task)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// doMaintenance runs the task for up to RetentionConcurrency tenants at a time. The tenants being deleted are skipped.
+func (rw *readerWriter) doMaintenance(ctx context.Context, task func(ctx context.Context, tenantID string)) {
+	rw.deletingTenantsMtx.Lock()
+	deleting := rw.deletingTenants
+	rw.deletingTenantsMtx.Unlock()
+
+	bg := boundedwaitgroup.New(rw.compactorCfg.RetentionConcurrency)
+	for _, tenantID := range rw.blocklist.Tenants() {
+		if _, ok := deleting[tenantID]; ok {
+			continue
+		}
+
+		bg.Add(1)
+		go func(t string) {
+			defer bg.Done()
+			task(ctx, t)
+		}(tenantID)
+	}
+	bg.Wait()
+}
+
 func (rw *readerWriter) retainTenant(ctx context.Context, tenantID string) {
 	start := time.Now()
 	defer func() { metricRetentionDuration.Observe(time.Since(start).Seconds()) }()
@@ -56,15 +124,35 @@ func (rw *readerWriter) retainTenant(ctx context.Context, tenantID string) {
 	}
 	level.Debug(rw.logger).Log("msg", "Performing block retention", "tenantID", tenantID, "retention", retention)
 
+	var (
+		dryRun    = rw.compactorCfg.RetentionDryRun
+		maxBlocks = rw.compactorCfg.RetentionMaxBlocksPerCycle
+		marked    int
+		deleted   int
+	)
+
 	// iterate through block list.  make compacted anything that is past retention.
-	cutoff := time.Now().Add(-retention)
+	now := time.Now()
 	blocklist := rw.blocklist.Metas(tenantID)
 	for _, b := range blocklist {
+		cutoff := now.Add(-rw.blockRetention(b, retention))
+
+		if maxBlocks > 0 && marked >= maxBlocks {
+			level.Info(rw.logger).Log("msg", "reached max blocks to mark for deletion per retention cycle", "tenantID", tenantID, "max", maxBlocks)
+			break
+		}
+
 		select {
 		case <-ctx.Done():
 			return
 		default:
 			if b.EndTime.Before(cutoff) && rw.compactorSharder.Owns(b.BlockID.String()) {
+				marked++
+				if dryRun {
+					level.Info(rw.logger).Log("msg", "dry run: would mark block for deletion", "blockID", b.BlockID, "tenantID", tenantID, "size", b.Size)
+					continue
+				}
+
 				level.Info(rw.logger).Log("msg", "marking block for deletion", "blockID", b.BlockID, "tenantID", tenantID)
 				err := rw.c.MarkBlockCompacted(b.BlockID, tenantID)
 				if err != nil {
@@ -72,8 +160,9 @@ func (rw *readerWriter) retainTenant(ctx context.Context, tenantID string) {
 					metricRetentionErrors.Inc()
 				} else {
 					metricMarkedForDeletion.Inc()
+					metricMarkedForDeletionBytes.Add(float64(b.Size))
 
-					rw.blocklist.Update(tenantID, nil, []*backend.BlockMeta{b}, []*backend.CompactedBlockMeta{
+					rw.updateBlocklist(tenantID, nil, []*backend.BlockMeta{b}, []*backend.CompactedBlockMeta{
 						{
 							BlockMeta:     *b,
 							CompactedTime: time.Now(),
@@ -85,26 +174,56 @@ func (rw *readerWriter) retainTenant(ctx context.Context, tenantID string) {
 	}
 
 	// iterate through compacted list looking for blocks ready to be cleared
-	cutoff = time.Now().Add(-rw.compactorCfg.CompactedBlockRetention)
+	cutoff := time.Now().Add(-rw.compactorCfg.CompactedBlockRetention)
 	compactedBlocklist := rw.blocklist.CompactedMetas(tenantID)
 	for _, b := range compactedBlocklist {
+		if maxBlocks > 0 && deleted >= maxBlocks {
+			level.Info(rw.logger).Log("msg", "reached max blocks to delete per retention cycle", "tenantID", tenantID, "max", maxBlocks)
+			break
+		}
+
 		select {
 		case <-ctx.Done():
 			return
 		default:
 			level.Debug(rw.logger).Log("owns", rw.compactorSharder.Owns(b.BlockID.String()), "blockID", b.BlockID, "tenantID", tenantID)
 			if b.CompactedTime.Before(cutoff) && rw.compactorSharder.Owns(b.BlockID.String()) {
+				deleted++
+				if dryRun {
+					level.Info(rw.logger).Log("msg", "dry run: would delete block", "blockID", b.BlockID, "tenantID", tenantID, "size", b.Size)
+					continue
+				}
+
 				level.Info(rw.logger).Log("msg", "deleting block", "blockID", b.BlockID, "tenantID", tenantID)
 				err := rw.c.ClearBlock(b.BlockID, tenantID)
+				if errors.Is(err, backend.ErrObjectLocked) {
+					// the block stays in the blocklist until the bucket lifecycle removes it and isn't counted as deleted
+					level.Debug(rw.logger).Log("msg", "compacted block is locked, it is removed by the bucket lifecycle", "blockID", b.BlockID, "tenantID", tenantID)
+					metricRetentionLocked.Inc()
+					deleted--
+					continue
+				}
 				if err != nil {
 					level.Error(rw.logger).Log("msg", "failed to clear compacted block during retention", "blockID", b.BlockID, "tenantID", tenantID, "err", err)
 					metricRetentionErrors.Inc()
 				} else {
 					metricDeleted.Inc()
+					metricDeletedBytes.Add(float64(b.Size))
 
-					rw.blocklist.Update(tenantID, nil, nil, nil, []*backend.CompactedBlockMeta{b})
+					rw.updateBlocklist(tenantID, nil, nil, nil, []*backend.CompactedBlockMeta{b})
+					rw.forgetQuarantinedBlock(ctx, tenantID, b.BlockID)
 				}
 			}
 		}
 	}
 }
+
+// blockRetention returns the retention of the block. Blocks with traces that requested a longer retention with the
+// tempo.retention attribute are kept up to the max trace retention.
+func (rw *readerWriter) blockRetention(b *backend.BlockMeta, retention time.Duration) time.Duration {
+	maxTraceRetention := rw.compactorCfg.MaxTraceRetention
+	if maxTraceRetention <= 0 || b.Retention <= retention {
+		return retention
+	}
+	return min(b.Retention, maxTraceRetention)
+}
@@ -217,3 +217,91 @@ func TestBlockRetentionOverride(t *testing.T) {
 	rw.pollBlocklist()
 	require.Equal(t, 0, len(rw.blocklist.Metas(testTenantID)))
 }
+
+func TestRetentionDryRunAndMaxBlocksPerCycle(t *testing.T) {
+	tempDir := t.TempDir()
+
+	r, w, c, err := New(&Config{
+		Backend: backend.Local,
+		Local: &local.Config{
+			Path: path.Join(tempDir, "traces"),
+		},
+		Block: &common.BlockConfig{
+			IndexDownsampleBytes: 17,
+			BloomFP:              0.01,
+			BloomShardSizeBytes:  100_000,
+			Version:              encoding.DefaultEncoding().Version(),
+			Encoding:             backend.EncLZ4_256k,
+			IndexPageSizeBytes:   1000,
+		},
+		WAL: &wal.Config{
+			Filepath: path.Join(tempDir, "wal"),
+		},
+		BlocklistPoll: 0,
+	}, nil, log.NewNopLogger())
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	err = c.EnableCompaction(ctx, &CompactorConfig{
+		ChunkSizeBytes:             10,
+		MaxCompactionRange:         time.Hour,
+		BlockRetention:             0,
+		CompactedBlockRetention:    0,
+		RetentionDryRun:            true,
+		RetentionMaxBlocksPerCycle: 3,
+	}, &mockSharder{}, &mockOverrides{})
+	require.NoError(t, err)
+
+	r.EnablePolling(ctx, &mockJobSharder{})
+
+	cutTestBlocks(t, w, testTenantID, 5, 10)
+
+	rw := r.(*readerWriter)
+	rw.pollBlocklist()
+	require.Len(t, rw.blocklist.Metas(testTenantID), 5)
+
+	// dry run changes nothing
+	rw.doRetention(ctx)
+	rw.pollBlocklist()
+	require.Len(t, rw.blocklist.Metas(testTenantID), 5)
+	require.Len(t, rw.blocklist.CompactedMetas(testTenantID), 0)
+
+	// at most 3 blocks are marked per cycle
+	rw.compactorCfg.RetentionDryRun = false
+	rw.compactorCfg.CompactedBlockRetention = time.Hour
+	rw.doRetention(ctx)
+	rw.pollBlocklist()
+	require.Len(t, rw.blocklist.Metas(testTenantID), 2)
+	require.Len(t, rw.blocklist.CompactedMetas(testTenantID), 3)
+
+	rw.doRetention(ctx)
+	rw.pollBlocklist()
+	require.Len(t, rw.blocklist.Metas(testTenantID), 0)
+	require.Len(t, rw.blocklist.CompactedMetas(testTenantID), 5)
+
+	// at most 3 blocks are deleted per cycle
+	rw.compactorCfg.CompactedBlockRetention = 0
+	rw.doRetention(ctx)
+	rw.pollBlocklist()
+	require.Len(t, rw.blocklist.CompactedMetas(testTenantID), 2)
+
+	rw.doRetention(ctx)
+	rw.pollBlocklist()
+	require.Len(t, rw.blocklist.CompactedMetas(testTenantID), 0)
+}
+
+func TestBlockRetentionFromTraceAttribute(t *testing.T) {
+	rw := &readerWriter{compactorCfg: &CompactorConfig{}}
+	day := 24 * time.Hour
+
+	// the attribute is ignored without a max trace retention
+	require.Equal(t, 7*day, rw.blockRetention(&backend.BlockMeta{Retention: 30 * day}, 7*day))
+
+	rw.compactorCfg.MaxTraceRetention = 90 * day
+	require.Equal(t, 7*day, rw.blockRetention(&backend.BlockMeta{}, 7*day))
+	require.Equal(t, 30*day, rw.blockRetention(&backend.BlockMeta{Retention: 30 * day}, 7*day))
+	require.Equal(t, 90*day, rw.blockRetention(&backend.BlockMeta{Retention: 365 * day}, 7*day))
+
+	// a shorter retention never deletes blocks early
+	require.Equal(t, 7*day, rw.blockRetention(&backend.BlockMeta{Retention: day}, 7*day))
+}
@@ -0,0 +1,226 @@
+package tempodb
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/go-kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/grafana/tempo/tempodb/backend"
+)
+
+const (
+	storageQuotaName = "quota.json"
+
+	// the mark of a tenant that exceeds its storage quota is written by the compactor that owns this job
+	storageQuotaJobPrefix = "storage-quota-"
+)
+
+var (
+	metricStorageQuotaUtilization = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "tempodb",
+		Name:      "storage_quota_utilization",
+		Help:      "Ratio of the storage quota of the tenant that is used by its blocks.",
+	}, []string{"tenant", "resource"})
+	metricStorageQuotaMarkedForDeletion = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "tempodb",
+		Name:      "storage_quota_marked_for_deletion_total",
+		Help:      "Total number of blocks marked for deletion because the tenant exceeded its storage quota.",
+	}, []string{"tenant"})
+)
+
+// StorageQuotaExceeded marks a tenant that exceeds its storage quota and rejects its traces. It is stored outside of
+// the tenant.
+type StorageQuotaExceeded struct {
+	TenantID  string    `json:"tenantID"`
+	UpdatedAt time.Time `json:"updatedAt"`
+	Bytes     uint64    `json:"bytes"`
+	Blocks    int       `json:"blocks"`
+	MaxBytes  int       `json:"maxBytes,omitempty"`
+	MaxBlocks int       `json:"maxBlocks,omitempty"`
+}
+
+// StorageQuotasExceeded returns the marks of all the tenants that exceed their storage quota and reject traces
+func (rw *readerWriter) StorageQuotasExceeded(ctx context.Context) ([]*StorageQuotaExceeded, error) {
+	tenants, err := rw.rawR.List(ctx, backend.KeyPath{backend.StorageQuotasKeyPath})
+	if err != nil {
+		return nil, fmt.Errorf("error listing storage quotas: %w", err)
+	}
+
+	quotas := make([]*StorageQuotaExceeded, 0, len(tenants))
+	for _, tenantID := range tenants {
+		q, err := rw.storageQuotaExceeded(ctx, tenantID)
+		if errors.Is(err, backend.ErrDoesNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		quotas = append(quotas, q)
+	}
+	return quotas, nil
+}
+
+func (rw *readerWriter) storageQuotaExceeded(ctx context.Context, tenantID string) (*StorageQuotaExceeded, error) {
+	reader, _, err := rw.rawR.Read(ctx, storageQuotaName, backend.KeyPath{backend.StorageQuotasKeyPath, tenantID}, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	buff, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("error reading storage quota: %w", err)
+	}
+
+	q := &StorageQuotaExceeded{}
+	err = json.Unmarshal(buff, q)
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshalling storage quota: %w", err)
+	}
+	return q, nil
+}
+
+// enforceStorageQuota compares the blocks of the tenant with its storage quota. A tenant that exceeds it has its
+// oldest blocks marked for deletion until it fits in the quota or, if the tenant rejects traces instead, is marked so
+// the ingesters stop accepting its traces until it fits in the quota again.
+func (rw *readerWriter) enforceStorageQuota(ctx context.Context, tenantID string) {
+	var (
+		maxBytes  = rw.compactorOverrides.MaxStoredBytesForTenant(tenantID)
+		maxBlocks = rw.compactorOverrides.MaxStoredBlocksForTenant(tenantID)
+		reject    = rw.compactorOverrides.StorageQuotaRejectsIngestForTenant(tenantID)
+		metas     = rw.blocklist.Metas(tenantID)
+		usedBytes uint64
+	)
+	for _, b := range metas {
+		usedBytes += b.Size
+	}
+
+	setStorageQuotaUtilization(tenantID, "bytes", float64(usedBytes), maxBytes)
+	setStorageQuotaUtilization(tenantID, "blocks", float64(len(metas)), maxBlocks)
+
+	exceeded := (maxBytes > 0 && usedBytes > uint64(maxBytes)) || (maxBlocks > 0 && len(metas) > maxBlocks)
+
+	if rw.compactorSharder.Owns(storageQuotaJobPrefix + tenantID) {
+		rw.updateStorageQuotaExceeded(ctx, tenantID, exceeded && reject, &StorageQuotaExceeded{
+			TenantID:  tenantID,
+			Bytes:     usedBytes,
+			Blocks:    len(metas),
+			MaxBytes:  maxBytes,
+			MaxBlocks: maxBlocks,
+		})
+	}
+
+	if !exceeded || reject {
+		return
+	}
+
+	// the newest blocks that fit in the quota are kept. every compactor marks the blocks it owns among the others
+	sort.Slice(metas, func(i, j int) bool { return metas[i].EndTime.After(metas[j].EndTime) })
+
+	var (
+		dryRun     = rw.compactorCfg.RetentionDryRun
+		maxMarked  = rw.compactorCfg.RetentionMaxBlocksPerCycle
+		keptBytes  uint64
+		keptBlocks int
+		full       bool
+		marked     int
+	)
+	for _, b := range metas {
+		if !full && (maxBytes <= 0 || keptBytes+b.Size <= uint64(maxBytes)) && (maxBlocks <= 0 || keptBlocks < maxBlocks) {
+			keptBytes += b.Size
+			keptBlocks++
+			continue
+		}
+		// once a block doesn't fit, the older blocks are deleted too even if they are small enough to fit
+		full = true
+
+		if !rw.compactorSharder.Owns(b.BlockID.String()) {
+			continue
+		}
+		if maxMarked > 0 && marked >= maxMarked {
+			level.Info(rw.logger).Log("msg", "reached max blocks to mark for deletion per retention cycle", "tenantID", tenantID, "max", maxMarked)
+			return
+		}
+		if ctx.Err() != nil {
+			return
+		}
+
+		marked++
+		if dryRun {
+			level.Info(rw.logger).Log("msg", "dry run: would mark block for deletion over storage quota", "blockID", b.BlockID, "tenantID", tenantID, "size", b.Size)
+			continue
+		}
+
+		level.Info(rw.logger).Log("msg", "marking block for deletion over storage quota", "blockID", b.BlockID, "tenantID", tenantID)
+		err := rw.c.MarkBlockCompacted(b.BlockID, tenantID)
+		if err != nil {
+			level.Error(rw.logger).Log("msg", "failed to mark block compacted over storage quota", "blockID", b.BlockID, "tenantID", tenantID, "err", err)
+			metricRetentionErrors.Inc()
+			continue
+		}
+		metricMarkedForDeletion.Inc()
+		metricMarkedForDeletionBytes.Add(float64(b.Size))
+		metricStorageQuotaMarkedForDeletion.WithLabelValues(tenantID).Inc()
+
+		rw.updateBlocklist(tenantID, nil, []*backend.BlockMeta{b}, []*backend.CompactedBlockMeta{
+			{
+				BlockMeta:     *b,
+				CompactedTime: time.Now(),
+			},
+		}, nil)
+	}
+}
+
+// updateStorageQuotaExceeded writes the mark of the tenant while it rejects traces over its storage quota and removes
+// it otherwise
+func (rw *readerWriter) updateStorageQuotaExceeded(ctx context.Context, tenantID string, exceeded bool, q *StorageQuotaExceeded) {
+	keypath := backend.KeyPath{backend.StorageQuotasKeyPath, tenantID}
+
+	if !exceeded {
+		_, err := rw.storageQuotaExceeded(ctx, tenantID)
+		if errors.Is(err, backend.ErrDoesNotExist) {
+			return
+		}
+		if err == nil {
+			level.Info(rw.logger).Log("msg", "tenant is within its storage quota, accepting traces", "tenantID", tenantID)
+			err = rw.rawW.Delete(ctx, storageQuotaName, keypath, nil)
+		}
+		if err != nil {
+			level.Error(rw.logger).Log("msg", "failed to remove storage quota mark", "tenantID", tenantID, "err", err)
+			metricRetentionErrors.Inc()
+		}
+		return
+	}
+
+	if rw.compactorCfg.RetentionDryRun {
+		level.Info(rw.logger).Log("msg", "dry run: would reject traces of tenant over storage quota", "tenantID", tenantID, "bytes", q.Bytes, "blocks", q.Blocks)
+		return
+	}
+
+	q.UpdatedAt = time.Now().UTC()
+	buff, err := json.Marshal(q)
+	if err == nil {
+		err = rw.rawW.Write(ctx, storageQuotaName, keypath, bytes.NewReader(buff), int64(len(buff)), nil)
+	}
+	if err != nil {
+		level.Error(rw.logger).Log("msg", "failed to write storage quota mark", "tenantID", tenantID, "err", err)
+		metricRetentionErrors.Inc()
+	}
+}
+
+func setStorageQuotaUtilization(tenantID, resource string, used float64, limit int) {
+	if limit <= 0 {
+		metricStorageQuotaUtilization.DeleteLabelValues(tenantID, resource)
+		return
+	}
+	metricStorageQuotaUtilization.WithLabelValues(tenantID, resource).Set(used / float64(limit))
+}
@@ -0,0 +1,83 @@
+package tempodb
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/pkg/util/test"
+	"github.com/grafana/tempo/tempodb/backend"
+)
+
+func TestStorageQuota(t *testing.T) {
+	r, w, c, _ := testConfig(t, backend.EncNone, 0)
+	ctx := context.Background()
+
+	overrides := &mockOverrides{}
+	err := c.EnableCompaction(ctx, &CompactorConfig{
+		ChunkSizeBytes:          10,
+		MaxCompactionRange:      time.Hour,
+		BlockRetention:          24 * time.Hour,
+		CompactedBlockRetention: time.Hour,
+	}, &mockSharder{}, overrides)
+	require.NoError(t, err)
+
+	r.EnablePolling(ctx, &mockJobSharder{})
+	rw := r.(*readerWriter)
+
+	now := time.Now()
+	for i := 3; i > 0; i-- {
+		writeTestBlock(t, w, testTenantID, test.ValidTraceID(nil), uint32(now.Add(-time.Duration(i)*time.Minute).Unix()))
+	}
+	rw.pollBlocklist()
+	metas := rw.blocklist.Metas(testTenantID)
+	require.Len(t, metas, 3)
+
+	newest := metas[0]
+	for _, m := range metas {
+		if m.EndTime.After(newest.EndTime) {
+			newest = m
+		}
+	}
+
+	// within the quota
+	overrides.maxStoredBlocks = 3
+	rw.enforceStorageQuota(ctx, testTenantID)
+	require.Len(t, rw.blocklist.Metas(testTenantID), 3)
+
+	// rejecting traces keeps the blocks and marks the tenant
+	overrides.maxStoredBlocks = 1
+	overrides.storageQuotaReject = true
+	rw.enforceStorageQuota(ctx, testTenantID)
+	require.Len(t, rw.blocklist.Metas(testTenantID), 3)
+
+	quotas, err := rw.StorageQuotasExceeded(ctx)
+	require.NoError(t, err)
+	require.Len(t, quotas, 1)
+	require.Equal(t, testTenantID, quotas[0].TenantID)
+	require.Equal(t, 3, quotas[0].Blocks)
+	require.Equal(t, 1, quotas[0].MaxBlocks)
+
+	// the marks are not tenants
+	tenants, err := rw.r.Tenants(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []string{testTenantID}, tenants)
+
+	// deleting the oldest blocks removes the mark
+	overrides.storageQuotaReject = false
+	rw.enforceStorageQuota(ctx, testTenantID)
+	require.Equal(t, []*backend.BlockMeta{newest}, rw.blocklist.Metas(testTenantID))
+	require.Len(t, rw.blocklist.CompactedMetas(testTenantID), 2)
+
+	quotas, err = rw.StorageQuotasExceeded(ctx)
+	require.NoError(t, err)
+	require.Empty(t, quotas)
+
+	// the size of the blocks is limited too
+	overrides.maxStoredBlocks = 0
+	overrides.maxStoredBytes = int(newest.Size) - 1
+	rw.enforceStorageQuota(ctx, testTenantID)
+	require.Empty(t, rw.blocklist.Metas(testTenantID))
+}
@@ -6,6 +6,9 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"math/rand"
+	"sort"
+	"sync"
 	"time"
 
 	"github.com/grafana/tempo/pkg/util"
@@ -16,19 +19,22 @@ import (
 	"github.com/opentracing/opentracing-go"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/atomic"
+	"golang.org/x/sync/singleflight"
 
 	"github.com/grafana/tempo/modules/cache/memcached"
 	"github.com/grafana/tempo/modules/cache/redis"
 	"github.com/grafana/tempo/pkg/cache"
 	"github.com/grafana/tempo/pkg/tempopb"
 	"github.com/grafana/tempo/pkg/traceql"
+	"github.com/grafana/tempo/pkg/usagestats"
 	"github.com/grafana/tempo/pkg/util/log"
 	"github.com/grafana/tempo/tempodb/backend"
-	"github.com/grafana/tempo/tempodb/backend/azure"
 	backend_cache "github.com/grafana/tempo/tempodb/backend/cache"
-	"github.com/grafana/tempo/tempodb/backend/gcs"
-	"github.com/grafana/tempo/tempodb/backend/local"
-	"github.com/grafana/tempo/tempodb/backend/s3"
+	"github.com/grafana/tempo/tempodb/backend/encryption"
+	"github.com/grafana/tempo/tempodb/backend/ratelimit"
+	"github.com/grafana/tempo/tempodb/backend/retry"
+	"github.com/grafana/tempo/tempodb/backend/tiering"
 	"github.com/grafana/tempo/tempodb/blocklist"
 	"github.com/grafana/tempo/tempodb/encoding"
 	"github.com/grafana/tempo/tempodb/encoding/common"
@@ -65,6 +71,24 @@ var (
 		Name:      "retention_deleted_total",
 		Help:      "Total number of blocks deleted.",
 	})
+	metricRetentionLocked = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "tempodb",
+		Name:      "retention_locked_total",
+		Help:      "Total number of times a compacted block was not deleted because its objects are locked.",
+	})
+	metricMarkedForDeletionBytes = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "tempodb",
+		Name:      "retention_marked_for_deletion_bytes_total",
+		Help:      "Total size in bytes of the blocks marked for deletion.",
+	})
+	metricDeletedBytes = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "tempodb",
+		Name:      "retention_deleted_bytes_total",
+		Help:      "Total size in bytes of the blocks deleted.",
+	})
+
+	statTenants = usagestats.NewString("storage_tenants")
+	statBlocks  = usagestats.NewString("storage_blocks")
 )
 
 type Writer interface {
@@ -72,12 +96,17 @@ type Writer interface {
 	CompleteBlock(ctx context.Context, block common.WALBlock) (common.BackendBlock, error)
 	CompleteBlockWithBackend(ctx context.Context, block common.WALBlock, r backend.Reader, w backend.Writer) (common.BackendBlock, error)
 	WAL() *wal.WAL
+	SetBlocklistNotifier(n BlocklistNotifier)
+	AddTraceAnnotation(ctx context.Context, tenantID string, traceID common.ID, a *Annotation) error
+	MarkTenantForDeletion(ctx context.Context, tenantID string) (*TenantDeletion, error)
+	AddTraceTombstones(ctx context.Context, tenantID string, traceIDs []common.ID) error
 }
 
 type IterateObjectCallback func(id common.ID, obj []byte) bool
 
 type Reader interface {
 	Find(ctx context.Context, tenantID string, id common.ID, blockStart string, blockEnd string, timeStart int64, timeEnd int64, opts common.SearchOptions) ([]*tempopb.Trace, []error, error)
+	FindWithCallback(ctx context.Context, tenantID string, id common.ID, blockStart string, blockEnd string, timeStart int64, timeEnd int64, opts common.SearchOptions, fn FindTraceFunc) ([]error, error)
 	Search(ctx context.Context, meta *backend.BlockMeta, req *tempopb.SearchRequest, opts common.SearchOptions) (*tempopb.SearchResponse, error)
 	SearchTags(ctx context.Context, meta *backend.BlockMeta, scope string, opts common.SearchOptions) (*tempopb.SearchTagsResponse, error)
 	SearchTagValues(ctx context.Context, meta *backend.BlockMeta, tag string, opts common.SearchOptions) ([]string, error)
@@ -87,13 +116,32 @@ type Reader interface {
 	FetchTagValues(ctx context.Context, meta *backend.BlockMeta, req traceql.FetchTagValuesRequest, cb traceql.FetchTagValuesCallback, opts common.SearchOptions) error
 
 	BlockMetas(tenantID string) []*backend.BlockMeta
+	TenantStats() []*TenantStats
+	BlockMeta(ctx context.Context, tenantID string, blockID uuid.UUID) (*backend.BlockMeta, error)
+	TraceAnnotations(ctx context.Context, tenantID string, traceID common.ID) ([]*Annotation, error)
+	TenantDeletion(ctx context.Context, tenantID string) (*TenantDeletion, error)
+	TenantDeletions(ctx context.Context) ([]*TenantDeletion, error)
+	StorageQuotasExceeded(ctx context.Context) ([]*StorageQuotaExceeded, error)
+	TraceTombstones(ctx context.Context, tenantID string) ([]*TraceTombstone, error)
+	TraceTombstoned(ctx context.Context, tenantID string, traceID common.ID) (bool, error)
+	TraceTombstonesCount(ctx context.Context, tenantID string) (int, error)
+	QuarantinedBlocks(ctx context.Context, tenantID string) ([]*QuarantinedBlock, error)
+	ReleaseQuarantinedBlock(ctx context.Context, tenantID string, blockID uuid.UUID) error
 	EnablePolling(ctx context.Context, sharder blocklist.JobSharder)
+	SetBlocklistPoll(interval time.Duration)
+	UpdateBlocklist(tenantID string, add []*backend.BlockMeta, remove []*backend.BlockMeta, compactedAdd []*backend.CompactedBlockMeta, compactedRemove []*backend.CompactedBlockMeta)
 
 	Shutdown()
 }
 
+// FindTraceFunc is called with every partial trace found in a block. The blocks are searched concurrently but it is
+// called with the partial traces in the order of the blocks, newest first, and never concurrently. Returning true
+// stops the search of the remaining blocks.
+type FindTraceFunc func(partialTrace *tempopb.Trace) (stop bool)
+
 type Compactor interface {
 	EnableCompaction(ctx context.Context, cfg *CompactorConfig, sharder CompactorSharder, overrides CompactorOverrides) error
+	CompactionStatus() *CompactionStatus
 }
 
 type CompactorSharder interface {
@@ -106,6 +154,17 @@ type CompactorOverrides interface {
 	BlockRetentionForTenant(tenantID string) time.Duration
 	MaxBytesPerTraceForTenant(tenantID string) int
 	MaxCompactionRangeForTenant(tenantID string) time.Duration
+	CompactionDisabledForTenant(tenantID string) bool
+	CompactionPriorityForTenant(tenantID string) int
+	MaxStoredBytesForTenant(tenantID string) int
+	MaxStoredBlocksForTenant(tenantID string) int
+	StorageQuotaRejectsIngestForTenant(tenantID string) bool
+}
+
+// BlocklistNotifier is told about the blocks written, compacted and deleted by this instance so it can announce them
+// to other instances before their next blocklist poll.
+type BlocklistNotifier interface {
+	BlocklistUpdated(tenantID string, add []*backend.BlockMeta, remove []*backend.BlockMeta, compactedAdd []*backend.CompactedBlockMeta, compactedRemove []*backend.CompactedBlockMeta)
 }
 
 type WriteableBlock interface {
@@ -116,9 +175,14 @@ type WriteableBlock interface {
 var _ Reader = (*readerWriter)(nil)
 
 type readerWriter struct {
-	r backend.Reader
-	w backend.Writer
-	c backend.Compactor
+	r    backend.Reader
+	w    backend.Writer
+	c    backend.Compactor
+	rawR backend.RawReader
+	rawW backend.RawWriter
+
+	// moves blocks to the cold tier. nil if the cold tier isn't configured
+	coldTier tiering.Mover
 
 	wal  *wal.WAL
 	pool *pool.Pool
@@ -126,13 +190,37 @@ type readerWriter struct {
 	logger gkLog.Logger
 	cfg    *Config
 
-	blocklistPoller *blocklist.Poller
-	blocklist       *blocklist.List
+	blocklistPoller   *blocklist.Poller
+	blocklist         *blocklist.List
+	blocklistPoll     *atomic.Duration
+	blocklistNotifier BlocklistNotifier
+	pollingEnabled    *atomic.Bool
+
+	annotationsMtx sync.Mutex
+
+	tombstonesCacheMtx sync.Mutex
+	tombstonesCache    map[string]*cachedTraceTombstones
+	tombstonesReads    singleflight.Group
+
+	quarantineMtx   sync.Mutex
+	quarantineCache map[string]*cachedQuarantine
+	quarantineReads singleflight.Group
+
+	claimedBlocksMtx sync.Mutex
+	claimedBlocks    map[uuid.UUID]struct{}
+
+	deletingTenantsMtx sync.Mutex
+	deletingTenants    map[string]struct{}
 
 	compactorCfg          *CompactorConfig
 	compactorSharder      CompactorSharder
 	compactorOverrides    CompactorOverrides
 	compactorTenantOffset uint
+	compactorTenantWaits  map[string]int
+
+	compactionStatusMtx sync.Mutex
+	compactionJobs      map[*CompactionJob]struct{}
+	compactionTenants   map[string]*TenantCompactionStatus
 }
 
 // New creates a new tempodb
@@ -146,23 +234,36 @@ func New(cfg *Config, cacheProvider cache.Provider, logger gkLog.Logger) (Reader
 		return nil, nil, nil, fmt.Errorf("invalid config while creating tempodb: %w", err)
 	}
 
-	switch cfg.Backend {
-	case backend.Local:
-		rawR, rawW, c, err = local.New(cfg.Local)
-	case backend.GCS:
-		rawR, rawW, c, err = gcs.New(cfg.GCS)
-	case backend.S3:
-		rawR, rawW, c, err = s3.New(cfg.S3)
-	case backend.Azure:
-		rawR, rawW, c, err = azure.New(cfg.Azure)
-	default:
-		err = fmt.Errorf("unknown backend %s", cfg.Backend)
+	rawR, rawW, c, err = newBackend(cfg)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	// the cold tier only holds the blocks of the configured backend so the blocks of the routed tenants never leave
+	// their buckets
+	var coldTier tiering.Mover
+	if cfg.ColdTier.Enabled() {
+		rawR, rawW, c, coldTier, err = newColdTier(cfg, rawR, rawW, c)
+		if err != nil {
+			return nil, nil, nil, err
+		}
 	}
 
+	rawR, rawW, c, err = newRoutedBackend(cfg, rawR, rawW, c)
 	if err != nil {
 		return nil, nil, nil, err
 	}
 
+	// limit the metadata operations and uploads below the retries so every attempt is limited
+	if cfg.RateLimit.Enabled() {
+		rawR, rawW, c = ratelimit.New(&cfg.RateLimit, rawR, rawW, c)
+	}
+
+	// retry transient errors below the caching layer
+	if cfg.Retry.Enabled() {
+		rawR, rawW, c = retry.New(&cfg.Retry, rawR, rawW, c)
+	}
+
 	// build a caching layer if we have a provider
 	if cacheProvider != nil {
 		legacyCache, roles, err := createLegacyCache(cfg, logger)
@@ -184,17 +285,38 @@ func New(cfg *Config, cacheProvider cache.Provider, logger gkLog.Logger) (Reader
 		}
 	}
 
+	// encrypt above the caching layer so the caches only hold encrypted objects
+	if cfg.Encryption.Enabled {
+		rawR, rawW, c, err = encryption.New(&cfg.Encryption, rawR, rawW, c)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("error creating encryption: %w", err)
+		}
+	}
+
 	r := backend.NewReader(rawR)
 	w := backend.NewWriter(rawW)
 	rw := &readerWriter{
 		c:         c,
 		r:         r,
 		w:         w,
+		rawR:      rawR,
+		rawW:      rawW,
+		coldTier:  coldTier,
 		cfg:       cfg,
 		logger:    logger,
 		pool:      pool.NewPool(cfg.Pool),
 		blocklist: blocklist.New(),
+
+		tombstonesCache: map[string]*cachedTraceTombstones{},
+		quarantineCache: map[string]*cachedQuarantine{},
+		claimedBlocks:   map[uuid.UUID]struct{}{},
+		deletingTenants: map[string]struct{}{},
+
+		compactionJobs:    map[*CompactionJob]struct{}{},
+		compactionTenants: map[string]*TenantCompactionStatus{},
 	}
+	rw.blocklistPoll = atomic.NewDuration(cfg.BlocklistPoll)
+	rw.pollingEnabled = atomic.NewBool(false)
 
 	rw.wal, err = wal.New(rw.cfg.WAL)
 	if err != nil {
@@ -205,7 +327,41 @@ func New(cfg *Config, cacheProvider cache.Provider, logger gkLog.Logger) (Reader
 }
 
 func (rw *readerWriter) WriteBlock(ctx context.Context, c WriteableBlock) error {
-	return c.Write(ctx, rw.w)
+	err := c.Write(ctx, rw.w)
+	if err != nil {
+		return err
+	}
+
+	if rw.blocklistNotifier != nil {
+		meta := c.BlockMeta()
+		rw.blocklistNotifier.BlocklistUpdated(meta.TenantID, []*backend.BlockMeta{meta}, nil, nil, nil)
+	}
+	return nil
+}
+
+// SetBlocklistNotifier sets the notifier told about the blocks written, compacted and deleted by this instance.
+// It must be called before the blocks are written or compaction is enabled.
+func (rw *readerWriter) SetBlocklistNotifier(n BlocklistNotifier) {
+	rw.blocklistNotifier = n
+}
+
+// updateBlocklist updates the blocklist with the changes made by this instance and announces them
+func (rw *readerWriter) updateBlocklist(tenantID string, add []*backend.BlockMeta, remove []*backend.BlockMeta, compactedAdd []*backend.CompactedBlockMeta, compactedRemove []*backend.CompactedBlockMeta) {
+	rw.blocklist.Update(tenantID, add, remove, compactedAdd, compactedRemove)
+
+	if rw.blocklistNotifier != nil {
+		rw.blocklistNotifier.BlocklistUpdated(tenantID, add, remove, compactedAdd, compactedRemove)
+	}
+}
+
+// UpdateBlocklist applies the changes announced by other instances to the blocklist. The changes are ignored if
+// polling isn't enabled. The next poll replaces them with the blocklist in the backend.
+func (rw *readerWriter) UpdateBlocklist(tenantID string, add []*backend.BlockMeta, remove []*backend.BlockMeta, compactedAdd []*backend.CompactedBlockMeta, compactedRemove []*backend.CompactedBlockMeta) {
+	if !rw.pollingEnabled.Load() {
+		return
+	}
+
+	rw.blocklist.Update(tenantID, add, remove, compactedAdd, compactedRemove)
 }
 
 // CompleteBlock iterates the given WAL block and flushes it to the TempoDB backend.
@@ -245,6 +401,7 @@ func (rw *readerWriter) CompleteBlockWithBackend(ctx context.Context, block comm
 		EndTime:          walMeta.EndTime,
 		DataEncoding:     walMeta.DataEncoding,
 		DedicatedColumns: walMeta.DedicatedColumns,
+		Retention:        walMeta.Retention,
 
 		// Other
 		Encoding: rw.cfg.Block.Encoding,
@@ -271,7 +428,32 @@ func (rw *readerWriter) BlockMetas(tenantID string) []*backend.BlockMeta {
 	return rw.blocklist.Metas(tenantID)
 }
 
+// BlockMeta reads the meta of the block from the backend. It returns backend.ErrDoesNotExist if the block doesn't
+// exist or is compacted.
+func (rw *readerWriter) BlockMeta(ctx context.Context, tenantID string, blockID uuid.UUID) (*backend.BlockMeta, error) {
+	return rw.r.BlockMeta(ctx, blockID, tenantID)
+}
+
 func (rw *readerWriter) Find(ctx context.Context, tenantID string, id common.ID, blockStart string, blockEnd string, timeStart int64, timeEnd int64, opts common.SearchOptions) ([]*tempopb.Trace, []error, error) {
+	var (
+		mtx           sync.Mutex
+		partialTraces []*tempopb.Trace
+	)
+
+	funcErrs, err := rw.FindWithCallback(ctx, tenantID, id, blockStart, blockEnd, timeStart, timeEnd, opts, func(partialTrace *tempopb.Trace) bool {
+		mtx.Lock()
+		defer mtx.Unlock()
+
+		partialTraces = append(partialTraces, partialTrace)
+		return false
+	})
+
+	return partialTraces, funcErrs, err
+}
+
+// FindWithCallback searches the blocks for the trace and passes every partial trace to fn as soon as it's found, so
+// the caller can combine them while the remaining blocks are searched.
+func (rw *readerWriter) FindWithCallback(ctx context.Context, tenantID string, id common.ID, blockStart string, blockEnd string, timeStart int64, timeEnd int64, opts common.SearchOptions, fn FindTraceFunc) ([]error, error) {
 	// tracing instrumentation
 	logger := log.WithContext(ctx, log.Logger)
 	span, ctx := opentracing.StartSpanFromContext(ctx, "store.Find")
@@ -279,19 +461,19 @@ func (rw *readerWriter) Find(ctx context.Context, tenantID string, id common.ID,
 
 	blockStartUUID, err := uuid.Parse(blockStart)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 	blockStartBytes, err := blockStartUUID.MarshalBinary()
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 	blockEndUUID, err := uuid.Parse(blockEnd)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 	blockEndBytes, err := blockEndUUID.MarshalBinary()
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 
 	// gather appropriate blocks
@@ -301,66 +483,132 @@ func (rw *readerWriter) Find(ctx context.Context, tenantID string, id common.ID,
 	blocksSearched := 0
 	compactedBlocksSearched := 0
 
+	// the quarantined blocks that may hold the trace are returned as errors so the trace can be flagged as partial
+	var skippedErrs []error
 	for _, b := range blocklist {
-		if includeBlock(b, id, blockStartBytes, blockEndBytes, timeStart, timeEnd, opts.BlockReplicationFactor) {
-			copiedBlocklist = append(copiedBlocklist, b)
-			blocksSearched++
+		if !includeBlock(b, id, blockStartBytes, blockEndBytes, timeStart, timeEnd, opts.BlockReplicationFactor) {
+			continue
+		}
+		if rw.isQuarantined(ctx, tenantID, b.BlockID) {
+			skippedErrs = append(skippedErrs, fmt.Errorf("blockID: %s: %w", b.BlockID, ErrBlockQuarantined))
+			continue
 		}
+		copiedBlocklist = append(copiedBlocklist, b)
+		blocksSearched++
 	}
 	for _, c := range compactedBlocklist {
-		if includeCompactedBlock(c, id, blockStartBytes, blockEndBytes, rw.cfg.BlocklistPoll, timeStart, timeEnd, opts.BlockReplicationFactor) {
-			copiedBlocklist = append(copiedBlocklist, &c.BlockMeta)
-			compactedBlocksSearched++
+		if !includeCompactedBlock(c, id, blockStartBytes, blockEndBytes, rw.cfg.BlocklistPoll, timeStart, timeEnd, opts.BlockReplicationFactor) {
+			continue
 		}
+		if rw.isQuarantined(ctx, tenantID, c.BlockID) {
+			skippedErrs = append(skippedErrs, fmt.Errorf("blockID: %s: %w", c.BlockID, ErrBlockQuarantined))
+			continue
+		}
+		copiedBlocklist = append(copiedBlocklist, &c.BlockMeta)
+		compactedBlocksSearched++
 	}
 	if len(copiedBlocklist) == 0 {
-		return nil, nil, nil
+		return skippedErrs, nil
 	}
 
+	// search the newest blocks first. they hold the most recent version of the trace and when fn stops the search
+	// it's the older blocks that are skipped
+	sort.SliceStable(copiedBlocklist, func(i, j int) bool {
+		return copiedBlocklist[i].(*backend.BlockMeta).EndTime.After(copiedBlocklist[j].(*backend.BlockMeta).EndTime)
+	})
+
 	if rw.cfg != nil && rw.cfg.Search != nil {
 		rw.cfg.Search.ApplyToOptions(&opts)
 	}
 
-	partialTraces, funcErrs, err := rw.pool.RunJobs(ctx, copiedBlocklist, func(ctx context.Context, payload interface{}) (interface{}, error) {
-		meta := payload.(*backend.BlockMeta)
+	// cancelled to stop the search of the remaining blocks when fn asks to stop
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stopped := atomic.NewBool(false)
+
+	// the partial traces are passed to fn in the order of the blocks, so the search stops at the newest block with the
+	// trace even if an older block is searched faster. The older blocks still searched are cancelled.
+	var (
+		foundMtx   sync.Mutex
+		found      = make([]*tempopb.Trace, len(copiedBlocklist))
+		searched   = make([]bool, len(copiedBlocklist))
+		nextResult int
+	)
+	done := func(i int, tr *tempopb.Trace) {
+		foundMtx.Lock()
+		defer foundMtx.Unlock()
+
+		found[i], searched[i] = tr, true
+		for ; nextResult < len(searched) && searched[nextResult]; nextResult++ {
+			if found[nextResult] != nil && !stopped.Load() && fn(found[nextResult]) {
+				stopped.Store(true)
+				cancel()
+			}
+			found[nextResult] = nil
+		}
+	}
+
+	jobs := make([]interface{}, len(copiedBlocklist))
+	for i := range copiedBlocklist {
+		jobs[i] = i
+	}
+
+	_, funcErrs, err := rw.pool.RunJobsWithConcurrency(ctx, jobs, opts.MaxConcurrentBlocks, func(ctx context.Context, payload interface{}) (interface{}, error) {
+		i := payload.(int)
+		meta := copiedBlocklist[i].(*backend.BlockMeta)
+
+		var foundObject *tempopb.Trace
+		defer func() { done(i, foundObject) }()
+
 		block, err := encoding.OpenBlock(meta, rw.r)
 		if err != nil {
+			if stopped.Load() {
+				return nil, nil
+			}
 			return nil, fmt.Errorf("error opening block for reading, blockID: %s: %w", meta.BlockID.String(), err)
 		}
 
-		foundObject, err := block.FindTraceByID(ctx, id, opts)
+		foundObject, err = block.FindTraceByID(ctx, id, opts)
 		if err != nil {
+			// lookups cancelled because the search was stopped are not errors
+			if stopped.Load() {
+				return nil, nil
+			}
+			rw.quarantineIfCorrupted(ctx, meta, err)
 			return nil, fmt.Errorf("error finding trace by id, blockID: %s: %w", meta.BlockID.String(), err)
 		}
 
 		level.Info(logger).Log("msg", "searching for trace in block", "findTraceID", hex.EncodeToString(id), "block", meta.BlockID, "found", foundObject != nil)
-		return foundObject, nil
+		return nil, nil
 	})
 
-	partialTraceObjs := make([]*tempopb.Trace, len(partialTraces))
-	for i := range partialTraces {
-		partialTraceObjs[i] = partialTraces[i].(*tempopb.Trace)
-	}
-
 	span.SetTag("blockErrs", len(funcErrs))
+	span.SetTag("quarantinedBlocks", len(skippedErrs))
 	span.SetTag("liveBlocks", len(blocklist))
 	span.SetTag("liveBlocksSearched", blocksSearched)
 	span.SetTag("compactedBlocks", len(compactedBlocklist))
 	span.SetTag("compactedBlocksSearched", compactedBlocksSearched)
+	span.SetTag("stopped", stopped.Load())
 
-	return partialTraceObjs, funcErrs, err
+	return append(funcErrs, skippedErrs...), err
 }
 
 // Search the given block.  This method takes the pre-loaded block meta instead of a block ID, which
 // eliminates a read per search request.
 func (rw *readerWriter) Search(ctx context.Context, meta *backend.BlockMeta, req *tempopb.SearchRequest, opts common.SearchOptions) (*tempopb.SearchResponse, error) {
+	if rw.isQuarantined(ctx, meta.TenantID, meta.BlockID) {
+		return nil, ErrBlockQuarantined
+	}
+
 	block, err := encoding.OpenBlock(meta, rw.r)
 	if err != nil {
 		return nil, err
 	}
 
 	rw.cfg.Search.ApplyToOptions(&opts)
-	return block.Search(ctx, req, opts)
+	resp, err := block.Search(ctx, req, opts)
+	rw.quarantineIfCorrupted(ctx, meta, err)
+	return resp, err
 }
 
 func (rw *readerWriter) SearchTags(ctx context.Context, meta *backend.BlockMeta, scope string, opts common.SearchOptions) (*tempopb.SearchTagsResponse, error) {
@@ -370,6 +618,10 @@ func (rw *readerWriter) SearchTags(ctx context.Context, meta *backend.BlockMeta,
 		return nil, fmt.Errorf("unknown scope: %s", scope)
 	}
 
+	if rw.isQuarantined(ctx, meta.TenantID, meta.BlockID) {
+		return nil, ErrBlockQuarantined
+	}
+
 	block, err := encoding.OpenBlock(meta, rw.r)
 	if err != nil {
 		return nil, err
@@ -378,6 +630,7 @@ func (rw *readerWriter) SearchTags(ctx context.Context, meta *backend.BlockMeta,
 	dv := util.NewDistinctStringCollector(0)
 	rw.cfg.Search.ApplyToOptions(&opts)
 	err = block.SearchTags(ctx, attributeScope, dv.Collect, opts)
+	rw.quarantineIfCorrupted(ctx, meta, err)
 
 	return &tempopb.SearchTagsResponse{
 		TagNames: dv.Strings(),
@@ -385,6 +638,10 @@ func (rw *readerWriter) SearchTags(ctx context.Context, meta *backend.BlockMeta,
 }
 
 func (rw *readerWriter) SearchTagValues(ctx context.Context, meta *backend.BlockMeta, tag string, opts common.SearchOptions) ([]string, error) {
+	if rw.isQuarantined(ctx, meta.TenantID, meta.BlockID) {
+		return nil, ErrBlockQuarantined
+	}
+
 	block, err := encoding.OpenBlock(meta, rw.r)
 	if err != nil {
 		return nil, err
@@ -393,11 +650,16 @@ func (rw *readerWriter) SearchTagValues(ctx context.Context, meta *backend.Block
 	dv := util.NewDistinctStringCollector(0)
 	rw.cfg.Search.ApplyToOptions(&opts)
 	err = block.SearchTagValues(ctx, tag, dv.Collect, opts)
+	rw.quarantineIfCorrupted(ctx, meta, err)
 
 	return dv.Strings(), err
 }
 
 func (rw *readerWriter) SearchTagValuesV2(ctx context.Context, meta *backend.BlockMeta, req *tempopb.SearchTagValuesRequest, opts common.SearchOptions) (*tempopb.SearchTagValuesV2Response, error) {
+	if rw.isQuarantined(ctx, meta.TenantID, meta.BlockID) {
+		return nil, ErrBlockQuarantined
+	}
+
 	block, err := encoding.OpenBlock(meta, rw.r)
 	if err != nil {
 		return nil, err
@@ -412,6 +674,7 @@ func (rw *readerWriter) SearchTagValuesV2(ctx context.Context, meta *backend.Blo
 	rw.cfg.Search.ApplyToOptions(&opts)
 	err = block.SearchTagValuesV2(ctx, tag, traceql.MakeCollectTagValueFunc(dv.Collect), opts)
 	if err != nil {
+		rw.quarantineIfCorrupted(ctx, meta, err)
 		return nil, err
 	}
 
@@ -426,23 +689,41 @@ func (rw *readerWriter) SearchTagValuesV2(ctx context.Context, meta *backend.Blo
 
 // Fetch only uses rw.r which has caching enabled
 func (rw *readerWriter) Fetch(ctx context.Context, meta *backend.BlockMeta, req traceql.FetchSpansRequest, opts common.SearchOptions) (traceql.FetchSpansResponse, error) {
+	if rw.isQuarantined(ctx, meta.TenantID, meta.BlockID) {
+		return traceql.FetchSpansResponse{}, ErrBlockQuarantined
+	}
+
 	block, err := encoding.OpenBlock(meta, rw.r)
 	if err != nil {
 		return traceql.FetchSpansResponse{}, err
 	}
 
 	rw.cfg.Search.ApplyToOptions(&opts)
-	return block.Fetch(ctx, req, opts)
+	resp, err := block.Fetch(ctx, req, opts)
+	if err != nil {
+		rw.quarantineIfCorrupted(ctx, meta, err)
+		return resp, err
+	}
+
+	// the spans are read lazily so corrupted pages are found while iterating
+	resp.Results = &quarantiningSpansetIterator{SpansetIterator: resp.Results, rw: rw, meta: meta}
+	return resp, nil
 }
 
 func (rw *readerWriter) FetchTagValues(ctx context.Context, meta *backend.BlockMeta, req traceql.FetchTagValuesRequest, cb traceql.FetchTagValuesCallback, opts common.SearchOptions) error {
+	if rw.isQuarantined(ctx, meta.TenantID, meta.BlockID) {
+		return ErrBlockQuarantined
+	}
+
 	block, err := encoding.OpenBlock(meta, rw.r)
 	if err != nil {
 		return err
 	}
 
 	rw.cfg.Search.ApplyToOptions(&opts)
-	return block.FetchTagValues(ctx, req, cb, opts)
+	err = block.FetchTagValues(ctx, req, cb, opts)
+	rw.quarantineIfCorrupted(ctx, meta, err)
+	return err
 }
 
 func (rw *readerWriter) Shutdown() {
@@ -464,6 +745,10 @@ func (rw *readerWriter) EnableCompaction(ctx context.Context, cfg *CompactorConf
 		cfg.RetentionConcurrency = DefaultRetentionConcurrency
 	}
 
+	if cfg.ColdTier.After > 0 && rw.coldTier == nil {
+		return errors.New("moving blocks to the cold tier requires the cold tier of the storage")
+	}
+
 	rw.compactorCfg = cfg
 	rw.compactorSharder = c
 	rw.compactorOverrides = overrides
@@ -477,6 +762,12 @@ func (rw *readerWriter) EnableCompaction(ctx context.Context, cfg *CompactorConf
 		level.Info(rw.logger).Log("msg", "compaction and retention enabled.")
 		go rw.compactionLoop(ctx)
 		go rw.retentionLoop(ctx)
+		// the other maintenance tasks run in loops of their own so they don't hold up the retention
+		go rw.maintenanceLoop(ctx, rw.enforceStorageQuota)
+		go rw.maintenanceLoop(ctx, rw.downsampleTenant)
+		go rw.maintenanceLoop(ctx, rw.migrateTenant)
+		go rw.maintenanceLoop(ctx, rw.moveTenantToColdTier)
+		go rw.maintenanceLoop(ctx, rw.removeTombstonedTraces)
 	}
 
 	return nil
@@ -493,6 +784,9 @@ func (rw *readerWriter) EnablePolling(ctx context.Context, sharder blocklist.Job
 	if rw.cfg.BlocklistPoll == 0 {
 		rw.cfg.BlocklistPoll = DefaultBlocklistPoll
 	}
+	if rw.blocklistPoll.Load() <= 0 {
+		rw.blocklistPoll.Store(rw.cfg.BlocklistPoll)
+	}
 
 	if rw.cfg.BlocklistPollConcurrency == 0 {
 		rw.cfg.BlocklistPollConcurrency = DefaultBlocklistPollConcurrency
@@ -513,6 +807,7 @@ func (rw *readerWriter) EnablePolling(ctx context.Context, sharder blocklist.Job
 		PollFallback:               rw.cfg.BlocklistPollFallback,
 		TenantIndexBuilders:        rw.cfg.BlocklistPollTenantIndexBuilders,
 		StaleTenantIndex:           rw.cfg.BlocklistPollStaleTenantIndex,
+		StaleTenantIndexTolerance:  rw.cfg.BlocklistPollStaleTenantIndexTolerance,
 		PollJitterMs:               rw.cfg.BlocklistPollJitterMs,
 		TolerateConsecutiveErrors:  rw.cfg.BlocklistPollTolerateConsecutiveErrors,
 		EmptyTenantDeletionAge:     rw.cfg.EmptyTenantDeletionAge,
@@ -524,19 +819,46 @@ func (rw *readerWriter) EnablePolling(ctx context.Context, sharder blocklist.Job
 	// do the first poll cycle synchronously. this will allow the caller to know
 	// that when this method returns the block list is updated
 	rw.pollBlocklist()
+	rw.pollingEnabled.Store(true)
 
 	go rw.pollingLoop(ctx)
 }
 
+// SetBlocklistPoll changes the interval between blocklist polls. The new interval is used after the next poll.
+func (rw *readerWriter) SetBlocklistPoll(interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	rw.blocklistPoll.Store(interval)
+}
+
 func (rw *readerWriter) pollingLoop(ctx context.Context) {
-	ticker := time.NewTicker(rw.cfg.BlocklistPoll)
+	interval := rw.blocklistPoll.Load()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
 
 	for {
 		select {
 		case <-ctx.Done():
 			return
 		case <-ticker.C:
+			// delay each cycle by a random amount so components started together don't poll the backend at the same time
+			if jitter := rw.cfg.BlocklistPollCycleJitter; jitter > 0 {
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(time.Duration(rand.Int63n(int64(jitter)))):
+				}
+			}
+
 			rw.pollBlocklist()
+
+			if next := rw.blocklistPoll.Load(); next != interval {
+				level.Info(rw.logger).Log("msg", "blocklist poll interval changed", "interval", next)
+				interval = next
+				ticker.Reset(interval)
+			}
 		}
 	}
 }
@@ -549,6 +871,13 @@ func (rw *readerWriter) pollBlocklist() {
 	}
 
 	rw.blocklist.ApplyPollResults(blocklist, compactedBlocklist)
+
+	blocks := 0
+	for _, metas := range blocklist {
+		blocks += len(metas)
+	}
+	statTenants.Set(usagestats.ScaleBucket(len(blocklist)))
+	statBlocks.Set(usagestats.ScaleBucket(blocks))
 }
 
 // includeBlock indicates whether a given block should be included in a backend search
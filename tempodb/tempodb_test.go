@@ -2,6 +2,8 @@ package tempodb
 
 import (
 	"context"
+	crand "crypto/rand"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"math/rand"
@@ -17,6 +19,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/atomic"
 
 	"github.com/grafana/tempo/modules/cache/memcached"
 	"github.com/grafana/tempo/modules/cache/redis"
@@ -26,6 +29,7 @@ import (
 	"github.com/grafana/tempo/pkg/tempopb"
 	"github.com/grafana/tempo/pkg/util/test"
 	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/backend/encryption"
 	"github.com/grafana/tempo/tempodb/backend/local"
 	"github.com/grafana/tempo/tempodb/encoding"
 	"github.com/grafana/tempo/tempodb/encoding/common"
@@ -122,6 +126,81 @@ func TestDB(t *testing.T) {
 	}
 }
 
+func TestFindWithCallback(t *testing.T) {
+	r, w, _, _ := testConfig(t, backend.EncGZIP, 0)
+	r.EnablePolling(context.Background(), &mockJobSharder{})
+
+	wal := w.WAL()
+	dec := model.MustNewSegmentDecoder(model.CurrentEncoding)
+
+	// every block holds a part of the trace. the newer blocks hold more batches
+	id := test.ValidTraceID(nil)
+	blocks := 3
+	for i := 0; i < blocks; i++ {
+		head, err := wal.NewBlock(&backend.BlockMeta{BlockID: uuid.New(), TenantID: testTenantID}, model.CurrentEncoding)
+		require.NoError(t, err)
+		start := uint32(100 * (i + 1))
+		writeTraceToWal(t, head, dec, id, test.MakeTrace(i+1, id), start, start+10)
+		_, err = w.CompleteBlock(context.Background(), head)
+		require.NoError(t, err)
+	}
+	r.(*readerWriter).pollBlocklist()
+
+	opts := common.DefaultSearchOptions()
+	opts.MaxConcurrentBlocks = 1
+
+	var found atomic.Int32
+	failedBlocks, err := r.FindWithCallback(context.Background(), testTenantID, id, BlockIDMin, BlockIDMax, 0, 0, opts, func(*tempopb.Trace) bool {
+		found.Inc()
+		return false
+	})
+	require.NoError(t, err)
+	require.Empty(t, failedBlocks)
+	require.Equal(t, int32(blocks), found.Load())
+
+	// the remaining blocks aren't searched once the callback stops the search. the newest block is searched first
+	found.Store(0)
+	var first *tempopb.Trace
+	failedBlocks, err = r.FindWithCallback(context.Background(), testTenantID, id, BlockIDMin, BlockIDMax, 0, 0, opts, func(tr *tempopb.Trace) bool {
+		found.Inc()
+		first = tr
+		return true
+	})
+	require.NoError(t, err)
+	require.Empty(t, failedBlocks)
+	require.Equal(t, int32(1), found.Load())
+	require.Len(t, first.Batches, blocks)
+
+	// the blocks searched concurrently are passed to the callback newest first, and the search stops at the newest
+	opts.MaxConcurrentBlocks = blocks
+	for i := 0; i < 10; i++ {
+		var batches []int
+		failedBlocks, err = r.FindWithCallback(context.Background(), testTenantID, id, BlockIDMin, BlockIDMax, 0, 0, opts, func(tr *tempopb.Trace) bool {
+			batches = append(batches, len(tr.Batches))
+			return false
+		})
+		require.NoError(t, err)
+		require.Empty(t, failedBlocks)
+		require.Equal(t, []int{3, 2, 1}, batches)
+
+		first = nil
+		failedBlocks, err = r.FindWithCallback(context.Background(), testTenantID, id, BlockIDMin, BlockIDMax, 0, 0, opts, func(tr *tempopb.Trace) bool {
+			require.Nil(t, first)
+			first = tr
+			return true
+		})
+		require.NoError(t, err)
+		require.Empty(t, failedBlocks)
+		require.Len(t, first.Batches, blocks)
+	}
+	opts.MaxConcurrentBlocks = 1
+
+	partialTraces, failedBlocks, err := r.Find(context.Background(), testTenantID, id, BlockIDMin, BlockIDMax, 0, 0, opts)
+	require.NoError(t, err)
+	require.Empty(t, failedBlocks)
+	require.Len(t, partialTraces, blocks)
+}
+
 func TestNoCompactionWhenCompactionRange0(t *testing.T) {
 	_, _, c, _ := testConfig(t, backend.EncGZIP, 0)
 
@@ -231,6 +310,67 @@ func TestBlockCleanup(t *testing.T) {
 	assert.Equal(t, 0, len(m))
 }
 
+type mockBlocklistNotifier struct {
+	added, compacted, deleted []uuid.UUID
+}
+
+func (m *mockBlocklistNotifier) BlocklistUpdated(_ string, add []*backend.BlockMeta, _ []*backend.BlockMeta, compactedAdd []*backend.CompactedBlockMeta, compactedRemove []*backend.CompactedBlockMeta) {
+	for _, b := range add {
+		m.added = append(m.added, b.BlockID)
+	}
+	for _, b := range compactedAdd {
+		m.compacted = append(m.compacted, b.BlockID)
+	}
+	for _, b := range compactedRemove {
+		m.deleted = append(m.deleted, b.BlockID)
+	}
+}
+
+func TestBlocklistUpdates(t *testing.T) {
+	r, w, c, _ := testConfig(t, backend.EncLZ4_256k, 0)
+
+	notifier := &mockBlocklistNotifier{}
+	w.SetBlocklistNotifier(notifier)
+
+	err := c.EnableCompaction(context.Background(), &CompactorConfig{
+		ChunkSizeBytes:          10,
+		MaxCompactionRange:      time.Hour,
+		BlockRetention:          0,
+		CompactedBlockRetention: 0,
+	}, &mockSharder{}, &mockOverrides{})
+	require.NoError(t, err)
+
+	announced := &backend.BlockMeta{BlockID: uuid.New(), TenantID: testTenantID}
+
+	// updates are ignored until polling is enabled
+	rw := r.(*readerWriter)
+	r.UpdateBlocklist(testTenantID, []*backend.BlockMeta{announced}, nil, nil, nil)
+	require.Empty(t, rw.blocklist.Metas(testTenantID))
+
+	r.EnablePolling(context.Background(), &mockJobSharder{})
+
+	r.UpdateBlocklist(testTenantID, []*backend.BlockMeta{announced}, nil, nil, nil)
+	require.Len(t, rw.blocklist.Metas(testTenantID), 1)
+	require.Empty(t, notifier.added)
+
+	// the announced block is kept for one poll in case the poll started before it was written. after that the
+	// blocklist in the backend replaces it
+	blocks := cutTestBlocks(t, w, testTenantID, 1, 1)
+	blockID := blocks[0].BlockMeta().BlockID
+	rw.pollBlocklist()
+	require.Len(t, rw.blocklist.Metas(testTenantID), 2)
+	rw.pollBlocklist()
+	require.Len(t, rw.blocklist.Metas(testTenantID), 1)
+	require.Equal(t, blockID, rw.blocklist.Metas(testTenantID)[0].BlockID)
+
+	// retention announces the blocks it marks compacted and deletes
+	rw.doRetention(context.Background())
+	require.Equal(t, []uuid.UUID{blockID}, notifier.compacted)
+
+	rw.doRetention(context.Background())
+	require.Equal(t, []uuid.UUID{blockID}, notifier.deleted)
+}
+
 func checkBlocklists(t *testing.T, expectedID uuid.UUID, expectedB int, expectedCB int, rw *readerWriter) {
 	rw.pollBlocklist()
 
@@ -869,3 +1009,34 @@ func TestCreateLegacyCache(t *testing.T) {
 		})
 	}
 }
+
+func TestEncryptedBlocks(t *testing.T) {
+	key := make([]byte, 32)
+	_, err := crand.Read(key)
+	require.NoError(t, err)
+	keyFile := path.Join(t.TempDir(), "key")
+	require.NoError(t, os.WriteFile(keyFile, []byte(hex.EncodeToString(key)), 0o600))
+
+	r, w, _, _ := testConfig(t, backend.EncNone, 0, func(cfg *Config) {
+		cfg.Encryption = encryption.Config{
+			Enabled:     true,
+			KeyProvider: encryption.KeyProviderFile,
+			File:        encryption.FileConfig{KeyFile: keyFile},
+		}
+	})
+	r.EnablePolling(context.Background(), &mockJobSharder{})
+
+	id := test.ValidTraceID(nil)
+	now := uint32(time.Now().Unix())
+	block := cutTestBlockWithTraces(t, w, testTenantID, []testData{{id: id, t: test.MakeTrace(2, id), start: now, end: now}})
+	r.(*readerWriter).pollBlocklist()
+
+	traces, failedBlocks, err := r.Find(context.Background(), testTenantID, id, BlockIDMin, BlockIDMax, 0, 0, common.DefaultSearchOptions())
+	require.NoError(t, err)
+	require.Empty(t, failedBlocks)
+	require.Len(t, traces, 1)
+
+	resp, err := r.Search(context.Background(), block.BlockMeta(), &tempopb.SearchRequest{Tags: map[string]string{}}, common.DefaultSearchOptions())
+	require.NoError(t, err)
+	require.Len(t, resp.Traces, 1)
+}
@@ -0,0 +1,248 @@
+package tempodb
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"time"
+
+	"github.com/go-kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/grafana/tempo/tempodb/backend"
+)
+
+const (
+	tenantDeletionName = "deletion.json"
+
+	// the deletion of a tenant is run by the compactor that owns this job
+	tenantDeletionJobPrefix = "tenant-deletion-"
+)
+
+var (
+	metricTenantDeletionBlocksDeleted = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "tempodb",
+		Name:      "tenant_deletion_blocks_deleted_total",
+		Help:      "Total number of blocks deleted from the tenants marked for deletion.",
+	}, []string{"tenant"})
+	metricTenantDeletionErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "tempodb",
+		Name:      "tenant_deletion_errors_total",
+		Help:      "Total number of times an error occurred while deleting the tenants marked for deletion.",
+	}, []string{"tenant"})
+)
+
+// TenantDeletion marks a tenant for deletion and reports the progress of the deletion. It is stored outside of the
+// tenant so it is kept after all the objects of the tenant are deleted.
+type TenantDeletion struct {
+	TenantID        string     `json:"tenantID"`
+	RequestedAt     time.Time  `json:"requestedAt"`
+	UpdatedAt       time.Time  `json:"updatedAt"`
+	BlocksDeleted   int        `json:"blocksDeleted"`
+	BlocksRemaining int        `json:"blocksRemaining"`
+	ObjectsDeleted  int        `json:"objectsDeleted"`
+	CompletedAt     *time.Time `json:"completedAt,omitempty"`
+}
+
+// MarkTenantForDeletion marks the tenant for deletion. The compactors delete its blocks and objects and the ingesters
+// stop accepting its traces. Marking a tenant that is already marked returns its current deletion.
+func (rw *readerWriter) MarkTenantForDeletion(ctx context.Context, tenantID string) (*TenantDeletion, error) {
+	d, err := rw.TenantDeletion(ctx, tenantID)
+	if err == nil {
+		return d, nil
+	}
+	if !errors.Is(err, backend.ErrDoesNotExist) {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	d = &TenantDeletion{
+		TenantID:    tenantID,
+		RequestedAt: now,
+		UpdatedAt:   now,
+	}
+	if err := rw.writeTenantDeletion(ctx, d); err != nil {
+		return nil, err
+	}
+
+	level.Info(rw.logger).Log("msg", "marked tenant for deletion", "tenantID", tenantID)
+	return d, nil
+}
+
+// TenantDeletion returns the deletion of the tenant or backend.ErrDoesNotExist if the tenant isn't marked for deletion
+func (rw *readerWriter) TenantDeletion(ctx context.Context, tenantID string) (*TenantDeletion, error) {
+	reader, _, err := rw.rawR.Read(ctx, tenantDeletionName, backend.KeyPath{backend.TenantDeletionsKeyPath, tenantID}, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	buff, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("error reading tenant deletion: %w", err)
+	}
+
+	d := &TenantDeletion{}
+	err = json.Unmarshal(buff, d)
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshalling tenant deletion: %w", err)
+	}
+	return d, nil
+}
+
+// TenantDeletions returns the deletions of all the tenants marked for deletion
+func (rw *readerWriter) TenantDeletions(ctx context.Context) ([]*TenantDeletion, error) {
+	tenants, err := rw.rawR.List(ctx, backend.KeyPath{backend.TenantDeletionsKeyPath})
+	if err != nil {
+		return nil, fmt.Errorf("error listing tenant deletions: %w", err)
+	}
+
+	deletions := make([]*TenantDeletion, 0, len(tenants))
+	for _, tenantID := range tenants {
+		d, err := rw.TenantDeletion(ctx, tenantID)
+		if errors.Is(err, backend.ErrDoesNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		deletions = append(deletions, d)
+	}
+	return deletions, nil
+}
+
+func (rw *readerWriter) writeTenantDeletion(ctx context.Context, d *TenantDeletion) error {
+	buff, err := json.Marshal(d)
+	if err != nil {
+		return fmt.Errorf("error marshalling tenant deletion: %w", err)
+	}
+
+	return rw.rawW.Write(ctx, tenantDeletionName, backend.KeyPath{backend.TenantDeletionsKeyPath, d.TenantID}, bytes.NewReader(buff), int64(len(buff)), nil)
+}
+
+// deleteTenant deletes the blocks of a tenant marked for deletion and, once they are gone, the rest of its objects.
+// It runs every retention cycle while the tenant is marked to catch the blocks flushed after it was marked and to
+// delete the blocks it marked compacted once they are past the compacted block retention.
+func (rw *readerWriter) deleteTenant(ctx context.Context, d *TenantDeletion) {
+	tenantID := d.TenantID
+	if !rw.compactorSharder.Owns(tenantDeletionJobPrefix + tenantID) {
+		return
+	}
+
+	if rw.compactorCfg.RetentionDryRun {
+		level.Info(rw.logger).Log("msg", "dry run: would delete tenant", "tenantID", tenantID, "blocks", len(rw.blocklist.Metas(tenantID))+len(rw.blocklist.CompactedMetas(tenantID)))
+		return
+	}
+
+	// the blocks are marked compacted first and deleted after the compacted block retention like any other block so
+	// the queriers with a stale blocklist don't fail on them
+	var (
+		now             = time.Now()
+		cutoff          = now.Add(-rw.compactorCfg.CompactedBlockRetention)
+		metas           = rw.blocklist.Metas(tenantID)
+		compactedMetas  = rw.blocklist.CompactedMetas(tenantID)
+		remove          = make([]*backend.BlockMeta, 0, len(metas))
+		compactedAdd    = make([]*backend.CompactedBlockMeta, 0, len(metas))
+		compactedRemove = make([]*backend.CompactedBlockMeta, 0, len(compactedMetas))
+	)
+
+	for _, b := range metas {
+		if ctx.Err() != nil {
+			break
+		}
+		if err := rw.c.MarkBlockCompacted(b.BlockID, tenantID); err != nil {
+			level.Error(rw.logger).Log("msg", "failed to mark block of tenant marked for deletion compacted", "blockID", b.BlockID, "tenantID", tenantID, "err", err)
+			metricTenantDeletionErrors.WithLabelValues(tenantID).Inc()
+			continue
+		}
+		remove = append(remove, b)
+		compactedAdd = append(compactedAdd, &backend.CompactedBlockMeta{BlockMeta: *b, CompactedTime: now})
+	}
+	for _, b := range compactedMetas {
+		if ctx.Err() != nil {
+			break
+		}
+		if b.CompactedTime.After(cutoff) {
+			continue
+		}
+		err := rw.c.ClearBlock(b.BlockID, tenantID)
+		if errors.Is(err, backend.ErrObjectLocked) {
+			// the block remains until the bucket lifecycle removes it
+			continue
+		}
+		if err != nil {
+			level.Error(rw.logger).Log("msg", "failed to delete block of tenant marked for deletion", "blockID", b.BlockID, "tenantID", tenantID, "err", err)
+			metricTenantDeletionErrors.WithLabelValues(tenantID).Inc()
+			continue
+		}
+		compactedRemove = append(compactedRemove, b)
+	}
+
+	if len(remove) > 0 || len(compactedRemove) > 0 {
+		rw.updateBlocklist(tenantID, nil, remove, compactedAdd, compactedRemove)
+	}
+
+	deleted := len(compactedRemove)
+	if deleted > 0 {
+		metricTenantDeletionBlocksDeleted.WithLabelValues(tenantID).Add(float64(deleted))
+	}
+
+	var (
+		remaining = len(metas) + len(compactedMetas) - deleted
+		objects   int
+		err       error
+	)
+	// the tenant index and any other object of the tenant are deleted after all of its blocks
+	if remaining == 0 {
+		objects, err = rw.deleteTenantObjects(ctx, tenantID)
+		if err != nil {
+			level.Error(rw.logger).Log("msg", "failed to delete objects of tenant marked for deletion", "tenantID", tenantID, "err", err)
+			metricTenantDeletionErrors.WithLabelValues(tenantID).Inc()
+		}
+	}
+
+	completed := remaining == 0 && err == nil && d.CompletedAt == nil
+	if deleted == 0 && objects == 0 && remaining == d.BlocksRemaining && !completed {
+		return
+	}
+
+	now = now.UTC()
+	d.UpdatedAt = now
+	d.BlocksDeleted += deleted
+	d.BlocksRemaining = remaining
+	d.ObjectsDeleted += objects
+	if completed {
+		d.CompletedAt = &now
+		level.Info(rw.logger).Log("msg", "deleted tenant", "tenantID", tenantID, "blocks", d.BlocksDeleted, "objects", d.ObjectsDeleted)
+	}
+
+	if err := rw.writeTenantDeletion(ctx, d); err != nil {
+		level.Error(rw.logger).Log("msg", "failed to update tenant deletion", "tenantID", tenantID, "err", err)
+		metricTenantDeletionErrors.WithLabelValues(tenantID).Inc()
+	}
+}
+
+// deleteTenantObjects deletes all the objects under the tenant prefix and returns how many were deleted
+func (rw *readerWriter) deleteTenantObjects(ctx context.Context, tenantID string) (int, error) {
+	var objects []string
+	err := rw.rawR.Find(ctx, backend.KeyPath{tenantID}, func(m backend.FindMatch) {
+		objects = append(objects, m.Key)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	for i, object := range objects {
+		dir, name := path.Split(object)
+		err = rw.rawW.Delete(ctx, name, backend.KeyPath{dir}, nil)
+		if err != nil {
+			return i, err
+		}
+	}
+	return len(objects), nil
+}
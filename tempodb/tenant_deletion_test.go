@@ -0,0 +1,177 @@
+package tempodb
+
+import (
+	"bytes"
+	"context"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/pkg/model"
+	"github.com/grafana/tempo/pkg/util/test"
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/backend/local"
+	"github.com/grafana/tempo/tempodb/backend/routing"
+	"github.com/grafana/tempo/tempodb/encoding"
+	"github.com/grafana/tempo/tempodb/encoding/common"
+	"github.com/grafana/tempo/tempodb/wal"
+)
+
+func TestTenantDeletion(t *testing.T) {
+	tempDir := t.TempDir()
+
+	r, w, c, err := New(&Config{
+		Backend: backend.Local,
+		Local: &local.Config{
+			Path: path.Join(tempDir, "traces"),
+		},
+		Block: &common.BlockConfig{
+			IndexDownsampleBytes: 17,
+			BloomFP:              0.01,
+			BloomShardSizeBytes:  100_000,
+			Version:              encoding.DefaultEncoding().Version(),
+			Encoding:             backend.EncLZ4_256k,
+			IndexPageSizeBytes:   1000,
+		},
+		WAL: &wal.Config{
+			Filepath: path.Join(tempDir, "wal"),
+		},
+		BlocklistPoll: 0,
+	}, nil, log.NewNopLogger())
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	err = c.EnableCompaction(ctx, &CompactorConfig{
+		ChunkSizeBytes:          10,
+		MaxCompactionRange:      time.Hour,
+		BlockRetention:          time.Hour,
+		CompactedBlockRetention: time.Hour,
+	}, &mockSharder{}, &mockOverrides{})
+	require.NoError(t, err)
+
+	rw := r.(*readerWriter)
+
+	writeBlock := func(tenantID string) uuid.UUID {
+		head, err := w.WAL().NewBlock(&backend.BlockMeta{BlockID: uuid.New(), TenantID: tenantID}, model.CurrentEncoding)
+		require.NoError(t, err)
+		complete, err := w.CompleteBlock(ctx, head)
+		require.NoError(t, err)
+		return complete.BlockMeta().BlockID
+	}
+
+	deletedBlockID := writeBlock(testTenantID)
+	writeBlock(testTenantID2)
+	require.NoError(t, w.AddTraceAnnotation(ctx, testTenantID, test.ValidTraceID(nil), &Annotation{Text: "deleted"}))
+
+	r.EnablePolling(ctx, &mockJobSharder{})
+	require.Len(t, rw.blocklist.Metas(testTenantID), 1)
+
+	_, err = r.TenantDeletion(ctx, testTenantID)
+	require.ErrorIs(t, err, backend.ErrDoesNotExist)
+	deletions, err := r.TenantDeletions(ctx)
+	require.NoError(t, err)
+	require.Empty(t, deletions)
+
+	d, err := w.MarkTenantForDeletion(ctx, testTenantID)
+	require.NoError(t, err)
+	require.Equal(t, testTenantID, d.TenantID)
+	require.Nil(t, d.CompletedAt)
+
+	// marking again returns the existing deletion
+	again, err := w.MarkTenantForDeletion(ctx, testTenantID)
+	require.NoError(t, err)
+	require.Equal(t, d.RequestedAt.Unix(), again.RequestedAt.Unix())
+
+	deletions, err = r.TenantDeletions(ctx)
+	require.NoError(t, err)
+	require.Len(t, deletions, 1)
+
+	// the deletion marks aren't a tenant
+	rw.pollBlocklist()
+	require.ElementsMatch(t, []string{testTenantID, testTenantID2}, rw.blocklist.Tenants())
+
+	// the block is marked compacted first
+	rw.doRetention(ctx)
+
+	d, err = r.TenantDeletion(ctx, testTenantID)
+	require.NoError(t, err)
+	require.Equal(t, 0, d.BlocksDeleted)
+	require.Equal(t, 1, d.BlocksRemaining)
+	require.Nil(t, d.CompletedAt)
+
+	require.Empty(t, rw.blocklist.Metas(testTenantID))
+	compacted := rw.blocklist.CompactedMetas(testTenantID)
+	require.Len(t, compacted, 1)
+	require.Equal(t, deletedBlockID, compacted[0].BlockID)
+	_, err = rw.r.BlockMeta(ctx, deletedBlockID, testTenantID)
+	require.ErrorIs(t, err, backend.ErrDoesNotExist)
+
+	// and deleted once it's past the compacted block retention
+	old := *compacted[0]
+	old.CompactedTime = time.Now().Add(-2 * time.Hour)
+	rw.updateBlocklist(testTenantID, nil, nil, nil, compacted)
+	rw.updateBlocklist(testTenantID, nil, nil, []*backend.CompactedBlockMeta{&old}, nil)
+
+	rw.doRetention(ctx)
+
+	d, err = r.TenantDeletion(ctx, testTenantID)
+	require.NoError(t, err)
+	require.Equal(t, 1, d.BlocksDeleted)
+	require.Equal(t, 0, d.BlocksRemaining)
+	require.NotZero(t, d.ObjectsDeleted)
+	require.NotNil(t, d.CompletedAt)
+
+	require.Empty(t, rw.blocklist.CompactedMetas(testTenantID))
+
+	objects := func(tenantID string) []string {
+		var keys []string
+		require.NoError(t, rw.rawR.Find(ctx, backend.KeyPath{tenantID}, func(m backend.FindMatch) {
+			keys = append(keys, m.Key)
+		}))
+		return keys
+	}
+	require.Empty(t, objects(testTenantID))
+
+	// other tenants are kept
+	require.NotEmpty(t, objects(testTenantID2))
+	require.Equal(t, 1, len(rw.blocklist.Metas(testTenantID2))+len(rw.blocklist.CompactedMetas(testTenantID2)))
+}
+
+func TestDeleteTenantObjectsRouted(t *testing.T) {
+	newLocalBackend := func() routing.Backend {
+		r, w, c, err := local.New(&local.Config{Path: t.TempDir()})
+		require.NoError(t, err)
+		return routing.Backend{Reader: r, Writer: w, Compactor: c}
+	}
+	defaultBackend := newLocalBackend()
+	routedBackend := newLocalBackend()
+
+	r, w, _ := routing.New(defaultBackend, map[string]routing.Backend{testTenantID: routedBackend})
+	rw := &readerWriter{rawR: r, rawW: w}
+	ctx := context.Background()
+
+	for _, tenantID := range []string{testTenantID, testTenantID2} {
+		keypath := backend.KeyPathForBlock(uuid.New(), tenantID)
+		require.NoError(t, w.Write(ctx, backend.MetaName, keypath, bytes.NewReader([]byte("{}")), 2, nil))
+	}
+
+	deleted, err := rw.deleteTenantObjects(ctx, testTenantID)
+	require.NoError(t, err)
+	require.Equal(t, 1, deleted)
+
+	objects := func(b routing.Backend, tenantID string) []string {
+		var keys []string
+		require.NoError(t, b.Reader.Find(ctx, backend.KeyPath{tenantID}, func(m backend.FindMatch) {
+			keys = append(keys, m.Key)
+		}))
+		return keys
+	}
+
+	// the objects are deleted from the backend of the routed tenant
+	require.Empty(t, objects(routedBackend, testTenantID))
+	require.NotEmpty(t, objects(defaultBackend, testTenantID2))
+}
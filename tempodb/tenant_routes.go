@@ -0,0 +1,144 @@
+package tempodb
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/backend/azure"
+	"github.com/grafana/tempo/tempodb/backend/gcs"
+	"github.com/grafana/tempo/tempodb/backend/local"
+	"github.com/grafana/tempo/tempodb/backend/routing"
+	"github.com/grafana/tempo/tempodb/backend/s3"
+	"github.com/grafana/tempo/tempodb/backend/swift"
+)
+
+// TenantRoute stores the data of a tenant in its own bucket or under its own prefix of the configured backend
+type TenantRoute struct {
+	Tenant string `yaml:"tenant"`
+	// Bucket replaces the bucket of s3, the bucket name of gcs, the container name of azure and swift and the path
+	// of the local backend. Empty keeps the configured bucket
+	Bucket string `yaml:"bucket"`
+	// Prefix replaces the prefix of the object store backends. Empty keeps the configured prefix
+	Prefix string `yaml:"prefix"`
+}
+
+func validateTenantRoutes(backendName string, routes []TenantRoute) error {
+	tenants := make(map[string]struct{}, len(routes))
+	for _, route := range routes {
+		if route.Tenant == "" {
+			return errors.New("tenant must be set")
+		}
+		if _, ok := tenants[route.Tenant]; ok {
+			return fmt.Errorf("tenant %s is routed more than once", route.Tenant)
+		}
+		tenants[route.Tenant] = struct{}{}
+
+		if route.Bucket == "" && route.Prefix == "" {
+			return fmt.Errorf("tenant %s must be routed to a bucket or a prefix", route.Tenant)
+		}
+		if backendName == backend.Local && route.Prefix != "" {
+			return fmt.Errorf("tenant %s can't be routed to a prefix of the local backend", route.Tenant)
+		}
+	}
+	return nil
+}
+
+// routed returns true if the tenant is routed to its own bucket or prefix
+func (cfg *Config) routed(tenantID string) bool {
+	for _, route := range cfg.TenantRoutes {
+		if route.Tenant == tenantID {
+			return true
+		}
+	}
+	return false
+}
+
+// newBackend creates the backend of the config
+func newBackend(cfg *Config) (backend.RawReader, backend.RawWriter, backend.Compactor, error) {
+	switch cfg.Backend {
+	case backend.Local:
+		return local.New(cfg.Local)
+	case backend.GCS:
+		return gcs.New(cfg.GCS)
+	case backend.S3:
+		return s3.New(cfg.S3)
+	case backend.Azure:
+		return azure.New(cfg.Azure)
+	case backend.Swift:
+		return swift.New(cfg.Swift)
+	default:
+		return nil, nil, nil, fmt.Errorf("unknown backend %s", cfg.Backend)
+	}
+}
+
+// newRoutedBackend creates the backends of the tenant routes and routes the other tenants to the backend
+func newRoutedBackend(cfg *Config, r backend.RawReader, w backend.RawWriter, c backend.Compactor) (backend.RawReader, backend.RawWriter, backend.Compactor, error) {
+	if len(cfg.TenantRoutes) == 0 {
+		return r, w, c, nil
+	}
+
+	tenants := make(map[string]routing.Backend, len(cfg.TenantRoutes))
+	for _, route := range cfg.TenantRoutes {
+		tenantR, tenantW, tenantC, err := newBackend(routeConfig(cfg, route))
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("error creating backend of tenant %s: %w", route.Tenant, err)
+		}
+		tenants[route.Tenant] = routing.Backend{Reader: tenantR, Writer: tenantW, Compactor: tenantC}
+	}
+
+	r, w, c = routing.New(routing.Backend{Reader: r, Writer: w, Compactor: c}, tenants)
+	return r, w, c, nil
+}
+
+// routeConfig returns a copy of the config with the bucket and prefix of the route
+func routeConfig(cfg *Config, route TenantRoute) *Config {
+	routed := *cfg
+
+	switch cfg.Backend {
+	case backend.Local:
+		localCfg := *cfg.Local
+		if route.Bucket != "" {
+			localCfg.Path = route.Bucket
+		}
+		routed.Local = &localCfg
+	case backend.GCS:
+		gcsCfg := *cfg.GCS
+		if route.Bucket != "" {
+			gcsCfg.BucketName = route.Bucket
+		}
+		if route.Prefix != "" {
+			gcsCfg.Prefix = route.Prefix
+		}
+		routed.GCS = &gcsCfg
+	case backend.S3:
+		s3Cfg := *cfg.S3
+		if route.Bucket != "" {
+			s3Cfg.Bucket = route.Bucket
+		}
+		if route.Prefix != "" {
+			s3Cfg.Prefix = route.Prefix
+		}
+		routed.S3 = &s3Cfg
+	case backend.Azure:
+		azureCfg := *cfg.Azure
+		if route.Bucket != "" {
+			azureCfg.ContainerName = route.Bucket
+		}
+		if route.Prefix != "" {
+			azureCfg.Prefix = route.Prefix
+		}
+		routed.Azure = &azureCfg
+	case backend.Swift:
+		swiftCfg := *cfg.Swift
+		if route.Bucket != "" {
+			swiftCfg.ContainerName = route.Bucket
+		}
+		if route.Prefix != "" {
+			swiftCfg.Prefix = route.Prefix
+		}
+		routed.Swift = &swiftCfg
+	}
+
+	return &routed
+}
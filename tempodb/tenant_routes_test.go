@@ -0,0 +1,110 @@
+package tempodb
+
+import (
+	"context"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/pkg/model"
+	"github.com/grafana/tempo/pkg/util/test"
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/encoding/common"
+)
+
+func TestValidateTenantRoutes(t *testing.T) {
+	tests := []struct {
+		name    string
+		backend string
+		routes  []TenantRoute
+		err     string
+	}{
+		{
+			name:    "valid",
+			backend: backend.S3,
+			routes:  []TenantRoute{{Tenant: "a", Bucket: "bucket-a"}, {Tenant: "b", Prefix: "b"}},
+		},
+		{
+			name:    "no tenant",
+			backend: backend.S3,
+			routes:  []TenantRoute{{Bucket: "bucket"}},
+			err:     "tenant must be set",
+		},
+		{
+			name:    "duplicate tenant",
+			backend: backend.S3,
+			routes:  []TenantRoute{{Tenant: "a", Bucket: "bucket-a"}, {Tenant: "a", Prefix: "a"}},
+			err:     "tenant a is routed more than once",
+		},
+		{
+			name:    "no bucket or prefix",
+			backend: backend.GCS,
+			routes:  []TenantRoute{{Tenant: "a"}},
+			err:     "tenant a must be routed to a bucket or a prefix",
+		},
+		{
+			name:    "local prefix",
+			backend: backend.Local,
+			routes:  []TenantRoute{{Tenant: "a", Prefix: "a"}},
+			err:     "tenant a can't be routed to a prefix of the local backend",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateTenantRoutes(tc.backend, tc.routes)
+			if tc.err == "" {
+				require.NoError(t, err)
+			} else {
+				require.EqualError(t, err, tc.err)
+			}
+		})
+	}
+}
+
+func TestTenantRoutes(t *testing.T) {
+	routedPath := path.Join(t.TempDir(), "routed")
+	r, w, _, tempDir := testConfig(t, backend.EncNone, 0, func(cfg *Config) {
+		cfg.TenantRoutes = []TenantRoute{{Tenant: "routed", Bucket: routedPath}}
+	})
+	r.EnablePolling(context.Background(), &mockJobSharder{})
+	rw := r.(*readerWriter)
+
+	now := uint32(time.Now().Unix())
+	ids := map[string]common.ID{}
+	for _, tenant := range []string{testTenantID, "routed"} {
+		id := test.ValidTraceID(nil)
+		ids[tenant] = id
+		writeTestBlock(t, w, tenant, id, now)
+	}
+
+	// the blocks of the routed tenant are written to its bucket
+	_, err := os.Stat(path.Join(tempDir, "traces", "routed"))
+	require.True(t, os.IsNotExist(err))
+	_, err = os.Stat(path.Join(routedPath, "routed"))
+	require.NoError(t, err)
+
+	rw.pollBlocklist()
+	require.ElementsMatch(t, []string{testTenantID, "routed"}, rw.blocklist.Tenants())
+
+	for tenant, id := range ids {
+		require.Len(t, rw.blocklist.Metas(tenant), 1)
+
+		traces, failedBlocks, err := r.Find(context.Background(), tenant, id, BlockIDMin, BlockIDMax, 0, 0, common.DefaultSearchOptions())
+		require.NoError(t, err)
+		require.Empty(t, failedBlocks)
+		require.Len(t, traces, 1)
+	}
+}
+
+func writeTestBlock(t *testing.T, w Writer, tenantID string, id common.ID, ts uint32) {
+	head, err := w.WAL().NewBlock(&backend.BlockMeta{BlockID: uuid.New(), TenantID: tenantID}, model.CurrentEncoding)
+	require.NoError(t, err)
+	writeTraceToWal(t, head, model.MustNewSegmentDecoder(model.CurrentEncoding), id, test.MakeTrace(2, id), ts, ts)
+	_, err = w.CompleteBlock(context.Background(), head)
+	require.NoError(t, err)
+}
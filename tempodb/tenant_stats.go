@@ -0,0 +1,44 @@
+package tempodb
+
+import (
+	"sort"
+	"time"
+)
+
+// TenantStats summarizes the blocks of a tenant in the blocklist
+type TenantStats struct {
+	TenantID        string    `json:"tenantID"`
+	Blocks          int       `json:"blocks"`
+	CompactedBlocks int       `json:"compactedBlocks"`
+	Bytes           uint64    `json:"bytes"`
+	OldestBlock     time.Time `json:"oldestBlock"`
+	NewestBlock     time.Time `json:"newestBlock"`
+}
+
+// TenantStats returns the stats of all the tenants with blocks in the blocklist sorted by ID. OldestBlock is the start
+// time of the oldest block and NewestBlock the end time of the newest block.
+func (rw *readerWriter) TenantStats() []*TenantStats {
+	tenants := rw.blocklist.Tenants()
+
+	stats := make([]*TenantStats, 0, len(tenants))
+	for _, tenantID := range tenants {
+		s := &TenantStats{
+			TenantID:        tenantID,
+			CompactedBlocks: len(rw.blocklist.CompactedMetas(tenantID)),
+		}
+		for _, m := range rw.blocklist.Metas(tenantID) {
+			s.Blocks++
+			s.Bytes += m.Size
+			if s.OldestBlock.IsZero() || m.StartTime.Before(s.OldestBlock) {
+				s.OldestBlock = m.StartTime
+			}
+			if m.EndTime.After(s.NewestBlock) {
+				s.NewestBlock = m.EndTime
+			}
+		}
+		stats = append(stats, s)
+	}
+
+	sort.Slice(stats, func(i, j int) bool { return stats[i].TenantID < stats[j].TenantID })
+	return stats
+}
@@ -0,0 +1,37 @@
+package tempodb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/blocklist"
+)
+
+func TestTenantStats(t *testing.T) {
+	r, _, _, _ := testConfig(t, backend.EncNone, 0)
+	rw := r.(*readerWriter)
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	rw.blocklist.ApplyPollResults(blocklist.PerTenant{
+		"b": {
+			{BlockID: uuid.New(), TenantID: "b", StartTime: start.Add(time.Hour), EndTime: start.Add(3 * time.Hour), Size: 10},
+			{BlockID: uuid.New(), TenantID: "b", StartTime: start, EndTime: start.Add(2 * time.Hour), Size: 20},
+		},
+		"a": {
+			{BlockID: uuid.New(), TenantID: "a", StartTime: start, EndTime: start.Add(time.Hour), Size: 5},
+		},
+	}, blocklist.PerTenantCompacted{
+		"b": {
+			{BlockMeta: backend.BlockMeta{BlockID: uuid.New(), TenantID: "b"}},
+		},
+	})
+
+	require.Equal(t, []*TenantStats{
+		{TenantID: "a", Blocks: 1, Bytes: 5, OldestBlock: start, NewestBlock: start.Add(time.Hour)},
+		{TenantID: "b", Blocks: 2, CompactedBlocks: 1, Bytes: 30, OldestBlock: start, NewestBlock: start.Add(3 * time.Hour)},
+	}, rw.TenantStats())
+}
@@ -0,0 +1,446 @@
+package tempodb
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-kit/log/level"
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/grafana/tempo/pkg/tempopb"
+	"github.com/grafana/tempo/pkg/util"
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/encoding"
+	"github.com/grafana/tempo/tempodb/encoding/common"
+)
+
+const (
+	// traceTombstonesDir holds an object for every request to delete traces of the tenant. A request is written once by
+	// the instance that received it and only updated by the compactor that owns the tombstones of the tenant, so
+	// requests added concurrently through different instances never overwrite each other.
+	traceTombstonesDir = "tombstones"
+
+	// the tombstoned traces of a tenant are removed from its blocks by the compactor that owns this job
+	traceTombstonesJobPrefix = "trace-tombstones-"
+
+	// how long the tombstones read by the query path and the compactions are cached
+	traceTombstonesCacheTTL = time.Minute
+
+	// tombstones are only marked deleted after this long so the traces held by the ingesters when the tombstone
+	// was added are flushed and removed too
+	traceTombstonesFlushGrace = time.Hour
+
+	// blocks created while the blocks of a tenant are searched for tombstoned traces are searched too. a tombstone
+	// isn't marked deleted while new blocks keep appearing after this many passes
+	traceTombstonesMaxPasses = 3
+)
+
+var (
+	metricTraceTombstonesRewrittenBlocks = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "tempodb",
+		Name:      "trace_tombstones_rewritten_blocks_total",
+		Help:      "Total number of blocks rewritten to remove tombstoned traces.",
+	})
+	metricTraceTombstonesErrors = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "tempodb",
+		Name:      "trace_tombstones_errors_total",
+		Help:      "Total number of times an error occurred while removing tombstoned traces from blocks.",
+	})
+)
+
+// TraceTombstone records the request to delete a trace. Tombstoned traces are filtered from query results, dropped by
+// compactions and removed from the blocks by the compactors.
+type TraceTombstone struct {
+	TraceID     string     `json:"traceID"`
+	RequestedAt time.Time  `json:"requestedAt"`
+	DeletedAt   *time.Time `json:"deletedAt,omitempty"`
+}
+
+// traceTombstonesRequest is the object written for a request to delete traces
+type traceTombstonesRequest struct {
+	name string
+
+	TraceIDs    []string   `json:"traceIDs"`
+	RequestedAt time.Time  `json:"requestedAt"`
+	DeletedAt   *time.Time `json:"deletedAt,omitempty"`
+}
+
+type cachedTraceTombstones struct {
+	traceIDs map[string]struct{}
+	expires  time.Time
+}
+
+// TraceTombstones returns the tombstones of the tenant in the order they were added. A trace deleted by several
+// requests has one tombstone, which is marked deleted once all of the requests are.
+func (rw *readerWriter) TraceTombstones(ctx context.Context, tenantID string) ([]*TraceTombstone, error) {
+	requests, err := rw.readTraceTombstonesRequests(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	return traceTombstonesOf(requests), nil
+}
+
+// AddTraceTombstones writes a request to delete the traces
+func (rw *readerWriter) AddTraceTombstones(ctx context.Context, tenantID string, traceIDs []common.ID) error {
+	now := time.Now().UTC()
+	req := &traceTombstonesRequest{
+		// requests are named by time so they are listed in the order they were added
+		name:        fmt.Sprintf("%020d-%s.json", now.UnixNano(), uuid.New()),
+		RequestedAt: now,
+	}
+
+	seen := make(map[string]struct{}, len(traceIDs))
+	for _, id := range traceIDs {
+		traceID := util.TraceIDToHexString(id)
+		if _, ok := seen[traceID]; ok {
+			continue
+		}
+		seen[traceID] = struct{}{}
+		req.TraceIDs = append(req.TraceIDs, traceID)
+	}
+
+	if err := rw.writeTraceTombstonesRequest(ctx, tenantID, req); err != nil {
+		return err
+	}
+
+	// the tombstones are filtered right away by this instance
+	requests, err := rw.readTraceTombstonesRequests(ctx, tenantID)
+	if err != nil {
+		level.Warn(rw.logger).Log("msg", "failed to refresh trace tombstones", "tenantID", tenantID, "err", err)
+		rw.tombstonesCacheMtx.Lock()
+		delete(rw.tombstonesCache, tenantID)
+		rw.tombstonesCacheMtx.Unlock()
+		return nil
+	}
+	rw.cacheTraceTombstones(tenantID, traceTombstonesOf(requests), true)
+	return nil
+}
+
+// TraceTombstoned returns true if the trace has a tombstone. The tombstones are cached for a minute so tombstones
+// added through other instances are filtered after up to a minute. Concurrent lookups of a tenant share one read of
+// the tombstones and don't block the lookups of other tenants.
+func (rw *readerWriter) TraceTombstoned(ctx context.Context, tenantID string, traceID common.ID) (bool, error) {
+	cached, err := rw.cachedTraceTombstones(ctx, tenantID)
+	if err != nil {
+		return false, err
+	}
+
+	_, ok := cached.traceIDs[util.TraceIDToHexString(traceID)]
+	return ok, nil
+}
+
+// TraceTombstonesCount returns the number of tombstones of the tenant. Tombstones are never removed so the count only
+// grows, which makes it usable as a version of the tombstones. It is cached like TraceTombstoned.
+func (rw *readerWriter) TraceTombstonesCount(ctx context.Context, tenantID string) (int, error) {
+	cached, err := rw.cachedTraceTombstones(ctx, tenantID)
+	if err != nil {
+		return 0, err
+	}
+
+	return len(cached.traceIDs), nil
+}
+
+// tombstonedTracesFilter returns a func that returns true for the tombstoned traces of the tenant, or nil if the
+// tenant has no tombstones. Compactions drop the traces it returns true for.
+func (rw *readerWriter) tombstonedTracesFilter(ctx context.Context, tenantID string) (func(common.ID) bool, error) {
+	cached, err := rw.cachedTraceTombstones(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if len(cached.traceIDs) == 0 {
+		return nil, nil
+	}
+
+	return func(id common.ID) bool {
+		_, ok := cached.traceIDs[util.TraceIDToHexString(id)]
+		return ok
+	}, nil
+}
+
+// cachedTraceTombstones returns the cached tombstones of the tenant and reads them if they are missing or expired
+func (rw *readerWriter) cachedTraceTombstones(ctx context.Context, tenantID string) (*cachedTraceTombstones, error) {
+	rw.tombstonesCacheMtx.Lock()
+	cached, ok := rw.tombstonesCache[tenantID]
+	rw.tombstonesCacheMtx.Unlock()
+
+	if ok && time.Now().Before(cached.expires) {
+		return cached, nil
+	}
+
+	v, err, _ := rw.tombstonesReads.Do(tenantID, func() (interface{}, error) {
+		requests, err := rw.readTraceTombstonesRequests(ctx, tenantID)
+		if err != nil {
+			return nil, err
+		}
+		return rw.cacheTraceTombstones(tenantID, traceTombstonesOf(requests), false), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.(*cachedTraceTombstones), nil
+}
+
+// cacheTraceTombstones caches the tombstones of the tenant and returns them. Unless replace is set, an unexpired entry
+// is kept so a read that started before tombstones were added doesn't overwrite them
+func (rw *readerWriter) cacheTraceTombstones(tenantID string, tombstones []*TraceTombstone, replace bool) *cachedTraceTombstones {
+	now := time.Now()
+	cached := &cachedTraceTombstones{
+		traceIDs: make(map[string]struct{}, len(tombstones)),
+		expires:  now.Add(traceTombstonesCacheTTL),
+	}
+	for _, t := range tombstones {
+		cached.traceIDs[t.TraceID] = struct{}{}
+	}
+
+	rw.tombstonesCacheMtx.Lock()
+	defer rw.tombstonesCacheMtx.Unlock()
+
+	if existing, ok := rw.tombstonesCache[tenantID]; ok && !replace && now.Before(existing.expires) {
+		return existing
+	}
+	rw.tombstonesCache[tenantID] = cached
+	return cached
+}
+
+// traceTombstonesOf returns the tombstones of the traces of the requests
+func traceTombstonesOf(requests []*traceTombstonesRequest) []*TraceTombstone {
+	var (
+		tombstones []*TraceTombstone
+		byTraceID  = map[string]*TraceTombstone{}
+	)
+	for _, req := range requests {
+		for _, traceID := range req.TraceIDs {
+			if t, ok := byTraceID[traceID]; ok {
+				if req.DeletedAt == nil {
+					t.DeletedAt = nil
+				}
+				continue
+			}
+
+			t := &TraceTombstone{TraceID: traceID, RequestedAt: req.RequestedAt, DeletedAt: req.DeletedAt}
+			byTraceID[traceID] = t
+			tombstones = append(tombstones, t)
+		}
+	}
+	return tombstones
+}
+
+// readTraceTombstonesRequests returns the requests to delete traces of the tenant in the order they were added
+func (rw *readerWriter) readTraceTombstonesRequests(ctx context.Context, tenantID string) ([]*traceTombstonesRequest, error) {
+	keypath := backend.KeyPath{tenantID, traceTombstonesDir}
+
+	var names []string
+	err := rw.rawR.Find(ctx, keypath, func(m backend.FindMatch) {
+		if name := path.Base(m.Key); strings.HasSuffix(name, ".json") {
+			names = append(names, name)
+		}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error listing trace tombstones: %w", err)
+	}
+	sort.Strings(names)
+
+	requests := make([]*traceTombstonesRequest, 0, len(names))
+	for _, name := range names {
+		reader, _, err := rw.rawR.Read(ctx, name, keypath, nil)
+		if errors.Is(err, backend.ErrDoesNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error reading trace tombstones %s: %w", name, err)
+		}
+
+		buff, err := io.ReadAll(reader)
+		reader.Close()
+		if err != nil {
+			return nil, fmt.Errorf("error reading trace tombstones %s: %w", name, err)
+		}
+
+		req := &traceTombstonesRequest{name: name}
+		if err := json.Unmarshal(buff, req); err != nil {
+			return nil, fmt.Errorf("error unmarshalling trace tombstones %s: %w", name, err)
+		}
+		requests = append(requests, req)
+	}
+	return requests, nil
+}
+
+func (rw *readerWriter) writeTraceTombstonesRequest(ctx context.Context, tenantID string, req *traceTombstonesRequest) error {
+	buff, err := json.Marshal(req)
+	if err != nil {
+		return fmt.Errorf("error marshalling trace tombstones: %w", err)
+	}
+
+	return rw.rawW.Write(ctx, req.name, backend.KeyPath{tenantID, traceTombstonesDir}, bytes.NewReader(buff), int64(len(buff)), nil)
+}
+
+// removeTombstonedTraces rewrites the blocks of the tenant that hold traces of requests that aren't marked deleted
+// yet. A request is marked deleted once no block holds its traces. Compactions drop the tombstoned traces too, the
+// blocks they create while the blocks are searched are searched as well.
+func (rw *readerWriter) removeTombstonedTraces(ctx context.Context, tenantID string) {
+	if !rw.compactorSharder.Owns(traceTombstonesJobPrefix + tenantID) {
+		return
+	}
+
+	requests, err := rw.readTraceTombstonesRequests(ctx, tenantID)
+	if err != nil {
+		level.Error(rw.logger).Log("msg", "failed to read trace tombstones", "tenantID", tenantID, "err", err)
+		metricTraceTombstonesErrors.Inc()
+		return
+	}
+
+	var (
+		pending   = map[string]common.ID{}
+		tombstone = map[string]struct{}{}
+	)
+	for _, req := range requests {
+		for _, traceID := range req.TraceIDs {
+			tombstone[traceID] = struct{}{}
+			if req.DeletedAt != nil {
+				continue
+			}
+			id, err := util.HexStringToTraceID(traceID)
+			if err != nil {
+				continue
+			}
+			pending[traceID] = id
+		}
+	}
+	if len(pending) == 0 {
+		return
+	}
+
+	var (
+		dryRun   = rw.compactorCfg.RetentionDryRun
+		failed   = false
+		searched = map[uuid.UUID]struct{}{}
+	)
+	for pass := 0; ; pass++ {
+		var blocks []*backend.BlockMeta
+		for _, b := range rw.blocklist.Metas(tenantID) {
+			if _, ok := searched[b.BlockID]; !ok {
+				blocks = append(blocks, b)
+			}
+		}
+		if len(blocks) == 0 {
+			break
+		}
+		if pass == traceTombstonesMaxPasses {
+			// new blocks keep appearing, they are searched on the next cycle
+			failed = true
+			break
+		}
+
+		for _, b := range blocks {
+			if ctx.Err() != nil {
+				return
+			}
+			searched[b.BlockID] = struct{}{}
+
+			found, err := rw.blockHoldsTraces(ctx, b, pending)
+			if err != nil {
+				level.Error(rw.logger).Log("msg", "failed to search block for tombstoned traces", "blockID", b.BlockID, "tenantID", tenantID, "err", err)
+				metricTraceTombstonesErrors.Inc()
+				failed = true
+				continue
+			}
+			if !found {
+				continue
+			}
+
+			if dryRun {
+				level.Info(rw.logger).Log("msg", "dry run: would rewrite block to remove tombstoned traces", "blockID", b.BlockID, "tenantID", tenantID)
+				failed = true
+				continue
+			}
+
+			// a block claimed by a compaction is searched again on the next cycle
+			if !rw.claimBlockForRewrite(ctx, tenantID, b) {
+				failed = true
+				continue
+			}
+			err = rw.removeTracesFromBlock(ctx, tenantID, b, func(id common.ID) bool {
+				_, ok := tombstone[util.TraceIDToHexString(id)]
+				return ok
+			})
+			rw.releaseBlocks([]*backend.BlockMeta{b})
+			if err != nil {
+				level.Error(rw.logger).Log("msg", "failed to rewrite block to remove tombstoned traces", "blockID", b.BlockID, "tenantID", tenantID, "err", err)
+				metricTraceTombstonesErrors.Inc()
+				failed = true
+				continue
+			}
+
+			metricTraceTombstonesRewrittenBlocks.Inc()
+		}
+	}
+
+	if failed {
+		return
+	}
+
+	// all blocks were searched and rewritten
+	now := time.Now().UTC()
+	for _, req := range requests {
+		if req.DeletedAt != nil || now.Sub(req.RequestedAt) < traceTombstonesFlushGrace {
+			continue
+		}
+
+		req.DeletedAt = &now
+		if err := rw.writeTraceTombstonesRequest(ctx, tenantID, req); err != nil {
+			level.Error(rw.logger).Log("msg", "failed to update trace tombstones", "tenantID", tenantID, "err", err)
+			metricTraceTombstonesErrors.Inc()
+		}
+	}
+}
+
+// removeTracesFromBlock replaces the block with a block without the traces drop returns true for. Blocks of versions
+// that can't be rewritten are compacted on their own, which drops the tombstoned traces of the tenant.
+func (rw *readerWriter) removeTracesFromBlock(ctx context.Context, tenantID string, meta *backend.BlockMeta, drop func(common.ID) bool) error {
+	_, dropped, err := rw.rewriteBlock(ctx, tenantID, meta, false, func(id common.ID, _ *tempopb.Trace) bool {
+		return !drop(id)
+	})
+	if errors.Is(err, errRewriteUnsupported) {
+		err = rw.compact(ctx, []*backend.BlockMeta{meta}, tenantID)
+		if err != nil {
+			return err
+		}
+		level.Info(rw.logger).Log("msg", "compacted block to remove tombstoned traces", "blockID", meta.BlockID, "tenantID", tenantID)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	level.Info(rw.logger).Log("msg", "rewrote block to remove tombstoned traces", "blockID", meta.BlockID, "tenantID", tenantID, "dropped", dropped)
+	return nil
+}
+
+// blockHoldsTraces returns true if the block holds any of the traces
+func (rw *readerWriter) blockHoldsTraces(ctx context.Context, meta *backend.BlockMeta, traceIDs map[string]common.ID) (bool, error) {
+	block, err := encoding.OpenBlock(meta, rw.r)
+	if err != nil {
+		return false, err
+	}
+
+	for _, id := range traceIDs {
+		tr, err := block.FindTraceByID(ctx, id, common.DefaultSearchOptions())
+		if err != nil {
+			return false, err
+		}
+		if tr != nil {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,262 @@
+package tempodb
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/pkg/util"
+	"github.com/grafana/tempo/pkg/util/test"
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/backend/local"
+	"github.com/grafana/tempo/tempodb/encoding"
+	"github.com/grafana/tempo/tempodb/encoding/common"
+	"github.com/grafana/tempo/tempodb/wal"
+)
+
+func TestTraceTombstones(t *testing.T) {
+	// blocks of versions that can't be rewritten are compacted on their own
+	for _, enc := range encoding.AllEncodings() {
+		t.Run(enc.Version(), func(t *testing.T) {
+			testTraceTombstones(t, enc.Version())
+		})
+	}
+}
+
+func testTraceTombstones(t *testing.T, version string) {
+	tempDir := t.TempDir()
+
+	r, w, c, err := New(&Config{
+		Backend: backend.Local,
+		Local: &local.Config{
+			Path: path.Join(tempDir, "traces"),
+		},
+		Block: &common.BlockConfig{
+			IndexDownsampleBytes: 17,
+			BloomFP:              0.01,
+			BloomShardSizeBytes:  100_000,
+			Version:              version,
+			Encoding:             backend.EncLZ4_256k,
+			IndexPageSizeBytes:   1000,
+			RowGroupSizeBytes:    30_000_000,
+		},
+		WAL: &wal.Config{
+			Filepath: path.Join(tempDir, "wal"),
+		},
+		BlocklistPoll: 0,
+	}, nil, log.NewNopLogger())
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	err = c.EnableCompaction(ctx, &CompactorConfig{
+		ChunkSizeBytes:          10,
+		MaxCompactionRange:      time.Hour,
+		BlockRetention:          24 * time.Hour,
+		CompactedBlockRetention: time.Hour,
+	}, &mockSharder{}, &mockOverrides{})
+	require.NoError(t, err)
+
+	r.EnablePolling(ctx, &mockJobSharder{})
+	rw := r.(*readerWriter)
+
+	deletedID := test.ValidTraceID(nil)
+	keptID := test.ValidTraceID(nil)
+
+	now := uint32(time.Now().Unix())
+	original := cutTestBlockWithTraces(t, w, testTenantID, []testData{
+		{id: deletedID, t: test.MakeTrace(2, deletedID), start: now, end: now},
+		{id: keptID, t: test.MakeTrace(2, keptID), start: now, end: now},
+	})
+	checkBlocklists(t, original.BlockMeta().BlockID, 1, 0, rw)
+
+	// no tombstones
+	tombstoned, err := r.TraceTombstoned(ctx, testTenantID, deletedID)
+	require.NoError(t, err)
+	require.False(t, tombstoned)
+
+	// adding a tombstone twice adds it once
+	require.NoError(t, w.AddTraceTombstones(ctx, testTenantID, []common.ID{deletedID}))
+	require.NoError(t, w.AddTraceTombstones(ctx, testTenantID, []common.ID{deletedID}))
+
+	tombstones, err := r.TraceTombstones(ctx, testTenantID)
+	require.NoError(t, err)
+	require.Len(t, tombstones, 1)
+	require.Equal(t, util.TraceIDToHexString(deletedID), tombstones[0].TraceID)
+	require.Nil(t, tombstones[0].DeletedAt)
+
+	tombstoned, err = r.TraceTombstoned(ctx, testTenantID, deletedID)
+	require.NoError(t, err)
+	require.True(t, tombstoned)
+
+	tombstoned, err = r.TraceTombstoned(ctx, testTenantID, keptID)
+	require.NoError(t, err)
+	require.False(t, tombstoned)
+
+	count, err := r.TraceTombstonesCount(ctx, testTenantID)
+	require.NoError(t, err)
+	require.Equal(t, 1, count)
+
+	// tombstones are per tenant
+	tombstoned, err = r.TraceTombstoned(ctx, "other", deletedID)
+	require.NoError(t, err)
+	require.False(t, tombstoned)
+
+	// the block is rewritten without the tombstoned trace
+	rw.doMaintenance(ctx, rw.removeTombstonedTraces)
+
+	metas := rw.blocklist.Metas(testTenantID)
+	require.Len(t, metas, 1)
+	require.NotEqual(t, original.BlockMeta().BlockID, metas[0].BlockID)
+	require.Equal(t, 1, metas[0].TotalObjects)
+
+	block, err := encoding.OpenBlock(metas[0], rw.r)
+	require.NoError(t, err)
+
+	tr, err := block.FindTraceByID(ctx, deletedID, common.DefaultSearchOptions())
+	require.NoError(t, err)
+	require.Nil(t, tr)
+
+	tr, err = block.FindTraceByID(ctx, keptID, common.DefaultSearchOptions())
+	require.NoError(t, err)
+	require.NotNil(t, tr)
+
+	// the tombstone is not marked deleted while the ingesters may still flush the trace
+	tombstones, err = r.TraceTombstones(ctx, testTenantID)
+	require.NoError(t, err)
+	require.Nil(t, tombstones[0].DeletedAt)
+
+	// once the grace period passed and no block holds the trace the tombstone is marked deleted
+	requests, err := rw.readTraceTombstonesRequests(ctx, testTenantID)
+	require.NoError(t, err)
+	require.Len(t, requests, 2)
+	for _, req := range requests {
+		req.RequestedAt = time.Now().Add(-2 * traceTombstonesFlushGrace)
+		require.NoError(t, rw.writeTraceTombstonesRequest(ctx, testTenantID, req))
+	}
+
+	rw.doMaintenance(ctx, rw.removeTombstonedTraces)
+
+	require.Equal(t, metas[0].BlockID, rw.blocklist.Metas(testTenantID)[0].BlockID)
+	tombstones, err = r.TraceTombstones(ctx, testTenantID)
+	require.NoError(t, err)
+	require.NotNil(t, tombstones[0].DeletedAt)
+
+	// deleted traces stay tombstoned
+	tombstoned, err = r.TraceTombstoned(ctx, testTenantID, deletedID)
+	require.NoError(t, err)
+	require.True(t, tombstoned)
+}
+
+func TestTraceTombstonesAddedConcurrently(t *testing.T) {
+	tempDir := t.TempDir()
+
+	var instances []*readerWriter
+	for i := 0; i < 2; i++ {
+		r, _, _, err := New(&Config{
+			Backend: backend.Local,
+			Local: &local.Config{
+				Path: path.Join(tempDir, "traces"),
+			},
+			Block: &common.BlockConfig{
+				IndexDownsampleBytes: 17,
+				BloomFP:              0.01,
+				BloomShardSizeBytes:  100_000,
+				Version:              encoding.DefaultEncoding().Version(),
+				Encoding:             backend.EncNone,
+				IndexPageSizeBytes:   1000,
+			},
+			WAL: &wal.Config{
+				Filepath: path.Join(tempDir, fmt.Sprintf("wal-%d", i)),
+			},
+			BlocklistPoll: 0,
+		}, nil, log.NewNopLogger())
+		require.NoError(t, err)
+		instances = append(instances, r.(*readerWriter))
+	}
+
+	// no request is lost when instances add tombstones at the same time
+	var (
+		wg  sync.WaitGroup
+		ids []common.ID
+	)
+	for i := 0; i < 20; i++ {
+		ids = append(ids, test.ValidTraceID(nil))
+	}
+	for i, id := range ids {
+		wg.Add(1)
+		go func(rw *readerWriter, id common.ID) {
+			defer wg.Done()
+			require.NoError(t, rw.AddTraceTombstones(context.Background(), testTenantID, []common.ID{id}))
+		}(instances[i%2], id)
+	}
+	wg.Wait()
+
+	tombstones, err := instances[0].TraceTombstones(context.Background(), testTenantID)
+	require.NoError(t, err)
+	require.Len(t, tombstones, len(ids))
+}
+
+func TestCompactionDropsTombstonedTraces(t *testing.T) {
+	tempDir := t.TempDir()
+
+	r, w, c, err := New(&Config{
+		Backend: backend.Local,
+		Local: &local.Config{
+			Path: path.Join(tempDir, "traces"),
+		},
+		Block: &common.BlockConfig{
+			IndexDownsampleBytes: 17,
+			BloomFP:              0.01,
+			BloomShardSizeBytes:  100_000,
+			Version:              encoding.DefaultEncoding().Version(),
+			Encoding:             backend.EncNone,
+			IndexPageSizeBytes:   1000,
+		},
+		WAL: &wal.Config{
+			Filepath: path.Join(tempDir, "wal"),
+		},
+		BlocklistPoll: 0,
+	}, nil, log.NewNopLogger())
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	err = c.EnableCompaction(ctx, &CompactorConfig{
+		ChunkSizeBytes:          10,
+		MaxCompactionRange:      time.Hour,
+		BlockRetention:          24 * time.Hour,
+		CompactedBlockRetention: time.Hour,
+	}, &mockSharder{}, &mockOverrides{})
+	require.NoError(t, err)
+
+	r.EnablePolling(ctx, &mockJobSharder{})
+	rw := r.(*readerWriter)
+
+	deletedID := test.ValidTraceID(nil)
+	keptID := test.ValidTraceID(nil)
+
+	now := uint32(time.Now().Unix())
+	cutTestBlockWithTraces(t, w, testTenantID, []testData{{id: deletedID, t: test.MakeTrace(2, deletedID), start: now, end: now}})
+	cutTestBlockWithTraces(t, w, testTenantID, []testData{{id: keptID, t: test.MakeTrace(2, keptID), start: now, end: now}})
+	require.NoError(t, w.AddTraceTombstones(ctx, testTenantID, []common.ID{deletedID}))
+
+	rw.pollBlocklist()
+	require.Len(t, rw.blocklist.Metas(testTenantID), 2)
+	require.NoError(t, rw.compact(ctx, rw.blocklist.Metas(testTenantID), testTenantID))
+
+	metas := rw.blocklist.Metas(testTenantID)
+	require.Len(t, metas, 1)
+	require.Equal(t, 1, metas[0].TotalObjects)
+
+	block, err := encoding.OpenBlock(metas[0], rw.r)
+	require.NoError(t, err)
+
+	tr, err := block.FindTraceByID(ctx, deletedID, common.DefaultSearchOptions())
+	require.NoError(t, err)
+	require.Nil(t, tr)
+}